@@ -0,0 +1,467 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// validateCreditTerms проверяет, что условия кредитной карты заданы только для кредитной карты и заданы полностью.
+func validateCreditTerms(accountType string, creditLimit models.Money, statementDay, paymentDueDay int) error {
+	switch accountType {
+	case "regular", "loan", "investment", "crypto":
+		if creditLimit != 0 || statementDay != 0 || paymentDueDay != 0 {
+			return fieldError("credit_limit", "credit_limit, statement_day and payment_due_day are only allowed for credit_card accounts")
+		}
+	case "credit_card":
+		if creditLimit <= 0 || creditLimit > db.MaxAmount {
+			return fieldError("credit_limit", "credit_limit must be positive and at most %s", db.MaxAmount)
+		}
+		if statementDay < 1 || statementDay > 28 {
+			return fieldError("statement_day", "statement_day and payment_due_day must be between 1 and 28")
+		}
+		if paymentDueDay < 1 || paymentDueDay > 28 {
+			return fieldError("payment_due_day", "statement_day and payment_due_day must be between 1 and 28")
+		}
+		if statementDay == paymentDueDay {
+			return fieldError("payment_due_day", "payment_due_day must differ from statement_day")
+		}
+	default:
+		return fieldError("type", "type must be regular, credit_card, loan, investment or crypto")
+	}
+	return nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать счет
+// @Description Создает счет с начальным балансом. Валюта и тип счета не меняются после создания,
+// @Description транзакции счета ведутся в его валюте. Для кредитной карты (type=credit_card) обязательны
+// @Description кредитный лимит, день закрытия выписки и день платежа, для кредита (type=loan) — условия кредита loan.
+// @Description Начальный остаток кредита — минус сумма кредита. Инвестиционный (type=investment) и криптовалютный (type=crypto)
+// @Description счета кроме остатка денежных средств хранят позиции ценных бумаг или криптовалют
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param account body models.CreateAccount true "Данные счета"
+// @Success 201 {object} models.Account
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /accounts [post]
+func (h *Handler) CreateAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var request models.CreateAccount
+	if !bindJSON(c, &request) {
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Type == "" {
+		request.Type = "regular"
+	}
+	if err := validateCreditTerms(request.Type, request.CreditLimit, request.StatementDay, request.PaymentDueDay); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if err := validateLoanTerms(request.Type, request.Loan, time.Now()); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if request.Loan != nil {
+		request.InitialBalance = -request.Loan.Principal
+	}
+
+	account, err := h.storage.CreateAccount(userID.(int), request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+// @Security ApiKeyAuth
+// @Summary Список счетов
+// @Description Возвращает счета пользователя и открытые ему чужие счета с текущими остатками и ролью пользователя.
+// @Description Закрытые счета возвращаются только с include_closed=true
+// @Tags accounts
+// @Produce json
+// @Param include_closed query bool false "Включить закрытые счета (по умолчанию false)"
+// @Success 200 {array} models.Account
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /accounts [get]
+func (h *Handler) GetAccounts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	includeClosed, err := strconv.ParseBool(c.DefaultQuery("include_closed", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include_closed must be true or false"})
+		return
+	}
+
+	accounts, err := h.storage.GetAccounts(userID.(int), includeClosed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить счет
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Success 200 {object} models.Account
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id} [get]
+func (h *Handler) GetAccount(c *gin.Context) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// @Security ApiKeyAuth
+// @Summary Остаток счета
+// @Description Возвращает текущий остаток счета: начальный баланс плюс доходы и минус расходы по счету
+// @Description без запланированных и удаленных транзакций. Остаток счетов с длинной историей кэшируется
+// @Description и пересчитывается после изменения их транзакций
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Success 200 {object} models.AccountBalance
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/balance [get]
+func (h *Handler) GetAccountBalance(c *gin.Context) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AccountBalance{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		InitialBalance: account.InitialBalance,
+		Balance:        account.Balance,
+	})
+}
+
+// loadAccount читает счет пользователя из параметра id; при ошибке отвечает клиенту и возвращает false.
+func (h *Handler) loadAccount(c *gin.Context) (*models.Account, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return nil, false
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return nil, false
+	}
+
+	account, err := h.storage.GetAccount(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if account == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return nil, false
+	}
+	return account, true
+}
+
+// @Security ApiKeyAuth
+// @Summary Обновить счет
+// @Description Изменяет имя, начальный баланс, условия кредитной карты и кредита. Закрытый счет изменить нельзя.
+// @Description Изменить счет может только его владелец
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path int true "ID счета"
+// @Param account body models.UpdateAccount true "Данные счета"
+// @Success 200 {object} models.Account
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id} [put]
+func (h *Handler) UpdateAccount(c *gin.Context) {
+	// Условия кредитной карты проверяются по типу счета, поэтому счет читается до изменения
+	existing, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+	if existing.Role != "owner" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the account owner can change the account"})
+		return
+	}
+
+	var request models.UpdateAccount
+	if !bindJSON(c, &request) {
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	if err := validateCreditTerms(existing.Type, request.CreditLimit, request.StatementDay, request.PaymentDueDay); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if err := validateLoanTerms(existing.Type, request.Loan, existing.CreatedAt); err != nil {
+		badRequest(c, err)
+		return
+	}
+	if request.Loan != nil {
+		request.InitialBalance = -request.Loan.Principal
+	}
+
+	updated, err := h.storage.UpdateAccount(existing.ID, existing.UserID, request)
+	if err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	account, err := h.storage.GetAccount(existing.ID, existing.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if account == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить счет
+// @Description Удаляет счет, по которому нет транзакций. Удалить счет может только его владелец
+// @Tags accounts
+// @Param id path int true "ID счета"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id} [delete]
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteAccount(id, userID.(int))
+	if err != nil {
+		if strings.Contains(err.Error(), "account is used in transactions") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Security ApiKeyAuth
+// @Summary Закрыть счет
+// @Description Закрывает счет: он скрывается из списка счетов и не входит в доступные средства, но остается в отчетах.
+// @Description Транзакции закрытого счета нельзя создавать, изменять и удалять, переводы с ним недоступны
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Success 200 {object} models.Account
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/close [post]
+func (h *Handler) CloseAccount(c *gin.Context) {
+	h.setAccountClosed(c, true)
+}
+
+// @Security ApiKeyAuth
+// @Summary Открыть счет заново
+// @Description Снимает с закрытого счета ограничения и возвращает его в список счетов
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Success 200 {object} models.Account
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/reopen [post]
+func (h *Handler) ReopenAccount(c *gin.Context) {
+	h.setAccountClosed(c, false)
+}
+
+func (h *Handler) setAccountClosed(c *gin.Context, closed bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	found, err := h.storage.SetAccountClosed(id, userID.(int), closed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// @Security ApiKeyAuth
+// @Summary Доступные средства
+// @Description Возвращает остатки открытых счетов по валютам и их сумму в базовой валюте пользователя.
+// @Description Учитываются свои счета и чужие счета, открытые для редактирования. Если курсы валют недоступны, сумма не возвращается
+// @Tags accounts
+// @Produce json
+// @Success 200 {object} models.AvailableFunds
+// @Failure 401 {object} models.ErrorResponse
+// @Router /accounts/available-funds [get]
+func (h *Handler) GetAvailableFunds(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	accounts, err := h.storage.GetAccounts(user.ID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	funds := models.AvailableFunds{Balances: []models.CurrencyAmount{}, Currency: user.BaseCurrency}
+	balances := make(map[string]models.Money)
+	for _, account := range accounts {
+		if account.Role == "viewer" {
+			continue
+		}
+		if _, ok := balances[account.Currency]; !ok {
+			funds.Balances = append(funds.Balances, models.CurrencyAmount{Currency: account.Currency})
+		}
+		balances[account.Currency] += account.Balance
+	}
+	// Сумма в базовой валюте считается как доход, чтобы пересчитать ее общим с итогами транзакций способом
+	totals := make([]models.TransactionTotals, len(funds.Balances))
+	for i := range funds.Balances {
+		funds.Balances[i].Amount = balances[funds.Balances[i].Currency]
+		totals[i] = models.TransactionTotals{Currency: funds.Balances[i].Currency, Income: funds.Balances[i].Amount}
+	}
+
+	converted, err := h.convertTotals(c.Request.Context(), totals, user.BaseCurrency)
+	if err != nil {
+		log.Printf("failed to convert available funds for user %d: %v", user.ID, err)
+	} else {
+		funds.Total = &converted.Income
+	}
+
+	c.JSON(http.StatusOK, funds)
+}
+
+// @Security ApiKeyAuth
+// @Summary Скорректировать остаток счета
+// @Description Создает доход или расход на разницу между фактическим остатком balance и остатком по транзакциям.
+// @Description Корректировка не учитывается в доходах, расходах и отчетах по категориям и не изменяется — только удаляется.
+// @Description Начальный остаток счета задается полем initial_balance счета
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path int true "ID счета"
+// @Param adjustment body models.CreateAdjustment true "Фактический остаток"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/adjustments [post]
+func (h *Handler) CreateAdjustment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	var request models.CreateAdjustment
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	transaction, err := h.storage.CreateAdjustment(userID.(int), id, request.Balance, request.Date, request.Description)
+	if err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if transaction == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
@@ -0,0 +1,358 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestAccountBalance тестирует счета, их остатки и кэширование остатка.
+func TestAccountBalance(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/accounts", models.CreateAccount{Name: "Наличные", InitialBalance: models.NewMoney(1000, 0)})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var cash models.Account
+	json.NewDecoder(w.Body).Decode(&cash)
+	if cash.ID == 0 || cash.Currency != "RUB" || cash.Balance != models.NewMoney(1000, 0) {
+		t.Errorf("Unexpected account: %+v", cash)
+	}
+	w = send("POST", "/accounts", models.CreateAccount{Name: "Visa", Currency: "USD"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var card models.Account
+	json.NewDecoder(w.Body).Decode(&card)
+
+	if w := send("POST", "/accounts", models.CreateAccount{Name: " "}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Транзакция без валюты получает валюту счета, с другой валютой отклоняется
+	w = send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(300, 50), Type: "expense", CategoryID: category.ID, AccountID: cash.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var expense models.Transaction
+	json.NewDecoder(w.Body).Decode(&expense)
+	if expense.AccountID != cash.ID || expense.Currency != "RUB" {
+		t.Errorf("Unexpected transaction: %+v", expense)
+	}
+	if w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID, AccountID: card.ID, Currency: "RUB"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID, AccountID: 999999}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(5000, 0), Type: "income", CategoryID: category.ID, AccountID: cash.ID}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	// Транзакция без счета не влияет на остатки
+	if w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(700, 0), Type: "expense", CategoryID: category.ID}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	getBalance := func(id int) models.Money {
+		w := send("GET", fmt.Sprintf("/accounts/%d/balance", id), nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var balance models.AccountBalance
+		json.NewDecoder(w.Body).Decode(&balance)
+		return balance.Balance
+	}
+
+	if balance := getBalance(cash.ID); balance != models.NewMoney(5699, 50) {
+		t.Errorf("Expected balance 5699.50, got %s", balance)
+	}
+	if balance := getBalance(card.ID); balance != 0 {
+		t.Errorf("Expected balance 0, got %s", balance)
+	}
+
+	w = send("GET", "/accounts", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var accounts []models.Account
+	json.NewDecoder(w.Body).Decode(&accounts)
+	if len(accounts) != 2 || accounts[0].Balance != models.NewMoney(5699, 50) || accounts[1].Balance != 0 {
+		t.Errorf("Unexpected accounts: %+v", accounts)
+	}
+
+	// Удаление транзакции в корзину и изменение начального баланса меняют остаток
+	if w := send("DELETE", fmt.Sprintf("/transactions/%d", expense.ID), nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if w := send("PUT", fmt.Sprintf("/accounts/%d", cash.ID), models.UpdateAccount{Name: "Кошелек", InitialBalance: models.NewMoney(500, 0)}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if balance := getBalance(cash.ID); balance != models.NewMoney(5500, 0) {
+		t.Errorf("Expected balance 5500, got %s", balance)
+	}
+
+	// Вычисленный остаток сохраняется и сбрасывается при изменении транзакций счета
+	storage.BalanceCacheMinTransactions = 1
+	getBalance(cash.ID)
+	if _, err := storage.DB.Exec("UPDATE accounts SET cached_balance = 1 WHERE id = $1", cash.ID); err != nil {
+		t.Fatalf("Failed to update cached balance: %v", err)
+	}
+	if balance := getBalance(cash.ID); balance != models.NewMoney(1, 0) {
+		t.Errorf("Expected cached balance 1, got %s", balance)
+	}
+	if w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, AccountID: cash.ID}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if balance := getBalance(cash.ID); balance != models.NewMoney(5400, 0) {
+		t.Errorf("Expected balance 5400, got %s", balance)
+	}
+
+	// Счет с транзакциями, в том числе в корзине, удалить нельзя
+	if w := send("DELETE", fmt.Sprintf("/accounts/%d", cash.ID), nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("/accounts/%d", card.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("GET", fmt.Sprintf("/accounts/%d/balance", card.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// TestCloseAccount тестирует закрытие счетов: закрытый счет скрыт из списка и доступных средств и доступен только для чтения.
+func TestCloseAccount(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cash, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "RUB", InitialBalance: models.NewMoney(1000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	card, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Карта", Currency: "RUB", InitialBalance: models.NewMoney(500, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, AccountID: card.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var expense models.Transaction
+	json.NewDecoder(w.Body).Decode(&expense)
+	w = send("POST", "/transfers", models.CreateTransfer{FromAccountID: cash.ID, ToAccountID: card.ID, Amount: models.NewMoney(200, 0)})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var transfer models.Transfer
+	json.NewDecoder(w.Body).Decode(&transfer)
+
+	w = send("POST", fmt.Sprintf("/accounts/%d/close", card.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var closed models.Account
+	json.NewDecoder(w.Body).Decode(&closed)
+	if closed.ClosedAt == nil || closed.Balance != models.NewMoney(600, 0) {
+		t.Errorf("Unexpected closed account: %+v", closed)
+	}
+	if w := send("POST", "/accounts/999999/close", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	listAccounts := func(query string) []models.Account {
+		w := send("GET", "/accounts"+query, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var accounts []models.Account
+		json.NewDecoder(w.Body).Decode(&accounts)
+		return accounts
+	}
+	if accounts := listAccounts(""); len(accounts) != 1 || accounts[0].ID != cash.ID {
+		t.Errorf("Expected only the open account, got %+v", accounts)
+	}
+	if accounts := listAccounts("?include_closed=true"); len(accounts) != 2 {
+		t.Errorf("Expected 2 accounts, got %+v", accounts)
+	}
+	if w := send("GET", "/accounts?include_closed=maybe", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Закрытый счет доступен только для чтения
+	for _, tc := range []struct {
+		method, path string
+		payload      interface{}
+	}{
+		{"POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID, AccountID: card.ID}},
+		{"DELETE", fmt.Sprintf("/transactions/%d", expense.ID), nil},
+		{"DELETE", fmt.Sprintf("/transactions/%d", transfer.FromTransactionID), nil},
+		{"DELETE", fmt.Sprintf("/transfers/%d", transfer.ID), nil},
+		{"POST", "/transfers", models.CreateTransfer{FromAccountID: cash.ID, ToAccountID: card.ID, Amount: models.NewMoney(10, 0)}},
+		{"PUT", fmt.Sprintf("/accounts/%d", card.ID), models.UpdateAccount{Name: "Старая карта"}},
+	} {
+		if w := send(tc.method, tc.path, tc.payload); w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tc.method, tc.path, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	}
+
+	w = send("GET", "/accounts/available-funds", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var funds models.AvailableFunds
+	json.NewDecoder(w.Body).Decode(&funds)
+	if len(funds.Balances) != 1 || funds.Balances[0].Amount != models.NewMoney(800, 0) || funds.Total == nil || *funds.Total != models.NewMoney(800, 0) {
+		t.Errorf("Unexpected available funds: %+v", funds)
+	}
+
+	// После открытия счет снова доступен для изменений
+	if w := send("POST", fmt.Sprintf("/accounts/%d/reopen", card.ID), nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w := send("DELETE", fmt.Sprintf("/transfers/%d", transfer.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if accounts := listAccounts(""); len(accounts) != 2 || accounts[1].ClosedAt != nil || accounts[1].Balance != models.NewMoney(400, 0) {
+		t.Errorf("Unexpected accounts after reopen: %+v", accounts)
+	}
+}
+
+// TestBalanceAdjustment тестирует корректировки остатка счета.
+func TestBalanceAdjustment(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cash, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", InitialBalance: models.NewMoney(1000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	getBalance := func() models.Money {
+		w := send("GET", fmt.Sprintf("/accounts/%d/balance", cash.ID), nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var balance models.AccountBalance
+		json.NewDecoder(w.Body).Decode(&balance)
+		return balance.Balance
+	}
+
+	if w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(300, 0), Type: "expense", CategoryID: category.ID, AccountID: cash.ID}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// Фактический остаток меньше остатка по транзакциям — корректировка становится расходом на разницу
+	w := send("POST", fmt.Sprintf("/accounts/%d/adjustments", cash.ID), models.CreateAdjustment{Balance: models.NewMoney(650, 0), Description: "Сверка"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var adjustment models.Transaction
+	json.NewDecoder(w.Body).Decode(&adjustment)
+	if !adjustment.Adjustment || adjustment.Type != "expense" || adjustment.Amount != models.NewMoney(50, 0) || adjustment.AccountID != cash.ID {
+		t.Errorf("Unexpected adjustment: %+v", adjustment)
+	}
+	if balance := getBalance(); balance != models.NewMoney(650, 0) {
+		t.Errorf("Expected balance 650, got %s", balance)
+	}
+	if w := send("POST", fmt.Sprintf("/accounts/%d/adjustments", cash.ID), models.CreateAdjustment{Balance: models.NewMoney(650, 0)}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", "/accounts/999999/adjustments", models.CreateAdjustment{Balance: 0}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// Корректировка не учитывается в расходах
+	w = send("GET", "/transactions", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response models.GetTransactionsResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	if response.ConvertedTotals == nil || response.ConvertedTotals.Expense != models.NewMoney(300, 0) {
+		t.Errorf("Expected expense 300 without adjustment, got %+v", response.ConvertedTotals)
+	}
+
+	// Корректировку нельзя изменить, но можно удалить
+	if w := send("PUT", fmt.Sprintf("/transactions/%d", adjustment.ID), models.CreateTransactionRequest{Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("/transactions/%d", adjustment.ID), nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if balance := getBalance(); balance != models.NewMoney(700, 0) {
+		t.Errorf("Expected balance 700, got %s", balance)
+	}
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// AdminMiddleware пропускает только пользователей с ролью администратора.
+// Должен подключаться после AuthMiddleware.
+func (h *Handler) AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+			c.Abort()
+			return
+		}
+
+		user, err := h.storage.GetUserByID(userID.(int))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		if user == nil || user.Role != models.RoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать приглашение
+// @Description Создает код приглашения для регистрации в режиме REGISTRATION_MODE=invite. Доступно только администраторам
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param invite body models.CreateInvite false "Параметры приглашения"
+// @Success 201 {object} models.Invite
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/invites [post]
+func (h *Handler) CreateInvite(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var request models.CreateInvite
+	if !bindOptionalJSON(c, &request) {
+		return
+	}
+
+	invite, err := h.storage.CreateInvite(userID.(int), time.Duration(request.ExpiresInHours)*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// @Security ApiKeyAuth
+// @Summary Системные категории
+// @Description Возвращает общие категории, которые видят все пользователи. Доступно только администраторам
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.Category
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/categories [get]
+func (h *Handler) GetSystemCategories(c *gin.Context) {
+	categories, err := h.storage.GetSystemCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if categories == nil {
+		categories = []models.Category{}
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать системную категорию
+// @Description Создает общую категорию, доступную всем пользователям только для чтения. Доступно только администраторам
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param category body models.CreateCategory true "Данные категории"
+// @Success 201 {object} models.Category
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/categories [post]
+func (h *Handler) CreateSystemCategory(c *gin.Context) {
+	var request models.CreateCategory
+	if !bindJSON(c, &request) {
+		return
+	}
+	icon, color, err := normalizeCategoryAppearance(request.Icon, request.Color)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.storage.CreateSystemCategory(request.Name, icon, color)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// @Security ApiKeyAuth
+// @Summary Обновить системную категорию
+// @Description Заменяет имя, значок и цвет общей категории. Доступно только администраторам
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param category body models.CreateCategory true "Данные категории"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/categories/{id} [put]
+func (h *Handler) UpdateSystemCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	var request models.CreateCategory
+	if !bindJSON(c, &request) {
+		return
+	}
+	icon, color, err := normalizeCategoryAppearance(request.Icon, request.Color)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.storage.UpdateSystemCategory(id, request.Name, icon, color)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Category{ID: id, Name: request.Name, Icon: icon, Color: color, System: true})
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить системную категорию
+// @Description Удаляет общую категорию, если ее не использует ни один пользователь. Доступно только администраторам
+// @Tags admin
+// @Param id path int true "ID категории"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/categories/{id} [delete]
+func (h *Handler) DeleteSystemCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteSystemCategory(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "category is used in transactions") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
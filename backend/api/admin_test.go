@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestInviteOnlyRegistration тестирует создание приглашений администратором
+// и регистрацию по ним в режиме REGISTRATION_MODE=invite.
+func TestInviteOnlyRegistration(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	// Роутер с обработчиком в режиме регистрации по приглашениям
+	handler := NewHandler(storage, Config{JWTSecret: "secret", RegistrationMode: RegistrationInvite})
+	r := gin.New()
+	r.POST("/register", handler.Register)
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.Group("/admin", handler.AdminMiddleware()).POST("/invites", handler.CreateInvite)
+
+	// Создаем администратора и обычного пользователя
+	if _, err := storage.CreateUser("admin", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := storage.PromoteAdmins([]string{"admin"}); err != nil {
+		t.Fatalf("Failed to promote admin: %v", err)
+	}
+	if _, err := storage.CreateUser("regular", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	createInvite := func(token string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.CreateInvite{ExpiresInHours: 24})
+		req, _ := http.NewRequest("POST", "/admin/invites", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	register := func(username, code string) int {
+		body, _ := json.Marshal(models.CreateUser{Login: username, Password: "password123", InviteCode: code})
+		req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Обычный пользователь не может создавать приглашения
+	if w := createInvite(getToken(t, r, "regular", "password123")); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+
+	// Администратор создает приглашение
+	w := createInvite(getToken(t, r, "admin", "password123"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var invite models.Invite
+	if err := json.NewDecoder(w.Body).Decode(&invite); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if invite.Code == "" || invite.ExpiresAt == nil {
+		t.Errorf("Expected invite with code and expiration, got %+v", invite)
+	}
+
+	// Регистрация без приглашения запрещена
+	if code := register("newuser", ""); code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, code)
+	}
+
+	// Регистрация с неверным кодом отклоняется
+	if code := register("newuser", "wrong"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+
+	// Регистрация по приглашению успешна
+	if code := register("newuser", invite.Code); code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+
+	// Приглашение одноразовое
+	if code := register("another", invite.Code); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+}
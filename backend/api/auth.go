@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/metrics"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Summary Запросить ссылку для входа
+// @Description Отправляет на email одноразовую ссылку для входа без пароля. Ответ не раскрывает, существует ли пользователь с таким email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.MagicLinkRequest true "Email пользователя"
+// @Success 202
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Router /auth/magic-link [post]
+func (h *Handler) RequestMagicLink(c *gin.Context) {
+	var request models.MagicLinkRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	user, err := h.storage.GetUserByEmail(request.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.Status(http.StatusAccepted)
+		return
+	}
+
+	token, err := h.storage.CreateOneTimeToken(user.ID, db.TokenPurposeMagicLink, h.cfg.MagicLinkTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	link := h.cfg.BaseURL + V1Prefix + "/auth/magic-link/verify?token=" + url.QueryEscape(token)
+	lang := language(c)
+	body := i18n.T(lang, "email.magic_link.body", user.Username, link, h.cfg.MagicLinkTTL)
+	if err := h.cfg.Mailer.Send(user.Email, i18n.T(lang, "email.magic_link.subject"), body); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send email"})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// @Summary Войти по ссылке
+// @Description Обменивает одноразовый токен из письма на JWT токен
+// @Tags auth
+// @Produce json
+// @Param token query string true "Токен из ссылки"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /auth/magic-link/verify [get]
+func (h *Handler) VerifyMagicLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	userID, err := h.storage.ConsumeOneTimeToken(token, db.TokenPurposeMagicLink)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if userID == 0 {
+		metrics.Logins.WithLabelValues("failure").Inc()
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired link"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired link"})
+		return
+	}
+	// Ссылка пришла на email пользователя, значит адрес принадлежит ему
+	if !user.EmailVerified {
+		if err := h.storage.MarkEmailVerified(user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	h.respondWithToken(c, user, h.cfg.TokenTTL)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestMagicLink тестирует вход по одноразовой ссылке из письма.
+func TestMagicLink(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	// Создаем пользователя с email
+	if _, err := storage.CreateUserWithEmail("testuser", "password123", "test@example.com"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	requestLink := func(email string) int {
+		body, _ := json.Marshal(models.MagicLinkRequest{Email: email})
+		req, _ := http.NewRequest("POST", "/auth/magic-link", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Для неизвестного email ответ такой же, но письмо не отправляется
+	if code := requestLink("unknown@example.com"); code != http.StatusAccepted {
+		t.Errorf("Expected status %d, got %d", http.StatusAccepted, code)
+	}
+	if len(testMailer.sent) != 0 {
+		t.Fatalf("Expected no emails, got %d", len(testMailer.sent))
+	}
+
+	// Запрашиваем ссылку для существующего пользователя
+	if code := requestLink("test@example.com"); code != http.StatusAccepted {
+		t.Errorf("Expected status %d, got %d", http.StatusAccepted, code)
+	}
+	if len(testMailer.sent) != 1 || testMailer.sent[0].To != "test@example.com" {
+		t.Fatalf("Expected one email to test@example.com, got %+v", testMailer.sent)
+	}
+
+	match := regexp.MustCompile(`token=([0-9a-f]+)`).FindStringSubmatch(testMailer.sent[0].Body)
+	if match == nil {
+		t.Fatalf("Expected link with token in email, got %q", testMailer.sent[0].Body)
+	}
+
+	verify := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/auth/magic-link/verify?token="+token, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Обмениваем токен на JWT
+	w := verify(match[1])
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response models.LoginResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Token == "" {
+		t.Error("Expected token, got empty")
+	}
+
+	// Повторное использование ссылки запрещено
+	if w := verify(match[1]); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	// Неизвестный токен
+	if w := verify("deadbeef"); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+// defaultBalanceHistoryDays — период истории остатков без параметра from.
+const defaultBalanceHistoryDays = 30
+
+// snapshotBalances сохраняет остатки счетов на конец прошедшего дня.
+// Снимок за день сохраняется один раз, поэтому частый запуск только гарантирует, что день не будет пропущен.
+func (h *Handler) snapshotBalances(context.Context) error {
+	yesterday := time.Now().UTC().AddDate(0, 0, -1)
+	saved, err := h.storage.SnapshotBalances(yesterday)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot balances: %w", err)
+	}
+	if saved > 0 {
+		log.Printf("saved %d account balances for %s", saved, yesterday.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// parseHistoryRange читает период from и to в формате YYYY-MM-DD.
+// По умолчанию to — сегодня, from — за defaultBalanceHistoryDays дней до to.
+func parseHistoryRange(c *gin.Context) (from, to time.Time, err error) {
+	now := time.Now().UTC()
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			return from, to, fmt.Errorf("to must be in format YYYY-MM-DD")
+		}
+	}
+	from = to.AddDate(0, 0, -defaultBalanceHistoryDays)
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			return from, to, fmt.Errorf("from must be in format YYYY-MM-DD")
+		}
+	}
+	if from.After(to) {
+		return from, to, fmt.Errorf("from must not be after to")
+	}
+	return from, to, nil
+}
+
+// @Security ApiKeyAuth
+// @Summary История остатка счета
+// @Description Возвращает остатки счета на конец каждого дня периода из ежедневных снимков.
+// @Description Снимок за день сохраняется после его окончания; дни без снимка пропускаются
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней до to)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Success 200 {array} models.BalancePoint
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/balance-history [get]
+func (h *Handler) GetAccountBalanceHistory(c *gin.Context) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+	from, to, err := parseHistoryRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	points, err := h.storage.GetAccountBalanceHistory(account.ID, c.GetInt("user_id"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
+
+// @Security ApiKeyAuth
+// @Summary История суммарного остатка
+// @Description Возвращает суммы остатков всех счетов по валютам на конец каждого дня периода из ежедневных снимков
+// @Tags accounts
+// @Produce json
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней до to)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Success 200 {array} models.BalancePoint
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /accounts/balance-history [get]
+func (h *Handler) GetBalanceHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+	from, to, err := parseHistoryRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	points, err := h.storage.GetBalanceHistory(userID.(int), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
+
+// @Security ApiKeyAuth
+// @Summary История чистых активов
+// @Description Возвращает чистые активы — положительные остатки своих счетов минус отрицательные — на последний день
+// @Description каждого дня, недели (с понедельника), месяца, квартала или года периода, за который сохранен снимок остатков. Интервалы
+// @Description без снимков пропускаются. Остатки пересчитываются в базовую валюту пользователя по текущим курсам;
+// @Description стоимость позиций инвестиционных счетов в снимки не входит
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param group_by query string false "Интервал: day, week, month (по умолчанию), quarter или year; месяцы начинаются с дня начала месяца из профиля"
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет до to)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.NetWorthHistory
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/net-worth/history [get]
+func (h *Handler) GetNetWorthHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	g, from, to, err := parseTimeSeriesRange(c, user.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	snapshots, err := h.storage.GetNetWorthHistory(user.ID, g, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Курсы загружаются один раз на всю историю
+	groups := make([][]models.TransactionTotals, len(snapshots))
+	for i := range snapshots {
+		groups[i] = snapshots[i].Balances
+	}
+	exchangeRates, err := h.exchangeRatesFor(c.Request.Context(), user.BaseCurrency, groups...)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert balances: " + err.Error()})
+		return
+	}
+	points := make([]models.NetWorthHistoryPoint, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		converted, err := sumConverted(exchangeRates, snapshot.Balances, user.BaseCurrency)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert balances: " + err.Error()})
+			return
+		}
+		points = append(points, models.NetWorthHistoryPoint{
+			Start:       snapshot.Start.Format("2006-01-02"),
+			Date:        snapshot.Date.Format("2006-01-02"),
+			Assets:      converted.Income,
+			Liabilities: converted.Expense,
+			NetWorth:    converted.Income - converted.Expense,
+		})
+	}
+
+	history := models.NetWorthHistory{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		GroupBy:  g.Kind,
+		Currency: user.BaseCurrency,
+		Points:   points,
+	}
+	writeReport(c, fmt.Sprintf("net-worth-history-%s-%s-%s", g.Kind, history.From, history.To), history,
+		func() report.Workbook { return netWorthHistoryWorkbook(history) })
+}
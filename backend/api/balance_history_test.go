@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestBalanceHistory тестирует ежедневные снимки остатков и историю остатков.
+func TestBalanceHistory(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cash, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "RUB", InitialBalance: models.NewMoney(1000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	card, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Карта", Currency: "RUB", InitialBalance: models.NewMoney(500, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	if _, err := storage.DB.Exec("UPDATE accounts SET created_at = '2025-03-01' WHERE user_id = $1", user.ID); err != nil {
+		t.Fatalf("Failed to update accounts: %v", err)
+	}
+	for _, tx := range []models.Transaction{
+		{Amount: models.NewMoney(100, 0), Type: "expense", Date: time.Date(2025, 3, 2, 15, 0, 0, 0, time.UTC), AccountID: cash.ID},
+		{Amount: models.NewMoney(300, 0), Type: "income", Date: time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC), AccountID: cash.ID},
+	} {
+		tx.UserID, tx.CategoryID = user.ID, category.ID
+		if err := storage.CreateTransaction(&tx); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	for day := 1; day <= 3; day++ {
+		if _, err := storage.SnapshotBalances(time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("Failed to snapshot balances: %v", err)
+		}
+	}
+	// Повторный снимок за день не меняет сохраненный остаток
+	if _, err := storage.DB.Exec("UPDATE accounts SET initial_balance = 0 WHERE id = $1", card.ID); err != nil {
+		t.Fatalf("Failed to update account: %v", err)
+	}
+	saved, err := storage.SnapshotBalances(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Failed to snapshot balances: %v", err)
+	}
+	if saved != 0 {
+		t.Errorf("Expected no new snapshots, got %d", saved)
+	}
+
+	token := getToken(t, r, "testuser", "password123")
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get(fmt.Sprintf("/accounts/%d/balance-history?from=2025-03-02&to=2025-03-31", cash.ID))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var points []models.BalancePoint
+	json.NewDecoder(w.Body).Decode(&points)
+	if len(points) != 2 || points[0].Balance != models.NewMoney(900, 0) || points[1].Balance != models.NewMoney(1200, 0) ||
+		!points[1].Date.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)) || points[1].Currency != "RUB" {
+		t.Errorf("Unexpected account history: %+v", points)
+	}
+
+	w = get("/accounts/balance-history?from=2025-03-01&to=2025-03-03")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&points)
+	if len(points) != 3 || points[0].Balance != models.NewMoney(1500, 0) || points[2].Balance != models.NewMoney(1700, 0) {
+		t.Errorf("Unexpected total history: %+v", points)
+	}
+
+	for _, path := range []string{
+		fmt.Sprintf("/accounts/%d/balance-history?from=2025-03-05&to=2025-03-01", cash.ID),
+		fmt.Sprintf("/accounts/%d/balance-history?from=03.03.2025", cash.ID),
+	} {
+		if w := get(path); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusBadRequest, w.Code)
+		}
+	}
+	if w := get("/accounts/999999/balance-history"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// TestGetNetWorthHistory тестирует историю чистых активов по снимкам остатков в разных валютах.
+func TestGetNetWorthHistory(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Rates: &fakeRates{}})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.GET("/reports/net-worth/history", handler.GetNetWorthHistory)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cash, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "RUB", InitialBalance: models.NewMoney(1000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	if _, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Доллары", Currency: "USD", InitialBalance: models.NewMoney(100, 0)}); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	if _, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Кредитка", Currency: "RUB", InitialBalance: models.NewMoney(-3000, 0)}); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	if _, err := storage.DB.Exec("UPDATE accounts SET created_at = '2025-03-01' WHERE user_id = $1", user.ID); err != nil {
+		t.Fatalf("Failed to update accounts: %v", err)
+	}
+	if err := storage.CreateTransaction(&models.Transaction{UserID: user.ID, CategoryID: category.ID, AccountID: cash.ID, Type: "expense",
+		Amount: models.NewMoney(500, 0), Date: time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	for _, date := range []time.Time{
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC),
+	} {
+		if _, err := storage.SnapshotBalances(date); err != nil {
+			t.Fatalf("Failed to snapshot balances: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/net-worth/history"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?from=2025-03-01&to=2025-05-31")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var history models.NetWorthHistory
+	json.NewDecoder(w.Body).Decode(&history)
+	// Май без снимков пропускается, месяц берется по последнему снимку
+	if history.Currency != "RUB" || history.GroupBy != "month" || len(history.Points) != 2 {
+		t.Fatalf("Unexpected history: %+v", history)
+	}
+	if p := history.Points[0]; p.Start != "2025-03-01" || p.Date != "2025-03-31" || p.Assets != models.NewMoney(8500, 0) ||
+		p.Liabilities != models.NewMoney(3000, 0) || p.NetWorth != models.NewMoney(5500, 0) {
+		t.Errorf("Unexpected March point: %+v", p)
+	}
+	if p := history.Points[1]; p.Start != "2025-04-01" || p.Date != "2025-04-10" {
+		t.Errorf("Unexpected April point: %+v", p)
+	}
+
+	w = get("?group_by=day&from=2025-03-01&to=2025-03-31")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	history = models.NetWorthHistory{}
+	json.NewDecoder(w.Body).Decode(&history)
+	if len(history.Points) != 3 || history.Points[0].NetWorth != models.NewMoney(6000, 0) || history.Points[2].NetWorth != models.NewMoney(5500, 0) {
+		t.Errorf("Unexpected daily history: %+v", history)
+	}
+
+	for _, query := range []string{"?group_by=hour", "?from=2025-04-01&to=2025-03-01", "?group_by=day&from=2020-01-01&to=2025-03-01", "?format=csv"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/events"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// sendBudgetAlerts отправляет пользователям письма о бюджетах текущего периода, расходы по которым
+// превысили доступную сумму. По каждому бюджету письмо отправляется один раз, пока не изменится его лимит.
+// Возвращает ошибки всех неотправленных писем.
+func (h *Handler) sendBudgetAlerts(context.Context) error {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	users, err := h.storage.GetBudgetAlertUsers(today)
+	if err != nil {
+		return fmt.Errorf("failed to get budget alert users: %w", err)
+	}
+
+	var errs []error
+	sent := 0
+	for _, user := range users {
+		ok, err := h.sendBudgetAlert(user, today)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send budget alert to user %d: %w", user.ID, err))
+		} else if ok {
+			sent++
+		}
+	}
+	if sent > 0 {
+		log.Printf("sent %d budget alerts", sent)
+	}
+	return errors.Join(errs...)
+}
+
+// sendBudgetAlert сообщает подключенным клиентам пользователя и отправляет письмо о превышенных
+// бюджетах на день date, о которых он еще не уведомлен. Письмо не отправляется, если у пользователя нет email.
+// Возвращает false, если уведомлять не о чем.
+func (h *Handler) sendBudgetAlert(user models.User, date time.Time) (bool, error) {
+	if err := h.storage.UpdateBudgetCarryover(user.ID, date); err != nil {
+		return false, err
+	}
+	budgets, err := h.storage.GetBudgetProgress(user.ID, date, date)
+	if err != nil {
+		return false, err
+	}
+	var exceeded []int
+	for _, b := range budgets {
+		if b.Exceeded {
+			exceeded = append(exceeded, b.ID)
+		}
+	}
+	if len(exceeded) == 0 {
+		return false, nil
+	}
+
+	claimed, err := h.storage.ClaimBudgetAlerts(user.ID, exceeded)
+	if err != nil || len(claimed) == 0 {
+		return false, err
+	}
+	alerts := make([]models.BudgetProgress, 0, len(claimed))
+	for _, b := range budgets {
+		for _, id := range claimed {
+			if b.ID == id {
+				alerts = append(alerts, b)
+				h.cfg.Events.Publish(user.ID, events.BudgetThreshold, b)
+			}
+		}
+	}
+	if user.Email == "" {
+		return true, nil
+	}
+
+	subject, body := renderBudgetAlert(user.Language, user, alerts)
+	return true, h.cfg.Mailer.Send(user.Email, subject, body)
+}
+
+// renderBudgetAlert формирует тему и текст письма о превышенных бюджетах на языке lang.
+func renderBudgetAlert(lang string, user models.User, budgets []models.BudgetProgress) (string, string) {
+	subject := i18n.T(lang, "email.budget_alert.subject")
+	if len(budgets) > 1 {
+		subject = i18n.T(lang, "email.budget_alert.subject_many", len(budgets))
+	}
+
+	var body strings.Builder
+	body.WriteString(i18n.T(lang, "email.budget_alert.intro", user.Username))
+	for _, b := range budgets {
+		label := b.CategoryName
+		if b.TagID != 0 {
+			label = "#" + b.TagName
+		}
+		body.WriteString(i18n.T(lang, "email.budget_alert.line", label,
+			b.StartDate.Format("2006-01-02"), b.EndDate.Format("2006-01-02"), b.Spent, b.Available, b.Currency, -b.Remaining))
+	}
+	body.WriteString(i18n.T(lang, "email.budget_alert.footer"))
+	return subject, body.String()
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestRenderBudgetAlert тестирует текст письма о превышенных бюджетах.
+func TestRenderBudgetAlert(t *testing.T) {
+	budgets := []models.BudgetProgress{
+		{CategoryName: "Продукты", StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+			Currency: "RUB", Available: models.NewMoney(20000, 0), Spent: models.NewMoney(21000, 0), Remaining: models.NewMoney(-1000, 0), Exceeded: true},
+	}
+	subject, body := renderBudgetAlert(i18n.English, models.User{Username: "john"}, budgets)
+	if subject != "Budget exceeded" {
+		t.Errorf("Unexpected subject: %q", subject)
+	}
+	if !strings.Contains(body, "- Продукты (2025-07-01 - 2025-07-31): spent 21000.00 of 20000.00 RUB, exceeded by 1000.00") {
+		t.Errorf("Unexpected body:\n%s", body)
+	}
+
+	budgets = append(budgets, models.BudgetProgress{TagID: 7, TagName: "отпуск", Exceeded: true})
+	if subject, body := renderBudgetAlert(i18n.English, models.User{Username: "john"}, budgets); subject != "2 budgets exceeded" || !strings.Contains(body, "#отпуск") {
+		t.Errorf("Unexpected alert for two budgets: %q\n%s", subject, body)
+	}
+
+	subject, body = renderBudgetAlert(i18n.Russian, models.User{Username: "john"}, budgets)
+	if subject != "Превышено бюджетов: 2" || !strings.Contains(body, "- Продукты (2025-07-01 - 2025-07-31): потрачено 21000.00 из 20000.00 RUB, превышение 1000.00") {
+		t.Errorf("Unexpected russian alert: %q\n%s", subject, body)
+	}
+}
+
+// TestBudgetAlerts тестирует однократное уведомление о превышении бюджета и повторное после изменения лимита.
+func TestBudgetAlerts(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+	mailer := &fakeMailer{}
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Mailer: mailer})
+
+	user, err := storage.CreateUserWithEmail("testuser", "password123", "test@example.com")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	period, err := budget.NewPeriod(budget.Month, today, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to create period: %v", err)
+	}
+	created, err := storage.CreateBudget(user.ID, period, models.CreateBudget{CategoryID: category.ID, Limit: models.NewMoney(1000, 0), Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("Failed to create budget: %v", err)
+	}
+
+	// Пока бюджет не превышен, писем нет
+	if err := handler.sendBudgetAlerts(context.Background()); err != nil || len(mailer.sent) != 0 {
+		t.Fatalf("Expected no alerts, got %d (%v)", len(mailer.sent), err)
+	}
+
+	if err := storage.CreateTransaction(&models.Transaction{UserID: user.ID, CategoryID: category.ID, Type: "expense", Currency: "RUB",
+		Amount: models.NewMoney(1500, 0), Date: today}); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	if err := handler.sendBudgetAlerts(context.Background()); err != nil || len(mailer.sent) != 1 {
+		t.Fatalf("Expected one alert, got %d (%v)", len(mailer.sent), err)
+	}
+	if mailer.sent[0].To != "test@example.com" || !strings.Contains(mailer.sent[0].Body, "exceeded by 500.00") {
+		t.Errorf("Unexpected alert: %+v", mailer.sent[0])
+	}
+
+	// Повторный запуск не уведомляет снова
+	if err := handler.sendBudgetAlerts(context.Background()); err != nil || len(mailer.sent) != 1 {
+		t.Errorf("Expected no repeated alert, got %d (%v)", len(mailer.sent), err)
+	}
+
+	// После изменения лимита уведомление отправляется заново
+	if _, err := storage.UpdateBudget(created.ID, user.ID, models.UpdateBudget{Limit: models.NewMoney(1200, 0)}); err != nil {
+		t.Fatalf("Failed to update budget: %v", err)
+	}
+	if err := handler.sendBudgetAlerts(context.Background()); err != nil || len(mailer.sent) != 2 {
+		t.Errorf("Expected alert after limit change, got %d (%v)", len(mailer.sent), err)
+	}
+}
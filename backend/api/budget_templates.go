@@ -0,0 +1,286 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// applyBudgetTemplates применяет шаблоны бюджетов с автоприменением к наступившему месяцу.
+func (h *Handler) applyBudgetTemplates(context.Context) error {
+	month, _ := parseBudgetMonth("")
+	created, err := h.storage.ApplyAutoBudgetTemplates(month)
+	if err != nil {
+		return fmt.Errorf("failed to apply budget templates: %w", err)
+	}
+	if created > 0 {
+		log.Printf("created %d budgets for %s from templates", created, month.Start.Format("2006-01"))
+	}
+	return nil
+}
+
+// validateBudgetTemplate проверяет, что категория встречается в шаблоне один раз;
+// остальные поля проверяются тегами binding.
+func validateBudgetTemplate(request *models.CreateBudgetTemplate) error {
+	request.Name = strings.TrimSpace(request.Name)
+	categories := make(map[int]bool, len(request.Items))
+	for i, item := range request.Items {
+		if categories[item.CategoryID] {
+			return fieldError(fmt.Sprintf("items[%d].category_id", i), "category %d appears more than once", item.CategoryID)
+		}
+		categories[item.CategoryID] = true
+	}
+	return nil
+}
+
+func budgetTemplateErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "category") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать шаблон бюджетов
+// @Description Создает шаблон с лимитами категорий. Шаблон с автоприменением в начале каждого месяца создает бюджеты месяца,
+// @Description начиная со следующего; к текущему месяцу его можно применить вручную
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param template body models.CreateBudgetTemplate true "Данные шаблона"
+// @Success 201 {object} models.BudgetTemplate
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /budget-templates [post]
+func (h *Handler) CreateBudgetTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var request models.CreateBudgetTemplate
+	if !bindJSON(c, &request) {
+		return
+	}
+	if err := validateBudgetTemplate(&request); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	template, err := h.storage.CreateBudgetTemplate(userID.(int), request)
+	if err != nil {
+		c.JSON(budgetTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// @Security ApiKeyAuth
+// @Summary Шаблоны бюджетов
+// @Tags budgets
+// @Produce json
+// @Success 200 {array} models.BudgetTemplate
+// @Failure 401 {object} models.ErrorResponse
+// @Router /budget-templates [get]
+func (h *Handler) GetBudgetTemplates(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	templates, err := h.storage.GetBudgetTemplates(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить шаблон бюджетов
+// @Tags budgets
+// @Produce json
+// @Param id path int true "ID шаблона"
+// @Success 200 {object} models.BudgetTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /budget-templates/{id} [get]
+func (h *Handler) GetBudgetTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	template, err := h.storage.GetBudgetTemplate(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Security ApiKeyAuth
+// @Summary Обновить шаблон бюджетов
+// @Description Заменяет название, автоприменение и лимиты шаблона. Созданные по шаблону бюджеты не меняются
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path int true "ID шаблона"
+// @Param template body models.CreateBudgetTemplate true "Данные шаблона"
+// @Success 200 {object} models.BudgetTemplate
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /budget-templates/{id} [put]
+func (h *Handler) UpdateBudgetTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	var request models.CreateBudgetTemplate
+	if !bindJSON(c, &request) {
+		return
+	}
+	if err := validateBudgetTemplate(&request); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	updated, err := h.storage.UpdateBudgetTemplate(id, userID.(int), request)
+	if err != nil {
+		c.JSON(budgetTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+
+	template, err := h.storage.GetBudgetTemplate(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить шаблон бюджетов
+// @Description Удаляет шаблон; созданные по нему бюджеты остаются
+// @Tags budgets
+// @Param id path int true "ID шаблона"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /budget-templates/{id} [delete]
+func (h *Handler) DeleteBudgetTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteBudgetTemplate(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Security ApiKeyAuth
+// @Summary Применить шаблон бюджетов
+// @Description Создает по шаблону месячные бюджеты месяца. Категории, у которых в месяце уже есть месячный бюджет, пропускаются
+// @Tags budgets
+// @Produce json
+// @Param id path int true "ID шаблона"
+// @Param month query string false "Месяц в формате YYYY-MM (по умолчанию текущий)"
+// @Success 200 {object} models.ApplyBudgetTemplateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /budget-templates/{id}/apply [post]
+func (h *Handler) ApplyBudgetTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+	month, err := parseBudgetMonth(c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.storage.GetBudgetTemplate(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if template == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+		return
+	}
+
+	created, err := h.storage.ApplyBudgetTemplate(id, userID.(int), month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApplyBudgetTemplateResponse{Month: month.Start.Format("2006-01"), Created: created})
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestValidateBudgetTemplate тестирует проверку повторяющихся категорий шаблона бюджетов.
+func TestValidateBudgetTemplate(t *testing.T) {
+	valid := models.CreateBudgetTemplate{Name: " Обычный месяц ", Items: []models.BudgetTemplateItem{
+		{CategoryID: 1, Limit: models.NewMoney(1000, 0), Currency: "USD"},
+		{CategoryID: 2, Limit: models.NewMoney(500, 0)},
+	}}
+	if err := validateBudgetTemplate(&valid); err != nil || valid.Name != "Обычный месяц" {
+		t.Errorf("Unexpected validation result: %v, %+v", err, valid)
+	}
+
+	repeated := models.CreateBudgetTemplate{Name: "Повтор", Items: []models.BudgetTemplateItem{{CategoryID: 1, Limit: 100}, {CategoryID: 1, Limit: 200}}}
+	var fields validationError
+	if err := validateBudgetTemplate(&repeated); !errors.As(err, &fields) || fields[0].Field != "items[1].category_id" {
+		t.Errorf("Expected items[1].category_id error, got %v", err)
+	}
+}
+
+// TestBudgetTemplates тестирует шаблоны бюджетов и их применение вручную и автоматически.
+func TestBudgetTemplates(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cafe, err := storage.CreateCategory(user.ID, "Кафе")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	request := models.CreateBudgetTemplate{Name: "Обычный месяц", AutoApply: true, Items: []models.BudgetTemplateItem{
+		{CategoryID: food.ID, Limit: models.NewMoney(20000, 0), Currency: "RUB", Rollover: true},
+		{CategoryID: 999999, Limit: models.NewMoney(5000, 0)},
+	}}
+	if w := send("POST", "/budget-templates", request); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for unknown category, got %d", http.StatusBadRequest, w.Code)
+	}
+	request.Items[1].CategoryID = cafe.ID
+	w := send("POST", "/budget-templates", request)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var template models.BudgetTemplate
+	json.NewDecoder(w.Body).Decode(&template)
+	if template.ID == 0 || len(template.Items) != 2 || template.Items[0].CategoryID != cafe.ID || template.Items[0].Currency != "RUB" {
+		t.Errorf("Unexpected template: %+v", template)
+	}
+	applyPath := fmt.Sprintf("/budget-templates/%d/apply", template.ID)
+
+	var applied models.ApplyBudgetTemplateResponse
+	w = send("POST", applyPath+"?month=2025-07", nil)
+	json.NewDecoder(w.Body).Decode(&applied)
+	if w.Code != http.StatusOK || applied.Month != "2025-07" || applied.Created != 2 {
+		t.Errorf("Unexpected apply result: %d %+v", w.Code, applied)
+	}
+	w = send("POST", applyPath+"?month=2025-07", nil)
+	json.NewDecoder(w.Body).Decode(&applied)
+	if applied.Created != 0 {
+		t.Errorf("Expected no budgets on repeated apply, got %+v", applied)
+	}
+	if w := send("POST", applyPath+"?month=2025", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid month, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Автоприменение начинается со следующего месяца и срабатывает один раз
+	current, _ := parseBudgetMonth("")
+	next, _ := budget.NewPeriod(budget.Month, current.End.AddDate(0, 0, 1), time.Time{})
+	if created, err := storage.ApplyAutoBudgetTemplates(current); err != nil || created != 0 {
+		t.Errorf("Expected no budgets for current month, got %d, %v", created, err)
+	}
+	if created, err := storage.ApplyAutoBudgetTemplates(next); err != nil || created != 2 {
+		t.Errorf("Expected 2 budgets for next month, got %d, %v", created, err)
+	}
+	budgets, err := storage.GetBudgetProgress(user.ID, next.Start, next.End)
+	if err != nil || len(budgets) != 2 || !budgets[1].Rollover || budgets[1].Limit != models.NewMoney(20000, 0) {
+		t.Fatalf("Unexpected budgets from template: %+v, %v", budgets, err)
+	}
+	if _, err := storage.DeleteBudget(budgets[0].ID, user.ID); err != nil {
+		t.Fatalf("Failed to delete budget: %v", err)
+	}
+	if created, err := storage.ApplyAutoBudgetTemplates(next); err != nil || created != 0 {
+		t.Errorf("Expected deleted budget not to be recreated, got %d, %v", created, err)
+	}
+
+	request.Items = request.Items[:1]
+	request.AutoApply = false
+	w = send("PUT", fmt.Sprintf("/budget-templates/%d", template.ID), request)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&template)
+	if template.AutoApply || len(template.Items) != 1 {
+		t.Errorf("Unexpected updated template: %+v", template)
+	}
+
+	if w := send("DELETE", fmt.Sprintf("/budget-templates/%d", template.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("POST", applyPath, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after delete, got %d", http.StatusNotFound, w.Code)
+	}
+}
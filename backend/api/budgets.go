@@ -0,0 +1,322 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// parseBudgetMonth разбирает месяц в формате YYYY-MM; пустой месяц заменяется текущим.
+func parseBudgetMonth(month string) (budget.Period, error) {
+	start := time.Now().UTC()
+	if month != "" {
+		var err error
+		if start, err = time.Parse("2006-01", month); err != nil {
+			return budget.Period{}, fmt.Errorf("month must be in format YYYY-MM")
+		}
+	}
+	return budget.NewPeriod(budget.Month, start, time.Time{})
+}
+
+// parseBudgetPeriod возвращает период нового бюджета: месяц из month или период вида period,
+// заданный днем start_date, а для произвольного периода — еще и последним днем end_date.
+// Ошибки возвращаются ошибками полей запроса.
+func parseBudgetPeriod(request models.CreateBudget) (budget.Period, error) {
+	if request.Month != "" {
+		if request.Period != "" && request.Period != budget.Month {
+			return budget.Period{}, fieldError("month", "month is only allowed for monthly budgets")
+		}
+		if request.StartDate != nil {
+			return budget.Period{}, fieldError("month", "month and start_date cannot be used together")
+		}
+		period, err := parseBudgetMonth(request.Month)
+		if err != nil {
+			return budget.Period{}, fieldError("month", "%v", err)
+		}
+		return period, nil
+	}
+	if request.StartDate == nil {
+		return budget.Period{}, fieldError("start_date", "month or start_date is required")
+	}
+	var end time.Time
+	if request.EndDate != nil {
+		end = *request.EndDate
+	}
+	period, err := budget.NewPeriod(request.Period, *request.StartDate, end)
+	if err != nil {
+		return budget.Period{}, fieldError("period", "%v", err)
+	}
+	return period, nil
+}
+
+// withCarryover пересчитывает перенос переносимого бюджета и возвращает его с актуальным переносом.
+func (h *Handler) withCarryover(b *models.Budget) (*models.Budget, error) {
+	if b == nil || !b.Rollover {
+		return b, nil
+	}
+	if err := h.storage.UpdateBudgetCarryover(b.UserID, b.StartDate); err != nil {
+		return nil, err
+	}
+	return h.storage.GetBudget(b.ID, b.UserID)
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать бюджет
+// @Description Задает лимит расходов категории или тега на неделю (с понедельника), месяц, квартал или произвольный период.
+// @Description Месячный бюджет задается месяцем month или днем start_date, недельный и квартальный — любым днем периода,
+// @Description произвольный — первым и последним днем. В бюджет тега входят расходы с тегом во всех категориях.
+// @Description Без валюты лимит задается в базовой валюте пользователя.
+// @Description У переносимого бюджета (rollover) остаток бюджета той же категории или того же тега, валюты и вида периода, закончившегося
+// @Description накануне, прибавляется к лимиту, а перерасход вычитается из него
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param budget body models.CreateBudget true "Данные бюджета"
+// @Success 201 {object} models.Budget
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /budgets [post]
+func (h *Handler) CreateBudget(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var request models.CreateBudget
+	if !bindJSON(c, &request) {
+		return
+	}
+	if (request.CategoryID == 0) == (request.TagID == 0) {
+		badRequest(c, fieldError("category_id", "exactly one of category_id and tag_id is required"))
+		return
+	}
+	period, err := parseBudgetPeriod(request)
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	budget, err := h.storage.CreateBudget(userID.(int), period, request)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if budget == nil {
+		if request.TagID != 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tag does not exist"})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category does not exist"})
+		}
+		return
+	}
+	if budget, err = h.withCarryover(budget); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+// @Security ApiKeyAuth
+// @Summary Бюджеты с расходами
+// @Description Возвращает бюджеты, периоды которых пересекаются с месяцем month или включают день date, с фактическими расходами
+// @Description по категориям или тегам в валюте бюджета за вычетом возвратов. Без параметров возвращаются бюджеты, действующие сегодня.
+// @Description Запланированные транзакции, переводы и корректировки не учитываются. Перенос переносимых бюджетов пересчитывается
+// @Description перед ответом, доступная сумма (available) — лимит с учетом переноса
+// @Tags budgets
+// @Produce json
+// @Param month query string false "Месяц в формате YYYY-MM"
+// @Param date query string false "День в формате YYYY-MM-DD"
+// @Success 200 {array} models.BudgetProgress
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /budgets [get]
+func (h *Handler) GetBudgets(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := from
+	switch month, date := c.Query("month"), c.Query("date"); {
+	case month != "" && date != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month and date cannot be used together"})
+		return
+	case month != "":
+		period, err := parseBudgetMonth(month)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		from, to = period.Start, period.End
+	case date != "":
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in format YYYY-MM-DD"})
+			return
+		}
+		from, to = day, day
+	}
+
+	if err := h.storage.UpdateBudgetCarryover(userID.(int), to); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	budgets, err := h.storage.GetBudgetProgress(userID.(int), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range budgets {
+		period := budget.Period{Kind: budgets[i].Period, Start: budgets[i].StartDate, End: budgets[i].EndDate}
+		budgets[i].Elapsed = period.Elapsed(now)
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить бюджет
+// @Tags budgets
+// @Produce json
+// @Param id path int true "ID бюджета"
+// @Success 200 {object} models.Budget
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /budgets/{id} [get]
+func (h *Handler) GetBudget(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget id"})
+		return
+	}
+
+	budget, err := h.storage.GetBudget(id, userID.(int))
+	if err == nil {
+		budget, err = h.withCarryover(budget)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if budget == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// @Security ApiKeyAuth
+// @Summary Обновить бюджет
+// @Description Изменяет лимит бюджета и перенос остатка. Категория или тег, период и валюта бюджета не меняются
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Param id path int true "ID бюджета"
+// @Param budget body models.UpdateBudget true "Данные бюджета"
+// @Success 200 {object} models.Budget
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /budgets/{id} [put]
+func (h *Handler) UpdateBudget(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget id"})
+		return
+	}
+
+	var request models.UpdateBudget
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	updated, err := h.storage.UpdateBudget(id, userID.(int), request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+
+	budget, err := h.storage.GetBudget(id, userID.(int))
+	if err == nil {
+		budget, err = h.withCarryover(budget)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if budget == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить бюджет
+// @Tags budgets
+// @Param id path int true "ID бюджета"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /budgets/{id} [delete]
+func (h *Handler) DeleteBudget(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteBudget(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestParseBudgetPeriod тестирует разбор месяца и периода бюджета.
+func TestParseBudgetPeriod(t *testing.T) {
+	if month, err := parseBudgetMonth("2025-07"); err != nil || month.Start != time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) ||
+		month.End != time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Unexpected month: %+v, %v", month, err)
+	}
+	if month, err := parseBudgetMonth(""); err != nil || month.Start.Format("2006-01") != time.Now().UTC().Format("2006-01") {
+		t.Errorf("Expected current month, got %+v, %v", month, err)
+	}
+	for _, invalid := range []string{"2025-13", "2025-7", "07-2025", "2025-07-01"} {
+		if _, err := parseBudgetMonth(invalid); err == nil {
+			t.Errorf("Expected error for month %q", invalid)
+		}
+	}
+
+	day := time.Date(2025, 7, 17, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC)
+	if week, err := parseBudgetPeriod(models.CreateBudget{Period: "week", StartDate: &day}); err != nil || week.Start.Day() != 14 {
+		t.Errorf("Unexpected week: %+v, %v", week, err)
+	}
+	if custom, err := parseBudgetPeriod(models.CreateBudget{Period: "custom", StartDate: &day, EndDate: &end}); err != nil || custom.Days() != 18 {
+		t.Errorf("Unexpected custom period: %+v, %v", custom, err)
+	}
+	for _, invalid := range []models.CreateBudget{
+		{},
+		{Period: "week", Month: "2025-07"},
+		{Month: "2025-07", StartDate: &day},
+		{Period: "custom", StartDate: &day},
+		{Period: "year", StartDate: &day},
+	} {
+		if _, err := parseBudgetPeriod(invalid); err == nil {
+			t.Errorf("Expected error for %+v", invalid)
+		}
+	}
+}
+
+// TestBudgets тестирует бюджеты категорий и подсчет расходов по ним.
+func TestBudgets(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cafe, err := storage.CreateCategory(user.ID, "Кафе")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	other, err := storage.CreateUser("otheruser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	foreign, err := storage.CreateCategory(other.ID, "Чужая")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(300, 0), Type: "expense", CategoryID: food.ID, Date: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(200, 50), Type: "expense", CategoryID: food.ID, Date: time.Date(2025, 7, 31, 23, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(1000, 0), Type: "expense", CategoryID: food.ID, Date: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(50, 0), Type: "expense", CategoryID: food.ID, Currency: "USD", Date: time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(700, 0), Type: "expense", CategoryID: cafe.ID, Date: time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC)},
+	} {
+		transaction.UserID = user.ID
+		if transaction.Currency == "" {
+			transaction.Currency = "RUB"
+		}
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	for _, invalid := range []models.CreateBudget{
+		{Month: "2025-07", Limit: models.NewMoney(1000, 0)},
+		{CategoryID: food.ID, Limit: models.NewMoney(1000, 0)},
+		{CategoryID: food.ID, Month: "2025-7", Limit: models.NewMoney(1000, 0)},
+		{CategoryID: food.ID, Month: "2025-07"},
+		{CategoryID: food.ID, Month: "2025-07", Limit: models.NewMoney(1000, 0), Currency: "XX"},
+		{CategoryID: foreign.ID, Month: "2025-07", Limit: models.NewMoney(1000, 0)},
+	} {
+		if w := send("POST", "/budgets", invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d: %s", http.StatusBadRequest, invalid, w.Code, w.Body.String())
+		}
+	}
+
+	w := send("POST", "/budgets", models.CreateBudget{CategoryID: food.ID, Month: "2025-07", Limit: models.NewMoney(1000, 0), Currency: "rub"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var budget models.Budget
+	json.NewDecoder(w.Body).Decode(&budget)
+	if budget.ID == 0 || budget.CategoryID != food.ID || budget.Month != "2025-07" || budget.Limit != models.NewMoney(1000, 0) || budget.Currency != "RUB" {
+		t.Errorf("Unexpected budget: %+v", budget)
+	}
+	if w := send("POST", "/budgets", models.CreateBudget{CategoryID: food.ID, Month: "2025-07", Limit: models.NewMoney(500, 0)}); w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for duplicate budget, got %d", http.StatusConflict, w.Code)
+	}
+	if w := send("POST", "/budgets", models.CreateBudget{CategoryID: cafe.ID, Month: "2025-07", Limit: models.NewMoney(500, 0), Currency: "RUB"}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w = send("GET", "/budgets?month=2025-07", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var progress []models.BudgetProgress
+	json.NewDecoder(w.Body).Decode(&progress)
+	if len(progress) != 2 {
+		t.Fatalf("Expected 2 budgets, got %+v", progress)
+	}
+	// Бюджеты упорядочены по названию категории: Кафе, Продукты
+	if p := progress[1]; p.CategoryName != "Продукты" || p.Spent != models.NewMoney(500, 50) || p.Remaining != models.NewMoney(499, 50) || p.Exceeded {
+		t.Errorf("Unexpected food budget progress: %+v", p)
+	}
+	if p := progress[0]; p.CategoryName != "Кафе" || p.Spent != models.NewMoney(700, 0) || p.Remaining != -models.NewMoney(200, 0) || !p.Exceeded {
+		t.Errorf("Unexpected cafe budget progress: %+v", p)
+	}
+	if w := send("GET", "/budgets?month=2025-08", nil); w.Code != http.StatusOK || w.Body.String() != "[]" {
+		t.Errorf("Expected no budgets for August, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send("GET", "/budgets?month=july", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid month, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Недельный бюджет с понедельника 28 июля по 3 августа захватывает расходы двух месяцев
+	wednesday := time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC)
+	w = send("POST", "/budgets", models.CreateBudget{CategoryID: food.ID, Period: "week", StartDate: &wednesday, Limit: models.NewMoney(1000, 0), Currency: "RUB"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var week models.Budget
+	json.NewDecoder(w.Body).Decode(&week)
+	if week.Period != "week" || week.StartDate.Day() != 28 || week.EndDate.Day() != 3 || week.Month != "" {
+		t.Errorf("Unexpected weekly budget: %+v", week)
+	}
+	w = send("GET", "/budgets?date=2025-08-01", nil)
+	progress = nil
+	json.NewDecoder(w.Body).Decode(&progress)
+	if w.Code != http.StatusOK || len(progress) != 1 || progress[0].ID != week.ID || progress[0].Spent != models.NewMoney(1200, 50) ||
+		!progress[0].Exceeded || progress[0].Elapsed != 1 {
+		t.Errorf("Unexpected weekly budget progress: %d %+v", w.Code, progress)
+	}
+	if w := send("GET", "/budgets?month=2025-07", nil); json.NewDecoder(w.Body).Decode(&progress) != nil || len(progress) != 3 {
+		t.Errorf("Expected weekly budget among July budgets, got %+v", progress)
+	}
+	if w := send("GET", "/budgets?month=2025-07&date=2025-07-01", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for month and date, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Бюджет тега считает расходы с тегом во всех категориях
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(400, 0), CategoryID: food.ID, Date: time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC), Tags: []string{"vacation-2025"}},
+		{Amount: models.NewMoney(900, 0), CategoryID: cafe.ID, Date: time.Date(2025, 9, 3, 0, 0, 0, 0, time.UTC), Tags: []string{"vacation-2025"}},
+		{Amount: models.NewMoney(100, 0), CategoryID: cafe.ID, Date: time.Date(2025, 9, 4, 0, 0, 0, 0, time.UTC)},
+	} {
+		transaction.UserID, transaction.Type, transaction.Currency = user.ID, "expense", "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	tags, err := storage.GetTags(user.ID)
+	if err != nil || len(tags) != 1 {
+		t.Fatalf("Failed to get tags: %v %+v", err, tags)
+	}
+	for _, invalid := range []models.CreateBudget{
+		{CategoryID: food.ID, TagID: tags[0].ID, Month: "2025-09", Limit: models.NewMoney(1000, 0)},
+		{TagID: tags[0].ID + 1000, Month: "2025-09", Limit: models.NewMoney(1000, 0)},
+	} {
+		if w := send("POST", "/budgets", invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d: %s", http.StatusBadRequest, invalid, w.Code, w.Body.String())
+		}
+	}
+	w = send("POST", "/budgets", models.CreateBudget{TagID: tags[0].ID, Month: "2025-09", Limit: models.NewMoney(1000, 0), Currency: "RUB"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var tagBudget models.Budget
+	json.NewDecoder(w.Body).Decode(&tagBudget)
+	if tagBudget.TagID != tags[0].ID || tagBudget.CategoryID != 0 {
+		t.Errorf("Unexpected tag budget: %+v", tagBudget)
+	}
+	if w := send("POST", "/budgets", models.CreateBudget{TagID: tags[0].ID, Month: "2025-09", Limit: models.NewMoney(500, 0)}); w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for duplicate tag budget, got %d", http.StatusConflict, w.Code)
+	}
+	w = send("GET", "/budgets?month=2025-09", nil)
+	progress = nil
+	json.NewDecoder(w.Body).Decode(&progress)
+	if w.Code != http.StatusOK || len(progress) != 1 || progress[0].TagName != "vacation-2025" || progress[0].CategoryName != "" ||
+		progress[0].Spent != models.NewMoney(1300, 0) || !progress[0].Exceeded {
+		t.Errorf("Unexpected tag budget progress: %d %+v", w.Code, progress)
+	}
+
+	budgetPath := fmt.Sprintf("/budgets/%d", budget.ID)
+	if w := send("PUT", budgetPath, models.UpdateBudget{Limit: 0}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for zero limit, got %d", http.StatusBadRequest, w.Code)
+	}
+	w = send("PUT", budgetPath, models.UpdateBudget{Limit: models.NewMoney(400, 0)})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&budget)
+	if budget.Limit != models.NewMoney(400, 0) {
+		t.Errorf("Expected updated limit, got %+v", budget)
+	}
+
+	if w := send("DELETE", budgetPath, nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("GET", budgetPath, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after delete, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := send("DELETE", budgetPath, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for repeated delete, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// TestBudgetRollover тестирует перенос остатка и перерасхода переносимых бюджетов.
+func TestBudgetRollover(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	progress := func(month string) models.BudgetProgress {
+		w := send("GET", "/budgets?month="+month, nil)
+		var budgets []models.BudgetProgress
+		json.NewDecoder(w.Body).Decode(&budgets)
+		if w.Code != http.StatusOK || len(budgets) != 1 {
+			t.Fatalf("Expected one budget for %s, got %d: %s", month, w.Code, w.Body.String())
+		}
+		return budgets[0]
+	}
+
+	for _, month := range []string{"2025-06", "2025-07", "2025-08"} {
+		budget := models.CreateBudget{CategoryID: food.ID, Month: month, Limit: models.NewMoney(1000, 0), Currency: "RUB", Rollover: true}
+		if w := send("POST", "/budgets", budget); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	}
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(1200, 0), Date: time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(300, 0), Date: time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)},
+	} {
+		transaction.UserID, transaction.CategoryID, transaction.Type, transaction.Currency = user.ID, food.ID, "expense", "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	// Перерасход июня уменьшает июль, остаток июля переходит в август
+	if p := progress("2025-07"); p.Carryover != -models.NewMoney(200, 0) || p.Available != models.NewMoney(800, 0) || p.Remaining != models.NewMoney(500, 0) {
+		t.Errorf("Unexpected July progress: %+v", p)
+	}
+	if p := progress("2025-08"); p.Carryover != models.NewMoney(500, 0) || p.Available != models.NewMoney(1500, 0) {
+		t.Errorf("Unexpected August progress: %+v", p)
+	}
+
+	// Без переноса в июле август ничего не получает
+	july := progress("2025-07")
+	if w := send("PUT", fmt.Sprintf("/budgets/%d", july.ID), models.UpdateBudget{Limit: models.NewMoney(1000, 0)}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if p := progress("2025-07"); p.Rollover || p.Carryover != 0 || p.Available != models.NewMoney(1000, 0) {
+		t.Errorf("Unexpected July progress without rollover: %+v", p)
+	}
+	if p := progress("2025-08"); p.Carryover != 0 {
+		t.Errorf("Expected no August carryover, got %+v", p)
+	}
+}
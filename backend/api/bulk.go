@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// maxBulkTransactions — максимальное число транзакций в одном пакетном запросе.
+const maxBulkTransactions = 1000
+
+// @Security ApiKeyAuth
+// @Summary Создать несколько транзакций
+// @Description Создает транзакции из массива в одной транзакции БД. Если хотя бы один элемент
+// @Description не проходит проверку, не создается ни одна транзакция, а в results перечисляются ошибки по индексам.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param transactions body []models.CreateTransactionRequest true "Массив транзакций"
+// @Success 201 {object} models.BulkTransactionsResponse
+// @Failure 400 {object} models.BulkTransactionsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transactions/bulk [post]
+func (h *Handler) CreateTransactionsBulk(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	// Элементы пакета проверяются по отдельности ниже, чтобы вернуть ошибки по индексам
+	var transactions []*models.Transaction
+	if !bindJSON(c, &transactions) {
+		return
+	}
+	if len(transactions) == 0 {
+		badRequest(c, fieldError("transactions", "at least one transaction is required"))
+		return
+	}
+	if len(transactions) > maxBulkTransactions {
+		badRequest(c, fieldError("transactions", "at most %d transactions per request", maxBulkTransactions))
+		return
+	}
+
+	categories, err := h.storage.GetCategories(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ownCategories := make(map[int]bool, len(categories))
+	for _, category := range categories {
+		ownCategories[category.ID] = true
+	}
+
+	// Сначала проверяем весь пакет, чтобы вернуть все ошибки сразу
+	results := make([]models.BulkTransactionResult, len(transactions))
+	valid := true
+	now := time.Now()
+	for i, t := range transactions {
+		results[i].Index = i
+		if t == nil {
+			results[i].Error = "transaction is required"
+			valid = false
+			continue
+		}
+		if err := validateTransaction(*t); err != nil {
+			results[i].Error = err.Error()
+			valid = false
+			continue
+		}
+		if !ownCategories[t.CategoryID] {
+			results[i].Error = "category does not exist or does not belong to user"
+			valid = false
+			continue
+		}
+
+		t.ID = 0
+		t.UserID = userID.(int)
+		if t.Date.IsZero() {
+			t.Date = now
+		}
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, models.BulkTransactionsResponse{Error: "validation failed", Results: results})
+		return
+	}
+
+	if err := h.storage.CreateTransactions(transactions); err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	for i, t := range transactions {
+		results[i].Transaction = t
+	}
+	h.transactionsCreated(userID.(int), transactions...)
+	c.JSON(http.StatusCreated, models.BulkTransactionsResponse{Results: results})
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить несколько транзакций
+// @Description Перемещает в корзину одним запросом транзакции, выбранные списком ID и/или фильтром (тип, категория, период),
+// @Description и возвращает их число. Пустой запрос отклоняется, чтобы случайно не удалить все.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param request body models.DeleteTransactionsRequest true "Условия удаления"
+// @Success 200 {object} models.DeleteTransactionsResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transactions [delete]
+func (h *Handler) DeleteTransactionsBulk(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.DeleteTransactionsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if len(req.IDs) == 0 && req.Type == "" && req.CategoryID == 0 && req.DateFrom == nil && req.DateTo == nil {
+		badRequest(c, fieldError("ids", "ids or at least one filter is required"))
+		return
+	}
+
+	filter := db.TransactionFilter{Type: req.Type, CategoryID: req.CategoryID, IDs: req.IDs, IncludePlanned: true}
+	if req.DateFrom != nil {
+		filter.DateFrom = *req.DateFrom
+	}
+	if req.DateTo != nil {
+		filter.DateTo = *req.DateTo
+	}
+	if !filter.DateFrom.IsZero() && !filter.DateTo.IsZero() && filter.DateFrom.After(filter.DateTo) {
+		badRequest(c, fieldError("date_from", "date_from must not be after date_to"))
+		return
+	}
+
+	deleted, err := h.storage.DeleteTransactions(userID.(int), filter)
+	if err != nil {
+		if strings.Contains(err.Error(), "category does not exist") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DeleteTransactionsResponse{Deleted: deleted})
+}
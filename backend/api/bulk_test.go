@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestCreateTransactionsBulk тестирует пакетное создание транзакций.
+func TestCreateTransactionsBulk(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	other, err := storage.CreateUser("other", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	otherCategory, err := storage.CreateCategory(other.ID, "other")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	post := func(payload interface{}) (int, models.BulkTransactionsResponse) {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", "/transactions/bulk", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.BulkTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+
+	countTransactions := func() int {
+		_, total, err := storage.GetTransactions(user.ID, db.TransactionFilter{}, 1, 10)
+		if err != nil {
+			t.Fatalf("Failed to get transactions: %v", err)
+		}
+		return total
+	}
+
+	// Пустой массив отклоняется
+	if code, _ := post([]models.CreateTransactionRequest{}); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+
+	// Ошибки возвращаются по индексам, и ничего не сохраняется
+	code, response := post([]models.CreateTransactionRequest{
+		{Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID},
+		{Amount: models.NewMoney(-5, 0), Type: "expense", CategoryID: category.ID},
+		{Amount: models.NewMoney(10, 0), Type: "income", CategoryID: otherCategory.ID},
+	})
+	if code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+	if len(response.Results) != 3 || response.Results[0].Error != "" ||
+		response.Results[1].Error != "amount must be positive" ||
+		response.Results[2].Error != "category does not exist or does not belong to user" {
+		t.Errorf("Unexpected results: %+v", response.Results)
+	}
+	if total := countTransactions(); total != 0 {
+		t.Errorf("Expected no transactions to be created, got %d", total)
+	}
+
+	// Корректный пакет создается целиком
+	code, response = post([]models.CreateTransactionRequest{
+		{Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, Tags: []string{"trip"}},
+		{Amount: models.NewMoney(200, 0), Type: "income", CategoryID: category.ID, Currency: "USD"},
+	})
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+	if len(response.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", response.Results)
+	}
+	for i, result := range response.Results {
+		if result.Index != i || result.Transaction == nil || result.Transaction.ID == 0 || result.Transaction.UserID != user.ID {
+			t.Errorf("Unexpected result %d: %+v", i, result)
+		}
+	}
+	if response.Results[1].Transaction.Currency != "USD" || len(response.Results[0].Transaction.Tags) != 1 {
+		t.Errorf("Expected currency and tags to be saved, got %+v", response.Results)
+	}
+	if total := countTransactions(); total != 2 {
+		t.Errorf("Expected 2 transactions, got %d", total)
+	}
+}
+
+// TestDeleteTransactionsBulk тестирует удаление транзакций по списку ID и по фильтру.
+func TestDeleteTransactionsBulk(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	salary, err := storage.CreateCategory(user.ID, "salary")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	other, err := storage.CreateUser("other", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	otherCategory, err := storage.CreateCategory(other.ID, "other")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	january := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC)
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: food.ID, Date: january},
+		{UserID: user.ID, Amount: models.NewMoney(20, 0), Type: "expense", CategoryID: food.ID, Date: february},
+		{UserID: user.ID, Amount: models.NewMoney(30, 0), Type: "income", CategoryID: salary.ID, Date: january},
+		{UserID: user.ID, Amount: models.NewMoney(40, 0), Type: "income", CategoryID: salary.ID, Date: february},
+		{UserID: other.ID, Amount: models.NewMoney(50, 0), Type: "expense", CategoryID: otherCategory.ID, Date: january},
+	}
+	if err := storage.CreateTransactions(transactions); err != nil {
+		t.Fatalf("Failed to create transactions: %v", err)
+	}
+
+	remove := func(payload models.DeleteTransactionsRequest) (int, int64) {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("DELETE", "/transactions", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.DeleteTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response.Deleted
+	}
+
+	// Запрос без условий отклоняется
+	if code, _ := remove(models.DeleteTransactionsRequest{}); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+
+	// Чужие транзакции не удаляются, даже если указаны их ID
+	code, deleted := remove(models.DeleteTransactionsRequest{IDs: []int{transactions[0].ID, transactions[4].ID}})
+	if code != http.StatusOK || deleted != 1 {
+		t.Errorf("Expected 1 deleted transaction, got status %d and %d", code, deleted)
+	}
+
+	// Фильтр по типу и периоду
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	code, deleted = remove(models.DeleteTransactionsRequest{Type: "expense", DateFrom: &from})
+	if code != http.StatusOK || deleted != 1 {
+		t.Errorf("Expected 1 deleted transaction, got status %d and %d", code, deleted)
+	}
+
+	// Фильтр по категории
+	code, deleted = remove(models.DeleteTransactionsRequest{CategoryID: salary.ID})
+	if code != http.StatusOK || deleted != 2 {
+		t.Errorf("Expected 2 deleted transactions, got status %d and %d", code, deleted)
+	}
+
+	// Чужая категория отклоняется
+	if code, _ := remove(models.DeleteTransactionsRequest{CategoryID: otherCategory.ID}); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+
+	if _, total, err := storage.GetTransactions(other.ID, db.TransactionFilter{}, 1, 10); err != nil || total != 1 {
+		t.Errorf("Expected other user's transaction to remain, got %d (%v)", total, err)
+	}
+}
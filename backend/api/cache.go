@@ -0,0 +1,157 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCachedBody — ответы больше этого размера не кэшируются.
+const maxCachedBody = 1 << 20
+
+// cachedHeaders — заголовки ответа, которые сохраняются в кэше вместе с телом.
+var cachedHeaders = []string{"Content-Type", "Content-Disposition"}
+
+// cachedResponse — ответ, сохраненный в кэше.
+type cachedResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header"`
+	Body   []byte            `json:"body"`
+}
+
+// cachedRoute сообщает, кэшируется ли ответ на GET-запрос маршрута: списка категорий, сводки и отчетов.
+// Версия API в шаблоне маршрута не учитывается.
+func cachedRoute(path string) bool {
+	path = unversionedRoute(path)
+	return path == "/categories" || path == "/dashboard" || strings.HasPrefix(path, "/reports/")
+}
+
+// bodyRecorder копирует тело ответа, не задерживая его отправку.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// CacheMiddleware отдает из кэша ответы на GET-запросы категорий, сводки и отчетов и сбрасывает
+// кэш пользователя и пользователей общих с ним счетов после каждого успешного изменяющего запроса;
+// без Config.Cache ничего не делает. Данные, которые меняются без запросов пользователя, —
+// периодическими задачами и курсами валют — обновляются в кэше по истечении срока хранения записей.
+func (h *Handler) CacheMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if h.cfg.Cache == nil || !exists {
+			c.Next()
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet:
+			if cachedRoute(c.FullPath()) {
+				h.serveCached(c, userID.(int))
+				return
+			}
+			c.Next()
+		case http.MethodHead, http.MethodOptions:
+			c.Next()
+		default:
+			c.Next()
+			if c.Writer.Status() < http.StatusBadRequest {
+				h.invalidateCache(c.Request.Context(), userID.(int))
+			}
+		}
+	}
+}
+
+// invalidateCache сбрасывает кэш пользователей userIDs и всех, с кем у них общие счета: в их сводках
+// и отчетах есть транзакции и остатки общих счетов. Без Config.Cache ничего не делает; ошибки только логируются.
+func (h *Handler) invalidateCache(ctx context.Context, userIDs ...int) {
+	if h.cfg.Cache == nil {
+		return
+	}
+	users := map[int]bool{}
+	for _, userID := range userIDs {
+		users[userID] = true
+		if h.storage == nil {
+			continue
+		}
+		sharing, err := h.storage.GetSharingUsers(userID)
+		if err != nil {
+			log.Printf("failed to get users sharing accounts with user %d: %v", userID, err)
+		}
+		for _, id := range sharing {
+			users[id] = true
+		}
+	}
+	for userID := range users {
+		if err := h.cfg.Cache.Invalidate(ctx, userID); err != nil {
+			log.Printf("failed to invalidate cache of user %d: %v", userID, err)
+		}
+	}
+}
+
+// serveCached отдает ответ из кэша или выполняет запрос и сохраняет успешный ответ.
+// Ошибки кэша не прерывают запрос: он тогда выполняется как обычно.
+func (h *Handler) serveCached(c *gin.Context, userID int) {
+	ctx := c.Request.Context()
+	key := c.Request.URL.RequestURI()
+
+	// Версия читается до выполнения запроса: если данные изменятся во время него,
+	// ответ сохранится под прежней версией и больше не будет прочитан
+	version, err := h.cfg.Cache.Version(ctx, userID)
+	if err != nil {
+		log.Printf("failed to read cache version of user %d: %v", userID, err)
+		c.Next()
+		return
+	}
+
+	data, ok, err := h.cfg.Cache.Get(ctx, userID, version, key)
+	if err != nil {
+		log.Printf("failed to read cache of user %d: %v", userID, err)
+	}
+	var cached cachedResponse
+	if ok && json.Unmarshal(data, &cached) == nil {
+		for name, value := range cached.Header {
+			c.Header(name, value)
+		}
+		c.Header("X-Cache", "HIT")
+		c.Data(cached.Status, cached.Header["Content-Type"], cached.Body)
+		c.Abort()
+		return
+	}
+
+	recorder := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+	c.Header("X-Cache", "MISS")
+	c.Next()
+	if recorder.Status() != http.StatusOK || recorder.body.Len() > maxCachedBody {
+		return
+	}
+
+	cached = cachedResponse{Status: recorder.Status(), Header: map[string]string{}, Body: recorder.body.Bytes()}
+	for _, name := range cachedHeaders {
+		if value := recorder.Header().Get(name); value != "" {
+			cached.Header[name] = value
+		}
+	}
+	if data, err = json.Marshal(cached); err == nil {
+		err = h.cfg.Cache.Set(ctx, userID, version, key, data)
+	}
+	if err != nil {
+		log.Printf("failed to save cache of user %d: %v", userID, err)
+	}
+}
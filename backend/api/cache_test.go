@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// fakeCache — кэш в памяти для тестов.
+type fakeCache struct {
+	versions map[int]int64
+	entries  map[string][]byte
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{versions: map[int]int64{}, entries: map[string][]byte{}}
+}
+
+func (f *fakeCache) Version(ctx context.Context, userID int) (int64, error) {
+	return f.versions[userID], nil
+}
+
+func (f *fakeCache) Invalidate(ctx context.Context, userID int) error {
+	f.versions[userID]++
+	return nil
+}
+
+func (f *fakeCache) Get(ctx context.Context, userID int, version int64, key string) ([]byte, bool, error) {
+	value, ok := f.entries[fmt.Sprintf("%d:%d:%s", userID, version, key)]
+	return value, ok, nil
+}
+
+func (f *fakeCache) Set(ctx context.Context, userID int, version int64, key string, value []byte) error {
+	f.entries[fmt.Sprintf("%d:%d:%s", userID, version, key)] = value
+	return nil
+}
+
+// TestCacheMiddleware проверяет кэширование ответов по пользователю и сброс кэша изменяющими запросами.
+func TestCacheMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{cfg: Config{Cache: newFakeCache()}}
+
+	calls := 0
+	counter := func(c *gin.Context) {
+		calls++
+		c.Header("Content-Disposition", "inline")
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	}
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-User"); user != "" {
+			var id int
+			fmt.Sscan(user, &id)
+			c.Set("user_id", id)
+		}
+	}, h.CacheMiddleware())
+	r.GET("/dashboard", counter)
+	r.GET("/transactions", counter)
+	r.POST("/categories", func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{}) })
+	r.PUT("/categories/:id", func(c *gin.Context) { c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"}) })
+
+	request := func(method, path, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := request(http.MethodGet, "/dashboard", "1")
+	if first.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected MISS, got %q", first.Header().Get("X-Cache"))
+	}
+	second := request(http.MethodGet, "/dashboard", "1")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() || calls != 1 {
+		t.Errorf("Expected cached response, got %q %s after %d calls", second.Header().Get("X-Cache"), second.Body.String(), calls)
+	}
+	if second.Header().Get("Content-Type") != first.Header().Get("Content-Type") || second.Header().Get("Content-Disposition") != "inline" {
+		t.Errorf("Expected cached headers, got %v", second.Header())
+	}
+
+	// Другой запрос и другой пользователь кэшируются отдельно, прочие маршруты не кэшируются
+	request(http.MethodGet, "/dashboard?month=2024-01", "1")
+	request(http.MethodGet, "/dashboard", "2")
+	request(http.MethodGet, "/transactions", "1")
+	if w := request(http.MethodGet, "/transactions", "1"); w.Header().Get("X-Cache") != "" || calls != 5 {
+		t.Errorf("Expected uncached routes to run every time, got %q after %d calls", w.Header().Get("X-Cache"), calls)
+	}
+
+	// Неуспешное изменение не сбрасывает кэш, успешное — сбрасывает только у своего пользователя
+	request(http.MethodPut, "/categories/1", "1")
+	if w := request(http.MethodGet, "/dashboard", "1"); w.Header().Get("X-Cache") != "HIT" {
+		t.Error("Expected failed mutation to keep the cache")
+	}
+	request(http.MethodPost, "/categories", "1")
+	if w := request(http.MethodGet, "/dashboard", "1"); w.Header().Get("X-Cache") != "MISS" || w.Body.String() != `{"calls":6}` {
+		t.Errorf("Expected fresh response after mutation, got %q %s", w.Header().Get("X-Cache"), w.Body.String())
+	}
+	if w := request(http.MethodGet, "/dashboard", "2"); w.Header().Get("X-Cache") != "HIT" {
+		t.Error("Expected cache of another user to be kept")
+	}
+
+	// Без пользователя и без кэша запросы выполняются как обычно
+	if w := request(http.MethodGet, "/dashboard", ""); w.Header().Get("X-Cache") != "" {
+		t.Error("Expected anonymous request not to be cached")
+	}
+	h.cfg.Cache = nil
+	if w := request(http.MethodGet, "/dashboard", "1"); w.Header().Get("X-Cache") != "" {
+		t.Error("Expected no caching without cache store")
+	}
+}
+
+// TestInvalidateCacheSharedAccounts проверяет сброс кэша всех пользователей общего счета.
+func TestInvalidateCacheSharedAccounts(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+	fake := newFakeCache()
+	h := &Handler{storage: storage, cfg: Config{Cache: fake}}
+
+	users := make(map[string]*models.User)
+	for _, name := range []string{"owner", "editor", "viewer", "stranger"} {
+		user, err := storage.CreateUser(name, "password123")
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		users[name] = user
+	}
+	wallet, err := storage.CreateAccount(users["owner"].ID, models.CreateAccount{Name: "Общий", Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	for _, name := range []string{"editor", "viewer"} {
+		if _, err := storage.ShareAccount(wallet.ID, users["owner"].ID, users[name].ID, name); err != nil {
+			t.Fatalf("Failed to share account: %v", err)
+		}
+	}
+
+	// Изменение, сделанное редактором, видят владелец и другие пользователи счета
+	h.invalidateCache(context.Background(), users["editor"].ID)
+	for name, expected := range map[string]int64{"owner": 1, "editor": 1, "viewer": 1, "stranger": 0} {
+		if version := fake.versions[users[name].ID]; version != expected {
+			t.Errorf("Expected cache version %d for %s, got %d", expected, name, version)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLoginCaptchaThreshold = 3
+	loginFailureWindow           = 15 * time.Minute
+)
+
+// loginFailures считает неудачные попытки входа по имени пользователя
+// в пределах скользящего окна loginFailureWindow.
+type loginFailures struct {
+	mu       sync.Mutex
+	attempts map[string]loginFailure
+}
+
+type loginFailure struct {
+	count int
+	last  time.Time
+}
+
+func newLoginFailures() *loginFailures {
+	return &loginFailures{attempts: make(map[string]loginFailure)}
+}
+
+func (f *loginFailures) key(username string) string {
+	return strings.ToLower(username)
+}
+
+func (f *loginFailures) count(username string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failure, ok := f.attempts[f.key(username)]
+	if !ok || time.Since(failure.last) > loginFailureWindow {
+		return 0
+	}
+	return failure.count
+}
+
+func (f *loginFailures) add(username string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := f.key(username)
+	failure := f.attempts[key]
+	if time.Since(failure.last) > loginFailureWindow {
+		failure.count = 0
+	}
+	failure.count++
+	failure.last = time.Now()
+	f.attempts[key] = failure
+
+	// Периодически убираем устаревшие записи, чтобы карта не росла бесконечно
+	if len(f.attempts) > 10000 {
+		for k, v := range f.attempts {
+			if time.Since(v.last) > loginFailureWindow {
+				delete(f.attempts, k)
+			}
+		}
+	}
+}
+
+func (f *loginFailures) reset(username string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.attempts, f.key(username))
+}
+
+// verifyCaptcha проверяет CAPTCHA-токен клиента. Если проверка не настроена, всегда успешна.
+func (h *Handler) verifyCaptcha(c *gin.Context, token string) (bool, error) {
+	if h.cfg.Captcha == nil {
+		return true, nil
+	}
+	return h.cfg.Captcha.Verify(c.Request.Context(), token, c.ClientIP())
+}
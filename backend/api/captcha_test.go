@@ -0,0 +1,73 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// fakeCaptcha принимает только токен "valid".
+type fakeCaptcha struct{}
+
+func (fakeCaptcha) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	return token == "valid", nil
+}
+
+// TestCaptcha тестирует проверку CAPTCHA при регистрации и после неудачных входов.
+func TestCaptcha(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Captcha: fakeCaptcha{}, LoginCaptchaThreshold: 2})
+	r := gin.New()
+	r.POST("/register", handler.Register)
+	r.POST("/login", handler.Login)
+
+	post := func(path string, payload interface{}) int {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Регистрация без CAPTCHA отклоняется
+	if code := post("/register", models.CreateUser{Login: "testuser", Password: "password123"}); code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, code)
+	}
+
+	// Регистрация с корректной CAPTCHA успешна
+	if code := post("/register", models.CreateUser{Login: "testuser", Password: "password123", CaptchaToken: "valid"}); code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+
+	// Первые попытки входа CAPTCHA не требуют
+	if code := post("/login", models.LoginRequest{Username: "testuser", Password: "password123"}); code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, code)
+	}
+	for i := 0; i < 2; i++ {
+		if code := post("/login", models.LoginRequest{Username: "testuser", Password: "wrong"}); code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, code)
+		}
+	}
+
+	// После двух неудач даже верный пароль требует CAPTCHA
+	if code := post("/login", models.LoginRequest{Username: "testuser", Password: "password123"}); code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, code)
+	}
+	if code := post("/login", models.LoginRequest{Username: "testuser", Password: "password123", CaptchaToken: "valid"}); code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, code)
+	}
+
+	// Успешный вход сбрасывает счетчик неудач
+	if code := post("/login", models.LoginRequest{Username: "testuser", Password: "password123"}); code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, code)
+	}
+}
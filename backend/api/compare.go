@@ -0,0 +1,190 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+// parseComparedPeriod читает период из параметра name: год YYYY, месяц YYYY-MM
+// или произвольный диапазон YYYY-MM-DD..YYYY-MM-DD. Возвращает первый и последний дни периода.
+func parseComparedPeriod(c *gin.Context, name string) (from, to time.Time, err error) {
+	value := c.Query(name)
+	if value == "" {
+		return from, to, fmt.Errorf("%s is required", name)
+	}
+	invalid := fmt.Errorf("%s must be YYYY, YYYY-MM or YYYY-MM-DD..YYYY-MM-DD", name)
+
+	if first, last, ok := strings.Cut(value, ".."); ok {
+		if from, err = time.Parse("2006-01-02", first); err != nil {
+			return from, to, invalid
+		}
+		if to, err = time.Parse("2006-01-02", last); err != nil {
+			return from, to, invalid
+		}
+		if from.After(to) {
+			return from, to, fmt.Errorf("%s must not end before it starts", name)
+		}
+		return from, to, nil
+	}
+	if from, err = time.Parse("2006-01", value); err == nil {
+		return from, from.AddDate(0, 1, -1), nil
+	}
+	if from, err = time.Parse("2006", value); err == nil {
+		return from, from.AddDate(1, 0, -1), nil
+	}
+	return from, to, invalid
+}
+
+// compareCategories пересчитывает суммы категорий двух периодов в currency и сопоставляет их.
+// Категории упорядочены по названию, транзакции без категории идут последними.
+func compareCategories(a, b []models.CategoryTotals, exchangeRates map[string]float64, currency string) ([]models.CategoryComparison, error) {
+	convertedA, err := convertCategoryTotals(a, exchangeRates, currency)
+	if err != nil {
+		return nil, err
+	}
+	convertedB, err := convertCategoryTotals(b, exchangeRates, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []models.CategoryComparison{}
+	index := make(map[int]int)
+	add := func(rows []models.CategoryTotals, period func(*models.CategoryComparison) *models.ComparisonTotals) {
+		for _, row := range rows {
+			i, ok := index[row.CategoryID]
+			if !ok {
+				i = len(categories)
+				index[row.CategoryID] = i
+				categories = append(categories, models.CategoryComparison{CategoryID: row.CategoryID, CategoryName: row.Category})
+			}
+			*period(&categories[i]) = models.ComparisonTotals{Income: row.Income, Expense: row.Expense}
+		}
+	}
+	add(convertedA, func(c *models.CategoryComparison) *models.ComparisonTotals { return &c.A })
+	add(convertedB, func(c *models.CategoryComparison) *models.ComparisonTotals { return &c.B })
+
+	for i := range categories {
+		category := &categories[i]
+		category.A.Net = category.A.Income - category.A.Expense
+		category.B.Net = category.B.Income - category.B.Expense
+		category.Delta = compareTotals(category.A, category.B)
+		category.Direction, category.ExpenseChange = spendingTrend(category.B.Expense, category.A.Expense)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		if (categories[i].CategoryID == 0) != (categories[j].CategoryID == 0) {
+			return categories[j].CategoryID == 0
+		}
+		return categories[i].CategoryName < categories[j].CategoryName
+	})
+	return categories, nil
+}
+
+// compareTotals возвращает разницу итогов b и a.
+func compareTotals(a, b models.ComparisonTotals) models.ComparisonTotals {
+	return models.ComparisonTotals{Income: b.Income - a.Income, Expense: b.Expense - a.Expense, Net: b.Net - a.Net}
+}
+
+// @Security ApiKeyAuth
+// @Summary Сравнение периодов
+// @Description Возвращает доходы, расходы за вычетом возвратов и их разницу за два периода в базовой валюте пользователя
+// @Description и те же суммы по категориям с изменением от периода A к периоду B. Периоды могут пересекаться и иметь разную длину.
+// @Description Переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param period_a query string true "Базовый период: год YYYY, месяц YYYY-MM или диапазон YYYY-MM-DD..YYYY-MM-DD"
+// @Param period_b query string true "Сравниваемый период в том же формате"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.PeriodComparison
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/compare [get]
+func (h *Handler) ComparePeriods(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	fromA, toA, err := parseComparedPeriod(c, "period_a")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fromB, toB, err := parseComparedPeriod(c, "period_b")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	includePlanned, err := parseIncludePlanned(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	totalsA, err := h.storage.GetCategoryTotals(user.ID, fromA, toA.AddDate(0, 0, 1), includePlanned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	totalsB, err := h.storage.GetCategoryTotals(user.ID, fromB, toB.AddDate(0, 0, 1), includePlanned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	currencies := make([]models.TransactionTotals, 0, len(totalsA)+len(totalsB))
+	for _, rows := range [][]models.CategoryTotals{totalsA, totalsB} {
+		for _, row := range rows {
+			currencies = append(currencies, models.TransactionTotals{Currency: row.Currency})
+		}
+	}
+	exchangeRates, err := h.exchangeRatesFor(c.Request.Context(), user.BaseCurrency, currencies)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+	categories, err := compareCategories(totalsA, totalsB, exchangeRates, user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+
+	comparison := models.PeriodComparison{
+		Currency:   user.BaseCurrency,
+		A:          models.ComparedPeriod{From: fromA.Format("2006-01-02"), To: toA.Format("2006-01-02")},
+		B:          models.ComparedPeriod{From: fromB.Format("2006-01-02"), To: toB.Format("2006-01-02")},
+		Categories: categories,
+	}
+	for _, category := range categories {
+		comparison.A.Income += category.A.Income
+		comparison.A.Expense += category.A.Expense
+		comparison.B.Income += category.B.Income
+		comparison.B.Expense += category.B.Expense
+	}
+	comparison.A.Net = comparison.A.Income - comparison.A.Expense
+	comparison.B.Net = comparison.B.Income - comparison.B.Expense
+	comparison.Delta = compareTotals(comparison.A.ComparisonTotals, comparison.B.ComparisonTotals)
+
+	filename := fmt.Sprintf("compare-%s-%s-vs-%s-%s", comparison.A.From, comparison.A.To, comparison.B.From, comparison.B.To)
+	writeReport(c, filename, comparison, func() report.Workbook { return periodComparisonWorkbook(comparison) })
+}
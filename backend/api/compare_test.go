@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestParseComparedPeriod тестирует разбор периода сравнения.
+func TestParseComparedPeriod(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		value    string
+		from, to time.Time
+		wantErr  bool
+	}{
+		{value: "2024-02", from: date(2024, 2, 1), to: date(2024, 2, 29)},
+		{value: "2025", from: date(2025, 1, 1), to: date(2025, 12, 31)},
+		{value: "2025-06-10..2025-07-09", from: date(2025, 6, 10), to: date(2025, 7, 9)},
+		{value: "2025-06-10..2025-06-10", from: date(2025, 6, 10), to: date(2025, 6, 10)},
+		{value: "", wantErr: true},
+		{value: "2025-07-09..2025-06-10", wantErr: true},
+		{value: "2025-06-10..", wantErr: true},
+		{value: "2025-13", wantErr: true},
+		{value: "июнь", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("GET", "/?period_a="+tt.value, nil)
+		from, to, err := parseComparedPeriod(c, "period_a")
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Expected error for %q", tt.value)
+			}
+			continue
+		}
+		if err != nil || !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("parseComparedPeriod(%q) = %s, %s, %v; expected %s, %s", tt.value, from, to, err, tt.from, tt.to)
+		}
+	}
+}
+
+// TestCompareCategories тестирует сопоставление категорий двух периодов.
+func TestCompareCategories(t *testing.T) {
+	exchangeRates := map[string]float64{"RUB": 1, "USD": 1.0 / 80}
+	a := []models.CategoryTotals{
+		{CategoryID: 2, Category: "Продукты", Currency: "RUB", Expense: models.NewMoney(1000, 0)},
+		{CategoryID: 2, Category: "Продукты", Currency: "USD", Expense: models.NewMoney(10, 0)},
+		{CategoryID: 0, Currency: "RUB", Expense: models.NewMoney(300, 0)},
+		{CategoryID: 3, Category: "Зарплата", Currency: "RUB", Income: models.NewMoney(50000, 0)},
+	}
+	b := []models.CategoryTotals{
+		{CategoryID: 2, Category: "Продукты", Currency: "RUB", Expense: models.NewMoney(1350, 0)},
+		{CategoryID: 1, Category: "Кафе", Currency: "RUB", Expense: models.NewMoney(500, 0)},
+	}
+	categories, err := compareCategories(a, b, exchangeRates, "RUB")
+	if err != nil {
+		t.Fatalf("Failed to compare categories: %v", err)
+	}
+	if len(categories) != 4 || categories[0].CategoryName != "Зарплата" || categories[1].CategoryName != "Кафе" ||
+		categories[2].CategoryName != "Продукты" || categories[3].CategoryID != 0 {
+		t.Fatalf("Unexpected categories order: %+v", categories)
+	}
+	if food := categories[2]; food.A.Expense != models.NewMoney(1800, 0) || food.B.Expense != models.NewMoney(1350, 0) ||
+		food.Delta.Expense != models.NewMoney(-450, 0) || food.Direction != "down" || food.ExpenseChange == nil || *food.ExpenseChange != -25 {
+		t.Errorf("Unexpected food comparison: %+v", food)
+	}
+	if cafe := categories[1]; cafe.Direction != "new" || cafe.ExpenseChange != nil || cafe.Delta.Net != models.NewMoney(-500, 0) {
+		t.Errorf("Unexpected cafe comparison: %+v", cafe)
+	}
+	if salary := categories[0]; salary.Delta.Income != models.NewMoney(-50000, 0) || salary.Direction != "flat" {
+		t.Errorf("Unexpected salary comparison: %+v", salary)
+	}
+
+	if _, err := compareCategories(a, b, nil, "RUB"); err == nil {
+		t.Error("Expected error without exchange rates")
+	}
+}
+
+// TestComparePeriods тестирует сравнение двух периодов.
+func TestComparePeriods(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for _, transaction := range []*models.Transaction{
+		{Type: "expense", Amount: models.NewMoney(4000, 0), CategoryID: food.ID, Date: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{Type: "income", Amount: models.NewMoney(60000, 0), Date: time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC)},
+		{Type: "expense", Amount: models.NewMoney(5000, 0), CategoryID: food.ID, Date: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{Type: "income", Amount: models.NewMoney(70000, 0), Date: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)},
+		{Type: "expense", Amount: models.NewMoney(9000, 0), CategoryID: food.ID, Date: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		transaction.UserID, transaction.Currency = user.ID, "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/compare"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?period_a=2024-06&period_b=2025-06")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var comparison models.PeriodComparison
+	json.NewDecoder(w.Body).Decode(&comparison)
+	if comparison.A.From != "2024-06-01" || comparison.A.To != "2024-06-30" || comparison.B.From != "2025-06-01" || comparison.B.To != "2025-06-30" {
+		t.Errorf("Unexpected periods: %+v, %+v", comparison.A, comparison.B)
+	}
+	if comparison.A.Expense != models.NewMoney(4000, 0) || comparison.B.Income != models.NewMoney(70000, 0) ||
+		comparison.Delta.Expense != models.NewMoney(1000, 0) || comparison.Delta.Net != models.NewMoney(9000, 0) {
+		t.Errorf("Unexpected totals: %+v", comparison)
+	}
+	if len(comparison.Categories) != 2 || comparison.Categories[0].CategoryID != food.ID || comparison.Categories[1].CategoryID != 0 {
+		t.Fatalf("Unexpected categories: %+v", comparison.Categories)
+	}
+	if change := comparison.Categories[0].ExpenseChange; change == nil || *change != 25 {
+		t.Errorf("Expected food expense change 25%%, got %v", change)
+	}
+
+	w = get("?period_a=2025-06-01..2025-06-15&period_b=2025-06-16..2025-07-01&format=xlsx")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != xlsxContentType {
+		t.Errorf("Expected XLSX workbook, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	for _, query := range []string{"?period_a=2025-06", "?period_a=2025-06&period_b=06.2025", "?period_a=2025-06&period_b=2025-05&format=csv"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
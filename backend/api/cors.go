@@ -0,0 +1,68 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// Значения CORS по умолчанию: методы и заголовки, которые использует API.
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "Accept", "If-None-Match", RequestIDHeader}
+	// corsExposedHeaders — заголовки ответа, доступные скриптам на другом источнике
+	corsExposedHeaders = []string{RequestIDHeader, "Content-Disposition", "Retry-After",
+		"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-Cache", "ETag", "Deprecation", "Sunset", "Link"}
+)
+
+const defaultCORSMaxAge = 12 * time.Hour
+
+// CORSConfig — настройки CORS.
+type CORSConfig struct {
+	// AllowedOrigins — источники, которым разрешены запросы, например https://app.example.com;
+	// "*" разрешает любой источник, а * внутри адреса — любую часть имени, например https://*.example.com
+	AllowedOrigins []string
+	// AllowedMethods и AllowedHeaders — разрешенные методы и заголовки запросов; пустые списки
+	// заменяются значениями по умолчанию
+	AllowedMethods []string
+	AllowedHeaders []string
+	// MaxAge — сколько браузер хранит результат предварительного запроса; по умолчанию 12 часов
+	MaxAge time.Duration
+}
+
+// CORSMiddleware отвечает на предварительные запросы OPTIONS и добавляет заголовки CORS
+// к ответам на запросы с разрешенных источников. Возвращает nil, если источники не заданы.
+func CORSMiddleware(cfg CORSConfig) (gin.HandlerFunc, error) {
+	if len(cfg.AllowedOrigins) == 0 {
+		return nil, nil
+	}
+	config := cors.Config{
+		AllowMethods:  cfg.AllowedMethods,
+		AllowHeaders:  cfg.AllowedHeaders,
+		ExposeHeaders: corsExposedHeaders,
+		MaxAge:        cfg.MaxAge,
+		AllowWildcard: true,
+	}
+	if len(config.AllowMethods) == 0 {
+		config.AllowMethods = defaultCORSMethods
+	}
+	if len(config.AllowHeaders) == 0 {
+		config.AllowHeaders = defaultCORSHeaders
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaultCORSMaxAge
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			config.AllowAllOrigins = true
+		}
+	}
+	if !config.AllowAllOrigins {
+		config.AllowOrigins = cfg.AllowedOrigins
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return cors.New(config), nil
+}
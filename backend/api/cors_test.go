@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestCORSMiddleware проверяет предварительные запросы и заголовки CORS для разрешенных
+// и неразрешенных источников.
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	if middleware, err := CORSMiddleware(CORSConfig{}); middleware != nil || err != nil {
+		t.Fatalf("Expected CORS to be disabled without origins, got %v", err)
+	}
+	if _, err := CORSMiddleware(CORSConfig{AllowedOrigins: []string{"app.example.com"}}); err == nil {
+		t.Error("Expected error for origin without scheme")
+	}
+
+	middleware, err := CORSMiddleware(CORSConfig{AllowedOrigins: []string{"https://app.example.com", "https://*.preview.example.com"}})
+	if err != nil {
+		t.Fatalf("CORSMiddleware failed: %v", err)
+	}
+	r := gin.New()
+	r.Use(middleware)
+	r.GET("/transactions", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/transactions", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodOptions, "https://app.example.com")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected preflight status 204, got %d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || w.Header().Get("Access-Control-Allow-Headers") == "" ||
+		w.Header().Get("Access-Control-Max-Age") != "43200" {
+		t.Errorf("Unexpected preflight headers: %v", w.Header())
+	}
+
+	w = request(http.MethodGet, "https://pr-1.preview.example.com")
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://pr-1.preview.example.com" {
+		t.Errorf("Expected wildcard origin to be allowed, got %d %v", w.Code, w.Header())
+	}
+	if w.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Error("Expected exposed headers")
+	}
+
+	if w := request(http.MethodGet, "https://evil.example.org"); w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected foreign origin to be rejected, got %d %v", w.Code, w.Header())
+	}
+
+	// Звездочка разрешает любой источник
+	all, err := CORSMiddleware(CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}})
+	if err != nil {
+		t.Fatalf("CORSMiddleware failed: %v", err)
+	}
+	r = gin.New()
+	r.Use(all)
+	r.GET("/transactions", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	if w := request(http.MethodGet, "https://any.example.org"); w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("Expected any origin to be allowed, got %v", w.Header())
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const (
+	// minimumPaymentPercent — обязательный платеж в процентах от долга по выписке.
+	minimumPaymentPercent = 5
+	// paymentReminderDays — за сколько дней до срока платежа появляется напоминание.
+	paymentReminderDays = 7
+)
+
+// statementDates возвращает первый и последний день последней закрытой выписки и срок платежа по ней.
+// Выписка закрывается в конце дня statementDay, платеж вносится до конца ближайшего после этого дня paymentDueDay.
+func statementDates(now time.Time, statementDay, paymentDueDay int) (start, end, due time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	end = time.Date(today.Year(), today.Month(), statementDay, 0, 0, 0, 0, time.UTC)
+	if !today.After(end) {
+		end = end.AddDate(0, -1, 0)
+	}
+	start = end.AddDate(0, -1, 1)
+	due = time.Date(end.Year(), end.Month(), paymentDueDay, 0, 0, 0, 0, time.UTC)
+	if paymentDueDay <= statementDay {
+		due = due.AddDate(0, 1, 0)
+	}
+	return start, end, due
+}
+
+// creditCardStatement собирает выписку кредитной карты по остатку счета на конец выписки
+// и поступлениям на карту после ее закрытия.
+func creditCardStatement(account *models.Account, now time.Time, closingBalance, paid models.Money) models.CreditCardStatement {
+	start, end, due := statementDates(now, account.StatementDay, account.PaymentDueDay)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	statement := models.CreditCardStatement{
+		AccountID:    account.ID,
+		Currency:     account.Currency,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		DueDate:      due,
+		Paid:         paid,
+		DaysUntilDue: int(due.Sub(today).Hours() / 24),
+	}
+	if closingBalance < 0 {
+		statement.StatementBalance = -closingBalance
+	}
+	if account.Balance < 0 {
+		statement.CurrentBalance = -account.Balance
+	}
+	if available := account.CreditLimit + account.Balance; available > 0 {
+		statement.AvailableCredit = available
+	}
+	if remaining := statement.StatementBalance - paid; remaining > 0 {
+		statement.RemainingDue = remaining
+	}
+	// Обязательный платеж округляется вверх до копейки
+	statement.MinimumPayment = (statement.StatementBalance*minimumPaymentPercent + 99) / 100
+	if minimumDue := statement.MinimumPayment - paid; minimumDue > 0 {
+		statement.MinimumDue = minimumDue
+	}
+
+	if statement.MinimumDue > 0 {
+		statement.Overdue = today.After(due)
+		switch {
+		case statement.Overdue:
+			statement.Reminder = fmt.Sprintf("Обязательный платеж %s %s просрочен: срок был %s",
+				statement.MinimumDue, account.Currency, due.Format("02.01.2006"))
+		case statement.DaysUntilDue <= paymentReminderDays:
+			statement.Reminder = fmt.Sprintf("Внесите обязательный платеж %s %s до %s",
+				statement.MinimumDue, account.Currency, due.Format("02.01.2006"))
+		}
+	}
+	return statement
+}
+
+// @Security ApiKeyAuth
+// @Summary Выписка кредитной карты
+// @Description Возвращает долг по последней закрытой выписке, обязательный платеж и срок его внесения.
+// @Description Платежами считаются поступления на карту после закрытия выписки. Напоминание возвращается,
+// @Description если обязательный платеж не внесен, а до срока осталось не больше недели или срок прошел
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Success 200 {object} models.CreditCardStatement
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/statement [get]
+func (h *Handler) GetCreditCardStatement(c *gin.Context) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+	if account.Type != "credit_card" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account is not a credit card"})
+		return
+	}
+
+	now := time.Now()
+	_, end, _ := statementDates(now, account.StatementDay, account.PaymentDueDay)
+	closingBalance, paid, err := h.storage.GetStatementAmounts(account.ID, c.GetInt("user_id"), end.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, creditCardStatement(account, now, closingBalance, paid))
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestStatementDates тестирует границы выписки и срок платежа.
+func TestStatementDates(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		now                     time.Time
+		statementDay, dueDay    int
+		wantStart, wantEnd, due time.Time
+	}{
+		// День закрытия еще не закончился — последняя закрытая выписка прошлого месяца
+		{date(2025, 3, 25), 25, 15, date(2025, 1, 26), date(2025, 2, 25), date(2025, 3, 15)},
+		{date(2025, 3, 26), 25, 15, date(2025, 2, 26), date(2025, 3, 25), date(2025, 4, 15)},
+		// Срок платежа в том же месяце, что и закрытие
+		{date(2025, 3, 10), 5, 25, date(2025, 2, 6), date(2025, 3, 5), date(2025, 3, 25)},
+		{date(2025, 1, 3), 5, 25, date(2024, 11, 6), date(2024, 12, 5), date(2024, 12, 25)},
+	}
+	for _, tt := range tests {
+		start, end, due := statementDates(tt.now, tt.statementDay, tt.dueDay)
+		if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) || !due.Equal(tt.due) {
+			t.Errorf("statementDates(%s, %d, %d) = %s, %s, %s; want %s, %s, %s",
+				tt.now.Format("2006-01-02"), tt.statementDay, tt.dueDay,
+				start.Format("2006-01-02"), end.Format("2006-01-02"), due.Format("2006-01-02"),
+				tt.wantStart.Format("2006-01-02"), tt.wantEnd.Format("2006-01-02"), tt.due.Format("2006-01-02"))
+		}
+	}
+}
+
+// TestCreditCardStatementReminder тестирует обязательный платеж и напоминание о нем.
+func TestCreditCardStatementReminder(t *testing.T) {
+	account := &models.Account{ID: 1, Currency: "RUB", Type: "credit_card", CreditLimit: models.NewMoney(100000, 0),
+		StatementDay: 25, PaymentDueDay: 15, Balance: models.NewMoney(-31000, 0)}
+
+	// Долг по выписке 25000, внесено 1000 из обязательных 1250, до срока 5 дней
+	statement := creditCardStatement(account, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC), models.NewMoney(-25000, 0), models.NewMoney(1000, 0))
+	if statement.StatementBalance != models.NewMoney(25000, 0) || statement.RemainingDue != models.NewMoney(24000, 0) ||
+		statement.MinimumPayment != models.NewMoney(1250, 0) || statement.MinimumDue != models.NewMoney(250, 0) {
+		t.Errorf("Unexpected statement amounts: %+v", statement)
+	}
+	if statement.CurrentBalance != models.NewMoney(31000, 0) || statement.AvailableCredit != models.NewMoney(69000, 0) {
+		t.Errorf("Unexpected current balance: %+v", statement)
+	}
+	if statement.DaysUntilDue != 5 || statement.Overdue || statement.Reminder != "Внесите обязательный платеж 250.00 RUB до 15.03.2025" {
+		t.Errorf("Unexpected reminder: %+v", statement)
+	}
+
+	// Задолго до срока напоминания нет
+	if statement := creditCardStatement(account, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), models.NewMoney(-25000, 0), 0); statement.Reminder != "" {
+		t.Errorf("Expected no reminder, got %q", statement.Reminder)
+	}
+	// После срока невнесенный платеж просрочен
+	statement = creditCardStatement(account, time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC), models.NewMoney(-25000, 0), 0)
+	if !statement.Overdue || statement.Reminder != "Обязательный платеж 1250.00 RUB просрочен: срок был 15.03.2025" {
+		t.Errorf("Unexpected overdue statement: %+v", statement)
+	}
+	// Внесенный обязательный платеж снимает напоминание
+	statement = creditCardStatement(account, time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC), models.NewMoney(-25000, 0), models.NewMoney(2000, 0))
+	if statement.Overdue || statement.Reminder != "" || statement.MinimumDue != 0 {
+		t.Errorf("Unexpected paid statement: %+v", statement)
+	}
+}
+
+// TestCreditCardStatement тестирует кредитные карты и их выписку.
+func TestCreditCardStatement(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, invalid := range []models.CreateAccount{
+		{Name: "Карта", Type: "credit_card", StatementDay: 25, PaymentDueDay: 15},
+		{Name: "Карта", Type: "credit_card", CreditLimit: models.NewMoney(1000, 0), StatementDay: 31, PaymentDueDay: 15},
+		{Name: "Карта", Type: "debit", CreditLimit: models.NewMoney(1000, 0), StatementDay: 25, PaymentDueDay: 15},
+		{Name: "Наличные", CreditLimit: models.NewMoney(1000, 0)},
+	} {
+		if w := send("POST", "/accounts", invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d", http.StatusBadRequest, invalid, w.Code)
+		}
+	}
+
+	w := send("POST", "/accounts", models.CreateAccount{Name: "Кредитка", Type: "credit_card",
+		CreditLimit: models.NewMoney(50000, 0), StatementDay: 25, PaymentDueDay: 15})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var card models.Account
+	json.NewDecoder(w.Body).Decode(&card)
+	if card.Type != "credit_card" || card.CreditLimit != models.NewMoney(50000, 0) || card.StatementDay != 25 || card.PaymentDueDay != 15 {
+		t.Errorf("Unexpected credit card: %+v", card)
+	}
+
+	// Покупка в закрытой выписке, покупка и платеж после ее закрытия
+	_, end, _ := statementDates(time.Now(), 25, 15)
+	for _, tx := range []models.CreateTransactionRequest{
+		{Amount: models.NewMoney(10000, 0), Type: "expense", Date: end.Add(12 * time.Hour)},
+		{Amount: models.NewMoney(3000, 0), Type: "expense", Date: end.AddDate(0, 0, 1)},
+		{Amount: models.NewMoney(200, 0), Type: "income", Date: end.AddDate(0, 0, 1)},
+	} {
+		tx.CategoryID, tx.AccountID = category.ID, card.ID
+		if w := send("POST", "/transactions", tx); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	}
+
+	w = send("GET", fmt.Sprintf("/accounts/%d/statement", card.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var statement models.CreditCardStatement
+	json.NewDecoder(w.Body).Decode(&statement)
+	if statement.StatementBalance != models.NewMoney(10000, 0) || statement.Paid != models.NewMoney(200, 0) ||
+		statement.MinimumPayment != models.NewMoney(500, 0) || statement.MinimumDue != models.NewMoney(300, 0) ||
+		statement.CurrentBalance != models.NewMoney(12800, 0) || statement.AvailableCredit != models.NewMoney(37200, 0) {
+		t.Errorf("Unexpected statement: %+v", statement)
+	}
+
+	// Условия кредитной карты меняются вместе со счетом, но не задаются обычному счету
+	if w := send("PUT", fmt.Sprintf("/accounts/%d", card.ID), models.UpdateAccount{Name: "Кредитка",
+		CreditLimit: models.NewMoney(80000, 0), StatementDay: 25, PaymentDueDay: 15}); w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w := send("PUT", fmt.Sprintf("/accounts/%d", card.ID), models.UpdateAccount{Name: "Кредитка"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	cash, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	if w := send("GET", fmt.Sprintf("/accounts/%d/statement", cash.ID), nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/rates"
+	"golang.org/x/sync/singleflight"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// rateRetryInterval — через сколько после неудачного запроса к провайдеру курсы запрашиваются снова.
+// До этого отдаются последние сохраненные курсы.
+const rateRetryInterval = 15 * time.Minute
+
+// rateCache отдает курсы валют из таблицы exchange_rates и обновляет их
+// у провайдера не чаще раза в сутки.
+type rateCache struct {
+	storage  *db.Storage
+	provider rates.Provider
+	// refresh объединяет одновременные запросы к провайдеру в один
+	refresh singleflight.Group
+	// failedAt — время последнего неудачного запроса к провайдеру в наносекундах Unix; 0 — запрос удался
+	failedAt atomic.Int64
+}
+
+func newRateCache(storage *db.Storage, provider rates.Provider) *rateCache {
+	return &rateCache{storage: storage, provider: provider}
+}
+
+// latest возвращает курсы на сегодня. Если провайдер недоступен, используются последние сохраненные курсы,
+// а провайдер запрашивается снова не раньше чем через rateRetryInterval.
+func (rc *rateCache) latest(ctx context.Context) (map[string]float64, error) {
+	date, cached, err := rc.storage.GetLatestExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if rc.provider == nil || !date.Before(today) {
+		return cached, nil
+	}
+	if cached != nil && time.Since(time.Unix(0, rc.failedAt.Load())) < rateRetryInterval {
+		return cached, nil
+	}
+
+	// Запрос к провайдеру общий для всех ожидающих, поэтому не прерывается отменой запроса одного из них
+	fresh, err, _ := rc.refresh.Do(today.Format("2006-01-02"), func() (any, error) {
+		return rc.fetch(context.WithoutCancel(ctx), today)
+	})
+	if err != nil {
+		if cached != nil {
+			log.Printf("failed to refresh exchange rates, using rates from %s: %v", date.Format("2006-01-02"), err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	return fresh.(map[string]float64), nil
+}
+
+// fetch запрашивает курсы у провайдера и сохраняет их на дату today.
+func (rc *rateCache) fetch(ctx context.Context, today time.Time) (map[string]float64, error) {
+	fresh, err := rc.provider.Fetch(ctx)
+	if err != nil {
+		rc.failedAt.Store(time.Now().UnixNano())
+		return nil, err
+	}
+	rc.failedAt.Store(0)
+	if err := rc.storage.SaveExchangeRates(today, fresh); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+// convertTotals пересчитывает суммы в разных валютах в одну валюту.
+func (h *Handler) convertTotals(ctx context.Context, totals []models.TransactionTotals, currency string) (*models.TransactionTotals, error) {
+	exchangeRates, err := h.exchangeRatesFor(ctx, currency, totals)
+	if err != nil {
+		return nil, err
+	}
+	return sumConverted(exchangeRates, totals, currency)
+}
+
+// exchangeRatesFor загружает курсы, только если среди сумм есть валюты, отличные от currency; иначе возвращает nil.
+// Позволяет пересчитать много групп сумм с одной загрузкой курсов.
+func (h *Handler) exchangeRatesFor(ctx context.Context, currency string, groups ...[]models.TransactionTotals) (map[string]float64, error) {
+	for _, totals := range groups {
+		for _, t := range totals {
+			if t.Currency != currency {
+				return h.rateCache.latest(ctx)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// sumConverted складывает суммы в разных валютах, пересчитывая их в currency по курсам exchangeRates.
+func sumConverted(exchangeRates map[string]float64, totals []models.TransactionTotals, currency string) (*models.TransactionTotals, error) {
+	result := &models.TransactionTotals{Currency: currency}
+	for _, t := range totals {
+		income, err := rates.Convert(exchangeRates, t.Income.Float64(), t.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		expense, err := rates.Convert(exchangeRates, t.Expense.Float64(), t.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		// Пересчитанная сумма округляется до копеек отдельно по каждой валюте
+		result.Income += models.MoneyFromFloat(income)
+		result.Expense += models.MoneyFromFloat(expense)
+	}
+	return result, nil
+}
+
+// convertedTotals возвращает суммы транзакций по фильтру в базовой валюте пользователя.
+func (h *Handler) convertedTotals(ctx context.Context, userID int, filter db.TransactionFilter) (*models.TransactionTotals, error) {
+	user, err := h.storage.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	totals, err := h.storage.GetTransactionTotals(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return h.convertTotals(ctx, totals, user.BaseCurrency)
+}
+
+// @Security ApiKeyAuth
+// @Summary Изменить базовую валюту
+// @Description Устанавливает валюту, в которую пересчитываются итоги, и в которой по умолчанию создаются транзакции
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body models.SetBaseCurrencyRequest true "Код валюты ISO 4217"
+// @Success 200 {object} models.Profile
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /me/currency [put]
+func (h *Handler) SetBaseCurrency(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.SetBaseCurrencyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updated, err := h.storage.SetBaseCurrency(userID.(int), req.Currency)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil || user == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load user"})
+		return
+	}
+	c.JSON(http.StatusOK, profileOf(user))
+}
+
+// maxFXRate — верхняя граница курса, помещающегося в столбец NUMERIC(18,8).
+const maxFXRate = 1e10
+
+// categoriesWithStats возвращает категории со статистикой и расходами, пересчитанными в базовую валюту.
+// Курсы загружаются один раз на весь список; если они недоступны, TotalSpent не заполняется.
+func (h *Handler) categoriesWithStats(ctx context.Context, userID int) ([]models.Category, error) {
+	user, err := h.storage.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	categories, err := h.storage.GetCategoriesWithStats(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []models.TransactionTotals
+	for _, category := range categories {
+		all = append(all, category.Totals...)
+	}
+	var exchangeRates map[string]float64
+	for _, t := range all {
+		if t.Currency != user.BaseCurrency {
+			if exchangeRates, err = h.rateCache.latest(ctx); err != nil {
+				log.Printf("failed to load exchange rates for user %d: %v", userID, err)
+			}
+			break
+		}
+	}
+
+	for i := range categories {
+		converted, err := sumConverted(exchangeRates, categories[i].Totals, user.BaseCurrency)
+		if err != nil {
+			continue
+		}
+		categories[i].TotalSpent = &converted.Expense
+	}
+	return categories, nil
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// fakeRates возвращает фиксированные курсы относительно RUB и считает обращения.
+type fakeRates struct {
+	calls int
+}
+
+func (p *fakeRates) Fetch(ctx context.Context) (map[string]float64, error) {
+	p.calls++
+	return map[string]float64{"RUB": 1, "USD": 1.0 / 80, "EUR": 1.0 / 100}, nil
+}
+
+// TestConvertedTotals тестирует валюту транзакций и пересчет итогов в базовую валюту.
+func TestConvertedTotals(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+
+	provider := &fakeRates{}
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Rates: provider})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.GET("/transactions", handler.GetTransactions)
+	protected.POST("/transactions", handler.CreateTransaction)
+	protected.PUT("/me/currency", handler.SetBaseCurrency)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if user.BaseCurrency != "RUB" {
+		t.Errorf("Expected default base currency RUB, got %q", user.BaseCurrency)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Без валюты транзакция создается в базовой валюте
+	w := send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(1000, 0), Type: "expense", CategoryID: category.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created models.Transaction
+	json.NewDecoder(w.Body).Decode(&created)
+	if created.Currency != "RUB" {
+		t.Errorf("Expected currency RUB, got %q", created.Currency)
+	}
+
+	w = send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID, Currency: "USD"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	w = send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(50, 0), Type: "income", CategoryID: category.ID, Currency: "EUR"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// Некорректный код валюты отклоняется
+	w = send("POST", "/transactions", models.CreateTransactionRequest{Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID, Currency: "usd"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	getTotals := func() *models.TransactionTotals {
+		w := send("GET", "/transactions", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response models.GetTransactionsResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.ConvertedTotals == nil {
+			t.Fatal("Expected converted_totals in response")
+		}
+		return response.ConvertedTotals
+	}
+
+	// Итоги пересчитываются в рубли: 1000 + 10*80 расходов и 50*100 доходов
+	totals := getTotals()
+	if totals.Currency != "RUB" || totals.Expense != models.NewMoney(1800, 0) || totals.Income != models.NewMoney(5000, 0) {
+		t.Errorf("Expected RUB totals income 5000 expense 1800, got %+v", totals)
+	}
+
+	// Курсы загружаются один раз в сутки и дальше берутся из кэша
+	getTotals()
+	if provider.calls != 1 {
+		t.Errorf("Expected 1 call to rates provider, got %d", provider.calls)
+	}
+
+	// Смена базовой валюты меняет валюту итогов
+	if w := send("PUT", "/me/currency", models.SetBaseCurrencyRequest{Currency: "EURO"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("PUT", "/me/currency", models.SetBaseCurrencyRequest{Currency: "USD"}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	totals = getTotals()
+	if totals.Currency != "USD" || totals.Expense != models.NewMoney(22, 50) || totals.Income != models.NewMoney(62, 50) {
+		t.Errorf("Expected USD totals income 62.5 expense 22.5, got %+v", totals)
+	}
+}
+
+// slowRates отдает курсы с задержкой или ошибку и потокобезопасно считает обращения.
+type slowRates struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (p *slowRates) Fetch(ctx context.Context) (map[string]float64, error) {
+	p.calls.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return map[string]float64{"RUB": 1, "USD": 1.0 / 90}, nil
+}
+
+// TestRateCacheRefresh тестирует общий запрос курсов для одновременных обращений и паузу после ошибки провайдера.
+func TestRateCacheRefresh(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	if err := storage.SaveExchangeRates(yesterday, map[string]float64{"RUB": 1, "USD": 1.0 / 80}); err != nil {
+		t.Fatalf("Failed to save rates: %v", err)
+	}
+
+	// После ошибки отдаются сохраненные курсы, а провайдер не запрашивается до конца паузы
+	failing := &slowRates{err: errors.New("provider unavailable")}
+	cache := newRateCache(storage, failing)
+	for range 3 {
+		exchangeRates, err := cache.latest(context.Background())
+		if err != nil || exchangeRates["USD"] != 1.0/80 {
+			t.Fatalf("Expected cached rates, got %v, %v", exchangeRates, err)
+		}
+	}
+	if calls := failing.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 call to failing provider, got %d", calls)
+	}
+
+	// Одновременные обращения ждут один запрос к провайдеру
+	provider := &slowRates{}
+	cache = newRateCache(storage, provider)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if exchangeRates, err := cache.latest(context.Background()); err != nil || exchangeRates["USD"] != 1.0/90 {
+				t.Errorf("Expected fresh rates, got %v, %v", exchangeRates, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 call to provider, got %d", calls)
+	}
+}
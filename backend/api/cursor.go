@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// encodeCursor кодирует позицию транзакции в непрозрачную строку для параметра after.
+func encodeCursor(t models.Transaction) string {
+	raw := t.Date.UTC().Format(time.RFC3339Nano) + "|" + strconv.Itoa(t.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor разбирает значение параметра after; пустая строка означает начало списка.
+func decodeCursor(value string) (*db.TransactionCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	dateStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	date, err := time.Parse(time.RFC3339Nano, dateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &db.TransactionCursor{Date: date, ID: id}, nil
+}
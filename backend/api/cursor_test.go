@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestCursorPagination тестирует курсорную пагинацию списка транзакций.
+func TestCursorPagination(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	// Две транзакции с одинаковой датой проверяют упорядочивание по ID
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var transactions []*models.Transaction
+	for i, day := range []int{0, 1, 1, 2, 3} {
+		transactions = append(transactions, &models.Transaction{UserID: user.ID, Amount: models.NewMoney(int64(i+1), 0), Type: "expense", CategoryID: category.ID, Date: base.AddDate(0, 0, day)})
+	}
+	if err := storage.CreateTransactions(transactions); err != nil {
+		t.Fatalf("Failed to create transactions: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) (int, models.GetTransactionsResponse) {
+		req, _ := http.NewRequest("GET", "/transactions?"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.GetTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+
+	// Проходим весь список страницами по 2 от новых к старым
+	var ids []int
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Too many pages")
+		}
+		code, response := get("limit=2&after=" + url.QueryEscape(cursor))
+		if code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+		}
+		if response.Total != 5 {
+			t.Errorf("Expected total 5, got %d", response.Total)
+		}
+		for _, transaction := range response.Transactions {
+			ids = append(ids, transaction.ID)
+		}
+		if response.NextCursor == "" {
+			break
+		}
+		cursor = response.NextCursor
+	}
+	expected := []int{transactions[4].ID, transactions[3].ID, transactions[2].ID, transactions[1].ID, transactions[0].ID}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected ids %v, got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Fatalf("Expected ids %v, got %v", expected, ids)
+		}
+	}
+
+	// Новая транзакция не сдвигает уже полученные страницы
+	_, first := get("limit=2&after=&sort=asc")
+	if err := storage.CreateTransaction(&models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, Date: base.AddDate(0, 0, -1)}); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	_, second := get("limit=2&sort=asc&after=" + url.QueryEscape(first.NextCursor))
+	if len(second.Transactions) != 2 || second.Transactions[0].ID != transactions[2].ID {
+		t.Errorf("Expected second page to start with %d, got %+v", transactions[2].ID, second.Transactions)
+	}
+
+	// Некорректный курсор и сочетание с page отклоняются
+	if code, _ := get("after=garbage"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := get("page=2&after="); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/period"
+)
+
+const (
+	// dashboardRecentTransactions — число последних транзакций на главном экране.
+	dashboardRecentTransactions = 10
+	// dashboardUpcomingDays — на сколько дней вперед показываются запланированные транзакции.
+	dashboardUpcomingDays = 30
+)
+
+// runConcurrently выполняет функции одновременно и возвращает ошибку первой по порядку неудачной функции.
+func runConcurrently(tasks ...func() error) error {
+	errs := make([]error, len(tasks))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = task()
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Главный экран
+// @Description Возвращает одним ответом суммарный остаток своих открытых счетов и итоги текущего месяца (с дня начала месяца из профиля) в базовой валюте,
+// @Description действующие бюджеты, 10 последних транзакций и запланированные транзакции на 30 дней вперед
+// @Tags dashboard
+// @Produce json
+// @Success 200 {object} models.Dashboard
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /dashboard [get]
+func (h *Handler) GetDashboard(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := period.Grouping{Kind: period.Month, MonthStart: user.MonthStart}.Start(today)
+
+	// Разделы независимы, поэтому запросы к базе выполняются одновременно
+	var (
+		accounts   []models.Account
+		byCurrency []models.CurrencySummary
+		budgets    []models.BudgetProgress
+		recent     []models.Transaction
+		upcoming   []models.Transaction
+	)
+	err = runConcurrently(
+		func() (err error) {
+			accounts, err = h.storage.GetAccounts(user.ID, false)
+			return err
+		},
+		func() (err error) {
+			byCurrency, err = h.storage.GetPeriodSummary(user.ID, monthStart, today.AddDate(0, 0, 1), false)
+			return err
+		},
+		func() (err error) {
+			if err := h.storage.UpdateBudgetCarryover(user.ID, today); err != nil {
+				return err
+			}
+			budgets, err = h.storage.GetBudgetProgress(user.ID, today, today)
+			return err
+		},
+		func() (err error) {
+			filter := db.TransactionFilter{SortBy: []db.SortKey{{Field: "date", Desc: true}}}
+			recent, _, err = h.storage.GetTransactions(user.ID, filter, 1, dashboardRecentTransactions)
+			return err
+		},
+		func() (err error) {
+			upcoming, err = h.storage.GetPeriodTransactions(user.ID, today, today.AddDate(0, 0, dashboardUpcomingDays+1), true)
+			return err
+		},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	balances := ownBalances(accounts)
+	exchangeRates, err := h.exchangeRatesFor(c.Request.Context(), user.BaseCurrency, balances, summaryTotals(byCurrency))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+	balance, err := sumConverted(exchangeRates, balances, user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert balances: " + err.Error()})
+		return
+	}
+	month, err := newPeriodSummary(monthStart, today, byCurrency, exchangeRates, user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+
+	for i := range budgets {
+		period := budget.Period{Kind: budgets[i].Period, Start: budgets[i].StartDate, End: budgets[i].EndDate}
+		budgets[i].Elapsed = period.Elapsed(now)
+	}
+	planned := []models.Transaction{}
+	for _, t := range upcoming {
+		if t.Planned {
+			planned = append(planned, t)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.Dashboard{
+		Currency:           user.BaseCurrency,
+		Balance:            balance.Income - balance.Expense,
+		Month:              month,
+		Budgets:            budgets,
+		RecentTransactions: recent,
+		Upcoming:           planned,
+	})
+}
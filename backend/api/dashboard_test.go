@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestRunConcurrently тестирует одновременное выполнение функций и выбор ошибки.
+func TestRunConcurrently(t *testing.T) {
+	results := make([]int, 3)
+	err := runConcurrently(
+		func() error { results[0] = 1; return nil },
+		func() error { results[1] = 2; return nil },
+		func() error { results[2] = 3; return nil },
+	)
+	if err != nil || results[0] != 1 || results[1] != 2 || results[2] != 3 {
+		t.Errorf("Unexpected results %v, error %v", results, err)
+	}
+
+	first, second := errors.New("first"), errors.New("second")
+	err = runConcurrently(
+		func() error { return nil },
+		func() error { time.Sleep(10 * time.Millisecond); return first },
+		func() error { return second },
+	)
+	if err != first {
+		t.Errorf("Expected first error in order, got %v", err)
+	}
+}
+
+// TestGetDashboard тестирует данные главного экрана.
+func TestGetDashboard(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	account, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Карта", Currency: "RUB", InitialBalance: models.NewMoney(10000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	period, err := budget.NewPeriod(budget.Month, today, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to create period: %v", err)
+	}
+	if _, err := storage.CreateBudget(user.ID, period, models.CreateBudget{CategoryID: category.ID, Limit: models.NewMoney(5000, 0), Currency: "RUB"}); err != nil {
+		t.Fatalf("Failed to create budget: %v", err)
+	}
+	for i, transaction := range []*models.Transaction{
+		{Type: "expense", Amount: models.NewMoney(1500, 0), Date: today},
+		{Type: "income", Amount: models.NewMoney(3000, 0), Date: today},
+		// Расход прошлого месяца не входит в итоги месяца, но уменьшает остаток
+		{Type: "expense", Amount: models.NewMoney(500, 0), Date: today.AddDate(0, -1, 0)},
+		{Type: "expense", Amount: models.NewMoney(2000, 0), Date: today.AddDate(0, 0, 3), Planned: true},
+		{Type: "expense", Amount: models.NewMoney(2000, 0), Date: today.AddDate(0, 0, dashboardUpcomingDays+5), Planned: true},
+	} {
+		transaction.UserID, transaction.AccountID, transaction.CategoryID, transaction.Currency = user.ID, account.ID, category.ID, "RUB"
+		transaction.Description = string(rune('a' + i))
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	req, _ := http.NewRequest("GET", "/dashboard", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var dashboard models.Dashboard
+	json.NewDecoder(w.Body).Decode(&dashboard)
+
+	if dashboard.Balance != models.NewMoney(11000, 0) {
+		t.Errorf("Expected balance 11000, got %s", dashboard.Balance)
+	}
+	if dashboard.Month.Expense != models.NewMoney(1500, 0) || dashboard.Month.Income != models.NewMoney(3000, 0) || dashboard.Month.Count != 2 {
+		t.Errorf("Unexpected month summary: %+v", dashboard.Month)
+	}
+	if len(dashboard.Budgets) != 1 || dashboard.Budgets[0].Spent != models.NewMoney(1500, 0) {
+		t.Errorf("Unexpected budgets: %+v", dashboard.Budgets)
+	}
+	if len(dashboard.RecentTransactions) != 3 || dashboard.RecentTransactions[2].Description != "c" {
+		t.Errorf("Unexpected recent transactions: %+v", dashboard.RecentTransactions)
+	}
+	if len(dashboard.Upcoming) != 1 || dashboard.Upcoming[0].Description != "d" {
+		t.Errorf("Unexpected upcoming transactions: %+v", dashboard.Upcoming)
+	}
+}
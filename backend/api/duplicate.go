@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Security ApiKeyAuth
+// @Summary Повторить транзакцию
+// @Description Создает копию транзакции (тип, категория, описание, валюта, теги) с текущей датой.
+// @Description Дату и сумму можно переопределить в теле запроса; тело необязательно.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID исходной транзакции"
+// @Param request body models.DuplicateTransactionRequest false "Переопределяемые поля"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id}/duplicate [post]
+func (h *Handler) DuplicateTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	// Тело необязательно: без него копия получает текущую дату и исходную сумму
+	var req models.DuplicateTransactionRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	original, err := h.storage.GetTransaction(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if original == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
+	duplicate := *original
+	duplicate.ID = 0
+	duplicate.Date = time.Now()
+	if req.Date != nil {
+		duplicate.Date = *req.Date
+	}
+	if req.Amount != nil {
+		duplicate.Amount = *req.Amount
+		// Прежний курс не соответствует новой сумме и вычисляется заново
+		duplicate.FXRate = 0
+	}
+	// Копия запланированной транзакции остается запланированной, только если ее дата в будущем
+	duplicate.Planned = original.Planned && duplicate.Date.After(time.Now())
+	// Копия еще не сверялась с выпиской и получает статус по умолчанию
+	duplicate.Status = ""
+	// Повторный возврат по той же покупке маловероятен, поэтому копия не привязывается к расходу
+	duplicate.LinkedTransactionID = 0
+
+	if err := validateTransaction(duplicate); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	if err := h.storage.CreateTransaction(&duplicate); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.transactionsCreated(userID.(int), &duplicate)
+	c.JSON(http.StatusCreated, duplicate)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestDuplicateTransaction тестирует повтор транзакции с переопределением полей.
+func TestDuplicateTransaction(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	original := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(1500, 0), Type: "expense", CategoryID: category.ID,
+		Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Description: "Продукты на неделю", Currency: "EUR", Tags: []string{"groceries"}}
+	if err := storage.CreateTransaction(original); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	duplicate := func(id int, body []byte) (int, models.Transaction) {
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/transactions/%d/duplicate", id), bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var transaction models.Transaction
+		json.NewDecoder(w.Body).Decode(&transaction)
+		return w.Code, transaction
+	}
+
+	// Без тела копируются все поля, кроме даты
+	before := time.Now().Add(-time.Minute)
+	code, copied := duplicate(original.ID, nil)
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+	if copied.ID == original.ID || copied.Amount != original.Amount || copied.CategoryID != original.CategoryID ||
+		copied.Description != original.Description || copied.Currency != "EUR" || !reflect.DeepEqual(copied.Tags, original.Tags) {
+		t.Errorf("Expected copy of %+v, got %+v", original, copied)
+	}
+	if copied.Date.Before(before) {
+		t.Errorf("Expected current date, got %v", copied.Date)
+	}
+
+	// Дата и сумма переопределяются
+	date := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	body, _ := json.Marshal(map[string]interface{}{"date": date, "amount": 1750.5})
+	code, copied = duplicate(original.ID, body)
+	if code != http.StatusCreated || copied.Amount != models.NewMoney(1750, 50) || !copied.Date.Equal(date) {
+		t.Errorf("Expected overridden amount and date, got status %d and %+v", code, copied)
+	}
+
+	// Некорректная сумма и несуществующая транзакция
+	body, _ = json.Marshal(map[string]interface{}{"amount": -1})
+	if code, _ := duplicate(original.ID, body); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := duplicate(999, nil); code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, code)
+	}
+}
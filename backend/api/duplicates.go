@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Security ApiKeyAuth
+// @Summary Разрешить возможный дубликат
+// @Description Снимает с транзакции отметку possible_duplicate. При action=confirm транзакция признается дубликатом
+// @Description и перемещается в корзину, при action=dismiss остается как обычная транзакция.
+// @Tags transactions
+// @Accept json
+// @Param id path int true "ID транзакции"
+// @Param request body models.ResolveDuplicateRequest true "Решение"
+// @Success 204
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id}/resolve-duplicate [post]
+func (h *Handler) ResolveDuplicate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	var req models.ResolveDuplicateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	resolved, err := h.storage.ResolvePossibleDuplicate(id, userID.(int), req.Action == "confirm")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !resolved {
+		c.JSON(http.StatusNotFound, gin.H{"error": "possible duplicate not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestDuplicateDetection тестирует отметку возможных дубликатов, режим reject и их разрешение.
+func TestDuplicateDetection(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	date := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	original := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(499, 90), Type: "expense", CategoryID: category.ID, Date: date, Payee: "Лента"}
+	if err := storage.CreateTransaction(original); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	if original.PossibleDuplicate {
+		t.Error("Expected first transaction not to be a duplicate")
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	do := func(method, url string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	create := func(query string, date time.Time, payee string) *httptest.ResponseRecorder {
+		return do("POST", "/transactions"+query, map[string]interface{}{
+			"amount": 499.9, "type": "expense", "category_id": category.ID, "date": date, "payee": payee,
+		})
+	}
+
+	// Другой контрагент или дата дальше суток — не дубликат
+	for _, w := range []*httptest.ResponseRecorder{create("", date, "Ашан"), create("", date.AddDate(0, 0, 2), "Лента")} {
+		var created models.Transaction
+		json.NewDecoder(w.Body).Decode(&created)
+		if w.Code != http.StatusCreated || created.PossibleDuplicate {
+			t.Errorf("Expected regular transaction, got status %d and %+v", w.Code, created)
+		}
+	}
+
+	// В режиме reject похожая транзакция не создается
+	w := create("?on_duplicate=reject", date.Add(20*time.Hour), "лента")
+	var conflict models.DuplicateConflictResponse
+	json.NewDecoder(w.Body).Decode(&conflict)
+	if w.Code != http.StatusConflict || !reflect.DeepEqual(conflict.DuplicateIDs, []int{original.ID}) {
+		t.Errorf("Expected conflict with %d, got status %d and %+v", original.ID, w.Code, conflict)
+	}
+
+	w = create("", date.Add(-20*time.Hour), "Лента")
+	var flagged models.Transaction
+	json.NewDecoder(w.Body).Decode(&flagged)
+	if w.Code != http.StatusCreated || !flagged.PossibleDuplicate || !reflect.DeepEqual(flagged.DuplicateOf, []int{original.ID}) {
+		t.Errorf("Expected flagged duplicate of %d, got status %d and %+v", original.ID, w.Code, flagged)
+	}
+
+	w = do("GET", "/transactions?possible_duplicate=true", nil)
+	var list models.GetTransactionsResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	if w.Code != http.StatusOK || list.Total != 1 || list.Transactions[0].ID != flagged.ID {
+		t.Errorf("Expected only flagged transaction, got status %d and %+v", w.Code, list)
+	}
+
+	resolve := func(id int, action string) int {
+		return do("POST", fmt.Sprintf("/transactions/%d/resolve-duplicate", id), models.ResolveDuplicateRequest{Action: action}).Code
+	}
+	if code := resolve(flagged.ID, "maybe"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid action, got %d", http.StatusBadRequest, code)
+	}
+	if code := resolve(original.ID, "dismiss"); code != http.StatusNotFound {
+		t.Errorf("Expected status %d for not flagged transaction, got %d", http.StatusNotFound, code)
+	}
+
+	// Подтвержденный дубликат уходит в корзину
+	if code := resolve(flagged.ID, "confirm"); code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, code)
+	}
+	if transaction, err := storage.GetTransaction(flagged.ID, user.ID); err != nil || transaction != nil {
+		t.Errorf("Expected confirmed duplicate to be deleted, got %+v (%v)", transaction, err)
+	}
+
+	// Отклоненная отметка снимается, транзакция остается
+	w = create("", date, "Лента")
+	json.NewDecoder(w.Body).Decode(&flagged)
+	if code := resolve(flagged.ID, "dismiss"); code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, code)
+	}
+	transaction, err := storage.GetTransaction(flagged.ID, user.ID)
+	if err != nil || transaction == nil || transaction.PossibleDuplicate {
+		t.Errorf("Expected dismissed transaction without flag, got %+v (%v)", transaction, err)
+	}
+}
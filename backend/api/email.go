@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Security ApiKeyAuth
+// @Summary Сменить email
+// @Description Отправляет ссылку подтверждения на новый адрес. Email меняется только после перехода по ссылке.
+// @Description Указание текущего адреса отправляет ссылку для его подтверждения
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body models.ChangeEmailRequest true "Новый email"
+// @Success 202
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /me/email [put]
+func (h *Handler) ChangeEmail(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var request models.ChangeEmailRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	token, err := h.storage.CreateEmailChange(userID.(int), request.Email, h.cfg.MagicLinkTTL)
+	if err != nil {
+		if strings.Contains(err.Error(), "email is already in use") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	link := h.cfg.BaseURL + V1Prefix + "/me/email/confirm?token=" + url.QueryEscape(token)
+	lang := language(c)
+	body := i18n.T(lang, "email.email_change.body", link, h.cfg.MagicLinkTTL)
+	if err := h.cfg.Mailer.Send(request.Email, i18n.T(lang, "email.email_change.subject"), body); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send email"})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// @Summary Подтвердить смену email
+// @Description Применяет смену email по токену из письма
+// @Tags me
+// @Produce json
+// @Param token query string true "Токен из ссылки"
+// @Success 200 {object} models.Profile
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /me/email/confirm [get]
+func (h *Handler) ConfirmEmailChange(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	user, err := h.storage.ConfirmEmailChange(token)
+	if err != nil {
+		if strings.Contains(err.Error(), "email is already in use") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profileOf(user))
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestChangeEmail тестирует смену email с подтверждением по ссылке.
+func TestChangeEmail(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUserWithEmail("testuser", "password123", "old@example.com")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if _, err := storage.CreateUserWithEmail("other", "password123", "taken@example.com"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	changeEmail := func(email string) int {
+		body, _ := json.Marshal(models.ChangeEmailRequest{Email: email})
+		req, _ := http.NewRequest("PUT", "/me/email", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Некорректный и занятый адреса отклоняются
+	if code := changeEmail("not-an-email"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+	if code := changeEmail("taken@example.com"); code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, code)
+	}
+
+	// Запрашиваем смену на свободный адрес
+	if code := changeEmail("new@example.com"); code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d", http.StatusAccepted, code)
+	}
+	if len(testMailer.sent) != 1 || testMailer.sent[0].To != "new@example.com" {
+		t.Fatalf("Expected confirmation email to new@example.com, got %+v", testMailer.sent)
+	}
+
+	// До подтверждения email не меняется
+	fetched, err := storage.GetUserByID(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if fetched.Email != "old@example.com" {
+		t.Errorf("Expected email 'old@example.com', got %s", fetched.Email)
+	}
+
+	match := regexp.MustCompile(`token=([0-9a-f]+)`).FindStringSubmatch(testMailer.sent[0].Body)
+	if match == nil {
+		t.Fatalf("Expected link with token in email, got %q", testMailer.sent[0].Body)
+	}
+
+	req, _ := http.NewRequest("GET", "/me/email/confirm?token="+match[1], nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	fetched, err = storage.GetUserByID(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if fetched.Email != "new@example.com" {
+		t.Errorf("Expected email 'new@example.com', got %s", fetched.Email)
+	}
+
+	// Старый адрес сохраняется для восстановления
+	var oldEmail string
+	if err := storage.DB.QueryRow("SELECT old_email FROM email_changes WHERE user_id = $1", user.ID).Scan(&oldEmail); err != nil {
+		t.Fatalf("Failed to get email change: %v", err)
+	}
+	if oldEmail != "old@example.com" {
+		t.Errorf("Expected old email 'old@example.com', got %s", oldEmail)
+	}
+
+	// Ссылка одноразовая
+	req, _ = http.NewRequest("GET", "/me/email/confirm?token="+match[1], nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
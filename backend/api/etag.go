@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etagRoute сообщает, отдается ли ответ на GET-запрос маршрута с ETag: профиля, списков категорий и транзакций,
+// которые мобильные клиенты часто опрашивают. Версия API в шаблоне маршрута не учитывается.
+func etagRoute(path string) bool {
+	path = unversionedRoute(path)
+	return path == "/me" || path == "/categories" || path == "/transactions"
+}
+
+// etagWriter задерживает ответ до конца обработки запроса, чтобы по его телу можно было вычислить ETag.
+type etagWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *etagWriter) WriteHeader(code int) {
+	w.status = code
+	w.written = true
+}
+
+func (w *etagWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *etagWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+func (w *etagWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *etagWriter) Status() int {
+	return w.status
+}
+
+func (w *etagWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *etagWriter) Written() bool {
+	return w.written
+}
+
+// etagMatch сообщает, совпадает ли etag с одним из значений заголовка If-None-Match.
+// Сравнение слабое (RFC 9110, 13.1.2): префикс W/ не учитывается.
+func etagMatch(ifNoneMatch, etag string) bool {
+	for _, value := range strings.Split(ifNoneMatch, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.TrimPrefix(value, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ETagMiddleware добавляет ETag — хэш тела ответа — к успешным ответам на GET-запросы профиля,
+// категорий и транзакций и отвечает 304 без тела, если он совпадает с заголовком If-None-Match.
+// Запрос при этом выполняется полностью: экономится только трафик клиента.
+func (h *Handler) ETagMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || !etagRoute(c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &etagWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.status == http.StatusOK {
+			sum := sha256.Sum256(writer.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+			c.Header("ETag", etag)
+			// Ответ зависит от пользователя, и клиент должен проверять его при каждом запросе
+			c.Header("Cache-Control", "private, no-cache")
+			if etagMatch(c.GetHeader("If-None-Match"), etag) {
+				original.Header().Del("Content-Type")
+				original.WriteHeader(http.StatusNotModified)
+				original.WriteHeaderNow()
+				return
+			}
+		}
+		original.WriteHeader(writer.status)
+		if writer.body.Len() > 0 {
+			original.Write(writer.body.Bytes())
+		} else {
+			original.WriteHeaderNow()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestETagMiddleware тестирует ETag и ответ 304 на условные запросы.
+func TestETagMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	handler := NewHandler(nil, Config{})
+	name := "food"
+
+	r := gin.New()
+	g := r.Group(V1Prefix, handler.ETagMiddleware())
+	g.GET("/categories", func(c *gin.Context) { c.JSON(http.StatusOK, []gin.H{{"name": name}}) })
+	g.GET("/transactions", func(c *gin.Context) { c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type"}) })
+	g.GET("/tags", func(c *gin.Context) { c.JSON(http.StatusOK, []gin.H{}) })
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", V1Prefix+path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/categories", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.String() != `[{"name":"food"}]` {
+		t.Fatalf("Expected 200 with ETag, got %d %q: %s", w.Code, etag, w.Body.String())
+	}
+	if got := w.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("Unexpected Cache-Control %q", got)
+	}
+
+	for _, ifNoneMatch := range []string{etag, `"other", W/` + etag, "*"} {
+		w = get("/categories", ifNoneMatch)
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != etag {
+			t.Errorf("Expected 304 for If-None-Match %s, got %d: %s", ifNoneMatch, w.Code, w.Body.String())
+		}
+	}
+
+	// После изменения данных меняется и ETag
+	name = "transport"
+	w = get("/categories", etag)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with new ETag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	// Ошибки и другие маршруты отдаются без ETag
+	w = get("/transactions", "*")
+	if w.Code != http.StatusBadRequest || w.Header().Get("ETag") != "" || w.Body.String() != `{"error":"invalid type"}` {
+		t.Errorf("Expected 400 without ETag, got %d %q: %s", w.Code, w.Header().Get("ETag"), w.Body.String())
+	}
+	w = get("/tags", "*")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != "" {
+		t.Errorf("Expected 200 without ETag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	// Ответ из кэша получает тот же ETag, что и исходный
+	cached := &Handler{cfg: Config{Cache: newFakeCache()}}
+	r = gin.New()
+	r.GET("/categories", func(c *gin.Context) { c.Set("user_id", 1) }, cached.ETagMiddleware(), cached.CacheMiddleware(),
+		func(c *gin.Context) { c.JSON(http.StatusOK, []gin.H{{"name": name}}) })
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/categories", nil))
+	etag = w.Header().Get("ETag")
+	req := httptest.NewRequest("GET", "/categories", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("Expected 304 from cache, got %d %q", w.Code, w.Header().Get("X-Cache"))
+	}
+}
+
+// TestGetProfile тестирует получение профиля и условный запрос к нему.
+func TestGetProfile(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("profileuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	token := getToken(t, r, "profileuser", "password123")
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var profile models.Profile
+	if err := json.NewDecoder(w.Body).Decode(&profile); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if profile.ID != user.ID || profile.Username != "profileuser" {
+		t.Errorf("Unexpected profile %+v", profile)
+	}
+
+	etag := w.Header().Get("ETag")
+	if w = get(etag); w.Code != http.StatusNotModified {
+		t.Errorf("Expected status %d, got %d", http.StatusNotModified, w.Code)
+	}
+
+	// Изменение профиля меняет ETag
+	req, _ := http.NewRequest("PUT", "/me/month-start", bytes.NewBufferString(`{"month_start": 25}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if w = get(etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with new ETag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+}
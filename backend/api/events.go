@@ -0,0 +1,242 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/nemopss/fin-ng/backend/events"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// Параметры соединения WebSocket.
+const (
+	wsWriteTimeout = 10 * time.Second
+	// wsPongTimeout — сколько ждать ответа на ping, прежде чем считать клиента отключенным
+	wsPongTimeout  = 60 * time.Second
+	wsPingInterval = wsPongTimeout * 9 / 10
+	wsMaxMessage   = 512
+)
+
+// Параметры потока Server-Sent Events.
+const (
+	// sseRetry — через сколько миллисекунд браузер переподключается после обрыва соединения
+	sseRetry = 3000
+	// sseKeepAlive — период комментариев, которые не дают прокси закрыть простаивающее соединение
+	sseKeepAlive = 30 * time.Second
+	// sseReset — событие, после которого клиенту нужно заново загрузить данные,
+	// потому что часть пропущенных событий уже не хранится
+	sseReset = "reset"
+)
+
+// wsUpgrader принимает соединения с любого источника: токен передается явно, а не в cookie,
+// поэтому чужая страница не может подключиться от имени пользователя.
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// @Security ApiKeyAuth
+// @Summary Поток событий через WebSocket
+// @Description Открывает соединение WebSocket, по которому сервер отправляет события пользователя в формате JSON:
+// @Description transaction.created (data — транзакция), budget.threshold (data — превышенный бюджет)
+// @Description и import.finished (data — завершенное задание импорта без отчета по строкам).
+// @Description Браузер не может передать заголовок Authorization, поэтому токен принимается и в параметре token.
+// @Description Сообщения клиента игнорируются. Если клиент не успевает читать события, соединение закрывается с кодом 1013.
+// @Tags events
+// @Param token query string false "JWT, если не передан заголовок Authorization"
+// @Success 101 {object} events.Event
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /ws [get]
+func (h *Handler) WebSocket(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	// Upgrade сам отвечает клиенту при ошибке
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := h.cfg.Events.Subscribe(userID.(int))
+	defer sub.Close()
+
+	// Чтение нужно, чтобы обрабатывать pong и закрытие соединения клиентом
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(wsMaxMessage)
+		conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case event, ok := <-sub.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too many pending events"))
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary Поток событий через Server-Sent Events
+// @Description Отправляет те же события, что и /ws, в формате text/event-stream: поле id — эпоха и ID события через дефис,
+// @Description event — его тип, data — событие в формате JSON. Для EventSource токен можно передать в параметре token.
+// @Description При переподключении браузер передает заголовок Last-Event-ID, и сервер сначала отправляет пропущенные события.
+// @Description Если часть из них уже не хранится или ID выдан в другой эпохе, например до перезапуска сервиса,
+// @Description сначала отправляется событие reset, а за ним все хранящиеся события пользователя: клиенту нужно заново загрузить данные.
+// @Tags events
+// @Produce text/event-stream
+// @Param token query string false "JWT, если не передан заголовок Authorization"
+// @Param Last-Event-ID header string false "ID последнего полученного события"
+// @Param last_event_id query string false "ID последнего полученного события, если не передан заголовок Last-Event-ID"
+// @Success 200 {object} events.Event
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /events [get]
+func (h *Handler) StreamEvents(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var sub *events.Subscription
+	var missed []events.Event
+	complete := true
+	if lastEventID != "" {
+		epoch, lastID, err := events.ParseStreamID(lastEventID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sub, missed, complete = h.cfg.Events.SubscribeSince(userID.(int), epoch, lastID)
+	} else {
+		sub = h.cfg.Events.Subscribe(userID.(int))
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Отключает буферизацию ответа в nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", sseRetry)
+	if !complete {
+		fmt.Fprintf(c.Writer, "event: %s\ndata: {}\n\n", sseReset)
+	}
+	for _, event := range missed {
+		if err := writeSSE(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case event, ok := <-sub.C:
+			// Закрытую подписку клиент восстановит, переподключившись с Last-Event-ID
+			if !ok {
+				return
+			}
+			if err := writeSSE(c.Writer, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeSSE записывает событие в формате text/event-stream.
+func writeSSE(w gin.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.StreamID(), event.Type, data)
+	return err
+}
+
+// transactionsCreated сообщает клиентам пользователя о новых транзакциях и, если среди них
+// есть проведенные расходы, в фоне проверяет превышение бюджетов.
+func (h *Handler) transactionsCreated(userID int, transactions ...*models.Transaction) {
+	expense := false
+	for _, t := range transactions {
+		h.cfg.Events.Publish(userID, events.TransactionCreated, t)
+		expense = expense || t.Type == "expense" && !t.Planned
+	}
+	if expense {
+		go h.checkBudgetAlerts(userID)
+	}
+}
+
+// checkBudgetAlerts уведомляет пользователя о бюджетах, превышенных новыми расходами.
+func (h *Handler) checkBudgetAlerts(userID int) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	user, err := h.storage.GetUserByID(userID)
+	if err == nil && user != nil {
+		_, err = h.sendBudgetAlert(*user, today)
+	}
+	if err != nil {
+		log.Printf("failed to check budget alerts of user %d: %v", userID, err)
+	}
+}
+
+// importFinished сообщает клиентам пользователя о завершении задания импорта.
+func (h *Handler) importFinished(jobID, userID int) {
+	job, err := h.storage.GetImportJob(jobID, userID)
+	if err != nil || job == nil {
+		log.Printf("failed to get finished import job %d: %v", jobID, err)
+		return
+	}
+	// Отчет по строкам может быть большим, он доступен в /imports/{id}
+	job.Report = nil
+	h.cfg.Events.Publish(userID, events.ImportFinished, job)
+}
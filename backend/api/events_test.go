@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+	"github.com/nemopss/fin-ng/backend/events"
+)
+
+// TestWebSocket тестирует авторизацию по параметру token и доставку событий пользователя.
+func TestWebSocket(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	hub := events.NewHub()
+	handler := NewHandler(nil, Config{JWTSecret: "secret", Events: hub})
+
+	r := gin.New()
+	r.GET("/ws", handler.StreamAuthMiddleware(), handler.WebSocket)
+	server := httptest.NewServer(r)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	if _, resp, err := websocket.DefaultDialer.Dial(url, nil); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 without token, got %v", err)
+	}
+	if _, resp, err := websocket.DefaultDialer.Dial(url+"?token=invalid", nil); err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 with invalid token, got %v", err)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()}).
+		SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	// Подписка создается после установки соединения
+	for deadline := time.Now().Add(5 * time.Second); hub.Subscribers(1) == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	hub.Publish(2, events.TransactionCreated, map[string]int{"id": 1})
+	published := hub.Publish(1, events.TransactionCreated, map[string]int{"id": 2})
+
+	var event struct {
+		ID   int64          `json:"id"`
+		Type string         `json:"type"`
+		Data map[string]int `json:"data"`
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if event.ID != published.ID || event.Type != events.TransactionCreated || event.Data["id"] != 2 {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	// После отключения клиента подписка удаляется
+	conn.Close()
+	for deadline := time.Now().Add(5 * time.Second); hub.Subscribers(1) != 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := hub.Subscribers(1); n != 0 {
+		t.Errorf("Expected subscription to be closed, got %d", n)
+	}
+}
+
+// TestStreamEvents тестирует поток Server-Sent Events и повторную отправку пропущенных событий.
+func TestStreamEvents(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	hub := events.NewHub()
+	handler := NewHandler(nil, Config{JWTSecret: "secret", Events: hub})
+
+	r := gin.New()
+	r.GET("/events", handler.StreamAuthMiddleware(), handler.StreamEvents)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()}).
+		SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	// connect открывает поток и возвращает его строки до первого события, отличного от reset
+	connect := func(query, lastEventID string) (int, []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/events?token="+token+query, nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("Unexpected content type %q", ct)
+		}
+
+		var lines []string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+			if strings.HasPrefix(scanner.Text(), "data: {\"id\"") {
+				break
+			}
+		}
+		return resp.StatusCode, lines
+	}
+
+	if code, _ := connect("&last_event_id=abc", ""); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid last event id, got %d", code)
+	}
+
+	first := hub.Publish(1, events.TransactionCreated, map[string]int{"id": 1})
+	second := hub.Publish(1, events.ImportFinished, map[string]int{"id": 7})
+
+	// Переподключение с Last-Event-ID получает только пропущенное событие
+	_, lines := connect("", first.StreamID())
+	want := []string{"retry: 3000", "", "id: " + second.StreamID(), "event: import.finished"}
+	if len(lines) != 5 || strings.Join(lines[:4], "\n") != strings.Join(want, "\n") || !strings.Contains(lines[4], `"data":{"id":7}`) {
+		t.Errorf("Unexpected stream:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// ID, выданный до перезапуска, приводит к событию reset и отправке всей истории
+	_, lines = connect("&last_event_id="+first.Epoch+"-100", "")
+	if len(lines) < 4 || lines[2] != "event: reset" || lines[len(lines)-3] != "id: "+first.StreamID() {
+		t.Errorf("Expected reset before history, got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// ID другой эпохи, например выданный другим экземпляром сервиса, тоже приводит к reset,
+	// хотя по номеру первое событие уже было получено
+	_, lines = connect("", "other-1")
+	if len(lines) < 4 || lines[2] != "event: reset" || lines[len(lines)-3] != "id: "+first.StreamID() {
+		t.Errorf("Expected reset before history for another epoch, got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// Без Last-Event-ID отправляются только новые события
+	for deadline := time.Now().Add(5 * time.Second); hub.Subscribers(1) != 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	go func() {
+		for deadline := time.Now().Add(5 * time.Second); hub.Subscribers(1) == 0 && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+		}
+		hub.Publish(1, events.BudgetThreshold, map[string]int{"id": 3})
+	}()
+	_, lines = connect("", "")
+	if len(lines) != 5 || lines[3] != "event: budget.threshold" {
+		t.Errorf("Expected only new event, got:\n%s", strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Security ApiKeyAuth
+// @Summary Экспорт данных пользователя
+// @Description Выгружает профиль, категории и все транзакции пользователя в ZIP-архиве (profile.json, categories.json, transactions.json)
+// @Tags me
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /me/export [get]
+func (h *Handler) ExportData(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	categories, err := h.storage.GetCategories(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if categories == nil {
+		categories = []models.Category{}
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="export.zip"`)
+	c.Status(http.StatusOK)
+
+	// После начала записи архива статус ответа изменить уже нельзя,
+	// поэтому ошибки только регистрируются в контексте и обрывают поток
+	zw := zip.NewWriter(c.Writer)
+	if err := writeZipJSON(zw, "profile.json", profileOf(user)); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := writeZipJSON(zw, "categories.json", categories); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := h.writeTransactions(zw, user.ID); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := zw.Close(); err != nil {
+		c.Error(err)
+	}
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(f).Encode(v)
+}
+
+// writeTransactions пишет транзакции JSON-массивом по одной записи, не накапливая их в памяти.
+func (h *Handler) writeTransactions(zw *zip.Writer, userID int) error {
+	f, err := zw.Create("transactions.json")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err = h.storage.ForEachTransaction(userID, func(t models.Transaction) error {
+		if !first {
+			if _, err := io.WriteString(f, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(f, "]\n")
+	return err
+}
+
+// exportFlushEvery — через сколько строк JSONL буфер ответа отправляется клиенту.
+const exportFlushEvery = 100
+
+// @Security ApiKeyAuth
+// @Summary Потоковый экспорт транзакций
+// @Description Выгружает транзакции пользователя в формате JSON Lines (по одной транзакции на строку) в порядке даты.
+// @Description Строки читаются из базы по мере отправки клиенту, поэтому выгрузка не накапливается в памяти.
+// @Tags transactions
+// @Produce application/x-ndjson
+// @Param format query string false "Формат выгрузки; поддерживается только jsonl (по умолчанию)"
+// @Param type query string false "Тип транзакции (income или expense)"
+// @Param category_id query int false "ID категории"
+// @Param date_from query string false "Начало периода включительно (RFC 3339)"
+// @Param date_to query string false "Конец периода включительно (RFC 3339)"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transactions/export [get]
+func (h *Handler) ExportTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	if format := c.DefaultQuery("format", "jsonl"); format != "jsonl" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be 'jsonl'"})
+		return
+	}
+
+	filter := db.TransactionFilter{Type: c.Query("type")}
+	if filter.Type != "" && filter.Type != "income" && filter.Type != "expense" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be 'income' or 'expense'"})
+		return
+	}
+	if value := c.Query("category_id"); value != "" {
+		categoryID, err := strconv.Atoi(value)
+		if err != nil || categoryID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+			return
+		}
+		category, err := h.storage.GetCategory(categoryID, userID.(int))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if category == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category does not exist or does not belong to user"})
+			return
+		}
+		filter.CategoryID = categoryID
+	}
+	for _, p := range []struct {
+		name   string
+		target *time.Time
+	}{{"date_from", &filter.DateFrom}, {"date_to", &filter.DateTo}} {
+		if value := c.Query(p.name); value != "" {
+			date, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.name})
+				return
+			}
+			*p.target = date
+		}
+	}
+	if !filter.DateFrom.IsZero() && !filter.DateTo.IsZero() && filter.DateFrom.After(filter.DateTo) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date_from must not be after date_to"})
+		return
+	}
+	includePlanned, err := parseIncludePlanned(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.IncludePlanned = includePlanned
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="transactions.jsonl"`)
+	c.Status(http.StatusOK)
+
+	// Как и в ExportData, после начала записи ошибки только регистрируются в контексте
+	enc := json.NewEncoder(c.Writer)
+	written := 0
+	err = h.storage.ForEachFilteredTransaction(c.Request.Context(), userID.(int), filter, func(t models.Transaction) error {
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+		if written++; written%exportFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		c.Error(err)
+	}
+}
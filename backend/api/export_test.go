@@ -0,0 +1,181 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestExportData тестирует выгрузку данных пользователя в ZIP-архиве.
+func TestExportData(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	// Создаем тестового пользователя с категорией и транзакциями
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		transaction := models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, Date: time.Now()}
+		if err := storage.CreateTransaction(&transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	token := getToken(t, r, "testuser", "password123")
+
+	req, _ := http.NewRequest("GET", "/me/export", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// Проверяем, что архив отдан (200 OK)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Expected Content-Type application/zip, got %s", ct)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	// Проверяем профиль
+	var profile models.Profile
+	decodeZipFile(t, files["profile.json"], &profile)
+	if profile.ID != user.ID || profile.Username != "testuser" {
+		t.Errorf("Expected profile {ID: %d, Username: testuser}, got %+v", user.ID, profile)
+	}
+
+	// Проверяем категории
+	var categories []models.Category
+	decodeZipFile(t, files["categories.json"], &categories)
+	if len(categories) != 1 || categories[0].Name != "food" {
+		t.Errorf("Expected 1 category 'food', got %+v", categories)
+	}
+
+	// Проверяем транзакции
+	var transactions []models.Transaction
+	decodeZipFile(t, files["transactions.json"], &transactions)
+	if len(transactions) != 3 {
+		t.Errorf("Expected 3 transactions, got %d", len(transactions))
+	}
+
+	// Тестируем экспорт без токена
+	req, _ = http.NewRequest("GET", "/me/export", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// Ожидаем ошибку 401 Unauthorized
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func decodeZipFile(t *testing.T, f *zip.File, v interface{}) {
+	t.Helper()
+	if f == nil {
+		t.Fatal("Expected file in archive, got nil")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", f.Name, err)
+	}
+	defer rc.Close()
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		t.Fatalf("Failed to decode %s: %v", f.Name, err)
+	}
+}
+
+// TestExportTransactionsJSONL тестирует потоковую выгрузку транзакций в формате JSON Lines.
+func TestExportTransactionsJSONL(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 250; i++ {
+		transactionType := "expense"
+		if i%5 == 0 {
+			transactionType = "income"
+		}
+		transaction := models.Transaction{UserID: user.ID, Amount: models.NewMoney(int64(i+1), 0), Type: transactionType, CategoryID: category.ID, Date: base.Add(time.Duration(i) * time.Hour)}
+		if err := storage.CreateTransaction(&transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	token := getToken(t, r, "testuser", "password123")
+	export := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/transactions/export"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) []models.Transaction {
+		var transactions []models.Transaction
+		dec := json.NewDecoder(w.Body)
+		for dec.More() {
+			var transaction models.Transaction
+			if err := dec.Decode(&transaction); err != nil {
+				t.Fatalf("Failed to decode line: %v", err)
+			}
+			transactions = append(transactions, transaction)
+		}
+		return transactions
+	}
+
+	w := export("?format=jsonl")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected Content-Type application/x-ndjson, got %s", ct)
+	}
+	if lines := bytes.Count(w.Body.Bytes(), []byte("\n")); lines != 250 {
+		t.Errorf("Expected 250 lines, got %d", lines)
+	}
+	transactions := decode(w)
+	if len(transactions) != 250 || transactions[0].Amount != models.NewMoney(1, 0) || transactions[249].Amount != models.NewMoney(250, 0) {
+		t.Errorf("Expected 250 transactions in date order, got %d", len(transactions))
+	}
+
+	// Фильтры применяются так же, как в списке транзакций
+	w = export("?type=income&date_to=" + base.Add(99*time.Hour).Format(time.RFC3339))
+	if transactions := decode(w); w.Code != http.StatusOK || len(transactions) != 20 {
+		t.Errorf("Expected 20 filtered transactions, got %d (status %d)", len(transactions), w.Code)
+	}
+
+	if w := export("?format=csv"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := export("?category_id=999999"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestToggleTransactionFlag тестирует отметку транзакций для проверки и фильтр flagged.
+func TestToggleTransactionFlag(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	var transactions []*models.Transaction
+	for i := 0; i < 2; i++ {
+		transaction := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID}
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	do := func(method, url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	toggle := func(id int) (int, models.Transaction) {
+		w := do("POST", fmt.Sprintf("/transactions/%d/flag", id))
+		var transaction models.Transaction
+		json.NewDecoder(w.Body).Decode(&transaction)
+		return w.Code, transaction
+	}
+	flaggedIDs := func() []int {
+		w := do("GET", "/transactions?flagged=true")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response models.GetTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		var ids []int
+		for _, transaction := range response.Transactions {
+			ids = append(ids, transaction.ID)
+		}
+		return ids
+	}
+
+	if code, transaction := toggle(transactions[0].ID); code != http.StatusOK || !transaction.Flagged {
+		t.Errorf("Expected flagged transaction, got status %d and %+v", code, transaction)
+	}
+	if ids := flaggedIDs(); len(ids) != 1 || ids[0] != transactions[0].ID {
+		t.Errorf("Expected only transaction %d flagged, got %v", transactions[0].ID, ids)
+	}
+
+	// Повторный вызов снимает отметку
+	if code, transaction := toggle(transactions[0].ID); code != http.StatusOK || transaction.Flagged {
+		t.Errorf("Expected unflagged transaction, got status %d and %+v", code, transaction)
+	}
+	if ids := flaggedIDs(); len(ids) != 0 {
+		t.Errorf("Expected no flagged transactions, got %v", ids)
+	}
+
+	if code, _ := toggle(999999); code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, code)
+	}
+	if w := do("GET", "/transactions?flagged=maybe"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+const (
+	// maxForecastDays — наибольший горизонт прогноза остатка.
+	maxForecastDays = 366
+	// maxForecastHistoryDays — наибольший период истории для среднего дневного потока.
+	maxForecastHistoryDays = 730
+)
+
+// forecastBalances прогнозирует остаток на каждый из days дней после дня today: к остатку balance каждый день
+// прибавляется средний поток dailyAverage и сумма запланированных на день транзакций planned по дате YYYY-MM-DD.
+// Возвращает точки прогноза и первый день с отрицательным остатком или пустую строку.
+func forecastBalances(balance, dailyAverage models.Money, today time.Time, days int, planned map[string]models.Money) ([]models.ForecastPoint, string) {
+	points := make([]models.ForecastPoint, 0, days)
+	negative := ""
+	for i := 1; i <= days; i++ {
+		date := today.AddDate(0, 0, i).Format("2006-01-02")
+		balance += dailyAverage + planned[date]
+		points = append(points, models.ForecastPoint{Date: date, Planned: planned[date], Balance: balance})
+		if balance < 0 && negative == "" {
+			negative = date
+		}
+	}
+	return points, negative
+}
+
+// ownBalances возвращает остатки своих счетов из accounts: положительные остатки учитываются как доходы,
+// отрицательные — как расходы, чтобы их можно было пересчитать по курсам вместе.
+func ownBalances(accounts []models.Account) []models.TransactionTotals {
+	balances := make([]models.TransactionTotals, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Role != "owner" {
+			continue
+		}
+		if account.Balance >= 0 {
+			balances = append(balances, models.TransactionTotals{Currency: account.Currency, Income: account.Balance})
+		} else {
+			balances = append(balances, models.TransactionTotals{Currency: account.Currency, Expense: -account.Balance})
+		}
+	}
+	return balances
+}
+
+// parseDays читает положительное число дней из параметра name не больше max; без параметра возвращает def.
+func parseDays(c *gin.Context, name string, def, max int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return def, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 || days > max {
+		return 0, fmt.Errorf("%s must be between 1 and %d", name, max)
+	}
+	return days, nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Прогноз остатка
+// @Description Прогнозирует суммарный остаток своих открытых счетов в базовой валюте пользователя на каждый день
+// @Description горизонта: к текущему остатку прибавляются средний дневной поток за последние history_days дней
+// @Description и запланированные транзакции их дат (просроченные — завтрашним днем). Возвращает первый день,
+// @Description в который остаток становится отрицательным, если такой есть. Переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param days query int false "Горизонт прогноза в днях, от 1 до 366 (по умолчанию 90)"
+// @Param history_days query int false "Период истории для среднего дневного потока в днях, от 1 до 730 (по умолчанию 90)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.CashFlowForecast
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/forecast [get]
+func (h *Handler) GetForecast(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	days, err := parseDays(c, "days", 90, maxForecastDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	historyDays, err := parseDays(c, "history_days", 90, maxForecastHistoryDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	accounts, err := h.storage.GetAccounts(user.ID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	history, err := h.storage.GetPeriodSummary(user.ID, tomorrow.AddDate(0, 0, -historyDays), tomorrow, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	plannedTotals, err := h.storage.GetPlannedTotals(user.ID, tomorrow, tomorrow.AddDate(0, 0, days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	balance, err := h.convertTotals(c.Request.Context(), ownBalances(accounts), user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert balances: " + err.Error()})
+		return
+	}
+	flow, err := h.convertTotals(c.Request.Context(), summaryTotals(history), user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+	planned := make(map[string]models.Money)
+	for _, day := range plannedTotals {
+		converted, err := h.convertTotals(c.Request.Context(), []models.TransactionTotals{day.TransactionTotals}, user.BaseCurrency)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert planned transactions: " + err.Error()})
+			return
+		}
+		planned[day.Date.Format("2006-01-02")] += converted.Income - converted.Expense
+	}
+
+	forecast := models.CashFlowForecast{
+		Currency:     user.BaseCurrency,
+		Balance:      balance.Income - balance.Expense,
+		DailyAverage: (flow.Income - flow.Expense) / models.Money(historyDays),
+		HistoryDays:  historyDays,
+	}
+	forecast.Points, forecast.NegativeDate = forecastBalances(forecast.Balance, forecast.DailyAverage, today, days, planned)
+
+	writeReport(c, "forecast", forecast, func() report.Workbook { return forecastWorkbook(forecast) })
+}
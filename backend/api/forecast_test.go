@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestForecastBalances тестирует прогноз остатка по дням.
+func TestForecastBalances(t *testing.T) {
+	today := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+	planned := map[string]models.Money{"2025-07-17": -models.NewMoney(500, 0), "2025-07-19": models.NewMoney(1000, 0)}
+	points, negative := forecastBalances(models.NewMoney(300, 0), -models.NewMoney(100, 0), today, 5, planned)
+	expected := []models.ForecastPoint{
+		{Date: "2025-07-16", Balance: models.NewMoney(200, 0)},
+		{Date: "2025-07-17", Planned: -models.NewMoney(500, 0), Balance: -models.NewMoney(400, 0)},
+		{Date: "2025-07-18", Balance: -models.NewMoney(500, 0)},
+		{Date: "2025-07-19", Planned: models.NewMoney(1000, 0), Balance: models.NewMoney(400, 0)},
+		{Date: "2025-07-20", Balance: models.NewMoney(300, 0)},
+	}
+	if len(points) != len(expected) {
+		t.Fatalf("Expected %d points, got %+v", len(expected), points)
+	}
+	for i := range expected {
+		if points[i] != expected[i] {
+			t.Errorf("Expected point %+v, got %+v", expected[i], points[i])
+		}
+	}
+	if negative != "2025-07-17" {
+		t.Errorf("Expected negative date 2025-07-17, got %q", negative)
+	}
+
+	if _, negative := forecastBalances(models.NewMoney(300, 0), 0, today, 5, nil); negative != "" {
+		t.Errorf("Expected no negative date, got %q", negative)
+	}
+}
+
+// TestGetForecast тестирует прогноз остатка по истории и запланированным транзакциям.
+func TestGetForecast(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	if _, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "RUB", InitialBalance: models.NewMoney(10000, 0)}); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(9000, 0), Date: today.AddDate(0, 0, -10)},
+		{Amount: models.NewMoney(5000, 0), Date: today.AddDate(0, 0, 5), Planned: true},
+	} {
+		transaction.UserID, transaction.CategoryID, transaction.Type, transaction.Currency = user.ID, category.ID, "expense", "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/forecast"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?days=60")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var forecast models.CashFlowForecast
+	json.NewDecoder(w.Body).Decode(&forecast)
+	// Расход 9000 за 90 дней — в среднем 100 в день
+	if forecast.Balance != models.NewMoney(10000, 0) || forecast.DailyAverage != -models.NewMoney(100, 0) || len(forecast.Points) != 60 {
+		t.Fatalf("Unexpected forecast: %+v", forecast)
+	}
+	if p := forecast.Points[4]; p.Planned != -models.NewMoney(5000, 0) || p.Balance != models.NewMoney(4500, 0) {
+		t.Errorf("Unexpected point with planned expense: %+v", p)
+	}
+	if expected := today.AddDate(0, 0, 51).Format("2006-01-02"); forecast.NegativeDate != expected {
+		t.Errorf("Expected negative date %s, got %q", expected, forecast.NegativeDate)
+	}
+
+	w = get("?days=30")
+	forecast = models.CashFlowForecast{}
+	json.NewDecoder(w.Body).Decode(&forecast)
+	if w.Code != http.StatusOK || len(forecast.Points) != 30 || forecast.NegativeDate != "" {
+		t.Errorf("Unexpected 30-day forecast: %d %+v", w.Code, forecast)
+	}
+
+	for _, query := range []string{"?days=0", "?days=367", "?history_days=abc", "?history_days=731"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
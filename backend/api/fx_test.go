@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestForeignAmount тестирует сумму в валюте покупки и курс пересчета.
+func TestForeignAmount(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "travel")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	do := func(method, url, body string) (int, models.Transaction) {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var transaction models.Transaction
+		json.NewDecoder(w.Body).Decode(&transaction)
+		return w.Code, transaction
+	}
+	create := func(fields string) (int, models.Transaction) {
+		return do("POST", "/transactions", fmt.Sprintf(`{"type": "expense", "category_id": %d, "currency": "RUB", %s}`, category.ID, fields))
+	}
+
+	// Курс передан явно и согласован с суммами
+	code, created := create(`"amount": 2511.75, "original_amount": 25.5, "original_currency": "EUR", "fx_rate": 98.5`)
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+	code, fetched := do("GET", fmt.Sprintf("/transactions/%d", created.ID), "")
+	if code != http.StatusOK || fetched.OriginalAmount != models.NewMoney(25, 50) || fetched.OriginalCurrency != "EUR" || fetched.FXRate != 98.5 {
+		t.Errorf("Expected original amount 25.50 EUR at 98.5, got status %d and %+v", code, fetched)
+	}
+
+	// Без курса он вычисляется по суммам
+	code, derived := create(`"amount": 1000, "original_amount": 12.5, "original_currency": "USD"`)
+	if code != http.StatusCreated || derived.FXRate != 80 {
+		t.Errorf("Expected derived fx_rate 80, got status %d and %+v", code, derived)
+	}
+
+	// Изменение суммы пересчитывает курс
+	code, patched := do("PATCH", fmt.Sprintf("/transactions/%d", derived.ID), `{"amount": 1100}`)
+	if code != http.StatusOK || patched.FXRate != 88 {
+		t.Errorf("Expected fx_rate 88 after amount change, got status %d and %+v", code, patched)
+	}
+
+	// Обычная транзакция не содержит полей валюты покупки
+	code, plain := create(`"amount": 100`)
+	if code != http.StatusCreated || plain.OriginalAmount != 0 || plain.OriginalCurrency != "" || plain.FXRate != 0 {
+		t.Errorf("Expected no foreign amount, got status %d and %+v", code, plain)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		fields string
+	}{
+		{"rate mismatch", `"amount": 2600, "original_amount": 25.5, "original_currency": "EUR", "fx_rate": 98.5`},
+		{"same currency", `"amount": 100, "original_amount": 100, "original_currency": "RUB"`},
+		{"currency without amount", `"amount": 100, "original_currency": "EUR"`},
+		{"amount without currency", `"amount": 100, "original_amount": 1`},
+		{"rate without amount", `"amount": 100, "fx_rate": 2`},
+		{"negative rate", `"amount": 100, "original_amount": 1, "original_currency": "EUR", "fx_rate": -100`},
+	} {
+		if code, _ := create(tc.fields); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tc.name, http.StatusBadRequest, code)
+		}
+	}
+}
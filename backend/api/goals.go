@@ -0,0 +1,412 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// maxGoalProjectionDays — горизонт прогноза достижения цели, 100 лет. При более медленном темпе дата не прогнозируется.
+const maxGoalProjectionDays = 36500
+
+// goalProgress заполняет прогресс цели по сумме взносов на дату now: остаток, процент выполнения,
+// ожидаемую дату достижения при среднем дневном темпе взносов с первого взноса и нужный ежемесячный взнос до срока.
+func goalProgress(goal *models.Goal, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	goal.Remaining = goal.Target - goal.Saved
+	if goal.Remaining < 0 {
+		goal.Remaining = 0
+	}
+	goal.Percent = math.Round(float64(goal.Saved)/float64(goal.Target)*1000) / 10
+	goal.Completed = goal.Remaining == 0
+	goal.ProjectedDate, goal.RequiredMonthly, goal.OnTrack = nil, 0, nil
+	if goal.Completed {
+		if goal.Deadline != nil {
+			onTrack := true
+			goal.OnTrack = &onTrack
+		}
+		return
+	}
+
+	if goal.FirstContributionDate != nil && goal.Saved > 0 {
+		// День первого взноса считается целиком, чтобы взнос в тот же день давал конечный темп
+		days := today.Sub(*goal.FirstContributionDate).Hours()/24 + 1
+		if days < 1 {
+			days = 1
+		}
+		daysLeft := math.Ceil(float64(goal.Remaining) / (float64(goal.Saved) / days))
+		if daysLeft <= maxGoalProjectionDays {
+			projected := today.AddDate(0, 0, int(daysLeft))
+			goal.ProjectedDate = &projected
+		}
+	}
+
+	if goal.Deadline != nil {
+		months := (goal.Deadline.Year()-today.Year())*12 + int(goal.Deadline.Month()-today.Month())
+		if months < 1 {
+			months = 1
+		}
+		goal.RequiredMonthly = (goal.Remaining + models.Money(months) - 1) / models.Money(months)
+		onTrack := goal.ProjectedDate != nil && !goal.ProjectedDate.After(*goal.Deadline)
+		goal.OnTrack = &onTrack
+	}
+}
+
+// truncateDeadline приводит срок цели к дате.
+func truncateDeadline(deadline *time.Time) {
+	if deadline != nil {
+		*deadline = time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// goalAccount проверяет, что счет цели доступен пользователю. Возвращает nil без счета.
+func (h *Handler) goalAccount(c *gin.Context, accountID, userID int) (*models.Account, bool) {
+	if accountID == 0 {
+		return nil, true
+	}
+	account, err := h.storage.GetAccount(accountID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if account == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account not found"})
+		return nil, false
+	}
+	return account, true
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать цель накоплений
+// @Description Создает цель с суммой, необязательным сроком и счетом, на котором копятся деньги.
+// @Description Без валюты цель создается в валюте счета или в базовой валюте пользователя
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param goal body models.CreateGoal true "Данные цели"
+// @Success 201 {object} models.Goal
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /goals [post]
+func (h *Handler) CreateGoal(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var request models.CreateGoal
+	if !bindJSON(c, &request) {
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	truncateDeadline(request.Deadline)
+	account, ok := h.goalAccount(c, request.AccountID, userID.(int))
+	if !ok {
+		return
+	}
+	if account != nil && request.Currency == "" {
+		request.Currency = account.Currency
+	}
+
+	goal, err := h.storage.CreateGoal(userID.(int), request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	goalProgress(goal, time.Now())
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+// @Security ApiKeyAuth
+// @Summary Цели накоплений
+// @Description Возвращает цели с прогрессом: накопленной суммой, остатком, ожидаемой датой достижения при среднем темпе взносов
+// @Description и ежемесячным взносом, нужным, чтобы успеть к сроку
+// @Tags goals
+// @Produce json
+// @Success 200 {array} models.Goal
+// @Failure 401 {object} models.ErrorResponse
+// @Router /goals [get]
+func (h *Handler) GetGoals(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	goals, err := h.storage.GetGoals(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	now := time.Now()
+	for i := range goals {
+		goalProgress(&goals[i], now)
+	}
+
+	c.JSON(http.StatusOK, goals)
+}
+
+// loadGoal читает цель пользователя из параметра id; при ошибке отвечает клиенту и возвращает false.
+func (h *Handler) loadGoal(c *gin.Context) (*models.Goal, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal id"})
+		return nil, false
+	}
+	goal, err := h.storage.GetGoal(id, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if goal == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+		return nil, false
+	}
+	goalProgress(goal, time.Now())
+	return goal, true
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить цель накоплений
+// @Tags goals
+// @Produce json
+// @Param id path int true "ID цели"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /goals/{id} [get]
+func (h *Handler) GetGoal(c *gin.Context) {
+	goal, ok := h.loadGoal(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, goal)
+}
+
+// @Security ApiKeyAuth
+// @Summary Обновить цель накоплений
+// @Description Изменяет название, сумму, срок и счет цели. Валюта не меняется; незаданные срок и счет сбрасываются
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID цели"
+// @Param goal body models.UpdateGoal true "Данные цели"
+// @Success 200 {object} models.Goal
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /goals/{id} [put]
+func (h *Handler) UpdateGoal(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal id"})
+		return
+	}
+
+	var request models.UpdateGoal
+	if !bindJSON(c, &request) {
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	truncateDeadline(request.Deadline)
+	if _, ok := h.goalAccount(c, request.AccountID, userID.(int)); !ok {
+		return
+	}
+
+	updated, err := h.storage.UpdateGoal(id, userID.(int), request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+		return
+	}
+
+	goal, ok := h.loadGoal(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, goal)
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить цель накоплений
+// @Description Удаляет цель вместе со взносами; транзакции, учтенные взносами, остаются
+// @Tags goals
+// @Param id path int true "ID цели"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /goals/{id} [delete]
+func (h *Handler) DeleteGoal(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteGoal(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Security ApiKeyAuth
+// @Summary Взносы в цель
+// @Description Возвращает взносы по дате. Взносы-транзакции удаленных транзакций не возвращаются
+// @Tags goals
+// @Produce json
+// @Param id path int true "ID цели"
+// @Success 200 {array} models.GoalContribution
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /goals/{id}/contributions [get]
+func (h *Handler) GetGoalContributions(c *gin.Context) {
+	goal, ok := h.loadGoal(c)
+	if !ok {
+		return
+	}
+
+	contributions, err := h.storage.GetGoalContributions(goal.ID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contributions)
+}
+
+// @Security ApiKeyAuth
+// @Summary Внести взнос в цель
+// @Description Добавляет взнос суммой (отрицательная сумма — изъятие) или учитывает взносом транзакцию в валюте цели.
+// @Description У цели со счетом взносом может быть только транзакция этого счета. Сумма и дата взноса-транзакции
+// @Description берутся из транзакции и меняются вместе с ней
+// @Tags goals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID цели"
+// @Param contribution body models.CreateGoalContribution true "Данные взноса"
+// @Success 201 {object} models.GoalContribution
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /goals/{id}/contributions [post]
+func (h *Handler) CreateGoalContribution(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal id"})
+		return
+	}
+
+	var request models.CreateGoalContribution
+	if !bindJSON(c, &request) {
+		return
+	}
+	if request.TransactionID != 0 && (request.Amount != 0 || request.Date != nil || request.Description != "") {
+		badRequest(c, fieldError("transaction_id", "amount, date and description are taken from the transaction"))
+		return
+	}
+	if request.TransactionID == 0 && request.Amount == 0 {
+		badRequest(c, fieldError("amount", "amount must be non-zero and at most %s in absolute value", db.MaxAmount))
+		return
+	}
+
+	contribution, err := h.storage.CreateGoalContribution(id, userID.(int), request)
+	if err != nil {
+		message := err.Error()
+		if strings.Contains(message, "already") {
+			c.JSON(http.StatusConflict, gin.H{"error": message})
+		} else if strings.Contains(message, "transaction") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+		return
+	}
+	if contribution == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "goal not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, contribution)
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить взнос
+// @Description Удаляет взнос из цели; транзакция взноса остается
+// @Tags goals
+// @Param id path int true "ID цели"
+// @Param contribution_id path int true "ID взноса"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /goals/{id}/contributions/{contribution_id} [delete]
+func (h *Handler) DeleteGoalContribution(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid goal id"})
+		return
+	}
+	contributionID, err := strconv.Atoi(c.Param("contribution_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contribution id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteGoalContribution(contributionID, id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contribution not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestGoalProgress тестирует прогресс цели и прогноз даты ее достижения.
+func TestGoalProgress(t *testing.T) {
+	now := time.Date(2025, 7, 10, 15, 0, 0, 0, time.UTC)
+	first := time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	// 3000 за 30 дней — 100 в день, остаток 7000 накопится за 70 дней
+	goal := &models.Goal{Target: models.NewMoney(10000, 0), Saved: models.NewMoney(3000, 0), FirstContributionDate: &first, Deadline: &deadline}
+	goalProgress(goal, now)
+	if goal.Remaining != models.NewMoney(7000, 0) || goal.Percent != 30 || goal.Completed {
+		t.Errorf("Unexpected progress: %+v", goal)
+	}
+	if goal.ProjectedDate == nil || !goal.ProjectedDate.Equal(time.Date(2025, 9, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected projected date: %v", goal.ProjectedDate)
+	}
+	if goal.RequiredMonthly != models.NewMoney(1400, 0) || goal.OnTrack == nil || !*goal.OnTrack {
+		t.Errorf("Unexpected deadline progress: %+v", goal)
+	}
+
+	// Срок раньше прогноза
+	early := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	goal.Deadline = &early
+	goalProgress(goal, now)
+	if goal.RequiredMonthly != models.NewMoney(7000, 0) || goal.OnTrack == nil || *goal.OnTrack {
+		t.Errorf("Expected goal off track, got %+v", goal)
+	}
+
+	// Без взносов дата не прогнозируется
+	goal = &models.Goal{Target: models.NewMoney(10000, 0)}
+	goalProgress(goal, now)
+	if goal.ProjectedDate != nil || goal.OnTrack != nil || goal.Remaining != goal.Target {
+		t.Errorf("Unexpected progress without contributions: %+v", goal)
+	}
+
+	goal = &models.Goal{Target: models.NewMoney(10000, 0), Saved: models.NewMoney(12000, 0), FirstContributionDate: &first, Deadline: &deadline}
+	goalProgress(goal, now)
+	if !goal.Completed || goal.Remaining != 0 || goal.Percent != 120 || goal.OnTrack == nil || !*goal.OnTrack {
+		t.Errorf("Unexpected completed goal: %+v", goal)
+	}
+}
+
+// TestGoals тестирует цели накоплений и взносы суммой и транзакциями.
+func TestGoals(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	savings, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Копилка", Currency: "USD", Type: "regular"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	cash, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "USD", Type: "regular"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, invalid := range []models.CreateGoal{
+		{Name: " ", Target: models.NewMoney(1000, 0)},
+		{Name: "Отпуск"},
+		{Name: "Отпуск", Target: models.NewMoney(1000, 0), Currency: "XX"},
+		{Name: "Отпуск", Target: models.NewMoney(1000, 0), AccountID: 999999},
+	} {
+		if w := send("POST", "/goals", invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d", http.StatusBadRequest, invalid, w.Code)
+		}
+	}
+
+	w := send("POST", "/goals", models.CreateGoal{Name: "Отпуск", Target: models.NewMoney(1000, 0), AccountID: savings.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var goal models.Goal
+	json.NewDecoder(w.Body).Decode(&goal)
+	if goal.ID == 0 || goal.Currency != "USD" || goal.AccountID != savings.ID || goal.Saved != 0 || goal.Remaining != goal.Target {
+		t.Errorf("Unexpected goal: %+v", goal)
+	}
+	contributionsPath := fmt.Sprintf("/goals/%d/contributions", goal.ID)
+
+	deposit := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(300, 0), Type: "income", Currency: "USD", AccountID: savings.ID,
+		Date: time.Now().AddDate(0, 0, -9)}
+	other := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(50, 0), Type: "income", Currency: "USD", AccountID: cash.ID}
+	for _, transaction := range []*models.Transaction{deposit, other} {
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	if w := send("POST", contributionsPath, models.CreateGoalContribution{TransactionID: other.ID}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for transaction of another account, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", contributionsPath, models.CreateGoalContribution{TransactionID: deposit.ID, Amount: models.NewMoney(1, 0)}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for transaction with amount, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", contributionsPath, models.CreateGoalContribution{}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for empty contribution, got %d", http.StatusBadRequest, w.Code)
+	}
+	w = send("POST", contributionsPath, models.CreateGoalContribution{TransactionID: deposit.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if w := send("POST", contributionsPath, models.CreateGoalContribution{TransactionID: deposit.ID}); w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for repeated transaction, got %d", http.StatusConflict, w.Code)
+	}
+	w = send("POST", contributionsPath, models.CreateGoalContribution{Amount: -models.NewMoney(100, 0), Description: "Сняли"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var withdrawal models.GoalContribution
+	json.NewDecoder(w.Body).Decode(&withdrawal)
+
+	w = send("GET", contributionsPath, nil)
+	var contributions []models.GoalContribution
+	json.NewDecoder(w.Body).Decode(&contributions)
+	if w.Code != http.StatusOK || len(contributions) != 2 || contributions[0].TransactionID != deposit.ID || contributions[0].Amount != models.NewMoney(300, 0) {
+		t.Errorf("Unexpected contributions: %d %+v", w.Code, contributions)
+	}
+
+	w = send("GET", fmt.Sprintf("/goals/%d", goal.ID), nil)
+	json.NewDecoder(w.Body).Decode(&goal)
+	if goal.Saved != models.NewMoney(200, 0) || goal.Percent != 20 || goal.ProjectedDate == nil {
+		t.Errorf("Unexpected goal progress: %+v", goal)
+	}
+
+	// Удаленная транзакция перестает учитываться
+	if _, err := storage.DeleteTransaction(deposit.ID, user.ID); err != nil {
+		t.Fatalf("Failed to delete transaction: %v", err)
+	}
+	w = send("GET", "/goals", nil)
+	var goals []models.Goal
+	json.NewDecoder(w.Body).Decode(&goals)
+	if w.Code != http.StatusOK || len(goals) != 1 || goals[0].Saved != -models.NewMoney(100, 0) {
+		t.Errorf("Unexpected goals after transaction delete: %d %+v", w.Code, goals)
+	}
+
+	deadline := time.Now().AddDate(1, 0, 0)
+	w = send("PUT", fmt.Sprintf("/goals/%d", goal.ID), models.UpdateGoal{Name: "Отпуск на море", Target: models.NewMoney(2000, 0), Deadline: &deadline})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&goal)
+	if goal.Name != "Отпуск на море" || goal.AccountID != 0 || goal.Deadline == nil || goal.RequiredMonthly == 0 {
+		t.Errorf("Unexpected updated goal: %+v", goal)
+	}
+
+	if w := send("DELETE", fmt.Sprintf("%s/%d", contributionsPath, withdrawal.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("/goals/%d", goal.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("GET", fmt.Sprintf("/goals/%d", goal.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after delete, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+	finv1 "github.com/nemopss/fin-ng/backend/proto/fin/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcUserKey — ключ контекста вызова gRPC, под которым хранится ID пользователя.
+type grpcUserKey struct{}
+
+// grpcMutations — методы, после успешного вызова которых сбрасывается кэш ответов пользователя.
+var grpcMutations = map[string]bool{
+	finv1.FinanceService_CreateCategory_FullMethodName:    true,
+	finv1.FinanceService_DeleteCategory_FullMethodName:    true,
+	finv1.FinanceService_CreateTransaction_FullMethodName: true,
+	finv1.FinanceService_DeleteTransaction_FullMethodName: true,
+}
+
+// GRPCServer создает gRPC-сервер с FinanceService. Вызовы авторизуются тем же JWT, что и HTTP API,
+// переданным в метаданных authorization, и расходуют ту же квоту запросов пользователя.
+// opts передаются в grpc.NewServer, например учетные данные TLS.
+func (h *Handler) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(opts, grpc.ChainUnaryInterceptor(h.grpcAuth))...)
+	finv1.RegisterFinanceServiceServer(server, &financeServer{h: h})
+	return server
+}
+
+// grpcAuth проверяет JWT и квоту запросов и сбрасывает кэш ответов после изменений.
+func (h *Handler) grpcAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata required")
+	}
+	userID, role, err := h.parseToken(values[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if limit := h.quotaFor(role); limit > 0 {
+		res, err := h.limiter.Allow(ctx, "user:"+strconv.Itoa(userID), limit, quotaWindow, time.Now())
+		if err != nil {
+			log.Printf("Rate limiter unavailable: %v", err)
+		} else if !res.Allowed {
+			return nil, status.Error(codes.ResourceExhausted, "rate quota exceeded")
+		}
+	}
+
+	resp, err := handler(context.WithValue(ctx, grpcUserKey{}, userID), req)
+	if err == nil && grpcMutations[info.FullMethod] {
+		h.invalidateCache(ctx, userID)
+	}
+	return resp, err
+}
+
+// grpcError преобразует ошибку хранилища в ошибку gRPC: ошибки в данных запроса
+// возвращаются как InvalidArgument, остальные — как Internal.
+func grpcError(err error) error {
+	code := codes.Internal
+	if linkErrorStatus(err) == http.StatusBadRequest || strings.Contains(err.Error(), "does not exist or does not belong to user") {
+		code = codes.InvalidArgument
+	}
+	return status.Error(code, err.Error())
+}
+
+// financeServer реализует FinanceService поверх тех же проверок и хранилища, что и HTTP API.
+type financeServer struct {
+	finv1.UnimplementedFinanceServiceServer
+	h *Handler
+}
+
+func (s *financeServer) GetProfile(ctx context.Context, _ *finv1.GetProfileRequest) (*finv1.Profile, error) {
+	user, err := s.h.storage.GetUserByID(ctx.Value(grpcUserKey{}).(int))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &finv1.Profile{Id: int64(user.ID), Username: user.Username, Email: user.Email, BaseCurrency: user.BaseCurrency,
+		MonthStart: int32(user.MonthStart)}, nil
+}
+
+func (s *financeServer) ListCategories(ctx context.Context, _ *finv1.ListCategoriesRequest) (*finv1.ListCategoriesResponse, error) {
+	categories, err := s.h.storage.GetCategories(ctx.Value(grpcUserKey{}).(int))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &finv1.ListCategoriesResponse{Categories: make([]*finv1.Category, 0, len(categories))}
+	for _, category := range categories {
+		resp.Categories = append(resp.Categories, categoryProto(&category))
+	}
+	return resp, nil
+}
+
+func (s *financeServer) CreateCategory(ctx context.Context, req *finv1.CreateCategoryRequest) (*finv1.Category, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "category name is required")
+	}
+	icon, color, err := normalizeCategoryAppearance(req.Icon, req.Color)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	category, err := s.h.storage.CreateCategoryWithAppearance(ctx.Value(grpcUserKey{}).(int), req.Name, icon, color)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return categoryProto(category), nil
+}
+
+func (s *financeServer) DeleteCategory(ctx context.Context, req *finv1.DeleteCategoryRequest) (*finv1.DeleteCategoryResponse, error) {
+	userID := ctx.Value(grpcUserKey{}).(int)
+	category, err := s.h.storage.GetCategory(int(req.Id), userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if category != nil && category.System {
+		return nil, status.Error(codes.PermissionDenied, "system categories are read-only")
+	}
+
+	deleted, err := s.h.storage.DeleteCategory(int(req.Id), userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "category is used in transactions") {
+			return nil, status.Error(codes.FailedPrecondition, "category is used in transactions")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !deleted {
+		return nil, status.Error(codes.NotFound, "category not found")
+	}
+	return &finv1.DeleteCategoryResponse{}, nil
+}
+
+func (s *financeServer) ListTransactions(ctx context.Context, req *finv1.ListTransactionsRequest) (*finv1.ListTransactionsResponse, error) {
+	userID := ctx.Value(grpcUserKey{}).(int)
+	if req.Type != "" && req.Type != "income" && req.Type != "expense" {
+		return nil, status.Error(codes.InvalidArgument, "type must be 'income' or 'expense'")
+	}
+	if req.CategoryId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "category_id must be positive")
+	}
+	page, limit := int(req.Page), int(req.Limit)
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 10
+	}
+	if page < 1 {
+		return nil, status.Error(codes.InvalidArgument, "page must be a positive integer")
+	}
+	if limit < 1 || limit > 100 {
+		return nil, status.Error(codes.InvalidArgument, "limit must be between 1 and 100")
+	}
+
+	filter := db.TransactionFilter{Type: req.Type, CategoryID: int(req.CategoryId), Sort: "desc"}
+	if req.From != nil {
+		filter.DateFrom = req.From.AsTime()
+	}
+	if req.To != nil {
+		filter.DateTo = req.To.AsTime()
+	}
+	if !filter.DateFrom.IsZero() && !filter.DateTo.IsZero() && filter.DateTo.Before(filter.DateFrom) {
+		return nil, status.Error(codes.InvalidArgument, "to must not be before from")
+	}
+
+	transactions, total, err := s.h.storage.GetTransactions(userID, filter, page, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &finv1.ListTransactionsResponse{Transactions: make([]*finv1.Transaction, 0, len(transactions)), Total: int64(total)}
+	for i := range transactions {
+		resp.Transactions = append(resp.Transactions, transactionProto(&transactions[i]))
+	}
+	return resp, nil
+}
+
+func (s *financeServer) GetTransaction(ctx context.Context, req *finv1.GetTransactionRequest) (*finv1.Transaction, error) {
+	transaction, err := s.h.storage.GetTransaction(int(req.Id), ctx.Value(grpcUserKey{}).(int))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if transaction == nil {
+		return nil, status.Error(codes.NotFound, "transaction not found")
+	}
+	return transactionProto(transaction), nil
+}
+
+func (s *financeServer) CreateTransaction(ctx context.Context, req *finv1.CreateTransactionRequest) (*finv1.Transaction, error) {
+	userID := ctx.Value(grpcUserKey{}).(int)
+	amount, err := models.ParseMoney(req.Amount)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	transaction := models.Transaction{
+		UserID:              userID,
+		Amount:              amount,
+		Type:                req.Type,
+		CategoryID:          int(req.CategoryId),
+		Description:         req.Description,
+		Currency:            req.Currency,
+		Tags:                req.Tags,
+		Status:              req.Status,
+		Payee:               req.Payee,
+		AccountID:           int(req.AccountId),
+		LinkedTransactionID: int(req.LinkedTransactionId),
+		Date:                time.Now(),
+	}
+	if req.Date != nil {
+		transaction.Date = req.Date.AsTime()
+	}
+	if err := validateTransaction(transaction); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.h.storage.CreateTransaction(&transaction); err != nil {
+		return nil, grpcError(err)
+	}
+	s.h.transactionsCreated(userID, &transaction)
+	return transactionProto(&transaction), nil
+}
+
+func (s *financeServer) DeleteTransaction(ctx context.Context, req *finv1.DeleteTransactionRequest) (*finv1.DeleteTransactionResponse, error) {
+	deleted, err := s.h.storage.DeleteTransaction(int(req.Id), ctx.Value(grpcUserKey{}).(int))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if !deleted {
+		return nil, status.Error(codes.NotFound, "transaction not found")
+	}
+	return &finv1.DeleteTransactionResponse{}, nil
+}
+
+// categoryProto преобразует категорию в сообщение gRPC.
+func categoryProto(c *models.Category) *finv1.Category {
+	return &finv1.Category{Id: int64(c.ID), Name: c.Name, Icon: c.Icon, Color: c.Color, System: c.System}
+}
+
+// transactionProto преобразует транзакцию в сообщение gRPC.
+func transactionProto(t *models.Transaction) *finv1.Transaction {
+	pb := &finv1.Transaction{
+		Id:                int64(t.ID),
+		Amount:            t.Amount.String(),
+		Type:              t.Type,
+		CategoryId:        int64(t.CategoryID),
+		Date:              timestamppb.New(t.Date),
+		Description:       t.Description,
+		Currency:          t.Currency,
+		Tags:              t.Tags,
+		Planned:           t.Planned,
+		Status:            t.Status,
+		Payee:             t.Payee,
+		AccountId:         int64(t.AccountID),
+		PossibleDuplicate: t.PossibleDuplicate,
+	}
+	for _, id := range t.DuplicateOf {
+		pb.DuplicateOf = append(pb.DuplicateOf, int64(id))
+	}
+	return pb
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nemopss/fin-ng/backend/events"
+	finv1 "github.com/nemopss/fin-ng/backend/proto/fin/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGRPC запускает gRPC-сервер обработчика в памяти и возвращает клиента.
+func dialGRPC(t *testing.T, handler *Handler) finv1.FinanceServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := handler.GRPCServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial gRPC server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return finv1.NewFinanceServiceClient(conn)
+}
+
+// grpcContext возвращает контекст вызова с JWT пользователя userID в метаданных.
+func grpcContext(t *testing.T, secret string, userID int) context.Context {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": userID, "exp": time.Now().Add(time.Hour).Unix()}).
+		SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// TestGRPCAuth тестирует проверку JWT и квоты запросов для вызовов gRPC.
+func TestGRPCAuth(t *testing.T) {
+	client := dialGRPC(t, NewHandler(nil, Config{JWTSecret: "secret", UserQuota: 1}))
+
+	if _, err := client.ListCategories(context.Background(), &finv1.ListCategoriesRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without token, got %v", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+	if _, err := client.ListCategories(ctx, &finv1.ListCategoriesRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with invalid token, got %v", err)
+	}
+
+	// Первый вызов проходит проверку и отклоняется по неверному аргументу, второй превышает квоту
+	ctx = grpcContext(t, "secret", 1)
+	if _, err := client.ListTransactions(ctx, &finv1.ListTransactionsRequest{Type: "other"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+	if _, err := client.ListTransactions(ctx, &finv1.ListTransactionsRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+}
+
+// TestGRPCFinanceService тестирует категории и транзакции через gRPC.
+func TestGRPCFinanceService(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+	hub := events.NewHub()
+	handler := NewHandler(storage, Config{JWTSecret: os.Getenv("JWT_SECRET"), Events: hub})
+	client := dialGRPC(t, handler)
+
+	user, err := storage.CreateUser("grpcuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	ctx := grpcContext(t, os.Getenv("JWT_SECRET"), user.ID)
+
+	profile, err := client.GetProfile(ctx, &finv1.GetProfileRequest{})
+	if err != nil || profile.Username != "grpcuser" {
+		t.Fatalf("Unexpected profile %v (%v)", profile, err)
+	}
+
+	category, err := client.CreateCategory(ctx, &finv1.CreateCategoryRequest{Name: "Продукты", Color: "#4CAF50"})
+	if err != nil || category.Id == 0 || category.Color != "#4caf50" {
+		t.Fatalf("Unexpected category %v (%v)", category, err)
+	}
+	if _, err := client.CreateCategory(ctx, &finv1.CreateCategoryRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for empty name, got %v", err)
+	}
+
+	sub := hub.Subscribe(user.ID)
+	defer sub.Close()
+	created, err := client.CreateTransaction(ctx, &finv1.CreateTransactionRequest{Amount: "1250.50", Type: "expense", CategoryId: category.Id,
+		Description: "Магазин", Tags: []string{"еда"}})
+	if err != nil || created.Id == 0 || created.Amount != "1250.50" || created.Currency != "RUB" || created.Date == nil {
+		t.Fatalf("Unexpected transaction %v (%v)", created, err)
+	}
+	if event := <-sub.C; event.Type != events.TransactionCreated {
+		t.Errorf("Expected transaction.created event, got %+v", event)
+	}
+	if _, err := client.CreateTransaction(ctx, &finv1.CreateTransactionRequest{Amount: "1.234", Type: "expense", CategoryId: category.Id}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for invalid amount, got %v", err)
+	}
+	if _, err := client.CreateTransaction(ctx, &finv1.CreateTransactionRequest{Amount: "10", Type: "expense", CategoryId: category.Id + 100}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for foreign category, got %v", err)
+	}
+
+	list, err := client.ListTransactions(ctx, &finv1.ListTransactionsRequest{CategoryId: category.Id})
+	if err != nil || list.Total != 1 || len(list.Transactions) != 1 || list.Transactions[0].Tags[0] != "еда" {
+		t.Fatalf("Unexpected transactions %v (%v)", list, err)
+	}
+	if got, err := client.GetTransaction(ctx, &finv1.GetTransactionRequest{Id: created.Id}); err != nil || got.Description != "Магазин" {
+		t.Errorf("Unexpected transaction %v (%v)", got, err)
+	}
+
+	if _, err := client.DeleteCategory(ctx, &finv1.DeleteCategoryRequest{Id: category.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for used category, got %v", err)
+	}
+	if _, err := client.DeleteTransaction(ctx, &finv1.DeleteTransactionRequest{Id: created.Id}); err != nil {
+		t.Errorf("DeleteTransaction failed: %v", err)
+	}
+	if _, err := client.GetTransaction(ctx, &finv1.GetTransactionRequest{Id: created.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound after delete, got %v", err)
+	}
+
+	// Транзакции другого пользователя недоступны
+	other, err := storage.CreateUser("other", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	otherCtx := grpcContext(t, os.Getenv("JWT_SECRET"), other.ID)
+	if list, err := client.ListTransactions(otherCtx, &finv1.ListTransactionsRequest{Limit: 100}); err != nil || list.Total != 0 {
+		t.Errorf("Expected no transactions for other user, got %v (%v)", list, err)
+	}
+}
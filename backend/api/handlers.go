@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,33 +10,156 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/nemopss/fin-ng/backend/cache"
+	"github.com/nemopss/fin-ng/backend/captcha"
 	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/events"
+	appmail "github.com/nemopss/fin-ng/backend/mail"
+	"github.com/nemopss/fin-ng/backend/metrics"
 	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/quotes"
+	"github.com/nemopss/fin-ng/backend/ratelimit"
+	"github.com/nemopss/fin-ng/backend/rates"
+	"github.com/nemopss/fin-ng/backend/receipt"
+	"github.com/nemopss/fin-ng/backend/scheduler"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // FIX: swagger output models
 
-type Handler struct {
-	storage   *db.Storage
-	jwtSecret string
+// Режимы регистрации
+const (
+	RegistrationOpen   = "open"
+	RegistrationInvite = "invite"
+)
+
+const (
+	defaultTokenTTL      = 24 * time.Hour
+	defaultRememberMeTTL = 30 * 24 * time.Hour
+	defaultMagicLinkTTL  = 15 * time.Minute
+	defaultBaseURL       = "http://localhost:8080"
+)
+
+// Config содержит настройки обработчиков API.
+type Config struct {
+	JWTSecret string
+	// TokenTTL — время жизни обычного токена
+	TokenTTL time.Duration
+	// RememberMeTTL — время жизни токена при входе с remember_me
+	RememberMeTTL time.Duration
+	// BaseURL — внешний адрес API, используется в ссылках из писем
+	BaseURL string
+	// Mailer отправляет письма пользователям
+	Mailer appmail.Sender
+	// MagicLinkTTL — время жизни ссылки для входа без пароля
+	MagicLinkTTL time.Duration
+	// OIDC — внешний OpenID Connect провайдер; nil, если вход через него отключен
+	OIDC *OIDCProvider
+	// RegistrationMode — RegistrationOpen (по умолчанию) или RegistrationInvite
+	RegistrationMode string
+	// Captcha проверяет CAPTCHA при регистрации и после неудачных входов; nil отключает проверку
+	Captcha captcha.Verifier
+	// LoginCaptchaThreshold — число неудачных входов, после которого требуется CAPTCHA
+	LoginCaptchaThreshold int
+	// LoginAlerts включает письма о входе с нового устройства
+	LoginAlerts bool
+	// UserQuota — число запросов в минуту на пользователя; 0 отключает ограничение
+	UserQuota int
+	// RoleQuotas переопределяет UserQuota для отдельных ролей
+	RoleQuotas map[string]int
+	// IPQuota — число запросов в минуту с одного IP-адреса к маршрутам без авторизации;
+	// 0 отключает ограничение
+	IPQuota int
+	// RateLimiter хранит квоты запросов; nil — квоты в памяти процесса
+	RateLimiter ratelimit.Limiter
+	// Rates — источник курсов валют; nil отключает загрузку курсов,
+	// пересчет тогда выполняется только по ранее сохраненным курсам
+	Rates rates.Provider
+	// TrashRetention — срок хранения удаленных транзакций в корзине
+	TrashRetention time.Duration
+	// Receipts получает кассовые чеки по QR-коду; nil отключает создание транзакций по чекам
+	Receipts receipt.Provider
+	// Quotes — источник котировок ценных бумаг; nil отключает оценку позиций,
+	// они тогда оцениваются по сумме покупки
+	Quotes quotes.Provider
+	// CryptoQuotes — источник цен криптовалют для криптовалютных счетов; nil, как и для Quotes,
+	// отключает оценку позиций
+	CryptoQuotes quotes.Provider
+	// Cache — кэш ответов категорий, сводки и отчетов; nil отключает кэширование
+	Cache cache.Store
+	// Scheduler — планировщик фоновых задач, состояние которых показывает GET /admin/jobs
+	Scheduler *scheduler.Scheduler
+	// Events рассылает события подключенным к /ws клиентам; nil — новый Hub
+	Events *events.Hub
 }
 
-func NewHandler(s *db.Storage, jwtSecret string) *Handler {
-	return &Handler{storage: s, jwtSecret: jwtSecret}
+type Handler struct {
+	storage       *db.Storage
+	jwtSecret     string
+	cfg           Config
+	loginFailures *loginFailures
+	limiter       ratelimit.Limiter
+	rateCache     *rateCache
+	quoteCache    *quoteCache
+	// cryptoQuoteCache отделен от quoteCache, потому что тикеры бумаг и символы криптовалют могут совпадать
+	cryptoQuoteCache *quoteCache
 }
 
-func validateTransaction(t models.Transaction) error {
-	if t.Amount <= 0 {
-		return fmt.Errorf("amount must be positive")
+func NewHandler(s *db.Storage, cfg Config) *Handler {
+	if cfg.TokenTTL <= 0 {
+		cfg.TokenTTL = defaultTokenTTL
+	}
+	if cfg.RememberMeTTL <= 0 {
+		cfg.RememberMeTTL = defaultRememberMeTTL
+	}
+	if cfg.MagicLinkTTL <= 0 {
+		cfg.MagicLinkTTL = defaultMagicLinkTTL
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultBaseURL
+	}
+	if cfg.RegistrationMode == "" {
+		cfg.RegistrationMode = RegistrationOpen
+	}
+	if cfg.Mailer == nil {
+		cfg.Mailer = appmail.LogSender{}
+	}
+	if cfg.TrashRetention <= 0 {
+		cfg.TrashRetention = defaultTrashRetention
+	}
+	if cfg.LoginCaptchaThreshold <= 0 {
+		cfg.LoginCaptchaThreshold = defaultLoginCaptchaThreshold
 	}
-	if t.Type != "income" && t.Type != "expense" {
-		return fmt.Errorf("type must be 'income' or 'expense'")
+	if cfg.Events == nil {
+		cfg.Events = events.NewHub()
 	}
-	if t.CategoryID <= 0 {
-		return fmt.Errorf("category_id is required and must be positive")
+	if cfg.RateLimiter == nil {
+		cfg.RateLimiter = ratelimit.NewMemory()
 	}
-	return nil
+	return &Handler{storage: s, jwtSecret: cfg.JWTSecret, cfg: cfg, loginFailures: newLoginFailures(), limiter: cfg.RateLimiter, rateCache: newRateCache(s, cfg.Rates), quoteCache: newQuoteCache(cfg.Quotes),
+		cryptoQuoteCache: newQuoteCache(cfg.CryptoQuotes)}
+}
+
+const maxDescriptionLength = 1000
+
+// parsePageLimit читает параметры page (по умолчанию 1) и limit (по умолчанию 10, не больше 100).
+func parsePageLimit(c *gin.Context) (int, int, error) {
+	page, limit := 1, 10
+	if pageStr := c.Query("page"); pageStr != "" {
+		var err error
+		page, err = strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 100 {
+			return 0, 0, fmt.Errorf("limit must be between 1 and 100")
+		}
+	}
+	return page, limit, nil
 }
 
 func (h *Handler) AuthMiddleware() gin.HandlerFunc {
@@ -46,41 +170,75 @@ func (h *Handler) AuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		h.authenticate(c, tokenString)
+	}
+}
 
-		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-			tokenString = tokenString[7:]
+// StreamAuthMiddleware работает как AuthMiddleware, но принимает токен и в параметре token:
+// браузерные WebSocket и EventSource не позволяют передать заголовок Authorization.
+func (h *Handler) StreamAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.GetHeader("Authorization")
+		if tokenString == "" {
+			tokenString = c.Query("token")
 		}
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(h.jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		if tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header or token parameter required"})
 			c.Abort()
 			return
 		}
+		h.authenticate(c, tokenString)
+	}
+}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
-			c.Abort()
-			return
-		}
+// authenticate проверяет JWT и сохраняет в контексте user_id и role.
+func (h *Handler) authenticate(c *gin.Context, tokenString string) {
+	userID, role, err := h.parseToken(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.Abort()
+		return
+	}
 
-		userID, ok := claims["user_id"].(float64)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user_id in token"})
-			c.Abort()
-			return
+	c.Set("user_id", userID)
+	c.Set("role", role)
+	c.Next()
+}
+
+// parseToken проверяет JWT, переданный с префиксом "Bearer " или без него,
+// и возвращает ID и роль пользователя.
+func (h *Handler) parseToken(tokenString string) (int, string, error) {
+	if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
+		tokenString = tokenString[7:]
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		return []byte(h.jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, "", fmt.Errorf("invalid or expired token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, "", fmt.Errorf("invalid token claims")
+	}
 
-		c.Set("user_id", int(userID))
-		c.Next()
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("invalid user_id in token")
 	}
+
+	// Роль используется только для некритичных решений (например, квот);
+	// права администратора проверяются по базе в AdminMiddleware
+	role, ok := claims["role"].(string)
+	if !ok || role == "" {
+		role = models.RoleUser
+	}
+	return int(userID), role, nil
 }
 
 // @Summary Регистрация нового пользователя
@@ -90,21 +248,35 @@ func (h *Handler) AuthMiddleware() gin.HandlerFunc {
 // @Produce json
 // @Param credentials body models.CreateUser true "Данные пользователя"
 // @Success 201 {object} models.RegisterResponse"
-// @Failure 400 {object} models.ErrorResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /register [post]
 func (h *Handler) Register(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request models.CreateUser
+	if !bindJSON(c, &request) {
 		return
 	}
 
-	if len(user.Password) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 6 characters"})
+	ok, err := h.verifyCaptcha(c, request.CaptchaToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify captcha"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "captcha verification required"})
 		return
 	}
 
-	createdUser, err := h.storage.CreateUser(user.Username, user.Password)
+	var createdUser *models.User
+	if h.cfg.RegistrationMode == RegistrationInvite {
+		if request.InviteCode == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invite code is required"})
+			return
+		}
+		createdUser, err = h.storage.CreateUserWithInvite(request.Login, request.Password, request.Email, request.InviteCode)
+	} else {
+		createdUser, err = h.storage.CreateUserWithEmail(request.Login, request.Password, request.Email)
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -118,21 +290,31 @@ func (h *Handler) Register(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param credentials body models.CreateUser true "Данные пользователя"
+// @Param credentials body models.LoginRequest true "Данные пользователя"
 // @Success 200 {object} models.LoginResponse
-// @Failure 400 {object} models.ErrorResponse
+// @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /login [post]
 func (h *Handler) Login(c *gin.Context) {
-	var credentials struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-	}
-	if err := c.ShouldBindJSON(&credentials); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var credentials models.LoginRequest
+	if !bindJSON(c, &credentials) {
 		return
 	}
 
+	// После нескольких неудачных попыток вход требует CAPTCHA
+	if h.loginFailures.count(credentials.Username) >= h.cfg.LoginCaptchaThreshold {
+		ok, err := h.verifyCaptcha(c, credentials.CaptchaToken)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify captcha"})
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "captcha verification required"})
+			return
+		}
+	}
+
 	user, err := h.storage.GetUserByUsername(credentials.Username)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -140,18 +322,39 @@ func (h *Handler) Login(c *gin.Context) {
 	}
 
 	if user == nil {
+		h.loginFailures.add(credentials.Username)
+		metrics.Logins.WithLabelValues("failure").Inc()
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(credentials.Password)); err != nil {
+		h.loginFailures.add(credentials.Username)
+		metrics.Logins.WithLabelValues("failure").Inc()
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
+	h.loginFailures.reset(credentials.Username)
+
+	ttl := h.cfg.TokenTTL
+	if credentials.RememberMe {
+		ttl = h.cfg.RememberMeTTL
+	}
+	h.respondWithToken(c, user, ttl)
+}
+
+// respondWithToken завершает успешный вход: записывает его в историю,
+// выпускает JWT для пользователя и отдает его клиенту.
+func (h *Handler) respondWithToken(c *gin.Context, user *models.User, ttl time.Duration) {
+	h.recordLogin(c, user.ID)
+	metrics.Logins.WithLabelValues("success").Inc()
+
+	expiresAt := time.Now().Add(ttl)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"role":    user.Role,
+		"exp":     expiresAt.Unix(),
 	})
 
 	tokenString, err := token.SignedString([]byte(h.jwtSecret))
@@ -160,18 +363,18 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	c.JSON(http.StatusOK, gin.H{"token": tokenString, "expires_at": expiresAt.Unix()})
 }
 
 // @Security ApiKeyAuth
 // @Summary Создать новую категорию
-// @Description Создает новую категорию для пользователя
+// @Description Создает новую категорию для пользователя. Значок выбирается из набора /categories/icons, цвет задается как #rrggbb
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Param category body models.CreateCategory true "Данные категории"
 // @Success 201 {object} models.Category
-// @Failure 400 {object} models.ErrorResponse
+// @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Router /categories [post]
 func (h *Handler) CreateCategory(c *gin.Context) {
@@ -181,18 +384,17 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	var category models.Category
-	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var category models.CreateCategory
+	if !bindJSON(c, &category) {
 		return
 	}
-
-	if category.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "category name is required"})
+	icon, color, err := normalizeCategoryAppearance(category.Icon, category.Color)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	createdCategory, err := h.storage.CreateCategory(userID.(int), category.Name)
+	createdCategory, err := h.storage.CreateCategoryWithAppearance(userID.(int), category.Name, icon, color)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -203,10 +405,15 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 
 // @Security ApiKeyAuth
 // @Summary Получить список категорий
-// @Description Получает список категорий пользователя
+// @Description Получает список категорий пользователя вместе с общими системными категориями (system=true), доступными только для чтения.
+// @Description С with_stats=true для каждой категории возвращаются число транзакций, суммы по валютам
+// @Description и расходы в базовой валюте пользователя за все время
 // @Tags categories
 // @Produce json
+// @Param with_stats query bool false "Включить статистику транзакций (по умолчанию false)"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
 // @Success 200 {array} models.Category
+// @Success 304 "Список не изменился"
 // @Failure 401 {object} models.ErrorResponse
 // @Router /categories [get]
 func (h *Handler) GetCategories(c *gin.Context) {
@@ -215,6 +422,24 @@ func (h *Handler) GetCategories(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
 		return
 	}
+	withStats := false
+	if value := c.Query("with_stats"); value != "" {
+		var err error
+		if withStats, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "with_stats must be 'true' or 'false'"})
+			return
+		}
+	}
+	if withStats {
+		categories, err := h.categoriesWithStats(c.Request.Context(), userID.(int))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, categories)
+		return
+	}
+
 	categories, err := h.storage.GetCategories(userID.(int))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -263,15 +488,16 @@ func (h *Handler) GetCategory(c *gin.Context) {
 
 // @Security ApiKeyAuth
 // @Summary Обновить категорию
-// @Description Обновляет существующую категорию пользователя
+// @Description Обновляет существующую категорию пользователя. Незаданные значок и цвет сбрасываются
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Param id path int true "ID категории"
 // @Param category body models.CreateCategory true "Новое имя категории"
 // @Success 200 {object} models.UpdateCategoryResponse"
-// @Failure 400 {object} models.ErrorResponse
+// @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /categories/{id} [put]
 func (h *Handler) UpdateCategory(c *gin.Context) {
@@ -288,18 +514,20 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	var category models.Category
-	if err := c.ShouldBindJSON(&category); err != nil {
+	var category models.CreateCategory
+	if !bindJSON(c, &category) {
+		return
+	}
+	icon, color, err := normalizeCategoryAppearance(category.Icon, category.Color)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	if category.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "category name is required"})
+	if h.rejectSystemCategory(c, id, userID.(int)) {
 		return
 	}
 
-	updated, err := h.storage.UpdateCategory(id, userID.(int), category.Name)
+	updated, err := h.storage.UpdateCategoryWithAppearance(id, userID.(int), category.Name, icon, color)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -309,18 +537,36 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"id": id, "user_id": userID, "name": category.Name})
+	c.JSON(http.StatusOK, gin.H{"id": id, "user_id": userID, "name": category.Name, "icon": icon, "color": color})
+}
+
+// rejectSystemCategory отвечает 403, если категория id — системная: пользователи не могут ее изменять.
+// Возвращает true, если ответ уже записан.
+func (h *Handler) rejectSystemCategory(c *gin.Context, id, userID int) bool {
+	category, err := h.storage.GetCategory(id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+	if category != nil && category.System {
+		c.JSON(http.StatusForbidden, gin.H{"error": "system categories are read-only"})
+		return true
+	}
+	return false
 }
 
 // @Security ApiKeyAuth
 // @Summary Удалить категорию
-// @Description Удаляет категорию пользователя, если она не используется в транзакциях
+// @Description Удаляет категорию пользователя, если она не используется в транзакциях.
+// @Description С reassign_to транзакции категории (включая корзину) сначала переносятся в указанную категорию в той же транзакции БД
 // @Tags categories
 // @Produce json
 // @Param id path int true "ID категории"
+// @Param reassign_to query int false "ID категории, в которую переносятся транзакции удаляемой"
 // @Success 204
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /categories/{id} [delete]
 func (h *Handler) DeleteCategory(c *gin.Context) {
@@ -337,8 +583,27 @@ func (h *Handler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	deleted, err := h.storage.DeleteCategory(id, userID.(int))
-	if err != nil {
+	if h.rejectSystemCategory(c, id, userID.(int)) {
+		return
+	}
+
+	var deleted bool
+	if value := c.Query("reassign_to"); value != "" {
+		targetID, err := strconv.Atoi(value)
+		if err != nil || targetID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassign_to"})
+			return
+		}
+		deleted, err = h.storage.DeleteCategoryReassigning(id, userID.(int), targetID)
+		if err != nil {
+			if strings.Contains(err.Error(), "target category") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+	} else if deleted, err = h.storage.DeleteCategory(id, userID.(int)); err != nil {
 		if strings.Contains(err.Error(), "category is used in transactions") {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "category is used in transactions"})
 		} else {
@@ -354,19 +619,70 @@ func (h *Handler) DeleteCategory(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// parseSortBy разбирает параметры sort_by и order. Допустимость ключей
+// проверяется здесь же, чтобы вернуть 400, а не ошибку хранилища.
+func parseSortBy(sortBy, order string) ([]db.SortKey, error) {
+	fields := strings.Split(sortBy, ",")
+	var orders []string
+	if order != "" {
+		orders = strings.Split(order, ",")
+	}
+	if len(orders) > 1 && len(orders) != len(fields) {
+		return nil, fmt.Errorf("order must have one value or one value per sort_by field")
+	}
+
+	keys := make([]db.SortKey, len(fields))
+	seen := make(map[string]bool)
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		if field != "date" && field != "amount" && field != "category" {
+			return nil, fmt.Errorf("sort_by must be one of: date, amount, category")
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("duplicate sort_by field: %s", field)
+		}
+		seen[field] = true
+
+		direction := "asc"
+		if len(orders) == 1 {
+			direction = strings.TrimSpace(orders[0])
+		} else if len(orders) > 1 {
+			direction = strings.TrimSpace(orders[i])
+		}
+		if direction != "asc" && direction != "desc" {
+			return nil, fmt.Errorf("order must be 'asc' or 'desc'")
+		}
+		keys[i] = db.SortKey{Field: field, Desc: direction == "desc"}
+	}
+	return keys, nil
+}
+
 // @Security ApiKeyAuth
 // @Summary Получить список транзакций
-// @Description Получает список транзакций пользователя с возможностью фильтрации и пагинации
+// @Description Получает список транзакций пользователя с возможностью фильтрации и пагинации.
+// @Description converted_totals содержит суммы доходов и расходов по фильтру в базовой валюте пользователя.
 // @Tags transactions
 // @Produce json
 // @Param type query string false "Тип транзакции (income или expense)"
 // @Param category_id query int false "ID категории"
+// @Param payee_id query int false "ID контрагента"
 // @Param min_amount query number false "Минимальная сумма"
 // @Param max_amount query number false "Максимальная сумма"
+// @Param q query string false "Подстрока для поиска в описании"
+// @Param tags query string false "Имена тегов через запятую (достаточно совпадения с любым)"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Param status query string false "Статус сверки (pending, cleared или reconciled)"
+// @Param possible_duplicate query bool false "Только транзакции, отмеченные как возможные дубликаты"
+// @Param flagged query bool false "Только транзакции, отмеченные для проверки"
 // @Param sort query string false "Сортировка по дате (asc или desc)"
+// @Param sort_by query string false "Ключи сортировки через запятую: date, amount, category. Несовместим с sort"
+// @Param order query string false "Направления для ключей sort_by через запятую (asc или desc); одно значение применяется ко всем ключам"
 // @Param page query int false "Номер страницы"
+// @Param after query string false "Курсор из next_cursor предыдущей страницы; пустое значение — первая страница. Несовместим с page"
 // @Param limit query int false "Лимит на страницу"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
 // @Success 200 {object} models.GetTransactionsResponse"
+// @Success 304 "Страница не изменилась"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Router /transactions [get]
@@ -386,7 +702,7 @@ func (h *Handler) GetTransactions(c *gin.Context) {
 	limitStr := c.Query("limit")
 
 	var filterCategoryID int
-	var minAmount, maxAmount float64
+	var minAmount, maxAmount models.Money
 	var page, limit int
 	var err error
 
@@ -412,7 +728,7 @@ func (h *Handler) GetTransactions(c *gin.Context) {
 	}
 
 	if minAmountStr != "" {
-		minAmount, err = strconv.ParseFloat(minAmountStr, 64)
+		minAmount, err = models.ParseMoney(minAmountStr)
 		if err != nil || minAmount < 0 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_amount"})
 			return
@@ -420,7 +736,7 @@ func (h *Handler) GetTransactions(c *gin.Context) {
 	}
 
 	if maxAmountStr != "" {
-		maxAmount, err = strconv.ParseFloat(maxAmountStr, 64)
+		maxAmount, err = models.ParseMoney(maxAmountStr)
 		if err != nil || maxAmount < 0 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid max_amount"})
 			return
@@ -437,6 +753,22 @@ func (h *Handler) GetTransactions(c *gin.Context) {
 		return
 	}
 
+	var sortBy []db.SortKey
+	if sortByStr := c.Query("sort_by"); sortByStr != "" {
+		if sort != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sort and sort_by cannot be used together"})
+			return
+		}
+		sortBy, err = parseSortBy(sortByStr, c.Query("order"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else if c.Query("order") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order requires sort_by"})
+		return
+	}
+
 	if pageStr == "" {
 		page = 1
 	} else {
@@ -457,16 +789,115 @@ func (h *Handler) GetTransactions(c *gin.Context) {
 		}
 	}
 
-	transactions, total, err := h.storage.GetTransactions(userID.(int), filterType, filterCategoryID, minAmount, maxAmount, sort, page, limit)
+	var tags []string
+	if tagsStr := c.Query("tags"); tagsStr != "" {
+		for _, tag := range strings.Split(tagsStr, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	var payeeID int
+	if payeeIDStr := c.Query("payee_id"); payeeIDStr != "" {
+		payeeID, err = strconv.Atoi(payeeIDStr)
+		if err != nil || payeeID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payee_id"})
+			return
+		}
+	}
+
+	includePlanned, err := parseIncludePlanned(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"transactions": transactions,
-		"total":        total,
-	})
+	var possibleDuplicate bool
+	if value := c.Query("possible_duplicate"); value != "" {
+		possibleDuplicate, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "possible_duplicate must be 'true' or 'false'"})
+			return
+		}
+	}
+
+	var flagged bool
+	if value := c.Query("flagged"); value != "" {
+		flagged, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "flagged must be 'true' or 'false'"})
+			return
+		}
+	}
+
+	status := c.Query("status")
+	if status != "" {
+		if err := validateStatus(status); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	filter := db.TransactionFilter{
+		Type:              filterType,
+		CategoryID:        filterCategoryID,
+		PayeeID:           payeeID,
+		PossibleDuplicate: possibleDuplicate,
+		Flagged:           flagged,
+		MinAmount:         minAmount,
+		MaxAmount:         maxAmount,
+		Query:             c.Query("q"),
+		Tags:              tags,
+		IncludePlanned:    includePlanned,
+		Status:            status,
+		Sort:              sort,
+		SortBy:            sortBy,
+	}
+
+	var transactions []models.Transaction
+	var total int
+	var nextCursor string
+	if afterStr, ok := c.GetQuery("after"); ok {
+		// Курсорная пагинация: запрашиваем на одну транзакцию больше, чтобы узнать, есть ли следующая страница
+		if pageStr != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page and after cannot be used together"})
+			return
+		}
+		if len(sortBy) > 1 || (len(sortBy) == 1 && sortBy[0].Field != "date") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cursor pagination supports only sorting by date"})
+			return
+		}
+		after, err := decodeCursor(afterStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		transactions, total, err = h.storage.GetTransactionsAfter(userID.(int), filter, after, limit+1)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(transactions) > limit {
+			transactions = transactions[:limit]
+			nextCursor = encodeCursor(transactions[limit-1])
+		}
+	} else {
+		transactions, total, err = h.storage.GetTransactions(userID.(int), filter, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	response := models.GetTransactionsResponse{Transactions: transactions, Total: total, NextCursor: nextCursor}
+	// Итоги не обязательны: при недоступных курсах список возвращается без них
+	response.ConvertedTotals, err = h.convertedTotals(c.Request.Context(), userID.(int), filter)
+	if err != nil {
+		log.Printf("failed to convert totals for user %d: %v", userID.(int), err)
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // @Security ApiKeyAuth
@@ -508,14 +939,18 @@ func (h *Handler) GetTransaction(c *gin.Context) {
 
 // @Security ApiKeyAuth
 // @Summary Создать новую транзакцию
-// @Description Создает новую транзакцию для пользователя
+// @Description Создает новую транзакцию для пользователя. Если найдены похожие транзакции (та же сумма, тип, валюта
+// @Description и контрагент, дата в пределах суток), транзакция создается с possible_duplicate и ID похожих в duplicate_of,
+// @Description а при on_duplicate=reject не создается и возвращается 409.
 // @Tags transactions
 // @Accept json
 // @Produce json
-// @Param transaction body models.CreateTransaction true "Данные транзакции"
+// @Param transaction body models.CreateTransactionRequest true "Данные транзакции"
+// @Param on_duplicate query string false "Реакция на возможный дубликат: flag (по умолчанию) или reject"
 // @Success 201 {object} models.Transaction
-// @Failure 400 {object} models.ErrorResponse
+// @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.DuplicateConflictResponse
 // @Router /transactions [post]
 func (h *Handler) CreateTransaction(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -524,34 +959,46 @@ func (h *Handler) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	var newTransaction = models.Transaction{}
-	if err := c.ShouldBindJSON(&newTransaction); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request models.CreateTransactionRequest
+	if !bindJSON(c, &request) {
 		return
 	}
 
-	if err := validateTransaction(newTransaction); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	onDuplicate := c.DefaultQuery("on_duplicate", "flag")
+	if onDuplicate != "flag" && onDuplicate != "reject" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "on_duplicate must be 'flag' or 'reject'"})
 		return
 	}
 
+	newTransaction := request.Transaction()
 	newTransaction.UserID = userID.(int)
 	if newTransaction.Date.IsZero() {
 		newTransaction.Date = time.Now()
 	}
 
-	if err := h.storage.CreateTransaction(&newTransaction); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if onDuplicate == "reject" {
+		duplicates, err := h.storage.CreateTransactionUnlessDuplicate(&newTransaction)
+		if err != nil {
+			c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if len(duplicates) > 0 {
+			c.JSON(http.StatusConflict, models.DuplicateConflictResponse{Error: "possible duplicate", DuplicateIDs: duplicates})
+			return
+		}
+	} else if err := h.storage.CreateTransaction(&newTransaction); err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	h.transactionsCreated(userID.(int), &newTransaction)
 	c.JSON(http.StatusCreated, newTransaction)
 
 }
 
 // @Security ApiKeyAuth
 // @Summary Удалить транзакцию
-// @Description Удаляет транзакцию пользователя
+// @Description Перемещает транзакцию пользователя в корзину
 // @Tags transactions
 // @Produce json
 // @Param id path int true "ID транзакции"
@@ -576,7 +1023,7 @@ func (h *Handler) DeleteTransaction(c *gin.Context) {
 
 	ok, err := h.storage.DeleteTransaction(id, userID.(int))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	if ok == false {
@@ -594,9 +1041,9 @@ func (h *Handler) DeleteTransaction(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID транзакции"
-// @Param transaction body models.CreateTransaction true "Новые данные транзакции"
+// @Param transaction body models.CreateTransactionRequest true "Новые данные транзакции"
 // @Success 200 {object} models.Transaction
-// @Failure 400 {object} models.ErrorResponse
+// @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /transactions/{id} [put]
@@ -624,26 +1071,21 @@ func (h *Handler) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	var updatedTransaction models.Transaction
-	if err := c.ShouldBindJSON(&updatedTransaction); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request models.CreateTransactionRequest
+	if !bindJSON(c, &request) {
 		return
 	}
+	updatedTransaction := request.Transaction()
 	updatedTransaction.ID = id
 	updatedTransaction.UserID = userID.(int)
 
-	if err := validateTransaction(updatedTransaction); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
 	if updatedTransaction.Date.IsZero() {
 		updatedTransaction.Date = time.Now()
 	}
 
 	ok, err := h.storage.UpdateTransaction(&updatedTransaction)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	if ok == false {
@@ -16,6 +16,23 @@ import (
 	"github.com/nemopss/fin-ng/backend/models"
 )
 
+// fakeMailer запоминает отправленные письма вместо их отправки.
+type fakeMailer struct {
+	sent []sentMail
+}
+
+type sentMail struct {
+	To, Subject, Body string
+}
+
+func (m *fakeMailer) Send(to, subject, body string) error {
+	m.sent = append(m.sent, sentMail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// testMailer — почтовый ящик обработчика, созданного последним вызовом setupTestHandler.
+var testMailer *fakeMailer
+
 // setupTestHandler инициализирует тестовую среду, создавая новый роутер Gin и подключение к тестовой базе данных.
 // Очищает таблицы перед тестами и настраивает маршруты API с middleware аутентификации.
 func setupTestHandler(t *testing.T) (*gin.Engine, *db.Storage) {
@@ -27,7 +44,7 @@ func setupTestHandler(t *testing.T) (*gin.Engine, *db.Storage) {
 
 	// Получаем строку подключения к тестовой базе данных
 	connStr := os.Getenv("POSTGRES_TEST_URL")
-	storage, err := db.NewStorage(connStr)
+	storage, err := db.NewStorage(connStr, db.PoolConfig{})
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
@@ -45,24 +62,11 @@ func setupTestHandler(t *testing.T) (*gin.Engine, *db.Storage) {
 	}
 
 	// Создаем новый обработчик с подключением к БД и JWT-секретом
-	handler := NewHandler(storage, jwtSecret)
+	testMailer = &fakeMailer{}
+	handler := NewHandler(storage, Config{JWTSecret: jwtSecret, Mailer: testMailer})
 	r := gin.Default()
-	// Регистрируем маршруты для регистрации и логина
-	r.POST("/register", handler.Register)
-	r.POST("/login", handler.Login)
-
-	// Настраиваем защищенные маршруты с middleware аутентификации
-	protected := r.Group("/", handler.AuthMiddleware())
-	protected.GET("/transactions", handler.GetTransactions)
-	protected.POST("/transactions", handler.CreateTransaction)
-	protected.GET("/transaction/:id", handler.GetTransaction)
-	protected.DELETE("/transaction/:id", handler.DeleteTransaction)
-	protected.PUT("/transaction/:id", handler.UpdateTransaction)
-	protected.POST("/categories", handler.CreateCategory)
-	protected.GET("/categories", handler.GetCategories)
-	protected.GET("/categories/:id", handler.GetCategory)
-	protected.PUT("/categories/:id", handler.UpdateCategory)
-	protected.DELETE("/categories/:id", handler.DeleteCategory)
+	// Регистрируем маршруты API без префикса версии, чтобы не дублировать его в тестах
+	handler.RegisterRoutes(r.Group("/"))
 
 	return r, storage
 }
@@ -81,12 +85,12 @@ func getToken(t *testing.T, r *gin.Engine, username, password string) string {
 		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
 	}
 
-	var response map[string]string
+	var response models.LoginResponse
 	// Декодируем ответ для получения токена
 	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
-	return response["token"]
+	return response.Token
 }
 
 // TestRegister тестирует функционал регистрации пользователей.
@@ -164,12 +168,12 @@ func TestLogin(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
-	var response map[string]string
+	var response models.LoginResponse
 	// Проверяем, что получен токен
 	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
-	if response["token"] == "" {
+	if response.Token == "" {
 		t.Error("Expected token, got empty")
 	}
 
@@ -187,6 +191,49 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+// TestLoginRememberMe тестирует выдачу долгоживущего токена при remember_me.
+func TestLoginRememberMe(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	// Создаем тестового пользователя
+	if _, err := storage.CreateUser("testuser", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	login := func(rememberMe bool) models.LoginResponse {
+		credentials := models.LoginRequest{Username: "testuser", Password: "password123", RememberMe: rememberMe}
+		body, _ := json.Marshal(credentials)
+		req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response models.LoginResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	// Обычный токен живет 24 часа по умолчанию
+	regular := login(false)
+	expected := time.Now().Add(defaultTokenTTL).Unix()
+	if regular.ExpiresAt < expected-60 || regular.ExpiresAt > expected+60 {
+		t.Errorf("Expected expires_at around %d, got %d", expected, regular.ExpiresAt)
+	}
+
+	// Токен с remember_me живет дольше
+	remembered := login(true)
+	expected = time.Now().Add(defaultRememberMeTTL).Unix()
+	if remembered.ExpiresAt < expected-60 || remembered.ExpiresAt > expected+60 {
+		t.Errorf("Expected expires_at around %d, got %d", expected, remembered.ExpiresAt)
+	}
+}
+
 // TestCategories тестирует функционал управления категориями (создание, получение, обновление, удаление).
 func TestCategories(t *testing.T) {
 	r, storage := setupTestHandler(t)
@@ -329,7 +376,7 @@ func TestCategories(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create category: %v", err)
 	}
-	transaction := models.Transaction{UserID: user.ID, Amount: 100, Type: "expense", CategoryID: newCategory.ID, Date: time.Now()}
+	transaction := models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: newCategory.ID, Date: time.Now()}
 	if err := storage.CreateTransaction(&transaction); err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
@@ -363,11 +410,8 @@ func TestCategories(t *testing.T) {
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 	}
-	if err := json.NewDecoder(w.Body).Decode(&errorResponse); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-	if errorResponse["error"] != "category name is required" {
-		t.Errorf("Expected error 'category name is required', got %v", errorResponse["error"])
+	if fields := decodeFieldErrors(t, w); fields["name"] != "name is required" {
+		t.Errorf("Expected error 'name is required', got %v", fields)
 	}
 
 	// Тестируем доступ к категориям без токена
@@ -464,7 +508,7 @@ func TestCreateTransaction(t *testing.T) {
 	}
 
 	// Тестируем создание транзакции
-	transaction := models.Transaction{Amount: 200.75, Type: "expense", CategoryID: category.ID, Date: time.Now()}
+	transaction := models.Transaction{Amount: models.NewMoney(200, 75), Type: "expense", CategoryID: category.ID, Date: time.Now()}
 	body, _ := json.Marshal(transaction)
 	req, _ := http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -483,12 +527,12 @@ func TestCreateTransaction(t *testing.T) {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
 
-	if createdTransaction.UserID != user.ID || createdTransaction.Amount != 200.75 || createdTransaction.Type != "expense" || createdTransaction.CategoryID != category.ID {
-		t.Errorf("Expected transaction {UserID: %d, Amount: 200.75, Type: expense, CategoryID: %d}, got %+v", user.ID, category.ID, createdTransaction)
+	if createdTransaction.UserID != user.ID || createdTransaction.Amount != models.NewMoney(200, 75) || createdTransaction.Type != "expense" || createdTransaction.CategoryID != category.ID {
+		t.Errorf("Expected transaction {UserID: %d, Amount: models.NewMoney(200, 75), Type: expense, CategoryID: %d}, got %+v", user.ID, category.ID, createdTransaction)
 	}
 
 	// Проверяем, что транзакция сохранена в базе
-	transactions, total, err := storage.GetTransactions(user.ID, "", 0, 0, 0, "", 1, 10)
+	transactions, total, err := storage.GetTransactions(user.ID, db.TransactionFilter{}, 1, 10)
 	if err != nil {
 		t.Fatalf("Failed to get transactions: %v", err)
 	}
@@ -500,7 +544,7 @@ func TestCreateTransaction(t *testing.T) {
 	}
 
 	// Тестируем создание транзакции без категории
-	transactionWithoutCategory := models.Transaction{Amount: 300.00, Type: "income", CategoryID: 0, Date: time.Now()}
+	transactionWithoutCategory := models.Transaction{Amount: models.NewMoney(300, 0), Type: "income", CategoryID: 0, Date: time.Now()}
 	body, _ = json.Marshal(transactionWithoutCategory)
 	req, _ = http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -512,17 +556,12 @@ func TestCreateTransaction(t *testing.T) {
 	if w.Code != http.StatusBadRequest {
 		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 	}
-
-	var errorResp gin.H
-	if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-	if errorResp["error"] != "category_id is required and must be positive" {
-		t.Errorf("Expected error 'category_id is required and must be positive', got %v", errorResp["error"])
+	if fields := decodeFieldErrors(t, w); fields["category_id"] != "category_id must be positive" {
+		t.Errorf("Expected error 'category_id must be positive', got %v", fields)
 	}
 
 	// Тестируем создание транзакции с отрицательной суммой
-	invalidTransaction := models.Transaction{Amount: -100, Type: "expense", CategoryID: category.ID, Date: time.Now()}
+	invalidTransaction := models.Transaction{Amount: models.NewMoney(-100, 0), Type: "expense", CategoryID: category.ID, Date: time.Now()}
 	body, _ = json.Marshal(invalidTransaction)
 	req, _ = http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -536,15 +575,12 @@ func TestCreateTransaction(t *testing.T) {
 	}
 
 	var errorResponse gin.H
-	if err := json.NewDecoder(w.Body).Decode(&errorResponse); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-	if errorResponse["error"] != "amount must be positive" {
-		t.Errorf("Expected error 'amount must be positive', got %v", errorResponse["error"])
+	if fields := decodeFieldErrors(t, w); fields["amount"] != "amount must be positive" {
+		t.Errorf("Expected error 'amount must be positive', got %v", fields)
 	}
 
 	// Тестируем создание транзакции с некорректным типом
-	invalidTransaction = models.Transaction{Amount: 100, Type: "invalid", CategoryID: category.ID, Date: time.Now()}
+	invalidTransaction = models.Transaction{Amount: models.NewMoney(100, 0), Type: "invalid", CategoryID: category.ID, Date: time.Now()}
 	body, _ = json.Marshal(invalidTransaction)
 	req, _ = http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -557,15 +593,12 @@ func TestCreateTransaction(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 	}
 
-	if err := json.NewDecoder(w.Body).Decode(&errorResponse); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-	if errorResponse["error"] != "type must be 'income' or 'expense'" {
-		t.Errorf("Expected error 'type must be 'income' or 'expense'', got %v", errorResponse["error"])
+	if fields := decodeFieldErrors(t, w); fields["type"] != "type must be 'income' or 'expense'" {
+		t.Errorf("Expected error 'type must be 'income' or 'expense'', got %v", fields)
 	}
 
 	// Тестируем создание транзакции с несуществующей категорией
-	invalidTransaction = models.Transaction{Amount: 100, Type: "expense", CategoryID: 999, Date: time.Now()}
+	invalidTransaction = models.Transaction{Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: 999, Date: time.Now()}
 	body, _ = json.Marshal(invalidTransaction)
 	req, _ = http.NewRequest("POST", "/transactions", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -625,10 +658,10 @@ func TestGetTransactions(t *testing.T) {
 	now := time.Now()
 	// Создаем тестовые транзакции
 	transactions := []models.Transaction{
-		{UserID: user.ID, Amount: 100.50, Type: "income", CategoryID: foodCategory.ID, Date: now.Add(-3 * time.Hour)},
-		{UserID: user.ID, Amount: 200.75, Type: "expense", CategoryID: transportCategory.ID, Date: now.Add(-2 * time.Hour)},
-		{UserID: user.ID, Amount: 300.00, Type: "income", CategoryID: foodCategory.ID, Date: now.Add(-1 * time.Hour)},
-		{UserID: user.ID, Amount: 400.25, Type: "expense", CategoryID: transportCategory.ID, Date: now},
+		{UserID: user.ID, Amount: models.NewMoney(100, 50), Type: "income", CategoryID: foodCategory.ID, Date: now.Add(-3 * time.Hour)},
+		{UserID: user.ID, Amount: models.NewMoney(200, 75), Type: "expense", CategoryID: transportCategory.ID, Date: now.Add(-2 * time.Hour)},
+		{UserID: user.ID, Amount: models.NewMoney(300, 0), Type: "income", CategoryID: foodCategory.ID, Date: now.Add(-1 * time.Hour)},
+		{UserID: user.ID, Amount: models.NewMoney(400, 25), Type: "expense", CategoryID: transportCategory.ID, Date: now},
 	}
 	for _, tx := range transactions {
 		if err := storage.CreateTransaction(&tx); err != nil {
@@ -661,7 +694,7 @@ func TestGetTransactions(t *testing.T) {
 	if len(response.Transactions) != 2 {
 		t.Errorf("Expected 2 transactions, got %d", len(response.Transactions))
 	}
-	if response.Transactions[0].Amount != 100.50 || response.Transactions[1].Amount != 200.75 {
+	if response.Transactions[0].Amount != models.NewMoney(100, 50) || response.Transactions[1].Amount != models.NewMoney(200, 75) {
 		t.Errorf("Expected transactions [100.50, 200.75], got %+v", response.Transactions)
 	}
 
@@ -684,7 +717,7 @@ func TestGetTransactions(t *testing.T) {
 	if len(response.Transactions) != 2 {
 		t.Errorf("Expected 2 transactions, got %d", len(response.Transactions))
 	}
-	if response.Transactions[0].Amount != 300.00 || response.Transactions[1].Amount != 400.25 {
+	if response.Transactions[0].Amount != models.NewMoney(300, 0) || response.Transactions[1].Amount != models.NewMoney(400, 25) {
 		t.Errorf("Expected transactions [300.00, 400.25], got %+v", response.Transactions)
 	}
 
@@ -810,13 +843,13 @@ func TestGetTransaction(t *testing.T) {
 	}
 
 	// Создаем транзакцию
-	transaction := models.Transaction{UserID: user.ID, Amount: 100.50, Type: "income", CategoryID: category.ID, Date: time.Now()}
+	transaction := models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 50), Type: "income", CategoryID: category.ID, Date: time.Now()}
 	if err := storage.CreateTransaction(&transaction); err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
 
 	// Тестируем получение транзакции
-	req, _ := http.NewRequest("GET", "/transaction/1", nil)
+	req, _ := http.NewRequest("GET", "/transactions/1", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -831,12 +864,12 @@ func TestGetTransaction(t *testing.T) {
 	if err := json.NewDecoder(w.Body).Decode(&fetchedTransaction); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
-	if fetchedTransaction.UserID != user.ID || fetchedTransaction.Amount != 100.50 || fetchedTransaction.Type != "income" || fetchedTransaction.CategoryID != category.ID {
-		t.Errorf("Expected transaction {UserID: %d, Amount: 100.50, Type: income, CategoryID: %d}, got %+v", user.ID, category.ID, fetchedTransaction)
+	if fetchedTransaction.UserID != user.ID || fetchedTransaction.Amount != models.NewMoney(100, 50) || fetchedTransaction.Type != "income" || fetchedTransaction.CategoryID != category.ID {
+		t.Errorf("Expected transaction {UserID: %d, Amount: models.NewMoney(100, 50), Type: income, CategoryID: %d}, got %+v", user.ID, category.ID, fetchedTransaction)
 	}
 
 	// Тестируем запрос несуществующей транзакции
-	req, _ = http.NewRequest("GET", "/transaction/999", nil)
+	req, _ = http.NewRequest("GET", "/transactions/999", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -847,7 +880,7 @@ func TestGetTransaction(t *testing.T) {
 	}
 
 	// Тестируем запрос без токена
-	req, _ = http.NewRequest("GET", "/transaction/1", nil)
+	req, _ = http.NewRequest("GET", "/transactions/1", nil)
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -878,13 +911,13 @@ func TestDeleteTransaction(t *testing.T) {
 	}
 
 	// Создаем транзакцию
-	transaction := models.Transaction{UserID: user.ID, Amount: 100.50, Type: "income", CategoryID: category.ID, Date: time.Now()}
+	transaction := models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 50), Type: "income", CategoryID: category.ID, Date: time.Now()}
 	if err := storage.CreateTransaction(&transaction); err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
 
 	// Тестируем удаление транзакции
-	req, _ := http.NewRequest("DELETE", "/transaction/1", nil)
+	req, _ := http.NewRequest("DELETE", "/transactions/1", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -895,7 +928,7 @@ func TestDeleteTransaction(t *testing.T) {
 	}
 
 	// Проверяем, что транзакция удалена из базы
-	_, total, err := storage.GetTransactions(user.ID, "", 0, 0, 0, "", 1, 10)
+	_, total, err := storage.GetTransactions(user.ID, db.TransactionFilter{}, 1, 10)
 	if err != nil {
 		t.Fatalf("Failed to get transactions: %v", err)
 	}
@@ -904,7 +937,7 @@ func TestDeleteTransaction(t *testing.T) {
 	}
 
 	// Тестируем удаление несуществующей транзакции
-	req, _ = http.NewRequest("DELETE", "/transaction/999", nil)
+	req, _ = http.NewRequest("DELETE", "/transactions/999", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
@@ -915,7 +948,7 @@ func TestDeleteTransaction(t *testing.T) {
 	}
 
 	// Тестируем удаление без токена
-	req, _ = http.NewRequest("DELETE", "/transaction/1", nil)
+	req, _ = http.NewRequest("DELETE", "/transactions/1", nil)
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
@@ -951,15 +984,15 @@ func TestUpdateTransaction(t *testing.T) {
 	}
 
 	// Создаем транзакцию
-	transaction := models.Transaction{UserID: user.ID, Amount: 100.50, Type: "income", CategoryID: foodCategory.ID, Date: time.Now()}
+	transaction := models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 50), Type: "income", CategoryID: foodCategory.ID, Date: time.Now()}
 	if err := storage.CreateTransaction(&transaction); err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
 
 	// Тестируем обновление транзакции
-	updatedTransaction := models.Transaction{Amount: 200.75, Type: "expense", CategoryID: transportCategory.ID, Date: time.Now().Add(time.Hour)}
+	updatedTransaction := models.Transaction{Amount: models.NewMoney(200, 75), Type: "expense", CategoryID: transportCategory.ID, Date: time.Now().Add(time.Hour)}
 	body, _ := json.Marshal(updatedTransaction)
-	req, _ := http.NewRequest("PUT", "/transaction/1", bytes.NewBuffer(body))
+	req, _ := http.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
@@ -975,14 +1008,14 @@ func TestUpdateTransaction(t *testing.T) {
 	if err := json.NewDecoder(w.Body).Decode(&fetchedTransaction); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
-	if fetchedTransaction.UserID != user.ID || fetchedTransaction.Amount != 200.75 || fetchedTransaction.Type != "expense" || fetchedTransaction.CategoryID != transportCategory.ID {
-		t.Errorf("Expected transaction {UserID: %d, Amount: 200.75, Type: expense, CategoryID: %d}, got %+v", user.ID, transportCategory.ID, fetchedTransaction)
+	if fetchedTransaction.UserID != user.ID || fetchedTransaction.Amount != models.NewMoney(200, 75) || fetchedTransaction.Type != "expense" || fetchedTransaction.CategoryID != transportCategory.ID {
+		t.Errorf("Expected transaction {UserID: %d, Amount: models.NewMoney(200, 75), Type: expense, CategoryID: %d}, got %+v", user.ID, transportCategory.ID, fetchedTransaction)
 	}
 
 	// Тестируем обновление с некорректной категорией (CategoryID = 0)
-	updatedTransaction = models.Transaction{Amount: 300.00, Type: "income", CategoryID: 0, Date: time.Now().Add(2 * time.Hour)}
+	updatedTransaction = models.Transaction{Amount: models.NewMoney(300, 0), Type: "income", CategoryID: 0, Date: time.Now().Add(2 * time.Hour)}
 	body, _ = json.Marshal(updatedTransaction)
-	req, _ = http.NewRequest("PUT", "/transaction/1", bytes.NewBuffer(body))
+	req, _ = http.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
@@ -993,18 +1026,14 @@ func TestUpdateTransaction(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 	}
 
-	var errorResp gin.H
-	if err := json.NewDecoder(w.Body).Decode(&errorResp); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-	if errorResp["error"] != "category_id is required and must be positive" {
-		t.Errorf("Expected error 'category_id is required and must be positive', got %v", errorResp["error"])
+	if fields := decodeFieldErrors(t, w); fields["category_id"] != "category_id must be positive" {
+		t.Errorf("Expected error 'category_id must be positive', got %v", fields)
 	}
 
 	// Тестируем обновление с несуществующей категорией
-	invalidTransaction := models.Transaction{Amount: 200.75, Type: "expense", CategoryID: 999, Date: time.Now()}
+	invalidTransaction := models.Transaction{Amount: models.NewMoney(200, 75), Type: "expense", CategoryID: 999, Date: time.Now()}
 	body, _ = json.Marshal(invalidTransaction)
-	req, _ = http.NewRequest("PUT", "/transaction/1", bytes.NewBuffer(body))
+	req, _ = http.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
@@ -1024,9 +1053,9 @@ func TestUpdateTransaction(t *testing.T) {
 	}
 
 	// Тестируем обновление с отрицательной суммой
-	invalidTransaction = models.Transaction{Amount: -100, Type: "expense", CategoryID: foodCategory.ID, Date: time.Now()}
+	invalidTransaction = models.Transaction{Amount: models.NewMoney(-100, 0), Type: "expense", CategoryID: foodCategory.ID, Date: time.Now()}
 	body, _ = json.Marshal(invalidTransaction)
-	req, _ = http.NewRequest("PUT", "/transaction/1", bytes.NewBuffer(body))
+	req, _ = http.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
@@ -1037,16 +1066,13 @@ func TestUpdateTransaction(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
 	}
 
-	if err := json.NewDecoder(w.Body).Decode(&errorResponse); err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-	if errorResponse["error"] != "amount must be positive" {
-		t.Errorf("Expected error 'amount must be positive', got %v", errorResponse["error"])
+	if fields := decodeFieldErrors(t, w); fields["amount"] != "amount must be positive" {
+		t.Errorf("Expected error 'amount must be positive', got %v", fields)
 	}
 
 	// Тестируем обновление несуществующей транзакции
 	body, _ = json.Marshal(updatedTransaction)
-	req, _ = http.NewRequest("PUT", "/transaction/999", bytes.NewBuffer(body))
+	req, _ = http.NewRequest("PUT", "/transactions/999", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 	w = httptest.NewRecorder()
@@ -1058,7 +1084,7 @@ func TestUpdateTransaction(t *testing.T) {
 	}
 
 	// Тестируем обновление без токена
-	req, _ = http.NewRequest("PUT", "/transaction/1", bytes.NewBuffer(body))
+	req, _ = http.NewRequest("PUT", "/transactions/1", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
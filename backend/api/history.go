@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Security ApiKeyAuth
+// @Summary Получить историю изменений транзакции
+// @Description Получает версии транзакции от первой к последней: состояние после каждого изменения и список изменившихся полей
+// @Tags transactions
+// @Produce json
+// @Param id path int true "ID транзакции"
+// @Success 200 {array} models.TransactionVersion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id}/history [get]
+func (h *Handler) GetTransactionHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	versions, err := h.storage.GetTransactionHistory(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if versions == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// @Security ApiKeyAuth
+// @Summary Откатить транзакцию к версии
+// @Description Возвращает транзакции состояние из указанной версии истории. Откат сохраняется в истории новой версией.
+// @Description Если контрагент версии был удален, он создается заново по имени.
+// @Tags transactions
+// @Produce json
+// @Param id path int true "ID транзакции"
+// @Param version path int true "Номер версии"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id}/revert/{version} [post]
+func (h *Handler) RevertTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	transaction, err := h.storage.GetTransactionVersion(id, userID.(int), version)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if transaction == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		return
+	}
+
+	// Контрагент восстанавливается по имени: с момента версии он мог быть удален.
+	// Запланированной транзакция остается, только если ее дата еще не наступила.
+	transaction.PayeeID = 0
+	transaction.Planned = transaction.Planned && transaction.Date.After(time.Now())
+	if err := validateTransaction(*transaction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ok, err := h.storage.UpdateTransaction(transaction)
+	if err != nil {
+		if strings.Contains(err.Error(), "category does not exist") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestTransactionHistory тестирует историю изменений транзакции и откат к версии.
+func TestTransactionHistory(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/transactions", fmt.Sprintf(`{"amount": 100, "type": "expense", "category_id": %d, "description": "Обед", "tags": ["work"]}`, category.ID))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created models.Transaction
+	json.NewDecoder(w.Body).Decode(&created)
+	url := fmt.Sprintf("/transactions/%d", created.ID)
+
+	if w := do("PATCH", url, `{"amount": 120, "description": "Обед с коллегами"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w := do("PATCH", url, `{"tags": []}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	history := func() []models.TransactionVersion {
+		w := do("GET", url+"/history", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var versions []models.TransactionVersion
+		json.NewDecoder(w.Body).Decode(&versions)
+		return versions
+	}
+	versions := history()
+	if len(versions) != 3 {
+		t.Fatalf("Expected 3 versions, got %d", len(versions))
+	}
+	if !reflect.DeepEqual(versions[1].ChangedFields, []string{"amount", "description"}) ||
+		!reflect.DeepEqual(versions[2].ChangedFields, []string{"tags"}) || versions[0].Transaction.Amount != models.NewMoney(100, 0) {
+		t.Errorf("Unexpected history: %+v", versions)
+	}
+
+	// Откат к первой версии возвращает сумму, описание и теги и сам становится новой версией
+	w = do("POST", url+"/revert/1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	saved, err := storage.GetTransaction(created.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if saved.Amount != models.NewMoney(100, 0) || saved.Description != "Обед" || !reflect.DeepEqual(saved.Tags, []string{"work"}) {
+		t.Errorf("Expected original state after revert, got %+v", saved)
+	}
+	if versions = history(); len(versions) != 4 {
+		t.Errorf("Expected 4 versions after revert, got %d", len(versions))
+	}
+
+	if w := do("POST", url+"/revert/10", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for missing version, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := do("POST", url+"/revert/abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid version, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := do("GET", fmt.Sprintf("/transactions/%d/history", created.ID+100), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for missing transaction, got %d", http.StatusNotFound, w.Code)
+	}
+}
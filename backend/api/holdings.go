@@ -0,0 +1,466 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/quotes"
+	"github.com/nemopss/fin-ng/backend/rates"
+)
+
+const (
+	// quoteTTL — сколько цена бумаги используется без повторного запроса к провайдеру.
+	quoteTTL = 15 * time.Minute
+)
+
+// holdingAccountTypes — типы счетов, на которых хранятся позиции.
+var holdingAccountTypes = map[string]bool{"investment": true, "crypto": true}
+
+var tickerPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{0,19}$`)
+
+// quoteCache хранит в памяти цены бумаг и запрашивает у провайдера только отсутствующие или устаревшие.
+type quoteCache struct {
+	mu       sync.Mutex
+	provider quotes.Provider
+	entries  map[string]cachedQuote
+}
+
+type cachedQuote struct {
+	quote     quotes.Quote
+	fetchedAt time.Time
+}
+
+func newQuoteCache(provider quotes.Provider) *quoteCache {
+	return &quoteCache{provider: provider, entries: make(map[string]cachedQuote)}
+}
+
+// get возвращает цены бумаг tickers. Если провайдер недоступен, используются ранее полученные цены;
+// без провайдера цен нет.
+func (qc *quoteCache) get(ctx context.Context, tickers []string) (map[string]quotes.Quote, error) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	result := make(map[string]quotes.Quote, len(tickers))
+	if qc.provider == nil {
+		return result, nil
+	}
+	var stale []string
+	for _, ticker := range tickers {
+		entry, ok := qc.entries[ticker]
+		if ok {
+			result[ticker] = entry.quote
+		}
+		if !ok || time.Since(entry.fetchedAt) > quoteTTL {
+			stale = append(stale, ticker)
+		}
+	}
+	if len(stale) == 0 {
+		return result, nil
+	}
+
+	fresh, err := qc.provider.Quotes(ctx, stale)
+	if err != nil {
+		if len(result) > 0 {
+			log.Printf("failed to refresh quotes, using cached prices: %v", err)
+			return result, nil
+		}
+		return nil, err
+	}
+	now := time.Now()
+	for ticker, quote := range fresh {
+		qc.entries[ticker] = cachedQuote{quote: quote, fetchedAt: now}
+		result[ticker] = quote
+	}
+	return result, nil
+}
+
+// valueHoldings оценивает позиции счета по ценам prices, пересчитывая цены в валюту счета по курсам exchangeRates.
+// Позиции без цены или курса оцениваются по сумме покупки.
+func valueHoldings(account *models.Account, holdings []models.Holding, prices map[string]quotes.Quote, exchangeRates map[string]float64) models.InvestmentValuation {
+	valuation := models.InvestmentValuation{AccountID: account.ID, Currency: account.Currency, Holdings: []models.HoldingValue{}}
+	for _, holding := range holdings {
+		value := models.HoldingValue{
+			HoldingID:   holding.ID,
+			Ticker:      holding.Ticker,
+			Quantity:    holding.Quantity,
+			CostBasis:   holding.CostBasis,
+			MarketValue: holding.CostBasis,
+		}
+		if quote, ok := prices[holding.Ticker]; ok {
+			if price, err := rates.Convert(exchangeRates, quote.Price, quote.Currency, account.Currency); err == nil {
+				value.Priced = true
+				value.Price = price
+				value.MarketValue = models.MoneyFromFloat(holding.Quantity * price)
+				value.UnrealizedPL = value.MarketValue - holding.CostBasis
+			}
+		}
+		valuation.CostBasis += value.CostBasis
+		valuation.MarketValue += value.MarketValue
+		valuation.UnrealizedPL += value.UnrealizedPL
+		valuation.Holdings = append(valuation.Holdings, value)
+	}
+	return valuation
+}
+
+// quotesFor возвращает котировки для позиций счета типа accountType: криптовалют или ценных бумаг.
+func (h *Handler) quotesFor(accountType string) *quoteCache {
+	if accountType == "crypto" {
+		return h.cryptoQuoteCache
+	}
+	return h.quoteCache
+}
+
+// investmentValuation оценивает позиции счета по текущим ценам. Курсы загружаются, только если
+// валюта цены отличается от валюты счета.
+func (h *Handler) investmentValuation(ctx context.Context, account *models.Account, holdings []models.Holding) (models.InvestmentValuation, error) {
+	tickers := make([]string, 0, len(holdings))
+	for _, holding := range holdings {
+		tickers = append(tickers, holding.Ticker)
+	}
+	prices, err := h.quotesFor(account.Type).get(ctx, tickers)
+	if err != nil {
+		return models.InvestmentValuation{}, err
+	}
+
+	var exchangeRates map[string]float64
+	for _, quote := range prices {
+		if quote.Currency != account.Currency {
+			if exchangeRates, err = h.rateCache.latest(ctx); err != nil {
+				return models.InvestmentValuation{}, err
+			}
+			break
+		}
+	}
+	return valueHoldings(account, holdings, prices, exchangeRates), nil
+}
+
+// holdingsMarketValue возвращает текущую стоимость позиций инвестиционных и криптовалютных счетов accounts по ID счета.
+// Если котировки недоступны, позиции оцениваются по сумме покупки.
+func (h *Handler) holdingsMarketValue(ctx context.Context, userID int, accounts []models.Account) (map[int]models.Money, error) {
+	byAccount := make(map[int][]models.Holding)
+	for _, account := range accounts {
+		if holdingAccountTypes[account.Type] {
+			byAccount[account.ID] = nil
+		}
+	}
+	if len(byAccount) == 0 {
+		return nil, nil
+	}
+	holdings, err := h.storage.GetUserHoldings(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, holding := range holdings {
+		if _, ok := byAccount[holding.AccountID]; ok {
+			byAccount[holding.AccountID] = append(byAccount[holding.AccountID], holding)
+		}
+	}
+
+	values := make(map[int]models.Money, len(byAccount))
+	for i := range accounts {
+		account := &accounts[i]
+		if len(byAccount[account.ID]) == 0 {
+			continue
+		}
+		valuation, err := h.investmentValuation(ctx, account, byAccount[account.ID])
+		if err != nil {
+			log.Printf("failed to value holdings of account %d, using cost basis: %v", account.ID, err)
+			valuation = valueHoldings(account, byAccount[account.ID], nil, nil)
+		}
+		values[account.ID] = valuation.MarketValue
+	}
+	return values, nil
+}
+
+// loadHoldingAccount читает инвестиционный или криптовалютный счет из параметра id; при ошибке или другом
+// типе счета отвечает клиенту и возвращает false. С open=true ошибкой считается и закрытый счет,
+// и счет, открытый пользователю только для просмотра.
+func (h *Handler) loadHoldingAccount(c *gin.Context, open bool) (*models.Account, bool) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return nil, false
+	}
+	if !holdingAccountTypes[account.Type] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account is not an investment or crypto account"})
+		return nil, false
+	}
+	if open && account.ClosedAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account is closed"})
+		return nil, false
+	}
+	if open && !canEditAccount(c, account) {
+		return nil, false
+	}
+	return account, true
+}
+
+// @Security ApiKeyAuth
+// @Summary Позиции счета
+// @Description Возвращает позиции инвестиционного или криптовалютного счета по тикеру
+// @Tags investments
+// @Produce json
+// @Param id path int true "ID инвестиционного или криптовалютного счета"
+// @Success 200 {array} models.Holding
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/holdings [get]
+func (h *Handler) GetHoldings(c *gin.Context) {
+	account, ok := h.loadHoldingAccount(c, false)
+	if !ok {
+		return
+	}
+
+	holdings, err := h.storage.GetHoldings(account.ID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, holdings)
+}
+
+// @Security ApiKeyAuth
+// @Summary Добавить позицию
+// @Description Добавляет на открытый инвестиционный или криптовалютный счет позицию: тикер бумаги или символ криптовалюты,
+// @Description количество и сумму покупки в валюте счета.
+// @Description Тикер приводится к верхнему регистру и уникален в пределах счета
+// @Tags investments
+// @Accept json
+// @Produce json
+// @Param id path int true "ID инвестиционного или криптовалютного счета"
+// @Param holding body models.CreateHolding true "Данные позиции"
+// @Success 201 {object} models.Holding
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/holdings [post]
+func (h *Handler) CreateHolding(c *gin.Context) {
+	account, ok := h.loadHoldingAccount(c, true)
+	if !ok {
+		return
+	}
+
+	var request models.CreateHolding
+	if !bindJSON(c, &request) {
+		return
+	}
+	request.Ticker = strings.ToUpper(strings.TrimSpace(request.Ticker))
+	if !tickerPattern.MatchString(request.Ticker) {
+		badRequest(c, fieldError("ticker", "ticker must be 1 to 20 letters, digits, '.', '_' or '-'"))
+		return
+	}
+
+	holding, err := h.storage.CreateHolding(c.GetInt("user_id"), account.ID, request)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "already exists") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if holding == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, holding)
+}
+
+// @Security ApiKeyAuth
+// @Summary Обновить позицию
+// @Description Изменяет количество и сумму покупки позиции открытого инвестиционного или криптовалютного счета
+// @Tags investments
+// @Accept json
+// @Produce json
+// @Param id path int true "ID инвестиционного или криптовалютного счета"
+// @Param holding_id path int true "ID позиции"
+// @Param holding body models.UpdateHolding true "Данные позиции"
+// @Success 200 {object} models.Holding
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/holdings/{holding_id} [put]
+func (h *Handler) UpdateHolding(c *gin.Context) {
+	account, ok := h.loadHoldingAccount(c, true)
+	if !ok {
+		return
+	}
+	holdingID, err := strconv.Atoi(c.Param("holding_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid holding id"})
+		return
+	}
+
+	var request models.UpdateHolding
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	updated, err := h.storage.UpdateHolding(holdingID, account.ID, c.GetInt("user_id"), request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "holding not found"})
+		return
+	}
+
+	holdings, err := h.storage.GetHoldings(account.ID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, holding := range holdings {
+		if holding.ID == holdingID {
+			c.JSON(http.StatusOK, holding)
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "holding not found"})
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить позицию
+// @Description Удаляет позицию открытого инвестиционного или криптовалютного счета
+// @Tags investments
+// @Produce json
+// @Param id path int true "ID инвестиционного или криптовалютного счета"
+// @Param holding_id path int true "ID позиции"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/holdings/{holding_id} [delete]
+func (h *Handler) DeleteHolding(c *gin.Context) {
+	account, ok := h.loadHoldingAccount(c, true)
+	if !ok {
+		return
+	}
+	holdingID, err := strconv.Atoi(c.Param("holding_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid holding id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteHolding(holdingID, account.ID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "holding not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Security ApiKeyAuth
+// @Summary Стоимость позиций счета
+// @Description Оценивает позиции инвестиционного или криптовалютного счета по текущим котировкам в валюте счета и считает нереализованную
+// @Description прибыль или убыток относительно суммы покупки. Позиции без котировки оцениваются по сумме покупки
+// @Tags investments
+// @Produce json
+// @Param id path int true "ID инвестиционного или криптовалютного счета"
+// @Success 200 {object} models.InvestmentValuation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /accounts/{id}/valuation [get]
+func (h *Handler) GetInvestmentValuation(c *gin.Context) {
+	account, ok := h.loadHoldingAccount(c, false)
+	if !ok {
+		return
+	}
+
+	holdings, err := h.storage.GetHoldings(account.ID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	valuation, err := h.investmentValuation(c.Request.Context(), account, holdings)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to load quotes: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, valuation)
+}
+
+// snapshotValuations сохраняет стоимость позиций открытых счетов за день date.
+// Снимок дня перезаписывается при каждом запуске, поэтому за прошедший день остается последняя оценка.
+// Счета, котировки для которых не настроены или недоступны, пропускаются.
+func (h *Handler) snapshotValuations(ctx context.Context, date time.Time) error {
+	accounts, err := h.storage.GetHoldingAccounts()
+	if err != nil {
+		return err
+	}
+	for i := range accounts {
+		account := &accounts[i]
+		if h.quotesFor(account.Type).provider == nil {
+			continue
+		}
+		holdings, err := h.storage.GetHoldings(account.ID, account.UserID)
+		if err != nil {
+			return err
+		}
+		valuation, err := h.investmentValuation(ctx, account, holdings)
+		if err != nil {
+			log.Printf("failed to value holdings of account %d: %v", account.ID, err)
+			continue
+		}
+		if err := h.storage.SaveValuation(account.ID, date, valuation.CostBasis, valuation.MarketValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// @Security ApiKeyAuth
+// @Summary История стоимости позиций счета
+// @Description Возвращает стоимость позиций инвестиционного или криптовалютного счета и сумму их покупки на конец каждого дня
+// @Description периода из ежедневных снимков. Снимок текущего дня обновляется до его окончания; дни без снимка пропускаются
+// @Tags investments
+// @Produce json
+// @Param id path int true "ID инвестиционного или криптовалютного счета"
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней до to)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Success 200 {array} models.ValuationPoint
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/valuation-history [get]
+func (h *Handler) GetValuationHistory(c *gin.Context) {
+	account, ok := h.loadHoldingAccount(c, false)
+	if !ok {
+		return
+	}
+	from, to, err := parseHistoryRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	points, err := h.storage.GetValuationHistory(account.ID, c.GetInt("user_id"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, points)
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/quotes"
+)
+
+// fakeQuotes возвращает фиксированные цены и считает обращения.
+type fakeQuotes struct {
+	prices map[string]quotes.Quote
+	calls  int
+}
+
+func (p *fakeQuotes) Quotes(ctx context.Context, tickers []string) (map[string]quotes.Quote, error) {
+	p.calls++
+	result := make(map[string]quotes.Quote)
+	for _, ticker := range tickers {
+		if quote, ok := p.prices[ticker]; ok {
+			result[ticker] = quote
+		}
+	}
+	return result, nil
+}
+
+// TestValueHoldings тестирует оценку позиций с пересчетом цен в валюту счета.
+func TestValueHoldings(t *testing.T) {
+	account := &models.Account{ID: 1, Currency: "RUB"}
+	holdings := []models.Holding{
+		{ID: 1, Ticker: "SBER", Quantity: 100, CostBasis: models.NewMoney(25000, 0)},
+		{ID: 2, Ticker: "AAPL", Quantity: 2, CostBasis: models.NewMoney(30000, 0)},
+		{ID: 3, Ticker: "UNKNOWN", Quantity: 5, CostBasis: models.NewMoney(1000, 0)},
+	}
+	prices := map[string]quotes.Quote{
+		"SBER": {Price: 310.25, Currency: "RUB"},
+		"AAPL": {Price: 200, Currency: "USD"},
+	}
+	exchangeRates := map[string]float64{"RUB": 1, "USD": 1.0 / 80}
+
+	valuation := valueHoldings(account, holdings, prices, exchangeRates)
+	if valuation.MarketValue != models.NewMoney(31025+32000+1000, 0) || valuation.UnrealizedPL != models.NewMoney(6025+2000, 0) ||
+		valuation.CostBasis != models.NewMoney(56000, 0) {
+		t.Errorf("Unexpected valuation totals: %+v", valuation)
+	}
+	if unknown := valuation.Holdings[2]; unknown.Priced || unknown.MarketValue != unknown.CostBasis {
+		t.Errorf("Expected unpriced holding valued at cost basis, got %+v", unknown)
+	}
+
+	// Без курсов позиция в другой валюте оценивается по сумме покупки
+	valuation = valueHoldings(account, holdings, prices, nil)
+	if valuation.Holdings[1].Priced || valuation.MarketValue != models.NewMoney(31025+30000+1000, 0) {
+		t.Errorf("Unexpected valuation without rates: %+v", valuation)
+	}
+}
+
+// TestQuoteCache тестирует повторное использование полученных цен.
+func TestQuoteCache(t *testing.T) {
+	provider := &fakeQuotes{prices: map[string]quotes.Quote{"SBER": {Price: 300, Currency: "RUB"}}}
+	cache := newQuoteCache(provider)
+	for i := 0; i < 2; i++ {
+		prices, err := cache.get(context.Background(), []string{"SBER"})
+		if err != nil {
+			t.Fatalf("Failed to get quotes: %v", err)
+		}
+		if prices["SBER"].Price != 300 {
+			t.Errorf("Unexpected prices: %v", prices)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("Expected 1 provider call, got %d", provider.calls)
+	}
+
+	prices, err := newQuoteCache(nil).get(context.Background(), []string{"SBER"})
+	if err != nil || len(prices) != 0 {
+		t.Errorf("Expected no prices without provider, got %v, %v", prices, err)
+	}
+}
+
+// TestHoldings тестирует позиции инвестиционного счета, их оценку и учет в чистых активах.
+func TestHoldings(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	provider := &fakeQuotes{prices: map[string]quotes.Quote{"SBER": {Price: 310.25, Currency: "RUB"}}}
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Quotes: provider})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.GET("/accounts/:id/holdings", handler.GetHoldings)
+	protected.POST("/accounts/:id/holdings", handler.CreateHolding)
+	protected.PUT("/accounts/:id/holdings/:holding_id", handler.UpdateHolding)
+	protected.DELETE("/accounts/:id/holdings/:holding_id", handler.DeleteHolding)
+	protected.GET("/accounts/:id/valuation", handler.GetInvestmentValuation)
+	protected.GET("/reports/net-worth", handler.GetNetWorth)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	regular, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "RUB", Type: "regular"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	broker, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Брокер", Currency: "RUB", Type: "investment",
+		InitialBalance: models.NewMoney(1000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	sber := models.CreateHolding{Ticker: " sber ", Quantity: 100, CostBasis: models.NewMoney(25000, 0)}
+	if w := send("POST", fmt.Sprintf("/accounts/%d/holdings", regular.ID), sber); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for regular account, got %d", http.StatusBadRequest, w.Code)
+	}
+	for _, invalid := range []models.CreateHolding{
+		{Ticker: "", Quantity: 1},
+		{Ticker: "SBER", Quantity: 0},
+		{Ticker: "SBER", Quantity: 1, CostBasis: -1},
+	} {
+		if w := send("POST", fmt.Sprintf("/accounts/%d/holdings", broker.ID), invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d", http.StatusBadRequest, invalid, w.Code)
+		}
+	}
+
+	w := send("POST", fmt.Sprintf("/accounts/%d/holdings", broker.ID), sber)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var holding models.Holding
+	json.NewDecoder(w.Body).Decode(&holding)
+	if holding.Ticker != "SBER" || holding.Quantity != 100 {
+		t.Errorf("Unexpected holding: %+v", holding)
+	}
+	if w := send("POST", fmt.Sprintf("/accounts/%d/holdings", broker.ID), sber); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for duplicate ticker, got %d", http.StatusBadRequest, w.Code)
+	}
+	w = send("POST", fmt.Sprintf("/accounts/%d/holdings", broker.ID), models.CreateHolding{Ticker: "GAZP", Quantity: 10, CostBasis: models.NewMoney(1500, 0)})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var gazp models.Holding
+	json.NewDecoder(w.Body).Decode(&gazp)
+
+	w = send("GET", fmt.Sprintf("/accounts/%d/valuation", broker.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var valuation models.InvestmentValuation
+	json.NewDecoder(w.Body).Decode(&valuation)
+	if valuation.MarketValue != models.NewMoney(31025+1500, 0) || valuation.UnrealizedPL != models.NewMoney(6025, 0) || len(valuation.Holdings) != 2 {
+		t.Errorf("Unexpected valuation: %+v", valuation)
+	}
+
+	w = send("GET", "/reports/net-worth", nil)
+	var netWorth models.NetWorth
+	json.NewDecoder(w.Body).Decode(&netWorth)
+	if netWorth.NetWorth != models.NewMoney(1000+31025+1500, 0) {
+		t.Errorf("Unexpected net worth: %+v", netWorth)
+	}
+
+	w = send("PUT", fmt.Sprintf("/accounts/%d/holdings/%d", broker.ID, holding.ID), models.UpdateHolding{Quantity: 50, CostBasis: models.NewMoney(12500, 0)})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&holding)
+	if holding.Quantity != 50 || holding.CostBasis != models.NewMoney(12500, 0) {
+		t.Errorf("Unexpected updated holding: %+v", holding)
+	}
+	if w := send("DELETE", fmt.Sprintf("/accounts/%d/holdings/%d", broker.ID, gazp.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("/accounts/%d/holdings/%d", broker.ID, gazp.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = send("GET", fmt.Sprintf("/accounts/%d/holdings", broker.ID), nil)
+	var holdings []models.Holding
+	json.NewDecoder(w.Body).Decode(&holdings)
+	if len(holdings) != 1 || holdings[0].ID != holding.ID {
+		t.Errorf("Unexpected holdings: %+v", holdings)
+	}
+}
+
+// TestCryptoHoldings тестирует криптовалютный счет: оценку по ценам в долларах, снимки стоимости и чистые активы.
+func TestCryptoHoldings(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+	securities := &fakeQuotes{prices: map[string]quotes.Quote{"BTC": {Price: 1, Currency: "RUB"}}}
+	crypto := &fakeQuotes{prices: map[string]quotes.Quote{"BTC": {Price: 60000, Currency: "USD"}}}
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Rates: &fakeRates{}, Quotes: securities, CryptoQuotes: crypto})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.POST("/accounts/:id/holdings", handler.CreateHolding)
+	protected.GET("/accounts/:id/valuation", handler.GetInvestmentValuation)
+	protected.GET("/accounts/:id/valuation-history", handler.GetValuationHistory)
+	protected.GET("/reports/net-worth", handler.GetNetWorth)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	wallet, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Кошелек", Currency: "RUB", Type: "crypto"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", fmt.Sprintf("/accounts/%d/holdings", wallet.ID), models.CreateHolding{Ticker: "btc", Quantity: 0.5, CostBasis: models.NewMoney(2000000, 0)})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// 0.5 BTC по 60000 USD при курсе 80 RUB за доллар
+	w = send("GET", fmt.Sprintf("/accounts/%d/valuation", wallet.ID), nil)
+	var valuation models.InvestmentValuation
+	json.NewDecoder(w.Body).Decode(&valuation)
+	if valuation.MarketValue != models.NewMoney(2400000, 0) || valuation.UnrealizedPL != models.NewMoney(400000, 0) {
+		t.Errorf("Unexpected valuation: %+v", valuation)
+	}
+	if securities.calls != 0 {
+		t.Errorf("Expected crypto account to use crypto quotes, got %d securities quote calls", securities.calls)
+	}
+
+	w = send("GET", "/reports/net-worth", nil)
+	var netWorth models.NetWorth
+	json.NewDecoder(w.Body).Decode(&netWorth)
+	if netWorth.NetWorth != models.NewMoney(2400000, 0) {
+		t.Errorf("Unexpected net worth: %+v", netWorth)
+	}
+
+	today := time.Now().UTC()
+	for i := 0; i < 2; i++ {
+		if err := handler.snapshotValuations(context.Background(), today); err != nil {
+			t.Fatalf("Failed to snapshot valuations: %v", err)
+		}
+	}
+	w = send("GET", fmt.Sprintf("/accounts/%d/valuation-history", wallet.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var points []models.ValuationPoint
+	json.NewDecoder(w.Body).Decode(&points)
+	if len(points) != 1 || points[0].MarketValue != models.NewMoney(2400000, 0) || points[0].CostBasis != models.NewMoney(2000000, 0) {
+		t.Errorf("Unexpected valuation history: %+v", points)
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// categoryIcons — набор значков категорий, который поддерживают клиенты.
+var categoryIcons = []string{
+	"bank", "beauty", "bonus", "books", "car", "card", "cart", "cash", "clothes", "cafe",
+	"education", "entertainment", "food", "fuel", "games", "gift", "health", "home", "internet", "investments",
+	"kids", "music", "other", "pets", "pharmacy", "phone", "plane", "repair", "salary", "savings",
+	"sport", "subscriptions", "taxes", "transport", "travel", "utilities",
+}
+
+var categoryColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// normalizeCategoryAppearance проверяет значок и цвет категории и приводит цвет к нижнему регистру.
+// Пустые значения допустимы и означают, что значок или цвет не заданы.
+func normalizeCategoryAppearance(icon, color string) (string, string, error) {
+	if icon != "" && !isCategoryIcon(icon) {
+		return "", "", fmt.Errorf("unknown icon %q", icon)
+	}
+	if color != "" && !categoryColorPattern.MatchString(color) {
+		return "", "", fmt.Errorf("color must be in #rrggbb format")
+	}
+	return icon, strings.ToLower(color), nil
+}
+
+func isCategoryIcon(icon string) bool {
+	for _, known := range categoryIcons {
+		if icon == known {
+			return true
+		}
+	}
+	return false
+}
+
+// @Security ApiKeyAuth
+// @Summary Значки категорий
+// @Description Возвращает имена значков, которые можно назначить категории
+// @Tags categories
+// @Produce json
+// @Success 200 {array} string
+// @Failure 401 {object} models.ErrorResponse
+// @Router /categories/icons [get]
+func (h *Handler) GetCategoryIcons(c *gin.Context) {
+	c.JSON(http.StatusOK, categoryIcons)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestCategoryAppearance тестирует значок и цвет категории при создании, обновлении и частичном обновлении.
+func TestCategoryAppearance(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.CreateUser("testuser", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, url, body string) (int, models.Category) {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var category models.Category
+		json.NewDecoder(w.Body).Decode(&category)
+		return w.Code, category
+	}
+
+	code, category := send("POST", "/categories", `{"name": "Продукты", "icon": "cart", "color": "#4CAF50"}`)
+	if code != http.StatusCreated || category.Icon != "cart" || category.Color != "#4caf50" {
+		t.Fatalf("Expected category with icon and normalized color, got status %d and %+v", code, category)
+	}
+	if code, fetched := send("GET", fmt.Sprintf("/categories/%d", category.ID), ""); code != http.StatusOK || fetched.Icon != "cart" || fetched.Color != "#4caf50" {
+		t.Errorf("Expected stored icon and color, got status %d and %+v", code, fetched)
+	}
+
+	for _, body := range []string{
+		`{"name": "x", "icon": "rocket"}`,
+		`{"name": "x", "color": "green"}`,
+		`{"name": "x", "color": "#fff"}`,
+	} {
+		if code, _ := send("POST", "/categories", body); code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, body, code)
+		}
+	}
+
+	// PATCH меняет только переданные поля, пустая строка сбрасывает значение
+	if code, patched := send("PATCH", fmt.Sprintf("/categories/%d", category.ID), `{"color": "#112233"}`); code != http.StatusOK || patched.Icon != "cart" || patched.Color != "#112233" {
+		t.Errorf("Expected patched color, got status %d and %+v", code, patched)
+	}
+	if code, patched := send("PATCH", fmt.Sprintf("/categories/%d", category.ID), `{"icon": ""}`); code != http.StatusOK || patched.Icon != "" || patched.Color != "#112233" {
+		t.Errorf("Expected reset icon, got status %d and %+v", code, patched)
+	}
+	if code, _ := send("PATCH", fmt.Sprintf("/categories/%d", category.ID), `{"icon": "rocket"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for unknown icon, got %d", http.StatusBadRequest, code)
+	}
+
+	// PUT заменяет категорию целиком
+	if code, updated := send("PUT", fmt.Sprintf("/categories/%d", category.ID), `{"name": "Еда", "icon": "food"}`); code != http.StatusOK || updated.Icon != "food" || updated.Color != "" {
+		t.Errorf("Expected replaced appearance, got status %d and %+v", code, updated)
+	}
+
+	req, _ := http.NewRequest("GET", "/categories/icons", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var icons []string
+	json.NewDecoder(w.Body).Decode(&icons)
+	if w.Code != http.StatusOK || len(icons) != len(categoryIcons) {
+		t.Errorf("Expected %d icons, got status %d and %v", len(categoryIcons), w.Code, icons)
+	}
+}
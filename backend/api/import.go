@@ -0,0 +1,287 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/importer"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// maxImportSize — максимальный размер загружаемого файла.
+const maxImportSize = 10 << 20
+
+// pendingCategoryID подставляется при проверке строки вместо ID категории, которая еще не создана:
+// правила транзакции требуют положительный ID, а саму категорию создает хранилище вместе с транзакцией.
+const pendingCategoryID = 1
+
+// importOptions — настройки сопоставления строк импорта с категориями.
+type importOptions struct {
+	// CreateCategories создает категории, которых еще нет у пользователя
+	CreateCategories bool
+	// DefaultCategory — категория для строк без категории
+	DefaultCategory string
+	// DryRun только проверяет строки и ищет дубликаты, ничего не записывая
+	DryRun bool
+}
+
+// importRows проверяет разобранные строки, сопоставляет их с категориями пользователя
+// и создает корректные транзакции и недостающие категории одной транзакцией БД. При opts.DryRun отчет содержит
+// транзакции, которые были бы созданы.
+func (h *Handler) importRows(userID int, rows []importer.Row, opts importOptions) (*models.ImportReport, error) {
+	categories, err := h.storage.GetCategories(userID)
+	if err != nil {
+		return nil, err
+	}
+	// Строкам, оставшимся без категории после правил из запроса, категория назначается по ключевым словам
+	keywords, err := h.storage.GetCategoryKeywords(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	importer.ApplyRules(rows, keywordRules(keywords, categories))
+	// Собственная категория пользователя важнее системной с тем же именем
+	categoryIDs := make(map[string]int, len(categories))
+	for _, category := range categories {
+		if _, ok := categoryIDs[strings.ToLower(category.Name)]; !ok || !category.System {
+			categoryIDs[strings.ToLower(category.Name)] = category.ID
+		}
+	}
+
+	report := &models.ImportReport{DryRun: opts.DryRun, Rows: make([]models.ImportRowResult, len(rows))}
+	// pendingCategories — категории, которые будут созданы вместе с транзакциями
+	pendingCategories := make(map[string]bool)
+	var transactions []*models.Transaction
+	// newCategories — имя категории, которую нужно создать для транзакции с тем же индексом
+	var newCategories []string
+	var resultIndexes []int
+	for i, row := range rows {
+		result := &report.Rows[i]
+		result.Line = row.Line
+		if row.Err != nil {
+			result.Error = row.Err.Error()
+			continue
+		}
+
+		t := &models.Transaction{
+			UserID:      userID,
+			Amount:      row.Amount,
+			Type:        row.Type,
+			Date:        row.Date,
+			Description: row.Description,
+			Payee:       row.Payee,
+			Currency:    row.Currency,
+			Tags:        row.Tags,
+		}
+
+		name := row.Category
+		if name == "" {
+			name = opts.DefaultCategory
+		}
+		if name == "" {
+			result.Error = "category is required"
+			continue
+		}
+
+		categoryID, exists := categoryIDs[strings.ToLower(name)]
+		pending := !exists && pendingCategories[strings.ToLower(name)]
+		if !exists && !pending && !opts.CreateCategories {
+			result.Error = fmt.Sprintf("category %q not found", name)
+			continue
+		}
+
+		// Категория создается только для строк, прошедших остальные проверки
+		check := *t
+		check.CategoryID = categoryID
+		if !exists {
+			check.CategoryID = pendingCategoryID
+		}
+		if err := validateTransaction(check); err != nil {
+			result.Error = err.Error()
+			continue
+		}
+
+		newCategory := ""
+		if !exists {
+			newCategory = name
+			if !pending {
+				pendingCategories[strings.ToLower(name)] = true
+				report.CreatedCategories = append(report.CreatedCategories, name)
+			}
+		}
+		t.CategoryID = categoryID
+		result.Category = name
+
+		transactions = append(transactions, t)
+		newCategories = append(newCategories, newCategory)
+		resultIndexes = append(resultIndexes, i)
+	}
+
+	if opts.DryRun {
+		if err := h.storage.FindDuplicates(transactions); err != nil {
+			return nil, err
+		}
+		for j, t := range transactions {
+			result := &report.Rows[resultIndexes[j]]
+			result.Transaction = t
+			result.PossibleDuplicate = t.PossibleDuplicate
+		}
+		report.Imported = len(transactions)
+		report.Failed = len(rows) - len(transactions)
+		return report, nil
+	}
+
+	if len(transactions) > 0 {
+		if err := h.storage.CreateTransactionsWithCategories(transactions, newCategories); err != nil {
+			return nil, err
+		}
+	}
+	for j, t := range transactions {
+		report.Rows[resultIndexes[j]].TransactionID = t.ID
+		report.Rows[resultIndexes[j]].PossibleDuplicate = t.PossibleDuplicate
+	}
+	report.Imported = len(transactions)
+	report.Failed = len(rows) - len(transactions)
+	return report, nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Импорт транзакций из CSV, OFX или QIF
+// @Description Загружает банковскую выписку и создает транзакции. Для CSV столбцы задаются сопоставлением mapping
+// @Description или встроенным профилем банка preset.
+// @Description Категории строк без категории назначаются по правилам rules (подстрока в контрагенте или описании),
+// @Description затем по ключевым словам категорий пользователя.
+// @Description Некорректные строки пропускаются и перечисляются в отчете; корректные создаются одной транзакцией БД.
+// @Description Строки, похожие на уже существующие транзакции, создаются с отметкой possible_duplicate.
+// @Description При dry_run=true файл только проверяется: отчет содержит транзакции, которые были бы созданы, и ничего не записывается.
+// @Tags transactions
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Файл выписки"
+// @Param format formData string false "Формат: csv, ofx или qif; по умолчанию определяется по расширению файла"
+// @Param mapping formData string false "Для CSV: сопоставление столбцов в JSON (date, amount, type, category, payee, description, currency, tags, date_format, delimiter, encoding)"
+// @Param preset formData string false "Профиль выгрузки банка вместо mapping: tinkoff, sber, revolut или wise; подразумевает формат csv"
+// @Param date_format formData string false "Для QIF: формат даты в нотации Go"
+// @Param rules formData string false "Правила категорий в JSON: [{\"match\": \"Пятерочка\", \"category\": \"Продукты\"}]"
+// @Param create_categories formData bool false "Создавать отсутствующие категории"
+// @Param default_category formData string false "Категория для строк без категории"
+// @Param dry_run query bool false "Только проверить файл и показать результат без записи"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transactions/import [post]
+func (h *Handler) ImportTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be 'true' or 'false'"})
+			return
+		}
+	}
+
+	rows, opts, ok := parseImport(c)
+	if !ok {
+		return
+	}
+	opts.DryRun = dryRun
+
+	report, err := h.importRows(userID.(int), rows, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// parseImport разбирает загруженный файл выписки и настройки импорта из multipart-формы.
+// При ошибке ответ уже записан и возвращается false.
+func parseImport(c *gin.Context) ([]importer.Row, importOptions, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return nil, importOptions{}, false
+	}
+
+	var mapping importer.Mapping
+	preset := c.PostForm("preset")
+	if preset != "" {
+		if c.PostForm("mapping") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mapping and preset cannot be used together"})
+			return nil, importOptions{}, false
+		}
+		var ok bool
+		if mapping, ok = importer.Preset(preset); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "preset must be one of: " + strings.Join(importer.PresetNames(), ", ")})
+			return nil, importOptions{}, false
+		}
+	}
+
+	format := strings.ToLower(c.PostForm("format"))
+	if format == "" && preset != "" {
+		format = "csv"
+	}
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+	if preset != "" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "preset is supported only for csv"})
+		return nil, importOptions{}, false
+	}
+
+	var rules []importer.Rule
+	if rulesStr := c.PostForm("rules"); rulesStr != "" {
+		if err := json.Unmarshal([]byte(rulesStr), &rules); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rules"})
+			return nil, importOptions{}, false
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, importOptions{}, false
+	}
+	defer file.Close()
+
+	var rows []importer.Row
+	switch format {
+	case "csv":
+		if preset == "" {
+			if err := json.Unmarshal([]byte(c.PostForm("mapping")), &mapping); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mapping"})
+				return nil, importOptions{}, false
+			}
+		}
+		rows, err = importer.ParseCSV(file, mapping)
+	case "ofx":
+		rows, err = importer.ParseOFX(file)
+	case "qif":
+		rows, err = importer.ParseQIF(file, c.PostForm("date_format"))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be 'csv', 'ofx' or 'qif'"})
+		return nil, importOptions{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, importOptions{}, false
+	}
+	importer.ApplyRules(rows, rules)
+
+	return rows, importOptions{
+		CreateCategories: c.PostForm("create_categories") == "true",
+		DefaultCategory:  strings.TrimSpace(c.PostForm("default_category")),
+	}, true
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestImportDryRun тестирует предварительный просмотр импорта без записи.
+func TestImportDryRun(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	existing := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(1234, 50), Type: "expense", CategoryID: food.ID,
+		Date: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Payee: "Пятерочка"}
+	if err := storage.CreateTransaction(existing); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	data := "Дата;Сумма;Категория;Получатель\n" +
+		"15.01.2025;-1 234,50;Продукты;Пятерочка\n" +
+		"16.01.2025;-300;Кафе;Шоколадница\n" +
+		"17.01.2025;abc;Продукты;Лента\n"
+	upload := func(query string) (int, models.ImportReport) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", "statement.csv")
+		part.Write([]byte(data))
+		mw.WriteField("mapping", `{"date": "Дата", "amount": "Сумма", "category": "Категория", "payee": "Получатель", "date_format": "02.01.2006", "delimiter": ";"}`)
+		mw.WriteField("create_categories", "true")
+		mw.Close()
+
+		req, _ := http.NewRequest("POST", "/transactions/import"+query, &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var report models.ImportReport
+		json.NewDecoder(w.Body).Decode(&report)
+		return w.Code, report
+	}
+	count := func() int {
+		_, total, err := storage.GetTransactions(user.ID, db.TransactionFilter{}, 1, 10)
+		if err != nil {
+			t.Fatalf("Failed to get transactions: %v", err)
+		}
+		return total
+	}
+
+	code, report := upload("?dry_run=true")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+	}
+	if !report.DryRun || report.Imported != 2 || report.Failed != 1 || len(report.CreatedCategories) != 1 || report.CreatedCategories[0] != "Кафе" {
+		t.Errorf("Unexpected dry run report: %+v", report)
+	}
+	first, second := report.Rows[0], report.Rows[1]
+	if first.Transaction == nil || !first.PossibleDuplicate || first.Transaction.CategoryID != food.ID || first.TransactionID != 0 {
+		t.Errorf("Expected first row to preview a duplicate in existing category, got %+v", first)
+	}
+	if second.Transaction == nil || second.PossibleDuplicate || second.Category != "Кафе" || second.Transaction.CategoryID != 0 {
+		t.Errorf("Expected second row to preview a new category, got %+v", second)
+	}
+	if report.Rows[2].Error == "" {
+		t.Errorf("Expected error for invalid amount, got %+v", report.Rows[2])
+	}
+
+	// Ничего не записано: ни транзакций, ни категорий, ни контрагентов
+	if n := count(); n != 1 {
+		t.Errorf("Expected 1 transaction after dry run, got %d", n)
+	}
+	categories, err := storage.GetCategories(user.ID)
+	if err != nil || len(categories) != 1 {
+		t.Errorf("Expected only the existing category after dry run, got %v (%v)", categories, err)
+	}
+	if payees, err := storage.GetPayees(user.ID, "", 10); err != nil || len(payees) != 1 {
+		t.Errorf("Expected only the existing payee after dry run, got %v (%v)", payees, err)
+	}
+
+	// Подтвержденный импорт создает то же, что было показано
+	code, report = upload("")
+	if code != http.StatusOK || report.DryRun || report.Imported != 2 || !report.Rows[0].PossibleDuplicate || report.Rows[0].Transaction != nil {
+		t.Errorf("Unexpected import report: status %d, %+v", code, report)
+	}
+	if n := count(); n != 3 {
+		t.Errorf("Expected 3 transactions after import, got %d", n)
+	}
+
+	if code, _ := upload("?dry_run=maybe"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+}
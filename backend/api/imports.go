@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/importer"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// importBatchSize — число строк, которые фоновый импорт создает одной транзакцией БД.
+// Между партиями сохраняется прогресс и проверяется отмена.
+const importBatchSize = 500
+
+// FailInterruptedImports помечает неудачными задания импорта, прерванные перезапуском сервера.
+func (h *Handler) FailInterruptedImports() {
+	failed, err := h.storage.FailInterruptedImportJobs()
+	if err != nil {
+		log.Printf("failed to mark interrupted import jobs: %v", err)
+	} else if failed > 0 {
+		log.Printf("marked %d interrupted import jobs as failed", failed)
+	}
+}
+
+// runImportJob импортирует строки партиями, сохраняя прогресс задания после каждой из них.
+// Партии, созданные до отмены, остаются в отчете и в базе. Задание пишет уже после ответа на запрос,
+// поэтому кэш пользователя сбрасывается при его завершении.
+func (h *Handler) runImportJob(jobID, userID int, rows []importer.Row, opts importOptions) {
+	report := &models.ImportReport{Rows: []models.ImportRowResult{}}
+	finish := func(status, errMessage string) {
+		h.invalidateCache(context.Background(), userID)
+		if err := h.storage.FinishImportJob(jobID, status, report, errMessage); err != nil {
+			log.Printf("failed to finish import job %d: %v", jobID, err)
+			return
+		}
+		h.importFinished(jobID, userID)
+	}
+
+	active, err := h.storage.UpdateImportJobProgress(jobID, 0, report)
+	for start := 0; err == nil && active && start < len(rows); start += importBatchSize {
+		end := min(start+importBatchSize, len(rows))
+		var batch *models.ImportReport
+		if batch, err = h.importRows(userID, rows[start:end], opts); err != nil {
+			break
+		}
+		report.Imported += batch.Imported
+		report.Failed += batch.Failed
+		report.CreatedCategories = append(report.CreatedCategories, batch.CreatedCategories...)
+		report.Rows = append(report.Rows, batch.Rows...)
+		active, err = h.storage.UpdateImportJobProgress(jobID, end, report)
+	}
+
+	switch {
+	case err != nil:
+		log.Printf("import job %d failed: %v", jobID, err)
+		finish("failed", err.Error())
+	case !active:
+		finish("cancelled", "")
+	default:
+		finish("completed", "")
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary Фоновый импорт транзакций
+// @Description Принимает те же поля, что и /transactions/import, проверяет файл и сразу возвращает задание.
+// @Description Строки создаются в фоне партиями; прогресс и отчет по строкам доступны в /imports/{id}.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Файл выписки"
+// @Param format formData string false "Формат: csv, ofx или qif; по умолчанию определяется по расширению файла"
+// @Param mapping formData string false "Для CSV: сопоставление столбцов в JSON"
+// @Param preset formData string false "Профиль выгрузки банка вместо mapping: tinkoff, sber, revolut или wise"
+// @Param date_format formData string false "Для QIF: формат даты в нотации Go"
+// @Param rules formData string false "Правила категорий в JSON"
+// @Param create_categories formData bool false "Создавать отсутствующие категории"
+// @Param default_category formData string false "Категория для строк без категории"
+// @Success 202 {object} models.ImportJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /imports [post]
+func (h *Handler) CreateImportJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	rows, opts, ok := parseImport(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.storage.CreateImportJob(userID.(int), len(rows))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	go h.runImportJob(job.ID, userID.(int), rows, opts)
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Security ApiKeyAuth
+// @Summary Задание импорта
+// @Description Возвращает статус, прогресс и отчет по уже обработанным строкам
+// @Tags imports
+// @Produce json
+// @Param id path int true "ID задания"
+// @Success 200 {object} models.ImportJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /imports/{id} [get]
+func (h *Handler) GetImportJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import job id"})
+		return
+	}
+
+	job, err := h.storage.GetImportJob(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// @Security ApiKeyAuth
+// @Summary Отменить задание импорта
+// @Description Останавливает импорт после текущей партии. Уже созданные транзакции остаются
+// @Tags imports
+// @Param id path int true "ID задания"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /imports/{id}/cancel [post]
+func (h *Handler) CancelImportJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import job id"})
+		return
+	}
+
+	job, err := h.storage.GetImportJob(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+		return
+	}
+
+	cancelled, err := h.storage.CancelImportJob(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !cancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "import job is already finished"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestImportJobs тестирует фоновый импорт, прогресс задания и его отмену.
+func TestImportJobs(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	do := func(method, url string, body *bytes.Buffer, contentType string) *httptest.ResponseRecorder {
+		if body == nil {
+			body = &bytes.Buffer{}
+		}
+		req, _ := http.NewRequest(method, url, body)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	getJob := func(id int) models.ImportJob {
+		w := do("GET", fmt.Sprintf("/imports/%d", id), nil, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var job models.ImportJob
+		json.NewDecoder(w.Body).Decode(&job)
+		return job
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "statement.csv")
+	part.Write([]byte("date,amount,category\n2025-01-15,-100,Кафе\n2025-01-16,abc,Кафе\n2025-01-17,500,Зарплата\n"))
+	mw.WriteField("mapping", `{"date": "date", "amount": "amount", "category": "category"}`)
+	mw.WriteField("create_categories", "true")
+	mw.Close()
+
+	w := do("POST", "/imports", &body, mw.FormDataContentType())
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	var job models.ImportJob
+	json.NewDecoder(w.Body).Decode(&job)
+	if job.ID == 0 || job.TotalRows != 3 {
+		t.Fatalf("Unexpected job: %+v", job)
+	}
+
+	// Задание выполняется в фоне; ждем его завершения
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != "completed" && job.Status != "failed" && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		job = getJob(job.ID)
+	}
+	if job.Status != "completed" || job.ProcessedRows != 3 || job.FinishedAt == nil || job.Report == nil {
+		t.Fatalf("Expected completed job, got %+v", job)
+	}
+	if job.Report.Imported != 2 || job.Report.Failed != 1 || len(job.Report.Rows) != 3 || job.Report.Rows[1].Error == "" {
+		t.Errorf("Unexpected job report: %+v", job.Report)
+	}
+
+	// Завершенное задание отменить нельзя
+	if w := do("POST", fmt.Sprintf("/imports/%d/cancel", job.ID), nil, ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	// Ожидающее задание отменяется
+	pending, err := storage.CreateImportJob(user.ID, 10)
+	if err != nil {
+		t.Fatalf("Failed to create import job: %v", err)
+	}
+	if w := do("POST", fmt.Sprintf("/imports/%d/cancel", pending.ID), nil, ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if cancelled := getJob(pending.ID); cancelled.Status != "cancelled" || cancelled.FinishedAt == nil {
+		t.Errorf("Expected cancelled job, got %+v", cancelled)
+	}
+	// Отмененное задание не продолжает обработку
+	if active, err := storage.UpdateImportJobProgress(pending.ID, 5, &models.ImportReport{}); err != nil || active {
+		t.Errorf("Expected progress update to report cancellation, got %v (%v)", active, err)
+	}
+
+	if w := do("GET", "/imports/999999", nil, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/scheduler"
+)
+
+// Jobs возвращает фоновые задачи сервиса с расписаниями по умолчанию.
+func (h *Handler) Jobs() []scheduler.Job {
+	return []scheduler.Job{
+		{Name: "planned_conversion", Schedule: "@every 15m", Run: h.convertPlanned},
+		{Name: "balance_snapshots", Schedule: "@every 1h", Run: h.snapshotBalances},
+		{Name: "valuation_snapshots", Schedule: "@every 1h", Run: func(ctx context.Context) error {
+			return h.snapshotValuations(ctx, time.Now().UTC())
+		}},
+		{Name: "budget_templates", Schedule: "@every 1h", Run: h.applyBudgetTemplates},
+		{Name: "budget_alerts", Schedule: "@every 1h", Run: h.sendBudgetAlerts},
+		{Name: "report_emails", Schedule: "@every 1h", Run: h.sendReportEmails},
+		{Name: "trash_purge", Schedule: "@every 1h", Run: h.purgeTrash},
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary Фоновые задачи
+// @Description Возвращает фоновые задачи планировщика: расписание, включена ли задача и результат последнего запуска.
+// @Description Доступно только администраторам
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.JobStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/jobs [get]
+func (h *Handler) GetJobs(c *gin.Context) {
+	if h.cfg.Scheduler == nil {
+		c.JSON(http.StatusOK, []models.JobStatus{})
+		return
+	}
+	c.JSON(http.StatusOK, h.cfg.Scheduler.Status())
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/importer"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+func keywordErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "keyword") || strings.Contains(err.Error(), "category does not exist") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// keywordRules превращает ключевые слова категорий в правила импорта.
+func keywordRules(keywords []models.CategoryKeyword, categories []models.Category) []importer.Rule {
+	names := make(map[int]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	rules := make([]importer.Rule, 0, len(keywords))
+	for _, k := range keywords {
+		if name, ok := names[k.CategoryID]; ok {
+			rules = append(rules, importer.Rule{Match: k.Keyword, Category: name})
+		}
+	}
+	return rules
+}
+
+// @Security ApiKeyAuth
+// @Summary Ключевые слова категории
+// @Description Возвращает ключевые слова, по которым категория назначается при импорте и создании транзакций по чекам
+// @Tags categories
+// @Produce json
+// @Param id path int true "ID категории"
+// @Success 200 {array} models.CategoryKeyword
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /categories/{id}/keywords [get]
+func (h *Handler) GetCategoryKeywords(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	category, err := h.storage.GetCategory(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if category == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+
+	keywords, err := h.storage.GetCategoryKeywords(userID.(int), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, keywords)
+}
+
+// @Security ApiKeyAuth
+// @Summary Добавить ключевое слово категории
+// @Description Транзакциям без категории, в контрагенте или описании которых встречается слово (без учета регистра),
+// @Description назначается эта категория. Слово уникально у пользователя; при нескольких совпадениях выбирается самое длинное.
+// @Description Ключевые слова можно добавлять и к системным категориям
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param keyword body models.CreateCategoryKeyword true "Ключевое слово"
+// @Success 201 {object} models.CategoryKeyword
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /categories/{id}/keywords [post]
+func (h *Handler) CreateCategoryKeyword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	var req models.CreateCategoryKeyword
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	keyword, err := h.storage.CreateCategoryKeyword(userID.(int), id, req.Keyword)
+	if err != nil {
+		c.JSON(keywordErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, keyword)
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить ключевое слово категории
+// @Tags categories
+// @Param id path int true "ID категории"
+// @Param keyword_id path int true "ID ключевого слова"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /categories/{id}/keywords/{keyword_id} [delete]
+func (h *Handler) DeleteCategoryKeyword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+	keywordID, err := strconv.Atoi(c.Param("keyword_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid keyword id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteCategoryKeyword(keywordID, id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "keyword not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestCategoryKeywords тестирует ключевые слова категорий и автоматическое назначение категории при импорте.
+func TestCategoryKeywords(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cafe, err := storage.CreateCategory(user.ID, "Кафе")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	keywordsPath := fmt.Sprintf("/categories/%d/keywords", food.ID)
+	w := send("POST", keywordsPath, models.CreateCategoryKeyword{Keyword: "Пятерочка"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var keyword models.CategoryKeyword
+	json.NewDecoder(w.Body).Decode(&keyword)
+	if keyword.ID == 0 || keyword.CategoryID != food.ID || keyword.Keyword != "Пятерочка" {
+		t.Errorf("Unexpected keyword: %+v", keyword)
+	}
+	if w := send("POST", fmt.Sprintf("/categories/%d/keywords", cafe.ID), models.CreateCategoryKeyword{Keyword: "Шоколадница"}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// Слово уникально у пользователя без учета регистра
+	if w := send("POST", fmt.Sprintf("/categories/%d/keywords", cafe.ID), models.CreateCategoryKeyword{Keyword: "пятерочка"}); w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if w := send("POST", keywordsPath, models.CreateCategoryKeyword{Keyword: "  "}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", "/categories/999999/keywords", models.CreateCategoryKeyword{Keyword: "Магнит"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = send("GET", keywordsPath, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var keywords []models.CategoryKeyword
+	json.NewDecoder(w.Body).Decode(&keywords)
+	if len(keywords) != 1 || keywords[0].ID != keyword.ID {
+		t.Errorf("Expected only keyword %d, got %+v", keyword.ID, keywords)
+	}
+	if w := send("GET", "/categories/999999/keywords", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// Строки без категории получают ее по ключевому слову, явная категория не меняется
+	data := "Дата;Сумма;Категория;Получатель\n" +
+		"15.01.2025;-1 234,50;;ПЯТЕРОЧКА 1234\n" +
+		"16.01.2025;-300;;Шоколадница\n" +
+		"17.01.2025;-500;Кафе;Пятерочка\n"
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "statement.csv")
+	part.Write([]byte(data))
+	mw.WriteField("mapping", `{"date": "Дата", "amount": "Сумма", "category": "Категория", "payee": "Получатель", "date_format": "02.01.2006", "delimiter": ";"}`)
+	mw.Close()
+	req, _ := http.NewRequest("POST", "/transactions/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	transactions, _, err := storage.GetTransactions(user.ID, db.TransactionFilter{}, 1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get transactions: %v", err)
+	}
+	byCategory := map[int]int{}
+	for _, transaction := range transactions {
+		byCategory[transaction.CategoryID]++
+	}
+	if byCategory[food.ID] != 1 || byCategory[cafe.ID] != 2 {
+		t.Errorf("Expected 1 food and 2 cafe transactions, got %v", byCategory)
+	}
+
+	if w := send("DELETE", fmt.Sprintf("%s/%d", keywordsPath, keyword.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("%s/%d", keywordsPath, keyword.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// TestScanReceiptKeywords тестирует подбор категории чека по ключевому слову продавца.
+func TestScanReceiptKeywords(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Receipts: fakeReceipts{}})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.POST("/transactions/receipt", handler.ScanReceipt)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func() *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(models.ScanReceiptRequest{QR: "t=20250115T1230&s=1234.50&fn=9999078900012345&i=12345&fp=1234567890&n=1"})
+		req, _ := http.NewRequest("POST", "/transactions/receipt", &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Без категории и подходящих ключевых слов чек отклоняется
+	if w := send(); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if _, err := storage.CreateCategoryKeyword(user.ID, category.ID, "пятерочка"); err != nil {
+		t.Fatalf("Failed to create keyword: %v", err)
+	}
+	w := send()
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created models.ReceiptTransaction
+	json.NewDecoder(w.Body).Decode(&created)
+	if created.Transaction.CategoryID != category.ID {
+		t.Errorf("Expected category %d, got %d", category.ID, created.Transaction.CategoryID)
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// linkErrorStatus возвращает 400 для ошибок связи возврата с расходом, счета, перевода и корректировки транзакции
+// и 500 для остальных ошибок хранилища.
+func linkErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "linked") || strings.Contains(err.Error(), "refund") || strings.Contains(err.Error(), "account") ||
+		strings.Contains(err.Error(), "transfer") || strings.Contains(err.Error(), "adjustment") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Security ApiKeyAuth
+// @Summary Связанные транзакции
+// @Description Возвращает расход, привязанные к нему возвраты и сумму расхода за вычетом возвратов.
+// @Description Для возврата возвращается группа его исходного расхода.
+// @Tags transactions
+// @Produce json
+// @Param id path int true "ID транзакции"
+// @Success 200 {object} models.LinkedTransactions
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id}/linked [get]
+func (h *Handler) GetLinkedTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	linked, err := h.storage.GetLinkedTransactions(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if linked == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, linked)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestLinkedTransactions тестирует привязку возвратов к расходам и итоги за вычетом возвратов.
+func TestLinkedTransactions(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "clothes")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	purchase := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(5000, 0), Type: "expense", CategoryID: category.ID, Currency: "RUB"}
+	if err := storage.CreateTransaction(purchase); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	refundBody := func(amount string, linkedID int, currency string) string {
+		return fmt.Sprintf(`{"amount": %s, "type": "income", "category_id": %d, "currency": %q, "linked_transaction_id": %d}`,
+			amount, category.ID, currency, linkedID)
+	}
+
+	// Частичный возврат по покупке
+	w := do("POST", "/transactions", refundBody("1500", purchase.ID, "RUB"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var refund models.Transaction
+	json.NewDecoder(w.Body).Decode(&refund)
+	if refund.LinkedTransactionID != purchase.ID {
+		t.Errorf("Expected refund linked to %d, got %+v", purchase.ID, refund)
+	}
+
+	// Группа одинакова для расхода и для возврата
+	for _, id := range []int{purchase.ID, refund.ID} {
+		w = do("GET", fmt.Sprintf("/transactions/%d/linked", id), "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var linked models.LinkedTransactions
+		json.NewDecoder(w.Body).Decode(&linked)
+		if linked.Transaction.ID != purchase.ID || len(linked.Refunds) != 1 || linked.Refunds[0].ID != refund.ID ||
+			linked.RefundedAmount != models.NewMoney(1500, 0) || linked.NetAmount != models.NewMoney(3500, 0) {
+			t.Errorf("Unexpected linked transactions for %d: %+v", id, linked)
+		}
+	}
+
+	// Возврат уменьшает расход и не считается доходом
+	totals, err := storage.GetTransactionTotals(user.ID, db.TransactionFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get totals: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Income != 0 || totals[0].Expense != models.NewMoney(3500, 0) {
+		t.Errorf("Expected net expense 3500 and no income, got %+v", totals)
+	}
+
+	// Расход с возвратами нельзя превратить в доход
+	if w := do("PATCH", fmt.Sprintf("/transactions/%d", purchase.ID), `{"type": "income"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for expense with refunds, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	for _, tc := range []struct {
+		name string
+		body string
+	}{
+		{"link to income", refundBody("10", refund.ID, "RUB")},
+		{"other currency", refundBody("10", purchase.ID, "USD")},
+		{"missing expense", refundBody("10", purchase.ID+1000, "RUB")},
+		{"expense as refund", fmt.Sprintf(`{"amount": 10, "type": "expense", "category_id": %d, "linked_transaction_id": %d}`, category.ID, purchase.ID)},
+	} {
+		if w := do("POST", "/transactions", tc.body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d: %s", tc.name, http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	}
+
+	// Отвязанный возврат снова считается доходом
+	if w := do("PATCH", fmt.Sprintf("/transactions/%d", refund.ID), `{"linked_transaction_id": 0}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	totals, err = storage.GetTransactionTotals(user.ID, db.TransactionFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get totals: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Income != models.NewMoney(1500, 0) || totals[0].Expense != models.NewMoney(5000, 0) {
+		t.Errorf("Expected income 1500 and expense 5000, got %+v", totals)
+	}
+
+	if w := do("GET", "/transactions/999999/linked", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/loan"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// maxLoanTermMonths — наибольший срок кредита, 50 лет.
+const maxLoanTermMonths = 600
+
+// validateLoanTerms проверяет, что условия кредита заданы только для кредита и заданы полностью.
+// Без даты первого платежа она устанавливается через месяц после created.
+func validateLoanTerms(accountType string, terms *models.LoanTerms, created time.Time) error {
+	if accountType != "loan" {
+		if terms != nil {
+			return fieldError("loan", "loan terms are only allowed for loan accounts")
+		}
+		return nil
+	}
+	if terms == nil {
+		return fieldError("loan", "loan terms are required for loan accounts")
+	}
+	if terms.Principal <= 0 || terms.Principal > db.MaxAmount {
+		return fieldError("loan.principal", "loan principal must be positive and at most %s", db.MaxAmount)
+	}
+	if terms.InterestRate < 0 || terms.InterestRate >= 1000 {
+		return fieldError("loan.interest_rate", "loan interest_rate must be between 0 and 1000 percent")
+	}
+	if terms.TermMonths < 1 || terms.TermMonths > maxLoanTermMonths {
+		return fieldError("loan.term_months", "loan term_months must be between 1 and %d", maxLoanTermMonths)
+	}
+	if terms.FirstPaymentDate.IsZero() {
+		terms.FirstPaymentDate = loan.AddMonths(created.UTC(), 1)
+	}
+	terms.FirstPaymentDate = time.Date(terms.FirstPaymentDate.Year(), terms.FirstPaymentDate.Month(), terms.FirstPaymentDate.Day(), 0, 0, 0, 0, time.UTC)
+	return nil
+}
+
+// loadLoan читает счет кредита из параметра id; при ошибке или другом типе счета отвечает клиенту и возвращает false.
+func (h *Handler) loadLoan(c *gin.Context) (*models.Account, bool) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return nil, false
+	}
+	if account.Loan == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "account is not a loan"})
+		return nil, false
+	}
+	return account, true
+}
+
+// @Security ApiKeyAuth
+// @Summary График погашения кредита
+// @Description Возвращает аннуитетный график погашения по условиям кредита. Платежи отмечаются внесенными по числу внесенных платежей
+// @Tags loans
+// @Produce json
+// @Param id path int true "ID счета кредита"
+// @Success 200 {object} models.AmortizationSchedule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/amortization [get]
+func (h *Handler) GetAmortizationSchedule(c *gin.Context) {
+	account, ok := h.loadLoan(c)
+	if !ok {
+		return
+	}
+	payments, _, err := h.storage.GetLoanPaymentTotals(account.ID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule := models.AmortizationSchedule{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		Payment:   loan.Payment(account.Loan.Principal, account.Loan.InterestRate, account.Loan.TermMonths),
+		Rows:      loan.Schedule(*account.Loan),
+	}
+	for i := range schedule.Rows {
+		schedule.Rows[i].Paid = schedule.Rows[i].Number <= payments
+		schedule.TotalInterest += schedule.Rows[i].Interest
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// @Security ApiKeyAuth
+// @Summary Состояние кредита
+// @Description Возвращает остаток основного долга, уплаченные проценты и следующий платеж по графику
+// @Tags loans
+// @Produce json
+// @Param id path int true "ID счета кредита"
+// @Success 200 {object} models.LoanStatus
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/loan [get]
+func (h *Handler) GetLoanStatus(c *gin.Context) {
+	account, ok := h.loadLoan(c)
+	if !ok {
+		return
+	}
+	payments, interest, err := h.storage.GetLoanPaymentTotals(account.ID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := models.LoanStatus{
+		AccountID:    account.ID,
+		Currency:     account.Currency,
+		Principal:    account.Loan.Principal,
+		InterestPaid: interest,
+		PaymentsMade: payments,
+	}
+	if account.Balance < 0 {
+		status.RemainingBalance = -account.Balance
+	}
+	status.PrincipalPaid = status.Principal - status.RemainingBalance
+	if rows := loan.Schedule(*account.Loan); status.RemainingBalance > 0 && payments < len(rows) {
+		status.NextPaymentDate = &rows[payments].Date
+		status.NextPayment = rows[payments].Payment
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// @Security ApiKeyAuth
+// @Summary Внести платеж по кредиту
+// @Description Проценты за месяц на остаток долга записываются расходом счета-источника в категории category_id,
+// @Description остальная сумма переводится на счет кредита и уменьшает долг. Без суммы вносится платеж по графику
+// @Tags loans
+// @Accept json
+// @Produce json
+// @Param id path int true "ID счета кредита"
+// @Param payment body models.CreateLoanPayment true "Данные платежа"
+// @Success 201 {object} models.LoanPayment
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/loan-payments [post]
+func (h *Handler) CreateLoanPayment(c *gin.Context) {
+	account, ok := h.loadLoan(c)
+	if !ok || !canEditAccount(c, account) {
+		return
+	}
+
+	var request models.CreateLoanPayment
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	payment, err := h.storage.CreateLoanPayment(c.GetInt("user_id"), account.ID, request)
+	if err != nil {
+		message := err.Error()
+		if strings.Contains(message, "account") || strings.Contains(message, "amount") || strings.Contains(message, "loan") ||
+			strings.Contains(message, "category") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+		}
+		return
+	}
+	if payment == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestLoans тестирует кредиты: график погашения, платежи и состояние кредита.
+func TestLoans(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "interest")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	checking, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Карта", Currency: "RUB", InitialBalance: models.NewMoney(50000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	dollars, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Доллары", Currency: "USD", InitialBalance: models.NewMoney(50000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, invalid := range []models.CreateAccount{
+		{Name: "Кредит", Type: "loan"},
+		{Name: "Кредит", Type: "loan", Loan: &models.LoanTerms{Principal: models.NewMoney(100000, 0), InterestRate: 12}},
+		{Name: "Карта", Loan: &models.LoanTerms{Principal: models.NewMoney(100000, 0), InterestRate: 12, TermMonths: 12}},
+	} {
+		if w := send("POST", "/accounts", invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d", http.StatusBadRequest, invalid, w.Code)
+		}
+	}
+
+	first := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	w := send("POST", "/accounts", models.CreateAccount{Name: "Кредит", Type: "loan", Currency: "RUB",
+		Loan: &models.LoanTerms{Principal: models.NewMoney(100000, 0), InterestRate: 12, TermMonths: 12, FirstPaymentDate: first}})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var loanAccount models.Account
+	json.NewDecoder(w.Body).Decode(&loanAccount)
+	if loanAccount.InitialBalance != models.NewMoney(-100000, 0) || loanAccount.Loan == nil || loanAccount.Loan.TermMonths != 12 {
+		t.Errorf("Unexpected loan account: %+v", loanAccount)
+	}
+
+	w = send("GET", fmt.Sprintf("/accounts/%d/amortization", loanAccount.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var schedule models.AmortizationSchedule
+	json.NewDecoder(w.Body).Decode(&schedule)
+	if schedule.Payment != models.NewMoney(8884, 88) || len(schedule.Rows) != 12 || schedule.Rows[0].Paid ||
+		!schedule.Rows[0].Date.Equal(first) {
+		t.Errorf("Unexpected schedule: %+v", schedule)
+	}
+
+	// Для процентов нужна категория, валюта счета-источника должна совпадать с валютой кредита
+	if w := send("POST", fmt.Sprintf("/accounts/%d/loan-payments", loanAccount.ID), models.CreateLoanPayment{FromAccountID: checking.ID}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", fmt.Sprintf("/accounts/%d/loan-payments", loanAccount.ID), models.CreateLoanPayment{FromAccountID: dollars.ID, CategoryID: category.ID}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", fmt.Sprintf("/accounts/%d/loan-payments", loanAccount.ID), models.CreateLoanPayment{FromAccountID: checking.ID, CategoryID: category.ID, Amount: models.NewMoney(500, 0)}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Платеж по графику: 1000 процентов за месяц и 7884.88 основного долга
+	w = send("POST", fmt.Sprintf("/accounts/%d/loan-payments", loanAccount.ID), models.CreateLoanPayment{FromAccountID: checking.ID, CategoryID: category.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var payment models.LoanPayment
+	json.NewDecoder(w.Body).Decode(&payment)
+	if payment.Amount != models.NewMoney(8884, 88) || payment.Interest != models.NewMoney(1000, 0) || payment.Principal != models.NewMoney(7884, 88) ||
+		payment.TransferID == 0 || payment.InterestTransactionID == 0 {
+		t.Errorf("Unexpected payment: %+v", payment)
+	}
+
+	getStatus := func() models.LoanStatus {
+		w := send("GET", fmt.Sprintf("/accounts/%d/loan", loanAccount.ID), nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var status models.LoanStatus
+		json.NewDecoder(w.Body).Decode(&status)
+		return status
+	}
+	status := getStatus()
+	if status.RemainingBalance != models.NewMoney(92115, 12) || status.PrincipalPaid != models.NewMoney(7884, 88) ||
+		status.InterestPaid != models.NewMoney(1000, 0) || status.PaymentsMade != 1 ||
+		status.NextPaymentDate == nil || !status.NextPaymentDate.Equal(first.AddDate(0, 1, 0)) {
+		t.Errorf("Unexpected loan status: %+v", status)
+	}
+	account, err := storage.GetAccount(checking.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get account: %v", err)
+	}
+	if account.Balance != models.NewMoney(41115, 12) {
+		t.Errorf("Expected checking balance 41115.12, got %s", account.Balance)
+	}
+
+	// Удаленный перевод платежа не учитывается
+	if w := send("DELETE", fmt.Sprintf("/transfers/%d", payment.TransferID), nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if status := getStatus(); status.RemainingBalance != models.NewMoney(100000, 0) || status.PaymentsMade != 0 || status.InterestPaid != 0 {
+		t.Errorf("Unexpected loan status after deleting the payment: %+v", status)
+	}
+
+	if w := send("GET", fmt.Sprintf("/accounts/%d/loan", checking.ID), nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
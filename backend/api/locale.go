@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// localeWriter переводит тексты ошибок в JSON-ответах на язык запроса.
+type localeWriter struct {
+	gin.ResponseWriter
+	lang string
+}
+
+func (w *localeWriter) Write(b []byte) (int, error) {
+	if w.Status() < 400 || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return w.ResponseWriter.Write(b)
+	}
+	body := bytes.TrimSpace(b)
+	if len(body) < 2 || body[0] != '{' {
+		return w.ResponseWriter.Write(b)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return w.ResponseWriter.Write(b)
+	}
+	var message string
+	if err := json.Unmarshal(fields["error"], &message); err == nil {
+		fields["error"], _ = json.Marshal(i18n.Translate(w.lang, message))
+	}
+	var fieldErrors []map[string]any
+	if err := json.Unmarshal(fields["fields"], &fieldErrors); err == nil {
+		for _, fe := range fieldErrors {
+			if message, ok := fe["message"].(string); ok {
+				fe["message"] = i18n.Translate(w.lang, message)
+			}
+		}
+		fields["fields"], _ = json.Marshal(fieldErrors)
+	}
+	translated, err := json.Marshal(fields)
+	if err != nil {
+		return w.ResponseWriter.Write(b)
+	}
+	if _, err := w.ResponseWriter.Write(translated); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *localeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// LocaleMiddleware выбирает язык ответа по заголовку Accept-Language, сохраняет его в контексте
+// под ключом lang и возвращает в заголовке Content-Language. Тексты ошибок в ответах
+// переводятся на этот язык; сообщения без перевода остаются на английском.
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Match(c.GetHeader("Accept-Language"))
+		c.Set("lang", lang)
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		if lang != i18n.Default {
+			c.Writer = &localeWriter{ResponseWriter: c.Writer, lang: lang}
+		}
+		c.Next()
+	}
+}
+
+// language возвращает язык запроса, выбранный LocaleMiddleware.
+func language(c *gin.Context) string {
+	if lang := c.GetString("lang"); lang != "" {
+		return lang
+	}
+	return i18n.Match(c.GetHeader("Accept-Language"))
+}
+
+// @Security ApiKeyAuth
+// @Summary Изменить язык писем
+// @Description Устанавливает язык писем, которые отправляются без запроса пользователя: отчетов и уведомлений
+// @Description о бюджетах. Язык ответов API и остальных писем выбирается по заголовку Accept-Language
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body models.SetLanguageRequest true "Код языка"
+// @Success 200 {object} models.Profile
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /me/language [put]
+func (h *Handler) SetLanguage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.SetLanguageRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updated, err := h.storage.SetLanguage(userID.(int), req.Language)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil || user == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load user"})
+		return
+	}
+	c.JSON(http.StatusOK, profileOf(user))
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestLocaleMiddleware тестирует перевод ошибок на язык из заголовка Accept-Language.
+func TestLocaleMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), LocaleMiddleware())
+	r.GET("/error", func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"error": "category not found"}) })
+	r.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"name": "category not found"}) })
+	r.POST("/transactions", func(c *gin.Context) {
+		var request models.CreateTransactionRequest
+		if bindJSON(c, &request) {
+			c.Status(http.StatusCreated)
+		}
+	})
+
+	do := func(method, path, body, acceptLanguage string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("GET", "/error", "", "ru-RU,ru;q=0.9,en;q=0.8")
+	var response models.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error != "категория не найдена" || response.RequestID == "" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+	if w.Header().Get("Content-Language") != "ru" || w.Header().Get("Vary") != "Accept-Language" {
+		t.Errorf("Unexpected headers %v", w.Header())
+	}
+
+	w = do("GET", "/error", "", "")
+	if w.Header().Get("Content-Language") != "en" || !bytes.Contains(w.Body.Bytes(), []byte(`"error":"category not found"`)) {
+		t.Errorf("Expected english error, got %q: %s", w.Header().Get("Content-Language"), w.Body.String())
+	}
+
+	// Успешные ответы не меняются
+	if w = do("GET", "/ok", "", "ru"); w.Body.String() != `{"name":"category not found"}` {
+		t.Errorf("Unexpected body %s", w.Body.String())
+	}
+
+	// Ошибки полей переводятся вместе с общим текстом ошибки
+	w = do("POST", "/transactions", `{"amount": 100, "type": "other", "category_id": 1}`, "ru")
+	var validation models.ValidationErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&validation); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if validation.Error != "ошибка проверки данных" || len(validation.Fields) != 1 ||
+		validation.Fields[0].Field != "type" || validation.Fields[0].Message != "type должен быть 'income' или 'expense'" {
+		t.Errorf("Unexpected response %+v", validation)
+	}
+}
+
+// TestSetLanguage тестирует смену языка писем.
+func TestSetLanguage(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("languser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if user.Language != "en" {
+		t.Errorf("Expected default language en, got %q", user.Language)
+	}
+	token := getToken(t, r, "languser", "password123")
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", "/me/language", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := put(`{"language": "ru"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var profile models.Profile
+	if err := json.NewDecoder(w.Body).Decode(&profile); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if profile.Language != "ru" {
+		t.Errorf("Expected language ru, got %q", profile.Language)
+	}
+
+	w = put(`{"language": "de"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if fields := decodeFieldErrors(t, w); fields["language"] != "language must be a supported language" {
+		t.Errorf("Unexpected field errors %v", fields)
+	}
+}
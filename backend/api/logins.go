@@ -0,0 +1,71 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/i18n"
+)
+
+// recordLogin сохраняет успешный вход в историю и, если включено, предупреждает
+// пользователя по email о входе с нового устройства. Ошибки не прерывают вход.
+func (h *Handler) recordLogin(c *gin.Context, userID int) {
+	newDevice, err := h.storage.RecordLogin(userID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		log.Printf("failed to record login for user %d: %v", userID, err)
+		return
+	}
+	if !newDevice || !h.cfg.LoginAlerts {
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID)
+	if err != nil || user == nil || user.Email == "" {
+		return
+	}
+
+	lang := language(c)
+	body := i18n.T(lang, "email.login_alert.body", user.Username, time.Now().Format(time.RFC1123), c.ClientIP(), c.Request.UserAgent())
+	if err := h.cfg.Mailer.Send(user.Email, i18n.T(lang, "email.login_alert.subject"), body); err != nil {
+		log.Printf("failed to send login alert to user %d: %v", userID, err)
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary История входов
+// @Description Возвращает последние успешные входы пользователя с IP-адресом и User-Agent
+// @Tags me
+// @Produce json
+// @Param limit query int false "Количество записей (1-100, по умолчанию 20)"
+// @Success 200 {array} models.LoginEvent
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /me/logins [get]
+func (h *Handler) GetLogins(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+	}
+
+	logins, err := h.storage.GetLogins(userID.(int), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, logins)
+}
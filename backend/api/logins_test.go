@@ -0,0 +1,69 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestGetLogins тестирует запись и получение истории входов.
+func TestGetLogins(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.CreateUser("testuser", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// Входим дважды с разных устройств
+	for _, userAgent := range []string{"phone", "laptop"} {
+		body, _ := json.Marshal(models.LoginRequest{Username: "testuser", Password: "password123"})
+		req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	}
+
+	// Неудачный вход в историю не попадает
+	body, _ := json.Marshal(models.LoginRequest{Username: "testuser", Password: "wrong"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	token := getToken(t, r, "testuser", "password123")
+
+	req, _ = http.NewRequest("GET", "/me/logins?limit=2", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var logins []models.LoginEvent
+	if err := json.NewDecoder(w.Body).Decode(&logins); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	// Последним был вход через getToken, перед ним — с ноутбука
+	if len(logins) != 2 || logins[1].UserAgent != "laptop" {
+		t.Errorf("Expected 2 latest logins ending with 'laptop', got %+v", logins)
+	}
+
+	// Некорректный лимит
+	req, _ = http.NewRequest("GET", "/me/logins?limit=0", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
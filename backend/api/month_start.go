@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Security ApiKeyAuth
+// @Summary Изменить день начала месяца
+// @Description Устанавливает день, с которого в отчетах начинаются месяцы, например день зарплаты: при 25 месяц
+// @Description длится с 25 числа по 24 следующего месяца, кварталы и годы складываются из таких месяцев.
+// @Description Бюджеты и отчеты за явно указанные календарные месяцы не меняются
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body models.SetMonthStartRequest true "День месяца от 1 до 28"
+// @Success 200 {object} models.Profile
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /me/month-start [put]
+func (h *Handler) SetMonthStart(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.SetMonthStartRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updated, err := h.storage.SetMonthStart(userID.(int), req.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil || user == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load user"})
+		return
+	}
+	c.JSON(http.StatusOK, profileOf(user))
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	oidcNonceCookie = "oidc_nonce"
+	oidcCookieTTL   = 600
+)
+
+// OIDCConfig содержит настройки внешнего OpenID Connect провайдера
+// (Keycloak, Authelia и т.п.).
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// OIDCProvider выполняет вход через внешний OpenID Connect провайдер.
+type OIDCProvider struct {
+	issuer   string
+	verifier *oidc.IDTokenVerifier
+	oauth2   oauth2.Config
+}
+
+// NewOIDCProvider получает настройки провайдера через discovery
+// (/.well-known/openid-configuration).
+func NewOIDCProvider(ctx context.Context, cfg OIDCConfig) (*OIDCProvider, error) {
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+
+	return &OIDCProvider{
+		issuer:   cfg.IssuerURL,
+		verifier: provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		},
+	}, nil
+}
+
+// cookiePath возвращает путь cookie состояния входа: каталог адреса возврата, чтобы браузер прислал их
+// на callback независимо от того, через какую группу маршрутов (/api/v1 или без версии) начат вход.
+func (p *OIDCProvider) cookiePath() string {
+	redirect, err := url.Parse(p.oauth2.RedirectURL)
+	if err != nil || redirect.Path == "" {
+		return "/"
+	}
+	return path.Dir(redirect.Path)
+}
+
+// oidcClaims — поля ID токена, используемые для привязки пользователя.
+type oidcClaims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// @Summary Вход через OIDC провайдер
+// @Description Перенаправляет пользователя на страницу входа настроенного OpenID Connect провайдера
+// @Tags auth
+// @Success 302
+// @Failure 404 {object} models.ErrorResponse
+// @Router /auth/oidc/login [get]
+func (h *Handler) OIDCLogin(c *gin.Context) {
+	if h.cfg.OIDC == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "oidc is not configured"})
+		return
+	}
+
+	state, err := randomString()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	nonce, err := randomString()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	secure := c.Request.TLS != nil
+	cookiePath := h.cfg.OIDC.cookiePath()
+	c.SetCookie(oidcStateCookie, state, oidcCookieTTL, cookiePath, "", secure, true)
+	c.SetCookie(oidcNonceCookie, nonce, oidcCookieTTL, cookiePath, "", secure, true)
+	c.Redirect(http.StatusFound, h.cfg.OIDC.oauth2.AuthCodeURL(state, oidc.Nonce(nonce)))
+}
+
+// @Summary Обработка ответа OIDC провайдера
+// @Description Обменивает код авторизации на ID токен, находит или создает связанного пользователя и возвращает JWT токен
+// @Tags auth
+// @Produce json
+// @Param code query string true "Код авторизации"
+// @Param state query string true "Состояние из запроса входа"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /auth/oidc/callback [get]
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	provider := h.cfg.OIDC
+	if provider == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "oidc is not configured"})
+		return
+	}
+
+	state, err := c.Cookie(oidcStateCookie)
+	if err != nil || state == "" || c.Query("state") != state {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid oidc state"})
+		return
+	}
+	nonce, _ := c.Cookie(oidcNonceCookie)
+
+	oauth2Token, err := provider.oauth2.Exchange(c.Request.Context(), c.Query("code"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "failed to exchange authorization code"})
+		return
+	}
+
+	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "id_token is missing"})
+		return
+	}
+
+	idToken, err := provider.verifier.Verify(c.Request.Context(), rawIDToken)
+	if err != nil || idToken.Nonce != nonce {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid id_token"})
+		return
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid id_token claims"})
+		return
+	}
+
+	user, err := h.resolveOIDCUser(provider.issuer, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.SetCookie(oidcStateCookie, "", -1, provider.cookiePath(), "", false, true)
+	c.SetCookie(oidcNonceCookie, "", -1, provider.cookiePath(), "", false, true)
+	h.respondWithToken(c, user, h.cfg.TokenTTL)
+}
+
+// resolveOIDCUser находит пользователя по привязке к провайдеру. Если привязки нет, учетная запись
+// связывается с существующим пользователем по email, только если адрес подтвержден и провайдером,
+// и самим пользователем: при регистрации email не проверяется, и иначе вход через провайдер открыл бы
+// чужую учетную запись, зарегистрированную на этот адрес. В остальных случаях создается новый пользователь.
+func (h *Handler) resolveOIDCUser(issuer string, claims oidcClaims) (*models.User, error) {
+	userID, err := h.storage.GetUserIDByIdentity(issuer, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if userID != 0 {
+		return h.storage.GetUserByID(userID)
+	}
+
+	var user *models.User
+	if claims.Email != "" {
+		existing, err := h.storage.GetUserByEmail(claims.Email)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && claims.EmailVerified && existing.EmailVerified {
+			user = existing
+		} else if existing != nil {
+			// Адрес занят другим пользователем: новый пользователь создается без email
+			if claims.PreferredUsername == "" {
+				claims.PreferredUsername, _, _ = strings.Cut(claims.Email, "@")
+			}
+			claims.Email, claims.EmailVerified = "", false
+		}
+	}
+
+	if user == nil {
+		user, err = h.createOIDCUser(claims)
+		if err != nil {
+			return nil, err
+		}
+		if claims.EmailVerified && user.Email != "" {
+			if err := h.storage.MarkEmailVerified(user.ID); err != nil {
+				return nil, err
+			}
+			user.EmailVerified = true
+		}
+	}
+
+	if err := h.storage.LinkIdentity(user.ID, issuer, claims.Subject); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createOIDCUser создает пользователя со случайным паролем и свободным именем,
+// основанным на preferred_username или email.
+func (h *Handler) createOIDCUser(claims oidcClaims) (*models.User, error) {
+	if h.cfg.RegistrationMode == RegistrationInvite {
+		return nil, fmt.Errorf("registration is invite-only")
+	}
+
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	password, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	username := base
+	for i := 1; ; i++ {
+		existing, err := h.storage.GetUserByUsername(username)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			break
+		}
+		username = fmt.Sprintf("%s%d", base, i)
+	}
+
+	return h.storage.CreateUserWithEmail(username, password, claims.Email)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"golang.org/x/oauth2"
+)
+
+// TestResolveOIDCUser тестирует привязку учетных записей OIDC провайдера к пользователям.
+func TestResolveOIDCUser(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+	handler := NewHandler(storage, Config{JWTSecret: "secret"})
+
+	const issuer = "https://id.example.com"
+
+	// Существующий пользователь с email
+	existing, err := storage.CreateUserWithEmail("alice", "password123", "alice@example.com")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// Email, не подтвержденный самим пользователем, не связывается: его мог указать кто угодно
+	user, err := handler.resolveOIDCUser(issuer, oidcClaims{Subject: "sub-0", Email: "Alice@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("Failed to resolve user: %v", err)
+	}
+	if user.ID == existing.ID || user.Email != "" || user.Username != "Alice" {
+		t.Errorf("Expected separate user without email, got %+v", user)
+	}
+
+	// Email, подтвержденный и провайдером, и пользователем, связывается с существующим пользователем
+	if err := storage.MarkEmailVerified(existing.ID); err != nil {
+		t.Fatalf("Failed to verify email: %v", err)
+	}
+	user, err = handler.resolveOIDCUser(issuer, oidcClaims{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("Failed to resolve user: %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("Expected user %d, got %d", existing.ID, user.ID)
+	}
+
+	// Повторный вход находит пользователя по привязке
+	user, err = handler.resolveOIDCUser(issuer, oidcClaims{Subject: "sub-1"})
+	if err != nil {
+		t.Fatalf("Failed to resolve user: %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("Expected user %d, got %d", existing.ID, user.ID)
+	}
+
+	// Email, не подтвержденный провайдером, не связывается
+	user, err = handler.resolveOIDCUser(issuer, oidcClaims{Subject: "sub-2", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("Failed to resolve user: %v", err)
+	}
+	if user.ID == existing.ID || user.Email != "" {
+		t.Errorf("Expected separate user without email, got %+v", user)
+	}
+
+	// Новый пользователь создается со свободным именем и подтвержденным провайдером email
+	user, err = handler.resolveOIDCUser(issuer, oidcClaims{Subject: "sub-3", PreferredUsername: "alice", Email: "bob@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("Failed to resolve user: %v", err)
+	}
+	if user.ID == existing.ID || user.Username != "alice2" || !user.EmailVerified {
+		t.Errorf("Expected new user 'alice2' with verified email, got %+v", user)
+	}
+}
+
+// TestOIDCLoginCallbackV1 тестирует, что cookie состояния, выданные при входе через /api/v1,
+// браузер отправляет на callback этой же группы маршрутов.
+func TestOIDCLoginCallbackV1(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	// Провайдер отклоняет обмен кода: до него callback доходит, только если проверка состояния прошла
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer tokenServer.Close()
+
+	provider := &OIDCProvider{
+		issuer:   "https://id.example.com",
+		verifier: oidc.NewVerifier("https://id.example.com", &oidc.StaticKeySet{}, &oidc.Config{ClientID: "fin"}),
+		oauth2: oauth2.Config{ClientID: "fin", Endpoint: oauth2.Endpoint{
+			AuthURL: "https://id.example.com/auth", TokenURL: tokenServer.URL, AuthStyle: oauth2.AuthStyleInParams}},
+	}
+	handler := NewHandler(nil, Config{JWTSecret: "secret", OIDC: provider})
+	r := gin.New()
+	handler.RegisterRoutes(r.Group(V1Prefix))
+	server := httptest.NewServer(r)
+	defer server.Close()
+	provider.oauth2.RedirectURL = server.URL + V1Prefix + "/auth/oidc/callback"
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create cookie jar: %v", err)
+	}
+	client := &http.Client{Jar: jar, CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	resp, err := client.Get(server.URL + V1Prefix + "/auth/oidc/login")
+	if err != nil {
+		t.Fatalf("Login request failed: %v", err)
+	}
+	resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if resp.StatusCode != http.StatusFound || err != nil {
+		t.Fatalf("Expected redirect to provider, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Path != V1Prefix+"/auth/oidc" {
+			t.Errorf("Expected cookie %s path %s, got %q", cookie.Name, V1Prefix+"/auth/oidc", cookie.Path)
+		}
+	}
+
+	resp, err = client.Get(provider.oauth2.RedirectURL + "?code=abc&state=" + url.QueryEscape(location.Query().Get("state")))
+	if err != nil {
+		t.Fatalf("Callback request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var response models.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized || response.Error != "failed to exchange authorization code" {
+		t.Errorf("Expected state check to pass, got %d %q", resp.StatusCode, response.Error)
+	}
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// applyTransactionPatch переносит в транзакцию переданные поля частичного обновления.
+func applyTransactionPatch(t *models.Transaction, patch models.PatchTransaction) {
+	if patch.Amount != nil {
+		t.Amount = *patch.Amount
+	}
+	if patch.Type != nil {
+		t.Type = *patch.Type
+	}
+	if patch.CategoryID != nil {
+		t.CategoryID = *patch.CategoryID
+	}
+	if patch.Date != nil {
+		t.Date = *patch.Date
+	}
+	if patch.Description != nil {
+		t.Description = *patch.Description
+	}
+	if patch.Currency != nil {
+		t.Currency = *patch.Currency
+	}
+	if patch.Tags != nil {
+		t.Tags = *patch.Tags
+	}
+	if patch.Planned != nil {
+		t.Planned = *patch.Planned
+	}
+	if patch.Status != nil {
+		t.Status = *patch.Status
+	}
+	// Контрагент задается либо ID, либо именем
+	if patch.PayeeID != nil {
+		t.PayeeID, t.Payee = *patch.PayeeID, ""
+	} else if patch.Payee != nil {
+		t.PayeeID, t.Payee = 0, *patch.Payee
+	}
+	if patch.LinkedTransactionID != nil {
+		t.LinkedTransactionID = *patch.LinkedTransactionID
+	}
+	if patch.OriginalAmount != nil {
+		t.OriginalAmount = *patch.OriginalAmount
+	}
+	if patch.OriginalCurrency != nil {
+		t.OriginalCurrency = *patch.OriginalCurrency
+	}
+	if patch.AccountID != nil {
+		t.AccountID = *patch.AccountID
+	}
+	if patch.FXRate != nil {
+		t.FXRate = *patch.FXRate
+	} else if patch.Amount != nil || patch.OriginalAmount != nil {
+		// Прежний курс не соответствует новым суммам и вычисляется заново
+		t.FXRate = 0
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary Частично обновить транзакцию
+// @Description Изменяет только переданные поля транзакции; остальные сохраняют прежние значения
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID транзакции"
+// @Param transaction body models.PatchTransaction true "Изменяемые поля"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id} [patch]
+func (h *Handler) PatchTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	var patch models.PatchTransaction
+	if !bindJSON(c, &patch) {
+		return
+	}
+
+	transaction, err := h.storage.GetTransaction(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if transaction == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
+	applyTransactionPatch(transaction, patch)
+	if err := validateTransaction(*transaction); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	ok, err := h.storage.UpdateTransaction(transaction)
+	if err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// @Security ApiKeyAuth
+// @Summary Частично обновить категорию
+// @Description Изменяет только переданные поля категории
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param category body models.PatchCategory true "Изменяемые поля"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /categories/{id} [patch]
+func (h *Handler) PatchCategory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	var patch models.PatchCategory
+	if !bindJSON(c, &patch) {
+		return
+	}
+
+	category, err := h.storage.GetCategory(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if category == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+	if category.System {
+		c.JSON(http.StatusForbidden, gin.H{"error": "system categories are read-only"})
+		return
+	}
+
+	if patch.Name != nil {
+		category.Name = *patch.Name
+	}
+	if patch.Icon != nil {
+		category.Icon = *patch.Icon
+	}
+	if patch.Color != nil {
+		category.Color = *patch.Color
+	}
+	if category.Icon, category.Color, err = normalizeCategoryAppearance(category.Icon, category.Color); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.storage.UpdateCategoryWithAppearance(id, userID.(int), category.Name, category.Icon, category.Color)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
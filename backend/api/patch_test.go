@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestPatchTransaction тестирует частичное обновление транзакции.
+func TestPatchTransaction(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(1500, 0), Type: "expense", CategoryID: category.ID, Date: date,
+		Description: "Продкуты", Currency: "EUR", Tags: []string{"groceries"}, Payee: "Лента", Status: "pending"}
+	if err := storage.CreateTransaction(original); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	patch := func(id int, body string) (int, models.Transaction) {
+		req, _ := http.NewRequest("PATCH", fmt.Sprintf("/transactions/%d", id), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var transaction models.Transaction
+		json.NewDecoder(w.Body).Decode(&transaction)
+		return w.Code, transaction
+	}
+
+	// Исправление опечатки не затрагивает остальные поля
+	code, patched := patch(original.ID, `{"description": "Продукты"}`)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+	}
+	saved, err := storage.GetTransaction(original.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	for _, tr := range []models.Transaction{patched, *saved} {
+		if tr.Description != "Продукты" || tr.Amount != models.NewMoney(1500, 0) || tr.Type != "expense" || tr.CategoryID != category.ID ||
+			!tr.Date.Equal(date) || tr.Currency != "EUR" || !reflect.DeepEqual(tr.Tags, []string{"groceries"}) ||
+			tr.Payee != "Лента" || tr.Status != "pending" {
+			t.Errorf("Expected only description to change, got %+v", tr)
+		}
+	}
+
+	// Пустые теги и контрагент очищаются
+	code, patched = patch(original.ID, `{"amount": 1750.5, "tags": [], "payee": ""}`)
+	if code != http.StatusOK || patched.Amount != models.NewMoney(1750, 50) || len(patched.Tags) != 0 || patched.PayeeID != 0 || patched.Description != "Продукты" {
+		t.Errorf("Unexpected patched transaction: status %d and %+v", code, patched)
+	}
+
+	if code, _ := patch(original.ID, `{"amount": -1}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid amount, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := patch(original.ID, `{"type": "gift"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid type, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := patch(original.ID+100, `{"amount": 1}`); code != http.StatusNotFound {
+		t.Errorf("Expected status %d for missing transaction, got %d", http.StatusNotFound, code)
+	}
+}
+
+// TestPatchCategory тестирует частичное обновление категории.
+func TestPatchCategory(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	patch := func(id int, body string) (int, models.Category) {
+		req, _ := http.NewRequest("PATCH", fmt.Sprintf("/categories/%d", id), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var category models.Category
+		json.NewDecoder(w.Body).Decode(&category)
+		return w.Code, category
+	}
+
+	if code, patched := patch(category.ID, `{}`); code != http.StatusOK || patched.Name != "food" {
+		t.Errorf("Expected unchanged category, got status %d and %+v", code, patched)
+	}
+	if code, patched := patch(category.ID, `{"name": "groceries"}`); code != http.StatusOK || patched.Name != "groceries" {
+		t.Errorf("Expected renamed category, got status %d and %+v", code, patched)
+	}
+	if code, _ := patch(category.ID, `{"name": ""}`); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for empty name, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := patch(category.ID+100, `{"name": "x"}`); code != http.StatusNotFound {
+		t.Errorf("Expected status %d for missing category, got %d", http.StatusNotFound, code)
+	}
+}
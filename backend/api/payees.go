@@ -0,0 +1,240 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// payeeErrorStatus возвращает код ответа для ошибки хранилища контрагентов.
+func payeeErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "payee name") || strings.Contains(err.Error(), "payee already exists") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать контрагента
+// @Description Создает контрагента пользователя. Имя уникально без учета регистра
+// @Tags payees
+// @Accept json
+// @Produce json
+// @Param payee body models.CreatePayee true "Данные контрагента"
+// @Success 201 {object} models.Payee
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /payees [post]
+func (h *Handler) CreatePayee(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.CreatePayee
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	payee, err := h.storage.CreatePayee(userID.(int), req.Name)
+	if err != nil {
+		c.JSON(payeeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, payee)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить список контрагентов
+// @Description Получает контрагентов пользователя для автодополнения: с q — только содержащих подстроку,
+// @Description сначала совпадения с начала имени, затем самые используемые
+// @Tags payees
+// @Produce json
+// @Param q query string false "Подстрока имени"
+// @Param limit query int false "Максимальное число результатов (по умолчанию 10, не больше 100)"
+// @Success 200 {array} models.Payee
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /payees [get]
+func (h *Handler) GetPayees(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	_, limit, err := parsePageLimit(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payees, err := h.storage.GetPayees(userID.(int), strings.TrimSpace(c.Query("q")), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, payees)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить контрагента
+// @Description Получает контрагента пользователя по ID
+// @Tags payees
+// @Produce json
+// @Param id path int true "ID контрагента"
+// @Success 200 {object} models.Payee
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /payees/{id} [get]
+func (h *Handler) GetPayee(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payee id"})
+		return
+	}
+
+	payee, err := h.storage.GetPayee(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if payee == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payee not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, payee)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить статистику по контрагенту
+// @Description Получает число транзакций контрагента, даты первой и последней из них и суммы доходов и расходов по валютам.
+// @Description Запланированные транзакции не учитываются
+// @Tags payees
+// @Produce json
+// @Param id path int true "ID контрагента"
+// @Success 200 {object} models.PayeeStats
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /payees/{id}/stats [get]
+func (h *Handler) GetPayeeStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payee id"})
+		return
+	}
+
+	stats, err := h.storage.GetPayeeStats(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if stats == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payee not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// @Security ApiKeyAuth
+// @Summary Переименовать контрагента
+// @Description Переименовывает контрагента пользователя; новое имя применяется ко всем его транзакциям
+// @Tags payees
+// @Accept json
+// @Produce json
+// @Param id path int true "ID контрагента"
+// @Param payee body models.CreatePayee true "Новое имя контрагента"
+// @Success 200 {object} models.Payee
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /payees/{id} [put]
+func (h *Handler) UpdatePayee(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payee id"})
+		return
+	}
+
+	var req models.CreatePayee
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updated, err := h.storage.UpdatePayee(id, userID.(int), req.Name)
+	if err != nil {
+		c.JSON(payeeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payee not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Payee{ID: id, UserID: userID.(int), Name: strings.TrimSpace(req.Name)})
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить контрагента
+// @Description Удаляет контрагента пользователя; его транзакции остаются без контрагента
+// @Tags payees
+// @Produce json
+// @Param id path int true "ID контрагента"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /payees/{id} [delete]
+func (h *Handler) DeletePayee(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payee id"})
+		return
+	}
+
+	deleted, err := h.storage.DeletePayee(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payee not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestPayeeEndpoints тестирует CRUD контрагентов, автодополнение и фильтр транзакций по контрагенту.
+func TestPayeeEndpoints(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	do := func(method, url string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		req, _ := http.NewRequest(method, url, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/payees", models.CreatePayee{Name: "Пятерочка"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var payee models.Payee
+	json.NewDecoder(w.Body).Decode(&payee)
+	if w := do("POST", "/payees", models.CreatePayee{Name: "пятерочка"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for duplicate payee, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Транзакция с новым именем контрагента создает его
+	w = do("POST", "/transactions", map[string]interface{}{"amount": 100, "type": "expense", "category_id": category.ID, "payee": "Лента"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	w = do("POST", "/transactions", map[string]interface{}{"amount": 200, "type": "expense", "category_id": category.ID, "payee_id": payee.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w = do("GET", "/payees?q=лен", nil)
+	var payees []models.Payee
+	json.NewDecoder(w.Body).Decode(&payees)
+	if w.Code != http.StatusOK || len(payees) != 1 || payees[0].Name != "Лента" {
+		t.Errorf("Expected autocomplete to find Лента, got status %d and %+v", w.Code, payees)
+	}
+
+	w = do("GET", fmt.Sprintf("/transactions?payee_id=%d", payee.ID), nil)
+	var list models.GetTransactionsResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	if w.Code != http.StatusOK || list.Total != 1 || list.Transactions[0].Payee != "Пятерочка" {
+		t.Errorf("Expected 1 transaction for payee, got status %d and %+v", w.Code, list)
+	}
+
+	w = do("GET", fmt.Sprintf("/payees/%d/stats", payee.ID), nil)
+	var stats models.PayeeStats
+	json.NewDecoder(w.Body).Decode(&stats)
+	if w.Code != http.StatusOK || stats.TransactionCount != 1 || len(stats.Totals) != 1 || stats.Totals[0].Expense != models.NewMoney(200, 0) {
+		t.Errorf("Unexpected payee stats: status %d and %+v", w.Code, stats)
+	}
+
+	if w := do("PUT", fmt.Sprintf("/payees/%d", payee.ID), models.CreatePayee{Name: "Пятерочка у дома"}); w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w := do("DELETE", fmt.Sprintf("/payees/%d", payee.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := do("GET", fmt.Sprintf("/payees/%d", payee.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
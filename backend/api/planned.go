@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// convertPlanned превращает запланированные транзакции в обычные по наступлении их даты.
+func (h *Handler) convertPlanned(context.Context) error {
+	converted, err := h.storage.ConvertDuePlannedTransactions(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to convert planned transactions: %w", err)
+	}
+	if converted > 0 {
+		log.Printf("converted %d planned transactions", converted)
+	}
+	return nil
+}
+
+// parseIncludePlanned читает параметр include_planned (по умолчанию false).
+func parseIncludePlanned(c *gin.Context) (bool, error) {
+	value := c.Query("include_planned")
+	if value == "" {
+		return false, nil
+	}
+	includePlanned, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("include_planned must be 'true' or 'false'")
+	}
+	return includePlanned, nil
+}
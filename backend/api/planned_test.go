@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestPlannedTransactions тестирует создание запланированных транзакций и параметр include_planned.
+func TestPlannedTransactions(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "rent")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	create := func(date time.Time, planned bool) int {
+		body, _ := json.Marshal(map[string]interface{}{
+			"amount": 30000, "type": "expense", "category_id": category.ID, "date": date, "planned": planned,
+		})
+		req, _ := http.NewRequest("POST", "/transactions", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := create(time.Now().AddDate(0, 0, -1), true); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for planned transaction in the past, got %d", http.StatusBadRequest, code)
+	}
+	if code := create(time.Now().AddDate(0, 1, 0), true); code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+	if code := create(time.Now().AddDate(0, 0, -1), false); code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+
+	list := func(query string) (int, models.GetTransactionsResponse) {
+		req, _ := http.NewRequest("GET", "/transactions"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.GetTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+
+	if code, response := list(""); code != http.StatusOK || response.Total != 1 || response.Transactions[0].Planned {
+		t.Errorf("Expected only the regular transaction, got status %d and %+v", code, response)
+	}
+	if code, response := list("?include_planned=true"); code != http.StatusOK || response.Total != 2 {
+		t.Errorf("Expected 2 transactions with include_planned, got status %d and %+v", code, response)
+	}
+	if code, _ := list("?include_planned=maybe"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid include_planned, got %d", http.StatusBadRequest, code)
+	}
+}
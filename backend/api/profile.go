@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// profileOf возвращает профиль пользователя без секретных полей.
+func profileOf(user *models.User) models.Profile {
+	return models.Profile{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		BaseCurrency:  user.BaseCurrency,
+		MonthStart:    user.MonthStart,
+		Language:      user.Language,
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary Профиль пользователя
+// @Description Возвращает профиль текущего пользователя. Ответ содержит ETag: при совпадении
+// @Description заголовка If-None-Match с ним возвращается 304 без тела
+// @Tags me
+// @Produce json
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Success 200 {object} models.Profile
+// @Success 304 "Профиль не изменился"
+// @Failure 401 {object} models.ErrorResponse
+// @Router /me [get]
+func (h *Handler) GetProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+	c.JSON(http.StatusOK, profileOf(user))
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// quotaWindow — период, за который восполняется квота запросов.
+const quotaWindow = time.Minute
+
+// quotaFor возвращает квоту запросов в минуту для роли; 0 означает отсутствие ограничения.
+func (h *Handler) quotaFor(role string) int {
+	if quota, ok := h.cfg.RoleQuotas[role]; ok {
+		return quota
+	}
+	return h.cfg.UserQuota
+}
+
+// limitRequest забирает маркер из корзины key с квотой limit запросов в минуту и выставляет
+// заголовки X-RateLimit-*. При исчерпании квоты отвечает 429 и возвращает false.
+// Если хранилище квот недоступно, запрос пропускается.
+func (h *Handler) limitRequest(c *gin.Context, key string, limit int) bool {
+	res, err := h.limiter.Allow(c.Request.Context(), key, limit, quotaWindow, time.Now())
+	if err != nil {
+		log.Printf("Rate limiter unavailable: %v", err)
+		return true
+	}
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(res.Reset.Unix(), 10))
+
+	if !res.Allowed {
+		retryAfter := int((res.RetryAfter + time.Second - 1) / time.Second)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate quota exceeded"})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// QuotaMiddleware ограничивает число запросов пользователя в минуту.
+// Должен подключаться после AuthMiddleware.
+func (h *Handler) QuotaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		limit := h.quotaFor(c.GetString("role"))
+		if limit <= 0 || h.limitRequest(c, "user:"+strconv.Itoa(userID.(int)), limit) {
+			c.Next()
+		}
+	}
+}
+
+// IPQuotaMiddleware ограничивает число запросов с одного IP-адреса в минуту
+// для маршрутов без авторизации.
+func (h *Handler) IPQuotaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.cfg.IPQuota <= 0 || h.limitRequest(c, "ip:"+c.ClientIP(), h.cfg.IPQuota) {
+			c.Next()
+		}
+	}
+}
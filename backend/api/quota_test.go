@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestQuotaMiddleware тестирует ответы 429 и заголовки квоты с учетом ролей.
+func TestQuotaMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	handler := NewHandler(nil, Config{JWTSecret: "secret", UserQuota: 1, RoleQuotas: map[string]int{models.RoleAdmin: 0}})
+
+	r := gin.New()
+	r.GET("/ping", func(c *gin.Context) {
+		c.Set("user_id", 1)
+		c.Set("role", c.Query("role"))
+	}, handler.QuotaMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(role string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/ping?role="+role, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(models.RoleUser)
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "1" || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected 200 with quota headers, got %d %v", w.Code, w.Header())
+	}
+
+	w = request(models.RoleUser)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+
+	// Для администратора квота отключена
+	if w := request(models.RoleAdmin); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "" {
+		t.Errorf("Expected unlimited admin request, got %d %v", w.Code, w.Header())
+	}
+}
+
+// TestIPQuotaMiddleware тестирует квоту запросов с одного IP-адреса.
+func TestIPQuotaMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	handler := NewHandler(nil, Config{JWTSecret: "secret", IPQuota: 2})
+
+	r := gin.New()
+	r.POST("/login", handler.IPQuotaMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(ip string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = ip + ":40000"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := request("192.0.2.1"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "2" {
+			t.Errorf("Request %d: expected 200 with quota headers, got %d %v", i, w.Code, w.Header())
+		}
+	}
+	w := request("192.0.2.1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "30" || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected 429 with Retry-After 30, got %d %v", w.Code, w.Header())
+	}
+
+	// У другого адреса своя квота
+	if w := request("192.0.2.2"); w.Code != http.StatusOK {
+		t.Errorf("Expected other IP to be allowed, got %d", w.Code)
+	}
+
+	// Без доверенных прокси подмена X-Forwarded-For не обходит квоту
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatalf("Failed to set trusted proxies: %v", err)
+	}
+	forwarded := func(ip, forwardedFor string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/login", nil)
+		req.RemoteAddr = ip + ":40000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := forwarded("192.0.2.1", "198.51.100.7"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected spoofed X-Forwarded-For to be ignored, got %d", w.Code)
+	}
+
+	// За доверенным прокси квота считается по адресу клиента из заголовка
+	if err := r.SetTrustedProxies([]string{"192.0.2.0/24"}); err != nil {
+		t.Fatalf("Failed to set trusted proxies: %v", err)
+	}
+	if w := forwarded("192.0.2.1", "198.51.100.7"); w.Code != http.StatusOK {
+		t.Errorf("Expected forwarded client to have own quota, got %d", w.Code)
+	}
+}
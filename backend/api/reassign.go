@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Security ApiKeyAuth
+// @Summary Перенести транзакции в другую категорию
+// @Description Переносит все транзакции категории (включая корзину) в целевую категорию одним запросом,
+// @Description при необходимости только за период. Используется для объединения и чистки категорий.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "ID исходной категории"
+// @Param request body models.ReassignCategoryRequest true "Целевая категория и период"
+// @Success 200 {object} models.ReassignCategoryResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /categories/{id}/reassign [post]
+func (h *Handler) ReassignCategory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	var req models.ReassignCategoryRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.TargetCategoryID == id {
+		badRequest(c, fieldError("target_category_id", "target category must differ from source category"))
+		return
+	}
+
+	category, err := h.storage.GetCategory(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if category == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+
+	var dateFrom, dateTo time.Time
+	if req.DateFrom != nil {
+		dateFrom = *req.DateFrom
+	}
+	if req.DateTo != nil {
+		dateTo = *req.DateTo
+	}
+	if !dateFrom.IsZero() && !dateTo.IsZero() && dateFrom.After(dateTo) {
+		badRequest(c, fieldError("date_from", "date_from must not be after date_to"))
+		return
+	}
+
+	moved, err := h.storage.ReassignCategory(userID.(int), id, req.TargetCategoryID, dateFrom, dateTo)
+	if err != nil {
+		if strings.Contains(err.Error(), "category does not exist") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReassignCategoryResponse{Moved: moved})
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestReassignCategory тестирует перенос транзакций между категориями.
+func TestReassignCategory(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	source, err := storage.CreateCategory(user.ID, "cafe")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	target, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	var transactions []*models.Transaction
+	for _, month := range []time.Month{time.January, time.February, time.March} {
+		transaction := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: source.ID, Date: time.Date(2025, month, 15, 0, 0, 0, 0, time.UTC)}
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	if _, err := storage.DeleteTransaction(transactions[2].ID, user.ID); err != nil {
+		t.Fatalf("Failed to delete transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	reassign := func(id int, body string) (int, models.ReassignCategoryResponse) {
+		req, _ := http.NewRequest("POST", fmt.Sprintf("/categories/%d/reassign", id), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.ReassignCategoryResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+	countIn := func(categoryID int) int {
+		_, total, err := storage.GetTransactions(user.ID, db.TransactionFilter{CategoryID: categoryID}, 1, 10)
+		if err != nil {
+			t.Fatalf("Failed to get transactions: %v", err)
+		}
+		return total
+	}
+
+	// Сначала переносится только январь
+	code, response := reassign(source.ID, fmt.Sprintf(`{"target_category_id": %d, "date_to": "2025-01-31T23:59:59Z"}`, target.ID))
+	if code != http.StatusOK || response.Moved != 1 {
+		t.Errorf("Expected 1 moved transaction, got status %d and %+v", code, response)
+	}
+	if n := countIn(target.ID); n != 1 {
+		t.Errorf("Expected 1 transaction in target category, got %d", n)
+	}
+
+	// Без периода переносятся остальные, включая корзину, и исходную категорию можно удалить
+	code, response = reassign(source.ID, fmt.Sprintf(`{"target_category_id": %d}`, target.ID))
+	if code != http.StatusOK || response.Moved != 2 {
+		t.Errorf("Expected 2 moved transactions, got status %d and %+v", code, response)
+	}
+	if deleted, err := storage.DeleteCategory(source.ID, user.ID); err != nil || !deleted {
+		t.Errorf("Expected source category to be deletable, got %v (%v)", deleted, err)
+	}
+
+	for _, tc := range []struct {
+		id       int
+		body     string
+		expected int
+	}{
+		{target.ID, fmt.Sprintf(`{"target_category_id": %d}`, target.ID), http.StatusBadRequest},
+		{target.ID, `{}`, http.StatusBadRequest},
+		{target.ID, `{"target_category_id": 999999}`, http.StatusBadRequest},
+		{source.ID, fmt.Sprintf(`{"target_category_id": %d}`, target.ID), http.StatusNotFound},
+	} {
+		if code, _ := reassign(tc.id, tc.body); code != tc.expected {
+			t.Errorf("Expected status %d for %s, got %d", tc.expected, tc.body, code)
+		}
+	}
+}
+
+// TestDeleteCategoryReassign тестирует удаление категории с переносом ее транзакций.
+func TestDeleteCategoryReassign(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	source, err := storage.CreateCategory(user.ID, "cafe")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	target, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	other, err := storage.CreateUser("other", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	foreign, err := storage.CreateCategory(other.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	var transactions []*models.Transaction
+	for i := 0; i < 2; i++ {
+		transaction := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: source.ID, Date: time.Now()}
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	if _, err := storage.DeleteTransaction(transactions[1].ID, user.ID); err != nil {
+		t.Fatalf("Failed to delete transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	remove := func(id int, query string) int {
+		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/categories/%d%s", id, query), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Без reassign_to используемую категорию удалить нельзя
+	if code := remove(source.ID, ""); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+	for _, query := range []string{"?reassign_to=abc", fmt.Sprintf("?reassign_to=%d", source.ID), fmt.Sprintf("?reassign_to=%d", foreign.ID)} {
+		if code := remove(source.ID, query); code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, code)
+		}
+	}
+	if code := remove(source.ID+100, fmt.Sprintf("?reassign_to=%d", target.ID)); code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, code)
+	}
+
+	if code := remove(source.ID, fmt.Sprintf("?reassign_to=%d", target.ID)); code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, code)
+	}
+	if category, err := storage.GetCategory(source.ID, user.ID); err != nil || category != nil {
+		t.Errorf("Expected category to be deleted, got %+v (%v)", category, err)
+	}
+	// Переносятся и транзакции из корзины
+	for _, transaction := range transactions {
+		var categoryID int
+		if err := storage.DB.QueryRow("SELECT category_id FROM transactions WHERE id = $1", transaction.ID).Scan(&categoryID); err != nil {
+			t.Fatalf("Failed to get transaction: %v", err)
+		}
+		if categoryID != target.ID {
+			t.Errorf("Expected transaction %d in category %d, got %d", transaction.ID, target.ID, categoryID)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/receipt"
+)
+
+// @Security ApiKeyAuth
+// @Summary Транзакция по QR-коду чека
+// @Description Получает кассовый чек из ФНС по строке QR-кода и создает по нему транзакцию в рублях
+// @Description с продавцом в качестве контрагента. Позиции чека сохраняются и доступны в /transactions/{id}/receipt.
+// @Description Покупка и возврат расхода создаются как расход, возврат покупки — как доход.
+// @Description Если category_id не указан, категория подбирается по ключевым словам категорий в продавце и описании.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param receipt body models.ScanReceiptRequest true "Данные QR-кода"
+// @Success 201 {object} models.ReceiptTransaction
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /transactions/receipt [post]
+func (h *Handler) ScanReceipt(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+	if h.cfg.Receipts == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "receipt lookup is not configured"})
+		return
+	}
+
+	var req models.ScanReceiptRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	qr, err := receipt.ParseQR(req.QR)
+	if err != nil {
+		badRequest(c, fieldError("qr", "%v", err))
+		return
+	}
+	if req.CategoryID > 0 {
+		category, err := h.storage.GetCategory(req.CategoryID, userID.(int))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if category == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category does not exist or does not belong to user"})
+			return
+		}
+	}
+
+	r, err := h.cfg.Receipts.Fetch(c.Request.Context(), qr)
+	if errors.Is(err, receipt.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "receipt not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Без явной категории она подбирается по ключевым словам в продавце и описании
+	if req.CategoryID == 0 {
+		req.CategoryID, err = h.storage.MatchCategoryKeyword(userID.(int), r.Seller+" "+req.Description)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if req.CategoryID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category_id is required: no category keyword matches the receipt"})
+			return
+		}
+	}
+
+	t := models.Transaction{
+		UserID:      userID.(int),
+		Amount:      r.Total,
+		Type:        qr.TransactionType(),
+		CategoryID:  req.CategoryID,
+		Date:        r.Date,
+		Description: req.Description,
+		Currency:    "RUB",
+		Payee:       r.Seller,
+	}
+	if err := validateTransaction(t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.storage.CreateReceiptTransaction(&t, r); err != nil {
+		if err.Error() == "receipt has already been added" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	h.transactionsCreated(userID.(int), &t)
+	c.JSON(http.StatusCreated, models.ReceiptTransaction{Transaction: t, Receipt: *r})
+}
+
+// @Security ApiKeyAuth
+// @Summary Чек транзакции
+// @Description Возвращает кассовый чек с позициями, по которому создана транзакция
+// @Tags transactions
+// @Produce json
+// @Param id path int true "ID транзакции"
+// @Success 200 {object} models.Receipt
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id}/receipt [get]
+func (h *Handler) GetReceipt(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	r, err := h.storage.GetReceipt(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if r == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "receipt not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/receipt"
+)
+
+// fakeReceipts возвращает чек с двумя позициями для известного фискального признака.
+type fakeReceipts struct{}
+
+func (fakeReceipts) Fetch(ctx context.Context, qr receipt.QR) (*models.Receipt, error) {
+	if qr.FP != "1234567890" {
+		return nil, receipt.ErrNotFound
+	}
+	return &models.Receipt{FN: qr.FN, FD: qr.FD, FP: qr.FP, Seller: "Пятерочка", Date: qr.Time, Total: qr.Sum,
+		Items: []models.ReceiptItem{
+			{Name: "Молоко", Price: models.NewMoney(89, 99), Quantity: 2, Sum: models.NewMoney(179, 98)},
+			{Name: "Сыр", Price: models.NewMoney(1054, 52), Quantity: 1, Sum: models.NewMoney(1054, 52)},
+		}}, nil
+}
+
+// TestScanReceipt тестирует создание транзакции по QR-коду чека и сохранение его позиций.
+func TestScanReceipt(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Receipts: fakeReceipts{}})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.POST("/transactions/receipt", handler.ScanReceipt)
+	protected.GET("/transactions/:id/receipt", handler.GetReceipt)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	qr := "t=20250115T1230&s=1234.50&fn=9999078900012345&i=12345&fp=1234567890&n=1"
+	w := send("POST", "/transactions/receipt", models.ScanReceiptRequest{QR: qr, CategoryID: category.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created models.ReceiptTransaction
+	json.NewDecoder(w.Body).Decode(&created)
+	transaction := created.Transaction
+	if transaction.ID == 0 || transaction.Amount != models.NewMoney(1234, 50) || transaction.Type != "expense" ||
+		transaction.Currency != "RUB" || transaction.Payee != "Пятерочка" || transaction.CategoryID != category.ID {
+		t.Errorf("Unexpected transaction: %+v", transaction)
+	}
+
+	w = send("GET", fmt.Sprintf("/transactions/%d/receipt", transaction.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var stored models.Receipt
+	json.NewDecoder(w.Body).Decode(&stored)
+	if stored.TransactionID != transaction.ID || stored.FP != "1234567890" || len(stored.Items) != 2 ||
+		stored.Items[0].Name != "Молоко" || stored.Items[0].Quantity != 2 || stored.Items[1].Sum != models.NewMoney(1054, 52) {
+		t.Errorf("Unexpected receipt: %+v", stored)
+	}
+
+	// Тот же чек повторно не добавляется
+	if w := send("POST", "/transactions/receipt", models.ScanReceiptRequest{QR: qr, CategoryID: category.ID}); w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	unknown := "t=20250115T1230&s=100&fn=1&i=2&fp=3&n=1"
+	if w := send("POST", "/transactions/receipt", models.ScanReceiptRequest{QR: unknown, CategoryID: category.ID}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := send("POST", "/transactions/receipt", models.ScanReceiptRequest{QR: "not a receipt", CategoryID: category.ID}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("POST", "/transactions/receipt", models.ScanReceiptRequest{QR: qr, CategoryID: 999999}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("GET", "/transactions/999999/receipt", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// sendReportEmails отправляет письма с отчетами за прошедшие неделю и месяц подписанным пользователям.
+// Письмо за период отправляется один раз, поэтому частый запуск только гарантирует, что период не будет пропущен.
+// Возвращает ошибки всех неотправленных писем.
+func (h *Handler) sendReportEmails(ctx context.Context) error {
+	var errs []error
+	now := time.Now().UTC()
+	for _, kind := range []string{budget.Week, budget.Month} {
+		current, _ := budget.NewPeriod(kind, now, time.Time{})
+		period, _ := budget.NewPeriod(kind, current.Start.AddDate(0, 0, -1), time.Time{})
+		users, err := h.storage.ClaimReportEmails(kind, period.Start, period.End)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to claim %s report emails: %w", kind, err))
+			continue
+		}
+		for _, user := range users {
+			if err := h.sendReportEmail(ctx, user, period); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send %s report email to user %d: %w", kind, user.ID, err))
+			}
+		}
+		if len(users) > 0 {
+			log.Printf("sent %d %s report emails for %s", len(users), kind, period.Start.Format("2006-01-02"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendReportEmail отправляет пользователю итоги и бюджеты за прошедший период в базовой валюте пользователя.
+func (h *Handler) sendReportEmail(ctx context.Context, user models.User, period budget.Period) error {
+	byCurrency, err := h.storage.GetPeriodSummary(user.ID, period.Start, period.End.AddDate(0, 0, 1), false)
+	if err != nil {
+		return err
+	}
+	if err := h.storage.UpdateBudgetCarryover(user.ID, period.End); err != nil {
+		return err
+	}
+	budgets, err := h.storage.GetBudgetProgress(user.ID, period.Start, period.End)
+	if err != nil {
+		return err
+	}
+
+	exchangeRates, err := h.exchangeRatesFor(ctx, user.BaseCurrency, summaryTotals(byCurrency))
+	if err != nil {
+		return fmt.Errorf("failed to convert totals: %v", err)
+	}
+	summary, err := newPeriodSummary(period.Start, period.End, byCurrency, exchangeRates, user.BaseCurrency)
+	if err != nil {
+		return fmt.Errorf("failed to convert totals: %v", err)
+	}
+
+	subject, body := renderReportEmail(user.Language, user, period.Kind, summary, budgets)
+	return h.cfg.Mailer.Send(user.Email, subject, body)
+}
+
+// renderReportEmail формирует на языке lang тему и текст письма с итогами периода и ходом исполнения бюджетов.
+func renderReportEmail(lang string, user models.User, kind string, summary models.PeriodSummary, budgets []models.BudgetProgress) (string, string) {
+	name := i18n.T(lang, "email.report.weekly")
+	if kind == budget.Month {
+		name = i18n.T(lang, "email.report.monthly")
+	}
+	subject := i18n.T(lang, "email.report.subject", name, summary.From, summary.To)
+
+	var body strings.Builder
+	body.WriteString(i18n.T(lang, "email.report.intro", user.Username, name, summary.From, summary.To))
+	body.WriteString(i18n.T(lang, "email.report.income", summary.Income, summary.Currency))
+	body.WriteString(i18n.T(lang, "email.report.expense", summary.Expense, summary.Currency))
+	body.WriteString(i18n.T(lang, "email.report.net", summary.Net, summary.Currency))
+	body.WriteString(i18n.T(lang, "email.report.count", summary.Count))
+
+	if len(budgets) > 0 {
+		body.WriteString(i18n.T(lang, "email.report.budgets"))
+		for _, b := range budgets {
+			label := b.CategoryName
+			if b.TagID != 0 {
+				label = "#" + b.TagName
+			}
+			body.WriteString(i18n.T(lang, "email.report.budget_line", label,
+				b.StartDate.Format("2006-01-02"), b.EndDate.Format("2006-01-02"), b.Spent, b.Available, b.Currency))
+			if b.Exceeded {
+				body.WriteString(i18n.T(lang, "email.report.exceeded_by", -b.Remaining))
+			} else {
+				body.WriteString(i18n.T(lang, "email.report.left", b.Remaining))
+			}
+		}
+	}
+
+	body.WriteString(i18n.T(lang, "email.report.footer"))
+	return subject, body.String()
+}
+
+// @Security ApiKeyAuth
+// @Summary Подписки на письма с отчетами
+// @Description Возвращает, подписан ли пользователь на еженедельные и ежемесячные письма с итогами и бюджетами
+// @Tags me
+// @Produce json
+// @Success 200 {object} models.ReportEmailPreferences
+// @Failure 401 {object} models.ErrorResponse
+// @Router /me/report-emails [get]
+func (h *Handler) GetReportEmailPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	preferences, err := h.storage.GetReportEmailPreferences(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// @Security ApiKeyAuth
+// @Summary Изменить подписки на письма с отчетами
+// @Description Подписывает на письма с итогами и ходом исполнения бюджетов за прошедшую неделю (с понедельника)
+// @Description или календарный месяц и отписывает от них. Письмо отправляется на email пользователя после окончания
+// @Description периода; первое письмо приходит за период, в котором оформлена подписка. Для подписки нужен email
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body models.ReportEmailPreferences true "Подписки"
+// @Success 200 {object} models.ReportEmailPreferences
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /me/report-emails [put]
+func (h *Handler) SetReportEmailPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var preferences models.ReportEmailPreferences
+	if !bindJSON(c, &preferences) {
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if user.Email == "" && (preferences.Weekly || preferences.Monthly) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required to subscribe to report emails"})
+		return
+	}
+
+	if err := h.storage.SetReportEmailPreferences(user.ID, preferences); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
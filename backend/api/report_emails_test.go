@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestRenderReportEmail тестирует текст письма с итогами и бюджетами.
+func TestRenderReportEmail(t *testing.T) {
+	summary := models.PeriodSummary{From: "2025-07-01", To: "2025-07-31", Currency: "RUB", Income: models.NewMoney(50000, 0),
+		Expense: models.NewMoney(32000, 50), Net: models.NewMoney(17999, 50), Count: 42}
+	budgets := []models.BudgetProgress{
+		{CategoryName: "Продукты", StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+			Currency: "RUB", Available: models.NewMoney(20000, 0), Spent: models.NewMoney(15000, 0), Remaining: models.NewMoney(5000, 0)},
+		{TagID: 7, TagName: "отпуск", StartDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC),
+			Currency: "RUB", Available: models.NewMoney(1000, 0), Spent: models.NewMoney(1500, 0), Remaining: models.NewMoney(-500, 0), Exceeded: true},
+	}
+
+	subject, body := renderReportEmail(i18n.English, models.User{Username: "john"}, budget.Month, summary, budgets)
+	if subject != "Your monthly report for 2025-07-01 - 2025-07-31" {
+		t.Errorf("Unexpected subject: %q", subject)
+	}
+	for _, line := range []string{
+		"Hello, john!",
+		"Expenses: 32000.50 RUB",
+		"Transactions: 42",
+		"- Продукты (2025-07-01 - 2025-07-31): spent 15000.00 of 20000.00 RUB, 5000.00 left",
+		"- #отпуск (2025-07-01 - 2025-07-31): spent 1500.00 of 1000.00 RUB, exceeded by 500.00",
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected %q in body:\n%s", line, body)
+		}
+	}
+
+	if _, body := renderReportEmail(i18n.English, models.User{Username: "john"}, budget.Week, summary, nil); strings.Contains(body, "Budgets:") {
+		t.Errorf("Expected no budgets section, got:\n%s", body)
+	}
+
+	subject, body = renderReportEmail(i18n.Russian, models.User{Username: "john"}, budget.Month, summary, budgets)
+	if subject != "Ваш ежемесячный отчет за 2025-07-01 - 2025-07-31" {
+		t.Errorf("Unexpected subject: %q", subject)
+	}
+	for _, line := range []string{"Здравствуйте, john!", "Расходы: 32000.50 RUB", "осталось 5000.00", "превышение 500.00"} {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected %q in body:\n%s", line, body)
+		}
+	}
+}
+
+// TestReportEmails тестирует подписку на письма с отчетами и их отправку за прошедший период.
+func TestReportEmails(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUserWithEmail("testuser", "password123", "test@example.com")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if _, err := storage.CreateUser("noemail", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	if err := storage.CreateTransaction(&models.Transaction{UserID: user.ID, CategoryID: category.ID, Type: "expense", Currency: "RUB",
+		Amount: models.NewMoney(1200, 0), Date: time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+
+	send := func(token, method string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, "/me/report-emails", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	w := send(token, "PUT", models.ReportEmailPreferences{Weekly: true, Monthly: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = send(token, "PUT", models.ReportEmailPreferences{Monthly: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = send(token, "GET", nil)
+	var preferences models.ReportEmailPreferences
+	json.NewDecoder(w.Body).Decode(&preferences)
+	if preferences.Weekly || !preferences.Monthly {
+		t.Errorf("Unexpected preferences: %+v", preferences)
+	}
+	if w := send(getToken(t, r, "noemail", "password123"), "PUT", models.ReportEmailPreferences{Weekly: true}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without email, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// Письмо приходит только за периоды, закончившиеся после подписки, и только один раз
+	july, _ := budget.NewPeriod(budget.Month, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	users, err := storage.ClaimReportEmails(budget.Month, july.Start, july.End)
+	if err != nil {
+		t.Fatalf("Failed to claim report emails: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("Expected no emails for period before subscription, got %+v", users)
+	}
+	if _, err := storage.DB.Exec("UPDATE report_emails SET created_at = '2025-07-15' WHERE user_id = $1", user.ID); err != nil {
+		t.Fatalf("Failed to update report emails: %v", err)
+	}
+	users, err = storage.ClaimReportEmails(budget.Month, july.Start, july.End)
+	if err != nil {
+		t.Fatalf("Failed to claim report emails: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != user.ID || users[0].Email != "test@example.com" {
+		t.Fatalf("Unexpected users: %+v", users)
+	}
+	if users, _ := storage.ClaimReportEmails(budget.Month, july.Start, july.End); len(users) != 0 {
+		t.Errorf("Expected email to be claimed once, got %+v", users)
+	}
+
+	mailer := &fakeMailer{}
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Mailer: mailer})
+	if err := handler.sendReportEmail(context.Background(), users[0], july); err != nil {
+		t.Fatalf("Failed to send report email: %v", err)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To != "test@example.com" || !strings.Contains(mailer.sent[0].Body, "Expenses: 1200.00 RUB") {
+		t.Errorf("Unexpected emails: %+v", mailer.sent)
+	}
+}
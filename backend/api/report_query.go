@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+// validateReportQuery проверяет ограничения отчета, которые не зависят от списков измерений и показателей;
+// сами списки проверяет хранилище при построении запроса.
+func validateReportQuery(q *models.ReportQuery) error {
+	f := &q.Filters
+	if f.DateFrom != nil && f.DateTo != nil && f.DateFrom.After(*f.DateTo) {
+		return fieldError("filters.date_from", "date_from must not be after date_to")
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		return fieldError("filters.min_amount", "min_amount must not be greater than max_amount")
+	}
+	for i := range f.Tags {
+		f.Tags[i] = strings.TrimSpace(f.Tags[i])
+	}
+	return nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Пользовательский отчет
+// @Description Строит отчет по транзакциям из описания: фильтров, измерений группировки и показателей.
+// @Description Измерения: category, payee, account, tag, currency, type, day, week, month, quarter, year.
+// @Description Показатели: count, income, expense, net, average, min, max; доходы и расходы считаются за вычетом возвратов.
+// @Description Денежные показатели без фильтра по валюте группируются еще и по валюте. Переводы и корректировки не учитываются.
+// @Description Месяцы, кварталы и годы начинаются с дня начала месяца из профиля и обозначаются по первому дню
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param query body models.ReportQuery true "Описание отчета"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.ReportQueryResult
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /reports/query [post]
+func (h *Handler) RunReportQuery(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var query models.ReportQuery
+	if !bindJSON(c, &query) {
+		return
+	}
+	if err := validateReportQuery(&query); err != nil {
+		badRequest(c, err)
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	result, err := h.storage.RunReportQuery(user.ID, user.MonthStart, query)
+	if errors.Is(err, db.ErrInvalidReportQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	writeReport(c, "report", result, func() report.Workbook { return reportQueryWorkbook(*result) })
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestRunReportQuery тестирует пользовательский отчет по описанию.
+func TestRunReportQuery(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cafe, err := storage.CreateCategory(user.ID, "Кафе")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(300, 0), CategoryID: food.ID, Date: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), Tags: []string{"vacation"}},
+		{Amount: models.NewMoney(200, 0), CategoryID: food.ID, Date: time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(700, 0), CategoryID: cafe.ID, Date: time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC), Tags: []string{"vacation"}},
+		{Amount: models.NewMoney(100, 0), CategoryID: cafe.ID, Date: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		transaction.UserID, transaction.Type, transaction.Currency = user.ID, "expense", "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	query := func(q models.ReportQuery) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(q)
+		req, _ := http.NewRequest("POST", "/reports/query", &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	w := query(models.ReportQuery{
+		GroupBy: []string{"category"},
+		Metrics: []string{"expense", "count"},
+		Filters: models.ReportQueryFilters{DateFrom: &from, DateTo: &to},
+		OrderBy: "expense",
+		Desc:    true,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var result struct {
+		Columns []string
+		Rows    [][]interface{}
+	}
+	json.NewDecoder(w.Body).Decode(&result)
+	expected := [][]interface{}{{"Кафе", "RUB", 700.0, 1.0}, {"Продукты", "RUB", 500.0, 2.0}}
+	if len(result.Columns) != 4 || len(result.Rows) != len(expected) {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if result.Rows[i][j] != expected[i][j] {
+				t.Errorf("Expected row %v, got %v", expected[i], result.Rows[i])
+				break
+			}
+		}
+	}
+
+	w = query(models.ReportQuery{GroupBy: []string{"month"}, Metrics: []string{"count"}, Filters: models.ReportQueryFilters{Tags: []string{"vacation"}}})
+	result.Rows = nil
+	json.NewDecoder(w.Body).Decode(&result)
+	if w.Code != http.StatusOK || len(result.Rows) != 1 || result.Rows[0][0] != "2025-06" || result.Rows[0][1] != 2.0 {
+		t.Errorf("Unexpected tag report: %d %+v", w.Code, result)
+	}
+
+	for _, invalid := range []models.ReportQuery{
+		{Metrics: []string{"sum"}},
+		{Metrics: []string{"count"}, GroupBy: []string{"1; DROP TABLE users"}},
+		{Metrics: []string{"count"}, Limit: -1},
+		{Metrics: []string{"count"}, Filters: models.ReportQueryFilters{Currency: "XX"}},
+		{Metrics: []string{"count"}, Filters: models.ReportQueryFilters{DateFrom: &to, DateTo: &from}},
+	} {
+		if w := query(invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d: %s", http.StatusBadRequest, invalid, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// writeReport отвечает отчетом data в формате из параметра format: JSON по умолчанию
+// или книгой Excel из листов workbook с именем файла filename.xlsx.
+func writeReport(c *gin.Context, filename string, data interface{}, workbook func() report.Workbook) {
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, data)
+	case "xlsx":
+		// Книга собирается в памяти, чтобы ошибку формирования можно было вернуть статусом
+		var buf bytes.Buffer
+		if err := workbook().WriteXLSX(&buf); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.xlsx"`, filename))
+		c.Data(http.StatusOK, xlsxContentType, buf.Bytes())
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be 'json' or 'xlsx'"})
+	}
+}
+
+// optionalFloat возвращает значение ячейки для необязательного числа: nil, если его нет.
+func optionalFloat(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// optionalString возвращает значение ячейки для необязательной строки: nil, если она пустая.
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func netWorthWorkbook(n models.NetWorth) report.Workbook {
+	return report.Workbook{Sheets: []report.Sheet{{
+		Name:    "Чистые активы",
+		Headers: []string{"Валюта", "Активы", "Обязательства", "Чистые активы"},
+		Rows:    [][]interface{}{{n.Currency, n.Assets, n.Liabilities, n.NetWorth}},
+	}}}
+}
+
+func netWorthHistoryWorkbook(n models.NetWorthHistory) report.Workbook {
+	points := report.Sheet{Name: "Чистые активы", Headers: []string{"Начало интервала", "Дата снимка",
+		"Активы, " + n.Currency, "Обязательства, " + n.Currency, "Чистые активы, " + n.Currency}}
+	for _, p := range n.Points {
+		points.Rows = append(points.Rows, []interface{}{p.Start, p.Date, p.Assets, p.Liabilities, p.NetWorth})
+	}
+	return report.Workbook{Sheets: []report.Sheet{points}}
+}
+
+func budgetVsActualWorkbook(b models.BudgetVsActual) report.Workbook {
+	rows := report.Sheet{Name: "Бюджеты", Headers: []string{"Месяц", "Категория", "Валюта", "Бюджет", "Факт", "Отклонение"}}
+	for _, r := range b.Rows {
+		rows.Rows = append(rows.Rows, []interface{}{r.Month, r.CategoryName, r.Currency, r.Budgeted, r.Actual, r.Variance})
+	}
+	totals := report.Sheet{Name: "Итоги", Headers: []string{"Месяц", "Валюта", "Бюджет", "Факт", "Отклонение"}}
+	for _, t := range b.Totals {
+		totals.Rows = append(totals.Rows, []interface{}{t.Month, t.Currency, t.Budgeted, t.Actual, t.Variance})
+	}
+	return report.Workbook{Sheets: []report.Sheet{rows, totals}}
+}
+
+func periodSummaryWorkbook(s models.PeriodSummary) report.Workbook {
+	totals := report.Sheet{
+		Name:    "Итоги",
+		Headers: []string{"С", "По", "Валюта", "Доходы", "Расходы", "Разница", "Транзакций"},
+		Rows:    [][]interface{}{{s.From, s.To, s.Currency, s.Income, s.Expense, s.Net, s.Count}},
+	}
+	byCurrency := report.Sheet{Name: "По валютам", Headers: []string{"Валюта", "Доходы", "Расходы", "Разница", "Транзакций"}}
+	for _, r := range s.ByCurrency {
+		byCurrency.Rows = append(byCurrency.Rows, []interface{}{r.Currency, r.Income, r.Expense, r.Net, r.Count})
+	}
+	return report.Workbook{Sheets: []report.Sheet{totals, byCurrency}}
+}
+
+func timeSeriesWorkbook(s models.TimeSeries) report.Workbook {
+	points := report.Sheet{Name: "Интервалы", Headers: []string{"Начало", "Доходы, " + s.Currency, "Расходы, " + s.Currency, "Разница, " + s.Currency}}
+	byCurrency := report.Sheet{Name: "По валютам", Headers: []string{"Начало", "Валюта", "Доходы", "Расходы"}}
+	for _, p := range s.Points {
+		points.Rows = append(points.Rows, []interface{}{p.Start, p.Income, p.Expense, p.Net})
+		for _, t := range p.ByCurrency {
+			byCurrency.Rows = append(byCurrency.Rows, []interface{}{p.Start, t.Currency, t.Income, t.Expense})
+		}
+	}
+	return report.Workbook{Sheets: []report.Sheet{points, byCurrency}}
+}
+
+func spendingTrendsWorkbook(s models.SpendingTrends) report.Workbook {
+	trends := report.Sheet{Name: "Тренды", Headers: []string{"Категория", "Валюта", "Текущий период", "Среднее", "Изменение, %", "Направление"}}
+	for _, t := range s.Trends {
+		trends.Rows = append(trends.Rows, []interface{}{t.CategoryName, t.Currency, t.Current, t.Average, optionalFloat(t.Change), t.Direction})
+	}
+	return report.Workbook{Sheets: []report.Sheet{trends}}
+}
+
+func forecastWorkbook(f models.CashFlowForecast) report.Workbook {
+	points := report.Sheet{Name: "Прогноз", Headers: []string{"Дата", "Запланировано, " + f.Currency, "Остаток, " + f.Currency}}
+	for _, p := range f.Points {
+		points.Rows = append(points.Rows, []interface{}{p.Date, p.Planned, p.Balance})
+	}
+	totals := report.Sheet{
+		Name:    "Итоги",
+		Headers: []string{"Валюта", "Текущий остаток", "Средний поток за день", "Дней истории", "Остаток отрицательный с"},
+		Rows:    [][]interface{}{{f.Currency, f.Balance, f.DailyAverage, f.HistoryDays, optionalString(f.NegativeDate)}},
+	}
+	return report.Workbook{Sheets: []report.Sheet{points, totals}}
+}
+
+func reportQueryWorkbook(r models.ReportQueryResult) report.Workbook {
+	return report.Workbook{Sheets: []report.Sheet{{Name: "Отчет", Headers: r.Columns, Rows: r.Rows}}}
+}
+
+func topPayeesWorkbook(p models.TopPayees) report.Workbook {
+	payees := report.Sheet{Name: "Контрагенты", Headers: []string{"Контрагент", "Расходы, " + p.Currency, "Покупок", "Средний чек, " + p.Currency}}
+	byCurrency := report.Sheet{Name: "По валютам", Headers: []string{"Контрагент", "Валюта", "Расходы"}}
+	for _, payee := range p.Payees {
+		payees.Rows = append(payees.Rows, []interface{}{payee.Name, payee.Spent, payee.Visits, payee.Average})
+		for _, t := range payee.Totals {
+			byCurrency.Rows = append(byCurrency.Rows, []interface{}{payee.Name, t.Currency, t.Expense})
+		}
+	}
+	return report.Workbook{Sheets: []report.Sheet{payees, byCurrency}}
+}
+
+// weekdayNames — названия дней недели по номеру ISO 8601.
+var weekdayNames = [...]string{1: "Понедельник", "Вторник", "Среда", "Четверг", "Пятница", "Суббота", "Воскресенье"}
+
+func spendingStatsWorkbook(s models.SpendingStats) report.Workbook {
+	stats := report.Sheet{Name: "Показатели", Headers: []string{"Валюта", "Расходы", "Число расходов", "Среднее за день",
+		"Медиана за день", "Стандартное отклонение", "Крупнейший расход", "Дата", "Описание"}}
+	weekdays := report.Sheet{Name: "По дням недели", Headers: []string{"Валюта", "День недели", "Расходы", "В среднем за день"}}
+	for _, c := range s.Currencies {
+		row := []interface{}{c.Currency, c.Total, c.ExpenseCount, c.MeanDaily, c.MedianDaily, c.StdDevDaily, nil, nil, nil}
+		if t := c.LargestExpense; t != nil {
+			row[6], row[7], row[8] = t.Amount, t.Date.Format("2006-01-02"), optionalString(t.Description)
+		}
+		stats.Rows = append(stats.Rows, row)
+		for _, w := range c.ByWeekday {
+			weekdays.Rows = append(weekdays.Rows, []interface{}{c.Currency, weekdayNames[w.Weekday], w.Total, w.Average})
+		}
+	}
+	return report.Workbook{Sheets: []report.Sheet{stats, weekdays}}
+}
+
+func periodComparisonWorkbook(p models.PeriodComparison) report.Workbook {
+	periodA := fmt.Sprintf("%s — %s, %s", p.A.From, p.A.To, p.Currency)
+	periodB := fmt.Sprintf("%s — %s, %s", p.B.From, p.B.To, p.Currency)
+	totals := report.Sheet{Name: "Итоги", Headers: []string{"Показатель", periodA, periodB, "Изменение"}, Rows: [][]interface{}{
+		{"Доходы", p.A.Income, p.B.Income, p.Delta.Income},
+		{"Расходы", p.A.Expense, p.B.Expense, p.Delta.Expense},
+		{"Разница", p.A.Net, p.B.Net, p.Delta.Net},
+	}}
+	categories := report.Sheet{Name: "Категории", Headers: []string{"Категория", "Расходы A", "Расходы B", "Изменение расходов",
+		"Изменение, %", "Доходы A", "Доходы B"}}
+	for _, c := range p.Categories {
+		name := c.CategoryName
+		if c.CategoryID == 0 {
+			name = "Без категории"
+		}
+		categories.Rows = append(categories.Rows, []interface{}{name, c.A.Expense, c.B.Expense, c.Delta.Expense,
+			optionalFloat(c.ExpenseChange), c.A.Income, c.B.Income})
+	}
+	return report.Workbook{Sheets: []report.Sheet{totals, categories}}
+}
+
+func sankeyWorkbook(s models.CashFlowSankey) report.Workbook {
+	names := make(map[string]string, len(s.Nodes))
+	for _, node := range s.Nodes {
+		names[node.ID] = node.Name
+	}
+	flows := report.Sheet{Name: "Потоки", Headers: []string{"Откуда", "Куда", "Сумма, " + s.Currency}}
+	for _, link := range s.Links {
+		flows.Rows = append(flows.Rows, []interface{}{names[link.Source], names[link.Target], link.Value})
+	}
+	return report.Workbook{Sheets: []report.Sheet{flows}}
+}
+
+func subscriptionsWorkbook(s models.Subscriptions) report.Workbook {
+	subscriptions := report.Sheet{Name: "Подписки", Headers: []string{"Контрагент", "Валюта", "Сумма", "Периодичность",
+		"В месяц", "Списаний", "Первое списание", "Последнее списание", "Следующее списание"}}
+	for _, sub := range s.Subscriptions {
+		subscriptions.Rows = append(subscriptions.Rows, []interface{}{sub.Payee, sub.Currency, sub.Amount, sub.Frequency,
+			sub.MonthlyCost, sub.Charges, sub.FirstCharge, sub.LastCharge, sub.NextCharge})
+	}
+	return report.Workbook{Sheets: []report.Sheet{subscriptions}}
+}
+
+func savingsRateWorkbook(s models.SavingsRate) report.Workbook {
+	points := report.Sheet{Name: "Норма сбережений", Headers: []string{"Начало интервала", "Доходы, " + s.Currency,
+		"Расходы, " + s.Currency, "Сбережения, " + s.Currency, "Норма сбережений, %"}}
+	for _, p := range s.Points {
+		points.Rows = append(points.Rows, []interface{}{p.Start, p.Income, p.Expense, p.Savings, optionalFloat(p.Rate)})
+	}
+	points.Rows = append(points.Rows, []interface{}{"Итого", s.Income, s.Expense, s.Savings, optionalFloat(s.Rate)})
+	return report.Workbook{Sheets: []report.Sheet{points}}
+}
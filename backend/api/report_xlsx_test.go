@@ -0,0 +1,43 @@
+package api
+
+import (
+	"io"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+// TestReportWorkbooks тестирует, что книги всех отчетов формируются, в том числе для пустых отчетов.
+func TestReportWorkbooks(t *testing.T) {
+	change := 23.0
+	workbooks := map[string]report.Workbook{
+		"net-worth": netWorthWorkbook(models.NetWorth{Currency: "RUB", Assets: models.NewMoney(100, 0)}),
+		"net-worth-history": netWorthHistoryWorkbook(models.NetWorthHistory{Currency: "RUB", Points: []models.NetWorthHistoryPoint{
+			{Start: "2025-07-01", Date: "2025-07-31", Assets: models.NewMoney(100, 0)},
+		}}),
+		"budget-vs-actual": budgetVsActualWorkbook(models.BudgetVsActual{
+			Rows:   []models.BudgetVsActualRow{{Month: "2025-07", CategoryName: "Продукты", Currency: "RUB", Budgeted: models.NewMoney(100, 0)}},
+			Totals: []models.BudgetVsActualTotal{{Month: "2025-07", Currency: "RUB"}},
+		}),
+		"summary": periodSummaryWorkbook(models.PeriodSummary{ByCurrency: []models.CurrencySummary{{Currency: "RUB", Count: 2}}}),
+		"timeseries": timeSeriesWorkbook(models.TimeSeries{Currency: "RUB", Points: []models.TimeSeriesPoint{
+			{Start: "2025-07-01", ByCurrency: []models.TransactionTotals{{Currency: "USD", Expense: models.NewMoney(5, 0)}}},
+		}}),
+		"trends": spendingTrendsWorkbook(models.SpendingTrends{Trends: []models.SpendingTrend{
+			{CategoryName: "Продукты", Change: &change, Direction: "up"},
+			{CategoryName: "Кафе", Direction: "new"},
+		}}),
+		"forecast": forecastWorkbook(models.CashFlowForecast{Currency: "RUB", Points: []models.ForecastPoint{{Date: "2025-07-16"}}}),
+		"query": reportQueryWorkbook(models.ReportQueryResult{
+			Columns: []string{"category", "expense", "count"},
+			Rows:    [][]interface{}{{"Продукты", models.NewMoney(500, 0), int64(2)}, {nil, models.NewMoney(1, 0), int64(1)}},
+		}),
+		"empty summary": periodSummaryWorkbook(models.PeriodSummary{}),
+	}
+	for name, workbook := range workbooks {
+		if err := workbook.WriteXLSX(io.Discard); err != nil {
+			t.Errorf("Failed to write %s workbook: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,878 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/period"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+// @Security ApiKeyAuth
+// @Summary Выписка в PDF
+// @Description Формирует PDF-выписку за месяц: итоги по категориям и таблицу транзакций
+// @Tags reports
+// @Produce application/pdf
+// @Param month query string true "Месяц в формате YYYY-MM"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /reports/statement.pdf [get]
+func (h *Handler) GetStatementPDF(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	month := c.Query("month")
+	from, err := time.Parse("2006-01", month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month must be in format YYYY-MM"})
+		return
+	}
+	to := from.AddDate(0, 1, 0)
+
+	includePlanned, err := parseIncludePlanned(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	categories, err := h.storage.GetCategories(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	totals, err := h.storage.GetCategoryTotals(user.ID, from, to, includePlanned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	transactions, err := h.storage.GetPeriodTransactions(user.ID, from, to, includePlanned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement := report.Statement{
+		Username:     user.Username,
+		From:         from,
+		To:           to,
+		Categories:   make(map[int]string, len(categories)),
+		Totals:       totals,
+		Transactions: transactions,
+	}
+	for _, category := range categories {
+		statement.Categories[category.ID] = category.Name
+	}
+
+	// Документ собирается в памяти, чтобы ошибку формирования можно было вернуть статусом
+	var buf bytes.Buffer
+	if err := statement.WritePDF(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s.pdf"`, from.Format("2006-01")))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// statsPeriods — число последних месяцев, включая текущий, для параметра period; 0 — все время.
+var statsPeriods = map[string]int{"month": 1, "quarter": 3, "year": 12, "all": 0}
+
+// @Security ApiKeyAuth
+// @Summary Статистика категории
+// @Description Возвращает число транзакций, суммы доходов и расходов, среднюю сумму транзакции
+// @Description и помесячный ряд по валютам за последние месяцы, включая текущий. Запланированные транзакции не учитываются
+// @Tags categories
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param period query string false "Период: month, quarter, year (по умолчанию) или all"
+// @Success 200 {object} models.CategoryStats
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /categories/{id}/stats [get]
+func (h *Handler) GetCategoryStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	period := c.DefaultQuery("period", "year")
+	months, ok := statsPeriods[period]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be 'month', 'quarter', 'year' or 'all'"})
+		return
+	}
+	now := time.Now().UTC()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := currentMonth.AddDate(0, 1, 0)
+	var from time.Time
+	if months > 0 {
+		from = currentMonth.AddDate(0, 1-months, 0)
+	}
+
+	stats, err := h.storage.GetCategoryStats(id, userID.(int), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if stats == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		return
+	}
+	stats.Period = period
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// @Security ApiKeyAuth
+// @Summary Чистые активы
+// @Description Возвращает сумму остатков всех своих счетов, включая закрытые, в базовой валюте пользователя:
+// @Description активы (положительные остатки) минус обязательства (отрицательные остатки).
+// @Description К остатку инвестиционного счета прибавляется текущая стоимость его позиций
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.NetWorth
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/net-worth [get]
+func (h *Handler) GetNetWorth(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	accounts, err := h.storage.GetAccounts(user.ID, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	holdingValues, err := h.holdingsMarketValue(c.Request.Context(), user.ID, accounts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Активы учитываются как доходы, обязательства — как расходы, чтобы пересчитать их вместе с курсами итогов
+	totals := make([]models.TransactionTotals, 0, len(accounts))
+	for _, account := range accounts {
+		// Открытые пользователю чужие счета учитываются в активах их владельца
+		if account.Role != "owner" {
+			continue
+		}
+		balance := account.Balance + holdingValues[account.ID]
+		if balance >= 0 {
+			totals = append(totals, models.TransactionTotals{Currency: account.Currency, Income: balance})
+		} else {
+			totals = append(totals, models.TransactionTotals{Currency: account.Currency, Expense: -balance})
+		}
+	}
+	converted, err := h.convertTotals(c.Request.Context(), totals, user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert balances: " + err.Error()})
+		return
+	}
+
+	netWorth := models.NetWorth{
+		Currency:    user.BaseCurrency,
+		Assets:      converted.Income,
+		Liabilities: converted.Expense,
+		NetWorth:    converted.Income - converted.Expense,
+	}
+	writeReport(c, "net-worth", netWorth, func() report.Workbook { return netWorthWorkbook(netWorth) })
+}
+
+// maxBudgetReportMonths — наибольшая длина периода отчета по бюджетам, 10 лет.
+const maxBudgetReportMonths = 120
+
+// budgetVsActualTotals складывает строки отчета по бюджетам в итоги по месяцам и валютам.
+// Строки упорядочены по месяцу, поэтому итоги тоже идут по месяцам.
+func budgetVsActualTotals(rows []models.BudgetVsActualRow) []models.BudgetVsActualTotal {
+	totals := []models.BudgetVsActualTotal{}
+	index := make(map[[2]string]int)
+	for _, row := range rows {
+		key := [2]string{row.Month, row.Currency}
+		i, ok := index[key]
+		if !ok {
+			i = len(totals)
+			index[key] = i
+			totals = append(totals, models.BudgetVsActualTotal{Month: row.Month, Currency: row.Currency})
+		}
+		totals[i].Budgeted += row.Budgeted
+		totals[i].Actual += row.Actual
+		totals[i].Variance += row.Variance
+	}
+	sort.SliceStable(totals, func(i, j int) bool {
+		if totals[i].Month != totals[j].Month {
+			return totals[i].Month < totals[j].Month
+		}
+		return totals[i].Currency < totals[j].Currency
+	})
+	return totals
+}
+
+// @Security ApiKeyAuth
+// @Summary Бюджет и факт
+// @Description Возвращает по каждому месяцу периода бюджеты категорий и фактические расходы за вычетом возвратов
+// @Description с отклонением (бюджет минус расходы) и итоги месяцев по валютам. Расходы категорий без бюджета
+// @Description входят с нулевым бюджетом; учитываются только месячные бюджеты категорий; запланированные транзакции, переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string false "Первый месяц периода в формате YYYY-MM (по умолчанию за 5 месяцев до to)"
+// @Param to query string false "Последний месяц периода в формате YYYY-MM (по умолчанию текущий)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.BudgetVsActual
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /reports/budget-vs-actual [get]
+func (h *Handler) GetBudgetVsActual(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	toMonth, err := parseBudgetMonth(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be in format YYYY-MM"})
+		return
+	}
+	to := toMonth.Start
+	from := to.AddDate(0, -5, 0)
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01", value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be in format YYYY-MM"})
+			return
+		}
+	}
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	if from.AddDate(0, maxBudgetReportMonths-1, 0).Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("period must be at most %d months", maxBudgetReportMonths)})
+		return
+	}
+
+	rows, err := h.storage.GetBudgetVsActual(userID.(int), from, to.AddDate(0, 1, 0))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := models.BudgetVsActual{
+		From:   from.Format("2006-01"),
+		To:     to.Format("2006-01"),
+		Rows:   rows,
+		Totals: budgetVsActualTotals(rows),
+	}
+	writeReport(c, fmt.Sprintf("budget-vs-actual-%s-%s", result.From, result.To), result,
+		func() report.Workbook { return budgetVsActualWorkbook(result) })
+}
+
+// parseReportRange читает период отчета from и to в формате YYYY-MM-DD, оба дня включительно.
+// По умолчанию to — сегодня, from — первый день месяца to, который начинается с дня monthStart.
+func parseReportRange(c *gin.Context, monthStart int) (from, to time.Time, err error) {
+	now := time.Now().UTC()
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			return from, to, fmt.Errorf("to must be in format YYYY-MM-DD")
+		}
+	}
+	from = period.Grouping{Kind: period.Month, MonthStart: monthStart}.Start(to)
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			return from, to, fmt.Errorf("from must be in format YYYY-MM-DD")
+		}
+	}
+	if from.After(to) {
+		return from, to, fmt.Errorf("from must not be after to")
+	}
+	return from, to, nil
+}
+
+// summaryTotals возвращает суммы итогов по валютам для пересчета по курсам.
+func summaryTotals(byCurrency []models.CurrencySummary) []models.TransactionTotals {
+	totals := make([]models.TransactionTotals, 0, len(byCurrency))
+	for _, row := range byCurrency {
+		totals = append(totals, models.TransactionTotals{Currency: row.Currency, Income: row.Income, Expense: row.Expense})
+	}
+	return totals
+}
+
+// newPeriodSummary собирает итоги дней [from, to] из итогов по валютам byCurrency, пересчитанных в currency.
+func newPeriodSummary(from, to time.Time, byCurrency []models.CurrencySummary, exchangeRates map[string]float64, currency string) (models.PeriodSummary, error) {
+	summary := models.PeriodSummary{
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+		Currency:   currency,
+		ByCurrency: byCurrency,
+	}
+	converted, err := sumConverted(exchangeRates, summaryTotals(byCurrency), currency)
+	if err != nil {
+		return summary, err
+	}
+	for _, row := range byCurrency {
+		summary.Count += row.Count
+	}
+	summary.Income, summary.Expense = converted.Income, converted.Expense
+	summary.Net = summary.Income - summary.Expense
+	return summary, nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Итоги за период
+// @Description Возвращает доходы, расходы за вычетом возвратов, их разницу и число транзакций за период
+// @Description в базовой валюте пользователя и по каждой валюте. Переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.PeriodSummary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/summary [get]
+func (h *Handler) GetPeriodSummary(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	from, to, err := parseReportRange(c, user.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	includePlanned, err := parseIncludePlanned(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	byCurrency, err := h.storage.GetPeriodSummary(user.ID, from, to.AddDate(0, 0, 1), includePlanned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	exchangeRates, err := h.exchangeRatesFor(c.Request.Context(), user.BaseCurrency, summaryTotals(byCurrency))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+	summary, err := newPeriodSummary(from, to, byCurrency, exchangeRates, user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+
+	writeReport(c, fmt.Sprintf("summary-%s-%s", summary.From, summary.To), summary,
+		func() report.Workbook { return periodSummaryWorkbook(summary) })
+}
+
+// maxTimeSeriesPoints — наибольшее число интервалов во временном ряду.
+const maxTimeSeriesPoints = 1000
+
+// parseTimeSeriesRange читает группировку group_by (по умолчанию month) и период from и to. Месяцы, кварталы и годы
+// начинаются с дня monthStart. Без from период начинается за 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет
+// до to, а число интервалов ограничено maxTimeSeriesPoints.
+func parseTimeSeriesRange(c *gin.Context, monthStart int) (g period.Grouping, from, to time.Time, err error) {
+	if g, err = period.NewGrouping(c.Query("group_by"), monthStart); err != nil {
+		return g, from, to, err
+	}
+	if from, to, err = parseReportRange(c, monthStart); err != nil {
+		return g, from, to, err
+	}
+	if c.Query("from") == "" {
+		from = g.DefaultFrom(to)
+	}
+	if g.Count(from, to) > maxTimeSeriesPoints {
+		return g, from, to, fmt.Errorf("period must contain at most %d intervals", maxTimeSeriesPoints)
+	}
+	return g, from, to, nil
+}
+
+// loadTimeSeries читает параметры group_by, from, to и include_planned и возвращает доходы и расходы по интервалам
+// в базовой валюте пользователя. При ошибке отвечает на запрос сам и возвращает false.
+func (h *Handler) loadTimeSeries(c *gin.Context) (*models.TimeSeries, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return nil, false
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return nil, false
+	}
+	g, from, to, err := parseTimeSeriesRange(c, user.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	includePlanned, err := parseIncludePlanned(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	points, err := h.storage.GetTimeSeries(user.ID, g, from, to, includePlanned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	// Курсы загружаются один раз на весь ряд
+	groups := make([][]models.TransactionTotals, len(points))
+	for i := range points {
+		groups[i] = points[i].ByCurrency
+	}
+	exchangeRates, err := h.exchangeRatesFor(c.Request.Context(), user.BaseCurrency, groups...)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return nil, false
+	}
+	for i := range points {
+		converted, err := sumConverted(exchangeRates, points[i].ByCurrency, user.BaseCurrency)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+			return nil, false
+		}
+		points[i].Income, points[i].Expense = converted.Income, converted.Expense
+		points[i].Net = converted.Income - converted.Expense
+	}
+
+	series := models.TimeSeries{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		GroupBy:  g.Kind,
+		Currency: user.BaseCurrency,
+		Points:   points,
+	}
+	return &series, true
+}
+
+// @Security ApiKeyAuth
+// @Summary Доходы и расходы по интервалам
+// @Description Возвращает доходы и расходы за вычетом возвратов по дням, неделям (с понедельника), месяцам, кварталам или годам периода
+// @Description в базовой валюте пользователя и по каждой валюте. Интервалы без транзакций входят с нулями; первый интервал
+// @Description начинается с начала интервала, содержащего from. Переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param group_by query string false "Интервал: day, week, month (по умолчанию), quarter или year; месяцы начинаются с дня начала месяца из профиля"
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет до to)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.TimeSeries
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/timeseries [get]
+func (h *Handler) GetTimeSeries(c *gin.Context) {
+	series, ok := h.loadTimeSeries(c)
+	if !ok {
+		return
+	}
+	writeReport(c, fmt.Sprintf("timeseries-%s-%s-%s", series.GroupBy, series.From, series.To), series,
+		func() report.Workbook { return timeSeriesWorkbook(*series) })
+}
+
+// maxTrendWindow — наибольшее число предыдущих периодов для среднего в отчете о трендах.
+const maxTrendWindow = 12
+
+// spendingTrend возвращает направление и изменение текущих расходов относительно среднего в процентах
+// с точностью до десятых. При нулевом среднем изменение не определено.
+func spendingTrend(current, average models.Money) (string, *float64) {
+	if average == 0 {
+		if current == 0 {
+			return "flat", nil
+		}
+		return "new", nil
+	}
+	change := math.Round(float64(current-average)/math.Abs(float64(average))*1000) / 10
+	switch {
+	case change > 0:
+		return "up", &change
+	case change < 0:
+		return "down", &change
+	default:
+		return "flat", &change
+	}
+}
+
+// @Security ApiKeyAuth
+// @Summary Тренды расходов
+// @Description Сравнивает расходы каждой категории за текущую неделю, месяц, квартал или год со средними расходами
+// @Description за несколько предыдущих периодов того же вида и возвращает изменение в процентах и направление.
+// @Description Текущий период может быть неполным. Суммы считаются в валютах транзакций за вычетом возвратов;
+// @Description запланированные транзакции, переводы и корректировки не учитываются. Месяцы, кварталы и годы начинаются
+// @Description с дня начала месяца из профиля
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param period query string false "Вид периода: week, month (по умолчанию), quarter или year"
+// @Param window query int false "Число предыдущих периодов для среднего, от 1 до 12 (по умолчанию 3)"
+// @Param date query string false "День текущего периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.SpendingTrends
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /reports/trends [get]
+func (h *Handler) GetSpendingTrends(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	kind := c.DefaultQuery("period", period.Month)
+	if kind == period.Day {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be 'week', 'month', 'quarter' or 'year'"})
+		return
+	}
+	g, err := period.NewGrouping(kind, user.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be 'week', 'month', 'quarter' or 'year'"})
+		return
+	}
+	window := 3
+	if value := c.Query("window"); value != "" {
+		if window, err = strconv.Atoi(value); err != nil || window < 1 || window > maxTrendWindow {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window must be between 1 and %d", maxTrendWindow)})
+			return
+		}
+	}
+	day := time.Now().UTC()
+	if value := c.Query("date"); value != "" {
+		if day, err = time.Parse("2006-01-02", value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in format YYYY-MM-DD"})
+			return
+		}
+	}
+	start := g.Start(day)
+	end := g.End(start)
+
+	trends, err := h.storage.GetSpendingTrends(user.ID, g.Add(start, -window), start, end.AddDate(0, 0, 1), window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range trends {
+		trends[i].Direction, trends[i].Change = spendingTrend(trends[i].Current, trends[i].Average)
+	}
+
+	result := models.SpendingTrends{
+		Period: g.Kind,
+		Start:  start.Format("2006-01-02"),
+		End:    end.Format("2006-01-02"),
+		Window: window,
+		Trends: trends,
+	}
+	writeReport(c, fmt.Sprintf("trends-%s-%s", kind, result.Start), result,
+		func() report.Workbook { return spendingTrendsWorkbook(result) })
+}
+
+const (
+	defaultTopPayees = 10
+	maxTopPayees     = 100
+)
+
+// rankPayees упорядочивает контрагентов по расходам или по числу покупок; при равенстве решает второй показатель, затем имя.
+func rankPayees(payees []models.TopPayee, sortBy string) {
+	sort.SliceStable(payees, func(i, j int) bool {
+		a, b := payees[i], payees[j]
+		if sortBy == "visits" && a.Visits != b.Visits {
+			return a.Visits > b.Visits
+		}
+		if a.Spent != b.Spent {
+			return a.Spent > b.Spent
+		}
+		if a.Visits != b.Visits {
+			return a.Visits > b.Visits
+		}
+		return a.Name < b.Name
+	})
+}
+
+// @Security ApiKeyAuth
+// @Summary Главные контрагенты
+// @Description Возвращает контрагентов с наибольшими расходами за вычетом возвратов в базовой валюте пользователя
+// @Description или с наибольшим числом покупок за период. Запланированные транзакции, переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param limit query int false "Число контрагентов, от 1 до 100 (по умолчанию 10)"
+// @Param sort query string false "Порядок: spent (по умолчанию) или visits"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.TopPayees
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/top-payees [get]
+func (h *Handler) GetTopPayees(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	from, to, err := parseReportRange(c, user.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit := defaultTopPayees
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxTopPayees {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxTopPayees)})
+			return
+		}
+	}
+	sortBy := c.DefaultQuery("sort", "spent")
+	if sortBy != "spent" && sortBy != "visits" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be 'spent' or 'visits'"})
+		return
+	}
+	payees, err := h.storage.GetPayeeSpend(user.ID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	groups := make([][]models.TransactionTotals, len(payees))
+	for i := range payees {
+		groups[i] = payees[i].Totals
+	}
+	exchangeRates, err := h.exchangeRatesFor(c.Request.Context(), user.BaseCurrency, groups...)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+	for i := range payees {
+		converted, err := sumConverted(exchangeRates, payees[i].Totals, user.BaseCurrency)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+			return
+		}
+		payees[i].Spent = converted.Expense
+		if payees[i].Visits > 0 {
+			payees[i].Average = payees[i].Spent / models.Money(payees[i].Visits)
+		}
+	}
+	rankPayees(payees, sortBy)
+	if len(payees) > limit {
+		payees = payees[:limit]
+	}
+
+	result := models.TopPayees{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Currency: user.BaseCurrency,
+		Sort:     sortBy,
+		Payees:   payees,
+	}
+	writeReport(c, fmt.Sprintf("top-payees-%s-%s", result.From, result.To), result,
+		func() report.Workbook { return topPayeesWorkbook(result) })
+}
+
+// @Security ApiKeyAuth
+// @Summary Статистика расходов
+// @Description Возвращает по каждой валюте статистику расходов за вычетом возвратов за период: сумму, среднее, медиану
+// @Description и стандартное отклонение дневных расходов с учетом дней без расходов, расходы по дням недели
+// @Description и самый крупный расход. Запланированные транзакции, переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.SpendingStats
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /reports/spending-stats [get]
+func (h *Handler) GetSpendingStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	from, to, err := parseReportRange(c, user.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	currencies, err := h.storage.GetSpendingStats(user.ID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats := models.SpendingStats{
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+		Days:       int(to.Sub(from).Hours()/24) + 1,
+		Currencies: currencies,
+	}
+	writeReport(c, fmt.Sprintf("spending-stats-%s-%s", stats.From, stats.To), stats,
+		func() report.Workbook { return spendingStatsWorkbook(stats) })
+}
+
+// savingsRate возвращает долю сбережений (income − expense) / income в процентах с точностью до десятых.
+// Без доходов норма сбережений не определена.
+func savingsRate(income, expense models.Money) *float64 {
+	if income <= 0 {
+		return nil
+	}
+	rate := math.Round(float64(income-expense)/float64(income)*1000) / 10
+	return &rate
+}
+
+// @Security ApiKeyAuth
+// @Summary Норма сбережений
+// @Description Возвращает долю доходов, оставшуюся после расходов за вычетом возвратов, за весь период и по дням,
+// @Description неделям (с понедельника), месяцам, кварталам или годам в базовой валюте пользователя. Переводы между счетами
+// @Description и корректировки остатков не учитываются ни в доходах, ни в расходах. Для интервалов без доходов норма не определена
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param group_by query string false "Интервал: day, week, month (по умолчанию), quarter или year; месяцы начинаются с дня начала месяца из профиля"
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет до to)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.SavingsRate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/savings-rate [get]
+func (h *Handler) GetSavingsRate(c *gin.Context) {
+	series, ok := h.loadTimeSeries(c)
+	if !ok {
+		return
+	}
+
+	result := models.SavingsRate{
+		From:     series.From,
+		To:       series.To,
+		GroupBy:  series.GroupBy,
+		Currency: series.Currency,
+		Points:   make([]models.SavingsRatePoint, 0, len(series.Points)),
+	}
+	for _, p := range series.Points {
+		result.Points = append(result.Points, models.SavingsRatePoint{
+			Start:   p.Start,
+			Income:  p.Income,
+			Expense: p.Expense,
+			Savings: p.Net,
+			Rate:    savingsRate(p.Income, p.Expense),
+		})
+		result.Income += p.Income
+		result.Expense += p.Expense
+	}
+	result.Savings = result.Income - result.Expense
+	result.Rate = savingsRate(result.Income, result.Expense)
+
+	writeReport(c, fmt.Sprintf("savings-rate-%s-%s-%s", result.GroupBy, result.From, result.To), result,
+		func() report.Workbook { return savingsRateWorkbook(result) })
+}
@@ -0,0 +1,955 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestGetStatementPDF тестирует выгрузку PDF-выписки за месяц.
+func TestGetStatementPDF(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for _, date := range []time.Time{
+		time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 30, 23, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		tr := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, Date: date, Description: "Магазин"}
+		if err := storage.CreateTransaction(tr); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	// В выписку за июнь попадают только июньские транзакции
+	totals, err := storage.GetCategoryTotals(user.ID, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), false)
+	if err != nil {
+		t.Fatalf("Failed to get category totals: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Category != "Продукты" || totals[0].Expense != models.NewMoney(200, 0) {
+		t.Errorf("Unexpected category totals: %+v", totals)
+	}
+
+	token := getToken(t, r, "testuser", "password123")
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/statement.pdf"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?month=2024-06")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("Expected Content-Type application/pdf, got %s", ct)
+	}
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")) {
+		t.Error("Expected PDF document in response body")
+	}
+
+	for _, query := range []string{"", "?month=2024-13", "?month=06.2024"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+// TestGetCategoryStats тестирует итоги и помесячный ряд категории за период.
+func TestGetCategoryStats(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	now := time.Now().UTC()
+	month := func(back int) time.Time {
+		return time.Date(now.Year(), now.Month(), 1, 12, 0, 0, 0, time.UTC).AddDate(0, -back, 0)
+	}
+	create := func(transaction *models.Transaction) {
+		transaction.UserID, transaction.CategoryID = user.ID, category.ID
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	expense := &models.Transaction{Amount: models.NewMoney(300, 0), Type: "expense", Date: month(0)}
+	create(expense)
+	create(&models.Transaction{Amount: models.NewMoney(100, 0), Type: "income", Date: month(0), LinkedTransactionID: expense.ID})
+	create(&models.Transaction{Amount: models.NewMoney(500, 0), Type: "expense", Date: month(2)})
+	create(&models.Transaction{Amount: models.NewMoney(10, 0), Type: "expense", Date: month(2), Currency: "USD"})
+	create(&models.Transaction{Amount: models.NewMoney(700, 0), Type: "expense", Date: month(20)})
+	deleted := &models.Transaction{Amount: models.NewMoney(900, 0), Type: "expense", Date: month(1)}
+	create(deleted)
+	if _, err := storage.DeleteTransaction(deleted.ID, user.ID); err != nil {
+		t.Fatalf("Failed to delete transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(path string) (int, models.CategoryStats) {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var stats models.CategoryStats
+		json.NewDecoder(w.Body).Decode(&stats)
+		return w.Code, stats
+	}
+
+	code, stats := get(fmt.Sprintf("/categories/%d/stats?period=quarter", category.ID))
+	if code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+	}
+	if stats.Period != "quarter" || stats.From == nil || len(stats.Totals) != 2 {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+	rub := stats.Totals[0]
+	// Возврат уменьшает расход; удаленная транзакция и транзакции вне периода не учитываются
+	if rub.Currency != "RUB" || rub.Count != 3 || rub.Income != 0 || rub.Expense != models.NewMoney(700, 0) || rub.Average != models.NewMoney(300, 0) {
+		t.Errorf("Unexpected RUB totals: %+v", rub)
+	}
+	if len(stats.Monthly) != 6 {
+		t.Fatalf("Expected 3 months in 2 currencies, got %+v", stats.Monthly)
+	}
+	if first := stats.Monthly[0]; first.Month != month(2).Format("2006-01") || first.Currency != "RUB" || first.Expense != models.NewMoney(500, 0) {
+		t.Errorf("Unexpected first month: %+v", first)
+	}
+	if gap := stats.Monthly[2]; gap.Month != month(1).Format("2006-01") || gap.Count != 0 || gap.Expense != 0 {
+		t.Errorf("Expected empty month, got %+v", gap)
+	}
+	if last := stats.Monthly[4]; last.Count != 2 || last.Expense != models.NewMoney(200, 0) {
+		t.Errorf("Unexpected current month: %+v", last)
+	}
+
+	// По умолчанию — последние 12 месяцев, all — с первой транзакции
+	if _, stats := get(fmt.Sprintf("/categories/%d/stats", category.ID)); stats.Period != "year" || len(stats.Monthly) != 24 {
+		t.Errorf("Expected 12 months in 2 currencies, got %d", len(stats.Monthly))
+	}
+	if _, stats := get(fmt.Sprintf("/categories/%d/stats?period=all", category.ID)); stats.From != nil || len(stats.Monthly) != 42 || stats.Totals[0].Count != 4 {
+		t.Errorf("Expected 21 months in 2 currencies, got %d", len(stats.Monthly))
+	}
+
+	if code, _ := get(fmt.Sprintf("/categories/%d/stats?period=week", category.ID)); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+	if code, _ := get(fmt.Sprintf("/categories/%d/stats", category.ID+100)); code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, code)
+	}
+}
+
+// TestGetCategoriesWithStats тестирует список категорий с числом транзакций и суммами.
+func TestGetCategoriesWithStats(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	travel, err := storage.CreateCategory(user.ID, "Путешествия")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	empty, err := storage.CreateCategory(user.ID, "Прочее")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(300, 0), Type: "expense", CategoryID: food.ID},
+		{Amount: models.NewMoney(200, 50), Type: "expense", CategoryID: food.ID},
+		{Amount: models.NewMoney(50, 0), Type: "expense", CategoryID: travel.ID, Currency: "USD"},
+		{Amount: models.NewMoney(1000, 0), Type: "expense", CategoryID: food.ID, Planned: true, Date: time.Now().AddDate(0, 1, 0)},
+	} {
+		transaction.UserID = user.ID
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) (int, []models.Category) {
+		req, _ := http.NewRequest("GET", "/categories"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var categories []models.Category
+		json.NewDecoder(w.Body).Decode(&categories)
+		return w.Code, categories
+	}
+
+	code, categories := get("?with_stats=true")
+	if code != http.StatusOK || len(categories) != 3 {
+		t.Fatalf("Expected 3 categories, got status %d and %+v", code, categories)
+	}
+	byID := make(map[int]models.Category)
+	for _, category := range categories {
+		if category.TransactionCount == nil {
+			t.Fatalf("Expected transaction_count for %+v", category)
+		}
+		byID[category.ID] = category
+	}
+	// Запланированная транзакция не учитывается
+	if c := byID[food.ID]; *c.TransactionCount != 2 || c.TotalSpent == nil || *c.TotalSpent != models.NewMoney(500, 50) || len(c.Totals) != 1 {
+		t.Errorf("Unexpected food stats: %+v", c)
+	}
+	// Без курсов сумма в долларах не пересчитывается
+	if c := byID[travel.ID]; *c.TransactionCount != 1 || c.TotalSpent != nil || len(c.Totals) != 1 || c.Totals[0].Currency != "USD" {
+		t.Errorf("Unexpected travel stats: %+v", c)
+	}
+	if c := byID[empty.ID]; *c.TransactionCount != 0 || c.TotalSpent == nil || *c.TotalSpent != 0 {
+		t.Errorf("Unexpected empty category stats: %+v", c)
+	}
+
+	// Без with_stats статистика не возвращается
+	if _, categories := get(""); len(categories) != 3 || categories[0].TransactionCount != nil {
+		t.Errorf("Expected categories without stats, got %+v", categories)
+	}
+	if code, _ := get("?with_stats=maybe"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+}
+
+// TestGetNetWorth тестирует чистые активы по остаткам счетов в разных валютах.
+func TestGetNetWorth(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Rates: &fakeRates{}})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.GET("/reports/net-worth", handler.GetNetWorth)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	getNetWorth := func() models.NetWorth {
+		req, _ := http.NewRequest("GET", "/reports/net-worth", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var netWorth models.NetWorth
+		json.NewDecoder(w.Body).Decode(&netWorth)
+		return netWorth
+	}
+
+	if netWorth := getNetWorth(); netWorth.Currency != "RUB" || netWorth.NetWorth != 0 {
+		t.Errorf("Expected zero net worth without accounts, got %+v", netWorth)
+	}
+
+	if _, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "RUB", InitialBalance: models.NewMoney(10000, 0)}); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	if _, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Доллары", Currency: "USD", InitialBalance: models.NewMoney(100, 0)}); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	credit, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Кредитка", Currency: "RUB", InitialBalance: models.NewMoney(-3000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	// Закрытые счета тоже входят в чистые активы
+	if _, err := storage.SetAccountClosed(credit.ID, user.ID, true); err != nil {
+		t.Fatalf("Failed to close account: %v", err)
+	}
+
+	netWorth := getNetWorth()
+	if netWorth.Assets != models.NewMoney(18000, 0) || netWorth.Liabilities != models.NewMoney(3000, 0) || netWorth.NetWorth != models.NewMoney(15000, 0) {
+		t.Errorf("Unexpected net worth: %+v", netWorth)
+	}
+}
+
+// TestBudgetVsActualTotals тестирует итоги отчета по бюджетам по месяцам и валютам.
+func TestBudgetVsActualTotals(t *testing.T) {
+	rows := []models.BudgetVsActualRow{
+		{Month: "2025-06", Currency: "USD", Budgeted: 100, Actual: 50, Variance: 50},
+		{Month: "2025-06", Currency: "RUB", Budgeted: 1000, Actual: 1200, Variance: -200},
+		{Month: "2025-06", Currency: "RUB", Budgeted: 0, Actual: 300, Variance: -300},
+		{Month: "2025-07", Currency: "RUB", Budgeted: 1000, Actual: 0, Variance: 1000},
+	}
+	totals := budgetVsActualTotals(rows)
+	expected := []models.BudgetVsActualTotal{
+		{Month: "2025-06", Currency: "RUB", Budgeted: 1000, Actual: 1500, Variance: -500},
+		{Month: "2025-06", Currency: "USD", Budgeted: 100, Actual: 50, Variance: 50},
+		{Month: "2025-07", Currency: "RUB", Budgeted: 1000, Actual: 0, Variance: 1000},
+	}
+	if len(totals) != len(expected) {
+		t.Fatalf("Expected %d totals, got %+v", len(expected), totals)
+	}
+	for i := range expected {
+		if totals[i] != expected[i] {
+			t.Errorf("Expected total %+v, got %+v", expected[i], totals[i])
+		}
+	}
+}
+
+// TestGetBudgetVsActual тестирует отчет «бюджет и факт».
+func TestGetBudgetVsActual(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cafe, err := storage.CreateCategory(user.ID, "Кафе")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for _, request := range []models.CreateBudget{
+		{CategoryID: food.ID, Month: "2025-06", Limit: models.NewMoney(1000, 0), Currency: "RUB"},
+		{CategoryID: food.ID, Month: "2025-07", Limit: models.NewMoney(1000, 0), Currency: "RUB"},
+	} {
+		period, _ := parseBudgetPeriod(request)
+		if _, err := storage.CreateBudget(user.ID, period, request); err != nil {
+			t.Fatalf("Failed to create budget: %v", err)
+		}
+	}
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(1200, 0), CategoryID: food.ID, Date: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(300, 0), CategoryID: cafe.ID, Date: time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(999, 0), CategoryID: cafe.ID, Date: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		transaction.UserID, transaction.Type, transaction.Currency = user.ID, "expense", "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/budget-vs-actual"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?from=2025-06&to=2025-07")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var report models.BudgetVsActual
+	json.NewDecoder(w.Body).Decode(&report)
+	if report.From != "2025-06" || report.To != "2025-07" || len(report.Rows) != 3 || len(report.Totals) != 2 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	if row := report.Rows[0]; row.Month != "2025-06" || row.CategoryID != food.ID || row.Actual != models.NewMoney(1200, 0) ||
+		row.Variance != -models.NewMoney(200, 0) {
+		t.Errorf("Unexpected June row: %+v", row)
+	}
+	// Июль: расходы без бюджета в «Кафе» и бюджет без расходов в «Продуктах»
+	if row := report.Rows[1]; row.CategoryID != cafe.ID || row.Budgeted != 0 || row.Actual != models.NewMoney(300, 0) {
+		t.Errorf("Unexpected July cafe row: %+v", row)
+	}
+	if row := report.Rows[2]; row.CategoryID != food.ID || row.Budgeted != models.NewMoney(1000, 0) || row.Actual != 0 {
+		t.Errorf("Unexpected July food row: %+v", row)
+	}
+	if total := report.Totals[1]; total.Month != "2025-07" || total.Variance != models.NewMoney(700, 0) {
+		t.Errorf("Unexpected July total: %+v", total)
+	}
+
+	for _, query := range []string{"?from=2025-07&to=2025-06", "?from=2025", "?to=07-2025", "?from=2000-01&to=2025-01"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+// TestGetPeriodSummary тестирует итоги за период в базовой валюте и по валютам.
+func TestGetPeriodSummary(t *testing.T) {
+	_, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+
+	handler := NewHandler(storage, Config{JWTSecret: "secret", Rates: &fakeRates{}})
+	r := gin.New()
+	r.POST("/login", handler.Login)
+	protected := r.Group("/", handler.AuthMiddleware())
+	protected.GET("/reports/summary", handler.GetPeriodSummary)
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(50000, 0), Type: "income", Currency: "RUB", Date: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(2000, 0), Type: "expense", Currency: "RUB", Date: time.Date(2025, 7, 31, 23, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(100, 0), Type: "expense", Currency: "USD", Date: time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(700, 0), Type: "expense", Currency: "RUB", Date: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(900, 0), Type: "expense", Currency: "RUB", Date: time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC), Planned: true},
+	} {
+		transaction.UserID, transaction.CategoryID = user.ID, category.ID
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/summary"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?from=2025-07-01&to=2025-07-31")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var summary models.PeriodSummary
+	json.NewDecoder(w.Body).Decode(&summary)
+	// 100 USD по курсу 80 — 8000 RUB
+	if summary.Currency != "RUB" || summary.Income != models.NewMoney(50000, 0) || summary.Expense != models.NewMoney(10000, 0) ||
+		summary.Net != models.NewMoney(40000, 0) || summary.Count != 3 || len(summary.ByCurrency) != 2 {
+		t.Fatalf("Unexpected summary: %+v", summary)
+	}
+	if row := summary.ByCurrency[1]; row.Currency != "USD" || row.Expense != models.NewMoney(100, 0) || row.Net != -models.NewMoney(100, 0) || row.Count != 1 {
+		t.Errorf("Unexpected USD summary: %+v", row)
+	}
+
+	w = get("?from=2025-07-01&to=2025-07-31&include_planned=true")
+	summary = models.PeriodSummary{}
+	json.NewDecoder(w.Body).Decode(&summary)
+	if w.Code != http.StatusOK || summary.Count != 4 || summary.Expense != models.NewMoney(10900, 0) {
+		t.Errorf("Unexpected summary with planned transactions: %d %+v", w.Code, summary)
+	}
+
+	w = get("?from=2025-07-01&to=2025-07-31&format=xlsx")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != xlsxContentType || !bytes.HasPrefix(w.Body.Bytes(), []byte("PK")) {
+		t.Errorf("Expected XLSX summary, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if disposition := w.Header().Get("Content-Disposition"); disposition != `attachment; filename="summary-2025-07-01-2025-07-31.xlsx"` {
+		t.Errorf("Unexpected Content-Disposition: %s", disposition)
+	}
+
+	w = get("?from=2025-09-01&to=2025-09-30")
+	summary = models.PeriodSummary{}
+	json.NewDecoder(w.Body).Decode(&summary)
+	if w.Code != http.StatusOK || summary.Count != 0 || summary.Net != 0 || len(summary.ByCurrency) != 0 {
+		t.Errorf("Expected empty summary, got %d %+v", w.Code, summary)
+	}
+
+	for _, query := range []string{"?from=2025-07-31&to=2025-07-01", "?from=2025-07", "?to=31.07.2025", "?include_planned=maybe", "?format=csv"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+// TestGetTimeSeries тестирует доходы и расходы по интервалам.
+func TestGetTimeSeries(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(50000, 0), Type: "income", Date: time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(2000, 0), Type: "expense", Date: time.Date(2025, 5, 31, 23, 0, 0, 0, time.UTC)},
+		{Amount: models.NewMoney(700, 0), Type: "expense", Date: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		transaction.UserID, transaction.CategoryID, transaction.Currency = user.ID, category.ID, "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/timeseries"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?group_by=month&from=2025-05-10&to=2025-07-15")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var series models.TimeSeries
+	json.NewDecoder(w.Body).Decode(&series)
+	if series.GroupBy != "month" || series.Currency != "RUB" || len(series.Points) != 3 {
+		t.Fatalf("Unexpected time series: %+v", series)
+	}
+	// Доход 5 мая раньше from и не учитывается, июнь пустой
+	if p := series.Points[0]; p.Start != "2025-05-01" || p.Income != 0 || p.Expense != models.NewMoney(2000, 0) || len(p.ByCurrency) != 1 {
+		t.Errorf("Unexpected May point: %+v", p)
+	}
+	if p := series.Points[1]; p.Start != "2025-06-01" || p.Net != 0 || len(p.ByCurrency) != 0 {
+		t.Errorf("Unexpected June point: %+v", p)
+	}
+	if p := series.Points[2]; p.Start != "2025-07-01" || p.Net != -models.NewMoney(700, 0) {
+		t.Errorf("Unexpected July point: %+v", p)
+	}
+
+	w = get("?group_by=week&from=2025-06-25&to=2025-07-01")
+	series = models.TimeSeries{}
+	json.NewDecoder(w.Body).Decode(&series)
+	if w.Code != http.StatusOK || len(series.Points) != 2 || series.Points[0].Start != "2025-06-23" ||
+		series.Points[1].Start != "2025-06-30" || series.Points[1].Expense != models.NewMoney(700, 0) {
+		t.Errorf("Unexpected weekly time series: %d %+v", w.Code, series)
+	}
+
+	w = get("?group_by=day&to=2025-07-01")
+	series = models.TimeSeries{}
+	json.NewDecoder(w.Body).Decode(&series)
+	if w.Code != http.StatusOK || series.From != "2025-06-02" || len(series.Points) != 30 {
+		t.Errorf("Unexpected daily time series: %d %+v", w.Code, series)
+	}
+
+	w = get("?group_by=quarter&from=2025-01-01&to=2025-07-15")
+	series = models.TimeSeries{}
+	json.NewDecoder(w.Body).Decode(&series)
+	if w.Code != http.StatusOK || len(series.Points) != 3 || series.Points[1].Start != "2025-04-01" ||
+		series.Points[1].Income != models.NewMoney(50000, 0) || series.Points[2].Expense != models.NewMoney(700, 0) {
+		t.Errorf("Unexpected quarterly time series: %d %+v", w.Code, series)
+	}
+
+	// С началом месяца 25 числа расход 31 мая попадает в месяц с 25 мая
+	body, _ := json.Marshal(models.SetMonthStartRequest{MonthStart: 25})
+	req, _ := http.NewRequest("PUT", "/me/month-start", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = get("?from=2025-05-10&to=2025-07-15")
+	series = models.TimeSeries{}
+	json.NewDecoder(w.Body).Decode(&series)
+	if w.Code != http.StatusOK || len(series.Points) != 3 || series.Points[0].Start != "2025-04-25" ||
+		series.Points[1].Start != "2025-05-25" || series.Points[1].Expense != models.NewMoney(2000, 0) || series.Points[2].Expense != models.NewMoney(700, 0) {
+		t.Errorf("Unexpected time series with month start: %d %+v", w.Code, series)
+	}
+
+	for _, query := range []string{"?group_by=hour", "?from=2025-07-02&to=2025-07-01", "?group_by=day&from=2000-01-01&to=2025-01-01"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+// TestSpendingTrend тестирует направление и изменение расходов относительно среднего.
+func TestSpendingTrend(t *testing.T) {
+	ptr := func(v float64) *float64 { return &v }
+	tests := []struct {
+		current, average models.Money
+		direction        string
+		change           *float64
+	}{
+		{models.NewMoney(1230, 0), models.NewMoney(1000, 0), "up", ptr(23.0)},
+		{models.NewMoney(500, 0), models.NewMoney(1000, 0), "down", ptr(-50.0)},
+		{models.NewMoney(1000, 0), models.NewMoney(1000, 0), "flat", ptr(0.0)},
+		{models.NewMoney(100, 0), models.NewMoney(300, 0), "down", ptr(-66.7)},
+		{models.NewMoney(100, 0), 0, "new", nil},
+		{0, 0, "flat", nil},
+	}
+	for _, tt := range tests {
+		direction, change := spendingTrend(tt.current, tt.average)
+		if direction != tt.direction || (change == nil) != (tt.change == nil) || (change != nil && *change != *tt.change) {
+			t.Errorf("spendingTrend(%s, %s) = %s %v, expected %s %v", tt.current, tt.average, direction, change, tt.direction, tt.change)
+		}
+	}
+}
+
+// TestGetSpendingTrends тестирует сравнение расходов категорий со средними за предыдущие месяцы.
+func TestGetSpendingTrends(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	cafe, err := storage.CreateCategory(user.ID, "Кафе")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	gifts, err := storage.CreateCategory(user.ID, "Подарки")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC) }
+	for _, transaction := range []*models.Transaction{
+		{Amount: models.NewMoney(5000, 0), CategoryID: food.ID, Date: date(3, 31)},
+		{Amount: models.NewMoney(1000, 0), CategoryID: food.ID, Date: date(4, 1)},
+		{Amount: models.NewMoney(2000, 0), CategoryID: food.ID, Date: date(5, 20)},
+		{Amount: models.NewMoney(1230, 0), CategoryID: food.ID, Date: date(7, 31)},
+		{Amount: models.NewMoney(3000, 0), CategoryID: cafe.ID, Date: date(6, 30)},
+		{Amount: models.NewMoney(500, 0), CategoryID: cafe.ID, Date: date(7, 1)},
+		{Amount: models.NewMoney(100, 0), CategoryID: gifts.ID, Date: date(7, 10)},
+	} {
+		transaction.UserID, transaction.Type, transaction.Currency = user.ID, "expense", "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/trends"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?date=2025-07-15")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var report models.SpendingTrends
+	json.NewDecoder(w.Body).Decode(&report)
+	if report.Period != "month" || report.Start != "2025-07-01" || report.End != "2025-07-31" || report.Window != 3 || len(report.Trends) != 3 {
+		t.Fatalf("Unexpected trends: %+v", report)
+	}
+	// Категории упорядочены по названию: Кафе, Подарки, Продукты
+	if trend := report.Trends[0]; trend.CategoryID != cafe.ID || trend.Average != models.NewMoney(1000, 0) || trend.Direction != "down" ||
+		trend.Change == nil || *trend.Change != -50 {
+		t.Errorf("Unexpected cafe trend: %+v", trend)
+	}
+	if trend := report.Trends[1]; trend.CategoryID != gifts.ID || trend.Direction != "new" || trend.Change != nil {
+		t.Errorf("Unexpected gifts trend: %+v", trend)
+	}
+	// Расходы 31 марта не входят в три предыдущих месяца
+	if trend := report.Trends[2]; trend.CategoryID != food.ID || trend.Current != models.NewMoney(1230, 0) || trend.Average != models.NewMoney(1000, 0) ||
+		trend.Direction != "up" || trend.Change == nil || *trend.Change != 23 {
+		t.Errorf("Unexpected food trend: %+v", trend)
+	}
+
+	w = get("?date=2025-07-15&period=quarter&window=1")
+	report = models.SpendingTrends{}
+	json.NewDecoder(w.Body).Decode(&report)
+	if w.Code != http.StatusOK || report.Start != "2025-07-01" || report.End != "2025-09-30" || len(report.Trends) != 3 ||
+		report.Trends[2].Average != models.NewMoney(3000, 0) {
+		t.Errorf("Unexpected quarterly trends: %d %+v", w.Code, report)
+	}
+
+	for _, query := range []string{"?period=day", "?window=0", "?window=13", "?date=15.07.2025"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+// TestRankPayees тестирует порядок контрагентов в отчете.
+func TestRankPayees(t *testing.T) {
+	payees := []models.TopPayee{
+		{Name: "Аптека", Spent: models.NewMoney(500, 0), Visits: 1},
+		{Name: "Кафе", Spent: models.NewMoney(300, 0), Visits: 5},
+		{Name: "Бензин", Spent: models.NewMoney(500, 0), Visits: 2},
+		{Name: "Булочная", Spent: models.NewMoney(100, 0), Visits: 5},
+	}
+	rankPayees(payees, "spent")
+	if payees[0].Name != "Бензин" || payees[1].Name != "Аптека" || payees[2].Name != "Кафе" {
+		t.Errorf("Unexpected order by spent: %+v", payees)
+	}
+	rankPayees(payees, "visits")
+	if payees[0].Name != "Кафе" || payees[1].Name != "Булочная" || payees[2].Name != "Бензин" {
+		t.Errorf("Unexpected order by visits: %+v", payees)
+	}
+}
+
+// TestGetTopPayees тестирует отчет о главных контрагентах.
+func TestGetTopPayees(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	shop, err := storage.CreatePayee(user.ID, "Пятерочка")
+	if err != nil {
+		t.Fatalf("Failed to create payee: %v", err)
+	}
+	cafe, err := storage.CreatePayee(user.ID, "Кофейня")
+	if err != nil {
+		t.Fatalf("Failed to create payee: %v", err)
+	}
+	employer, err := storage.CreatePayee(user.ID, "Работодатель")
+	if err != nil {
+		t.Fatalf("Failed to create payee: %v", err)
+	}
+	date := func(day int) time.Time { return time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC) }
+	purchase := &models.Transaction{UserID: user.ID, Type: "expense", Amount: models.NewMoney(2000, 0), Currency: "RUB", PayeeID: shop.ID, Date: date(10)}
+	if err := storage.CreateTransaction(purchase); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	for _, transaction := range []*models.Transaction{
+		{Type: "expense", Amount: models.NewMoney(3000, 0), PayeeID: shop.ID, Date: date(1)},
+		// Возврат уменьшает расходы, но не считается покупкой
+		{Type: "income", Amount: models.NewMoney(1000, 0), PayeeID: shop.ID, Date: date(11), LinkedTransactionID: purchase.ID},
+		{Type: "expense", Amount: models.NewMoney(300, 0), PayeeID: cafe.ID, Date: date(2)},
+		{Type: "expense", Amount: models.NewMoney(300, 0), PayeeID: cafe.ID, Date: date(3)},
+		{Type: "expense", Amount: models.NewMoney(300, 0), PayeeID: cafe.ID, Date: date(31)},
+		{Type: "income", Amount: models.NewMoney(100000, 0), PayeeID: employer.ID, Date: date(5)},
+		// Вне периода
+		{Type: "expense", Amount: models.NewMoney(9000, 0), PayeeID: cafe.ID, Date: date(1).AddDate(0, 1, 0)},
+	} {
+		transaction.UserID, transaction.Currency = user.ID, "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/top-payees?from=2025-03-01&to=2025-03-31"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var report models.TopPayees
+	json.NewDecoder(w.Body).Decode(&report)
+	if report.Sort != "spent" || report.Currency != "RUB" || len(report.Payees) != 2 {
+		t.Fatalf("Unexpected report: %+v", report)
+	}
+	if payee := report.Payees[0]; payee.PayeeID != shop.ID || payee.Spent != models.NewMoney(4000, 0) || payee.Visits != 2 ||
+		payee.Average != models.NewMoney(2000, 0) {
+		t.Errorf("Unexpected first payee: %+v", payee)
+	}
+	if payee := report.Payees[1]; payee.PayeeID != cafe.ID || payee.Spent != models.NewMoney(900, 0) || payee.Visits != 3 {
+		t.Errorf("Unexpected second payee: %+v", payee)
+	}
+
+	w = get("&sort=visits&limit=1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	report = models.TopPayees{}
+	json.NewDecoder(w.Body).Decode(&report)
+	if len(report.Payees) != 1 || report.Payees[0].PayeeID != cafe.ID {
+		t.Errorf("Expected only the most visited payee, got %+v", report.Payees)
+	}
+
+	for _, query := range []string{"&limit=0", "&limit=101", "&limit=abc", "&sort=name", "&format=csv"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+// TestGetSpendingStats тестирует статистику расходов за период.
+func TestGetSpendingStats(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	// Период со 2 по 8 июня 2025 года — с понедельника по воскресенье
+	date := func(day int) time.Time { return time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC) }
+	for _, transaction := range []*models.Transaction{
+		{Type: "expense", Amount: models.NewMoney(100, 0), Currency: "RUB", Date: date(2)},
+		{Type: "expense", Amount: models.NewMoney(200, 0), Currency: "RUB", Date: date(2)},
+		{Type: "expense", Amount: models.NewMoney(700, 0), Currency: "RUB", Date: date(4), Description: "Кроссовки"},
+		{Type: "expense", Amount: models.NewMoney(10, 0), Currency: "USD", Date: date(8)},
+		// Валюта только с доходами и расходы вне периода не учитываются
+		{Type: "income", Amount: models.NewMoney(500, 0), Currency: "EUR", Date: date(3)},
+		{Type: "expense", Amount: models.NewMoney(5000, 0), Currency: "RUB", Date: date(9)},
+		{Type: "expense", Amount: models.NewMoney(5000, 0), Currency: "RUB", Date: date(4), Planned: true},
+	} {
+		transaction.UserID = user.ID
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/spending-stats"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?from=2025-06-02&to=2025-06-08")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var stats models.SpendingStats
+	json.NewDecoder(w.Body).Decode(&stats)
+	if stats.Days != 7 || len(stats.Currencies) != 2 || stats.Currencies[0].Currency != "RUB" || stats.Currencies[1].Currency != "USD" {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+	// Дневные расходы в рублях: 300, 0, 700, 0, 0, 0, 0
+	rub := stats.Currencies[0]
+	if rub.Total != models.NewMoney(1000, 0) || rub.ExpenseCount != 3 || rub.MeanDaily != models.NewMoney(142, 86) ||
+		rub.MedianDaily != 0 || rub.StdDevDaily != models.NewMoney(249, 90) {
+		t.Errorf("Unexpected RUB stats: %+v", rub)
+	}
+	if rub.LargestExpense == nil || rub.LargestExpense.Amount != models.NewMoney(700, 0) || rub.LargestExpense.Description != "Кроссовки" {
+		t.Errorf("Unexpected largest expense: %+v", rub.LargestExpense)
+	}
+	if len(rub.ByWeekday) != 7 || rub.ByWeekday[0].Weekday != 1 || rub.ByWeekday[0].Total != models.NewMoney(300, 0) ||
+		rub.ByWeekday[2].Total != models.NewMoney(700, 0) || rub.ByWeekday[6].Total != 0 {
+		t.Errorf("Unexpected RUB weekdays: %+v", rub.ByWeekday)
+	}
+	if usd := stats.Currencies[1]; usd.ByWeekday[6].Weekday != 7 || usd.ByWeekday[6].Total != models.NewMoney(10, 0) {
+		t.Errorf("Unexpected USD weekdays: %+v", usd.ByWeekday)
+	}
+
+	for _, query := range []string{"?from=2025-06-09&to=2025-06-08", "?from=06.2025", "?format=csv"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+// TestSavingsRate тестирует вычисление нормы сбережений.
+func TestSavingsRate(t *testing.T) {
+	if rate := savingsRate(models.NewMoney(1000, 0), models.NewMoney(700, 0)); rate == nil || *rate != 30 {
+		t.Errorf("Expected 30%%, got %v", rate)
+	}
+	if rate := savingsRate(models.NewMoney(3000, 0), models.NewMoney(4000, 0)); rate == nil || *rate != -33.3 {
+		t.Errorf("Expected -33.3%%, got %v", rate)
+	}
+	if rate := savingsRate(0, models.NewMoney(100, 0)); rate != nil {
+		t.Errorf("Expected undefined rate without income, got %v", *rate)
+	}
+}
+
+// TestGetSavingsRate тестирует норму сбережений по месяцам.
+func TestGetSavingsRate(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	card, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Карта", Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	savings, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Вклад", Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC) }
+	for _, transaction := range []*models.Transaction{
+		{Type: "income", Amount: models.NewMoney(100000, 0), Date: date(5, 5)},
+		{Type: "expense", Amount: models.NewMoney(60000, 0), Date: date(5, 20)},
+		{Type: "expense", Amount: models.NewMoney(5000, 0), Date: date(6, 10)},
+	} {
+		transaction.UserID, transaction.AccountID, transaction.Currency = user.ID, card.ID, "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	// Перевод на вклад и корректировка остатка не меняют норму сбережений
+	transfer := &models.Transfer{FromAccountID: card.ID, ToAccountID: savings.ID, Amount: models.NewMoney(30000, 0), Date: date(5, 25)}
+	if err := storage.CreateTransfer(user.ID, transfer); err != nil {
+		t.Fatalf("Failed to create transfer: %v", err)
+	}
+	if _, err := storage.CreateAdjustment(user.ID, savings.ID, models.NewMoney(31000, 0), date(5, 31), "Проценты"); err != nil {
+		t.Fatalf("Failed to create adjustment: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/savings-rate"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?from=2025-05-01&to=2025-06-30")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var result models.SavingsRate
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.GroupBy != "month" || len(result.Points) != 2 {
+		t.Fatalf("Unexpected savings rate: %+v", result)
+	}
+	if p := result.Points[0]; p.Income != models.NewMoney(100000, 0) || p.Expense != models.NewMoney(60000, 0) || p.Rate == nil || *p.Rate != 40 {
+		t.Errorf("Unexpected May point: %+v", p)
+	}
+	if p := result.Points[1]; p.Savings != -models.NewMoney(5000, 0) || p.Rate != nil {
+		t.Errorf("Unexpected June point: %+v", p)
+	}
+	if result.Savings != models.NewMoney(35000, 0) || result.Rate == nil || *result.Rate != 35 {
+		t.Errorf("Unexpected period totals: %+v", result)
+	}
+
+	for _, query := range []string{"?group_by=hour", "?from=2025-07-02&to=2025-07-01", "?format=csv"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
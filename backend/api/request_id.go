@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader — заголовок с идентификатором запроса.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает длину идентификатора, полученного от клиента или прокси.
+const maxRequestIDLength = 128
+
+// validRequestID проверяет идентификатор из входящего запроса: он попадает в логи и ответы,
+// поэтому допускаются только буквы, цифры и символы -_.:
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDWriter добавляет идентификатор запроса в JSON-ответы с ошибкой.
+type requestIDWriter struct {
+	gin.ResponseWriter
+	id string
+}
+
+func (w *requestIDWriter) Write(b []byte) (int, error) {
+	if w.Status() < 400 || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return w.ResponseWriter.Write(b)
+	}
+	// Ответ с ошибкой записывается одним объектом: поле добавляется перед закрывающей скобкой
+	body := bytes.TrimRight(b, " \n")
+	if len(body) < 2 || body[0] != '{' || body[len(body)-1] != '}' {
+		return w.ResponseWriter.Write(b)
+	}
+	field := `"request_id":` + strconv.Quote(w.id)
+	if len(bytes.TrimSpace(body[1:len(body)-1])) > 0 {
+		field = "," + field
+	}
+	patched := append(append(body[:len(body)-1:len(body)-1], field...), '}')
+	if _, err := w.ResponseWriter.Write(patched); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *requestIDWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// RequestIDMiddleware берет идентификатор запроса из заголовка X-Request-ID или создает новый,
+// возвращает его в заголовке ответа и в поле request_id ответов с ошибкой и сохраняет
+// в контексте под ключом request_id для логов.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			var err error
+			if id, err = randomString(); err != nil {
+				id = strconv.FormatInt(time.Now().UnixNano(), 36)
+			}
+		}
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Writer = &requestIDWriter{ResponseWriter: c.Writer, id: id}
+		c.Next()
+	}
+}
+
+// redactedQueryParams — параметры запроса, значения которых не попадают в журнал:
+// token передает JWT потоковым маршрутам /ws и /events.
+var redactedQueryParams = []string{"token"}
+
+// redactPath заменяет в пути с параметрами запроса значения секретных параметров, сохраняя остальные как есть.
+func redactPath(path string) string {
+	base, query, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if slices.Contains(redactedQueryParams, name) {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// LogFormatter — формат журнала запросов gin с идентификатором запроса.
+// Значения секретных параметров запроса, например токена потоковых маршрутов, скрываются.
+func LogFormatter(param gin.LogFormatterParams) string {
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | request_id=%v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		redactPath(param.Path),
+		param.Keys["request_id"],
+		param.ErrorMessage,
+	)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRequestIDMiddleware проверяет создание и передачу идентификатора запроса
+// и его добавление в ответы с ошибкой.
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"status": "ok"}) })
+	r.GET("/error", func(c *gin.Context) { c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"}) })
+	r.GET("/empty", func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{}) })
+	r.GET("/text", func(c *gin.Context) { c.String(http.StatusInternalServerError, "failure") })
+
+	request := func(path, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if id != "" {
+			req.Header.Set(RequestIDHeader, id)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("/ok", "")
+	generated := w.Header().Get(RequestIDHeader)
+	if len(generated) != 32 {
+		t.Errorf("Expected generated request ID, got %q", generated)
+	}
+	if w.Body.String() != `{"status":"ok"}` {
+		t.Errorf("Expected successful response unchanged, got %s", w.Body.String())
+	}
+	if other := request("/ok", "").Header().Get(RequestIDHeader); other == generated {
+		t.Error("Expected unique request IDs")
+	}
+
+	w = request("/error", "req-42")
+	if w.Header().Get(RequestIDHeader) != "req-42" {
+		t.Errorf("Expected incoming request ID, got %q", w.Header().Get(RequestIDHeader))
+	}
+	if w.Body.String() != `{"error":"invalid","request_id":"req-42"}` {
+		t.Errorf("Expected request ID in error response, got %s", w.Body.String())
+	}
+	if w := request("/empty", "req-43"); w.Body.String() != `{"request_id":"req-43"}` {
+		t.Errorf("Expected request ID in empty error object, got %s", w.Body.String())
+	}
+	if w := request("/text", "req-44"); w.Body.String() != "failure" {
+		t.Errorf("Expected non-JSON response unchanged, got %s", w.Body.String())
+	}
+
+	// Недопустимый идентификатор заменяется новым
+	for _, id := range []string{"bad id", "<script>", strings.Repeat("a", maxRequestIDLength+1)} {
+		if got := request("/ok", id).Header().Get(RequestIDHeader); got == id || len(got) != 32 {
+			t.Errorf("Expected %q to be replaced, got %q", id, got)
+		}
+	}
+}
+
+func TestLogFormatter(t *testing.T) {
+	line := LogFormatter(gin.LogFormatterParams{
+		TimeStamp:  time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		StatusCode: http.StatusNotFound,
+		Method:     http.MethodGet,
+		Path:       "/transactions/1",
+		Keys:       map[string]any{"request_id": "req-42"},
+	})
+	if !strings.Contains(line, "request_id=req-42") || !strings.Contains(line, `"/transactions/1"`) {
+		t.Errorf("Unexpected log line: %s", line)
+	}
+
+	// Токен потоковых маршрутов не попадает в журнал
+	line = LogFormatter(gin.LogFormatterParams{Method: http.MethodGet, Path: "/events?last_event_id=5&token=eyJhbGciOi.secret&%74oken=x"})
+	if strings.Contains(line, "secret") || strings.Contains(line, "=x") || !strings.Contains(line, `"/events?last_event_id=5&token=REDACTED&token=REDACTED"`) {
+		t.Errorf("Expected token to be redacted: %s", line)
+	}
+}
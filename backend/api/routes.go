@@ -0,0 +1,190 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// V1Prefix — префикс маршрутов первой версии API. Каждая версия монтируется в свою группу,
+// поэтому несовместимая версия (/api/v2) сможет работать одновременно с предыдущей.
+const V1Prefix = "/api/v1"
+
+// legacyDeprecatedAt — дата, с которой маршруты без версии считаются устаревшими: выход /api/v1.
+var legacyDeprecatedAt = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+// versionPrefix — префикс версии в шаблоне маршрута.
+var versionPrefix = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// unversionedRoute возвращает шаблон маршрута без префикса версии: "/api/v1/categories" — "/categories".
+func unversionedRoute(path string) string {
+	return versionPrefix.ReplaceAllString(path, "")
+}
+
+// RegisterRoutes регистрирует маршруты API первой версии в группе g.
+func (h *Handler) RegisterRoutes(g *gin.RouterGroup) {
+	public := g.Group("/", h.IPQuotaMiddleware())
+	public.POST("/register", h.Register)
+	public.POST("/login", h.Login)
+	public.POST("/auth/magic-link", h.RequestMagicLink)
+	public.GET("/auth/magic-link/verify", h.VerifyMagicLink)
+	public.GET("/me/email/confirm", h.ConfirmEmailChange)
+	public.GET("/auth/oidc/login", h.OIDCLogin)
+	public.GET("/auth/oidc/callback", h.OIDCCallback)
+
+	// Потоки событий принимают токен и в параметре запроса
+	streams := g.Group("/", h.StreamAuthMiddleware(), h.QuotaMiddleware())
+	streams.GET("/ws", h.WebSocket)
+	streams.GET("/events", h.StreamEvents)
+
+	protected := g.Group("/", h.AuthMiddleware(), h.QuotaMiddleware(), h.ETagMiddleware(), h.CacheMiddleware())
+	protected.GET("/transactions", h.GetTransactions)
+	protected.GET("/transactions/search", h.SearchTransactions)
+	protected.GET("/transactions/export", h.ExportTransactions)
+	protected.GET("/transactions/:id", h.GetTransaction)
+	protected.POST("/transactions", h.CreateTransaction)
+	protected.POST("/transactions/bulk", h.CreateTransactionsBulk)
+	protected.POST("/transactions/import", h.ImportTransactions)
+	protected.POST("/transactions/receipt", h.ScanReceipt)
+	protected.POST("/imports", h.CreateImportJob)
+	protected.GET("/imports/:id", h.GetImportJob)
+	protected.POST("/imports/:id/cancel", h.CancelImportJob)
+	protected.POST("/transactions/mark-cleared", h.MarkTransactionsCleared)
+	protected.DELETE("/transactions", h.DeleteTransactionsBulk)
+	protected.POST("/transactions/:id/restore", h.RestoreTransaction)
+	protected.POST("/transactions/:id/duplicate", h.DuplicateTransaction)
+	protected.POST("/transactions/:id/resolve-duplicate", h.ResolveDuplicate)
+	protected.GET("/transactions/:id/history", h.GetTransactionHistory)
+	protected.GET("/transactions/:id/linked", h.GetLinkedTransactions)
+	protected.POST("/transactions/:id/flag", h.ToggleTransactionFlag)
+	protected.GET("/transactions/:id/receipt", h.GetReceipt)
+	protected.POST("/transactions/:id/revert/:version", h.RevertTransaction)
+	protected.GET("/trash", h.GetTrash)
+	protected.DELETE("/trash", h.EmptyTrash)
+	protected.DELETE("/transactions/:id", h.DeleteTransaction)
+	protected.PUT("/transactions/:id", h.UpdateTransaction)
+	protected.PATCH("/transactions/:id", h.PatchTransaction)
+	protected.POST("/categories", h.CreateCategory)
+	protected.GET("/categories", h.GetCategories)
+	protected.GET("/categories/icons", h.GetCategoryIcons)
+	protected.GET("/categories/:id", h.GetCategory)
+	protected.GET("/categories/:id/stats", h.GetCategoryStats)
+	protected.GET("/categories/:id/keywords", h.GetCategoryKeywords)
+	protected.POST("/categories/:id/keywords", h.CreateCategoryKeyword)
+	protected.DELETE("/categories/:id/keywords/:keyword_id", h.DeleteCategoryKeyword)
+	protected.GET("/accounts", h.GetAccounts)
+	protected.POST("/accounts", h.CreateAccount)
+	protected.GET("/accounts/:id", h.GetAccount)
+	protected.PUT("/accounts/:id", h.UpdateAccount)
+	protected.DELETE("/accounts/:id", h.DeleteAccount)
+	protected.GET("/accounts/:id/balance", h.GetAccountBalance)
+	protected.GET("/accounts/:id/statement", h.GetCreditCardStatement)
+	protected.POST("/accounts/:id/adjustments", h.CreateAdjustment)
+	protected.GET("/accounts/:id/balance-history", h.GetAccountBalanceHistory)
+	protected.GET("/accounts/balance-history", h.GetBalanceHistory)
+	protected.GET("/accounts/:id/amortization", h.GetAmortizationSchedule)
+	protected.GET("/accounts/:id/loan", h.GetLoanStatus)
+	protected.POST("/accounts/:id/loan-payments", h.CreateLoanPayment)
+	protected.GET("/accounts/:id/holdings", h.GetHoldings)
+	protected.POST("/accounts/:id/holdings", h.CreateHolding)
+	protected.PUT("/accounts/:id/holdings/:holding_id", h.UpdateHolding)
+	protected.DELETE("/accounts/:id/holdings/:holding_id", h.DeleteHolding)
+	protected.GET("/accounts/:id/valuation", h.GetInvestmentValuation)
+	protected.GET("/accounts/:id/valuation-history", h.GetValuationHistory)
+	protected.GET("/accounts/:id/shares", h.GetAccountShares)
+	protected.PUT("/accounts/:id/shares", h.ShareAccount)
+	protected.DELETE("/accounts/:id/shares/:user_id", h.DeleteAccountShare)
+	protected.GET("/accounts/:id/transactions", h.GetAccountTransactions)
+	protected.POST("/accounts/:id/close", h.CloseAccount)
+	protected.POST("/accounts/:id/reopen", h.ReopenAccount)
+	protected.GET("/accounts/available-funds", h.GetAvailableFunds)
+	protected.GET("/budgets", h.GetBudgets)
+	protected.POST("/budgets", h.CreateBudget)
+	protected.GET("/budgets/:id", h.GetBudget)
+	protected.PUT("/budgets/:id", h.UpdateBudget)
+	protected.DELETE("/budgets/:id", h.DeleteBudget)
+	protected.GET("/budget-templates", h.GetBudgetTemplates)
+	protected.POST("/budget-templates", h.CreateBudgetTemplate)
+	protected.GET("/budget-templates/:id", h.GetBudgetTemplate)
+	protected.PUT("/budget-templates/:id", h.UpdateBudgetTemplate)
+	protected.DELETE("/budget-templates/:id", h.DeleteBudgetTemplate)
+	protected.POST("/budget-templates/:id/apply", h.ApplyBudgetTemplate)
+	protected.GET("/goals", h.GetGoals)
+	protected.POST("/goals", h.CreateGoal)
+	protected.GET("/goals/:id", h.GetGoal)
+	protected.PUT("/goals/:id", h.UpdateGoal)
+	protected.DELETE("/goals/:id", h.DeleteGoal)
+	protected.GET("/goals/:id/contributions", h.GetGoalContributions)
+	protected.POST("/goals/:id/contributions", h.CreateGoalContribution)
+	protected.DELETE("/goals/:id/contributions/:contribution_id", h.DeleteGoalContribution)
+	protected.GET("/transfers", h.GetTransfers)
+	protected.POST("/transfers", h.CreateTransfer)
+	protected.GET("/transfers/:id", h.GetTransfer)
+	protected.DELETE("/transfers/:id", h.DeleteTransfer)
+	protected.PUT("/categories/:id", h.UpdateCategory)
+	protected.PATCH("/categories/:id", h.PatchCategory)
+	protected.POST("/categories/:id/reassign", h.ReassignCategory)
+	protected.DELETE("/categories/:id", h.DeleteCategory)
+	protected.POST("/tags", h.CreateTag)
+	protected.GET("/tags", h.GetTags)
+	protected.GET("/tags/:id", h.GetTag)
+	protected.PUT("/tags/:id", h.UpdateTag)
+	protected.DELETE("/tags/:id", h.DeleteTag)
+	protected.POST("/payees", h.CreatePayee)
+	protected.GET("/payees", h.GetPayees)
+	protected.GET("/payees/:id", h.GetPayee)
+	protected.GET("/payees/:id/stats", h.GetPayeeStats)
+	protected.PUT("/payees/:id", h.UpdatePayee)
+	protected.DELETE("/payees/:id", h.DeletePayee)
+	protected.GET("/me", h.GetProfile)
+	protected.GET("/me/export", h.ExportData)
+	protected.GET("/me/logins", h.GetLogins)
+	protected.PUT("/me/email", h.ChangeEmail)
+	protected.PUT("/me/currency", h.SetBaseCurrency)
+	protected.PUT("/me/month-start", h.SetMonthStart)
+	protected.PUT("/me/language", h.SetLanguage)
+	protected.GET("/me/report-emails", h.GetReportEmailPreferences)
+	protected.PUT("/me/report-emails", h.SetReportEmailPreferences)
+	protected.GET("/reports/statement.pdf", h.GetStatementPDF)
+	protected.GET("/reports/net-worth", h.GetNetWorth)
+	protected.GET("/reports/net-worth/history", h.GetNetWorthHistory)
+	protected.GET("/reports/budget-vs-actual", h.GetBudgetVsActual)
+	protected.GET("/reports/summary", h.GetPeriodSummary)
+	protected.GET("/reports/timeseries", h.GetTimeSeries)
+	protected.GET("/reports/savings-rate", h.GetSavingsRate)
+	protected.GET("/reports/trends", h.GetSpendingTrends)
+	protected.GET("/reports/forecast", h.GetForecast)
+	protected.POST("/reports/query", h.RunReportQuery)
+	protected.GET("/reports/top-payees", h.GetTopPayees)
+	protected.GET("/reports/spending-stats", h.GetSpendingStats)
+	protected.GET("/reports/compare", h.ComparePeriods)
+	protected.GET("/reports/sankey", h.GetCashFlowSankey)
+	protected.GET("/reports/subscriptions", h.GetSubscriptions)
+	protected.POST("/reports/subscriptions/:id/schedule", h.ScheduleSubscription)
+	protected.GET("/dashboard", h.GetDashboard)
+
+	admin := protected.Group("/admin", h.AdminMiddleware())
+	admin.POST("/invites", h.CreateInvite)
+	admin.GET("/categories", h.GetSystemCategories)
+	admin.POST("/categories", h.CreateSystemCategory)
+	admin.PUT("/categories/:id", h.UpdateSystemCategory)
+	admin.DELETE("/categories/:id", h.DeleteSystemCategory)
+	admin.GET("/jobs", h.GetJobs)
+}
+
+// DeprecationMiddleware отмечает ответы маршрутов без версии как устаревшие: заголовок Deprecation
+// содержит дату, с которой они устарели (RFC 9745), Sunset — дату отключения, если она объявлена
+// (RFC 8594), а Link — адрес того же ресурса в версии с префиксом successor.
+func DeprecationMiddleware(successor string, sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()))
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, c.Request.URL.Path))
+		c.Next()
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRegisterRoutes тестирует маршруты с префиксом версии и заголовки устаревших маршрутов без версии.
+func TestRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	handler := NewHandler(nil, Config{JWTSecret: "secret"})
+	sunset := time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	r := gin.New()
+	handler.RegisterRoutes(r.Group(V1Prefix))
+	handler.RegisterRoutes(r.Group("/", DeprecationMiddleware(V1Prefix, sunset)))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/categories", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	if w.Header().Get("Deprecation") != "" {
+		t.Errorf("Versioned route must not be deprecated, got %q", w.Header().Get("Deprecation"))
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/categories/5", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+	if got := w.Header().Get("Deprecation"); got != "@1792281600" {
+		t.Errorf("Unexpected Deprecation header %q", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Thu, 01 Apr 2027 00:00:00 GMT" {
+		t.Errorf("Unexpected Sunset header %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `</api/v1/categories/5>; rel="successor-version"` {
+		t.Errorf("Unexpected Link header %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/categories", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown version, got %d", w.Code)
+	}
+}
+
+// TestUnversionedRoute тестирует удаление префикса версии из шаблона маршрута.
+func TestUnversionedRoute(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/categories":      "/categories",
+		"/api/v2/reports/summary": "/reports/summary",
+		"/categories":             "/categories",
+		"/api/versions":           "/api/versions",
+	}
+	for path, want := range tests {
+		if got := unversionedRoute(path); got != want {
+			t.Errorf("unversionedRoute(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if !cachedRoute("/api/v1/reports/summary") || cachedRoute("/api/v1/transactions") {
+		t.Error("cachedRoute must ignore the version prefix")
+	}
+}
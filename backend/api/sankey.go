@@ -0,0 +1,164 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+)
+
+// convertCategoryTotals пересчитывает суммы категорий в currency и складывает суммы одной категории в разных валютах.
+// Порядок категорий сохраняется.
+func convertCategoryTotals(rows []models.CategoryTotals, exchangeRates map[string]float64, currency string) ([]models.CategoryTotals, error) {
+	categories := []models.CategoryTotals{}
+	index := make(map[int]int)
+	for _, row := range rows {
+		converted, err := sumConverted(exchangeRates, []models.TransactionTotals{{Currency: row.Currency, Income: row.Income, Expense: row.Expense}}, currency)
+		if err != nil {
+			return nil, err
+		}
+		i, ok := index[row.CategoryID]
+		if !ok {
+			i = len(categories)
+			index[row.CategoryID] = i
+			categories = append(categories, models.CategoryTotals{CategoryID: row.CategoryID, Category: row.Category, Currency: currency})
+		}
+		categories[i].Income += converted.Income
+		categories[i].Expense += converted.Expense
+	}
+	return categories, nil
+}
+
+// buildSankey строит узлы и связи диаграммы из сумм категорий в одной валюте: доходы категорий сходятся в узел budget,
+// из которого расходятся расходы категорий. Остаток доходов уходит в узел savings, расходы сверх доходов
+// приходят из узла deficit. Категории упорядочены по убыванию суммы, нулевые и отрицательные суммы пропускаются.
+func buildSankey(categories []models.CategoryTotals) ([]models.SankeyNode, []models.SankeyLink) {
+	nodes, links := []models.SankeyNode{}, []models.SankeyLink{}
+	name := func(c models.CategoryTotals) string {
+		if c.CategoryID == 0 {
+			return "Без категории"
+		}
+		return c.Category
+	}
+	byValue := func(value func(models.CategoryTotals) models.Money) []models.CategoryTotals {
+		result := []models.CategoryTotals{}
+		for _, c := range categories {
+			if value(c) > 0 {
+				result = append(result, c)
+			}
+		}
+		sort.SliceStable(result, func(i, j int) bool { return value(result[i]) > value(result[j]) })
+		return result
+	}
+	incomes := byValue(func(c models.CategoryTotals) models.Money { return c.Income })
+	expenses := byValue(func(c models.CategoryTotals) models.Money { return c.Expense })
+	if len(incomes) == 0 && len(expenses) == 0 {
+		return nodes, links
+	}
+
+	var income, expense models.Money
+	for _, c := range incomes {
+		id := "income:" + strconv.Itoa(c.CategoryID)
+		nodes = append(nodes, models.SankeyNode{ID: id, Name: name(c), Kind: "income"})
+		links = append(links, models.SankeyLink{Source: id, Target: "budget", Value: c.Income})
+		income += c.Income
+	}
+	for _, c := range expenses {
+		expense += c.Expense
+	}
+	if expense > income {
+		nodes = append(nodes, models.SankeyNode{ID: "deficit", Name: "Расходы сверх доходов", Kind: "deficit"})
+		links = append(links, models.SankeyLink{Source: "deficit", Target: "budget", Value: expense - income})
+	}
+	nodes = append(nodes, models.SankeyNode{ID: "budget", Name: "Бюджет", Kind: "budget"})
+	for _, c := range expenses {
+		id := "expense:" + strconv.Itoa(c.CategoryID)
+		nodes = append(nodes, models.SankeyNode{ID: id, Name: name(c), Kind: "expense"})
+		links = append(links, models.SankeyLink{Source: "budget", Target: id, Value: c.Expense})
+	}
+	if income > expense {
+		nodes = append(nodes, models.SankeyNode{ID: "savings", Name: "Сбережения", Kind: "savings"})
+		links = append(links, models.SankeyLink{Source: "budget", Target: "savings", Value: income - expense})
+	}
+	return nodes, links
+}
+
+// @Security ApiKeyAuth
+// @Summary Диаграмма денежных потоков
+// @Description Возвращает потоки за период в базовой валюте пользователя в виде узлов и связей диаграммы Санкея:
+// @Description доходы категорий сходятся в узел бюджета, из которого расходятся расходы категорий за вычетом возвратов
+// @Description и остаток в сбережения. Переводы и корректировки не учитываются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string false "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)"
+// @Param to query string false "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)"
+// @Param include_planned query bool false "Включать запланированные транзакции (по умолчанию false)"
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.CashFlowSankey
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/sankey [get]
+func (h *Handler) GetCashFlowSankey(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	from, to, err := parseReportRange(c, user.MonthStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	includePlanned, err := parseIncludePlanned(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	totals, err := h.storage.GetCategoryTotals(user.ID, from, to.AddDate(0, 0, 1), includePlanned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	currencies := make([]models.TransactionTotals, 0, len(totals))
+	for _, row := range totals {
+		currencies = append(currencies, models.TransactionTotals{Currency: row.Currency})
+	}
+	exchangeRates, err := h.exchangeRatesFor(c.Request.Context(), user.BaseCurrency, currencies)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+	categories, err := convertCategoryTotals(totals, exchangeRates, user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+
+	sankey := models.CashFlowSankey{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Currency: user.BaseCurrency,
+	}
+	sankey.Nodes, sankey.Links = buildSankey(categories)
+
+	writeReport(c, fmt.Sprintf("sankey-%s-%s", sankey.From, sankey.To), sankey,
+		func() report.Workbook { return sankeyWorkbook(sankey) })
+}
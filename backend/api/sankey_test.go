@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestBuildSankey тестирует узлы и связи диаграммы денежных потоков.
+func TestBuildSankey(t *testing.T) {
+	nodes, links := buildSankey(nil)
+	if len(nodes) != 0 || len(links) != 0 {
+		t.Errorf("Expected empty diagram, got %v %v", nodes, links)
+	}
+
+	nodes, links = buildSankey([]models.CategoryTotals{
+		{CategoryID: 1, Category: "Зарплата", Income: models.NewMoney(100000, 0)},
+		{CategoryID: 2, Category: "Продукты", Expense: models.NewMoney(20000, 0)},
+		{CategoryID: 3, Category: "Аренда", Expense: models.NewMoney(40000, 0)},
+		// Возвраты больше расходов: категория не попадает в диаграмму
+		{CategoryID: 4, Category: "Одежда", Expense: models.NewMoney(-500, 0)},
+		{CategoryID: 0, Income: models.NewMoney(5000, 0), Expense: models.NewMoney(1000, 0)},
+	})
+	expectedNodes := []string{"income:1", "income:0", "budget", "expense:3", "expense:2", "expense:0", "savings"}
+	if len(nodes) != len(expectedNodes) {
+		t.Fatalf("Expected nodes %v, got %+v", expectedNodes, nodes)
+	}
+	for i, id := range expectedNodes {
+		if nodes[i].ID != id {
+			t.Errorf("Expected node %d to be %s, got %+v", i, id, nodes[i])
+		}
+	}
+	if nodes[1].Name != "Без категории" {
+		t.Errorf("Expected uncategorized node name, got %q", nodes[1].Name)
+	}
+	if last := links[len(links)-1]; last.Source != "budget" || last.Target != "savings" || last.Value != models.NewMoney(44000, 0) {
+		t.Errorf("Unexpected savings link: %+v", last)
+	}
+
+	nodes, links = buildSankey([]models.CategoryTotals{
+		{CategoryID: 1, Category: "Зарплата", Income: models.NewMoney(1000, 0)},
+		{CategoryID: 2, Category: "Продукты", Expense: models.NewMoney(1500, 0)},
+	})
+	if len(nodes) != 4 || nodes[1].ID != "deficit" || links[1].Source != "deficit" || links[1].Value != models.NewMoney(500, 0) {
+		t.Errorf("Expected deficit node, got %+v %+v", nodes, links)
+	}
+}
+
+// TestConvertCategoryTotals тестирует пересчет сумм категорий в одну валюту.
+func TestConvertCategoryTotals(t *testing.T) {
+	exchangeRates := map[string]float64{"RUB": 1, "USD": 1.0 / 80}
+	categories, err := convertCategoryTotals([]models.CategoryTotals{
+		{CategoryID: 2, Category: "Продукты", Currency: "RUB", Expense: models.NewMoney(1000, 0)},
+		{CategoryID: 2, Category: "Продукты", Currency: "USD", Expense: models.NewMoney(10, 0), Income: models.NewMoney(1, 0)},
+		{CategoryID: 1, Category: "Кафе", Currency: "RUB", Expense: models.NewMoney(300, 0)},
+	}, exchangeRates, "RUB")
+	if err != nil {
+		t.Fatalf("Failed to convert totals: %v", err)
+	}
+	if len(categories) != 2 || categories[0].CategoryID != 2 || categories[0].Expense != models.NewMoney(1800, 0) ||
+		categories[0].Income != models.NewMoney(80, 0) || categories[1].Currency != "RUB" {
+		t.Errorf("Unexpected categories: %+v", categories)
+	}
+}
+
+// TestGetCashFlowSankey тестирует диаграмму денежных потоков за период.
+func TestGetCashFlowSankey(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	salary, err := storage.CreateCategory(user.ID, "Зарплата")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	date := time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	for _, transaction := range []*models.Transaction{
+		{Type: "income", Amount: models.NewMoney(50000, 0), CategoryID: salary.ID, Date: date},
+		{Type: "expense", Amount: models.NewMoney(12000, 0), CategoryID: food.ID, Date: date},
+		{Type: "expense", Amount: models.NewMoney(9000, 0), CategoryID: food.ID, Date: date.AddDate(0, 1, 0)},
+	} {
+		transaction.UserID, transaction.Currency = user.ID, "RUB"
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/reports/sankey"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?from=2025-07-01&to=2025-07-31")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var sankey models.CashFlowSankey
+	json.NewDecoder(w.Body).Decode(&sankey)
+	if sankey.Currency != "RUB" || len(sankey.Nodes) != 4 || len(sankey.Links) != 3 {
+		t.Fatalf("Unexpected diagram: %+v", sankey)
+	}
+	if link := sankey.Links[1]; link.Source != "budget" || link.Target != "expense:"+strconv.Itoa(food.ID) || link.Value != models.NewMoney(12000, 0) {
+		t.Errorf("Unexpected expense link: %+v", link)
+	}
+	if link := sankey.Links[2]; link.Target != "savings" || link.Value != models.NewMoney(38000, 0) {
+		t.Errorf("Unexpected savings link: %+v", link)
+	}
+
+	for _, query := range []string{"?from=2025-08-01&to=2025-07-01", "?include_planned=maybe", "?format=csv"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const maxSearchQueryLength = 200
+
+// @Security ApiKeyAuth
+// @Summary Полнотекстовый поиск транзакций
+// @Description Ищет транзакции по описанию, названию категории и имени контрагента с учетом словоформ и по подстроке.
+// @Description Результаты упорядочены по релевантности, совпадения в описании выделены тегом <mark>.
+// @Tags transactions
+// @Produce json
+// @Param q query string true "Поисковый запрос (поддерживаются кавычки, or и -)"
+// @Param page query int false "Номер страницы"
+// @Param limit query int false "Лимит на страницу"
+// @Success 200 {object} models.SearchTransactionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transactions/search [get]
+func (h *Handler) SearchTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is too long"})
+		return
+	}
+
+	page, limit, err := parsePageLimit(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, total, err := h.storage.SearchTransactions(userID.(int), query, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SearchTransactionsResponse{Results: results, Total: total})
+}
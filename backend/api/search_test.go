@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestSearchTransactions тестирует полнотекстовый поиск по описанию и категории.
+func TestSearchTransactions(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	travel, err := storage.CreateCategory(user.ID, "Путешествия")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: food.ID, Description: "Молоко и хлеб"},
+		{UserID: user.ID, Amount: models.NewMoney(20, 0), Type: "expense", CategoryID: travel.ID, Description: "Билеты на поезд"},
+		{UserID: user.ID, Amount: models.NewMoney(30, 0), Type: "expense", CategoryID: travel.ID, Description: "Гостиница"},
+	}
+	if err := storage.CreateTransactions(transactions); err != nil {
+		t.Fatalf("Failed to create transactions: %v", err)
+	}
+	// Удаленные транзакции не находятся
+	if _, err := storage.DeleteTransaction(transactions[2].ID, user.ID); err != nil {
+		t.Fatalf("Failed to delete transaction: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	search := func(query string) (int, models.SearchTransactionsResponse) {
+		req, _ := http.NewRequest("GET", "/transactions/search?q="+url.QueryEscape(query), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.SearchTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+
+	if code, _ := search(""); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, code)
+	}
+
+	// Поиск с учетом словоформ и выделением совпадения
+	code, response := search("билет")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+	}
+	if response.Total != 1 || response.Results[0].ID != transactions[1].ID {
+		t.Fatalf("Expected transaction %d, got %+v", transactions[1].ID, response)
+	}
+	if !strings.Contains(response.Results[0].Highlight, "<mark>Билеты</mark>") {
+		t.Errorf("Expected highlighted match, got %q", response.Results[0].Highlight)
+	}
+
+	// Поиск по названию категории
+	_, response = search("путешествия")
+	if response.Total != 1 || response.Results[0].ID != transactions[1].ID {
+		t.Errorf("Expected transaction %d by category name, got %+v", transactions[1].ID, response)
+	}
+
+	// Поиск по подстроке
+	_, response = search("олок")
+	if response.Total != 1 || response.Results[0].ID != transactions[0].ID {
+		t.Errorf("Expected transaction %d by substring, got %+v", transactions[0].ID, response)
+	}
+
+	_, response = search("гостиница")
+	if response.Total != 0 {
+		t.Errorf("Expected deleted transaction not to be found, got %+v", response)
+	}
+}
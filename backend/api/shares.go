@@ -0,0 +1,186 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// canEditAccount проверяет, что пользователь может вести операции по счету: владеет им или счет открыт ему
+// для редактирования. Иначе отвечает клиенту и возвращает false.
+func canEditAccount(c *gin.Context, account *models.Account) bool {
+	if account.Role == "viewer" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "account is shared with read-only access"})
+		return false
+	}
+	return true
+}
+
+// loadOwnAccount читает счет из параметра id и проверяет, что пользователь — его владелец;
+// иначе отвечает клиенту и возвращает false.
+func (h *Handler) loadOwnAccount(c *gin.Context) (*models.Account, bool) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return nil, false
+	}
+	if account.Role != "owner" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the account owner can manage access"})
+		return nil, false
+	}
+	return account, true
+}
+
+// @Security ApiKeyAuth
+// @Summary Пользователи с доступом к счету
+// @Description Возвращает пользователей, которым владелец открыл доступ к счету
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Success 200 {array} models.AccountShare
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/shares [get]
+func (h *Handler) GetAccountShares(c *gin.Context) {
+	account, ok := h.loadOwnAccount(c)
+	if !ok {
+		return
+	}
+
+	shares, err := h.storage.GetAccountShares(account.ID, account.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+// @Security ApiKeyAuth
+// @Summary Открыть доступ к счету
+// @Description Открывает зарегистрированному пользователю доступ к счету или меняет его роль. viewer видит счет,
+// @Description его остаток и транзакции всех пользователей по счету, editor также создает по счету транзакции, переводы
+// @Description и позиции. Транзакции создаются от имени их автора в его категориях. Открыть доступ может только владелец
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path int true "ID счета"
+// @Param share body models.ShareAccount true "Пользователь и роль"
+// @Success 200 {object} models.AccountShare
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/shares [put]
+func (h *Handler) ShareAccount(c *gin.Context) {
+	account, ok := h.loadOwnAccount(c)
+	if !ok {
+		return
+	}
+
+	var request models.ShareAccount
+	if !bindJSON(c, &request) {
+		return
+	}
+	user, err := h.storage.GetUserByUsername(strings.TrimSpace(request.Username))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	share, err := h.storage.ShareAccount(account.ID, account.UserID, user.ID, request.Role)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "owner") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if share == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, share)
+}
+
+// @Security ApiKeyAuth
+// @Summary Закрыть доступ к счету
+// @Description Закрывает пользователю доступ к счету. Владелец закрывает доступ любому пользователю,
+// @Description пользователь с доступом может отказаться от него сам. Транзакции пользователя по счету сохраняются
+// @Tags accounts
+// @Param id path int true "ID счета"
+// @Param user_id path int true "ID пользователя"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/shares/{user_id} [delete]
+func (h *Handler) DeleteAccountShare(c *gin.Context) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+	shareUserID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteAccountShare(account.ID, shareUserID, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "share not found"})
+		return
+	}
+	// Пользователи больше не связаны счетом, поэтому CacheMiddleware не сбросит кэш другой стороны
+	h.invalidateCache(c.Request.Context(), account.UserID, shareUserID)
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Security ApiKeyAuth
+// @Summary Транзакции счета
+// @Description Возвращает транзакции по счету всех пользователей с доступом к нему, начиная с последних.
+// @Description Доступно владельцу и пользователям, которым открыт счет
+// @Tags accounts
+// @Produce json
+// @Param id path int true "ID счета"
+// @Param page query int false "Номер страницы (по умолчанию 1)"
+// @Param limit query int false "Количество транзакций на странице (по умолчанию 10, не больше 100)"
+// @Success 200 {object} models.GetTransactionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /accounts/{id}/transactions [get]
+func (h *Handler) GetAccountTransactions(c *gin.Context) {
+	account, ok := h.loadAccount(c)
+	if !ok {
+		return
+	}
+	page, limit, err := parsePageLimit(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transactions, total, err := h.storage.GetAccountTransactions(account.ID, c.GetInt("user_id"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetTransactionsResponse{Transactions: transactions, Total: total})
+}
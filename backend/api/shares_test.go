@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestSharedAccounts тестирует доступ к счету других пользователей с ролями viewer и editor.
+func TestSharedAccounts(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	tokens := make(map[string]string)
+	users := make(map[string]*models.User)
+	for _, name := range []string{"owner", "editor", "viewer"} {
+		user, err := storage.CreateUser(name, "password123")
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		users[name] = user
+		tokens[name] = getToken(t, r, name, "password123")
+	}
+	category, err := storage.CreateCategory(users["editor"].ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	wallet, err := storage.CreateAccount(users["owner"].ID, models.CreateAccount{Name: "Общий", Currency: "RUB", InitialBalance: models.NewMoney(1000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+
+	send := func(user, method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+tokens[user])
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	path := fmt.Sprintf("/accounts/%d", wallet.ID)
+
+	if w := send("editor", "GET", path, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d before sharing, got %d", http.StatusNotFound, w.Code)
+	}
+	for _, invalid := range []models.ShareAccount{{Username: "editor", Role: "admin"}, {Username: "nobody", Role: "viewer"}, {Username: "owner", Role: "viewer"}} {
+		if w := send("owner", "PUT", path+"/shares", invalid); w.Code != http.StatusBadRequest && w.Code != http.StatusNotFound {
+			t.Errorf("Expected error for %+v, got %d", invalid, w.Code)
+		}
+	}
+	for name, role := range map[string]string{"editor": "editor", "viewer": "viewer"} {
+		if w := send("owner", "PUT", path+"/shares", models.ShareAccount{Username: name, Role: role}); w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	}
+	if w := send("editor", "GET", path+"/shares", nil); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d for non-owner, got %d", http.StatusForbidden, w.Code)
+	}
+
+	w := send("viewer", "GET", "/accounts", nil)
+	var accounts []models.Account
+	json.NewDecoder(w.Body).Decode(&accounts)
+	if len(accounts) != 1 || accounts[0].ID != wallet.ID || accounts[0].Role != "viewer" {
+		t.Errorf("Unexpected viewer accounts: %+v", accounts)
+	}
+
+	expense := models.CreateTransactionRequest{Amount: models.NewMoney(300, 0), Type: "expense", CategoryID: category.ID, AccountID: wallet.ID}
+	if w := send("editor", "POST", "/transactions", expense); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if w := send("viewer", "POST", "/transactions", expense); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for viewer transaction, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := send("editor", "PUT", path, models.UpdateAccount{Name: "Мой"}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d for editor update, got %d", http.StatusForbidden, w.Code)
+	}
+
+	w = send("owner", "GET", path+"/transactions", nil)
+	var response models.GetTransactionsResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	if response.Total != 1 || response.Transactions[0].UserID != users["editor"].ID {
+		t.Errorf("Unexpected account transactions: %+v", response)
+	}
+	w = send("viewer", "GET", path, nil)
+	var account models.Account
+	json.NewDecoder(w.Body).Decode(&account)
+	if account.Balance != models.NewMoney(700, 0) {
+		t.Errorf("Expected shared balance 700, got %s", account.Balance)
+	}
+
+	w = send("owner", "GET", path+"/shares", nil)
+	var shares []models.AccountShare
+	json.NewDecoder(w.Body).Decode(&shares)
+	if len(shares) != 2 || shares[0].Username != "editor" || shares[0].Role != "editor" {
+		t.Errorf("Unexpected shares: %+v", shares)
+	}
+
+	// Владелец закрывает доступ, пользователь отказывается от доступа сам
+	if w := send("owner", "DELETE", fmt.Sprintf("%s/shares/%d", path, users["viewer"].ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("viewer", "GET", path, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d after revoke, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := send("editor", "DELETE", fmt.Sprintf("%s/shares/%d", path, users["editor"].ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("editor", "POST", "/transactions", expense); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d after leaving, got %d", http.StatusBadRequest, w.Code)
+	}
+}
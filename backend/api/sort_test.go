@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestTransactionSorting тестирует сортировку списка транзакций по нескольким ключам.
+func TestTransactionSorting(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	food, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	auto, err := storage.CreateCategory(user.ID, "auto")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(30, 0), Type: "expense", CategoryID: food.ID, Date: base},
+		{UserID: user.ID, Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: auto.ID, Date: base.AddDate(0, 0, 1)},
+		{UserID: user.ID, Amount: models.NewMoney(20, 0), Type: "expense", CategoryID: food.ID, Date: base.AddDate(0, 0, 2)},
+		{UserID: user.ID, Amount: models.NewMoney(50, 0), Type: "expense", CategoryID: auto.ID, Date: base.AddDate(0, 0, 3)},
+	}
+	if err := storage.CreateTransactions(transactions); err != nil {
+		t.Fatalf("Failed to create transactions: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	get := func(query string) (int, []float64) {
+		req, _ := http.NewRequest("GET", "/transactions?"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.GetTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		var amounts []float64
+		for _, transaction := range response.Transactions {
+			amounts = append(amounts, transaction.Amount.Float64())
+		}
+		return w.Code, amounts
+	}
+
+	tests := []struct {
+		query    string
+		expected []float64
+	}{
+		{"sort_by=amount", []float64{10, 20, 30, 50}},
+		{"sort_by=amount&order=desc", []float64{50, 30, 20, 10}},
+		{"sort_by=date&order=desc", []float64{50, 20, 10, 30}},
+		// Сначала по названию категории (auto, food), внутри — по убыванию суммы
+		{"sort_by=category,amount&order=asc,desc", []float64{50, 10, 30, 20}},
+	}
+	for _, tt := range tests {
+		code, amounts := get(tt.query)
+		if code != http.StatusOK {
+			t.Errorf("%s: expected status %d, got %d", tt.query, http.StatusOK, code)
+			continue
+		}
+		if len(amounts) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.expected, amounts)
+			continue
+		}
+		for i := range amounts {
+			if amounts[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.query, tt.expected, amounts)
+				break
+			}
+		}
+	}
+
+	// Неизвестные ключи и некорректные сочетания параметров отклоняются
+	for _, query := range []string{
+		"sort_by=user_id",
+		"sort_by=amount;DROP%20TABLE%20users",
+		"sort_by=amount&order=up",
+		"sort_by=amount,date&order=asc,desc,asc",
+		"sort_by=amount,amount",
+		"sort_by=amount&sort=asc",
+		"order=desc",
+		"sort_by=amount&after=",
+	} {
+		if code, _ := get(query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, code)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// validateStatus проверяет статус сверки транзакции.
+func validateStatus(status string) error {
+	switch status {
+	case "pending", "cleared", "reconciled":
+		return nil
+	}
+	return fmt.Errorf("status must be 'pending', 'cleared' or 'reconciled'")
+}
+
+// @Security ApiKeyAuth
+// @Summary Отметить транзакции проведенными
+// @Description Переводит ожидающие (pending) транзакции в статус cleared: по списку ID и/или все с датой не позже date_to.
+// @Description Транзакции в других статусах не изменяются. Возвращает число обновленных транзакций.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param request body models.MarkClearedRequest true "Отмечаемые транзакции"
+// @Success 200 {object} models.MarkClearedResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transactions/mark-cleared [post]
+func (h *Handler) MarkTransactionsCleared(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.MarkClearedRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if len(req.IDs) == 0 && req.DateTo == nil {
+		badRequest(c, fieldError("ids", "ids or date_to is required"))
+		return
+	}
+
+	filter := db.TransactionFilter{IDs: req.IDs}
+	if req.DateTo != nil {
+		filter.DateTo = *req.DateTo
+	}
+
+	updated, err := h.storage.MarkTransactionsCleared(userID.(int), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MarkClearedResponse{Updated: updated})
+}
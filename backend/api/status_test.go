@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestTransactionStatus тестирует статус сверки, фильтр по нему и пакетную отметку проведенными.
+func TestTransactionStatus(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID, Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Status: "pending"},
+		{UserID: user.ID, Amount: models.NewMoney(20, 0), Type: "expense", CategoryID: category.ID, Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Status: "pending"},
+		{UserID: user.ID, Amount: models.NewMoney(30, 0), Type: "expense", CategoryID: category.ID, Date: time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC), Status: "pending"},
+		{UserID: user.ID, Amount: models.NewMoney(40, 0), Type: "expense", CategoryID: category.ID, Date: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Status: "reconciled"},
+		{UserID: user.ID, Amount: models.NewMoney(50, 0), Type: "expense", CategoryID: category.ID, Date: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, transaction := range transactions {
+		if err := storage.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	if transactions[4].Status != "cleared" {
+		t.Errorf("Expected default status cleared, got %q", transactions[4].Status)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	countByStatus := func(status string) int {
+		req, _ := http.NewRequest("GET", "/transactions?status="+status, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response models.GetTransactionsResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return response.Total
+	}
+	if n := countByStatus("pending"); n != 3 {
+		t.Errorf("Expected 3 pending transactions, got %d", n)
+	}
+
+	markCleared := func(body string) (int, models.MarkClearedResponse) {
+		req, _ := http.NewRequest("POST", "/transactions/mark-cleared", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response models.MarkClearedResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		return w.Code, response
+	}
+
+	// По дате выписки отмечаются только ожидающие транзакции до нее; сверенная не меняется
+	code, response := markCleared(`{"date_to": "2025-01-31T23:59:59Z"}`)
+	if code != http.StatusOK || response.Updated != 2 {
+		t.Errorf("Expected 2 updated transactions, got status %d and %+v", code, response)
+	}
+	if n := countByStatus("reconciled"); n != 1 {
+		t.Errorf("Expected 1 reconciled transaction, got %d", n)
+	}
+
+	code, response = markCleared(fmt.Sprintf(`{"ids": [%d]}`, transactions[2].ID))
+	if code != http.StatusOK || response.Updated != 1 {
+		t.Errorf("Expected 1 updated transaction, got status %d and %+v", code, response)
+	}
+	if n := countByStatus("pending"); n != 0 {
+		t.Errorf("Expected no pending transactions, got %d", n)
+	}
+
+	for _, body := range []string{`{}`, `{"ids": [0]}`} {
+		if code, _ := markCleared(body); code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, body, code)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/transactions?status=unknown", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid status, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/report"
+	"github.com/nemopss/fin-ng/backend/subscription"
+)
+
+// detectSubscriptions находит подписки пользователя по расходам за последние subscription.HistoryMonths месяцев.
+func (h *Handler) detectSubscriptions(userID int, today time.Time) ([]models.Subscription, error) {
+	transactions, err := h.storage.GetPayeeExpenses(userID, today.AddDate(0, -subscription.HistoryMonths, 0))
+	if err != nil {
+		return nil, err
+	}
+	return subscription.Detect(transactions, today), nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Подписки
+// @Description Находит в расходах за последние 25 месяцев подписки — списания одной суммы у одного контрагента
+// @Description раз в неделю, месяц, квартал или год — и возвращает их стоимость в месяц и дату следующего списания.
+// @Description Подписки, очередное списание которых давно просрочено, считаются отмененными и не возвращаются
+// @Tags reports
+// @Produce json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета"
+// @Success 200 {object} models.Subscriptions
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Router /reports/subscriptions [get]
+func (h *Handler) GetSubscriptions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	user, err := h.storage.GetUserByID(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	subscriptions, err := h.detectSubscriptions(user.ID, time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	costs := make([]models.TransactionTotals, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		costs = append(costs, models.TransactionTotals{Currency: s.Currency, Expense: s.MonthlyCost})
+	}
+	total, err := h.convertTotals(c.Request.Context(), costs, user.BaseCurrency)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to convert totals: " + err.Error()})
+		return
+	}
+
+	result := models.Subscriptions{Currency: user.BaseCurrency, MonthlyTotal: total.Expense, Subscriptions: subscriptions}
+	writeReport(c, "subscriptions", result, func() report.Workbook { return subscriptionsWorkbook(result) })
+}
+
+// @Security ApiKeyAuth
+// @Summary Запланировать списание подписки
+// @Description Создает запланированную транзакцию на дату следующего списания найденной подписки — копию
+// @Description последнего списания с той же суммой, категорией, счетом, контрагентом и тегами
+// @Tags reports
+// @Produce json
+// @Param id path int true "ID транзакции последнего списания подписки (last_transaction_id)"
+// @Success 201 {object} models.Transaction
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /reports/subscriptions/{id}/schedule [post]
+func (h *Handler) ScheduleSubscription(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	now := time.Now().UTC()
+	subscriptions, err := h.detectSubscriptions(userID.(int), now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var found *models.Subscription
+	for i := range subscriptions {
+		if subscriptions[i].LastTransactionID == id {
+			found = &subscriptions[i]
+		}
+	}
+	if found == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		return
+	}
+	next, _ := time.Parse("2006-01-02", found.NextCharge)
+	// Запланированная транзакция с прошедшей датой сразу стала бы обычной
+	if !next.After(now) {
+		c.JSON(http.StatusConflict, gin.H{"error": "next charge is already due"})
+		return
+	}
+
+	last, err := h.storage.GetTransaction(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if last == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		return
+	}
+
+	planned := *last
+	planned.ID = 0
+	planned.Date = next
+	planned.Planned = true
+	planned.Status = ""
+	planned.LinkedTransactionID = 0
+
+	if err := h.storage.CreateTransaction(&planned); err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, planned)
+}
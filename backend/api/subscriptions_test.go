@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestSubscriptions тестирует поиск подписок и планирование следующего списания.
+func TestSubscriptions(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	payee, err := storage.CreatePayee(user.ID, "Кинопоиск")
+	if err != nil {
+		t.Fatalf("Failed to create payee: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "Развлечения")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var last *models.Transaction
+	for _, daysAgo := range []int{61, 31, 1} {
+		last = &models.Transaction{UserID: user.ID, Type: "expense", Amount: models.NewMoney(299, 0), Currency: "RUB",
+			CategoryID: category.ID, PayeeID: payee.ID, Date: today.AddDate(0, 0, -daysAgo), Tags: []string{"подписки"}}
+		if err := storage.CreateTransaction(last); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("GET", "/reports/subscriptions")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var result models.Subscriptions
+	json.NewDecoder(w.Body).Decode(&result)
+	if result.MonthlyTotal != models.NewMoney(299, 0) || len(result.Subscriptions) != 1 {
+		t.Fatalf("Unexpected subscriptions: %+v", result)
+	}
+	subscription := result.Subscriptions[0]
+	if subscription.PayeeID != payee.ID || subscription.Frequency != "monthly" || subscription.Charges != 3 || subscription.LastTransactionID != last.ID {
+		t.Errorf("Unexpected subscription: %+v", subscription)
+	}
+
+	w = send("POST", fmt.Sprintf("/reports/subscriptions/%d/schedule", last.ID))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var planned models.Transaction
+	json.NewDecoder(w.Body).Decode(&planned)
+	if !planned.Planned || planned.Date.Format("2006-01-02") != subscription.NextCharge || planned.Amount != models.NewMoney(299, 0) ||
+		planned.PayeeID != payee.ID || planned.CategoryID != category.ID || len(planned.Tags) != 1 {
+		t.Errorf("Unexpected planned transaction: %+v", planned)
+	}
+
+	// Запланированное списание не меняет найденные подписки
+	w = send("GET", "/reports/subscriptions")
+	result = models.Subscriptions{}
+	json.NewDecoder(w.Body).Decode(&result)
+	if len(result.Subscriptions) != 1 || result.Subscriptions[0].Charges != 3 {
+		t.Errorf("Unexpected subscriptions after scheduling: %+v", result)
+	}
+
+	if w := send("POST", fmt.Sprintf("/reports/subscriptions/%d/schedule", last.ID-1)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for earlier charge, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := send("POST", "/reports/subscriptions/abc/schedule"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
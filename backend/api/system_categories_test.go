@@ -0,0 +1,117 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestSystemCategories тестирует общие категории: управление администратором
+// и доступ пользователей только для чтения.
+func TestSystemCategories(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	if _, err := storage.CreateUser("admin", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := storage.PromoteAdmins([]string{"admin"}); err != nil {
+		t.Fatalf("Failed to promote admin: %v", err)
+	}
+	user, err := storage.CreateUser("regular", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	own, err := storage.CreateCategory(user.ID, "Кафе")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	adminToken := getToken(t, r, "admin", "password123")
+	userToken := getToken(t, r, "regular", "password123")
+
+	send := func(token, method, url, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send(userToken, "POST", "/admin/categories", `{"name": "Продукты"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d for regular user, got %d", http.StatusForbidden, w.Code)
+	}
+	w := send(adminToken, "POST", "/admin/categories", `{"name": "Продукты", "icon": "cart"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var system models.Category
+	json.NewDecoder(w.Body).Decode(&system)
+	if !system.System || system.UserID != 0 || system.Icon != "cart" {
+		t.Errorf("Unexpected system category: %+v", system)
+	}
+
+	// Пользователь видит системную категорию рядом со своими
+	w = send(userToken, "GET", "/categories", "")
+	var categories []models.Category
+	json.NewDecoder(w.Body).Decode(&categories)
+	if len(categories) != 2 || categories[0].ID != own.ID || categories[0].System || !categories[1].System {
+		t.Errorf("Expected own and system categories, got %+v", categories)
+	}
+	if w := send(userToken, "GET", fmt.Sprintf("/categories/%d", system.ID), ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	// Изменить или удалить системную категорию пользователь не может
+	for _, req := range []struct{ method, body string }{
+		{"PUT", `{"name": "Еда"}`},
+		{"PATCH", `{"name": "Еда"}`},
+		{"DELETE", ""},
+	} {
+		if w := send(userToken, req.method, fmt.Sprintf("/categories/%d", system.ID), req.body); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusForbidden, req.method, w.Code)
+		}
+	}
+
+	// Транзакцию можно создать в системной категории
+	transaction := models.Transaction{Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: system.ID, Date: time.Now()}
+	body, _ := json.Marshal(transaction)
+	if w := send(userToken, "POST", "/transactions", string(body)); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w = send(adminToken, "PUT", fmt.Sprintf("/admin/categories/%d", system.ID), `{"name": "Еда", "color": "#FF0000"}`)
+	var updated models.Category
+	json.NewDecoder(w.Body).Decode(&updated)
+	if w.Code != http.StatusOK || updated.Name != "Еда" || updated.Icon != "" || updated.Color != "#ff0000" {
+		t.Errorf("Unexpected update: status %d, %+v", w.Code, updated)
+	}
+	if w := send(adminToken, "PUT", fmt.Sprintf("/admin/categories/%d", own.ID), `{"name": "Еда"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for user category, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// Используемую системную категорию удалить нельзя
+	if w := send(adminToken, "DELETE", fmt.Sprintf("/admin/categories/%d", system.ID), ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	unused, err := storage.CreateSystemCategory("Прочее", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create system category: %v", err)
+	}
+	if w := send(adminToken, "DELETE", fmt.Sprintf("/admin/categories/%d", unused.ID), ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+
+	w = send(adminToken, "GET", "/admin/categories", "")
+	categories = nil
+	json.NewDecoder(w.Body).Decode(&categories)
+	if w.Code != http.StatusOK || len(categories) != 1 || categories[0].ID != system.ID {
+		t.Errorf("Expected one system category, got status %d and %+v", w.Code, categories)
+	}
+}
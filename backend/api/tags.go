@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// tagErrorStatus возвращает код ответа для ошибки хранилища тегов.
+func tagErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "tag name") || strings.Contains(err.Error(), "tag already exists") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// @Security ApiKeyAuth
+// @Summary Создать тег
+// @Description Создает новый тег пользователя
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param tag body models.CreateTag true "Данные тега"
+// @Success 201 {object} models.Tag
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /tags [post]
+func (h *Handler) CreateTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.CreateTag
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tag, err := h.storage.CreateTag(userID.(int), req.Name)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить список тегов
+// @Description Получает список тегов пользователя, отсортированный по имени
+// @Tags tags
+// @Produce json
+// @Success 200 {array} models.Tag
+// @Failure 401 {object} models.ErrorResponse
+// @Router /tags [get]
+func (h *Handler) GetTags(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	tags, err := h.storage.GetTags(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить тег
+// @Description Получает тег пользователя по ID
+// @Tags tags
+// @Produce json
+// @Param id path int true "ID тега"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tags/{id} [get]
+func (h *Handler) GetTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		return
+	}
+
+	tag, err := h.storage.GetTag(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if tag == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// @Security ApiKeyAuth
+// @Summary Переименовать тег
+// @Description Переименовывает тег пользователя; новое имя применяется ко всем транзакциям с этим тегом
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID тега"
+// @Param tag body models.CreateTag true "Новое имя тега"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tags/{id} [put]
+func (h *Handler) UpdateTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		return
+	}
+
+	var req models.CreateTag
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	updated, err := h.storage.UpdateTag(id, userID.(int), req.Name)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Tag{ID: id, UserID: userID.(int), Name: strings.TrimSpace(req.Name)})
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить тег
+// @Description Удаляет тег пользователя и снимает его со всех транзакций
+// @Tags tags
+// @Produce json
+// @Param id path int true "ID тега"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tags/{id} [delete]
+func (h *Handler) DeleteTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteTag(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
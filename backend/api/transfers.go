@@ -0,0 +1,170 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// @Security ApiKeyAuth
+// @Summary Перевести между счетами
+// @Description Атомарно создает расход на счете-источнике и доход на счете-получателе. Переводы не учитываются
+// @Description в доходах и расходах. Для счетов в разных валютах нужна сумма зачисления to_amount или курс fx_rate
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param transfer body models.CreateTransfer true "Данные перевода"
+// @Success 201 {object} models.Transfer
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transfers [post]
+func (h *Handler) CreateTransfer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var req models.CreateTransfer
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	transfer := models.Transfer{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		ToAmount:      req.ToAmount,
+		FXRate:        req.FXRate,
+		Date:          req.Date,
+		Description:   req.Description,
+	}
+	if err := h.storage.CreateTransfer(userID.(int), &transfer); err != nil {
+		if strings.Contains(err.Error(), "account") || strings.Contains(err.Error(), "amount") || strings.Contains(err.Error(), "fx_rate") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// @Security ApiKeyAuth
+// @Summary Список переводов
+// @Description Возвращает переводы между счетами как единые операции, начиная с последних
+// @Tags transfers
+// @Produce json
+// @Param account_id query int false "Только переводы с этого счета или на него"
+// @Param page query int false "Номер страницы (по умолчанию 1)"
+// @Param limit query int false "Количество переводов на странице (по умолчанию 10, не больше 100)"
+// @Success 200 {object} models.GetTransfersResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /transfers [get]
+func (h *Handler) GetTransfers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	var accountID int
+	if accountIDStr := c.Query("account_id"); accountIDStr != "" {
+		var err error
+		accountID, err = strconv.Atoi(accountIDStr)
+		if err != nil || accountID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "account_id must be a positive integer"})
+			return
+		}
+	}
+	page, limit, err := parsePageLimit(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfers, total, err := h.storage.GetTransfers(userID.(int), accountID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetTransfersResponse{Transfers: transfers, Total: total})
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить перевод
+// @Tags transfers
+// @Produce json
+// @Param id path int true "ID перевода"
+// @Success 200 {object} models.Transfer
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transfers/{id} [get]
+func (h *Handler) GetTransfer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer id"})
+		return
+	}
+
+	transfer, err := h.storage.GetTransfer(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if transfer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transfer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// @Security ApiKeyAuth
+// @Summary Удалить перевод
+// @Description Перемещает обе транзакции перевода в корзину; восстановление любой из них возвращает перевод целиком
+// @Tags transfers
+// @Param id path int true "ID перевода"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transfers/{id} [delete]
+func (h *Handler) DeleteTransfer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer id"})
+		return
+	}
+
+	deleted, err := h.storage.DeleteTransfer(id, userID.(int))
+	if err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transfer not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestTransfers тестирует переводы между счетами, в том числе между валютами.
+func TestTransfers(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	cash, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Наличные", Currency: "RUB", InitialBalance: models.NewMoney(10000, 0)})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	card, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Карта", Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	dollars, err := storage.CreateAccount(user.ID, models.CreateAccount{Name: "Доллары", Currency: "USD"})
+	if err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	balance := func(id int) models.Money {
+		account, err := storage.GetAccount(id, user.ID)
+		if err != nil || account == nil {
+			t.Fatalf("Failed to get account %d: %v", id, err)
+		}
+		return account.Balance
+	}
+
+	w := send("POST", "/transfers", models.CreateTransfer{FromAccountID: cash.ID, ToAccountID: card.ID, Amount: models.NewMoney(3000, 0), Description: "На карту"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var transfer models.Transfer
+	json.NewDecoder(w.Body).Decode(&transfer)
+	if transfer.ID == 0 || transfer.ToAmount != models.NewMoney(3000, 0) || transfer.Currency != "RUB" || transfer.FXRate != 0 ||
+		transfer.FromTransactionID == 0 || transfer.ToTransactionID == 0 {
+		t.Errorf("Unexpected transfer: %+v", transfer)
+	}
+	if balance(cash.ID) != models.NewMoney(7000, 0) || balance(card.ID) != models.NewMoney(3000, 0) {
+		t.Errorf("Unexpected balances after transfer: %s, %s", balance(cash.ID), balance(card.ID))
+	}
+
+	// Курс вычисляется по суммам, сумма зачисления — по курсу
+	w = send("POST", "/transfers", models.CreateTransfer{FromAccountID: card.ID, ToAccountID: dollars.ID, Amount: models.NewMoney(1000, 0), ToAmount: models.NewMoney(12, 50)})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	json.NewDecoder(w.Body).Decode(&transfer)
+	if transfer.ToCurrency != "USD" || transfer.FXRate != 0.0125 {
+		t.Errorf("Unexpected transfer: %+v", transfer)
+	}
+	w = send("POST", "/transfers", models.CreateTransfer{FromAccountID: dollars.ID, ToAccountID: cash.ID, Amount: models.NewMoney(2, 50), FXRate: 80})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var back models.Transfer
+	json.NewDecoder(w.Body).Decode(&back)
+	if back.ToAmount != models.NewMoney(200, 0) {
+		t.Errorf("Expected to_amount 200, got %s", back.ToAmount)
+	}
+	if balance(dollars.ID) != models.NewMoney(10, 0) || balance(cash.ID) != models.NewMoney(7200, 0) {
+		t.Errorf("Unexpected balances after exchange: %s, %s", balance(dollars.ID), balance(cash.ID))
+	}
+
+	for _, bad := range []models.CreateTransfer{
+		{FromAccountID: cash.ID, ToAccountID: cash.ID, Amount: models.NewMoney(1, 0)},
+		{FromAccountID: cash.ID, ToAccountID: dollars.ID, Amount: models.NewMoney(1, 0)},
+		{FromAccountID: cash.ID, ToAccountID: card.ID, Amount: models.NewMoney(1, 0), ToAmount: models.NewMoney(2, 0)},
+		{FromAccountID: cash.ID, ToAccountID: dollars.ID, Amount: models.NewMoney(100, 0), ToAmount: models.NewMoney(5, 0), FXRate: 0.01},
+		{FromAccountID: cash.ID, ToAccountID: 999999, Amount: models.NewMoney(1, 0)},
+		{FromAccountID: cash.ID, ToAccountID: card.ID},
+	} {
+		if w := send("POST", "/transfers", bad); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %+v, got %d: %s", http.StatusBadRequest, bad, w.Code, w.Body.String())
+		}
+	}
+
+	// Переводы не учитываются в доходах и расходах
+	w = send("GET", "/transactions", nil)
+	var list models.GetTransactionsResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	if list.Total != 6 {
+		t.Errorf("Expected 6 transfer transactions, got %d", list.Total)
+	}
+	totals, err := storage.GetTransactionTotals(user.ID, db.TransactionFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get totals: %v", err)
+	}
+	for _, total := range totals {
+		if total.Income != 0 || total.Expense != 0 {
+			t.Errorf("Expected zero totals, got %+v", total)
+		}
+	}
+
+	// Транзакции перевода не редактируются по отдельности
+	if w := send("PATCH", fmt.Sprintf("/transactions/%d", back.FromTransactionID), map[string]interface{}{"description": "x"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+
+	w = send("GET", fmt.Sprintf("/transfers?account_id=%d", dollars.ID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var transfers models.GetTransfersResponse
+	json.NewDecoder(w.Body).Decode(&transfers)
+	if transfers.Total != 2 || len(transfers.Transfers) != 2 || transfers.Transfers[0].ID != back.ID {
+		t.Errorf("Unexpected transfers: %+v", transfers)
+	}
+
+	// Удаление одной транзакции перевода удаляет и парную, восстановление возвращает обе
+	if w := send("DELETE", fmt.Sprintf("/transactions/%d", back.ToTransactionID), nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if balance(dollars.ID) != models.NewMoney(12, 50) || balance(cash.ID) != models.NewMoney(7000, 0) {
+		t.Errorf("Unexpected balances after delete: %s, %s", balance(dollars.ID), balance(cash.ID))
+	}
+	if w := send("GET", fmt.Sprintf("/transfers/%d", back.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := send("POST", fmt.Sprintf("/transactions/%d/restore", back.FromTransactionID), nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w := send("GET", fmt.Sprintf("/transfers/%d", back.ID), nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w := send("DELETE", fmt.Sprintf("/transfers/%d", back.ID), nil); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("/transfers/%d", back.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
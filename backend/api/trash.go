@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// purgeTrash окончательно удаляет транзакции, пролежавшие в корзине дольше TrashRetention.
+func (h *Handler) purgeTrash(context.Context) error {
+	purged, err := h.storage.PurgeDeletedTransactions(h.cfg.TrashRetention)
+	if err != nil {
+		return fmt.Errorf("failed to purge trash: %w", err)
+	}
+	if purged > 0 {
+		log.Printf("purged %d transactions from trash", purged)
+	}
+	return nil
+}
+
+// @Security ApiKeyAuth
+// @Summary Получить корзину
+// @Description Получает удаленные транзакции пользователя. Транзакции удаляются из корзины окончательно по истечении срока хранения.
+// @Tags trash
+// @Produce json
+// @Param page query int false "Номер страницы"
+// @Param limit query int false "Лимит на страницу"
+// @Success 200 {object} models.GetTransactionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /trash [get]
+func (h *Handler) GetTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	page, limit, err := parsePageLimit(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transactions, total, err := h.storage.GetTransactions(userID.(int), db.TransactionFilter{Deleted: true, IncludePlanned: true, Sort: "desc"}, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetTransactionsResponse{Transactions: transactions, Total: total})
+}
+
+// @Security ApiKeyAuth
+// @Summary Очистить корзину
+// @Description Окончательно удаляет все транзакции пользователя из корзины
+// @Tags trash
+// @Produce json
+// @Success 200 {object} models.DeleteTransactionsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /trash [delete]
+func (h *Handler) EmptyTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	deleted, err := h.storage.EmptyTrash(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DeleteTransactionsResponse{Deleted: deleted})
+}
+
+// @Security ApiKeyAuth
+// @Summary Восстановить транзакцию
+// @Description Возвращает транзакцию из корзины
+// @Tags trash
+// @Produce json
+// @Param id path int true "ID транзакции"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /transactions/{id}/restore [post]
+func (h *Handler) RestoreTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+
+	restored, err := h.storage.RestoreTransaction(id, userID.(int))
+	if err != nil {
+		c.JSON(linkErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !restored {
+		c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found in trash"})
+		return
+	}
+
+	transaction, err := h.storage.GetTransaction(id, userID.(int))
+	if err != nil || transaction == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load transaction"})
+		return
+	}
+	c.JSON(http.StatusOK, transaction)
+}
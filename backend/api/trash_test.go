@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestTrash тестирует перемещение транзакций в корзину, восстановление и очистку.
+func TestTrash(t *testing.T) {
+	r, storage := setupTestHandler(t)
+	defer storage.Close()
+
+	user, err := storage.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := storage.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID},
+		{UserID: user.ID, Amount: models.NewMoney(20, 0), Type: "expense", CategoryID: category.ID},
+	}
+	if err := storage.CreateTransactions(transactions); err != nil {
+		t.Fatalf("Failed to create transactions: %v", err)
+	}
+	token := getToken(t, r, "testuser", "password123")
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	list := func(path string) models.GetTransactionsResponse {
+		w := send("GET", path)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var response models.GetTransactionsResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	// Удаленная транзакция пропадает из списка и появляется в корзине
+	for _, transaction := range transactions {
+		if w := send("DELETE", fmt.Sprintf("/transactions/%d", transaction.ID)); w.Code != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+		}
+	}
+	if response := list("/transactions"); response.Total != 0 {
+		t.Errorf("Expected no active transactions, got %d", response.Total)
+	}
+	trash := list("/trash")
+	if trash.Total != 2 || trash.Transactions[0].DeletedAt == nil {
+		t.Fatalf("Expected 2 transactions in trash with deleted_at, got %+v", trash)
+	}
+
+	// Транзакция из корзины недоступна по ID и не удаляется повторно
+	if w := send("GET", fmt.Sprintf("/transactions/%d", transactions[0].ID)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("/transactions/%d", transactions[0].ID)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// Восстановление возвращает транзакцию в список
+	w := send("POST", fmt.Sprintf("/transactions/%d/restore", transactions[0].ID))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var restored models.Transaction
+	json.NewDecoder(w.Body).Decode(&restored)
+	if restored.ID != transactions[0].ID || restored.DeletedAt != nil {
+		t.Errorf("Expected restored transaction %d without deleted_at, got %+v", transactions[0].ID, restored)
+	}
+	if w := send("POST", fmt.Sprintf("/transactions/%d/restore", transactions[0].ID)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if response := list("/transactions"); response.Total != 1 {
+		t.Errorf("Expected 1 active transaction, got %d", response.Total)
+	}
+
+	// Очистка корзины удаляет оставшуюся транзакцию окончательно
+	w = send("DELETE", "/trash")
+	var emptied models.DeleteTransactionsResponse
+	json.NewDecoder(w.Body).Decode(&emptied)
+	if w.Code != http.StatusOK || emptied.Deleted != 1 {
+		t.Errorf("Expected 1 purged transaction, got status %d and %d", w.Code, emptied.Deleted)
+	}
+	if trash := list("/trash"); trash.Total != 0 {
+		t.Errorf("Expected empty trash, got %d", trash.Total)
+	}
+
+	// Очистка по сроку хранения затрагивает только давно удаленные транзакции
+	if _, err := storage.DeleteTransaction(transactions[0].ID, user.ID); err != nil {
+		t.Fatalf("Failed to delete transaction: %v", err)
+	}
+	purged, err := storage.PurgeDeletedTransactions(time.Hour)
+	if err != nil || purged != 0 {
+		t.Errorf("Expected nothing to purge, got %d (%v)", purged, err)
+	}
+	purged, err = storage.PurgeDeletedTransactions(-time.Hour)
+	if err != nil || purged != 1 {
+		t.Errorf("Expected 1 purged transaction, got %d (%v)", purged, err)
+	}
+}
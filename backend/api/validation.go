@@ -0,0 +1,234 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/nemopss/fin-ng/backend/db"
+	"github.com/nemopss/fin-ng/backend/i18n"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// Тела запросов проверяются по тегам binding моделей запросов при их чтении. Здесь регистрируются
+// собственные проверки, которые используются в тегах, и правила, связывающие несколько полей.
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	// В ошибках поля называются так же, как в JSON
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("maxamount", func(fl validator.FieldLevel) bool {
+		return models.Money(fl.Field().Int()) <= db.MaxAmount
+	})
+	v.RegisterValidation("absamount", func(fl validator.FieldLevel) bool {
+		amount := models.Money(fl.Field().Int())
+		return amount <= db.MaxAmount && amount >= -db.MaxAmount
+	})
+	v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return currencyPattern.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+	v.RegisterValidation("language", func(fl validator.FieldLevel) bool {
+		return i18n.Supported(fl.Field().String())
+	})
+	v.RegisterStructValidation(transactionRules, models.CreateTransactionRequest{})
+}
+
+// transactionRules проверяет правила транзакции, связывающие несколько полей.
+func transactionRules(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.CreateTransactionRequest)
+	if req.LinkedTransactionID > 0 && req.Type != "income" {
+		sl.ReportError(req.LinkedTransactionID, "linked_transaction_id", "LinkedTransactionID", "refund", "")
+	}
+	if req.Planned && !req.Date.After(time.Now()) {
+		sl.ReportError(req.Date, "date", "Date", "future", "")
+	}
+	if req.OriginalAmount == 0 && req.OriginalCurrency == "" {
+		if req.FXRate != 0 {
+			sl.ReportError(req.FXRate, "fx_rate", "FXRate", "fxrate", "")
+		}
+		return
+	}
+	// Сумма сверяется с курсом, только если все три значения корректны: иначе ошибки уже в их полях
+	if req.Amount > 0 && req.OriginalAmount > 0 && req.FXRate > 0 && req.FXRate < maxFXRate {
+		expected := models.MoneyFromFloat(req.OriginalAmount.Float64() * req.FXRate)
+		if diff := expected - req.Amount; diff > 1 || diff < -1 {
+			sl.ReportError(req.Amount, "amount", "Amount", "fxamount", expected.String())
+		}
+	}
+}
+
+// fieldName возвращает путь к полю в теле запроса, например items[0].category_id.
+func fieldName(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// fieldMessage возвращает текст ошибки поля.
+func fieldMessage(fe validator.FieldError) string {
+	field, param := fieldName(fe), fe.Param()
+	switch fe.Tag() {
+	case "required", "required_with":
+		return field + " is required"
+	case "notblank":
+		return field + " must not be blank"
+	case "gt":
+		if param == "0" {
+			return field + " must be positive"
+		}
+		return fmt.Sprintf("%s must be greater than %s", field, param)
+	case "gte":
+		if param == "0" {
+			return field + " must not be negative"
+		}
+		return fmt.Sprintf("%s must be at least %s", field, param)
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", field, param)
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s", field, param)
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters", field, bound, param)
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("%s must contain %s %s items", field, bound, param)
+		}
+		return fmt.Sprintf("%s must be %s %s", field, bound, param)
+	case "oneof":
+		values := strings.Fields(param)
+		for i := range values {
+			values[i] = "'" + values[i] + "'"
+		}
+		if len(values) == 1 {
+			return fmt.Sprintf("%s must be %s", field, values[0])
+		}
+		return fmt.Sprintf("%s must be %s or %s", field, strings.Join(values[:len(values)-1], ", "), values[len(values)-1])
+	case "email":
+		return field + " must be a valid email address"
+	case "currency":
+		return field + " must be a 3-letter ISO 4217 code"
+	case "language":
+		return field + " must be a supported language"
+	case "maxamount":
+		return fmt.Sprintf("%s must be at most %s", field, db.MaxAmount)
+	case "absamount":
+		return fmt.Sprintf("%s must be at most %s in absolute value", field, db.MaxAmount)
+	case "nefield":
+		return fmt.Sprintf("%s must differ from %s", field, strings.ToLower(param))
+	case "refund":
+		return "only income can be linked to an expense as a refund"
+	case "future":
+		return "planned transaction must have a future date"
+	case "fxrate":
+		return "fx_rate requires original_amount and original_currency"
+	case "fxamount":
+		return fmt.Sprintf("%s %v does not match original_amount * fx_rate = %s", field, fe.Value(), param)
+	}
+	return field + " is invalid"
+}
+
+// validationError — ошибки полей, найденные при проверке. Текст ошибки перечисляет их все.
+type validationError []models.FieldError
+
+func (e validationError) Error() string {
+	messages := make([]string, len(e))
+	for i, field := range e {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// fieldError возвращает ошибку поля field для проверок, которые нельзя задать тегами binding:
+// на нее badRequest отвечает так же, как на ошибки тегов.
+func fieldError(field, format string, args ...any) error {
+	return validationError{{Field: field, Message: fmt.Sprintf(format, args...)}}
+}
+
+// asValidationError преобразует ошибки валидатора в validationError; другие ошибки возвращаются как есть.
+func asValidationError(err error) error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	fields := make(validationError, len(errs))
+	for i, fe := range errs {
+		fields[i] = models.FieldError{Field: fieldName(fe), Message: fieldMessage(fe)}
+	}
+	return fields
+}
+
+// validateTransaction проверяет транзакцию по тем же правилам, что и тело запроса на ее создание.
+func validateTransaction(t models.Transaction) error {
+	return asValidationError(binding.Validator.ValidateStruct(models.CreateTransactionRequest{
+		Amount:              t.Amount,
+		Type:                t.Type,
+		CategoryID:          t.CategoryID,
+		Description:         t.Description,
+		Tags:                t.Tags,
+		Currency:            t.Currency,
+		Date:                t.Date,
+		Planned:             t.Planned,
+		Status:              t.Status,
+		PayeeID:             t.PayeeID,
+		Payee:               t.Payee,
+		LinkedTransactionID: t.LinkedTransactionID,
+		OriginalAmount:      t.OriginalAmount,
+		OriginalCurrency:    t.OriginalCurrency,
+		FXRate:              t.FXRate,
+		AccountID:           t.AccountID,
+	}))
+}
+
+// badRequest отвечает 400: с ошибками всех полей, если запрос не прошел проверку, иначе с текстом ошибки.
+func badRequest(c *gin.Context, err error) {
+	var fields validationError
+	if errors.As(asValidationError(err), &fields) {
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{Error: "validation failed", Fields: fields})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// bindJSON читает тело запроса в req и проверяет его по тегам binding; при ошибке отвечает 400.
+func bindJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return false
+	}
+	return true
+}
+
+// bindOptionalJSON — bindJSON для необязательного тела: пустое тело оставляет req без изменений.
+func bindOptionalJSON(c *gin.Context, req any) bool {
+	if c.Request.Body == nil || c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		badRequest(c, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// decodeFieldErrors читает ответ с ошибками полей и возвращает их тексты по именам полей.
+func decodeFieldErrors(t *testing.T, w *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	var response models.ValidationErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Error != "validation failed" {
+		t.Errorf("Expected error 'validation failed', got %q", response.Error)
+	}
+	fields := map[string]string{}
+	for _, field := range response.Fields {
+		fields[field.Field] = field.Message
+	}
+	return fields
+}
+
+// TestBindJSON тестирует ответ со всеми ошибками полей запроса.
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.POST("/transactions", func(c *gin.Context) {
+		var request models.CreateTransactionRequest
+		if bindJSON(c, &request) {
+			c.JSON(http.StatusCreated, request.Transaction())
+		}
+	})
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/transactions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"amount": -5, "type": "other", "currency": "usd", "status": "done", "tags": ["food", " "], "linked_transaction_id": 3}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	want := map[string]string{
+		"amount":                "amount must be positive",
+		"type":                  "type must be 'income' or 'expense'",
+		"category_id":           "category_id must be positive",
+		"currency":              "currency must be a 3-letter ISO 4217 code",
+		"status":                "status must be 'pending', 'cleared' or 'reconciled'",
+		"tags[1]":               "tags[1] must not be blank",
+		"linked_transaction_id": "only income can be linked to an expense as a refund",
+	}
+	fields := decodeFieldErrors(t, w)
+	if len(fields) != len(want) {
+		t.Errorf("Expected %d field errors, got %v", len(want), fields)
+	}
+	for field, message := range want {
+		if fields[field] != message {
+			t.Errorf("Expected %s error %q, got %q", field, message, fields[field])
+		}
+	}
+
+	// Правила суммы в валюте покупки
+	w = post(`{"amount": 100, "type": "expense", "category_id": 1, "currency": "RUB", "original_currency": "RUB", "fx_rate": 2}`)
+	fields = decodeFieldErrors(t, w)
+	if fields["original_amount"] != "original_amount is required" || fields["original_currency"] != "original_currency must differ from currency" {
+		t.Errorf("Unexpected field errors %v", fields)
+	}
+	w = post(`{"amount": 100, "type": "expense", "category_id": 1, "original_amount": 10, "original_currency": "EUR", "fx_rate": 2}`)
+	if fields = decodeFieldErrors(t, w); fields["amount"] != "amount 100.00 does not match original_amount * fx_rate = 20.00" {
+		t.Errorf("Unexpected field errors %v", fields)
+	}
+
+	// Тело, которое не удалось разобрать, возвращается с текстом ошибки
+	if w = post(`{"amount": "abc"`); w.Code != http.StatusBadRequest || strings.Contains(w.Body.String(), "fields") {
+		t.Errorf("Expected plain error, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = post(`{"amount": 100, "type": "income", "category_id": 1, "linked_transaction_id": 3, "tags": ["food"]}`)
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+}
+
+// TestValidateTransaction тестирует проверку транзакций, созданных не из тела запроса.
+func TestValidateTransaction(t *testing.T) {
+	valid := models.Transaction{Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: 1}
+	if err := validateTransaction(valid); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	planned := valid
+	planned.Planned, planned.Date = true, time.Now().Add(-time.Hour)
+	planned.Amount = 0
+	err := validateTransaction(planned)
+	if err == nil || err.Error() != "amount must be positive; planned transaction must have a future date" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, ok := err.(validationError); !ok {
+		t.Errorf("Expected validationError, got %T", err)
+	}
+}
+
+// TestBindJSONRequests тестирует теги binding моделей запросов и ответ на ошибку fieldError.
+func TestBindJSONRequests(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	tests := []struct {
+		request any
+		body    string
+		want    map[string]string
+	}{
+		{&models.ShareAccount{}, `{"username": " ", "role": "owner"}`, map[string]string{
+			"username": "username must not be blank",
+			"role":     "role must be 'viewer' or 'editor'",
+		}},
+		{&models.CreateAccount{}, `{"name": "Карта", "currency": "rub", "initial_balance": -10000000000000, "type": "deposit"}`, map[string]string{
+			"currency":        "currency must be a 3-letter ISO 4217 code",
+			"initial_balance": "initial_balance must be at most 999999999999.99 in absolute value",
+			"type":            "type must be 'regular', 'credit_card', 'loan', 'investment' or 'crypto'",
+		}},
+		{&models.CreateTransfer{}, `{"from_account_id": 1, "amount": 0, "fx_rate": -1}`, map[string]string{
+			"to_account_id": "to_account_id must be positive",
+			"amount":        "amount must be positive",
+			"fx_rate":       "fx_rate must not be negative",
+		}},
+		{&models.CreateHolding{}, `{"ticker": "SBER", "quantity": 2e12, "cost_basis": -1}`, map[string]string{
+			"quantity":   "quantity must be at most 1e12",
+			"cost_basis": "cost_basis must not be negative",
+		}},
+		{&models.MarkClearedRequest{}, `{"ids": [1, 0]}`, map[string]string{
+			"ids[1]": "ids[1] must be positive",
+		}},
+		{&models.PatchCategory{}, `{"name": ""}`, map[string]string{
+			"name": "name must not be blank",
+		}},
+		{&models.CreateBudgetTemplate{}, `{"name": " ", "items": [{"category_id": 1, "limit": 100}, {"limit": 0, "currency": "rub"}]}`, map[string]string{
+			"name":                 "name must not be blank",
+			"items[1].category_id": "items[1].category_id must be positive",
+			"items[1].limit":       "items[1].limit must be positive",
+			"items[1].currency":    "items[1].currency must be a 3-letter ISO 4217 code",
+		}},
+		{&models.CreateBudgetTemplate{}, `{"name": "Обычный месяц", "items": []}`, map[string]string{
+			"items": "items must contain at least 1 items",
+		}},
+		{&models.ReportQuery{}, `{"metrics": ["count"], "limit": 20000, "filters": {"type": "transfer", "currency": "rub", "category_ids": [0]}}`, map[string]string{
+			"limit":                   "limit must be at most 10000",
+			"filters.type":            "filters.type must be 'income' or 'expense'",
+			"filters.currency":        "filters.currency must be a 3-letter ISO 4217 code",
+			"filters.category_ids[0]": "filters.category_ids[0] must be positive",
+		}},
+		{&models.ResolveDuplicateRequest{}, `{"action": "merge"}`, map[string]string{
+			"action": "action must be 'confirm' or 'dismiss'",
+		}},
+		{&models.ScanReceiptRequest{}, `{"category_id": -1}`, map[string]string{
+			"qr":          "qr is required",
+			"category_id": "category_id must not be negative",
+		}},
+		{&models.LoginRequest{}, `{"username": "john_doe"}`, map[string]string{
+			"password": "password is required",
+		}},
+		{&models.CreateInvite{}, `{"expires_in_hours": -1}`, map[string]string{
+			"expires_in_hours": "expires_in_hours must not be negative",
+		}},
+		{&models.DuplicateTransactionRequest{}, `{"amount": 0}`, map[string]string{
+			"amount": "amount must be positive",
+		}},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		r.POST("/", func(c *gin.Context) {
+			if bindJSON(c, tt.request) {
+				c.Status(http.StatusNoContent)
+			}
+		})
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%T: expected status %d, got %d", tt.request, http.StatusBadRequest, w.Code)
+			continue
+		}
+		fields := decodeFieldErrors(t, w)
+		if len(fields) != len(tt.want) {
+			t.Errorf("%T: expected %d field errors, got %v", tt.request, len(tt.want), fields)
+		}
+		for field, message := range tt.want {
+			if fields[field] != message {
+				t.Errorf("%T: expected %s error %q, got %q", tt.request, field, message, fields[field])
+			}
+		}
+	}
+
+	// Проверки, которые нельзя задать тегами, отвечают так же, как ошибки тегов
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	badRequest(c, validateCreditTerms("credit_card", models.NewMoney(1000, 0), 10, 10))
+	if fields := decodeFieldErrors(t, w); fields["payment_due_day"] != "payment_due_day must differ from statement_day" {
+		t.Errorf("Unexpected field errors %v", fields)
+	}
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	minAmount, maxAmount := models.NewMoney(500, 0), models.NewMoney(100, 0)
+	badRequest(c, validateReportQuery(&models.ReportQuery{Filters: models.ReportQueryFilters{MinAmount: &minAmount, MaxAmount: &maxAmount}}))
+	if fields := decodeFieldErrors(t, w); fields["filters.min_amount"] != "min_amount must not be greater than max_amount" {
+		t.Errorf("Unexpected field errors %v", fields)
+	}
+}
+
+// TestBindOptionalJSON тестирует необязательное тело запроса: пустое тело не считается ошибкой.
+func TestBindOptionalJSON(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.POST("/invites", func(c *gin.Context) {
+		var request models.CreateInvite
+		if bindOptionalJSON(c, &request) {
+			c.JSON(http.StatusOK, request)
+		}
+	})
+	for _, tt := range []struct {
+		body string
+		code int
+	}{
+		{"", http.StatusOK},
+		{`{"expires_in_hours": 72}`, http.StatusOK},
+		{`{"expires_in_hours": -1}`, http.StatusBadRequest},
+		{`{"expires_in_hours":`, http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest("POST", "/invites", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%q: expected status %d, got %d: %s", tt.body, tt.code, w.Code, w.Body.String())
+		}
+	}
+}
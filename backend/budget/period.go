@@ -0,0 +1,87 @@
+// Package budget определяет периоды бюджетов: неделю, месяц, квартал и произвольный диапазон дат.
+package budget
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/period"
+)
+
+const (
+	Week    = period.Week
+	Month   = period.Month
+	Quarter = period.Quarter
+	Custom  = "custom"
+)
+
+// MaxCustomDays — наибольшая длина произвольного периода, 5 лет.
+const MaxCustomDays = 5 * 366
+
+// Period — период бюджета с первого по последний день включительно. Даты — полночь UTC.
+// Недели, месяцы и кварталы бюджетов календарные.
+type Period struct {
+	Kind  string
+	Start time.Time
+	End   time.Time
+}
+
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// NewPeriod возвращает период вида kind, содержащий дату start: неделю с понедельника, календарный месяц
+// или квартал. Последний день end задается только для произвольного периода. Пустой вид означает месяц.
+func NewPeriod(kind string, start, end time.Time) (Period, error) {
+	if kind == "" {
+		kind = Month
+	}
+	if kind != Custom && !end.IsZero() {
+		return Period{}, fmt.Errorf("end_date is only allowed for custom periods")
+	}
+	start = date(start)
+	p := Period{Kind: kind}
+	switch kind {
+	case Week, Month, Quarter:
+		grouping := period.Calendar(kind)
+		p.Start = grouping.Start(start)
+		p.End = grouping.End(p.Start)
+	case Custom:
+		if end.IsZero() {
+			return Period{}, fmt.Errorf("end_date is required for custom periods")
+		}
+		p.Start, p.End = start, date(end)
+		if p.End.Before(p.Start) {
+			return Period{}, fmt.Errorf("end_date must not be before start_date")
+		}
+		if p.Days() > MaxCustomDays {
+			return Period{}, fmt.Errorf("custom period must be at most %d days", MaxCustomDays)
+		}
+	default:
+		return Period{}, fmt.Errorf("period must be week, month, quarter or custom")
+	}
+	return p, nil
+}
+
+// Days возвращает число дней периода.
+func (p Period) Days() int {
+	return int(p.End.Sub(p.Start).Hours()/24) + 1
+}
+
+// Contains сообщает, входит ли день t в период.
+func (p Period) Contains(t time.Time) bool {
+	t = date(t)
+	return !t.Before(p.Start) && !t.After(p.End)
+}
+
+// Elapsed возвращает долю периода, прошедшую к моменту now, от 0 до 1. День now считается прошедшим.
+func (p Period) Elapsed(now time.Time) float64 {
+	now = date(now)
+	switch {
+	case now.Before(p.Start):
+		return 0
+	case now.After(p.End):
+		return 1
+	}
+	return float64(int(now.Sub(p.Start).Hours()/24)+1) / float64(p.Days())
+}
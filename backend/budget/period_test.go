@@ -0,0 +1,76 @@
+package budget
+
+import (
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// TestNewPeriod тестирует границы периодов каждого вида.
+func TestNewPeriod(t *testing.T) {
+	tests := []struct {
+		kind       string
+		start, end time.Time
+		wantStart  time.Time
+		wantEnd    time.Time
+	}{
+		{"", time.Date(2025, 7, 17, 15, 30, 0, 0, time.UTC), time.Time{}, day(2025, 7, 1), day(2025, 7, 31)},
+		{Month, day(2024, 2, 29), time.Time{}, day(2024, 2, 1), day(2024, 2, 29)},
+		// 17 июля 2025 — четверг, неделя начинается в понедельник 14 июля
+		{Week, day(2025, 7, 17), time.Time{}, day(2025, 7, 14), day(2025, 7, 20)},
+		{Week, day(2025, 7, 20), time.Time{}, day(2025, 7, 14), day(2025, 7, 20)},
+		{Week, day(2025, 7, 14), time.Time{}, day(2025, 7, 14), day(2025, 7, 20)},
+		{Quarter, day(2025, 8, 15), time.Time{}, day(2025, 7, 1), day(2025, 9, 30)},
+		{Quarter, day(2025, 12, 31), time.Time{}, day(2025, 10, 1), day(2025, 12, 31)},
+		{Custom, day(2025, 7, 10), day(2025, 7, 24), day(2025, 7, 10), day(2025, 7, 24)},
+		{Custom, day(2025, 7, 10), day(2025, 7, 10), day(2025, 7, 10), day(2025, 7, 10)},
+	}
+	for _, tt := range tests {
+		p, err := NewPeriod(tt.kind, tt.start, tt.end)
+		if err != nil {
+			t.Errorf("NewPeriod(%q, %v): unexpected error %v", tt.kind, tt.start, err)
+			continue
+		}
+		if !p.Start.Equal(tt.wantStart) || !p.End.Equal(tt.wantEnd) {
+			t.Errorf("NewPeriod(%q, %v) = %v..%v, want %v..%v", tt.kind, tt.start, p.Start, p.End, tt.wantStart, tt.wantEnd)
+		}
+	}
+
+	for _, invalid := range []struct {
+		kind       string
+		start, end time.Time
+	}{
+		{"year", day(2025, 1, 1), time.Time{}},
+		{Month, day(2025, 1, 1), day(2025, 1, 31)},
+		{Custom, day(2025, 1, 1), time.Time{}},
+		{Custom, day(2025, 1, 10), day(2025, 1, 9)},
+		{Custom, day(2020, 1, 1), day(2030, 1, 1)},
+	} {
+		if _, err := NewPeriod(invalid.kind, invalid.start, invalid.end); err == nil {
+			t.Errorf("Expected error for %+v", invalid)
+		}
+	}
+}
+
+// TestPeriodElapsed тестирует долю прошедшего периода.
+func TestPeriodElapsed(t *testing.T) {
+	p, _ := NewPeriod(Week, day(2025, 7, 14), time.Time{})
+	if p.Days() != 7 {
+		t.Errorf("Expected 7 days, got %d", p.Days())
+	}
+	if elapsed := p.Elapsed(day(2025, 7, 1)); elapsed != 0 {
+		t.Errorf("Expected 0 before period, got %v", elapsed)
+	}
+	if elapsed := p.Elapsed(time.Date(2025, 7, 14, 23, 0, 0, 0, time.UTC)); elapsed != 1.0/7 {
+		t.Errorf("Expected 1/7 on first day, got %v", elapsed)
+	}
+	if elapsed := p.Elapsed(day(2025, 8, 1)); elapsed != 1 {
+		t.Errorf("Expected 1 after period, got %v", elapsed)
+	}
+	if !p.Contains(time.Date(2025, 7, 20, 23, 59, 0, 0, time.UTC)) || p.Contains(day(2025, 7, 21)) {
+		t.Errorf("Unexpected Contains result for %v..%v", p.Start, p.End)
+	}
+}
@@ -0,0 +1,89 @@
+// Package cache хранит ответы API отдельно для каждого пользователя.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store — кэш ответов API. Записи привязаны к версии данных пользователя: Invalidate
+// увеличивает версию, и записи прежних версий больше не читаются.
+type Store interface {
+	// Version возвращает текущую версию данных пользователя.
+	Version(ctx context.Context, userID int) (int64, error)
+	// Invalidate увеличивает версию данных пользователя.
+	Invalidate(ctx context.Context, userID int) error
+	// Get возвращает запись версии version; ok == false, если записи нет.
+	Get(ctx context.Context, userID int, version int64, key string) (value []byte, ok bool, err error)
+	// Set сохраняет запись версии version.
+	Set(ctx context.Context, userID int, version int64, key string, value []byte) error
+}
+
+const keyPrefix = "fin-ng:cache:"
+
+// Redis — Store в Redis. Записи удаляются самим Redis по истечении TTL, поэтому записи
+// прежних версий не нужно удалять при сбросе.
+type Redis struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedis подключается к Redis по адресу вида redis://[:password@]host:port[/db].
+// Записи хранятся не дольше ttl.
+func NewRedis(ctx context.Context, url string, ttl time.Duration) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &Redis{client: client, ttl: ttl}, nil
+}
+
+func versionKey(userID int) string {
+	return keyPrefix + strconv.Itoa(userID) + ":version"
+}
+
+func entryKey(userID int, version int64, key string) string {
+	return keyPrefix + strconv.Itoa(userID) + ":" + strconv.FormatInt(version, 10) + ":" + key
+}
+
+func (r *Redis) Version(ctx context.Context, userID int) (int64, error) {
+	version, err := r.client.Get(ctx, versionKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return version, err
+}
+
+func (r *Redis) Invalidate(ctx context.Context, userID int) error {
+	return r.client.Incr(ctx, versionKey(userID)).Err()
+}
+
+func (r *Redis) Get(ctx context.Context, userID int, version int64, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, entryKey(userID, version, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, userID int, version int64, key string, value []byte) error {
+	return r.client.Set(ctx, entryKey(userID, version, key), value, r.ttl).Err()
+}
+
+// Close закрывает соединения с Redis.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	store, err := NewRedis(ctx, "redis://"+server.Addr(), time.Minute)
+	if err != nil {
+		t.Fatalf("NewRedis failed: %v", err)
+	}
+	defer store.Close()
+
+	version, err := store.Version(ctx, 1)
+	if err != nil || version != 0 {
+		t.Fatalf("Expected version 0, got %d (%v)", version, err)
+	}
+	if _, ok, err := store.Get(ctx, 1, version, "/dashboard"); ok || err != nil {
+		t.Fatalf("Expected miss, got ok=%v err=%v", ok, err)
+	}
+	if err := store.Set(ctx, 1, version, "/dashboard", []byte("cached")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	value, ok, err := store.Get(ctx, 1, version, "/dashboard")
+	if err != nil || !ok || string(value) != "cached" {
+		t.Fatalf("Expected cached value, got %q ok=%v err=%v", value, ok, err)
+	}
+	if _, ok, _ := store.Get(ctx, 2, version, "/dashboard"); ok {
+		t.Error("Expected entries of another user to be separate")
+	}
+
+	// Запись живет не дольше TTL
+	server.FastForward(2 * time.Minute)
+	if _, ok, _ := store.Get(ctx, 1, version, "/dashboard"); ok {
+		t.Error("Expected entry to expire")
+	}
+
+	// Сброс меняет версию только у своего пользователя
+	if err := store.Invalidate(ctx, 1); err != nil {
+		t.Fatalf("Invalidate failed: %v", err)
+	}
+	if version, _ := store.Version(ctx, 1); version != 1 {
+		t.Errorf("Expected version 1 after invalidation, got %d", version)
+	}
+	if version, _ := store.Version(ctx, 2); version != 0 {
+		t.Errorf("Expected version of another user to stay 0, got %d", version)
+	}
+
+	if _, err := NewRedis(ctx, "http://"+server.Addr(), time.Minute); err == nil {
+		t.Error("Expected error for invalid url")
+	}
+}
@@ -0,0 +1,88 @@
+// Package captcha проверяет ответы CAPTCHA-сервисов (hCaptcha, Cloudflare Turnstile).
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	hCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// Verifier проверяет токен, полученный клиентом от CAPTCHA-виджета.
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// SiteVerifier реализует протокол siteverify, общий для hCaptcha и Turnstile.
+type SiteVerifier struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+func NewHCaptcha(secret string) *SiteVerifier {
+	return NewSiteVerifier(hCaptchaVerifyURL, secret)
+}
+
+func NewTurnstile(secret string) *SiteVerifier {
+	return NewSiteVerifier(turnstileVerifyURL, secret)
+}
+
+func NewSiteVerifier(verifyURL, secret string) *SiteVerifier {
+	return &SiteVerifier{verifyURL: verifyURL, secret: secret, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// New создает проверку для провайдера "hcaptcha" или "turnstile".
+func New(provider, secret string) (Verifier, error) {
+	switch provider {
+	case "hcaptcha":
+		return NewHCaptcha(secret), nil
+	case "turnstile":
+		return NewTurnstile(secret), nil
+	default:
+		return nil, fmt.Errorf("unknown captcha provider: %s", provider)
+	}
+}
+
+func (v *SiteVerifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha verification failed: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
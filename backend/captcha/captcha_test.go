@@ -0,0 +1,44 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSiteVerifier тестирует проверку токена через siteverify API.
+func TestSiteVerifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+		if r.PostForm.Get("secret") != "secret" {
+			t.Errorf("Expected secret 'secret', got %q", r.PostForm.Get("secret"))
+		}
+		if r.PostForm.Get("response") == "valid" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false}`))
+	}))
+	defer server.Close()
+
+	verifier := NewSiteVerifier(server.URL, "secret")
+
+	ok, err := verifier.Verify(context.Background(), "valid", "127.0.0.1")
+	if err != nil || !ok {
+		t.Errorf("Expected valid token to pass, got %v (%v)", ok, err)
+	}
+
+	ok, err = verifier.Verify(context.Background(), "invalid", "")
+	if err != nil || ok {
+		t.Errorf("Expected invalid token to fail, got %v (%v)", ok, err)
+	}
+
+	// Пустой токен отклоняется без запроса к сервису
+	ok, err = verifier.Verify(context.Background(), "", "")
+	if err != nil || ok {
+		t.Errorf("Expected empty token to fail, got %v (%v)", ok, err)
+	}
+}
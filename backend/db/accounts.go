@@ -0,0 +1,278 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// DefaultBalanceCacheMinTransactions — число транзакций, начиная с которого остаток счета кэшируется по умолчанию.
+const DefaultBalanceCacheMinTransactions = 1000
+
+// accountSelect вычисляет остатки счетов одним запросом. Транзакции счетов с сохраненным
+// остатком не соединяются, поэтому для них запрос не читает историю.
+const accountSelect = `SELECT a.id, a.user_id, a.name, a.currency, a.type, a.initial_balance,
+	COALESCE(a.credit_limit, 0), COALESCE(a.statement_day, 0), COALESCE(a.payment_due_day, 0),
+	a.loan_principal, COALESCE(a.loan_interest_rate, 0), COALESCE(a.loan_term_months, 0), a.loan_first_payment, a.created_at, a.closed_at,
+	COALESCE(a.cached_balance, a.initial_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0)),
+	a.cached_balance IS NOT NULL, COUNT(t.id), a.balance_version
+	FROM accounts a
+	LEFT JOIN transactions t ON t.account_id = a.id AND a.cached_balance IS NULL AND t.deleted_at IS NULL AND NOT t.planned`
+
+// CreateAccount создает счет; без валюты счет открывается в базовой валюте пользователя, без типа — обычным счетом.
+// Условия кредитной карты сохраняются только для кредитных карт.
+func (s *Storage) CreateAccount(userID int, request models.CreateAccount) (*models.Account, error) {
+	if request.Name == "" {
+		return nil, fmt.Errorf("account name is required")
+	}
+
+	account := &models.Account{UserID: userID, Name: request.Name, InitialBalance: request.InitialBalance, Balance: request.InitialBalance, Loan: request.Loan,
+		Role: "owner"}
+	principal, rate, term, firstPayment := loanArgs(request.Loan)
+	err := s.DB.QueryRow(`INSERT INTO accounts (user_id, name, currency, type, initial_balance, credit_limit, statement_day, payment_due_day,
+			loan_principal, loan_interest_rate, loan_term_months, loan_first_payment)
+		VALUES ($1, $2, COALESCE(NULLIF($3, ''), (SELECT base_currency FROM users WHERE id = $1)), COALESCE(NULLIF($4, ''), 'regular'), $5,
+			NULLIF($6::numeric, 0), NULLIF($7, 0), NULLIF($8, 0), $9, $10, $11, $12)
+		RETURNING id, currency, type, COALESCE(credit_limit, 0), COALESCE(statement_day, 0), COALESCE(payment_due_day, 0), created_at`,
+		userID, request.Name, request.Currency, request.Type, request.InitialBalance,
+		request.CreditLimit, request.StatementDay, request.PaymentDueDay, principal, rate, term, firstPayment).
+		Scan(&account.ID, &account.Currency, &account.Type, &account.CreditLimit, &account.StatementDay, &account.PaymentDueDay, &account.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// GetAccounts возвращает счета пользователя и открытые ему чужие счета с текущими остатками;
+// закрытые — только при includeClosed.
+func (s *Storage) GetAccounts(userID int, includeClosed bool) ([]models.Account, error) {
+	accounts, err := s.queryAccounts(accountSelect+" WHERE "+accountAccess("a", "$1", false)+" AND (a.closed_at IS NULL OR $2) GROUP BY a.id ORDER BY a.id",
+		userID, includeClosed)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, s.setAccountRoles(userID, accounts)
+}
+
+// GetAccount возвращает счет пользователя или открытый ему чужой счет с текущим остатком или nil, если счета нет.
+func (s *Storage) GetAccount(id, userID int) (*models.Account, error) {
+	accounts, err := s.queryAccounts(accountSelect+" WHERE a.id = $1 AND "+accountAccess("a", "$2", false)+" GROUP BY a.id", id, userID)
+	if err != nil || len(accounts) == 0 {
+		return nil, err
+	}
+	return &accounts[0], s.setAccountRoles(userID, accounts)
+}
+
+// loanArgs возвращает значения столбцов условий кредита; без условий все они NULL.
+func loanArgs(loan *models.LoanTerms) (principal, rate, term, firstPayment interface{}) {
+	if loan == nil {
+		return nil, nil, nil, nil
+	}
+	return loan.Principal, loan.InterestRate, loan.TermMonths, loan.FirstPaymentDate.Format("2006-01-02")
+}
+
+// queryAccounts выполняет запрос на основе accountSelect и сохраняет вычисленные остатки счетов,
+// у которых транзакций не меньше BalanceCacheMinTransactions.
+func (s *Storage) queryAccounts(query string, args ...interface{}) ([]models.Account, error) {
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type cacheEntry struct {
+		id      int
+		balance models.Money
+		version int64
+	}
+	var toCache []cacheEntry
+	accounts := []models.Account{}
+	for rows.Next() {
+		var a models.Account
+		var loan models.LoanTerms
+		var principal *models.Money
+		var firstPayment sql.NullTime
+		var cached bool
+		var count int
+		var version int64
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Name, &a.Currency, &a.Type, &a.InitialBalance,
+			&a.CreditLimit, &a.StatementDay, &a.PaymentDueDay,
+			&principal, &loan.InterestRate, &loan.TermMonths, &firstPayment, &a.CreatedAt, &a.ClosedAt,
+			&a.Balance, &cached, &count, &version); err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			loan.Principal, loan.FirstPaymentDate = *principal, firstPayment.Time
+			a.Loan = &loan
+		}
+		if !cached && s.BalanceCacheMinTransactions > 0 && count >= s.BalanceCacheMinTransactions {
+			toCache = append(toCache, cacheEntry{a.ID, a.Balance, version})
+		}
+		accounts = append(accounts, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Если транзакции счета изменились после вычисления, версия уже другая и остаток не сохраняется
+	for _, entry := range toCache {
+		_, err := s.DB.Exec("UPDATE accounts SET cached_balance = $1 WHERE id = $2 AND balance_version = $3",
+			entry.balance, entry.id, entry.version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// UpdateAccount изменяет имя, начальный баланс и условия кредитной карты или кредита открытого счета владельца.
+func (s *Storage) UpdateAccount(id, userID int, request models.UpdateAccount) (bool, error) {
+	if request.Name == "" {
+		return false, fmt.Errorf("account name is required")
+	}
+
+	principal, rate, term, firstPayment := loanArgs(request.Loan)
+	var closed bool
+	err := s.DB.QueryRow(`UPDATE accounts SET
+		name = CASE WHEN closed_at IS NULL THEN $1 ELSE name END,
+		initial_balance = CASE WHEN closed_at IS NULL THEN $2 ELSE initial_balance END,
+		credit_limit = CASE WHEN closed_at IS NULL THEN NULLIF($3::numeric, 0) ELSE credit_limit END,
+		statement_day = CASE WHEN closed_at IS NULL THEN NULLIF($4, 0) ELSE statement_day END,
+		payment_due_day = CASE WHEN closed_at IS NULL THEN NULLIF($5, 0) ELSE payment_due_day END,
+		loan_principal = CASE WHEN closed_at IS NULL THEN $6 ELSE loan_principal END,
+		loan_interest_rate = CASE WHEN closed_at IS NULL THEN $7 ELSE loan_interest_rate END,
+		loan_term_months = CASE WHEN closed_at IS NULL THEN $8 ELSE loan_term_months END,
+		loan_first_payment = CASE WHEN closed_at IS NULL THEN $9::date ELSE loan_first_payment END,
+		cached_balance = CASE WHEN closed_at IS NULL THEN NULL ELSE cached_balance END,
+		balance_version = balance_version + 1
+		WHERE id = $10 AND user_id = $11 RETURNING closed_at IS NOT NULL`,
+		request.Name, request.InitialBalance, request.CreditLimit, request.StatementDay, request.PaymentDueDay,
+		principal, rate, term, firstPayment, id, userID).Scan(&closed)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if closed {
+		return false, fmt.Errorf("account is closed")
+	}
+	return true, nil
+}
+
+// SetAccountClosed закрывает или открывает счет владельца. Повторное закрытие сохраняет прежнее время закрытия.
+func (s *Storage) SetAccountClosed(id, userID int, closed bool) (bool, error) {
+	result, err := s.DB.Exec(`UPDATE accounts SET closed_at = CASE WHEN $1 THEN COALESCE(closed_at, NOW()) END
+		WHERE id = $2 AND user_id = $3`, closed, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// inOpenAccounts — условие для строки transactions: ни транзакция, ни парная ей транзакция перевода
+// не относятся к закрытому счету.
+const inOpenAccounts = `NOT EXISTS (SELECT 1 FROM transactions p JOIN accounts a ON a.id = p.account_id
+	WHERE a.closed_at IS NOT NULL AND (p.id = transactions.id OR p.transfer_id = transactions.transfer_id))`
+
+// checkOpenAccounts возвращает ошибку, если транзакция пользователя или парная ей транзакция перевода
+// относится к закрытому счету. Несуществующая транзакция ошибкой не считается.
+func (s *Storage) checkOpenAccounts(transactionID, userID int) error {
+	var open bool
+	err := s.DB.QueryRow("SELECT "+inOpenAccounts+" FROM transactions WHERE id = $1 AND user_id = $2", transactionID, userID).Scan(&open)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !open {
+		return fmt.Errorf("account is closed")
+	}
+	return nil
+}
+
+// GetStatementAmounts возвращает остаток счета на момент before и сумму поступлений на счет начиная с before.
+// Запланированные и удаленные транзакции не учитываются, корректировки остатка не считаются поступлениями.
+func (s *Storage) GetStatementAmounts(id, userID int, before time.Time) (balance, received models.Money, err error) {
+	err = s.DB.QueryRow(`SELECT a.initial_balance + COALESCE(SUM(CASE WHEN t.date < $3 THEN
+			CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END END), 0),
+		COALESCE(SUM(CASE WHEN t.date >= $3 AND t.type = 'income' AND NOT t.adjustment THEN t.amount END), 0)
+		FROM accounts a
+		LEFT JOIN transactions t ON t.account_id = a.id AND t.deleted_at IS NULL AND NOT t.planned
+		WHERE a.id = $1 AND `+accountAccess("a", "$2", false)+`
+		GROUP BY a.id`, id, userID, before).Scan(&balance, &received)
+	return balance, received, err
+}
+
+// accountBalance вычисляет остаток счета по его транзакциям без кэша.
+func accountBalance(tx *sql.Tx, accountID int) (models.Money, error) {
+	var balance models.Money
+	err := tx.QueryRow(`SELECT a.initial_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0)
+		FROM accounts a
+		LEFT JOIN transactions t ON t.account_id = a.id AND t.deleted_at IS NULL AND NOT t.planned
+		WHERE a.id = $1
+		GROUP BY a.id`, accountID).Scan(&balance)
+	return balance, err
+}
+
+// DeleteAccount удаляет счет владельца, если по нему нет транзакций любых пользователей, в том числе в корзине.
+func (s *Storage) DeleteAccount(id, userID int) (bool, error) {
+	var used bool
+	err := s.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM transactions t JOIN accounts a ON a.id = t.account_id
+		WHERE a.id = $1 AND a.user_id = $2)`, id, userID).Scan(&used)
+	if err != nil {
+		return false, err
+	}
+	if used {
+		return false, fmt.Errorf("account is used in transactions")
+	}
+
+	result, err := s.DB.Exec("DELETE FROM accounts WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// checkAccount проверяет, что счет транзакции принадлежит пользователю или открыт ему для редактирования
+// и не закрыт, и приводит валюту
+// транзакции к валюте счета: без явной валюты берется валюта счета, другая валюта отклоняется.
+func checkAccount(tx *sql.Tx, t *models.Transaction) error {
+	if t.AccountID < 0 {
+		return fmt.Errorf("account_id must be positive")
+	}
+	if t.AccountID == 0 {
+		return nil
+	}
+
+	var currency string
+	var closed bool
+	err := tx.QueryRow("SELECT currency, closed_at IS NOT NULL FROM accounts WHERE id = $1 AND "+accountAccess("accounts", "$2", true), t.AccountID, t.UserID).
+		Scan(&currency, &closed)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("account does not exist or does not belong to user")
+	}
+	if err != nil {
+		return err
+	}
+	if closed {
+		return fmt.Errorf("account is closed")
+	}
+	if t.Currency == "" {
+		t.Currency = currency
+	} else if t.Currency != currency {
+		return fmt.Errorf("transaction currency %s does not match account currency %s", t.Currency, currency)
+	}
+	return nil
+}
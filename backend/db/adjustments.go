@@ -0,0 +1,68 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// CreateAdjustment создает корректировку, после которой остаток открытого счета становится равным balance:
+// доход или расход на разницу остатков, который не учитывается в доходах и расходах.
+func (s *Storage) CreateAdjustment(userID, accountID int, balance models.Money, date time.Time, description string) (*models.Transaction, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Блокируем счет, чтобы параллельные корректировки не исправили остаток дважды
+	var currency string
+	var closed bool
+	err = tx.QueryRow("SELECT currency, closed_at IS NOT NULL FROM accounts WHERE id = $1 AND "+accountAccess("accounts", "$2", true)+" FOR UPDATE", accountID, userID).
+		Scan(&currency, &closed)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		return nil, fmt.Errorf("account is closed")
+	}
+
+	current, err := accountBalance(tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.Transaction{UserID: userID, Type: "income", Amount: balance - current, Date: date, Description: description,
+		Currency: currency, Status: "cleared", AccountID: accountID, Adjustment: true, Tags: []string{}}
+	if t.Amount == 0 {
+		return nil, fmt.Errorf("account balance already equals %s", balance)
+	}
+	if t.Amount < 0 {
+		t.Type, t.Amount = "expense", -t.Amount
+	}
+	if t.Amount > MaxAmount {
+		return nil, fmt.Errorf("adjustment amount must be at most %s", MaxAmount)
+	}
+	if t.Date.IsZero() {
+		t.Date = time.Now()
+	}
+
+	err = tx.QueryRow(`INSERT INTO transactions (user_id, amount, type, date, description, currency, status, account_id, adjustment)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, true) RETURNING id`,
+		t.UserID, t.Amount, t.Type, t.Date, t.Description, t.Currency, t.Status, t.AccountID).Scan(&t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordVersion(tx, t.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/period"
+)
+
+// SnapshotBalances сохраняет остатки всех счетов на конец дня date и их суммы по валютам для каждого пользователя.
+// Уже сохраненные снимки за этот день не изменяются. Возвращает число сохраненных снимков счетов.
+func (s *Storage) SnapshotBalances(date time.Time) (int64, error) {
+	day := date.Format("2006-01-02")
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO account_balance_history (account_id, date, balance)
+		SELECT a.id, $1::date, a.initial_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0)
+		FROM accounts a
+		LEFT JOIN transactions t ON t.account_id = a.id AND t.deleted_at IS NULL AND NOT t.planned AND t.date < $1::date + 1
+		WHERE a.created_at < $1::date + 1
+		GROUP BY a.id
+		ON CONFLICT (account_id, date) DO NOTHING`, day)
+	if err != nil {
+		return 0, err
+	}
+	snapshots, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO balance_history (user_id, date, currency, balance)
+		SELECT a.user_id, h.date, a.currency, SUM(h.balance)
+		FROM account_balance_history h JOIN accounts a ON a.id = h.account_id
+		WHERE h.date = $1::date
+		GROUP BY a.user_id, h.date, a.currency
+		ON CONFLICT (user_id, date, currency) DO NOTHING`, day)
+	if err != nil {
+		return 0, err
+	}
+	return snapshots, tx.Commit()
+}
+
+// GetAccountBalanceHistory возвращает сохраненные остатки счета пользователя за дни с from по to включительно.
+func (s *Storage) GetAccountBalanceHistory(accountID, userID int, from, to time.Time) ([]models.BalancePoint, error) {
+	rows, err := s.DB.Query(`SELECT h.date, a.currency, h.balance
+		FROM account_balance_history h JOIN accounts a ON a.id = h.account_id
+		WHERE h.account_id = $1 AND `+accountAccess("a", "$2", false)+` AND h.date BETWEEN $3::date AND $4::date
+		ORDER BY h.date`, accountID, userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return scanBalancePoints(rows)
+}
+
+// GetBalanceHistory возвращает сохраненные суммы остатков счетов пользователя по валютам за дни с from по to включительно.
+func (s *Storage) GetBalanceHistory(userID int, from, to time.Time) ([]models.BalancePoint, error) {
+	rows, err := s.DB.Query(`SELECT date, currency, balance FROM balance_history
+		WHERE user_id = $1 AND date BETWEEN $2::date AND $3::date
+		ORDER BY date, currency`, userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return scanBalancePoints(rows)
+}
+
+func scanBalancePoints(rows *sql.Rows) ([]models.BalancePoint, error) {
+	defer rows.Close()
+
+	points := []models.BalancePoint{}
+	for rows.Next() {
+		var p models.BalancePoint
+		if err := rows.Scan(&p.Date, &p.Currency, &p.Balance); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// NetWorthSnapshot — остатки своих счетов пользователя на последний сохраненный день интервала.
+type NetWorthSnapshot struct {
+	// Start — первый день интервала, Date — день снимка
+	Start time.Time
+	Date  time.Time
+	// Balances — суммы по валютам: положительные остатки счетов в Income, отрицательные по модулю в Expense
+	Balances []models.TransactionTotals
+}
+
+// GetNetWorthHistory возвращает остатки своих счетов пользователя на последний день каждого интервала группировки g,
+// за который есть снимок, среди дней с from по to включительно. Интервалы без снимков пропускаются.
+func (s *Storage) GetNetWorthHistory(userID int, g period.Grouping, from, to time.Time) ([]NetWorthSnapshot, error) {
+	bucket, err := periodBucket(g, "h.date")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.DB.Query(`WITH snapshots AS (
+			SELECT h.date, `+bucket+` AS bucket, a.currency, h.balance
+			FROM account_balance_history h JOIN accounts a ON a.id = h.account_id
+			WHERE a.user_id = $1 AND h.date BETWEEN $2::date AND $3::date
+		), last AS (
+			SELECT bucket, MAX(date) AS date FROM snapshots GROUP BY bucket
+		)
+		SELECT l.bucket, l.date, s.currency, COALESCE(SUM(s.balance) FILTER (WHERE s.balance > 0), 0),
+			COALESCE(-SUM(s.balance) FILTER (WHERE s.balance < 0), 0)
+		FROM last l JOIN snapshots s ON s.date = l.date
+		GROUP BY l.bucket, l.date, s.currency
+		ORDER BY 1, 3`, userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := []NetWorthSnapshot{}
+	for rows.Next() {
+		var start, date time.Time
+		var t models.TransactionTotals
+		if err := rows.Scan(&start, &date, &t.Currency, &t.Income, &t.Expense); err != nil {
+			return nil, err
+		}
+		if n := len(snapshots); n == 0 || !snapshots[n-1].Start.Equal(start) {
+			snapshots = append(snapshots, NetWorthSnapshot{Start: start, Date: date})
+		}
+		last := &snapshots[len(snapshots)-1]
+		last.Balances = append(last.Balances, t)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// insertBudgetTemplateItems записывает лимиты шаблона; категория должна быть доступна пользователю.
+func insertBudgetTemplateItems(tx *sql.Tx, templateID, userID int, items []models.BudgetTemplateItem) error {
+	for _, item := range items {
+		result, err := tx.Exec(`INSERT INTO budget_template_items (template_id, category_id, amount, currency, rollover)
+			SELECT $1, id, $3, COALESCE(NULLIF($4, ''), (SELECT base_currency FROM users WHERE id = $5)), $6
+			FROM categories WHERE id = $2 AND `+visibleCategory(5),
+			templateID, item.CategoryID, item.Limit, item.Currency, userID, item.Rollover)
+		if err != nil {
+			return err
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if rowsAffected == 0 {
+			return fmt.Errorf("category %d does not exist", item.CategoryID)
+		}
+	}
+	return nil
+}
+
+// CreateBudgetTemplate создает шаблон бюджетов. Автоматически шаблон применяется начиная со следующего месяца.
+func (s *Storage) CreateBudgetTemplate(userID int, request models.CreateBudgetTemplate) (*models.BudgetTemplate, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`INSERT INTO budget_templates (user_id, name, auto_apply, applied_month) VALUES ($1, $2, $3, ($4 || '-01')::date)
+		RETURNING id`, userID, request.Name, request.AutoApply, time.Now().UTC().Format("2006-01")).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertBudgetTemplateItems(tx, id, userID, request.Items); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetBudgetTemplate(id, userID)
+}
+
+// GetBudgetTemplates возвращает шаблоны бюджетов пользователя с лимитами.
+func (s *Storage) GetBudgetTemplates(userID int) ([]models.BudgetTemplate, error) {
+	return s.queryBudgetTemplates("t.user_id = $1", userID)
+}
+
+// GetBudgetTemplate возвращает шаблон бюджетов пользователя или nil, если его нет.
+func (s *Storage) GetBudgetTemplate(id, userID int) (*models.BudgetTemplate, error) {
+	templates, err := s.queryBudgetTemplates("t.id = $2 AND t.user_id = $1", userID, id)
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+	return &templates[0], nil
+}
+
+func (s *Storage) queryBudgetTemplates(condition string, args ...interface{}) ([]models.BudgetTemplate, error) {
+	rows, err := s.DB.Query(`SELECT t.id, t.user_id, t.name, t.auto_apply, t.created_at FROM budget_templates t
+		WHERE `+condition+` ORDER BY t.name, t.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.BudgetTemplate{}
+	index := make(map[int]int)
+	for rows.Next() {
+		t := models.BudgetTemplate{Items: []models.BudgetTemplateItem{}}
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.AutoApply, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		index[t.ID] = len(templates)
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	items, err := s.DB.Query(`SELECT i.template_id, i.category_id, i.amount, i.currency, i.rollover
+		FROM budget_template_items i JOIN budget_templates t ON t.id = i.template_id JOIN categories c ON c.id = i.category_id
+		WHERE `+condition+` ORDER BY c.name, i.category_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer items.Close()
+	for items.Next() {
+		var templateID int
+		var item models.BudgetTemplateItem
+		if err := items.Scan(&templateID, &item.CategoryID, &item.Limit, &item.Currency, &item.Rollover); err != nil {
+			return nil, err
+		}
+		t := &templates[index[templateID]]
+		t.Items = append(t.Items, item)
+	}
+	return templates, items.Err()
+}
+
+// UpdateBudgetTemplate заменяет название, автоприменение и лимиты шаблона. Включенное автоприменение
+// действует начиная со следующего месяца.
+func (s *Storage) UpdateBudgetTemplate(id, userID int, request models.CreateBudgetTemplate) (bool, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE budget_templates SET name = $1, auto_apply = $2,
+		applied_month = CASE WHEN auto_apply THEN applied_month ELSE ($3 || '-01')::date END
+		WHERE id = $4 AND user_id = $5`, request.Name, request.AutoApply, time.Now().UTC().Format("2006-01"), id, userID)
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return false, err
+	}
+	if _, err := tx.Exec("DELETE FROM budget_template_items WHERE template_id = $1", id); err != nil {
+		return false, err
+	}
+	if err := insertBudgetTemplateItems(tx, id, userID, request.Items); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (s *Storage) DeleteBudgetTemplate(id, userID int) (bool, error) {
+	result, err := s.DB.Exec("DELETE FROM budget_templates WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// ApplyBudgetTemplate создает по шаблону пользователя месячные бюджеты месяца month и возвращает их число.
+// Категории, у которых в месяце уже есть месячный бюджет, пропускаются.
+func (s *Storage) ApplyBudgetTemplate(id, userID int, month budget.Period) (int64, error) {
+	result, err := s.DB.Exec(`INSERT INTO budgets (user_id, category_id, period, start_date, end_date, amount, currency, rollover)
+		SELECT t.user_id, i.category_id, 'month', $3, $4, i.amount, i.currency, i.rollover
+		FROM budget_template_items i JOIN budget_templates t ON t.id = i.template_id
+		WHERE t.id = $1 AND t.user_id = $2
+		ON CONFLICT (user_id, category_id, period, start_date) DO NOTHING`, id, userID, month.Start, month.End)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ApplyAutoBudgetTemplates применяет к месяцу month шаблоны с автоприменением, которые еще
+// не применялись к нему, и возвращает число созданных бюджетов. Каждый шаблон применяется к месяцу один раз,
+// поэтому удаленные пользователем бюджеты не создаются повторно.
+func (s *Storage) ApplyAutoBudgetTemplates(month budget.Period) (int64, error) {
+	result, err := s.DB.Exec(`WITH due AS (
+			UPDATE budget_templates SET applied_month = $1
+			WHERE auto_apply AND (applied_month IS NULL OR applied_month < $1)
+			RETURNING id, user_id
+		)
+		INSERT INTO budgets (user_id, category_id, period, start_date, end_date, amount, currency, rollover)
+		SELECT due.user_id, i.category_id, 'month', $1, $2, i.amount, i.currency, i.rollover
+		FROM due JOIN budget_template_items i ON i.template_id = due.id
+		ON CONFLICT (user_id, category_id, period, start_date) DO NOTHING`, month.Start, month.End)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
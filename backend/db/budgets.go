@@ -0,0 +1,224 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/budget"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const budgetColumns = "id, user_id, category_id, tag_id, period, start_date, end_date, amount, currency, rollover, carryover"
+
+// budgetTransactions присоединяет к бюджету b транзакции, которые считаются его расходами:
+// транзакции категории или с тегом бюджета в валюте бюджета за его период, без запланированных и удаленных.
+const budgetTransactions = `LEFT JOIN transactions t ON t.user_id = b.user_id AND t.currency = b.currency
+			AND (t.category_id = b.category_id OR EXISTS (SELECT 1 FROM transaction_tags tt WHERE tt.transaction_id = t.id AND tt.tag_id = b.tag_id))
+			AND t.deleted_at IS NULL AND NOT t.planned AND t.date >= b.start_date AND t.date < b.end_date + 1`
+
+// budgetMonth возвращает месяц месячного бюджета в формате YYYY-MM и пустую строку для остальных.
+func budgetMonth(period string, start time.Time) string {
+	if period != budget.Month {
+		return ""
+	}
+	return start.Format("2006-01")
+}
+
+func scanBudget(row rowScanner) (*models.Budget, error) {
+	var b models.Budget
+	var categoryID, tagID sql.NullInt32
+	err := row.Scan(&b.ID, &b.UserID, &categoryID, &tagID, &b.Period, &b.StartDate, &b.EndDate, &b.Limit, &b.Currency, &b.Rollover, &b.Carryover)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.CategoryID, b.TagID = int(categoryID.Int32), int(tagID.Int32)
+	b.Month = budgetMonth(b.Period, b.StartDate)
+	return &b, nil
+}
+
+// CreateBudget создает бюджет категории или тега на период; без валюты — в базовой валюте пользователя.
+// Возвращает nil, если категория или тег не существуют или недоступны пользователю.
+func (s *Storage) CreateBudget(userID int, period budget.Period, request models.CreateBudget) (*models.Budget, error) {
+	key, id := "category", request.CategoryID
+	source := `SELECT id, NULL::integer FROM categories WHERE id = $2 AND ` + visibleCategory(1)
+	if request.TagID != 0 {
+		key, id = "tag", request.TagID
+		source = `SELECT NULL::integer, id FROM tags WHERE id = $2 AND user_id = $1`
+	}
+	b, err := scanBudget(s.DB.QueryRow(`INSERT INTO budgets (user_id, category_id, tag_id, period, start_date, end_date, amount, currency, rollover)
+		SELECT $1, k.category_id, k.tag_id, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), (SELECT base_currency FROM users WHERE id = $1)), $8
+		FROM (`+source+`) AS k(category_id, tag_id)
+		RETURNING `+budgetColumns,
+		userID, id, period.Kind, period.Start, period.End, request.Limit, request.Currency, request.Rollover))
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("%s budget for %s %d starting %s already exists", period.Kind, key, id, period.Start.Format("2006-01-02"))
+	}
+	return b, err
+}
+
+// GetBudget возвращает бюджет пользователя или nil, если его нет.
+func (s *Storage) GetBudget(id, userID int) (*models.Budget, error) {
+	return scanBudget(s.DB.QueryRow("SELECT "+budgetColumns+" FROM budgets WHERE id = $1 AND user_id = $2", id, userID))
+}
+
+// UpdateBudgetCarryover пересчитывает и сохраняет перенос переносимых бюджетов пользователя, начинающихся
+// не позже дня until. Перенос — остаток бюджета той же категории или того же тега, валюты и вида периода, закончившегося накануне,
+// с учетом его собственного переноса; перерасход переносится отрицательной суммой.
+// Если прошлого переносимого бюджета нет, перенос равен нулю.
+func (s *Storage) UpdateBudgetCarryover(userID int, until time.Time) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT DISTINCT start_date FROM budgets WHERE user_id = $1 AND rollover AND start_date <= $2
+		ORDER BY start_date`, userID, until)
+	if err != nil {
+		return err
+	}
+	var starts []time.Time
+	for rows.Next() {
+		var start time.Time
+		if err := rows.Scan(&start); err != nil {
+			rows.Close()
+			return err
+		}
+		starts = append(starts, start)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Периоды обходятся по порядку: перенос зависит от уже пересчитанного переноса прошлого периода
+	for _, start := range starts {
+		_, err := tx.Exec(`UPDATE budgets cur SET carryover = COALESCE((
+			SELECT prev.amount + prev.carryover - prev.spent FROM (
+				SELECT b.amount, b.carryover, `+netTotalsColumns("t.")+`
+				FROM budgets b `+budgetTransactions+`
+				WHERE b.user_id = cur.user_id AND b.category_id IS NOT DISTINCT FROM cur.category_id
+					AND b.tag_id IS NOT DISTINCT FROM cur.tag_id AND b.currency = cur.currency
+					AND b.period = cur.period AND b.end_date = cur.start_date - 1 AND b.rollover
+				GROUP BY b.id
+			) AS prev(amount, carryover, income, spent)), 0)
+			WHERE cur.user_id = $1 AND cur.start_date = $2 AND cur.rollover`, userID, start)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetBudgetProgress возвращает бюджеты пользователя, периоды которых пересекаются с днями [from, to],
+// с расходами по ним одним запросом: сначала бюджеты категорий, затем тегов. Расходы считаются по транзакциям
+// категории или с тегом в валюте бюджета за вычетом возвратов, без запланированных, удаленных, переводов и корректировок.
+// Перенос берется сохраненный; его обновляет UpdateBudgetCarryover.
+func (s *Storage) GetBudgetProgress(userID int, from, to time.Time) ([]models.BudgetProgress, error) {
+	rows, err := s.DB.Query(`SELECT b.id, COALESCE(b.category_id, 0), COALESCE(c.name, ''), COALESCE(b.tag_id, 0), COALESCE(g.name, ''),
+		b.period, b.start_date, b.end_date, b.amount, b.currency, b.rollover, b.carryover, `+netTotalsColumns("t.")+`
+		FROM budgets b
+		LEFT JOIN categories c ON c.id = b.category_id
+		LEFT JOIN tags g ON g.id = b.tag_id
+		`+budgetTransactions+`
+		WHERE b.user_id = $1 AND b.start_date <= $3 AND b.end_date >= $2
+		GROUP BY b.id, c.name, g.name
+		ORDER BY b.tag_id IS NOT NULL, COALESCE(c.name, g.name), b.start_date, b.currency`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []models.BudgetProgress{}
+	for rows.Next() {
+		var b models.BudgetProgress
+		var income models.Money
+		if err := rows.Scan(&b.ID, &b.CategoryID, &b.CategoryName, &b.TagID, &b.TagName, &b.Period, &b.StartDate, &b.EndDate, &b.Limit, &b.Currency,
+			&b.Rollover, &b.Carryover, &income, &b.Spent); err != nil {
+			return nil, err
+		}
+		b.Month = budgetMonth(b.Period, b.StartDate)
+		b.Available = b.Limit + b.Carryover
+		b.Remaining = b.Available - b.Spent
+		b.Exceeded = b.Spent > b.Available
+		budgets = append(budgets, b)
+	}
+	return budgets, rows.Err()
+}
+
+// GetBudgetAlertUsers возвращает пользователей с email, у которых есть бюджеты на день date,
+// уведомление о превышении которых еще не отправлялось.
+func (s *Storage) GetBudgetAlertUsers(date time.Time) ([]models.User, error) {
+	rows, err := s.DB.Query(`SELECT u.id, u.username, u.email, u.base_currency, u.language FROM users u
+		WHERE u.email IS NOT NULL AND EXISTS (SELECT 1 FROM budgets b
+			WHERE b.user_id = u.id AND b.start_date <= $1 AND b.end_date >= $1 AND b.alerted_at IS NULL)
+		ORDER BY u.id`, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.BaseCurrency, &user.Language); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// ClaimBudgetAlerts отмечает уведомления о превышении бюджетов ids отправленными и возвращает ID бюджетов,
+// уведомления о которых еще не отправлялись. Отмеченное уведомление не возвращается повторно,
+// даже если его не удалось отправить.
+func (s *Storage) ClaimBudgetAlerts(userID int, ids []int) ([]int, error) {
+	rows, err := s.DB.Query(`UPDATE budgets SET alerted_at = NOW()
+		WHERE user_id = $1 AND id = ANY($2) AND alerted_at IS NULL RETURNING id`, userID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	claimed := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, id)
+	}
+	return claimed, rows.Err()
+}
+
+// UpdateBudget изменяет лимит и перенос бюджета; у непереносимого бюджета перенос обнуляется.
+// Уведомление о превышении измененного бюджета отправляется заново.
+func (s *Storage) UpdateBudget(id, userID int, request models.UpdateBudget) (bool, error) {
+	result, err := s.DB.Exec(`UPDATE budgets SET amount = $1, rollover = $2, carryover = CASE WHEN $2 THEN carryover ELSE 0 END, alerted_at = NULL
+		WHERE id = $3 AND user_id = $4`, request.Limit, request.Rollover, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (s *Storage) DeleteBudget(id, userID int) (bool, error) {
+	result, err := s.DB.Exec("DELETE FROM budgets WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,334 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// copyColumns — столбцы transactions, которые заполняет copyTransactions.
+var copyColumns = []string{"id", "user_id", "amount", "type", "category_id", "date", "description", "currency", "planned", "status",
+	"payee_id", "possible_duplicate", "linked_transaction_id", "flagged", "original_amount", "original_currency", "fx_rate", "account_id"}
+
+// bulkChecks кэширует проверки, которые в пакете транзакций повторяются для одних и тех же
+// категорий, контрагентов, счетов и пользователей.
+type bulkChecks struct {
+	tx         *sql.Tx
+	categories map[[2]int]bool
+	payees     map[string]payeeRef
+	accounts   map[[2]int]accountCheck
+	currencies map[int]string
+}
+
+type payeeRef struct {
+	id   int
+	name string
+}
+
+type accountCheck struct {
+	currency string
+	err      error
+}
+
+func newBulkChecks(tx *sql.Tx) *bulkChecks {
+	return &bulkChecks{
+		tx:         tx,
+		categories: make(map[[2]int]bool),
+		payees:     make(map[string]payeeRef),
+		accounts:   make(map[[2]int]accountCheck),
+		currencies: make(map[int]string),
+	}
+}
+
+// prepare проверяет транзакцию и заполняет поля, которые insertTransaction вычисляет при записи.
+func (b *bulkChecks) prepare(t *models.Transaction) error {
+	if t.UserID == 0 {
+		return fmt.Errorf("user_id is required")
+	}
+	if t.CategoryID <= 0 {
+		return fmt.Errorf("category_id is required and must be positive")
+	}
+
+	key := [2]int{t.CategoryID, t.UserID}
+	exists, checked := b.categories[key]
+	if !checked {
+		err := b.tx.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND "+visibleCategory(2)+")", t.CategoryID, t.UserID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		b.categories[key] = exists
+	}
+	if !exists {
+		return fmt.Errorf("category does not exist or does not belong to user")
+	}
+
+	if t.Date.IsZero() {
+		t.Date = time.Now()
+	}
+	if err := b.resolvePayee(t); err != nil {
+		return err
+	}
+	deriveFXRate(t)
+	if err := b.checkAccount(t); err != nil {
+		return err
+	}
+
+	// Без явной валюты транзакция записывается в базовой валюте пользователя
+	if t.Currency == "" {
+		currency, ok := b.currencies[t.UserID]
+		if !ok {
+			if err := b.tx.QueryRow("SELECT base_currency FROM users WHERE id = $1", t.UserID).Scan(&currency); err != nil {
+				return err
+			}
+			b.currencies[t.UserID] = currency
+		}
+		t.Currency = currency
+	}
+	if t.Status == "" {
+		t.Status = "cleared"
+	}
+	return nil
+}
+
+// resolvePayee работает как resolvePayee, но находит контрагента с одним именем один раз.
+func (b *bulkChecks) resolvePayee(t *models.Transaction) error {
+	if t.PayeeID > 0 || strings.TrimSpace(t.Payee) == "" {
+		return resolvePayee(b.tx, t)
+	}
+	name, err := normalizePayeeName(t.Payee)
+	if err != nil {
+		return err
+	}
+	key := strconv.Itoa(t.UserID) + ":" + strings.ToLower(name)
+	if payee, ok := b.payees[key]; ok {
+		t.PayeeID, t.Payee = payee.id, payee.name
+		return nil
+	}
+	if err := resolvePayee(b.tx, t); err != nil {
+		return err
+	}
+	b.payees[key] = payeeRef{id: t.PayeeID, name: t.Payee}
+	return nil
+}
+
+// checkAccount работает как checkAccount, но читает каждый счет один раз.
+func (b *bulkChecks) checkAccount(t *models.Transaction) error {
+	if t.AccountID <= 0 {
+		return checkAccount(b.tx, t)
+	}
+	key := [2]int{t.AccountID, t.UserID}
+	check, ok := b.accounts[key]
+	if !ok {
+		// Валюта счета подставляется в пустую валюту транзакции
+		probe := models.Transaction{UserID: t.UserID, AccountID: t.AccountID}
+		check.err = checkAccount(b.tx, &probe)
+		check.currency = probe.Currency
+		b.accounts[key] = check
+	}
+	if check.err != nil {
+		return check.err
+	}
+	if t.Currency == "" {
+		t.Currency = check.currency
+	} else if t.Currency != check.currency {
+		return fmt.Errorf("transaction currency %s does not match account currency %s", t.Currency, check.currency)
+	}
+	return nil
+}
+
+// nullIfZero возвращает nil для нулевого значения, чтобы COPY записал NULL, как NULLIF в insertTransaction.
+func nullIfZero[T comparable](v T) interface{} {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return v
+}
+
+// copyTransactions записывает проверенные транзакции одной командой COPY вместо INSERT
+// для каждой строки и выполняет за несколько запросов то, что insertTransaction делает
+// после записи: поиск дубликатов, проверку возвратов, теги и первую версию истории.
+// Транзакции должны быть подготовлены bulkChecks.prepare.
+func copyTransactions(tx *sql.Tx, ts []*models.Transaction) error {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	// COPY не возвращает ID, поэтому они выделяются из последовательности заранее
+	var ids []int64
+	err := tx.QueryRow("SELECT ARRAY(SELECT nextval(pg_get_serial_sequence('transactions', 'id')) FROM generate_series(1, $1))", len(ts)).
+		Scan(pq.Array(&ids))
+	if err != nil {
+		return err
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for i, t := range ts {
+		t.ID = int(ids[i])
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("transactions", copyColumns...))
+	if err != nil {
+		return err
+	}
+	for _, t := range ts {
+		_, err := stmt.Exec(t.ID, t.UserID, t.Amount, t.Type, t.CategoryID, t.Date, t.Description, t.Currency, t.Planned, t.Status,
+			nullIfZero(t.PayeeID), false, nullIfZero(t.LinkedTransactionID), t.Flagged,
+			nullIfZero(t.OriginalAmount), nullIfZero(t.OriginalCurrency), nullIfZero(t.FXRate), nullIfZero(t.AccountID))
+		if err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	if err := markBulkDuplicates(tx, ts, ids); err != nil {
+		return err
+	}
+	for i, t := range ts {
+		if t.LinkedTransactionID == 0 {
+			continue
+		}
+		if err := checkLinkedTransaction(tx, t); err != nil {
+			return fmt.Errorf("transaction %d: %w", i, err)
+		}
+	}
+	if err := setBulkTags(tx, ts); err != nil {
+		return err
+	}
+	return recordBulkVersions(tx, ids)
+}
+
+// markBulkDuplicates ищет дубликаты по тем же правилам, что findDuplicates. Транзакция пакета
+// сравнивается с транзакциями, созданными раньше нее, в том числе с предыдущими транзакциями пакета.
+func markBulkDuplicates(tx *sql.Tx, ts []*models.Transaction, ids []int64) error {
+	rows, err := tx.Query(`SELECT t.id, ARRAY(SELECT d.id FROM transactions d
+			WHERE d.user_id = t.user_id AND d.id < t.id AND d.amount = t.amount AND d.type = t.type
+				AND d.currency = t.currency AND d.payee_id IS NOT DISTINCT FROM t.payee_id
+				AND d.date BETWEEN t.date - INTERVAL '1 day' AND t.date + INTERVAL '1 day'
+				AND d.deleted_at IS NULL AND NOT d.planned
+			ORDER BY d.id)
+		FROM transactions t WHERE t.id = ANY($1) AND NOT t.planned`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	duplicates := make(map[int][]int)
+	for rows.Next() {
+		var id int
+		var of []int64
+		if err := rows.Scan(&id, pq.Array(&of)); err != nil {
+			rows.Close()
+			return err
+		}
+		for _, d := range of {
+			duplicates[id] = append(duplicates[id], int(d))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var flagged []int64
+	for _, t := range ts {
+		t.DuplicateOf = duplicates[t.ID]
+		t.PossibleDuplicate = len(t.DuplicateOf) > 0
+		if t.PossibleDuplicate {
+			flagged = append(flagged, int64(t.ID))
+		}
+	}
+	if len(flagged) == 0 {
+		return nil
+	}
+	_, err = tx.Exec("UPDATE transactions SET possible_duplicate = TRUE WHERE id = ANY($1)", pq.Array(flagged))
+	return err
+}
+
+// setBulkTags создает недостающие теги пользователей и привязывает их ко всем транзакциям пакета
+// двумя запросами.
+func setBulkTags(tx *sql.Tx, ts []*models.Transaction) error {
+	var (
+		ids   []int64
+		users []int64
+		names []string
+	)
+	for i, t := range ts {
+		normalized, err := normalizeTagNames(t.Tags)
+		if err != nil {
+			return fmt.Errorf("transaction %d: %w", i, err)
+		}
+		t.Tags = normalized
+		for _, name := range normalized {
+			ids, users, names = append(ids, int64(t.ID)), append(users, int64(t.UserID)), append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(`INSERT INTO tags (user_id, name) SELECT DISTINCT * FROM unnest($1::int[], $2::text[])
+		ON CONFLICT (user_id, name) DO NOTHING`, pq.Array(users), pq.Array(names))
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO transaction_tags (transaction_id, tag_id)
+		SELECT n.transaction_id, tags.id FROM unnest($1::int[], $2::int[], $3::text[]) AS n(transaction_id, user_id, name)
+		JOIN tags ON tags.user_id = n.user_id AND tags.name = n.name`,
+		pq.Array(ids), pq.Array(users), pq.Array(names))
+	return err
+}
+
+// recordBulkVersions сохраняет первую версию истории созданных транзакций командой COPY.
+func recordBulkVersions(tx *sql.Tx, ids []int64) error {
+	rows, err := tx.Query("SELECT "+transactionColumns+" FROM transactions WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	var versions []models.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		versions = append(versions, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("transaction_history", "transaction_id", "version", "data"))
+	if err != nil {
+		return err
+	}
+	for _, t := range versions {
+		data, err := json.Marshal(t)
+		if err != nil {
+			stmt.Close()
+			return err
+		}
+		// []byte COPY записал бы как bytea, а столбцу JSONB нужен текст
+		if _, err := stmt.Exec(t.ID, 1, string(data)); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
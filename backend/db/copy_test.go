@@ -0,0 +1,92 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestCreateTransactionsCopy проверяет, что пакет, записанный через COPY, получает те же
+// ID, валюту, контрагентов, теги, отметки дубликатов и историю, что и транзакция,
+// созданная по одной.
+func TestCreateTransactionsCopy(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("copyuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := store.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	date := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	existing := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(250, 0), Type: "expense", CategoryID: category.ID, Date: date, Payee: "Cafe"}
+	if err := store.CreateTransaction(existing); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+
+	var batch []*models.Transaction
+	for i := 0; i < 500; i++ {
+		batch = append(batch, &models.Transaction{UserID: user.ID, Amount: models.NewMoney(int64(1000+i), 0), Type: "income",
+			CategoryID: category.ID, Date: date.Add(time.Duration(i) * time.Hour), Description: fmt.Sprintf("row %d", i)})
+	}
+	// Похожа на существующую транзакцию, а следующая — на нее
+	batch[10] = &models.Transaction{UserID: user.ID, Amount: models.NewMoney(250, 0), Type: "expense", CategoryID: category.ID,
+		Date: date.Add(time.Hour), Payee: " cafe ", Tags: []string{"lunch", "work", "lunch"}}
+	batch[11] = &models.Transaction{UserID: user.ID, Amount: models.NewMoney(250, 0), Type: "expense", CategoryID: category.ID,
+		Date: date.Add(2 * time.Hour), Payee: "CAFE", Status: "pending"}
+
+	if err := store.CreateTransactions(batch); err != nil {
+		t.Fatalf("CreateTransactions failed: %v", err)
+	}
+	for i, tr := range batch {
+		if tr.ID <= existing.ID || i > 0 && tr.ID <= batch[i-1].ID {
+			t.Fatalf("Expected increasing IDs after %d, got %d at %d", existing.ID, tr.ID, i)
+		}
+	}
+
+	if !reflect.DeepEqual(batch[10].DuplicateOf, []int{existing.ID}) || !batch[10].PossibleDuplicate {
+		t.Errorf("Expected row 10 to duplicate existing transaction, got %v", batch[10].DuplicateOf)
+	}
+	if !reflect.DeepEqual(batch[11].DuplicateOf, []int{existing.ID, batch[10].ID}) {
+		t.Errorf("Expected row 11 to duplicate existing transaction and row 10, got %v", batch[11].DuplicateOf)
+	}
+	if batch[0].PossibleDuplicate {
+		t.Error("Expected unique row not to be flagged")
+	}
+
+	got, err := store.GetTransaction(batch[10].ID, user.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetTransaction failed: %v", err)
+	}
+	if got.PayeeID != existing.PayeeID || got.Currency != "RUB" || got.Status != "cleared" || !got.PossibleDuplicate ||
+		!reflect.DeepEqual(got.Tags, []string{"lunch", "work"}) {
+		t.Errorf("Unexpected stored transaction: %+v", got)
+	}
+	if got, _ := store.GetTransaction(batch[11].ID, user.ID); got == nil || got.Status != "pending" {
+		t.Errorf("Expected explicit status to be kept, got %+v", got)
+	}
+
+	history, err := store.GetTransactionHistory(batch[10].ID, user.ID)
+	if err != nil || len(history) != 1 || history[0].Version != 1 || !reflect.DeepEqual(history[0].Transaction.Tags, []string{"lunch", "work"}) {
+		t.Errorf("Expected first history version with tags, got %+v (%v)", history, err)
+	}
+
+	// Ошибка в любой строке откатывает весь пакет
+	invalid := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(1, 0), Type: "income", CategoryID: category.ID, Description: "rolled back"},
+		{UserID: user.ID, Amount: models.NewMoney(1, 0), Type: "income", CategoryID: category.ID + 1000},
+	}
+	if err := store.CreateTransactions(invalid); err == nil || err.Error() != "transaction 1: category does not exist or does not belong to user" {
+		t.Errorf("Expected category error for row 1, got %v", err)
+	}
+	if _, total, _ := store.GetTransactions(user.ID, TransactionFilter{}, 1, 1); total != 501 {
+		t.Errorf("Expected 501 transactions after rollback, got %d", total)
+	}
+}
@@ -1,62 +1,92 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/metrics"
 	"github.com/nemopss/fin-ng/backend/models"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Storage struct {
 	DB *sql.DB
+	// BalanceCacheMinTransactions — с какого числа транзакций вычисленный остаток счета сохраняется
+	// до следующего изменения его транзакций; 0 отключает кэш
+	BalanceCacheMinTransactions int
 }
 
-func NewStorage(connStr string) (*Storage, error) {
+// PoolConfig — настройки пула соединений и ожидания базы при запуске.
+// Нулевые значения оставляют умолчания database/sql
+type PoolConfig struct {
+	// MaxOpenConns ограничивает число открытых соединений; 0 — без ограничения
+	MaxOpenConns int
+	// MaxIdleConns — сколько простаивающих соединений держать открытыми; отрицательное значение
+	// закрывает соединения сразу после использования
+	MaxIdleConns int
+	// ConnMaxLifetime — через сколько соединение закрывается и открывается заново; 0 — без ограничения
+	ConnMaxLifetime time.Duration
+	// ConnectTimeout — сколько повторять проверку соединения, пока база недоступна; 0 — одна попытка
+	ConnectTimeout time.Duration
+}
 
+// NewStorage подключается к базе, дожидаясь ее доступности, и применяет к ней недостающие миграции схемы.
+func NewStorage(connStr string, pool PoolConfig) (*Storage, error) {
 	db, err := sql.Open("postgres", connStr)
-
 	if err != nil {
 		return nil, err
 	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	if pool.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
 
-	// Создание таблицы users
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS users (
-		id SERIAL PRIMARY KEY,
-		username TEXT UNIQUE,
-		password TEXT
-	)`)
-	if err != nil {
+	if err := waitForDB(db, pool.ConnectTimeout); err != nil {
+		db.Close()
 		return nil, err
 	}
-
-	// Создание таблицы categories
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS categories (
-		id SERIAL PRIMARY KEY,
-		user_id INTEGER REFERENCES users(id),
-		name TEXT NOT NULL
-	)`)
-	if err != nil {
+	if err := Migrate(context.Background(), db); err != nil {
+		db.Close()
 		return nil, err
 	}
+	return &Storage{DB: db, BalanceCacheMinTransactions: DefaultBalanceCacheMinTransactions}, nil
+}
 
-	// Создание таблицы transactions
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS transactions (
-		id SERIAL PRIMARY KEY,
-		user_id INTEGER REFERENCES users(id),
-		amount FLOAT,
-		type TEXT,
-		category_id INTEGER REFERENCES categories(id),
-		date TIMESTAMP
-	)`)
+// Пауза между попытками подключения растет вдвое от connectRetryMin до connectRetryMax
+var (
+	connectRetryMin = 500 * time.Millisecond
+	connectRetryMax = 10 * time.Second
+)
 
-	if err != nil {
-		return nil, err
+// waitForDB проверяет соединение с базой и при ошибке повторяет проверку с растущей паузой,
+// пока не истечет timeout.
+func waitForDB(db *sql.DB, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	delay := connectRetryMin
+	for {
+		err := db.Ping()
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		log.Printf("database is not available, retrying in %v: %v", delay, err)
+		time.Sleep(delay)
+		delay = min(delay*2, connectRetryMax)
 	}
+}
 
-	return &Storage{DB: db}, nil
+// sqlx возвращает соединение с базой с поддержкой именованных параметров и чтения строк в структуры.
+func (s *Storage) sqlx() *sqlx.DB {
+	return sqlx.NewDb(s.DB, "postgres")
 }
 
 func (s *Storage) Close() {
@@ -64,6 +94,34 @@ func (s *Storage) Close() {
 }
 
 func (s *Storage) CreateUser(username, password string) (*models.User, error) {
+	return s.CreateUserWithEmail(username, password, "")
+}
+
+// CreateUserWithEmail создает пользователя с необязательным email.
+func (s *Storage) CreateUserWithEmail(username, password, email string) (*models.User, error) {
+	user, err := newUser(username, password, email)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.DB.QueryRow(
+		"INSERT INTO users (username, password, email) VALUES ($1, $2, NULLIF($3, '')) RETURNING id, role, base_currency, month_start, language",
+		user.Username, user.Password, user.Email,
+	).Scan(&user.ID, &user.Role, &user.BaseCurrency, &user.MonthStart, &user.Language)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// NormalizeEmail приводит email к виду, в котором он хранится: без пробелов по краям и в нижнем регистре.
+// Уникальность email проверяется без учета регистра, поэтому адреса, различающиеся только регистром, совпадают.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// newUser проверяет данные пользователя и хеширует пароль.
+func newUser(username, password, email string) (*models.User, error) {
 	if username == "" || password == "" {
 		return nil, fmt.Errorf("username and password are required")
 	}
@@ -77,21 +135,26 @@ func (s *Storage) CreateUser(username, password string) (*models.User, error) {
 		return nil, err
 	}
 
-	user := &models.User{Username: username, Password: string(hashedPassword)}
-	err = s.DB.QueryRow(
-		"INSERT INTO users (username, password) VALUES ($1, $2) RETURNING id",
-		user.Username, user.Password,
-	).Scan(&user.ID)
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	return &models.User{Username: username, Password: string(hashedPassword), Email: NormalizeEmail(email)}, nil
 }
 
 func (s *Storage) GetUserByUsername(username string) (*models.User, error) {
+	return s.getUser("username = $1", username)
+}
+
+func (s *Storage) GetUserByID(id int) (*models.User, error) {
+	return s.getUser("id = $1", id)
+}
+
+func (s *Storage) GetUserByEmail(email string) (*models.User, error) {
+	return s.getUser("LOWER(email) = $1", NormalizeEmail(email))
+}
+
+func (s *Storage) getUser(condition string, arg interface{}) (*models.User, error) {
 	var user models.User
-	err := s.DB.QueryRow("SELECT id, username, password FROM users WHERE username = $1", username).
-		Scan(&user.ID, &user.Username, &user.Password)
+	var email sql.NullString
+	err := s.DB.QueryRow("SELECT id, username, password, email, email_verified, role, base_currency, month_start, language FROM users WHERE "+condition, arg).
+		Scan(&user.ID, &user.Username, &user.Password, &email, &user.EmailVerified, &user.Role, &user.BaseCurrency, &user.MonthStart, &user.Language)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -101,16 +164,23 @@ func (s *Storage) GetUserByUsername(username string) (*models.User, error) {
 		return nil, err
 	}
 
+	user.Email = email.String
 	return &user, nil
 }
 
 func (s *Storage) CreateCategory(userID int, name string) (*models.Category, error) {
+	return s.CreateCategoryWithAppearance(userID, name, "", "")
+}
+
+// CreateCategoryWithAppearance создает категорию со значком и цветом.
+func (s *Storage) CreateCategoryWithAppearance(userID int, name, icon, color string) (*models.Category, error) {
 	if name == "" {
 		return nil, fmt.Errorf("category name is required")
 	}
 
-	category := &models.Category{UserID: userID, Name: name}
-	err := s.DB.QueryRow("INSERT INTO categories (user_id, name) VALUES ($1, $2) RETURNING id", userID, name).Scan(&category.ID)
+	category := &models.Category{UserID: userID, Name: name, Icon: icon, Color: color}
+	err := s.DB.QueryRow("INSERT INTO categories (user_id, name, icon, color) VALUES ($1, $2, $3, $4) RETURNING id",
+		userID, name, icon, color).Scan(&category.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -118,8 +188,28 @@ func (s *Storage) CreateCategory(userID int, name string) (*models.Category, err
 	return category, nil
 }
 
+// categoryColumns — столбцы категории в порядке, ожидаемом scanCategory.
+// У системных категорий нет владельца, их user_id равен NULL.
+const categoryColumns = "id, COALESCE(user_id, 0), name, icon, color, user_id IS NULL"
+
+// visibleCategory — условие на категории, доступные пользователю $n: собственные и системные.
+func visibleCategory(n int) string {
+	return fmt.Sprintf("(user_id = $%d OR user_id IS NULL)", n)
+}
+
+func scanCategory(row rowScanner) (models.Category, error) {
+	var c models.Category
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Icon, &c.Color, &c.System)
+	return c, err
+}
+
+// GetCategories возвращает категории пользователя вместе с системными.
 func (s *Storage) GetCategories(userID int) ([]models.Category, error) {
-	rows, err := s.DB.Query("SELECT id, user_id, name FROM categories WHERE user_id = $1", userID)
+	return s.queryCategories("SELECT "+categoryColumns+" FROM categories WHERE "+visibleCategory(1)+" ORDER BY id", userID)
+}
+
+func (s *Storage) queryCategories(query string, args ...interface{}) ([]models.Category, error) {
+	rows, err := s.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -127,18 +217,18 @@ func (s *Storage) GetCategories(userID int) ([]models.Category, error) {
 
 	var categories []models.Category
 	for rows.Next() {
-		var c models.Category
-		if err := rows.Scan(&c.ID, &c.UserID, &c.Name); err != nil {
+		c, err := scanCategory(rows)
+		if err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
 	}
-	return categories, nil
+	return categories, rows.Err()
 }
 
+// GetCategory возвращает категорию пользователя или системную категорию.
 func (s *Storage) GetCategory(id, userID int) (*models.Category, error) {
-	var c models.Category
-	err := s.DB.QueryRow("SELECT id, user_id, name FROM categories WHERE id = $1 AND user_id = $2", id, userID).Scan(&c.ID, &c.UserID, &c.Name)
+	c, err := scanCategory(s.DB.QueryRow("SELECT "+categoryColumns+" FROM categories WHERE id = $1 AND "+visibleCategory(2), id, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -166,17 +256,14 @@ func (s *Storage) UpdateCategory(id, userID int, name string) (bool, error) {
 
 }
 
-func (s *Storage) DeleteCategory(id, userID int) (bool, error) {
-	var count int
-	err := s.DB.QueryRow("SELECT COUNT(*) FROM transactions WHERE category_id = $1 AND user_id = $2", id, userID).Scan(&count)
-	if count > 0 {
-		return false, fmt.Errorf("category is used in transactions")
-	}
-	if err != nil {
-		return false, err
+// UpdateCategoryWithAppearance изменяет имя, значок и цвет категории.
+func (s *Storage) UpdateCategoryWithAppearance(id, userID int, name, icon, color string) (bool, error) {
+	if name == "" {
+		return false, fmt.Errorf("category name is required")
 	}
 
-	result, err := s.DB.Exec("DELETE FROM categories WHERE id = $1 AND user_id = $2", id, userID)
+	result, err := s.DB.Exec("UPDATE categories SET name = $1, icon = $2, color = $3 WHERE id = $4 AND user_id = $5",
+		name, icon, color, id, userID)
 	if err != nil {
 		return false, err
 	}
@@ -185,111 +272,586 @@ func (s *Storage) DeleteCategory(id, userID int) (bool, error) {
 		return false, err
 	}
 	return rowsAffected > 0, nil
+}
+
+// DeleteCategory удаляет категорию пользователя, если она не используется в транзакциях.
+// Категория блокируется до проверки: транзакция, которую параллельно создают или переносят
+// в эту категорию, ждет удаления и получает ошибку внешнего ключа, а не остается без категории.
+func (s *Storage) DeleteCategory(id, userID int) (bool, error) {
+	var deleted bool
+	err := s.WithTx(sql.LevelReadCommitted, func(tx *sql.Tx) error {
+		deleted = false
+		var locked int
+		err := tx.QueryRow("SELECT id FROM categories WHERE id = $1 AND user_id = $2 FOR UPDATE", id, userID).Scan(&locked)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 
+		var used bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM transactions WHERE category_id = $1)", id).Scan(&used); err != nil {
+			return err
+		}
+		if used {
+			return fmt.Errorf("category is used in transactions")
+		}
+
+		if _, err := tx.Exec("DELETE FROM categories WHERE id = $1", id); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
 }
 
-func (s *Storage) GetTransactions(userID int, filterType string, filterCategoryID int, minAmount, maxAmount float64, sort string, page, limit int) ([]models.Transaction, int, error) {
-	countQuery := "SELECT COUNT(*) FROM transactions WHERE user_id = $1"
-	args := []interface{}{userID}
-	var conditions []string
+// MaxAmount — наибольшая сумма транзакции, помещающаяся в столбец NUMERIC(14,2).
+const MaxAmount = models.Money(1e14 - 1)
 
-	if filterType != "" {
-		if filterType != "income" && filterType != "expense" {
-			return nil, 0, fmt.Errorf("invalid type filter: must be 'income' or 'expense'")
+// transactionColumns — столбцы транзакции в порядке, ожидаемом scanTransaction, и с именами полей transactionRow.
+// Имя контрагента и теги собираются подзапросами, поэтому в запросе таблица transactions не должна иметь псевдонима.
+const transactionColumns = "id, user_id, amount, type, category_id, date, description, currency, planned, status, possible_duplicate, flagged, deleted_at, " +
+	"original_amount, COALESCE(original_currency, '') AS original_currency, COALESCE(fx_rate, 0) AS fx_rate, " +
+	"linked_transaction_id, account_id, transfer_id, adjustment, payee_id, (SELECT name FROM payees WHERE payees.id = transactions.payee_id) AS payee, " +
+	"ARRAY(SELECT tg.name FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id WHERE tt.transaction_id = transactions.id ORDER BY tg.name) AS tags"
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// transactionRow — строка транзакции, выбранная столбцами transactionColumns.
+type transactionRow struct {
+	ID                  int            `db:"id"`
+	UserID              int            `db:"user_id"`
+	Amount              models.Money   `db:"amount"`
+	Type                string         `db:"type"`
+	CategoryID          sql.NullInt32  `db:"category_id"`
+	Date                time.Time      `db:"date"`
+	Description         string         `db:"description"`
+	Currency            string         `db:"currency"`
+	Planned             bool           `db:"planned"`
+	Status              string         `db:"status"`
+	PossibleDuplicate   bool           `db:"possible_duplicate"`
+	Flagged             bool           `db:"flagged"`
+	DeletedAt           sql.NullTime   `db:"deleted_at"`
+	OriginalAmount      models.Money   `db:"original_amount"`
+	OriginalCurrency    string         `db:"original_currency"`
+	FXRate              float64        `db:"fx_rate"`
+	LinkedTransactionID sql.NullInt32  `db:"linked_transaction_id"`
+	AccountID           sql.NullInt32  `db:"account_id"`
+	TransferID          sql.NullInt32  `db:"transfer_id"`
+	Adjustment          bool           `db:"adjustment"`
+	PayeeID             sql.NullInt32  `db:"payee_id"`
+	Payee               sql.NullString `db:"payee"`
+	Tags                pq.StringArray `db:"tags"`
+}
+
+// pagedTransactionRow — строка транзакции с общим числом строк запроса без учета LIMIT.
+type pagedTransactionRow struct {
+	transactionRow
+	Total int `db:"total_count"`
+}
+
+func (r transactionRow) transaction() models.Transaction {
+	t := models.Transaction{
+		ID:                  r.ID,
+		UserID:              r.UserID,
+		Amount:              r.Amount,
+		Type:                r.Type,
+		CategoryID:          int(r.CategoryID.Int32),
+		Date:                r.Date,
+		Description:         r.Description,
+		Currency:            r.Currency,
+		Tags:                r.Tags,
+		Planned:             r.Planned,
+		Status:              r.Status,
+		PayeeID:             int(r.PayeeID.Int32),
+		Payee:               r.Payee.String,
+		PossibleDuplicate:   r.PossibleDuplicate,
+		Flagged:             r.Flagged,
+		LinkedTransactionID: int(r.LinkedTransactionID.Int32),
+		OriginalAmount:      r.OriginalAmount,
+		OriginalCurrency:    r.OriginalCurrency,
+		FXRate:              r.FXRate,
+		AccountID:           int(r.AccountID.Int32),
+		TransferID:          int(r.TransferID.Int32),
+		Adjustment:          r.Adjustment,
+	}
+	if r.DeletedAt.Valid {
+		t.DeletedAt = &r.DeletedAt.Time
+	}
+	return t
+}
+
+func scanTransaction(row rowScanner) (models.Transaction, error) {
+	var r transactionRow
+	err := row.Scan(&r.ID, &r.UserID, &r.Amount, &r.Type, &r.CategoryID, &r.Date, &r.Description, &r.Currency, &r.Planned, &r.Status, &r.PossibleDuplicate, &r.Flagged, &r.DeletedAt,
+		&r.OriginalAmount, &r.OriginalCurrency, &r.FXRate,
+		&r.LinkedTransactionID, &r.AccountID, &r.TransferID, &r.Adjustment, &r.PayeeID, &r.Payee, &r.Tags)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	return r.transaction(), nil
+}
+
+// TransactionFilter — условия отбора транзакций в списке.
+type TransactionFilter struct {
+	Type       string
+	CategoryID int
+	MinAmount  models.Money
+	MaxAmount  models.Money
+	// Query — подстрока для поиска в описании (без учета регистра)
+	Query string
+	// Tags — имена тегов; транзакция подходит, если у нее есть хотя бы один из них
+	Tags []string
+	// IDs — ограничивает отбор транзакциями с указанными ID
+	IDs []int
+	// DateFrom и DateTo — границы периода включительно; нулевое значение не ограничивает
+	DateFrom time.Time
+	DateTo   time.Time
+	// Deleted — отбирать транзакции из корзины вместо действующих
+	Deleted bool
+	// IncludePlanned — включать запланированные транзакции, которые по умолчанию не отбираются
+	IncludePlanned bool
+	// Status — статус сверки: "pending", "cleared" или "reconciled"
+	Status  string
+	PayeeID int
+	// PossibleDuplicate — отбирать только транзакции, отмеченные как возможные дубликаты
+	PossibleDuplicate bool
+	// Flagged — отбирать только транзакции, отмеченные для проверки
+	Flagged bool
+	// Sort — сортировка по дате: "asc", "desc" или пусто
+	Sort string
+	// SortBy — сортировка по нескольким ключам; если задана, Sort не используется
+	SortBy []SortKey
+}
+
+// SortKey — ключ сортировки списка транзакций.
+type SortKey struct {
+	// Field — "date", "amount" или "category" (по названию категории)
+	Field string
+	Desc  bool
+}
+
+// sortColumns — допустимые ключи сортировки и соответствующие им выражения SQL.
+var sortColumns = map[string]string{
+	"date":     "date",
+	"amount":   "amount",
+	"category": "(SELECT name FROM categories WHERE categories.id = transactions.category_id)",
+}
+
+// orderBy строит ORDER BY по ключам из белого списка. ID добавляется последним ключом,
+// чтобы порядок был однозначным при равных значениях.
+func orderBy(keys []SortKey) (string, error) {
+	terms := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		column, ok := sortColumns[key.Field]
+		if !ok {
+			return "", fmt.Errorf("invalid sort field: %s", key.Field)
+		}
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
 		}
-		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)+1))
-		args = append(args, filterType)
+		terms = append(terms, column+" "+direction)
+	}
+	terms = append(terms, "id ASC")
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// likeEscaper экранирует спецсимволы шаблона LIKE в пользовательском вводе.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// transactionWhere строит условие WHERE с именованными параметрами (:user_id, :type, ...) для фильтра
+// транзакций пользователя и значения этих параметров. Запрос с условием передается в bindNamed.
+func (s *Storage) transactionWhere(userID int, filter TransactionFilter) (string, map[string]interface{}, error) {
+	params := map[string]interface{}{"user_id": userID}
+	conditions := []string{"user_id = :user_id", "deleted_at IS NULL"}
+	if filter.Deleted {
+		conditions[1] = "deleted_at IS NOT NULL"
+	}
+	if !filter.IncludePlanned {
+		conditions = append(conditions, "NOT planned")
+	}
+
+	if filter.Type != "" {
+		if filter.Type != "income" && filter.Type != "expense" {
+			return "", nil, fmt.Errorf("invalid type filter: must be 'income' or 'expense'")
+		}
+		conditions = append(conditions, "type = :type")
+		params["type"] = filter.Type
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = :status")
+		params["status"] = filter.Status
 	}
 
-	if filterCategoryID > 0 {
+	if filter.CategoryID > 0 {
 		// Проверяем, существует ли категория и принадлежит ли она пользователю
 		var exists bool
-		err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND user_id = $2)", filterCategoryID, userID).Scan(&exists)
+		err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND "+visibleCategory(2)+")", filter.CategoryID, userID).Scan(&exists)
 		if err != nil {
-			return nil, 0, err
+			return "", nil, err
 		}
 		if !exists {
-			return nil, 0, fmt.Errorf("category does not exist or does not belong to user")
+			return "", nil, fmt.Errorf("category does not exist or does not belong to user")
 		}
-		conditions = append(conditions, fmt.Sprintf("category_id = $%d", len(args)+1))
-		args = append(args, filterCategoryID)
+		conditions = append(conditions, "category_id = :category_id")
+		params["category_id"] = filter.CategoryID
 	}
 
-	if minAmount > 0 {
-		conditions = append(conditions, fmt.Sprintf("amount >= $%d", len(args)+1))
-		args = append(args, minAmount)
+	if filter.PossibleDuplicate {
+		conditions = append(conditions, "possible_duplicate")
 	}
 
-	if maxAmount > 0 {
-		conditions = append(conditions, fmt.Sprintf("amount <= $%d", len(args)+1))
-		args = append(args, maxAmount)
+	if filter.Flagged {
+		conditions = append(conditions, "flagged")
 	}
 
-	if len(conditions) > 0 {
-		countQuery += " AND " + strings.Join(conditions, " AND ")
+	if filter.PayeeID > 0 {
+		conditions = append(conditions, "payee_id = :payee_id")
+		params["payee_id"] = filter.PayeeID
 	}
 
+	if filter.MinAmount > 0 {
+		conditions = append(conditions, "amount >= :min_amount")
+		params["min_amount"] = filter.MinAmount
+	}
+
+	if filter.MaxAmount > 0 {
+		conditions = append(conditions, "amount <= :max_amount")
+		params["max_amount"] = filter.MaxAmount
+	}
+
+	if filter.Query != "" {
+		conditions = append(conditions, "description ILIKE '%' || :query || '%'")
+		params["query"] = likeEscaper.Replace(filter.Query)
+	}
+
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, `id IN (SELECT tt.transaction_id FROM transaction_tags tt
+			JOIN tags tg ON tg.id = tt.tag_id WHERE tg.user_id = :user_id AND tg.name = ANY(:tags))`)
+		params["tags"] = pq.Array(filter.Tags)
+	}
+
+	if len(filter.IDs) > 0 {
+		conditions = append(conditions, "id = ANY(:ids)")
+		params["ids"] = pq.Array(filter.IDs)
+	}
+
+	if !filter.DateFrom.IsZero() {
+		conditions = append(conditions, "date >= :date_from")
+		params["date_from"] = filter.DateFrom
+	}
+
+	if !filter.DateTo.IsZero() {
+		conditions = append(conditions, "date <= :date_to")
+		params["date_to"] = filter.DateTo
+	}
+
+	return strings.Join(conditions, " AND "), params, nil
+}
+
+// bindNamed заменяет именованные параметры запроса позиционными параметрами PostgreSQL
+// и возвращает их значения в порядке следования.
+func bindNamed(query string, params map[string]interface{}) (string, []interface{}, error) {
+	query, args, err := sqlx.Named(query, params)
+	if err != nil {
+		return "", nil, err
+	}
+	return sqlx.Rebind(sqlx.DOLLAR, query), args, nil
+}
+
+// countTransactions возвращает число транзакций, подходящих под условие transactionWhere.
+func (s *Storage) countTransactions(where string, params map[string]interface{}) (int, error) {
+	query, args, err := bindNamed("SELECT COUNT(*) FROM transactions WHERE "+where, params)
+	if err != nil {
+		return 0, err
+	}
 	var total int
-	err := s.DB.QueryRow(countQuery, args...).Scan(&total)
+	err = s.sqlx().Get(&total, query, args...)
+	return total, err
+}
+
+func (s *Storage) GetTransactions(userID int, filter TransactionFilter, page, limit int) ([]models.Transaction, int, error) {
+	where, params, err := s.transactionWhere(userID, filter)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Запрос транзакций с пагинацией
-	query := "SELECT id, user_id, amount, type, category_id, date FROM transactions WHERE user_id = $1"
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
+	var order string
+	if len(filter.SortBy) > 0 {
+		if order, err = orderBy(filter.SortBy); err != nil {
+			return nil, 0, err
+		}
+	} else if filter.Sort == "asc" || filter.Sort == "desc" {
+		order = " ORDER BY date " + filter.Sort
+	} else if filter.Sort != "" {
+		return nil, 0, fmt.Errorf("invalid sort parameter: must be 'asc' or 'desc'")
 	}
 
-	if sort == "asc" || sort == "desc" {
-		query += fmt.Sprintf(" ORDER BY date %s", sort)
-	} else if sort != "" {
-		return nil, 0, fmt.Errorf("invalid sort parameter: must be 'asc' or 'desc'")
+	// Запрос транзакций с пагинацией. Общее число транзакций считает оконная функция:
+	// она вычисляется до LIMIT и OFFSET, поэтому отдельный COUNT не нужен
+	params["limit"], params["offset"] = limit, (page-1)*limit
+	query, args, err := bindNamed("SELECT "+transactionColumns+", COUNT(*) OVER() AS total_count FROM transactions WHERE "+where+order+
+		" LIMIT :limit OFFSET :offset", params)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
-	args = append(args, limit, (page-1)*limit)
+	var rows []pagedTransactionRow
+	if err := s.sqlx().Select(&rows, query, args...); err != nil {
+		return nil, 0, err
+	}
+	transactions := make([]models.Transaction, len(rows))
+	for i, r := range rows {
+		transactions[i] = r.transaction()
+	}
+	if len(rows) > 0 {
+		return transactions, rows[0].Total, nil
+	}
+	// Страница за концом списка пуста, и общее число приходится считать отдельно
+	if page <= 1 {
+		return transactions, 0, nil
+	}
+	total, err := s.countTransactions(where, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
 
-	rows, err := s.DB.Query(query, args...)
+// TransactionCursor — позиция в списке транзакций для keyset-пагинации:
+// дата и ID последней полученной транзакции.
+type TransactionCursor struct {
+	Date time.Time
+	ID   int
+}
+
+// GetTransactionsAfter возвращает до limit транзакций, следующих за курсором after
+// (nil — с начала списка), в порядке (date, id) по filter.Sort; по умолчанию от новых к старым.
+// В отличие от OFFSET, страницы не сдвигаются при добавлении и удалении транзакций.
+func (s *Storage) GetTransactionsAfter(userID int, filter TransactionFilter, after *TransactionCursor, limit int) ([]models.Transaction, int, error) {
+	where, params, err := s.transactionWhere(userID, filter)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	var transactions = []models.Transaction{}
-	for rows.Next() {
-		var t models.Transaction
-		var categoryID sql.NullInt32
-		err := rows.Scan(&t.ID, &t.UserID, &t.Amount, &t.Type, &categoryID, &t.Date)
-		if err != nil {
-			return nil, 0, err
+	total, err := s.countTransactions(where, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort := filter.Sort
+	if len(filter.SortBy) > 0 {
+		// Курсор хранит только дату и ID, поэтому других ключей сортировки он не поддерживает
+		if len(filter.SortBy) != 1 || filter.SortBy[0].Field != "date" {
+			return nil, 0, fmt.Errorf("cursor pagination supports only sorting by date")
 		}
-		if categoryID.Valid {
-			t.CategoryID = int(categoryID.Int32)
+		sort = "asc"
+		if filter.SortBy[0].Desc {
+			sort = "desc"
 		}
-		transactions = append(transactions, t)
+	}
+
+	order, cmp := "DESC", "<"
+	switch sort {
+	case "asc":
+		order, cmp = "ASC", ">"
+	case "", "desc":
+	default:
+		return nil, 0, fmt.Errorf("invalid sort parameter: must be 'asc' or 'desc'")
+	}
+
+	if after != nil {
+		where += " AND (date, id) " + cmp + " (:after_date, :after_id)"
+		params["after_date"], params["after_id"] = after.Date, after.ID
+	}
+
+	params["limit"] = limit
+	query, args, err := bindNamed(fmt.Sprintf("SELECT %s FROM transactions WHERE %s ORDER BY date %s, id %s LIMIT :limit",
+		transactionColumns, where, order, order), params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	transactions, err := s.queryTransactions(query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	return transactions, total, nil
 }
 
+// queryTransactions выполняет запрос, выбирающий столбцы transactionColumns.
+func (s *Storage) queryTransactions(query string, args ...interface{}) ([]models.Transaction, error) {
+	var rows []transactionRow
+	if err := s.sqlx().Select(&rows, query, args...); err != nil {
+		return nil, err
+	}
+	transactions := make([]models.Transaction, len(rows))
+	for i, r := range rows {
+		transactions[i] = r.transaction()
+	}
+	return transactions, nil
+}
+
+// GetTransactionTotals возвращает суммы доходов и расходов по фильтру, сгруппированные по валюте.
+// Возвраты, привязанные к расходам, вычитаются из расхода и не входят в доход.
+func (s *Storage) GetTransactionTotals(userID int, filter TransactionFilter) ([]models.TransactionTotals, error) {
+	where, params, err := s.transactionWhere(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := bindNamed(`SELECT currency, `+netTotalsColumns("")+`
+		FROM transactions WHERE `+where+` GROUP BY currency ORDER BY currency`, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []models.TransactionTotals{}
+	for rows.Next() {
+		var t models.TransactionTotals
+		if err := rows.Scan(&t.Currency, &t.Income, &t.Expense); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
 func (s *Storage) GetTransaction(id, userID int) (*models.Transaction, error) {
-	var t models.Transaction
-	var categoryID sql.NullInt32
-	row := s.DB.QueryRow("SELECT id, user_id, amount, type, category_id, date FROM transactions WHERE id = $1 AND user_id = $2", id, userID)
-	err := row.Scan(&t.ID, &t.UserID, &t.Amount, &t.Type, &categoryID, &t.Date)
+	row := s.DB.QueryRow("SELECT "+transactionColumns+" FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL", id, userID)
+	t, err := scanTransaction(row)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
+	return &t, nil
+}
 
-	if categoryID.Valid {
-		t.CategoryID = int(categoryID.Int32)
+// ForEachTransaction последовательно передает в fn все транзакции пользователя,
+// читая их из курсора без загрузки всего набора в память.
+func (s *Storage) ForEachTransaction(userID int, fn func(models.Transaction) error) error {
+	return s.ForEachFilteredTransaction(context.Background(), userID, TransactionFilter{IncludePlanned: true}, fn)
+}
+
+// ForEachFilteredTransaction передает в fn транзакции пользователя по фильтру в порядке даты.
+// Следующая строка читается из соединения только после возврата fn, поэтому медленный
+// получатель не приводит к накоплению результата в памяти. Отмена ctx прерывает запрос.
+func (s *Storage) ForEachFilteredTransaction(ctx context.Context, userID int, filter TransactionFilter, fn func(models.Transaction) error) error {
+	where, params, err := s.transactionWhere(userID, filter)
+	if err != nil {
+		return err
 	}
-	return &t, nil
+	query, args, err := bindNamed("SELECT "+transactionColumns+" FROM transactions WHERE "+where+" ORDER BY date, id", params)
+	if err != nil {
+		return err
+	}
+	rows, err := s.sqlx().QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r transactionRow
+		if err := rows.StructScan(&r); err != nil {
+			return err
+		}
+		if err := fn(r.transaction()); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
 func (s *Storage) CreateTransaction(t *models.Transaction) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertTransaction(tx, t); err != nil {
+		return err
+	}
+	return commitCreated(tx, 1)
+}
+
+// CreateTransactions создает несколько транзакций в одной транзакции БД:
+// при ошибке в любой из них не сохраняется ни одна. Транзакции записываются
+// командой COPY, поэтому даже большой импорт занимает несколько запросов.
+func (s *Storage) CreateTransactions(ts []*models.Transaction) error {
+	return s.CreateTransactionsWithCategories(ts, nil)
+}
+
+// CreateTransactionsWithCategories создает транзакции, как CreateTransactions, и в той же транзакции БД
+// создает недостающие категории: транзакция ts[i] с непустым categories[i] получает новую категорию
+// с этим именем, одну на все имена, совпадающие без учета регистра. Если не удалось создать хотя бы
+// одну транзакцию, не создаются и категории.
+func (s *Storage) CreateTransactionsWithCategories(ts []*models.Transaction, categories []string) error {
+	// При повторе транзакции БД транзакции заполняются заново с исходных значений
+	original := make([]models.Transaction, len(ts))
+	for i, t := range ts {
+		original[i] = *t
+	}
+	err := s.WithTx(sql.LevelReadCommitted, func(tx *sql.Tx) error {
+		created := make(map[string]int)
+		for i, t := range ts {
+			*t = original[i]
+			if i >= len(categories) || categories[i] == "" {
+				continue
+			}
+			key := strings.ToLower(categories[i])
+			if _, ok := created[key]; !ok {
+				var id int
+				err := tx.QueryRow("INSERT INTO categories (user_id, name) VALUES ($1, $2) RETURNING id", t.UserID, categories[i]).Scan(&id)
+				if err != nil {
+					return err
+				}
+				created[key] = id
+			}
+			t.CategoryID = created[key]
+		}
+
+		checks := newBulkChecks(tx)
+		for i, t := range ts {
+			if err := checks.prepare(t); err != nil {
+				return fmt.Errorf("transaction %d: %w", i, err)
+			}
+		}
+		return copyTransactions(tx, ts)
+	})
+	if err != nil {
+		return err
+	}
+	metrics.TransactionsCreated.Add(float64(len(ts)))
+	return nil
+}
+
+// commitCreated фиксирует транзакцию БД, в которой созданы n транзакций пользователя,
+// и учитывает их в метриках.
+func commitCreated(tx *sql.Tx, n int) error {
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	metrics.TransactionsCreated.Add(float64(n))
+	return nil
+}
+
+func insertTransaction(tx *sql.Tx, t *models.Transaction) error {
 	if t.UserID == 0 {
 		return fmt.Errorf("user_id is required")
 	}
@@ -298,7 +860,7 @@ func (s *Storage) CreateTransaction(t *models.Transaction) error {
 	}
 
 	var exists bool
-	err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND user_id = $2)", t.CategoryID, t.UserID).Scan(&exists)
+	err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND "+visibleCategory(2)+")", t.CategoryID, t.UserID).Scan(&exists)
 	if err != nil {
 		return err
 	}
@@ -309,13 +871,52 @@ func (s *Storage) CreateTransaction(t *models.Transaction) error {
 	if t.Date.IsZero() {
 		t.Date = time.Now()
 	}
-	return s.DB.QueryRow("INSERT INTO transactions (user_id, amount, type, category_id, date) VALUES ($1, $2, $3, $4, $5) RETURNING id",
-		t.UserID, t.Amount, t.Type, t.CategoryID, t.Date).
-		Scan(&t.ID)
+
+	if err := resolvePayee(tx, t); err != nil {
+		return err
+	}
+
+	if t.DuplicateOf, err = findDuplicates(tx, t); err != nil {
+		return err
+	}
+	t.PossibleDuplicate = len(t.DuplicateOf) > 0
+
+	deriveFXRate(t)
+	if err := checkAccount(tx, t); err != nil {
+		return err
+	}
+
+	// Без явной валюты транзакция записывается в базовой валюте пользователя
+	err = tx.QueryRow(`INSERT INTO transactions (user_id, amount, type, category_id, date, description, currency, planned, status, payee_id, possible_duplicate, linked_transaction_id, flagged,
+		original_amount, original_currency, fx_rate, account_id)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE(NULLIF($7, ''), (SELECT base_currency FROM users WHERE id = $1)), $8,
+		COALESCE(NULLIF($9, ''), 'cleared'), NULLIF($10, 0), $11, NULLIF($12, 0), $13,
+		NULLIF($14::numeric, 0), NULLIF($15, ''), NULLIF($16::numeric, 0), NULLIF($17, 0)) RETURNING id, currency, status`,
+		t.UserID, t.Amount, t.Type, t.CategoryID, t.Date, t.Description, t.Currency, t.Planned, t.Status, t.PayeeID, t.PossibleDuplicate, t.LinkedTransactionID,
+		t.Flagged, t.OriginalAmount, t.OriginalCurrency, t.FXRate, t.AccountID).
+		Scan(&t.ID, &t.Currency, &t.Status)
+	if err != nil {
+		return err
+	}
+	if err := checkLinkedTransaction(tx, t); err != nil {
+		return err
+	}
+
+	if t.Tags, err = setTransactionTags(tx, t.UserID, t.ID, t.Tags); err != nil {
+		return err
+	}
+	return recordVersion(tx, t.ID)
 }
 
+// DeleteTransaction перемещает транзакцию в корзину; транзакция перевода перемещается вместе с парной.
+// Транзакции закрытых счетов не удаляются.
 func (s *Storage) DeleteTransaction(id, userID int) (bool, error) {
-	result, err := s.DB.Exec("DELETE FROM transactions WHERE id = $1 AND user_id = $2 RETURNING id", id, userID)
+	if err := s.checkOpenAccounts(id, userID); err != nil {
+		return false, err
+	}
+
+	result, err := s.DB.Exec(`UPDATE transactions SET deleted_at = NOW() WHERE user_id = $2 AND deleted_at IS NULL
+		AND (id = $1 OR transfer_id IN (SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL))`, id, userID)
 	if err != nil {
 		return false, err
 	}
@@ -326,6 +927,27 @@ func (s *Storage) DeleteTransaction(id, userID int) (bool, error) {
 	return rowsAffected > 0, nil
 }
 
+// DeleteTransactions перемещает в корзину одним запросом все транзакции пользователя,
+// подходящие под фильтр, вместе с парными транзакциями переводов и возвращает их число.
+// Транзакции закрытых счетов пропускаются.
+func (s *Storage) DeleteTransactions(userID int, filter TransactionFilter) (int64, error) {
+	where, params, err := s.transactionWhere(userID, filter)
+	if err != nil {
+		return 0, err
+	}
+	query, args, err := bindNamed(`UPDATE transactions SET deleted_at = NOW() WHERE user_id = :user_id AND deleted_at IS NULL AND `+inOpenAccounts+`
+		AND (id IN (SELECT id FROM transactions WHERE `+where+`) OR transfer_id IN (SELECT transfer_id FROM transactions WHERE `+where+`))`, params)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.DB.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (s *Storage) UpdateTransaction(t *models.Transaction) (bool, error) {
 	if t.UserID == 0 {
 		return false, fmt.Errorf("user_id is required")
@@ -333,7 +955,7 @@ func (s *Storage) UpdateTransaction(t *models.Transaction) (bool, error) {
 
 	if t.CategoryID > 0 {
 		var exists bool
-		err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND user_id = $2)", t.CategoryID, t.UserID).Scan(&exists)
+		err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND "+visibleCategory(2)+")", t.CategoryID, t.UserID).Scan(&exists)
 		if err != nil {
 			return false, err
 		}
@@ -342,17 +964,68 @@ func (s *Storage) UpdateTransaction(t *models.Transaction) (bool, error) {
 		}
 	}
 
-	result, err := s.DB.Exec("UPDATE transactions SET amount = $1, type = $2, category_id = $3, date = $4 WHERE id = $5 AND user_id = $6",
-		t.Amount, t.Type, t.CategoryID, t.Date, t.ID, t.UserID)
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
 
+	// Блокируем транзакцию, чтобы версии истории шли по порядку
+	var transfer, adjustment, open bool
+	err = tx.QueryRow("SELECT id, transfer_id IS NOT NULL, adjustment, "+inOpenAccounts+" FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE", t.ID, t.UserID).
+		Scan(&t.ID, &transfer, &adjustment, &open)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
+	if transfer {
+		return false, fmt.Errorf("transfer transactions cannot be edited, delete the transfer instead")
+	}
+	if adjustment {
+		return false, fmt.Errorf("adjustment transactions cannot be edited, delete the adjustment instead")
+	}
+	if !open {
+		return false, fmt.Errorf("account is closed")
+	}
+	if err := recordInitialVersion(tx, t.ID); err != nil {
+		return false, err
+	}
 
-	rowsAffected, err := result.RowsAffected()
+	if err := resolvePayee(tx, t); err != nil {
+		return false, err
+	}
+	deriveFXRate(t)
+	if err := checkAccount(tx, t); err != nil {
+		return false, err
+	}
+
+	// Без явной валюты и статуса сохраняются прежние; отметка flagged меняется только переключением
+	err = tx.QueryRow(`UPDATE transactions SET amount = $1, type = $2, category_id = $3, date = $4, description = $5,
+		currency = COALESCE(NULLIF($6, ''), currency), planned = $7, status = COALESCE(NULLIF($8, ''), status), payee_id = NULLIF($9, 0),
+		linked_transaction_id = NULLIF($10, 0),
+		original_amount = NULLIF($11::numeric, 0), original_currency = NULLIF($12, ''), fx_rate = NULLIF($13::numeric, 0),
+		account_id = NULLIF($14, 0)
+		WHERE id = $15 AND user_id = $16 AND deleted_at IS NULL RETURNING currency, status, flagged`,
+		t.Amount, t.Type, t.CategoryID, t.Date, t.Description, t.Currency, t.Planned, t.Status, t.PayeeID, t.LinkedTransactionID,
+		t.OriginalAmount, t.OriginalCurrency, t.FXRate, t.AccountID, t.ID, t.UserID).
+		Scan(&t.Currency, &t.Status, &t.Flagged)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
+	if err := checkLinkedTransaction(tx, t); err != nil {
+		return false, err
+	}
 
-	return rowsAffected > 0, nil
+	if t.Tags, err = setTransactionTags(tx, t.UserID, t.ID, t.Tags); err != nil {
+		return false, err
+	}
+	if err := recordVersion(tx, t.ID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
 }
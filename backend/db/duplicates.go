@@ -0,0 +1,96 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// findDuplicates возвращает ID действующих транзакций пользователя, похожих на t:
+// та же сумма, тип, валюта и контрагент, дата отличается не больше чем на сутки.
+// Запланированные транзакции не проверяются и не считаются дубликатами.
+func findDuplicates(tx *sql.Tx, t *models.Transaction) ([]int, error) {
+	if t.Planned {
+		return nil, nil
+	}
+
+	var ids []int64
+	err := tx.QueryRow(`SELECT ARRAY(SELECT id FROM transactions
+		WHERE user_id = $1 AND amount = $2 AND type = $3
+			AND currency = COALESCE(NULLIF($4, ''), (SELECT base_currency FROM users WHERE id = $1))
+			AND payee_id IS NOT DISTINCT FROM NULLIF($5, 0)
+			AND date BETWEEN $6::timestamp - INTERVAL '1 day' AND $6::timestamp + INTERVAL '1 day'
+			AND deleted_at IS NULL AND NOT planned
+		ORDER BY id)`,
+		t.UserID, t.Amount, t.Type, t.Currency, t.PayeeID, t.Date).Scan(pq.Array(&ids))
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make([]int, len(ids))
+	for i, id := range ids {
+		duplicates[i] = int(id)
+	}
+	return duplicates, nil
+}
+
+// FindDuplicates отмечает транзакции, похожие на уже сохраненные, не создавая их:
+// заполняет DuplicateOf и PossibleDuplicate так же, как при создании.
+func (s *Storage) FindDuplicates(ts []*models.Transaction) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	// Контрагенты, созданные для сравнения, откатываются вместе с транзакцией БД
+	defer tx.Rollback()
+
+	for _, t := range ts {
+		payeeID := t.PayeeID
+		if err := resolvePayee(tx, t); err != nil {
+			return err
+		}
+		if t.DuplicateOf, err = findDuplicates(tx, t); err != nil {
+			return err
+		}
+		t.PossibleDuplicate = len(t.DuplicateOf) > 0
+		t.PayeeID = payeeID
+	}
+	return nil
+}
+
+// CreateTransactionUnlessDuplicate создает транзакцию, только если у пользователя нет похожих на нее.
+// Иначе транзакция не создается и возвращаются ID похожих транзакций.
+func (s *Storage) CreateTransactionUnlessDuplicate(t *models.Transaction) ([]int, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := insertTransaction(tx, t); err != nil {
+		return nil, err
+	}
+	if t.PossibleDuplicate {
+		return t.DuplicateOf, nil
+	}
+	return nil, commitCreated(tx, 1)
+}
+
+// ResolvePossibleDuplicate снимает отметку о возможном дубликате. При confirm транзакция признается
+// дубликатом и перемещается в корзину. Возвращает false, если отмеченной транзакции нет.
+func (s *Storage) ResolvePossibleDuplicate(id, userID int, confirm bool) (bool, error) {
+	query := "UPDATE transactions SET possible_duplicate = false"
+	if confirm {
+		query += ", deleted_at = NOW()"
+	}
+	result, err := s.DB.Exec(query+" WHERE id = $1 AND user_id = $2 AND possible_duplicate AND deleted_at IS NULL", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// CreateEmailChange создает запрос на смену email и возвращает токен подтверждения.
+// Запрос на текущий email пользователя подтверждает этот адрес.
+func (s *Storage) CreateEmailChange(userID int, newEmail string, ttl time.Duration) (string, error) {
+	newEmail = NormalizeEmail(newEmail)
+	var taken bool
+	err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = $1 AND id <> $2)", newEmail, userID).Scan(&taken)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", fmt.Errorf("email is already in use")
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO email_changes (user_id, old_email, new_email, token_hash, expires_at)
+		SELECT id, email, $2, $3, $4 FROM users WHERE id = $1`,
+		userID, newEmail, hash, time.Now().Add(ttl))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConfirmEmailChange применяет запрос на смену email по токену, отмечает адрес подтвержденным и возвращает
+// обновленного пользователя. Если токен не найден, использован или истек, возвращается nil.
+func (s *Storage) ConfirmEmailChange(token string) (*models.User, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var changeID, userID int
+	var newEmail string
+	err = tx.QueryRow(`UPDATE email_changes SET confirmed_at = NOW()
+		WHERE token_hash = $1 AND confirmed_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, new_email`, hashToken(token)).Scan(&changeID, &userID, &newEmail)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	err = tx.QueryRow("UPDATE users SET email = $1, email_verified = TRUE WHERE id = $2 RETURNING id, username, email, email_verified, role",
+		NormalizeEmail(newEmail), userID).
+		Scan(&user.ID, &user.Username, &user.Email, &user.EmailVerified, &user.Role)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("email is already in use")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// MarkEmailVerified отмечает текущий email пользователя подтвержденным: пользователь доказал,
+// что получает на него письма, например перешел по ссылке для входа.
+func (s *Storage) MarkEmailVerified(userID int) error {
+	_, err := s.DB.Exec("UPDATE users SET email_verified = TRUE WHERE id = $1 AND email IS NOT NULL", userID)
+	return err
+}
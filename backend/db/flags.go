@@ -0,0 +1,41 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// ToggleTransactionFlag переключает отметку flagged у действующей транзакции пользователя
+// и возвращает транзакцию после изменения. Для чужой или несуществующей транзакции возвращается nil.
+func (s *Storage) ToggleTransactionFlag(id, userID int) (*models.Transaction, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow("SELECT id FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE", id, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Переключение отметки сохраняется версией истории, как и другие изменения транзакции
+	if err := recordInitialVersion(tx, id); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("UPDATE transactions SET flagged = NOT flagged WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+	if err := recordVersion(tx, id); err != nil {
+		return nil, err
+	}
+
+	t, err := scanTransaction(tx.QueryRow("SELECT "+transactionColumns+" FROM transactions WHERE id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	return &t, tx.Commit()
+}
@@ -0,0 +1,16 @@
+package db
+
+import (
+	"math"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// deriveFXRate вычисляет курс по суммам, если задана сумма в валюте покупки, а курс не передан.
+// Курс округляется до 8 знаков — масштаба столбца fx_rate.
+func deriveFXRate(t *models.Transaction) {
+	if t.OriginalAmount == 0 || t.FXRate != 0 {
+		return
+	}
+	t.FXRate = math.Round(float64(t.Amount)/float64(t.OriginalAmount)*1e8) / 1e8
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// goalContributions присоединяет к цели g ее действующие взносы gc и их транзакции t.
+// Взнос-транзакция не учитывается, пока транзакция удалена или ее валюта отличается от валюты цели.
+const goalContributions = `LEFT JOIN (goal_contributions gc LEFT JOIN transactions t ON t.id = gc.transaction_id)
+		ON gc.goal_id = g.id AND (gc.transaction_id IS NULL OR (t.deleted_at IS NULL AND t.currency = g.currency))`
+
+const goalQuery = `SELECT g.id, g.user_id, g.name, g.target, g.currency, g.deadline, g.account_id, g.created_at,
+		COALESCE(SUM(COALESCE(gc.amount, t.amount)), 0), MIN(COALESCE(gc.date, t.date::date))
+		FROM goals g ` + goalContributions
+
+func (s *Storage) queryGoals(query string, args ...interface{}) ([]models.Goal, error) {
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []models.Goal{}
+	for rows.Next() {
+		var g models.Goal
+		var deadline, firstContribution sql.NullTime
+		var accountID sql.NullInt32
+		if err := rows.Scan(&g.ID, &g.UserID, &g.Name, &g.Target, &g.Currency, &deadline, &accountID, &g.CreatedAt,
+			&g.Saved, &firstContribution); err != nil {
+			return nil, err
+		}
+		if deadline.Valid {
+			g.Deadline = &deadline.Time
+		}
+		if firstContribution.Valid {
+			g.FirstContributionDate = &firstContribution.Time
+		}
+		g.AccountID = int(accountID.Int32)
+		goals = append(goals, g)
+	}
+	return goals, rows.Err()
+}
+
+// CreateGoal создает цель накоплений; без валюты — в базовой валюте пользователя.
+func (s *Storage) CreateGoal(userID int, request models.CreateGoal) (*models.Goal, error) {
+	var id int
+	err := s.DB.QueryRow(`INSERT INTO goals (user_id, name, target, currency, deadline, account_id)
+		VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), (SELECT base_currency FROM users WHERE id = $1)), $5, NULLIF($6, 0))
+		RETURNING id`, userID, request.Name, request.Target, request.Currency, request.Deadline, request.AccountID).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetGoal(id, userID)
+}
+
+// GetGoals возвращает цели пользователя с суммой взносов.
+func (s *Storage) GetGoals(userID int) ([]models.Goal, error) {
+	return s.queryGoals(goalQuery+` WHERE g.user_id = $1 GROUP BY g.id ORDER BY g.deadline NULLS LAST, g.id`, userID)
+}
+
+// GetGoal возвращает цель пользователя с суммой взносов или nil, если ее нет.
+func (s *Storage) GetGoal(id, userID int) (*models.Goal, error) {
+	goals, err := s.queryGoals(goalQuery+` WHERE g.id = $1 AND g.user_id = $2 GROUP BY g.id`, id, userID)
+	if err != nil || len(goals) == 0 {
+		return nil, err
+	}
+	return &goals[0], nil
+}
+
+func (s *Storage) UpdateGoal(id, userID int, request models.UpdateGoal) (bool, error) {
+	result, err := s.DB.Exec(`UPDATE goals SET name = $1, target = $2, deadline = $3, account_id = NULLIF($4, 0)
+		WHERE id = $5 AND user_id = $6`, request.Name, request.Target, request.Deadline, request.AccountID, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (s *Storage) DeleteGoal(id, userID int) (bool, error) {
+	result, err := s.DB.Exec("DELETE FROM goals WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetGoalContributions возвращает действующие взносы в цель пользователя по дате.
+func (s *Storage) GetGoalContributions(goalID, userID int) ([]models.GoalContribution, error) {
+	rows, err := s.DB.Query(`SELECT gc.id, gc.goal_id, COALESCE(gc.amount, t.amount), COALESCE(gc.date, t.date::date),
+		CASE WHEN gc.transaction_id IS NULL THEN gc.description ELSE t.description END, gc.transaction_id, gc.created_at
+		FROM goals g `+goalContributions+` WHERE gc.id IS NOT NULL AND g.id = $1 AND g.user_id = $2
+		ORDER BY 4, gc.id`, goalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contributions := []models.GoalContribution{}
+	for rows.Next() {
+		var gc models.GoalContribution
+		var transactionID sql.NullInt32
+		if err := rows.Scan(&gc.ID, &gc.GoalID, &gc.Amount, &gc.Date, &gc.Description, &transactionID, &gc.CreatedAt); err != nil {
+			return nil, err
+		}
+		gc.TransactionID = int(transactionID.Int32)
+		contributions = append(contributions, gc)
+	}
+	return contributions, rows.Err()
+}
+
+// CreateGoalContribution добавляет взнос в цель пользователя. Взносом-транзакцией может быть только действующая
+// транзакция пользователя в валюте цели, а у цели со счетом — транзакция этого счета. Возвращает nil, если цели нет.
+func (s *Storage) CreateGoalContribution(goalID, userID int, request models.CreateGoalContribution) (*models.GoalContribution, error) {
+	goal, err := s.GetGoal(goalID, userID)
+	if err != nil || goal == nil {
+		return nil, err
+	}
+
+	gc := &models.GoalContribution{GoalID: goalID, Amount: request.Amount, Description: request.Description, TransactionID: request.TransactionID}
+	if request.TransactionID == 0 {
+		date := time.Now().UTC()
+		if request.Date != nil {
+			date = *request.Date
+		}
+		err = s.DB.QueryRow(`INSERT INTO goal_contributions (goal_id, amount, date, description) VALUES ($1, $2, $3::date, $4)
+			RETURNING id, date, created_at`, goalID, request.Amount, date, request.Description).Scan(&gc.ID, &gc.Date, &gc.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		return gc, nil
+	}
+
+	var currency string
+	var planned bool
+	var accountID sql.NullInt32
+	err = s.DB.QueryRow(`SELECT amount, date::date, description, currency, planned, account_id FROM transactions
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, request.TransactionID, userID).
+		Scan(&gc.Amount, &gc.Date, &gc.Description, &currency, &planned, &accountID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("transaction %d not found", request.TransactionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if planned {
+		return nil, fmt.Errorf("planned transaction cannot be a goal contribution")
+	}
+	if currency != goal.Currency {
+		return nil, fmt.Errorf("transaction currency %s does not match goal currency %s", currency, goal.Currency)
+	}
+	if goal.AccountID != 0 && int(accountID.Int32) != goal.AccountID {
+		return nil, fmt.Errorf("transaction does not belong to the goal account")
+	}
+
+	err = s.DB.QueryRow(`INSERT INTO goal_contributions (goal_id, transaction_id) VALUES ($1, $2) RETURNING id, created_at`,
+		goalID, request.TransactionID).Scan(&gc.ID, &gc.CreatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("transaction %d is already a contribution to the goal", request.TransactionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return gc, nil
+}
+
+func (s *Storage) DeleteGoalContribution(id, goalID, userID int) (bool, error) {
+	result, err := s.DB.Exec(`DELETE FROM goal_contributions gc USING goals g
+		WHERE gc.id = $1 AND gc.goal_id = $2 AND g.id = gc.goal_id AND g.user_id = $3`, id, goalID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// recordVersion сохраняет текущее состояние транзакции следующей версией истории.
+func recordVersion(tx *sql.Tx, transactionID int) error {
+	t, err := scanTransaction(tx.QueryRow("SELECT "+transactionColumns+" FROM transactions WHERE id = $1", transactionID))
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO transaction_history (transaction_id, version, data)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2 FROM transaction_history WHERE transaction_id = $1`,
+		transactionID, data)
+	return err
+}
+
+// recordInitialVersion сохраняет исходное состояние транзакции, созданной до появления истории.
+func recordInitialVersion(tx *sql.Tx, transactionID int) error {
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM transaction_history WHERE transaction_id = $1)", transactionID).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+	return recordVersion(tx, transactionID)
+}
+
+// GetTransactionHistory возвращает версии действующей транзакции пользователя от первой к последней
+// с перечнем полей, изменившихся в каждой версии. Для чужой или несуществующей транзакции возвращается nil.
+func (s *Storage) GetTransactionHistory(id, userID int) ([]models.TransactionVersion, error) {
+	rows, err := s.DB.Query(`SELECT h.version, h.changed_at, h.data FROM transaction_history h
+		JOIN transactions t ON t.id = h.transaction_id
+		WHERE h.transaction_id = $1 AND t.user_id = $2 AND t.deleted_at IS NULL
+		ORDER BY h.version`, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.TransactionVersion
+	var previous map[string]interface{}
+	for rows.Next() {
+		var v models.TransactionVersion
+		var data []byte
+		if err := rows.Scan(&v.Version, &v.ChangedAt, &data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &v.Transaction); err != nil {
+			return nil, err
+		}
+
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		v.ChangedFields = changedFields(previous, fields)
+		previous = fields
+
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// У транзакций, созданных до появления истории, версий может не быть
+	if versions == nil {
+		t, err := s.GetTransaction(id, userID)
+		if err != nil || t == nil {
+			return nil, err
+		}
+		versions = []models.TransactionVersion{}
+	}
+	return versions, nil
+}
+
+// GetTransactionVersion возвращает состояние действующей транзакции пользователя в указанной версии
+// или nil, если такой версии нет.
+func (s *Storage) GetTransactionVersion(id, userID, version int) (*models.Transaction, error) {
+	var data []byte
+	err := s.DB.QueryRow(`SELECT h.data FROM transaction_history h
+		JOIN transactions t ON t.id = h.transaction_id
+		WHERE h.transaction_id = $1 AND t.user_id = $2 AND t.deleted_at IS NULL AND h.version = $3`,
+		id, userID, version).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t models.Transaction
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// changedFields возвращает отсортированные имена полей, значения которых различаются в двух снимках.
+func changedFields(previous, current map[string]interface{}) []string {
+	changed := []string{}
+	if previous == nil {
+		return changed
+	}
+	for key, value := range current {
+		if !reflect.DeepEqual(previous[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,20 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestChangedFields тестирует сравнение снимков транзакции.
+func TestChangedFields(t *testing.T) {
+	previous := map[string]interface{}{"amount": 100.0, "description": "a", "tags": []interface{}{"x"}, "payee": "Лента"}
+	current := map[string]interface{}{"amount": 150.0, "description": "a", "tags": []interface{}{"x", "y"}}
+
+	if got := changedFields(nil, current); len(got) != 0 {
+		t.Errorf("Expected no changed fields for first version, got %v", got)
+	}
+	expected := []string{"amount", "payee", "tags"}
+	if got := changedFields(previous, current); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const holdingColumns = "h.id, h.account_id, h.ticker, h.quantity, h.cost_basis, h.created_at"
+
+// CreateHolding добавляет позицию на инвестиционный или криптовалютный счет пользователя. Возвращает nil, если такого счета нет.
+func (s *Storage) CreateHolding(userID, accountID int, request models.CreateHolding) (*models.Holding, error) {
+	h := &models.Holding{AccountID: accountID, Ticker: request.Ticker, Quantity: request.Quantity, CostBasis: request.CostBasis}
+	err := s.DB.QueryRow(`INSERT INTO holdings (account_id, ticker, quantity, cost_basis)
+		SELECT id, $3, $4, $5 FROM accounts WHERE id = $1 AND `+accountAccess("accounts", "$2", true)+` AND type IN ('investment', 'crypto')
+		RETURNING id, created_at`,
+		accountID, userID, request.Ticker, request.Quantity, request.CostBasis).Scan(&h.ID, &h.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("holding for ticker %s already exists", request.Ticker)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// GetHoldings возвращает позиции счета пользователя или открытого ему счета по тикеру.
+func (s *Storage) GetHoldings(accountID, userID int) ([]models.Holding, error) {
+	return s.queryHoldings(`SELECT `+holdingColumns+` FROM holdings h JOIN accounts a ON a.id = h.account_id
+		WHERE h.account_id = $1 AND `+accountAccess("a", "$2", false)+` ORDER BY h.ticker`, accountID, userID)
+}
+
+// GetUserHoldings возвращает позиции всех счетов пользователя.
+func (s *Storage) GetUserHoldings(userID int) ([]models.Holding, error) {
+	return s.queryHoldings(`SELECT `+holdingColumns+` FROM holdings h JOIN accounts a ON a.id = h.account_id
+		WHERE a.user_id = $1 ORDER BY h.account_id, h.ticker`, userID)
+}
+
+func (s *Storage) queryHoldings(query string, args ...interface{}) ([]models.Holding, error) {
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holdings := []models.Holding{}
+	for rows.Next() {
+		var h models.Holding
+		if err := rows.Scan(&h.ID, &h.AccountID, &h.Ticker, &h.Quantity, &h.CostBasis, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		holdings = append(holdings, h)
+	}
+	return holdings, rows.Err()
+}
+
+// UpdateHolding изменяет количество бумаг и сумму покупки позиции счета пользователя.
+func (s *Storage) UpdateHolding(id, accountID, userID int, request models.UpdateHolding) (bool, error) {
+	result, err := s.DB.Exec(`UPDATE holdings h SET quantity = $1, cost_basis = $2 FROM accounts a
+		WHERE h.id = $3 AND h.account_id = $4 AND a.id = h.account_id AND `+accountAccess("a", "$5", true),
+		request.Quantity, request.CostBasis, id, accountID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (s *Storage) DeleteHolding(id, accountID, userID int) (bool, error) {
+	result, err := s.DB.Exec(`DELETE FROM holdings h USING accounts a
+		WHERE h.id = $1 AND h.account_id = $2 AND a.id = h.account_id AND `+accountAccess("a", "$3", true), id, accountID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetHoldingAccounts возвращает открытые счета всех пользователей, у которых есть позиции.
+func (s *Storage) GetHoldingAccounts() ([]models.Account, error) {
+	return s.queryAccounts(accountSelect + ` WHERE a.closed_at IS NULL AND EXISTS (SELECT 1 FROM holdings h WHERE h.account_id = a.id)
+		GROUP BY a.id ORDER BY a.id`)
+}
+
+// SaveValuation сохраняет стоимость позиций счета на дату date, заменяя снимок за этот день.
+func (s *Storage) SaveValuation(accountID int, date time.Time, costBasis, marketValue models.Money) error {
+	_, err := s.DB.Exec(`INSERT INTO holding_valuations (account_id, date, cost_basis, market_value) VALUES ($1, $2::date, $3, $4)
+		ON CONFLICT (account_id, date) DO UPDATE SET cost_basis = EXCLUDED.cost_basis, market_value = EXCLUDED.market_value`,
+		accountID, date.Format("2006-01-02"), costBasis, marketValue)
+	return err
+}
+
+// GetValuationHistory возвращает сохраненную стоимость позиций счета пользователя за дни с from по to включительно.
+func (s *Storage) GetValuationHistory(accountID, userID int, from, to time.Time) ([]models.ValuationPoint, error) {
+	rows, err := s.DB.Query(`SELECT v.date, a.currency, v.cost_basis, v.market_value
+		FROM holding_valuations v JOIN accounts a ON a.id = v.account_id
+		WHERE v.account_id = $1 AND `+accountAccess("a", "$2", false)+` AND v.date BETWEEN $3::date AND $4::date
+		ORDER BY v.date`, accountID, userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []models.ValuationPoint{}
+	for rows.Next() {
+		var p models.ValuationPoint
+		if err := rows.Scan(&p.Date, &p.Currency, &p.CostBasis, &p.MarketValue); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
@@ -0,0 +1,23 @@
+package db
+
+import "database/sql"
+
+// GetUserIDByIdentity возвращает ID пользователя, привязанного к учетной записи
+// внешнего провайдера, или 0, если привязки нет.
+func (s *Storage) GetUserIDByIdentity(issuer, subject string) (int, error) {
+	var userID int
+	err := s.DB.QueryRow("SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2", issuer, subject).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// LinkIdentity привязывает учетную запись внешнего провайдера к пользователю.
+func (s *Storage) LinkIdentity(userID int, issuer, subject string) error {
+	_, err := s.DB.Exec("INSERT INTO user_identities (user_id, issuer, subject) VALUES ($1, $2, $3)", userID, issuer, subject)
+	return err
+}
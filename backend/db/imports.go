@@ -0,0 +1,115 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/nemopss/fin-ng/backend/metrics"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// CreateImportJob создает задание импорта в статусе pending.
+func (s *Storage) CreateImportJob(userID, totalRows int) (*models.ImportJob, error) {
+	job := &models.ImportJob{Status: "pending", TotalRows: totalRows}
+	err := s.DB.QueryRow("INSERT INTO import_jobs (user_id, total_rows) VALUES ($1, $2) RETURNING id, created_at",
+		userID, totalRows).Scan(&job.ID, &job.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetImportJob возвращает задание импорта пользователя. Для чужого или несуществующего задания возвращается nil.
+func (s *Storage) GetImportJob(id, userID int) (*models.ImportJob, error) {
+	var job models.ImportJob
+	var report []byte
+	var finishedAt sql.NullTime
+	err := s.DB.QueryRow(`SELECT id, status, total_rows, processed_rows, report, error, created_at, finished_at
+		FROM import_jobs WHERE id = $1 AND user_id = $2`, id, userID).
+		Scan(&job.ID, &job.Status, &job.TotalRows, &job.ProcessedRows, &report, &job.Error, &job.CreatedAt, &finishedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if report != nil {
+		if err := json.Unmarshal(report, &job.Report); err != nil {
+			return nil, err
+		}
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
+
+// UpdateImportJobProgress сохраняет прогресс выполняемого задания и возвращает false,
+// если задание тем временем было отменено.
+func (s *Storage) UpdateImportJobProgress(id, processedRows int, report *models.ImportReport) (bool, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return false, err
+	}
+	result, err := s.DB.Exec(`UPDATE import_jobs SET status = 'running', processed_rows = $1, report = $2
+		WHERE id = $3 AND status IN ('pending', 'running')`, processedRows, data, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// FinishImportJob завершает задание со статусом completed или failed. Отчет отмененного задания
+// тоже сохраняется, но его статус не меняется.
+func (s *Storage) FinishImportJob(id int, status string, report *models.ImportReport, errMessage string) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	err = s.DB.QueryRow(`UPDATE import_jobs SET
+		status = CASE WHEN status = 'cancelled' THEN status ELSE $1 END,
+		report = $2, error = $3, finished_at = COALESCE(finished_at, NOW())
+		WHERE id = $4 RETURNING status`, status, data, errMessage, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	metrics.Imports.WithLabelValues(status).Inc()
+	return nil
+}
+
+// CancelImportJob отменяет незавершенное задание пользователя. Возвращает false, если такого задания нет.
+func (s *Storage) CancelImportJob(id, userID int) (bool, error) {
+	result, err := s.DB.Exec(`UPDATE import_jobs SET status = 'cancelled', finished_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND status IN ('pending', 'running')`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// FailInterruptedImportJobs помечает незавершенные задания неудачными. Вызывается при запуске
+// сервера: задания выполняются в памяти процесса и после перезапуска не продолжаются.
+func (s *Storage) FailInterruptedImportJobs() (int64, error) {
+	result, err := s.DB.Exec(`UPDATE import_jobs SET status = 'failed', error = 'import was interrupted by server restart', finished_at = NOW()
+		WHERE status IN ('pending', 'running')`)
+	if err != nil {
+		return 0, err
+	}
+	failed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	metrics.Imports.WithLabelValues("failed").Add(float64(failed))
+	return failed, nil
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+// explain возвращает план запроса. Последовательное чтение запрещено, поэтому план
+// показывает, может ли запрос вообще использовать индекс.
+func explain(t *testing.T, store *Storage, query string, args ...interface{}) string {
+	t.Helper()
+	tx, err := store.DB.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("SET LOCAL enable_seqscan = off"); err != nil {
+		t.Fatalf("SET enable_seqscan failed: %v", err)
+	}
+
+	rows, err := tx.Query("EXPLAIN "+query, args...)
+	if err != nil {
+		t.Fatalf("EXPLAIN failed: %v", err)
+	}
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		plan = append(plan, line)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("EXPLAIN failed: %v", err)
+	}
+	return strings.Join(plan, "\n")
+}
+
+// TestCoreQueryIndexes проверяет по EXPLAIN, что списки транзакций и категорий и вход
+// по имени пользователя используют индексы, а не читают таблицы целиком.
+func TestCoreQueryIndexes(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	for _, stmt := range []string{
+		`INSERT INTO users (username, password) SELECT 'plan_user_' || g, 'x' FROM generate_series(1, 1000) g`,
+		`INSERT INTO categories (user_id, name) SELECT g % 1000 + 1, 'Category ' || g FROM generate_series(1, 5000) g`,
+		`INSERT INTO transactions (user_id, amount, type, category_id, date)
+			SELECT g % 1000 + 1, 100, 'expense', g % 5000 + 1, TIMESTAMP '2024-01-01' + g * INTERVAL '1 minute'
+			FROM generate_series(1, 20000) g`,
+		`ANALYZE users, categories, transactions`,
+	} {
+		if _, err := store.DB.Exec(stmt); err != nil {
+			t.Fatalf("Failed to prepare data: %v", err)
+		}
+	}
+
+	transactionsQuery := func(filter TransactionFilter) (string, []interface{}) {
+		where, params, err := store.transactionWhere(1, filter)
+		if err != nil {
+			t.Fatalf("transactionWhere failed: %v", err)
+		}
+		params["limit"], params["offset"] = 20, 0
+		query, args, err := bindNamed("SELECT "+transactionColumns+" FROM transactions WHERE "+where+
+			" ORDER BY date DESC LIMIT :limit OFFSET :offset", params)
+		if err != nil {
+			t.Fatalf("bindNamed failed: %v", err)
+		}
+		return query, args
+	}
+
+	query, args := transactionsQuery(TransactionFilter{})
+	if plan := explain(t, store, query, args...); !strings.Contains(plan, "transactions_user_id_date_idx") {
+		t.Errorf("Expected transaction list to use transactions_user_id_date_idx, got:\n%s", plan)
+	}
+
+	// Категория 1000 принадлежит пользователю 1
+	query, args = transactionsQuery(TransactionFilter{CategoryID: 1000})
+	if plan := explain(t, store, query, args...); !strings.Contains(plan, "transactions_user_id_category_id_idx") &&
+		!strings.Contains(plan, "transactions_user_id_date_idx") || strings.Contains(plan, "Seq Scan") {
+		t.Errorf("Expected category filter to use a user index, got:\n%s", plan)
+	}
+
+	plan := explain(t, store, "SELECT "+categoryColumns+" FROM categories WHERE "+visibleCategory(1)+" ORDER BY id", 1)
+	if !strings.Contains(plan, "categories_user_id_idx") {
+		t.Errorf("Expected category list to use categories_user_id_idx, got:\n%s", plan)
+	}
+
+	plan = explain(t, store, "SELECT id FROM users WHERE username = $1", "plan_user_1")
+	if !strings.Contains(plan, "users_username_key") {
+		t.Errorf("Expected login lookup to use users_username_key, got:\n%s", plan)
+	}
+}
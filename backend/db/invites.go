@@ -0,0 +1,89 @@
+package db
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// PromoteAdmins назначает роль администратора существующим пользователям с указанными именами.
+func (s *Storage) PromoteAdmins(usernames []string) error {
+	for _, username := range usernames {
+		if _, err := s.DB.Exec("UPDATE users SET role = $1 WHERE username = $2", models.RoleAdmin, username); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateInvite создает приглашение. Нулевой ttl означает бессрочное приглашение.
+func (s *Storage) CreateInvite(createdBy int, ttl time.Duration) (*models.Invite, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	invite := &models.Invite{Code: hex.EncodeToString(buf), CreatedBy: createdBy}
+	var expiresAt sql.NullTime
+	if ttl > 0 {
+		expiresAt = sql.NullTime{Time: time.Now().Add(ttl), Valid: true}
+	}
+
+	err := s.DB.QueryRow("INSERT INTO invites (code, created_by, expires_at) VALUES ($1, $2, $3) RETURNING id, created_at",
+		invite.Code, createdBy, expiresAt).Scan(&invite.ID, &invite.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		invite.ExpiresAt = &expiresAt.Time
+	}
+	return invite, nil
+}
+
+// CreateUserWithInvite создает пользователя, одновременно погашая приглашение.
+// Обе операции выполняются в одной транзакции, поэтому приглашение не сгорает
+// при ошибке создания пользователя и не может быть использовано дважды.
+func (s *Storage) CreateUserWithInvite(username, password, email, code string) (*models.User, error) {
+	user, err := newUser(username, password, email)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var inviteID int
+	err = tx.QueryRow(`SELECT id FROM invites
+		WHERE code = $1 AND used_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		FOR UPDATE`, code).Scan(&inviteID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invalid or expired invite code")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(
+		"INSERT INTO users (username, password, email) VALUES ($1, $2, NULLIF($3, '')) RETURNING id, role, base_currency, month_start, language",
+		user.Username, user.Password, user.Email,
+	).Scan(&user.ID, &user.Role, &user.BaseCurrency, &user.MonthStart, &user.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("UPDATE invites SET used_by = $1, used_at = NOW() WHERE id = $2", user.ID, inviteID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// MaxKeywordLength — максимальная длина ключевого слова категории в символах.
+const MaxKeywordLength = 100
+
+// CreateCategoryKeyword добавляет ключевое слово к категории, доступной пользователю.
+func (s *Storage) CreateCategoryKeyword(userID, categoryID int, keyword string) (*models.CategoryKeyword, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, fmt.Errorf("keyword is required")
+	}
+	if len([]rune(keyword)) > MaxKeywordLength {
+		return nil, fmt.Errorf("keyword must be at most %d characters", MaxKeywordLength)
+	}
+
+	k := &models.CategoryKeyword{CategoryID: categoryID, Keyword: keyword}
+	err := s.DB.QueryRow(`INSERT INTO category_keywords (user_id, category_id, keyword)
+		SELECT $1, id, $3 FROM categories WHERE id = $2 AND `+visibleCategory(1)+` RETURNING id`,
+		userID, categoryID, keyword).Scan(&k.ID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("category does not exist or does not belong to user")
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("keyword already exists")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// GetCategoryKeywords возвращает ключевые слова пользователя; при categoryID > 0 — только для этой категории.
+// Более длинные слова идут первыми: при совпадении нескольких слов выигрывает самое точное.
+func (s *Storage) GetCategoryKeywords(userID, categoryID int) ([]models.CategoryKeyword, error) {
+	rows, err := s.DB.Query(`SELECT id, category_id, keyword FROM category_keywords
+		WHERE user_id = $1 AND ($2 = 0 OR category_id = $2)
+		ORDER BY length(keyword) DESC, id`, userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keywords := []models.CategoryKeyword{}
+	for rows.Next() {
+		var k models.CategoryKeyword
+		if err := rows.Scan(&k.ID, &k.CategoryID, &k.Keyword); err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, k)
+	}
+	return keywords, rows.Err()
+}
+
+// DeleteCategoryKeyword удаляет ключевое слово категории. Возвращает false, если такого слова нет.
+func (s *Storage) DeleteCategoryKeyword(id, categoryID, userID int) (bool, error) {
+	result, err := s.DB.Exec("DELETE FROM category_keywords WHERE id = $1 AND category_id = $2 AND user_id = $3", id, categoryID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// MatchCategoryKeyword возвращает категорию, ключевое слово которой входит в text без учета регистра.
+// Если подходит несколько слов, выбирается самое длинное. Без совпадений возвращается 0.
+func (s *Storage) MatchCategoryKeyword(userID int, text string) (int, error) {
+	var categoryID int
+	err := s.DB.QueryRow(`SELECT category_id FROM category_keywords
+		WHERE user_id = $1 AND strpos(lower($2), lower(keyword)) > 0
+		ORDER BY length(keyword) DESC, id LIMIT 1`, userID, text).Scan(&categoryID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return categoryID, err
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// netTotalsColumns возвращает суммы доходов и расходов, в которых возврат, привязанный к расходу,
+// уменьшает расход вместо того, чтобы увеличивать доход, а переводы между счетами и корректировки остатков не учитываются.
+// prefix — псевдоним таблицы с точкой или пустая строка.
+func netTotalsColumns(prefix string) string {
+	return netIncomeColumn(prefix) + ",\n\t\t" + netExpenseColumn(prefix)
+}
+
+// netIncomeColumn возвращает сумму доходов без возвратов, переводов и корректировок.
+func netIncomeColumn(prefix string) string {
+	return strings.NewReplacer("{t}", prefix).Replace(
+		`COALESCE(SUM({t}amount) FILTER (WHERE {t}type = 'income' AND {t}linked_transaction_id IS NULL AND {t}transfer_id IS NULL AND NOT {t}adjustment), 0)`)
+}
+
+// netExpenseColumn возвращает сумму расходов за вычетом привязанных к ним возвратов, без переводов и корректировок.
+func netExpenseColumn(prefix string) string {
+	return strings.NewReplacer("{t}", prefix).Replace(
+		`COALESCE(SUM(CASE WHEN {t}transfer_id IS NOT NULL OR {t}adjustment THEN NULL WHEN {t}type = 'expense' THEN {t}amount
+			WHEN {t}linked_transaction_id IS NOT NULL THEN -{t}amount END), 0)`)
+}
+
+// checkLinkedTransaction проверяет связь возврата с расходом после записи транзакции t:
+// возвратом может быть только доход, привязанный к действующему расходу пользователя в той же валюте.
+// Расход, к которому уже привязаны возвраты, должен оставаться расходом в прежней валюте.
+func checkLinkedTransaction(tx *sql.Tx, t *models.Transaction) error {
+	if t.LinkedTransactionID != 0 {
+		if t.Type != "income" {
+			return fmt.Errorf("only income can be linked to an expense as a refund")
+		}
+		if t.LinkedTransactionID == t.ID {
+			return fmt.Errorf("transaction cannot be linked to itself")
+		}
+
+		var linkedType, linkedCurrency string
+		err := tx.QueryRow("SELECT type, currency FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL",
+			t.LinkedTransactionID, t.UserID).Scan(&linkedType, &linkedCurrency)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("linked transaction does not exist or does not belong to user")
+		}
+		if err != nil {
+			return err
+		}
+		if linkedType != "expense" {
+			return fmt.Errorf("linked transaction must be an expense")
+		}
+		if linkedCurrency != t.Currency {
+			return fmt.Errorf("refund currency must match the linked expense")
+		}
+	}
+
+	var conflict bool
+	err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM transactions WHERE linked_transaction_id = $1 AND ($2 <> 'expense' OR currency <> $3))",
+		t.ID, t.Type, t.Currency).Scan(&conflict)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return fmt.Errorf("transaction has linked refunds and must remain an expense in the same currency")
+	}
+	return nil
+}
+
+// GetLinkedTransactions возвращает расход и привязанные к нему действующие возвраты.
+// Для возврата возвращается группа его исходного расхода. Для чужой или несуществующей транзакции возвращается nil.
+func (s *Storage) GetLinkedTransactions(id, userID int) (*models.LinkedTransactions, error) {
+	t, err := s.GetTransaction(id, userID)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	if t.LinkedTransactionID != 0 {
+		original, err := s.GetTransaction(t.LinkedTransactionID, userID)
+		if err != nil {
+			return nil, err
+		}
+		// Исходный расход может быть в корзине; тогда показывается только сам возврат
+		if original != nil {
+			t = original
+		}
+	}
+
+	refunds, err := s.queryTransactions("SELECT "+transactionColumns+` FROM transactions
+		WHERE linked_transaction_id = $1 AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY date, id`, t.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.LinkedTransactions{Transaction: *t, Refunds: refunds, NetAmount: t.Amount}
+	for _, refund := range refunds {
+		// Запланированные возвраты еще не уменьшают расход, как и в итогах
+		if !refund.Planned {
+			result.RefundedAmount += refund.Amount
+		}
+	}
+	if t.Type == "expense" {
+		result.NetAmount -= result.RefundedAmount
+	}
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/loan"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// CreateLoanPayment вносит платеж по кредиту loanAccountID: проценты за месяц на остаток долга записываются
+// расходом счета-источника в категории запроса, остальная сумма переводится на счет кредита.
+// Без суммы вносится платеж по графику, но не больше остатка долга с процентами. Возвращает nil, если счета кредита нет.
+func (s *Storage) CreateLoanPayment(userID, loanAccountID int, request models.CreateLoanPayment) (*models.LoanPayment, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Блокируем счет кредита, чтобы параллельные платежи считали проценты от актуального остатка
+	var accountType, currency string
+	var terms models.LoanTerms
+	err = tx.QueryRow(`SELECT type, currency, COALESCE(loan_principal, 0), COALESCE(loan_interest_rate, 0), COALESCE(loan_term_months, 0)
+		FROM accounts WHERE id = $1 AND `+accountAccess("accounts", "$2", true)+` FOR UPDATE`,
+		loanAccountID, userID).Scan(&accountType, &currency, &terms.Principal, &terms.InterestRate, &terms.TermMonths)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if accountType != "loan" {
+		return nil, fmt.Errorf("account is not a loan")
+	}
+	p := &models.LoanPayment{AccountID: loanAccountID, FromAccountID: request.FromAccountID, Amount: request.Amount, Date: request.Date}
+	fromCurrency, err := accountCurrency(tx, p.FromAccountID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if fromCurrency != currency {
+		return nil, fmt.Errorf("from account currency %s does not match loan currency %s", fromCurrency, currency)
+	}
+
+	balance, err := accountBalance(tx, loanAccountID)
+	if err != nil {
+		return nil, err
+	}
+	debt := -balance
+	if debt <= 0 {
+		return nil, fmt.Errorf("loan is already repaid")
+	}
+	p.Interest = loan.MonthlyInterest(debt, terms.InterestRate)
+	if p.Amount == 0 {
+		p.Amount = loan.Payment(terms.Principal, terms.InterestRate, terms.TermMonths)
+		if p.Amount > debt+p.Interest {
+			p.Amount = debt + p.Interest
+		}
+	}
+	p.Principal = p.Amount - p.Interest
+	if p.Principal <= 0 {
+		return nil, fmt.Errorf("amount must exceed accrued interest %s", p.Interest)
+	}
+	if p.Principal > debt {
+		return nil, fmt.Errorf("amount must be at most the remaining loan balance plus interest %s", debt+p.Interest)
+	}
+	if p.Date.IsZero() {
+		p.Date = time.Now()
+	}
+
+	transfer := &models.Transfer{FromAccountID: p.FromAccountID, ToAccountID: loanAccountID, Amount: p.Principal, Date: p.Date, Description: request.Description}
+	if err := createTransfer(tx, userID, transfer); err != nil {
+		return nil, err
+	}
+	p.TransferID = transfer.ID
+	if p.Interest > 0 {
+		interest := &models.Transaction{UserID: userID, Amount: p.Interest, Type: "expense", CategoryID: request.CategoryID, Date: p.Date,
+			Description: request.Description, Currency: currency, AccountID: p.FromAccountID}
+		if err := insertTransaction(tx, interest); err != nil {
+			return nil, err
+		}
+		p.InterestTransactionID = interest.ID
+	}
+
+	err = tx.QueryRow(`INSERT INTO loan_payments (account_id, transfer_id, interest_transaction_id, principal, interest, date)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6) RETURNING id`,
+		loanAccountID, p.TransferID, p.InterestTransactionID, p.Principal, p.Interest, p.Date).Scan(&p.ID)
+	if err != nil {
+		return nil, err
+	}
+	created := 0
+	if p.InterestTransactionID != 0 {
+		created = 1
+	}
+	if err := commitCreated(tx, created); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetLoanPaymentTotals возвращает число действующих платежей по кредиту и сумму уплаченных по ним процентов.
+// Платежи, перевод которых удален, не учитываются.
+func (s *Storage) GetLoanPaymentTotals(loanAccountID, userID int) (payments int, interest models.Money, err error) {
+	err = s.DB.QueryRow(`SELECT COUNT(*), COALESCE(SUM(lp.interest), 0)
+		FROM loan_payments lp
+		JOIN accounts a ON a.id = lp.account_id
+		JOIN transactions t ON t.transfer_id = lp.transfer_id AND t.account_id = lp.account_id
+		WHERE lp.account_id = $1 AND `+accountAccess("a", "$2", false)+` AND t.deleted_at IS NULL`, loanAccountID, userID).Scan(&payments, &interest)
+	return payments, interest, err
+}
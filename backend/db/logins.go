@@ -0,0 +1,41 @@
+package db
+
+import "github.com/nemopss/fin-ng/backend/models"
+
+// RecordLogin сохраняет успешный вход пользователя. newDevice равен true, если
+// у пользователя уже были входы, но ни одного с такими IP и User-Agent.
+func (s *Storage) RecordLogin(userID int, ip, userAgent string) (newDevice bool, err error) {
+	var hasHistory, seen bool
+	err = s.DB.QueryRow(`SELECT
+			EXISTS(SELECT 1 FROM login_events WHERE user_id = $1),
+			EXISTS(SELECT 1 FROM login_events WHERE user_id = $1 AND ip = $2 AND user_agent = $3)`,
+		userID, ip, userAgent).Scan(&hasHistory, &seen)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.DB.Exec("INSERT INTO login_events (user_id, ip, user_agent) VALUES ($1, $2, $3)", userID, ip, userAgent)
+	if err != nil {
+		return false, err
+	}
+	return hasHistory && !seen, nil
+}
+
+// GetLogins возвращает последние входы пользователя, начиная с самого нового.
+func (s *Storage) GetLogins(userID, limit int) ([]models.LoginEvent, error) {
+	rows, err := s.DB.Query("SELECT id, ip, user_agent, created_at FROM login_events WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2", userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logins := []models.LoginEvent{}
+	for rows.Next() {
+		var l models.LoginEvent
+		if err := rows.Scan(&l.ID, &l.IP, &l.UserAgent, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		logins = append(logins, l)
+	}
+	return logins, rows.Err()
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"path"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Миграции схемы встроены в бинарник. Новая миграция — файл NNNNN_описание.sql
+// с разделами -- +goose Up и -- +goose Down; примененные миграции не меняются
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// newMigrator создает провайдер миграций для встроенных файлов схемы.
+func newMigrator(db *sql.DB) (*goose.Provider, error) {
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return goose.NewProvider(goose.DialectPostgres, db, fsys)
+}
+
+// Migrate применяет к базе все еще не примененные миграции.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	p, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	results, err := p.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	for _, r := range results {
+		log.Printf("applied migration %s in %v", path.Base(r.Source.Path), r.Duration)
+	}
+	return nil
+}
+
+// RunMigrationCommand выполняет команду миграций и пишет ее результат в out:
+// up применяет все миграции, down откатывает последнюю, status выводит состояние
+// каждой миграции, version — версию схемы базы.
+func RunMigrationCommand(ctx context.Context, db *sql.DB, command string, out io.Writer) error {
+	p, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "up":
+		results, err := p.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "no migrations to apply")
+		}
+		for _, r := range results {
+			fmt.Fprintf(out, "applied %s in %v\n", path.Base(r.Source.Path), r.Duration)
+		}
+	case "down":
+		r, err := p.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "rolled back %s in %v\n", path.Base(r.Source.Path), r.Duration)
+	case "status":
+		statuses, err := p.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.State == goose.StateApplied {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%-40s %s\n", path.Base(s.Source.Path), applied)
+		}
+	case "version":
+		version, err := p.GetDBVersion(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, version)
+	default:
+		return fmt.Errorf("unknown migration command %q, expected up, down, status or version", command)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestMigrations проверяет, что повторный запуск миграций ничего не меняет, а команды
+// version и status видят все встроенные миграции примененными.
+func TestMigrations(t *testing.T) {
+	store := setupTestDB(t)
+	ctx := context.Background()
+
+	if err := Migrate(ctx, store.DB); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	p, err := newMigrator(store.DB)
+	if err != nil {
+		t.Fatalf("newMigrator failed: %v", err)
+	}
+	sources := p.ListSources()
+	if len(sources) == 0 {
+		t.Fatal("Expected embedded migrations")
+	}
+	latest := sources[len(sources)-1].Version
+
+	var out bytes.Buffer
+	if err := RunMigrationCommand(ctx, store.DB, "up", &out); err != nil {
+		t.Fatalf("up failed: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "no migrations to apply" {
+		t.Errorf("Expected nothing to apply, got %q", got)
+	}
+
+	out.Reset()
+	if err := RunMigrationCommand(ctx, store.DB, "version", &out); err != nil {
+		t.Fatalf("version failed: %v", err)
+	}
+	if got, want := strings.TrimSpace(out.String()), strconv.FormatInt(latest, 10); got != want {
+		t.Errorf("Expected version %s, got %s", want, got)
+	}
+
+	out.Reset()
+	if err := RunMigrationCommand(ctx, store.DB, "status", &out); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if strings.Contains(out.String(), "pending") {
+		t.Errorf("Expected all migrations applied, got:\n%s", out.String())
+	}
+
+	if err := RunMigrationCommand(ctx, store.DB, "sideways", &out); err == nil {
+		t.Error("Expected error for unknown command")
+	}
+}
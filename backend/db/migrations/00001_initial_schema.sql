@@ -0,0 +1,520 @@
+-- Начальная схема базы данных. Операторы идемпотентны, поэтому миграция безопасно применяется
+-- к базам, созданным до появления миграций.
+
+-- +goose Up
+-- Создание таблицы users
+CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	username TEXT UNIQUE,
+	password TEXT
+);
+
+-- Email пользователя (необязательный, нужен для входа по ссылке)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT UNIQUE;
+
+-- Роль пользователя ('user' или 'admin')
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user';
+
+-- Базовая валюта пользователя для пересчета итогов
+ALTER TABLE users ADD COLUMN IF NOT EXISTS base_currency TEXT NOT NULL DEFAULT 'RUB';
+
+-- День начала месяца в отчетах, например день зарплаты
+ALTER TABLE users ADD COLUMN IF NOT EXISTS month_start INTEGER NOT NULL DEFAULT 1 CHECK (month_start BETWEEN 1 AND 28);
+
+-- Создание таблицы categories
+CREATE TABLE IF NOT EXISTS categories (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id),
+	name TEXT NOT NULL
+);
+
+-- Создание таблицы transactions
+CREATE TABLE IF NOT EXISTS transactions (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id),
+	amount NUMERIC(14,2),
+	type TEXT,
+	category_id INTEGER REFERENCES categories(id),
+	date TIMESTAMP
+);
+
+-- Текстовое описание (заметка) транзакции
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+
+-- Валюта транзакции (код ISO 4217)
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'RUB';
+
+-- Создание таблицы одноразовых токенов (вход по ссылке и т.п.)
+CREATE TABLE IF NOT EXISTS one_time_tokens (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+	token_hash TEXT UNIQUE NOT NULL,
+	purpose TEXT NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	used_at TIMESTAMP
+);
+
+-- Создание таблицы привязок к внешним OIDC-провайдерам
+CREATE TABLE IF NOT EXISTS user_identities (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+	issuer TEXT NOT NULL,
+	subject TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (issuer, subject)
+);
+
+-- Создание таблицы приглашений для регистрации по инвайтам
+CREATE TABLE IF NOT EXISTS invites (
+	id SERIAL PRIMARY KEY,
+	code TEXT UNIQUE NOT NULL,
+	created_by INTEGER REFERENCES users(id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	expires_at TIMESTAMP,
+	used_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	used_at TIMESTAMP
+);
+
+-- Создание таблицы истории входов
+CREATE TABLE IF NOT EXISTS login_events (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+	ip TEXT NOT NULL,
+	user_agent TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создание таблицы запросов на смену email; подтвержденные записи
+-- сохраняют старый адрес для восстановления доступа
+CREATE TABLE IF NOT EXISTS email_changes (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+	old_email TEXT,
+	new_email TEXT NOT NULL,
+	token_hash TEXT UNIQUE NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	confirmed_at TIMESTAMP
+);
+
+-- Создание таблиц тегов и их связи с транзакциями
+CREATE TABLE IF NOT EXISTS tags (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+	transaction_id INTEGER REFERENCES transactions(id) ON DELETE CASCADE,
+	tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE,
+	PRIMARY KEY (transaction_id, tag_id)
+);
+
+-- Время перемещения транзакции в корзину; NULL — транзакция не удалена
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- Кэш курсов валют: курс — число единиц валюты за единицу опорной валюты провайдера
+CREATE TABLE IF NOT EXISTS exchange_rates (
+	date DATE NOT NULL,
+	currency TEXT NOT NULL,
+	rate DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (date, currency)
+);
+
+-- Полнотекстовый индекс по описанию транзакции
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS search_vector tsvector
+	GENERATED ALWAYS AS (to_tsvector('russian', description)) STORED;
+
+CREATE INDEX IF NOT EXISTS transactions_search_vector_idx ON transactions USING GIN (search_vector);
+
+-- Запланированные транзакции с будущей датой не учитываются в итогах до наступления даты
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS planned BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS transactions_planned_date_idx ON transactions (date) WHERE planned;
+
+-- Статус сверки с банковской выпиской: pending, cleared или reconciled
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'cleared'
+	CHECK (status IN ('pending', 'cleared', 'reconciled'));
+
+-- Контрагенты (магазины, организации); имя уникально у пользователя без учета регистра
+CREATE TABLE IF NOT EXISTS payees (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payees_user_name_idx ON payees (user_id, lower(name));
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS payee_id INTEGER REFERENCES payees(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS transactions_payee_id_idx ON transactions (payee_id);
+
+-- Отметка о возможном дубликате, выставляемая при создании транзакции
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS possible_duplicate BOOLEAN NOT NULL DEFAULT false;
+
+-- История изменений транзакций: снимок состояния после каждого изменения
+CREATE TABLE IF NOT EXISTS transaction_history (
+	transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+	version INTEGER NOT NULL,
+	data JSONB NOT NULL,
+	changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (transaction_id, version)
+);
+
+-- Суммы хранятся точно с двумя знаками после точки; ранее созданный столбец FLOAT
+-- приводится с округлением до копеек, для столбца NUMERIC(14,2) команда ничего не делает
+ALTER TABLE transactions ALTER COLUMN amount TYPE NUMERIC(14,2);
+
+-- Возврат (доход), привязанный к исходному расходу; в итогах уменьшает расход, а не увеличивает доход
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS linked_transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS transactions_linked_transaction_id_idx ON transactions (linked_transaction_id);
+
+-- Отметка «требует проверки», которую пользователь ставит и снимает вручную
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT false;
+
+-- Сумма в валюте покупки и курс пересчета для операций за границей
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS original_amount NUMERIC(14,2),
+	ADD COLUMN IF NOT EXISTS original_currency TEXT,
+	ADD COLUMN IF NOT EXISTS fx_rate NUMERIC(18,8);
+
+-- Фоновые задания импорта: прогресс и отчет по строкам
+CREATE TABLE IF NOT EXISTS import_jobs (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed', 'cancelled')),
+	total_rows INTEGER NOT NULL,
+	processed_rows INTEGER NOT NULL DEFAULT 0,
+	report JSONB,
+	error TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	finished_at TIMESTAMP
+);
+
+-- Значок и цвет категории, общие для всех клиентов
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS icon TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS color TEXT NOT NULL DEFAULT '';
+
+-- Ключевые слова категорий: транзакции, в контрагенте или описании которых встречается слово,
+-- получают категорию автоматически. Слово уникально у пользователя без учета регистра
+CREATE TABLE IF NOT EXISTS category_keywords (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+	keyword TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS category_keywords_user_keyword_idx ON category_keywords (user_id, lower(keyword));
+
+-- Счета пользователя (наличные, карты): остаток — начальный баланс плюс доходы и минус расходы по счету.
+-- cached_balance хранит вычисленный остаток счетов с длинной историей; balance_version увеличивается
+-- при каждом изменении транзакций счета, чтобы параллельный пересчет не сохранил устаревший остаток
+CREATE TABLE IF NOT EXISTS accounts (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	currency TEXT NOT NULL,
+	initial_balance NUMERIC(14,2) NOT NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	cached_balance NUMERIC(16,2),
+	balance_version BIGINT NOT NULL DEFAULT 0
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS account_id INTEGER REFERENCES accounts(id);
+
+CREATE INDEX IF NOT EXISTS transactions_account_id_idx ON transactions (account_id);
+
+-- Время закрытия счета; транзакции закрытого счета доступны только для чтения
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+-- Тип счета; у кредитной карты есть кредитный лимит, день закрытия выписки и день платежа,
+-- у кредита — сумма, годовая ставка в процентах, срок в месяцах и дата первого платежа
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'regular',
+	ADD COLUMN IF NOT EXISTS credit_limit NUMERIC(14,2),
+	ADD COLUMN IF NOT EXISTS statement_day SMALLINT CHECK (statement_day BETWEEN 1 AND 28),
+	ADD COLUMN IF NOT EXISTS payment_due_day SMALLINT CHECK (payment_due_day BETWEEN 1 AND 28),
+	ADD COLUMN IF NOT EXISTS loan_principal NUMERIC(14,2),
+	ADD COLUMN IF NOT EXISTS loan_interest_rate NUMERIC(7,4),
+	ADD COLUMN IF NOT EXISTS loan_term_months INTEGER,
+	ADD COLUMN IF NOT EXISTS loan_first_payment DATE;
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_type_check,
+	ADD CONSTRAINT accounts_type_check CHECK (type IN ('regular', 'credit_card', 'loan', 'investment', 'crypto'));
+
+-- Доступ к счету других пользователей: viewer видит счет и его транзакции, editor также ведет по нему транзакции
+CREATE TABLE IF NOT EXISTS account_shares (
+	account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	role TEXT NOT NULL CHECK (role IN ('viewer', 'editor')),
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (account_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS account_shares_user_id_idx ON account_shares (user_id);
+
+-- Переводы между счетами: пара транзакций с общим transfer_id — расход на счете-источнике
+-- и доход на счете-получателе. Переводы не учитываются в доходах и расходах
+CREATE TABLE IF NOT EXISTS transfers (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS transfer_id INTEGER REFERENCES transfers(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS transactions_transfer_id_idx ON transactions (transfer_id);
+
+-- Корректировки остатка счета: доходы и расходы, которые исправляют остаток и не учитываются в доходах и расходах
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS adjustment BOOLEAN NOT NULL DEFAULT false;
+
+-- Любое изменение транзакции, влияющее на остаток, сбрасывает кэш остатка ее прежнего и нового счета
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION reset_account_balance() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP <> 'INSERT' AND OLD.account_id IS NOT NULL THEN
+		UPDATE accounts SET cached_balance = NULL, balance_version = balance_version + 1 WHERE id = OLD.account_id;
+	END IF;
+	IF TG_OP <> 'DELETE' AND NEW.account_id IS NOT NULL AND NEW.account_id IS DISTINCT FROM OLD.account_id THEN
+		UPDATE accounts SET cached_balance = NULL, balance_version = balance_version + 1 WHERE id = NEW.account_id;
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS transactions_reset_account_balance ON transactions;
+CREATE TRIGGER transactions_reset_account_balance
+	AFTER INSERT OR DELETE OR UPDATE OF account_id, amount, type, planned, deleted_at ON transactions
+	FOR EACH ROW EXECUTE FUNCTION reset_account_balance();
+
+-- Платежи по кредитам: основной долг переводится на счет кредита, проценты записываются расходом
+CREATE TABLE IF NOT EXISTS loan_payments (
+	id SERIAL PRIMARY KEY,
+	account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+	transfer_id INTEGER NOT NULL REFERENCES transfers(id) ON DELETE CASCADE,
+	interest_transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+	principal NUMERIC(14,2) NOT NULL,
+	interest NUMERIC(14,2) NOT NULL,
+	date TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS loan_payments_account_id_idx ON loan_payments (account_id);
+
+-- Позиции инвестиционных и криптовалютных счетов: количество бумаг или монет и сумма их покупки в валюте счета
+CREATE TABLE IF NOT EXISTS holdings (
+	id SERIAL PRIMARY KEY,
+	account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+	ticker TEXT NOT NULL,
+	quantity NUMERIC(20,8) NOT NULL CHECK (quantity > 0),
+	cost_basis NUMERIC(14,2) NOT NULL CHECK (cost_basis >= 0),
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE (account_id, ticker)
+);
+
+-- Ежедневные снимки стоимости позиций счетов по текущим котировкам; снимок дня обновляется до его окончания
+CREATE TABLE IF NOT EXISTS holding_valuations (
+	account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+	date DATE NOT NULL,
+	cost_basis NUMERIC(16,2) NOT NULL,
+	market_value NUMERIC(16,2) NOT NULL,
+	PRIMARY KEY (account_id, date)
+);
+
+-- Ежедневные снимки остатков счетов на конец дня и их сумм по валютам для графиков остатков
+CREATE TABLE IF NOT EXISTS account_balance_history (
+	account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+	date DATE NOT NULL,
+	balance NUMERIC(16,2) NOT NULL,
+	PRIMARY KEY (account_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS balance_history (
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	date DATE NOT NULL,
+	currency TEXT NOT NULL,
+	balance NUMERIC(16,2) NOT NULL,
+	PRIMARY KEY (user_id, date, currency)
+);
+
+-- Месячные бюджеты категорий: лимит расходов категории на месяц в одной валюте
+CREATE TABLE IF NOT EXISTS budgets (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+	month DATE NOT NULL CHECK (EXTRACT(DAY FROM month) = 1),
+	amount NUMERIC(14,2) NOT NULL CHECK (amount > 0),
+	currency TEXT NOT NULL,
+	UNIQUE (user_id, category_id, month)
+);
+
+-- Переносимые бюджеты: остаток (или перерасход) прошлого месяца прибавляется к лимиту следующего
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS rollover BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS carryover NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+-- Бюджеты на неделю, квартал и произвольный период: месяц бюджета заменяется периодом
+-- с первым и последним днем включительно. Переносимый бюджет получает остаток бюджета
+-- того же вида, период которого заканчивается накануне
+ALTER TABLE budgets
+	ADD COLUMN IF NOT EXISTS period TEXT NOT NULL DEFAULT 'month' CHECK (period IN ('week', 'month', 'quarter', 'custom')),
+	ADD COLUMN IF NOT EXISTS start_date DATE,
+	ADD COLUMN IF NOT EXISTS end_date DATE;
+
+-- +goose StatementBegin
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'budgets' AND column_name = 'month') THEN
+		UPDATE budgets SET start_date = month, end_date = (month + INTERVAL '1 month')::date - 1;
+		ALTER TABLE budgets DROP COLUMN month;
+		ALTER TABLE budgets ALTER COLUMN start_date SET NOT NULL, ALTER COLUMN end_date SET NOT NULL,
+			ADD CHECK (end_date >= start_date);
+	END IF;
+END $$;
+-- +goose StatementEnd
+
+CREATE UNIQUE INDEX IF NOT EXISTS budgets_period_key ON budgets (user_id, category_id, period, start_date);
+
+-- Бюджеты по тегу: лимит расходов по транзакциям с тегом в любых категориях.
+-- У бюджета задана либо категория, либо тег
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE;
+
+-- +goose StatementBegin
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'budgets_key_check') THEN
+		ALTER TABLE budgets ALTER COLUMN category_id DROP NOT NULL,
+			ADD CONSTRAINT budgets_key_check CHECK ((category_id IS NULL) <> (tag_id IS NULL));
+	END IF;
+END $$;
+-- +goose StatementEnd
+
+CREATE UNIQUE INDEX IF NOT EXISTS budgets_tag_period_key ON budgets (user_id, tag_id, period, start_date);
+
+-- Шаблоны бюджетов: набор лимитов категорий, который применяется к месяцу вручную
+-- или автоматически в начале месяца. applied_month — последний месяц автоматического применения
+CREATE TABLE IF NOT EXISTS budget_templates (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	auto_apply BOOLEAN NOT NULL DEFAULT false,
+	applied_month DATE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS budget_template_items (
+	template_id INTEGER NOT NULL REFERENCES budget_templates(id) ON DELETE CASCADE,
+	category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+	amount NUMERIC(14,2) NOT NULL CHECK (amount > 0),
+	currency TEXT NOT NULL,
+	rollover BOOLEAN NOT NULL DEFAULT false,
+	PRIMARY KEY (template_id, category_id)
+);
+
+-- Цели накоплений и взносы в них. Взнос задается суммой или транзакцией; сумма, дата и описание
+-- взноса-транзакции берутся из самой транзакции
+CREATE TABLE IF NOT EXISTS goals (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	target NUMERIC(14,2) NOT NULL CHECK (target > 0),
+	currency TEXT NOT NULL,
+	deadline DATE,
+	account_id INTEGER REFERENCES accounts(id) ON DELETE SET NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS goal_contributions (
+	id SERIAL PRIMARY KEY,
+	goal_id INTEGER NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+	amount NUMERIC(14,2) CHECK (amount <> 0),
+	date DATE,
+	description TEXT NOT NULL DEFAULT '',
+	transaction_id INTEGER REFERENCES transactions(id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	CHECK ((transaction_id IS NULL) = (amount IS NOT NULL AND date IS NOT NULL)),
+	UNIQUE (goal_id, transaction_id)
+);
+
+-- Кассовые чеки, по которым созданы транзакции, и их позиции.
+-- Один чек нельзя добавить дважды: он определяется номерами ФН, ФД и фискальным признаком
+CREATE TABLE IF NOT EXISTS receipts (
+	transaction_id INTEGER PRIMARY KEY REFERENCES transactions(id) ON DELETE CASCADE,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	fn TEXT NOT NULL,
+	fd TEXT NOT NULL,
+	fp TEXT NOT NULL,
+	seller TEXT NOT NULL DEFAULT '',
+	seller_inn TEXT NOT NULL DEFAULT '',
+	date TIMESTAMP NOT NULL,
+	total NUMERIC(14,2) NOT NULL,
+	UNIQUE (user_id, fn, fd, fp)
+);
+
+CREATE TABLE IF NOT EXISTS receipt_items (
+	id SERIAL PRIMARY KEY,
+	transaction_id INTEGER NOT NULL REFERENCES receipts(transaction_id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	price NUMERIC(14,2) NOT NULL,
+	quantity NUMERIC(14,3) NOT NULL,
+	sum NUMERIC(14,2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS receipt_items_transaction_id_idx ON receipt_items (transaction_id);
+
+-- Подписки на письма с отчетами за прошедшую неделю или месяц. sent_start — первый день последнего периода,
+-- за который письмо отправлено; письмо приходит за периоды, закончившиеся после подписки
+CREATE TABLE IF NOT EXISTS report_emails (
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	period TEXT NOT NULL CHECK (period IN ('week', 'month')),
+	sent_start DATE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, period)
+);
+
+-- Триграммный индекс ускоряет поиск по подстроке; без прав на создание
+-- расширения pg_trgm поиск работает, но без индекса
+-- +goose StatementBegin
+DO $$
+BEGIN
+	CREATE EXTENSION IF NOT EXISTS pg_trgm;
+	CREATE INDEX IF NOT EXISTS transactions_description_trgm_idx ON transactions USING GIN (description gin_trgm_ops);
+EXCEPTION WHEN insufficient_privilege OR undefined_file THEN
+	RAISE NOTICE 'pg_trgm is not available, substring search will not be indexed: %', SQLERRM;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS report_emails CASCADE;
+DROP TABLE IF EXISTS receipt_items CASCADE;
+DROP TABLE IF EXISTS receipts CASCADE;
+DROP TABLE IF EXISTS goal_contributions CASCADE;
+DROP TABLE IF EXISTS goals CASCADE;
+DROP TABLE IF EXISTS budget_template_items CASCADE;
+DROP TABLE IF EXISTS budget_templates CASCADE;
+DROP TABLE IF EXISTS budgets CASCADE;
+DROP TABLE IF EXISTS balance_history CASCADE;
+DROP TABLE IF EXISTS account_balance_history CASCADE;
+DROP TABLE IF EXISTS holding_valuations CASCADE;
+DROP TABLE IF EXISTS holdings CASCADE;
+DROP TABLE IF EXISTS loan_payments CASCADE;
+DROP TABLE IF EXISTS transfers CASCADE;
+DROP TABLE IF EXISTS account_shares CASCADE;
+DROP TABLE IF EXISTS accounts CASCADE;
+DROP TABLE IF EXISTS category_keywords CASCADE;
+DROP TABLE IF EXISTS import_jobs CASCADE;
+DROP TABLE IF EXISTS transaction_history CASCADE;
+DROP TABLE IF EXISTS payees CASCADE;
+DROP TABLE IF EXISTS exchange_rates CASCADE;
+DROP TABLE IF EXISTS transaction_tags CASCADE;
+DROP TABLE IF EXISTS tags CASCADE;
+DROP TABLE IF EXISTS email_changes CASCADE;
+DROP TABLE IF EXISTS login_events CASCADE;
+DROP TABLE IF EXISTS invites CASCADE;
+DROP TABLE IF EXISTS user_identities CASCADE;
+DROP TABLE IF EXISTS one_time_tokens CASCADE;
+DROP TABLE IF EXISTS transactions CASCADE;
+DROP TABLE IF EXISTS categories CASCADE;
+DROP TABLE IF EXISTS users CASCADE;
+DROP FUNCTION IF EXISTS reset_account_balance();
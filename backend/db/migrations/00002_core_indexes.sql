@@ -0,0 +1,13 @@
+-- Индексы основных запросов: список транзакций пользователя по дате, фильтр по категории
+-- и список категорий. Поиск пользователя по имени при входе уже использует индекс
+-- ограничения UNIQUE на users(username), поэтому отдельный индекс не нужен.
+
+-- +goose Up
+CREATE INDEX IF NOT EXISTS transactions_user_id_date_idx ON transactions (user_id, date);
+CREATE INDEX IF NOT EXISTS transactions_user_id_category_id_idx ON transactions (user_id, category_id);
+CREATE INDEX IF NOT EXISTS categories_user_id_idx ON categories (user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS categories_user_id_idx;
+DROP INDEX IF EXISTS transactions_user_id_category_id_idx;
+DROP INDEX IF EXISTS transactions_user_id_date_idx;
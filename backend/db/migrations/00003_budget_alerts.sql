@@ -0,0 +1,8 @@
+-- Время отправки уведомления о превышении бюджета: уведомление по бюджету отправляется один раз,
+-- пока не изменится его лимит.
+
+-- +goose Up
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS alerted_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE budgets DROP COLUMN IF EXISTS alerted_at;
@@ -0,0 +1,7 @@
+-- Язык писем, которые отправляются без запроса пользователя: отчетов и уведомлений о бюджетах.
+
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT 'en';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS language;
@@ -0,0 +1,14 @@
+-- Email хранится в нижнем регистре и уникален без учета регистра: раньше ограничение UNIQUE различало
+-- Alice@example.com и alice@example.com, а поиск по email — нет. Если адрес уже занят без учета регистра,
+-- у более поздних пользователей email сбрасывается: они смогут указать его заново с подтверждением.
+
+-- +goose Up
+UPDATE users u SET email = NULL
+WHERE email IS NOT NULL AND EXISTS (SELECT 1 FROM users o WHERE LOWER(TRIM(o.email)) = LOWER(TRIM(u.email)) AND o.id < u.id);
+UPDATE users SET email = NULLIF(LOWER(TRIM(email)), '') WHERE email IS NOT NULL;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_idx ON users (LOWER(email));
+
+-- +goose Down
+DROP INDEX IF EXISTS users_email_lower_idx;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
@@ -0,0 +1,8 @@
+-- Подтвержден ли email пользователя переходом по ссылке из письма. При регистрации адрес не проверяется,
+-- поэтому вход через OIDC привязывается к существующему пользователю только по подтвержденному email.
+
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
@@ -0,0 +1,175 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// MaxPayeeLength — максимальная длина имени контрагента в символах.
+const MaxPayeeLength = 100
+
+// normalizePayeeName убирает пробелы по краям и проверяет длину имени контрагента.
+func normalizePayeeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("payee name is required")
+	}
+	if len([]rune(name)) > MaxPayeeLength {
+		return "", fmt.Errorf("payee name must be at most %d characters", MaxPayeeLength)
+	}
+	return name, nil
+}
+
+func (s *Storage) CreatePayee(userID int, name string) (*models.Payee, error) {
+	name, err := normalizePayeeName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	payee := &models.Payee{UserID: userID, Name: name}
+	err = s.DB.QueryRow("INSERT INTO payees (user_id, name) VALUES ($1, $2) RETURNING id", userID, name).Scan(&payee.ID)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("payee already exists")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payee, nil
+}
+
+// GetPayees возвращает до limit контрагентов пользователя, имя которых содержит query
+// (пустой query — все). Сначала идут совпадения с начала имени, затем самые используемые.
+func (s *Storage) GetPayees(userID int, query string, limit int) ([]models.Payee, error) {
+	query = likeEscaper.Replace(query)
+	rows, err := s.DB.Query(`SELECT p.id, p.user_id, p.name FROM payees p
+		WHERE p.user_id = $1 AND p.name ILIKE '%' || $2 || '%'
+		ORDER BY p.name ILIKE $2 || '%' DESC,
+			(SELECT COUNT(*) FROM transactions t WHERE t.payee_id = p.id AND t.deleted_at IS NULL) DESC,
+			p.name
+		LIMIT $3`, userID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payees := []models.Payee{}
+	for rows.Next() {
+		var p models.Payee
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Name); err != nil {
+			return nil, err
+		}
+		payees = append(payees, p)
+	}
+	return payees, rows.Err()
+}
+
+func (s *Storage) GetPayee(id, userID int) (*models.Payee, error) {
+	var p models.Payee
+	err := s.DB.QueryRow("SELECT id, user_id, name FROM payees WHERE id = $1 AND user_id = $2", id, userID).Scan(&p.ID, &p.UserID, &p.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *Storage) UpdatePayee(id, userID int, name string) (bool, error) {
+	name, err := normalizePayeeName(name)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.DB.Exec("UPDATE payees SET name = $1 WHERE id = $2 AND user_id = $3", name, id, userID)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return false, fmt.Errorf("payee already exists")
+	}
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeletePayee удаляет контрагента; у его транзакций контрагент сбрасывается.
+func (s *Storage) DeletePayee(id, userID int) (bool, error) {
+	result, err := s.DB.Exec("DELETE FROM payees WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetPayeeStats возвращает число транзакций контрагента, даты первой и последней из них
+// и суммы по валютам. Для чужого или несуществующего контрагента возвращается nil.
+func (s *Storage) GetPayeeStats(id, userID int) (*models.PayeeStats, error) {
+	payee, err := s.GetPayee(id, userID)
+	if err != nil || payee == nil {
+		return nil, err
+	}
+
+	stats := &models.PayeeStats{PayeeID: payee.ID, Name: payee.Name}
+	var firstDate, lastDate sql.NullTime
+	err = s.DB.QueryRow(`SELECT COUNT(*), MIN(date), MAX(date) FROM transactions
+		WHERE user_id = $1 AND payee_id = $2 AND deleted_at IS NULL AND NOT planned`, userID, id).
+		Scan(&stats.TransactionCount, &firstDate, &lastDate)
+	if err != nil {
+		return nil, err
+	}
+	if firstDate.Valid {
+		stats.FirstDate = &firstDate.Time
+		stats.LastDate = &lastDate.Time
+	}
+
+	stats.Totals, err = s.GetTransactionTotals(userID, TransactionFilter{PayeeID: id})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetPayeeExpenses возвращает расходы пользователя с контрагентом начиная с from по возрастанию даты.
+// Запланированные транзакции, переводы и корректировки не возвращаются.
+func (s *Storage) GetPayeeExpenses(userID int, from time.Time) ([]models.Transaction, error) {
+	return s.queryTransactions("SELECT "+transactionColumns+` FROM transactions
+		WHERE user_id = $1 AND deleted_at IS NULL AND NOT planned AND type = 'expense' AND payee_id IS NOT NULL
+			AND transfer_id IS NULL AND NOT adjustment AND date >= $2
+		ORDER BY date, id`, userID, from)
+}
+
+// resolvePayee проверяет контрагента транзакции по PayeeID или, если задано только имя Payee,
+// находит контрагента пользователя с таким именем без учета регистра, создавая его при необходимости.
+func resolvePayee(tx *sql.Tx, t *models.Transaction) error {
+	if t.PayeeID > 0 {
+		err := tx.QueryRow("SELECT name FROM payees WHERE id = $1 AND user_id = $2", t.PayeeID, t.UserID).Scan(&t.Payee)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("payee does not exist or does not belong to user")
+		}
+		return err
+	}
+	if strings.TrimSpace(t.Payee) == "" {
+		t.Payee = ""
+		return nil
+	}
+
+	name, err := normalizePayeeName(t.Payee)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRow(`INSERT INTO payees (user_id, name) VALUES ($1, $2)
+		ON CONFLICT (user_id, lower(name)) DO UPDATE SET name = payees.name RETURNING id, name`,
+		t.UserID, name).Scan(&t.PayeeID, &t.Payee)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestPayees тестирует контрагентов: создание по имени, автодополнение, статистику и удаление.
+func TestPayees(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := store.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	market, err := store.CreatePayee(user.ID, "  Пятерочка ")
+	if err != nil {
+		t.Fatalf("Failed to create payee: %v", err)
+	}
+	if market.Name != "Пятерочка" {
+		t.Errorf("Expected trimmed name, got %q", market.Name)
+	}
+	if _, err := store.CreatePayee(user.ID, "ПЯТЕРОЧКА"); err == nil || err.Error() != "payee already exists" {
+		t.Errorf("Expected error 'payee already exists', got %v", err)
+	}
+
+	// Имя контрагента без ID находит существующего контрагента без учета регистра или создает нового
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, Payee: "пятерочка", Date: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{UserID: user.ID, Amount: models.NewMoney(250, 0), Type: "expense", CategoryID: category.ID, PayeeID: market.ID, Date: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{UserID: user.ID, Amount: models.NewMoney(50, 0), Type: "expense", CategoryID: category.ID, Payee: "Перекресток"},
+	}
+	for _, transaction := range transactions {
+		if err := store.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	if transactions[0].PayeeID != market.ID || transactions[0].Payee != "Пятерочка" {
+		t.Errorf("Expected existing payee %d, got %d %q", market.ID, transactions[0].PayeeID, transactions[0].Payee)
+	}
+	if transactions[2].PayeeID == 0 || transactions[2].PayeeID == market.ID {
+		t.Errorf("Expected new payee, got %d", transactions[2].PayeeID)
+	}
+
+	saved, err := store.GetTransaction(transactions[1].ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if saved.PayeeID != market.ID || saved.Payee != "Пятерочка" {
+		t.Errorf("Expected payee %d %q, got %d %q", market.ID, "Пятерочка", saved.PayeeID, saved.Payee)
+	}
+
+	// Совпадения с начала имени идут раньше совпадений в середине
+	payees, err := store.GetPayees(user.ID, "пер", 10)
+	if err != nil {
+		t.Fatalf("Failed to get payees: %v", err)
+	}
+	if len(payees) != 2 || payees[0].Name != "Перекресток" {
+		t.Errorf("Expected Перекресток first, got %+v", payees)
+	}
+
+	stats, err := store.GetPayeeStats(market.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get payee stats: %v", err)
+	}
+	if stats.TransactionCount != 2 || len(stats.Totals) != 1 || stats.Totals[0].Expense != models.NewMoney(350, 0) ||
+		!stats.FirstDate.Equal(transactions[0].Date) || !stats.LastDate.Equal(transactions[1].Date) {
+		t.Errorf("Unexpected payee stats: %+v", stats)
+	}
+
+	other, err := store.CreateUser("otheruser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if stats, err := store.GetPayeeStats(market.ID, other.ID); err != nil || stats != nil {
+		t.Errorf("Expected no stats for other user, got %+v (%v)", stats, err)
+	}
+
+	// При удалении контрагента транзакции остаются без него
+	if deleted, err := store.DeletePayee(market.ID, user.ID); err != nil || !deleted {
+		t.Fatalf("Failed to delete payee: %v", err)
+	}
+	saved, err = store.GetTransaction(transactions[1].ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if saved.PayeeID != 0 || saved.Payee != "" {
+		t.Errorf("Expected no payee after delete, got %d %q", saved.PayeeID, saved.Payee)
+	}
+}
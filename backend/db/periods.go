@@ -0,0 +1,29 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/nemopss/fin-ng/backend/period"
+)
+
+// periodIntervals — шаг между началами соседних интервалов отчетов.
+var periodIntervals = map[string]string{
+	period.Day:     "1 day",
+	period.Week:    "1 week",
+	period.Month:   "1 month",
+	period.Quarter: "3 months",
+	period.Year:    "1 year",
+}
+
+// periodBucket возвращает SQL-выражение первого дня интервала g, содержащего дату column. Месяцы, кварталы и годы
+// с днем начала больше первого числа — календарные, сдвинутые на g.Offset() дней. Длина интервала подставляется
+// в запрос только из periodIntervals.
+func periodBucket(g period.Grouping, column string) (string, error) {
+	if _, ok := periodIntervals[g.Kind]; !ok {
+		return "", fmt.Errorf("unknown period %q", g.Kind)
+	}
+	if offset := g.Offset(); offset > 0 {
+		return fmt.Sprintf("(date_trunc('%s', %s - INTERVAL '%d days') + INTERVAL '%d days')::date", g.Kind, column, offset, offset), nil
+	}
+	return fmt.Sprintf("date_trunc('%s', %s)::date", g.Kind, column), nil
+}
@@ -0,0 +1,13 @@
+package db
+
+import "time"
+
+// ConvertDuePlannedTransactions превращает в обычные запланированные транзакции,
+// дата которых наступила к моменту now, и возвращает их число.
+func (s *Storage) ConvertDuePlannedTransactions(now time.Time) (int64, error) {
+	result, err := s.DB.Exec("UPDATE transactions SET planned = false WHERE planned AND date <= $1 AND deleted_at IS NULL", now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestPlannedTransactions тестирует отбор и превращение запланированных транзакций.
+func TestPlannedTransactions(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := store.CreateCategory(user.ID, "rent")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	now := time.Now()
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(100, 0), Type: "expense", CategoryID: category.ID, Date: now.Add(-time.Hour)},
+		{UserID: user.ID, Amount: models.NewMoney(500, 0), Type: "expense", CategoryID: category.ID, Date: now.Add(24 * time.Hour), Planned: true},
+		{UserID: user.ID, Amount: models.NewMoney(700, 0), Type: "expense", CategoryID: category.ID, Date: now.Add(48 * time.Hour), Planned: true},
+	}
+	for _, transaction := range transactions {
+		if err := store.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+
+	// По умолчанию запланированные транзакции не отбираются и не входят в итоги
+	_, total, err := store.GetTransactions(user.ID, TransactionFilter{}, 1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get transactions: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Expected 1 transaction without planned, got %d", total)
+	}
+	totals, err := store.GetTransactionTotals(user.ID, TransactionFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get totals: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Expense != models.NewMoney(100, 0) {
+		t.Errorf("Expected expense 100 without planned, got %+v", totals)
+	}
+	result, total, err := store.GetTransactions(user.ID, TransactionFilter{IncludePlanned: true, Sort: "asc"}, 1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get transactions: %v", err)
+	}
+	if total != 3 || !result[1].Planned {
+		t.Errorf("Expected 3 transactions with planned, got %d: %+v", total, result)
+	}
+
+	// Через сутки наступает дата только первой запланированной транзакции
+	converted, err := store.ConvertDuePlannedTransactions(now.Add(25 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to convert planned transactions: %v", err)
+	}
+	if converted != 1 {
+		t.Errorf("Expected 1 converted transaction, got %d", converted)
+	}
+	transaction, err := store.GetTransaction(transactions[1].ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if transaction.Planned {
+		t.Error("Expected transaction to be no longer planned")
+	}
+	if _, total, err = store.GetTransactions(user.ID, TransactionFilter{}, 1, 10); err != nil || total != 2 {
+		t.Errorf("Expected 2 transactions after conversion, got %d (%v)", total, err)
+	}
+}
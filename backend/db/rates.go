@@ -0,0 +1,89 @@
+package db
+
+import (
+	"time"
+)
+
+// SaveExchangeRates сохраняет курсы валют на указанную дату, заменяя ранее сохраненные.
+func (s *Storage) SaveExchangeRates(date time.Time, rates map[string]float64) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for currency, rate := range rates {
+		_, err := tx.Exec(`INSERT INTO exchange_rates (date, currency, rate) VALUES ($1, $2, $3)
+			ON CONFLICT (date, currency) DO UPDATE SET rate = EXCLUDED.rate`,
+			date.Format("2006-01-02"), currency, rate)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetLatestExchangeRates возвращает последние сохраненные курсы и их дату.
+// Если курсов еще нет, возвращается нулевая дата и nil.
+func (s *Storage) GetLatestExchangeRates() (time.Time, map[string]float64, error) {
+	rows, err := s.DB.Query(`SELECT date, currency, rate FROM exchange_rates
+		WHERE date = (SELECT MAX(date) FROM exchange_rates)`)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	defer rows.Close()
+
+	var date time.Time
+	var rates map[string]float64
+	for rows.Next() {
+		var currency string
+		var rate float64
+		if err := rows.Scan(&date, &currency, &rate); err != nil {
+			return time.Time{}, nil, err
+		}
+		if rates == nil {
+			rates = make(map[string]float64)
+		}
+		rates[currency] = rate
+	}
+	return date, rates, rows.Err()
+}
+
+// SetBaseCurrency меняет базовую валюту пользователя.
+func (s *Storage) SetBaseCurrency(userID int, currency string) (bool, error) {
+	result, err := s.DB.Exec("UPDATE users SET base_currency = $1 WHERE id = $2", currency, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// SetMonthStart меняет день начала месяца в отчетах пользователя.
+func (s *Storage) SetMonthStart(userID, day int) (bool, error) {
+	result, err := s.DB.Exec("UPDATE users SET month_start = $1 WHERE id = $2", day, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// SetLanguage меняет язык писем пользователя.
+func (s *Storage) SetLanguage(userID int, lang string) (bool, error) {
+	result, err := s.DB.Exec("UPDATE users SET language = $1 WHERE id = $2", lang, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestExchangeRates тестирует кэш курсов валют.
+func TestExchangeRates(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	if _, err := store.DB.Exec("TRUNCATE TABLE exchange_rates"); err != nil {
+		t.Fatalf("Failed to truncate exchange_rates: %v", err)
+	}
+
+	// Пока курсов нет, возвращается nil
+	_, rates, err := store.GetLatestExchangeRates()
+	if err != nil {
+		t.Fatalf("Failed to get rates: %v", err)
+	}
+	if rates != nil {
+		t.Errorf("Expected no rates, got %v", rates)
+	}
+
+	yesterday := time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+	if err := store.SaveExchangeRates(yesterday, map[string]float64{"RUB": 1, "USD": 0.0125}); err != nil {
+		t.Fatalf("Failed to save rates: %v", err)
+	}
+	if err := store.SaveExchangeRates(today, map[string]float64{"RUB": 1, "USD": 0.0124}); err != nil {
+		t.Fatalf("Failed to save rates: %v", err)
+	}
+
+	// Возвращаются курсы на последнюю дату
+	date, rates, err := store.GetLatestExchangeRates()
+	if err != nil {
+		t.Fatalf("Failed to get rates: %v", err)
+	}
+	if !date.Equal(today) || rates["USD"] != 0.0124 || len(rates) != 2 {
+		t.Errorf("Expected rates for %v with USD 0.0124, got %v %v", today, date, rates)
+	}
+}
+
+// TestTransactionTotals тестирует валюту транзакций и суммы по валютам.
+func TestTransactionTotals(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := store.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	// Валюта по умолчанию берется из базовой валюты пользователя
+	if _, err := store.SetBaseCurrency(user.ID, "EUR"); err != nil {
+		t.Fatalf("Failed to set base currency: %v", err)
+	}
+	transactions := []*models.Transaction{
+		{UserID: user.ID, Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID},
+		{UserID: user.ID, Amount: models.NewMoney(20, 0), Type: "income", CategoryID: category.ID},
+		{UserID: user.ID, Amount: models.NewMoney(5, 0), Type: "expense", CategoryID: category.ID, Currency: "USD"},
+	}
+	for _, transaction := range transactions {
+		if err := store.CreateTransaction(transaction); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+	}
+	if transactions[0].Currency != "EUR" || transactions[2].Currency != "USD" {
+		t.Errorf("Expected currencies EUR and USD, got %q and %q", transactions[0].Currency, transactions[2].Currency)
+	}
+
+	// Обновление без валюты сохраняет прежнюю
+	transactions[2].Currency = ""
+	if _, err := store.UpdateTransaction(transactions[2]); err != nil {
+		t.Fatalf("Failed to update transaction: %v", err)
+	}
+	if transactions[2].Currency != "USD" {
+		t.Errorf("Expected currency USD after update, got %q", transactions[2].Currency)
+	}
+
+	totals, err := store.GetTransactionTotals(user.ID, TransactionFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get totals: %v", err)
+	}
+	expected := []models.TransactionTotals{
+		{Currency: "EUR", Income: models.NewMoney(20, 0), Expense: models.NewMoney(10, 0)},
+		{Currency: "USD", Income: models.NewMoney(0, 0), Expense: models.NewMoney(5, 0)},
+	}
+	if len(totals) != len(expected) || totals[0] != expected[0] || totals[1] != expected[1] {
+		t.Errorf("Expected totals %+v, got %+v", expected, totals)
+	}
+
+	// Суммы учитывают фильтр
+	totals, err = store.GetTransactionTotals(user.ID, TransactionFilter{Type: "income"})
+	if err != nil {
+		t.Fatalf("Failed to get totals: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Income != models.NewMoney(20, 0) {
+		t.Errorf("Expected only EUR income 20, got %+v", totals)
+	}
+}
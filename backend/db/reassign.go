@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ReassignCategory переносит одним запросом транзакции пользователя из категории fromID в toID
+// и возвращает их число. Нулевые dateFrom и dateTo не ограничивают период. Переносятся
+// и транзакции из корзины, чтобы исходную категорию после переноса можно было удалить.
+// Обе категории блокируются от удаления до окончания переноса.
+func (s *Storage) ReassignCategory(userID, fromID, toID int, dateFrom, dateTo time.Time) (int64, error) {
+	var moved int64
+	err := s.WithTx(sql.LevelReadCommitted, func(tx *sql.Tx) error {
+		var count int
+		err := tx.QueryRow("SELECT COUNT(*) FROM (SELECT id FROM categories WHERE id IN ($1, $2) AND "+visibleCategory(3)+" ORDER BY id FOR SHARE) c",
+			fromID, toID, userID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count != 2 {
+			return fmt.Errorf("category does not exist or does not belong to user")
+		}
+		moved, err = reassignTransactions(tx, userID, fromID, toID, dateFrom, dateTo)
+		return err
+	})
+	return moved, err
+}
+
+func reassignTransactions(tx *sql.Tx, userID, fromID, toID int, dateFrom, dateTo time.Time) (int64, error) {
+	query := "UPDATE transactions SET category_id = $1 WHERE user_id = $2 AND category_id = $3"
+	args := []interface{}{toID, userID, fromID}
+	if !dateFrom.IsZero() {
+		query += fmt.Sprintf(" AND date >= $%d", len(args)+1)
+		args = append(args, dateFrom)
+	}
+	if !dateTo.IsZero() {
+		query += fmt.Sprintf(" AND date <= $%d", len(args)+1)
+		args = append(args, dateTo)
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteCategoryReassigning переносит все транзакции категории (включая корзину) в категорию toID
+// и удаляет исходную категорию в одной транзакции БД. Возвращает false, если исходной категории нет.
+func (s *Storage) DeleteCategoryReassigning(id, userID, toID int) (bool, error) {
+	if id == toID {
+		return false, fmt.Errorf("target category must differ from deleted category")
+	}
+
+	var deleted bool
+	err := s.WithTx(sql.LevelReadCommitted, func(tx *sql.Tx) error {
+		deleted = false
+		// Блокируем обе категории, чтобы целевую не удалили до переноса. Блокировки берутся по порядку ID,
+		// чтобы встречные переносы между двумя категориями не блокировали друг друга.
+		// Перенести транзакции можно и в системную категорию, но удалить — только собственную
+		rows, err := tx.Query("SELECT id, user_id IS NULL FROM categories WHERE id IN ($1, $2) AND "+visibleCategory(3)+" ORDER BY id FOR UPDATE", id, toID, userID)
+		if err != nil {
+			return err
+		}
+		system := make(map[int]bool)
+		for rows.Next() {
+			var categoryID int
+			var isSystem bool
+			if err := rows.Scan(&categoryID, &isSystem); err != nil {
+				rows.Close()
+				return err
+			}
+			system[categoryID] = isSystem
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if isSystem, ok := system[id]; !ok || isSystem {
+			return nil
+		}
+		if _, ok := system[toID]; !ok {
+			return fmt.Errorf("target category does not exist or does not belong to user")
+		}
+
+		if _, err := tx.Exec("UPDATE transactions SET category_id = $1 WHERE user_id = $2 AND category_id = $3", toID, userID, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM categories WHERE id = $1 AND user_id = $2", id, userID); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// CreateReceiptTransaction создает транзакцию вместе с кассовым чеком и его позициями.
+// Чек, уже добавленный пользователем, повторно не создается.
+func (s *Storage) CreateReceiptTransaction(t *models.Transaction, r *models.Receipt) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertTransaction(tx, t); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO receipts (transaction_id, user_id, fn, fd, fp, seller, seller_inn, date, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		t.ID, t.UserID, r.FN, r.FD, r.FP, r.Seller, r.SellerINN, r.Date, r.Total)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return fmt.Errorf("receipt has already been added")
+	}
+	if err != nil {
+		return err
+	}
+	for _, item := range r.Items {
+		_, err := tx.Exec("INSERT INTO receipt_items (transaction_id, name, price, quantity, sum) VALUES ($1, $2, $3, $4, $5)",
+			t.ID, item.Name, item.Price, item.Quantity, item.Sum)
+		if err != nil {
+			return err
+		}
+	}
+	r.TransactionID = t.ID
+	return commitCreated(tx, 1)
+}
+
+// GetReceipt возвращает чек транзакции пользователя. Если у транзакции нет чека, возвращается nil.
+func (s *Storage) GetReceipt(transactionID, userID int) (*models.Receipt, error) {
+	r := models.Receipt{Items: []models.ReceiptItem{}}
+	err := s.DB.QueryRow(`SELECT transaction_id, fn, fd, fp, seller, seller_inn, date, total FROM receipts
+		WHERE transaction_id = $1 AND user_id = $2`, transactionID, userID).
+		Scan(&r.TransactionID, &r.FN, &r.FD, &r.FP, &r.Seller, &r.SellerINN, &r.Date, &r.Total)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Query("SELECT name, price, quantity, sum FROM receipt_items WHERE transaction_id = $1 ORDER BY id", transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item models.ReceiptItem
+		if err := rows.Scan(&item.Name, &item.Price, &item.Quantity, &item.Sum); err != nil {
+			return nil, err
+		}
+		r.Items = append(r.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// GetReportEmailPreferences возвращает подписки пользователя на письма с отчетами.
+func (s *Storage) GetReportEmailPreferences(userID int) (*models.ReportEmailPreferences, error) {
+	var preferences models.ReportEmailPreferences
+	err := s.DB.QueryRow(`SELECT COALESCE(bool_or(period = 'week'), false), COALESCE(bool_or(period = 'month'), false)
+		FROM report_emails WHERE user_id = $1`, userID).Scan(&preferences.Weekly, &preferences.Monthly)
+	if err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// SetReportEmailPreferences подписывает пользователя на письма с отчетами и отписывает от них.
+// Существующая подписка сохраняется вместе с последним отправленным периодом.
+func (s *Storage) SetReportEmailPreferences(userID int, preferences models.ReportEmailPreferences) error {
+	periods := []string{}
+	if preferences.Weekly {
+		periods = append(periods, "week")
+	}
+	if preferences.Monthly {
+		periods = append(periods, "month")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM report_emails WHERE user_id = $1 AND period <> ALL($2)", userID, pq.Array(periods)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO report_emails (user_id, period) SELECT $1, unnest($2::text[])
+		ON CONFLICT (user_id, period) DO NOTHING`, userID, pq.Array(periods)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ClaimReportEmails отмечает письма за период вида period (week или month) с первого дня start по последний end
+// отправленными и возвращает пользователей с email, которым их нужно отправить. Подписавшиеся после конца периода
+// пропускаются. Отмеченное письмо не возвращается повторно, даже если его не удалось отправить.
+func (s *Storage) ClaimReportEmails(period string, start, end time.Time) ([]models.User, error) {
+	rows, err := s.DB.Query(`UPDATE report_emails r SET sent_start = $2
+		FROM users u
+		WHERE u.id = r.user_id AND u.email IS NOT NULL AND r.period = $1
+			AND r.created_at < $3 AND (r.sent_start IS NULL OR r.sent_start < $2)
+		RETURNING u.id, u.username, u.email, u.base_currency, u.language`,
+		period, start.Format("2006-01-02"), end.AddDate(0, 0, 1).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.BaseCurrency, &user.Language); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
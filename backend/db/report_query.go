@@ -0,0 +1,256 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/period"
+)
+
+// ErrInvalidReportQuery — описание пользовательского отчета не проходит проверку.
+var ErrInvalidReportQuery = errors.New("invalid report query")
+
+const (
+	maxReportDimensions = 4
+	// DefaultReportLimit и MaxReportLimit — число строк пользовательского отчета по умолчанию и наибольшее.
+	DefaultReportLimit = 1000
+	MaxReportLimit     = 10000
+)
+
+// reportDimension — выражение измерения и соединение, которое ему нужно.
+type reportDimension struct {
+	expr string
+	join string
+}
+
+// reportDimensions — допустимые измерения пользовательского отчета. Транзакция с несколькими тегами
+// входит в группу каждого тега, транзакция без тега — в группу null.
+var reportDimensions = map[string]reportDimension{
+	"category": {"c.name", "LEFT JOIN categories c ON c.id = t.category_id"},
+	"payee":    {"p.name", "LEFT JOIN payees p ON p.id = t.payee_id"},
+	"account":  {"a.name", "LEFT JOIN accounts a ON a.id = t.account_id"},
+	"tag":      {"tg.name", "LEFT JOIN transaction_tags tt ON tt.transaction_id = t.id LEFT JOIN tags tg ON tg.id = tt.tag_id"},
+	"currency": {"t.currency", ""},
+	"type":     {"t.type", ""},
+	"day":      {"to_char(t.date, 'YYYY-MM-DD')", ""},
+}
+
+// reportPeriods — формат измерений-интервалов пользовательского отчета. Интервал обозначается по первому дню,
+// поэтому при дне начала месяца больше первого числа месяц с 25 июня по 24 июля обозначается 2025-06.
+var reportPeriods = map[string]string{
+	period.Week:    "YYYY-MM-DD",
+	period.Month:   "YYYY-MM",
+	period.Quarter: `YYYY-"Q"Q`,
+	period.Year:    "YYYY",
+}
+
+// reportMetrics — допустимые показатели пользовательского отчета. Доходы и расходы считаются за вычетом возвратов.
+var reportMetrics = map[string]string{
+	"count":   "COUNT(*)",
+	"income":  netIncomeColumn("t."),
+	"expense": netExpenseColumn("t."),
+	"net":     netIncomeColumn("t.") + " - " + netExpenseColumn("t."),
+	"average": "ROUND(AVG(t.amount), 2)",
+	"min":     "MIN(t.amount)",
+	"max":     "MAX(t.amount)",
+}
+
+func invalidReportQuery(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidReportQuery, fmt.Sprintf(format, args...))
+}
+
+// buildReportQuery проверяет описание отчета по спискам допустимых измерений и показателей и переводит его в SQL.
+// Имена измерений и показателей подставляются в запрос только из этих списков, значения фильтров — параметрами.
+// Если запрошены денежные показатели без фильтра по валюте, к измерениям добавляется валюта, чтобы не складывать суммы в разных валютах.
+// Месяцы, кварталы и годы начинаются с дня monthStart. Возвращает запрос, его параметры и названия столбцов.
+func buildReportQuery(userID, monthStart int, q models.ReportQuery) (string, []interface{}, []string, error) {
+	if len(q.Metrics) == 0 {
+		return "", nil, nil, invalidReportQuery("at least one metric is required")
+	}
+	groupBy := append([]string{}, q.GroupBy...)
+	columns := make(map[string]bool)
+	monetary := false
+	for _, metric := range q.Metrics {
+		if _, ok := reportMetrics[metric]; !ok {
+			return "", nil, nil, invalidReportQuery("unknown metric %q", metric)
+		}
+		if columns[metric] {
+			return "", nil, nil, invalidReportQuery("metric %q appears more than once", metric)
+		}
+		columns[metric] = true
+		monetary = monetary || metric != "count"
+	}
+	dimensions := make(map[string]reportDimension, len(groupBy))
+	for _, dimension := range groupBy {
+		d, ok := reportDimensions[dimension]
+		if format, isPeriod := reportPeriods[dimension]; isPeriod {
+			bucket, err := periodBucket(period.Grouping{Kind: dimension, MonthStart: monthStart}, "t.date")
+			if err != nil {
+				return "", nil, nil, err
+			}
+			d, ok = reportDimension{expr: fmt.Sprintf("to_char(%s, '%s')", bucket, format)}, true
+		}
+		if !ok {
+			return "", nil, nil, invalidReportQuery("unknown dimension %q", dimension)
+		}
+		dimensions[dimension] = d
+		if columns[dimension] {
+			return "", nil, nil, invalidReportQuery("dimension %q appears more than once", dimension)
+		}
+		columns[dimension] = true
+	}
+	if monetary && q.Filters.Currency == "" && !columns["currency"] {
+		groupBy = append(groupBy, "currency")
+		columns["currency"] = true
+		dimensions["currency"] = reportDimensions["currency"]
+	}
+	if len(groupBy) > maxReportDimensions {
+		return "", nil, nil, invalidReportQuery("at most %d dimensions are allowed", maxReportDimensions)
+	}
+	if q.OrderBy != "" && !columns[q.OrderBy] {
+		return "", nil, nil, invalidReportQuery("order_by must be one of the requested dimensions or metrics")
+	}
+
+	args := []interface{}{userID}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where := []string{"t.user_id = $1", "t.deleted_at IS NULL", "t.transfer_id IS NULL", "NOT t.adjustment"}
+	f := q.Filters
+	if !f.IncludePlanned {
+		where = append(where, "NOT t.planned")
+	}
+	if f.DateFrom != nil {
+		where = append(where, "t.date >= "+arg(*f.DateFrom))
+	}
+	if f.DateTo != nil {
+		where = append(where, "t.date < "+arg(f.DateTo.AddDate(0, 0, 1)))
+	}
+	if f.Type != "" {
+		if f.Type != "income" && f.Type != "expense" {
+			return "", nil, nil, invalidReportQuery("type must be 'income' or 'expense'")
+		}
+		where = append(where, "t.type = "+arg(f.Type))
+	}
+	if len(f.CategoryIDs) > 0 {
+		where = append(where, "t.category_id = ANY("+arg(pq.Array(f.CategoryIDs))+")")
+	}
+	if len(f.AccountIDs) > 0 {
+		where = append(where, "t.account_id = ANY("+arg(pq.Array(f.AccountIDs))+")")
+	}
+	if len(f.PayeeIDs) > 0 {
+		where = append(where, "t.payee_id = ANY("+arg(pq.Array(f.PayeeIDs))+")")
+	}
+	if len(f.Tags) > 0 {
+		where = append(where, `EXISTS (SELECT 1 FROM transaction_tags ft JOIN tags fg ON fg.id = ft.tag_id
+			WHERE ft.transaction_id = t.id AND fg.name = ANY(`+arg(pq.Array(f.Tags))+`))`)
+	}
+	if f.Currency != "" {
+		where = append(where, "t.currency = "+arg(f.Currency))
+	}
+	if f.MinAmount != nil {
+		where = append(where, "t.amount >= "+arg(*f.MinAmount))
+	}
+	if f.MaxAmount != nil {
+		where = append(where, "t.amount <= "+arg(*f.MaxAmount))
+	}
+
+	var selects, joins, groups []string
+	for i, dimension := range groupBy {
+		d := dimensions[dimension]
+		selects = append(selects, d.expr)
+		groups = append(groups, fmt.Sprint(i+1))
+		if d.join != "" {
+			joins = append(joins, d.join)
+		}
+	}
+	for _, metric := range q.Metrics {
+		selects = append(selects, reportMetrics[metric])
+	}
+	names := append(groupBy, q.Metrics...)
+
+	query := "SELECT " + strings.Join(selects, ", ") + " FROM transactions t"
+	if len(joins) > 0 {
+		query += " " + strings.Join(joins, " ")
+	}
+	query += " WHERE " + strings.Join(where, " AND ")
+	if len(groups) > 0 {
+		query += " GROUP BY " + strings.Join(groups, ", ")
+	}
+	var order []string
+	if q.OrderBy != "" {
+		for i, name := range names {
+			if name == q.OrderBy {
+				direction := "ASC"
+				if q.Desc {
+					direction = "DESC"
+				}
+				order = append(order, fmt.Sprintf("%d %s NULLS LAST", i+1, direction))
+			}
+		}
+	}
+	order = append(order, groups...)
+	if len(order) > 0 {
+		query += " ORDER BY " + strings.Join(order, ", ")
+	}
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultReportLimit
+	}
+	query += " LIMIT " + arg(limit)
+	return query, args, names, nil
+}
+
+// RunReportQuery выполняет пользовательский отчет по транзакциям пользователя. Переводы и корректировки не учитываются.
+// Месяцы, кварталы и годы начинаются с дня monthStart. Ошибки в описании отчета возвращаются как ErrInvalidReportQuery.
+func (s *Storage) RunReportQuery(userID, monthStart int, q models.ReportQuery) (*models.ReportQueryResult, error) {
+	query, args, columns, err := buildReportQuery(userID, monthStart, q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dimensions := len(columns) - len(q.Metrics)
+	result := &models.ReportQueryResult{Columns: columns, Rows: [][]interface{}{}}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range columns {
+			switch {
+			case i < dimensions:
+				targets[i] = new(*string)
+			case columns[i] == "count":
+				targets[i] = new(int64)
+			default:
+				targets[i] = new(*models.Money)
+			}
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		for i, target := range targets {
+			switch v := target.(type) {
+			case **string:
+				if *v != nil {
+					values[i] = **v
+				}
+			case *int64:
+				values[i] = *v
+			case **models.Money:
+				if *v != nil {
+					values[i] = **v
+				}
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+}
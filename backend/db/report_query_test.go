@@ -0,0 +1,72 @@
+package db
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestBuildReportQuery тестирует проверку описания пользовательского отчета и построение запроса.
+func TestBuildReportQuery(t *testing.T) {
+	for _, invalid := range []models.ReportQuery{
+		{},
+		{Metrics: []string{"sum"}},
+		{Metrics: []string{"count", "count"}},
+		{Metrics: []string{"count"}, GroupBy: []string{"t.amount; DROP TABLE users"}},
+		{Metrics: []string{"count"}, GroupBy: []string{"month", "month"}},
+		{Metrics: []string{"count"}, GroupBy: []string{"day", "week", "month", "year", "type"}},
+		{Metrics: []string{"expense"}, GroupBy: []string{"day", "week", "month", "year"}},
+		{Metrics: []string{"count"}, OrderBy: "expense"},
+		{Metrics: []string{"count"}, Filters: models.ReportQueryFilters{Type: "transfer"}},
+	} {
+		if _, _, _, err := buildReportQuery(1, 1, invalid); !errors.Is(err, ErrInvalidReportQuery) {
+			t.Errorf("Expected invalid report query for %+v, got %v", invalid, err)
+		}
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	query, args, columns, err := buildReportQuery(7, 1, models.ReportQuery{
+		GroupBy: []string{"month", "category"},
+		Metrics: []string{"expense", "count"},
+		Filters: models.ReportQueryFilters{DateFrom: &from, DateTo: &to, Tags: []string{"vacation"}},
+		OrderBy: "expense",
+		Desc:    true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to build report query: %v", err)
+	}
+	// Денежный показатель без фильтра по валюте группируется еще и по валюте
+	if !reflect.DeepEqual(columns, []string{"month", "category", "currency", "expense", "count"}) {
+		t.Errorf("Unexpected columns: %v", columns)
+	}
+	if len(args) != 5 || args[0] != 7 || args[2] != to.AddDate(0, 0, 1) || args[4] != DefaultReportLimit {
+		t.Errorf("Unexpected args: %v", args)
+	}
+	for _, part := range []string{"LEFT JOIN categories c", "t.user_id = $1", "NOT t.planned", "GROUP BY 1, 2, 3", "ORDER BY 4 DESC NULLS LAST, 1, 2, 3", "LIMIT $5"} {
+		if !strings.Contains(query, part) {
+			t.Errorf("Expected %q in query %s", part, query)
+		}
+	}
+
+	_, _, columns, err = buildReportQuery(7, 1, models.ReportQuery{Metrics: []string{"net"}, Filters: models.ReportQueryFilters{Currency: "RUB"}})
+	if err != nil || !reflect.DeepEqual(columns, []string{"net"}) {
+		t.Errorf("Expected single net column with currency filter, got %v %v", columns, err)
+	}
+
+	// Месяцы с 25 числа — календарные месяцы, сдвинутые на 24 дня
+	query, _, _, err = buildReportQuery(7, 25, models.ReportQuery{GroupBy: []string{"month", "week"}, Metrics: []string{"count"}})
+	if err != nil {
+		t.Fatalf("Failed to build report query: %v", err)
+	}
+	for _, part := range []string{"to_char((date_trunc('month', t.date - INTERVAL '24 days') + INTERVAL '24 days')::date, 'YYYY-MM')",
+		"to_char(date_trunc('week', t.date)::date, 'YYYY-MM-DD')"} {
+		if !strings.Contains(query, part) {
+			t.Errorf("Expected %q in query %s", part, query)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/nemopss/fin-ng/backend/period"
+)
+
+// GetPeriodTransactions возвращает транзакции пользователя за период [from, to) по возрастанию даты.
+// Запланированные транзакции возвращаются только при includePlanned.
+func (s *Storage) GetPeriodTransactions(userID int, from, to time.Time, includePlanned bool) ([]models.Transaction, error) {
+	return s.queryTransactions("SELECT "+transactionColumns+` FROM transactions
+		WHERE user_id = $1 AND deleted_at IS NULL AND date >= $2 AND date < $3 AND (NOT planned OR $4)
+		ORDER BY date, id`, userID, from, to, includePlanned)
+}
+
+// GetCategoryTotals возвращает суммы доходов и расходов за период [from, to),
+// сгруппированные по категории и валюте. Транзакции без категории попадают в группу с ID 0.
+// Возвраты уменьшают расход своей категории, переводы между счетами и корректировки остатков не учитываются.
+// Запланированные транзакции учитываются только при includePlanned.
+func (s *Storage) GetCategoryTotals(userID int, from, to time.Time, includePlanned bool) ([]models.CategoryTotals, error) {
+	rows, err := s.DB.Query(`SELECT COALESCE(c.id, 0), COALESCE(c.name, ''), t.currency, `+netTotalsColumns("t.")+`
+		FROM transactions t LEFT JOIN categories c ON c.id = t.category_id
+		WHERE t.user_id = $1 AND t.deleted_at IS NULL AND t.transfer_id IS NULL AND NOT t.adjustment AND t.date >= $2 AND t.date < $3 AND (NOT t.planned OR $4)
+		GROUP BY c.id, c.name, t.currency
+		ORDER BY c.name NULLS LAST, t.currency`, userID, from, to, includePlanned)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []models.CategoryTotals{}
+	for rows.Next() {
+		var t models.CategoryTotals
+		if err := rows.Scan(&t.CategoryID, &t.Category, &t.Currency, &t.Income, &t.Expense); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// GetCategoryStats возвращает итоги категории по валютам и помесячный ряд за период [from, to).
+// Нулевой from означает период с первой транзакции категории. Запланированные транзакции не учитываются.
+// Для чужой или несуществующей категории возвращается nil.
+func (s *Storage) GetCategoryStats(id, userID int, from, to time.Time) (*models.CategoryStats, error) {
+	category, err := s.GetCategory(id, userID)
+	if err != nil || category == nil {
+		return nil, err
+	}
+
+	stats := &models.CategoryStats{CategoryID: category.ID, Name: category.Name, To: to,
+		Totals: []models.CategoryStatsTotals{}, Monthly: []models.CategoryMonthStats{}}
+	if !from.IsZero() {
+		stats.From = &from
+	}
+
+	const where = `WHERE user_id = $1 AND category_id = $2 AND deleted_at IS NULL AND NOT planned AND date >= $3 AND date < $4`
+	rows, err := s.DB.Query(`SELECT currency, COUNT(*), `+netTotalsColumns("")+`, ROUND(AVG(amount), 2)
+		FROM transactions `+where+`
+		GROUP BY currency ORDER BY currency`, userID, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t models.CategoryStatsTotals
+		if err := rows.Scan(&t.Currency, &t.Count, &t.Income, &t.Expense, &t.Average); err != nil {
+			return nil, err
+		}
+		stats.Totals = append(stats.Totals, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(stats.Totals) == 0 {
+		return stats, nil
+	}
+
+	rows, err = s.DB.Query(`SELECT date_trunc('month', date), currency, COUNT(*), `+netTotalsColumns("")+`
+		FROM transactions `+where+`
+		GROUP BY 1, 2`, userID, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	months := make(map[string]models.CategoryMonthStats)
+	first := to
+	for rows.Next() {
+		var month time.Time
+		var m models.CategoryMonthStats
+		if err := rows.Scan(&month, &m.Currency, &m.Count, &m.Income, &m.Expense); err != nil {
+			return nil, err
+		}
+		m.Month = month.Format("2006-01")
+		months[m.Month+m.Currency] = m
+		if month.Before(first) {
+			first = month
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Ряд непрерывный: месяцы без транзакций заполняются нулями
+	if !from.IsZero() {
+		first = from
+	}
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); month.Before(to); month = month.AddDate(0, 1, 0) {
+		for _, t := range stats.Totals {
+			key := month.Format("2006-01")
+			m, ok := months[key+t.Currency]
+			if !ok {
+				m = models.CategoryMonthStats{Month: key, Currency: t.Currency}
+			}
+			stats.Monthly = append(stats.Monthly, m)
+		}
+	}
+	return stats, nil
+}
+
+// GetCategoriesWithStats возвращает категории пользователя вместе с системными, число их транзакций
+// и суммы по валютам за все время одним запросом. Возвраты уменьшают расход своей категории,
+// запланированные и удаленные транзакции не учитываются.
+func (s *Storage) GetCategoriesWithStats(userID int) ([]models.Category, error) {
+	rows, err := s.DB.Query(`SELECT c.id, COALESCE(c.user_id, 0), c.name, c.icon, c.color, c.user_id IS NULL, t.currency, COUNT(t.id), `+netTotalsColumns("t.")+`
+		FROM categories c LEFT JOIN transactions t ON t.category_id = c.id AND t.user_id = $1 AND t.deleted_at IS NULL AND NOT t.planned
+		WHERE c.user_id = $1 OR c.user_id IS NULL
+		GROUP BY c.id, t.currency
+		ORDER BY c.id, t.currency`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var c models.Category
+		var currency sql.NullString
+		var count int
+		var totals models.TransactionTotals
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Icon, &c.Color, &c.System, &currency, &count, &totals.Income, &totals.Expense); err != nil {
+			return nil, err
+		}
+		// Строки одной категории идут подряд, по одной на валюту
+		if n := len(categories); n == 0 || categories[n-1].ID != c.ID {
+			c.TransactionCount = new(int)
+			c.Totals = []models.TransactionTotals{}
+			categories = append(categories, c)
+		}
+		last := &categories[len(categories)-1]
+		if currency.Valid {
+			totals.Currency = currency.String
+			*last.TransactionCount += count
+			last.Totals = append(last.Totals, totals)
+		}
+	}
+	return categories, rows.Err()
+}
+
+// GetBudgetVsActual возвращает бюджеты и расходы по категориям, месяцам и валютам за период [from, to)
+// по границам месяцев. Учитываются только месячные бюджеты категорий. Строка есть у каждого бюджета и у каждой категории с расходами без бюджета.
+// Расходы считаются за вычетом возвратов, без запланированных транзакций, переводов и корректировок.
+func (s *Storage) GetBudgetVsActual(userID int, from, to time.Time) ([]models.BudgetVsActualRow, error) {
+	rows, err := s.DB.Query(`WITH actual (category_id, month, currency, income, expense) AS (
+			SELECT t.category_id, date_trunc('month', t.date)::date, t.currency, `+netTotalsColumns("t.")+`
+			FROM transactions t
+			WHERE t.user_id = $1 AND t.category_id IS NOT NULL AND t.deleted_at IS NULL AND NOT t.planned AND t.date >= $2 AND t.date < $3
+			GROUP BY 1, 2, 3
+		), planned AS (
+			SELECT category_id, start_date AS month, currency, amount FROM budgets
+			WHERE user_id = $1 AND category_id IS NOT NULL AND period = 'month' AND start_date >= $2 AND start_date < $3
+		)
+		SELECT to_char(COALESCE(b.month, a.month), 'YYYY-MM') AS month, c.id, c.name, COALESCE(b.currency, a.currency) AS currency,
+			COALESCE(b.amount, 0), COALESCE(a.expense, 0)
+		FROM planned b FULL JOIN actual a ON a.category_id = b.category_id AND a.month = b.month AND a.currency = b.currency
+		JOIN categories c ON c.id = COALESCE(b.category_id, a.category_id)
+		WHERE b.category_id IS NOT NULL OR a.expense <> 0
+		ORDER BY month, c.name, currency`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := []models.BudgetVsActualRow{}
+	for rows.Next() {
+		var r models.BudgetVsActualRow
+		if err := rows.Scan(&r.Month, &r.CategoryID, &r.CategoryName, &r.Currency, &r.Budgeted, &r.Actual); err != nil {
+			return nil, err
+		}
+		r.Variance = r.Budgeted - r.Actual
+		report = append(report, r)
+	}
+	return report, rows.Err()
+}
+
+// GetPeriodSummary возвращает доходы, расходы за вычетом возвратов и число транзакций пользователя
+// за дни [from, to) по валютам. Переводы и корректировки не учитываются ни в суммах, ни в числе транзакций.
+func (s *Storage) GetPeriodSummary(userID int, from, to time.Time, includePlanned bool) ([]models.CurrencySummary, error) {
+	rows, err := s.DB.Query(`SELECT currency, `+netTotalsColumns("")+`, COUNT(*) FILTER (WHERE transfer_id IS NULL AND NOT adjustment)
+		FROM transactions
+		WHERE user_id = $1 AND deleted_at IS NULL AND date >= $2 AND date < $3 AND (NOT planned OR $4)
+		GROUP BY currency
+		HAVING COUNT(*) FILTER (WHERE transfer_id IS NULL AND NOT adjustment) > 0
+		ORDER BY currency`, userID, from, to, includePlanned)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := []models.CurrencySummary{}
+	for rows.Next() {
+		var row models.CurrencySummary
+		if err := rows.Scan(&row.Currency, &row.Income, &row.Expense, &row.Count); err != nil {
+			return nil, err
+		}
+		row.Net = row.Income - row.Expense
+		summary = append(summary, row)
+	}
+	return summary, rows.Err()
+}
+
+// GetTimeSeries возвращает доходы и расходы за вычетом возвратов по интервалам группировки g
+// за дни [from, to] по валютам. Интервалы идут подряд, включая пустые; первый интервал начинается
+// с начала интервала, содержащего from, но транзакции раньше from не учитываются.
+// Переводы и корректировки не учитываются.
+func (s *Storage) GetTimeSeries(userID int, g period.Grouping, from, to time.Time, includePlanned bool) ([]models.TimeSeriesPoint, error) {
+	bucket, err := periodBucket(g, "date")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.DB.Query(`WITH totals (bucket, currency, income, expense) AS (
+			SELECT `+bucket+`, currency, `+netTotalsColumns("")+`
+			FROM transactions
+			WHERE user_id = $1 AND deleted_at IS NULL AND date >= $2::timestamp AND date < $3::timestamp + INTERVAL '1 day' AND (NOT planned OR $4)
+				AND transfer_id IS NULL AND NOT adjustment
+			GROUP BY 1, 2
+		)
+		SELECT s.bucket::date, t.currency, COALESCE(t.income, 0), COALESCE(t.expense, 0)
+		FROM generate_series($5::timestamp, $3::timestamp, $6::interval) AS s(bucket)
+		LEFT JOIN totals t ON t.bucket = s.bucket::date
+		ORDER BY 1, 2`, userID, from, to, includePlanned, g.Start(from), periodIntervals[g.Kind])
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []models.TimeSeriesPoint{}
+	for rows.Next() {
+		var bucket time.Time
+		var currency sql.NullString
+		var totals models.TransactionTotals
+		if err := rows.Scan(&bucket, &currency, &totals.Income, &totals.Expense); err != nil {
+			return nil, err
+		}
+		start := bucket.Format("2006-01-02")
+		if len(points) == 0 || points[len(points)-1].Start != start {
+			points = append(points, models.TimeSeriesPoint{Start: start, ByCurrency: []models.TransactionTotals{}})
+		}
+		if currency.Valid {
+			totals.Currency = currency.String
+			point := &points[len(points)-1]
+			point.ByCurrency = append(point.ByCurrency, totals)
+		}
+	}
+	return points, rows.Err()
+}
+
+// GetSpendingTrends возвращает по категориям и валютам расходы за вычетом возвратов за дни [current, to)
+// и средние расходы за window предыдущих периодов, которые занимают дни [from, current).
+// Запланированные транзакции, переводы и корректировки не учитываются; категории без расходов пропускаются.
+func (s *Storage) GetSpendingTrends(userID int, from, current, to time.Time, window int) ([]models.SpendingTrend, error) {
+	rows, err := s.DB.Query(`WITH spend (category_id, currency, is_current, income, expense) AS (
+			SELECT t.category_id, t.currency, t.date >= $3, `+netTotalsColumns("t.")+`
+			FROM transactions t
+			WHERE t.user_id = $1 AND t.category_id IS NOT NULL AND t.deleted_at IS NULL AND NOT t.planned AND t.date >= $2 AND t.date < $4
+			GROUP BY 1, 2, 3
+		)
+		SELECT c.id, c.name, s.currency, COALESCE(SUM(s.expense) FILTER (WHERE s.is_current), 0),
+			ROUND(COALESCE(SUM(s.expense) FILTER (WHERE NOT s.is_current), 0) / $5, 2)
+		FROM spend s JOIN categories c ON c.id = s.category_id
+		GROUP BY c.id, c.name, s.currency
+		HAVING bool_or(s.expense <> 0)
+		ORDER BY c.name, s.currency`, userID, from, current, to, window)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trends := []models.SpendingTrend{}
+	for rows.Next() {
+		var trend models.SpendingTrend
+		if err := rows.Scan(&trend.CategoryID, &trend.CategoryName, &trend.Currency, &trend.Current, &trend.Average); err != nil {
+			return nil, err
+		}
+		trends = append(trends, trend)
+	}
+	return trends, rows.Err()
+}
+
+// DailyTotals — суммы доходов и расходов за день в одной валюте.
+type DailyTotals struct {
+	Date time.Time
+	models.TransactionTotals
+}
+
+// GetPlannedTotals возвращает суммы запланированных транзакций пользователя до дня to (не включая его)
+// по дням и валютам. Просроченные запланированные транзакции относятся ко дню from.
+// Переводы и корректировки не учитываются.
+func (s *Storage) GetPlannedTotals(userID int, from, to time.Time) ([]DailyTotals, error) {
+	rows, err := s.DB.Query(`SELECT GREATEST(date::date, $2::date), currency, `+netTotalsColumns("")+`
+		FROM transactions
+		WHERE user_id = $1 AND planned AND deleted_at IS NULL AND date < $3 AND transfer_id IS NULL AND NOT adjustment
+		GROUP BY 1, 2
+		ORDER BY 1, 2`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []DailyTotals{}
+	for rows.Next() {
+		var t DailyTotals
+		if err := rows.Scan(&t.Date, &t.Currency, &t.Income, &t.Expense); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// GetPayeeSpend возвращает расходы за вычетом возвратов и число расходных транзакций пользователя
+// по контрагентам за дни [from, to) с суммами по валютам. Контрагенты без расходных транзакций пропускаются.
+// Запланированные транзакции, переводы и корректировки не учитываются.
+func (s *Storage) GetPayeeSpend(userID int, from, to time.Time) ([]models.TopPayee, error) {
+	rows, err := s.DB.Query(`SELECT p.id, p.name, t.currency, `+netExpenseColumn("t.")+`, COUNT(*) FILTER (WHERE t.type = 'expense')
+		FROM transactions t JOIN payees p ON p.id = t.payee_id
+		WHERE t.user_id = $1 AND t.deleted_at IS NULL AND NOT t.planned AND t.date >= $2 AND t.date < $3
+			AND t.transfer_id IS NULL AND NOT t.adjustment
+		GROUP BY p.id, p.name, t.currency
+		HAVING COUNT(*) FILTER (WHERE t.type = 'expense') > 0
+		ORDER BY p.id, t.currency`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payees := []models.TopPayee{}
+	for rows.Next() {
+		var payee models.TopPayee
+		var totals models.TransactionTotals
+		var visits int
+		if err := rows.Scan(&payee.PayeeID, &payee.Name, &totals.Currency, &totals.Expense, &visits); err != nil {
+			return nil, err
+		}
+		if len(payees) == 0 || payees[len(payees)-1].PayeeID != payee.PayeeID {
+			payee.Totals = []models.TransactionTotals{}
+			payees = append(payees, payee)
+		}
+		last := &payees[len(payees)-1]
+		last.Visits += visits
+		last.Totals = append(last.Totals, totals)
+	}
+	return payees, rows.Err()
+}
+
+// GetSpendingStats возвращает статистику расходов за вычетом возвратов по валютам за дни [from, to):
+// сумму, среднее, медиану и стандартное отклонение дневных расходов, расходы по дням недели и самый крупный расход.
+// Дни без расходов входят в дневную статистику с нулем; валюты без расходов пропускаются.
+// Запланированные транзакции, переводы и корректировки не учитываются.
+func (s *Storage) GetSpendingStats(userID int, from, to time.Time) ([]models.CurrencySpendingStats, error) {
+	// Общие показатели валюты и показатели по дням недели считаются одним запросом через GROUPING SETS;
+	// у строк с общими показателями день недели равен NULL
+	rows, err := s.DB.Query(`WITH spend (day, currency, expense, expenses) AS (
+			SELECT date::date, currency, `+netExpenseColumn("")+`, COUNT(*) FILTER (WHERE type = 'expense')
+			FROM transactions
+			WHERE user_id = $1 AND deleted_at IS NULL AND NOT planned AND date >= $2::timestamp AND date < $3::timestamp
+				AND transfer_id IS NULL AND NOT adjustment
+			GROUP BY 1, 2
+		), daily (currency, day, expense, expenses) AS (
+			SELECT c.currency, d.day::date, COALESCE(s.expense, 0), COALESCE(s.expenses, 0)
+			FROM (SELECT currency FROM spend GROUP BY currency HAVING bool_or(expense <> 0)) c
+			CROSS JOIN generate_series($2::timestamp, $3::timestamp - INTERVAL '1 day', INTERVAL '1 day') AS d(day)
+			LEFT JOIN spend s ON s.currency = c.currency AND s.day = d.day::date
+		)
+		SELECT currency, EXTRACT(ISODOW FROM day)::int, SUM(expense), SUM(expenses), ROUND(AVG(expense), 2),
+			ROUND((percentile_cont(0.5) WITHIN GROUP (ORDER BY expense))::numeric, 2), ROUND(COALESCE(stddev_pop(expense), 0), 2)
+		FROM daily
+		GROUP BY GROUPING SETS ((currency), (currency, EXTRACT(ISODOW FROM day)::int))
+		ORDER BY 1, 2 NULLS FIRST`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.CurrencySpendingStats{}
+	for rows.Next() {
+		var currency string
+		var weekday sql.NullInt32
+		var total, mean, median, stddev models.Money
+		var expenses int
+		if err := rows.Scan(&currency, &weekday, &total, &expenses, &mean, &median, &stddev); err != nil {
+			return nil, err
+		}
+		if !weekday.Valid {
+			stats = append(stats, models.CurrencySpendingStats{
+				Currency: currency, Total: total, ExpenseCount: expenses,
+				MeanDaily: mean, MedianDaily: median, StdDevDaily: stddev,
+				ByWeekday: []models.WeekdaySpend{},
+			})
+			continue
+		}
+		row := &stats[len(stats)-1]
+		row.ByWeekday = append(row.ByWeekday, models.WeekdaySpend{Weekday: int(weekday.Int32), Total: total, Average: mean})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	largest, err := s.queryTransactions(`SELECT DISTINCT ON (currency) `+transactionColumns+` FROM transactions
+		WHERE user_id = $1 AND deleted_at IS NULL AND NOT planned AND date >= $2 AND date < $3
+			AND type = 'expense' AND transfer_id IS NULL AND NOT adjustment
+		ORDER BY currency, amount DESC, date, id`, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stats {
+		for j := range largest {
+			if largest[j].Currency == stats[i].Currency {
+				stats[i].LargestExpense = &largest[j]
+			}
+		}
+	}
+	return stats, nil
+}
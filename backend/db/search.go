@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// searchFrom — источник и условие полнотекстового поиска. $1 — пользователь,
+// $2 — запрос в синтаксисе websearch_to_tsquery, $3 — он же для поиска по подстроке.
+const searchFrom = `FROM transactions tr
+	CROSS JOIN websearch_to_tsquery('russian', $2) AS q(query)
+	LEFT JOIN categories c ON c.id = tr.category_id
+	LEFT JOIN payees p ON p.id = tr.payee_id
+	WHERE tr.user_id = $1 AND tr.deleted_at IS NULL AND (
+		tr.search_vector @@ q.query
+		OR to_tsvector('russian', COALESCE(c.name, '')) @@ q.query
+		OR to_tsvector('russian', COALESCE(p.name, '')) @@ q.query
+		OR tr.description ILIKE '%' || $3 || '%'
+		OR c.name ILIKE '%' || $3 || '%'
+		OR p.name ILIKE '%' || $3 || '%'
+	)`
+
+// SearchTransactions ищет транзакции пользователя по описанию, названию категории и имени контрагента.
+// Результаты упорядочены по релевантности; в описании совпадения выделены тегом <mark>.
+func (s *Storage) SearchTransactions(userID int, query string, page, limit int) ([]models.TransactionSearchResult, int, error) {
+	args := []interface{}{userID, query, likeEscaper.Replace(query)}
+
+	var total int
+	if err := s.DB.QueryRow("SELECT COUNT(*) "+searchFrom, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// Вложенный запрос назван transactions, чтобы transactionColumns
+	// (включая подзапрос тегов) ссылались на его строки
+	rows, err := s.DB.Query(fmt.Sprintf(`SELECT %s, rank, highlight FROM (
+		SELECT tr.*,
+			ts_rank(tr.search_vector, q.query)
+				+ 0.5 * ts_rank(to_tsvector('russian', COALESCE(c.name, '')), q.query)
+				+ 0.5 * ts_rank(to_tsvector('russian', COALESCE(p.name, '')), q.query)
+				+ CASE WHEN tr.description ILIKE '%%' || $3 || '%%' THEN 0.1 ELSE 0 END AS rank,
+			ts_headline('russian', tr.description, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight
+		%s
+	) AS transactions ORDER BY rank DESC, date DESC, id DESC LIMIT $4 OFFSET $5`, transactionColumns, searchFrom),
+		append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := []models.TransactionSearchResult{}
+	for rows.Next() {
+		var r models.TransactionSearchResult
+		scanner := extraColumns{row: rows, extra: []interface{}{&r.Rank, &r.Highlight}}
+		if r.Transaction, err = scanTransaction(scanner); err != nil {
+			return nil, 0, err
+		}
+		results = append(results, r)
+	}
+	return results, total, rows.Err()
+}
+
+// extraColumns дочитывает столбцы, следующие за столбцами транзакции.
+type extraColumns struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func (e extraColumns) Scan(dest ...interface{}) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
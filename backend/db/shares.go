@@ -0,0 +1,163 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// accountAccess возвращает условие доступа к счету alias для пользователя из параметра userParam:
+// пользователь — владелец счета или счет ему открыт; с edit — открыт с правом редактирования.
+func accountAccess(alias, userParam string, edit bool) string {
+	role := ""
+	if edit {
+		role = " AND s.role = 'editor'"
+	}
+	return fmt.Sprintf("(%[1]s.user_id = %[2]s OR EXISTS (SELECT 1 FROM account_shares s WHERE s.account_id = %[1]s.id AND s.user_id = %[2]s%[3]s))",
+		alias, userParam, role)
+}
+
+// setAccountRoles заполняет роль пользователя userID для счетов: owner для своих счетов, для чужих — роль доступа.
+func (s *Storage) setAccountRoles(userID int, accounts []models.Account) error {
+	shared := false
+	for i := range accounts {
+		if accounts[i].UserID == userID {
+			accounts[i].Role = "owner"
+		} else {
+			shared = true
+		}
+	}
+	if !shared {
+		return nil
+	}
+
+	rows, err := s.DB.Query("SELECT account_id, role FROM account_shares WHERE user_id = $1", userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	roles := make(map[int]string)
+	for rows.Next() {
+		var accountID int
+		var role string
+		if err := rows.Scan(&accountID, &role); err != nil {
+			return err
+		}
+		roles[accountID] = role
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i := range accounts {
+		if accounts[i].UserID != userID {
+			accounts[i].Role = roles[accounts[i].ID]
+		}
+	}
+	return nil
+}
+
+// ShareAccount открывает пользователю userID доступ к счету владельца ownerID с ролью viewer или editor
+// или меняет роль уже открытого доступа. Возвращает nil, если у владельца нет такого счета.
+func (s *Storage) ShareAccount(accountID, ownerID, userID int, role string) (*models.AccountShare, error) {
+	if userID == ownerID {
+		return nil, fmt.Errorf("account cannot be shared with its owner")
+	}
+
+	share := &models.AccountShare{AccountID: accountID, UserID: userID, Role: role}
+	err := s.DB.QueryRow(`INSERT INTO account_shares (account_id, user_id, role)
+		SELECT id, $3, $4 FROM accounts WHERE id = $1 AND user_id = $2
+		ON CONFLICT (account_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING created_at, (SELECT username FROM users WHERE id = $3)`,
+		accountID, ownerID, userID, role).Scan(&share.CreatedAt, &share.Username)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// GetAccountShares возвращает пользователей, которым владелец ownerID открыл доступ к счету.
+func (s *Storage) GetAccountShares(accountID, ownerID int) ([]models.AccountShare, error) {
+	rows, err := s.DB.Query(`SELECT s.account_id, s.user_id, u.username, s.role, s.created_at
+		FROM account_shares s JOIN accounts a ON a.id = s.account_id JOIN users u ON u.id = s.user_id
+		WHERE s.account_id = $1 AND a.user_id = $2
+		ORDER BY u.username`, accountID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []models.AccountShare{}
+	for rows.Next() {
+		var share models.AccountShare
+		if err := rows.Scan(&share.AccountID, &share.UserID, &share.Username, &share.Role, &share.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+// GetSharingUsers возвращает пользователей, с которыми у userID есть общие счета: владельцев счетов,
+// открытых userID, и всех, кому открыты эти счета или счета самого userID.
+func (s *Storage) GetSharingUsers(userID int) ([]int, error) {
+	rows, err := s.DB.Query(`WITH shared AS (
+			SELECT account_id FROM account_shares WHERE user_id = $1
+			UNION
+			SELECT s.account_id FROM account_shares s JOIN accounts a ON a.id = s.account_id WHERE a.user_id = $1
+		)
+		SELECT a.user_id FROM accounts a JOIN shared ON shared.account_id = a.id WHERE a.user_id <> $1
+		UNION
+		SELECT s.user_id FROM account_shares s JOIN shared ON shared.account_id = s.account_id WHERE s.user_id <> $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		users = append(users, id)
+	}
+	return users, rows.Err()
+}
+
+// DeleteAccountShare закрывает пользователю userID доступ к счету. Закрыть доступ может владелец счета
+// requesterID или сам пользователь, отказываясь от доступа.
+func (s *Storage) DeleteAccountShare(accountID, userID, requesterID int) (bool, error) {
+	result, err := s.DB.Exec(`DELETE FROM account_shares s USING accounts a
+		WHERE s.account_id = $1 AND s.user_id = $2 AND a.id = s.account_id AND (a.user_id = $3 OR s.user_id = $3)`,
+		accountID, userID, requesterID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetAccountTransactions возвращает страницу транзакций по счету всех пользователей, начиная с последних,
+// и их общее число. Счет должен принадлежать пользователю userID или быть ему открыт.
+func (s *Storage) GetAccountTransactions(accountID, userID, page, limit int) ([]models.Transaction, int, error) {
+	where := `account_id = $1 AND deleted_at IS NULL
+		AND EXISTS (SELECT 1 FROM accounts a WHERE a.id = $1 AND ` + accountAccess("a", "$2", false) + `)`
+
+	var total int
+	if err := s.DB.QueryRow("SELECT COUNT(*) FROM transactions WHERE "+where, accountID, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	transactions, err := s.queryTransactions("SELECT "+transactionColumns+" FROM transactions WHERE "+where+
+		" ORDER BY date DESC, id DESC LIMIT $3 OFFSET $4", accountID, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
@@ -0,0 +1,20 @@
+package db
+
+// MarkTransactionsCleared отмечает проведенными ожидающие (pending) транзакции пользователя,
+// подходящие под фильтр, и возвращает их число. Сверенные транзакции не затрагиваются.
+func (s *Storage) MarkTransactionsCleared(userID int, filter TransactionFilter) (int64, error) {
+	where, params, err := s.transactionWhere(userID, filter)
+	if err != nil {
+		return 0, err
+	}
+	query, args, err := bindNamed("UPDATE transactions SET status = 'cleared' WHERE "+where+" AND status = 'pending'", params)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.DB.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// GetSystemCategories возвращает общие категории, доступные всем пользователям.
+func (s *Storage) GetSystemCategories() ([]models.Category, error) {
+	return s.queryCategories("SELECT " + categoryColumns + " FROM categories WHERE user_id IS NULL ORDER BY id")
+}
+
+// CreateSystemCategory создает общую категорию без владельца.
+func (s *Storage) CreateSystemCategory(name, icon, color string) (*models.Category, error) {
+	if name == "" {
+		return nil, fmt.Errorf("category name is required")
+	}
+
+	category := &models.Category{Name: name, Icon: icon, Color: color, System: true}
+	err := s.DB.QueryRow("INSERT INTO categories (user_id, name, icon, color) VALUES (NULL, $1, $2, $3) RETURNING id",
+		name, icon, color).Scan(&category.ID)
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateSystemCategory изменяет имя, значок и цвет общей категории.
+func (s *Storage) UpdateSystemCategory(id int, name, icon, color string) (bool, error) {
+	if name == "" {
+		return false, fmt.Errorf("category name is required")
+	}
+
+	result, err := s.DB.Exec("UPDATE categories SET name = $1, icon = $2, color = $3 WHERE id = $4 AND user_id IS NULL",
+		name, icon, color, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteSystemCategory удаляет общую категорию, если ее не использует ни один пользователь.
+// Как и в DeleteCategory, категория блокируется до проверки, чтобы в нее не добавили транзакцию до удаления.
+func (s *Storage) DeleteSystemCategory(id int) (bool, error) {
+	var deleted bool
+	err := s.WithTx(sql.LevelReadCommitted, func(tx *sql.Tx) error {
+		deleted = false
+		var locked int
+		err := tx.QueryRow("SELECT id FROM categories WHERE id = $1 AND user_id IS NULL FOR UPDATE", id).Scan(&locked)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var used bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM transactions WHERE category_id = $1)", id).Scan(&used); err != nil {
+			return err
+		}
+		if used {
+			return fmt.Errorf("category is used in transactions")
+		}
+
+		if _, err := tx.Exec("DELETE FROM categories WHERE id = $1", id); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// MaxTagLength — максимальная длина имени тега в символах.
+const MaxTagLength = 50
+
+// normalizeTagName убирает пробелы по краям и проверяет длину имени тега.
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("tag name is required")
+	}
+	if len([]rune(name)) > MaxTagLength {
+		return "", fmt.Errorf("tag name must be at most %d characters", MaxTagLength)
+	}
+	return name, nil
+}
+
+func (s *Storage) CreateTag(userID int, name string) (*models.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &models.Tag{UserID: userID, Name: name}
+	err = s.DB.QueryRow("INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id", userID, name).Scan(&tag.ID)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("tag already exists")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *Storage) GetTags(userID int) ([]models.Tag, error) {
+	rows, err := s.DB.Query("SELECT id, user_id, name FROM tags WHERE user_id = $1 ORDER BY name", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (s *Storage) GetTag(id, userID int) (*models.Tag, error) {
+	var t models.Tag
+	err := s.DB.QueryRow("SELECT id, user_id, name FROM tags WHERE id = $1 AND user_id = $2", id, userID).Scan(&t.ID, &t.UserID, &t.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *Storage) UpdateTag(id, userID int, name string) (bool, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.DB.Exec("UPDATE tags SET name = $1 WHERE id = $2 AND user_id = $3", name, id, userID)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return false, fmt.Errorf("tag already exists")
+	}
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteTag удаляет тег; связи с транзакциями удаляются каскадно.
+func (s *Storage) DeleteTag(id, userID int) (bool, error) {
+	result, err := s.DB.Exec("DELETE FROM tags WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// normalizeTagNames нормализует имена тегов и убирает повторы, сохраняя порядок.
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, name := range names {
+		name, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
+
+// setTransactionTags заменяет теги транзакции на переданный список имен,
+// создавая недостающие теги пользователя. Возвращает нормализованный список.
+func setTransactionTags(tx *sql.Tx, userID, transactionID int, names []string) ([]string, error) {
+	normalized, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM transaction_tags WHERE transaction_id = $1", transactionID); err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return normalized, nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO tags (user_id, name) SELECT $1, unnest($2::text[]) ON CONFLICT (user_id, name) DO NOTHING`,
+		userID, pq.Array(normalized))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO transaction_tags (transaction_id, tag_id)
+		SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3)`,
+		transactionID, userID, pq.Array(normalized))
+	if err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestTransactionTags тестирует привязку тегов к транзакциям, фильтрацию и CRUD тегов.
+func TestTransactionTags(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := store.CreateCategory(user.ID, "food")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	// Теги нормализуются: пробелы обрезаются, дубликаты удаляются
+	first := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(10, 0), Type: "expense", CategoryID: category.ID, Date: time.Now(), Tags: []string{" vacation ", "work", "vacation"}}
+	if err := store.CreateTransaction(first); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"vacation", "work"}) {
+		t.Errorf("Expected tags [vacation work], got %v", first.Tags)
+	}
+	second := &models.Transaction{UserID: user.ID, Amount: models.NewMoney(20, 0), Type: "expense", CategoryID: category.ID, Date: time.Now(), Tags: []string{"home"}}
+	if err := store.CreateTransaction(second); err != nil {
+		t.Fatalf("Failed to create transaction: %v", err)
+	}
+
+	// Теги возвращаются вместе с транзакцией, отсортированными по имени
+	fetched, err := store.GetTransaction(first.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if !reflect.DeepEqual(fetched.Tags, []string{"vacation", "work"}) {
+		t.Errorf("Expected tags [vacation work], got %v", fetched.Tags)
+	}
+
+	// Фильтр по любому из тегов
+	_, total, err := store.GetTransactions(user.ID, TransactionFilter{Tags: []string{"work", "home"}}, 1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get transactions: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 transactions, got %d", total)
+	}
+	result, total, err := store.GetTransactions(user.ID, TransactionFilter{Tags: []string{"home"}}, 1, 10)
+	if err != nil {
+		t.Fatalf("Failed to get transactions: %v", err)
+	}
+	if total != 1 || len(result) != 1 || result[0].ID != second.ID {
+		t.Errorf("Expected only transaction %d, got %d %+v", second.ID, total, result)
+	}
+
+	// Обновление заменяет набор тегов
+	first.Tags = []string{"work"}
+	if _, err := store.UpdateTransaction(first); err != nil {
+		t.Fatalf("Failed to update transaction: %v", err)
+	}
+	fetched, err = store.GetTransaction(first.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if !reflect.DeepEqual(fetched.Tags, []string{"work"}) {
+		t.Errorf("Expected tags [work], got %v", fetched.Tags)
+	}
+
+	// Теги создаются автоматически и видны в списке
+	tags, err := store.GetTags(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(tags) != 3 || tags[0].Name != "home" || tags[1].Name != "vacation" || tags[2].Name != "work" {
+		t.Errorf("Expected tags [home vacation work], got %+v", tags)
+	}
+
+	// Повторное создание и пустое имя отклоняются
+	if _, err := store.CreateTag(user.ID, "work"); err == nil || err.Error() != "tag already exists" {
+		t.Errorf("Expected error 'tag already exists', got %v", err)
+	}
+	if _, err := store.CreateTag(user.ID, "  "); err == nil || err.Error() != "tag name is required" {
+		t.Errorf("Expected error 'tag name is required', got %v", err)
+	}
+
+	// Переименование отражается на транзакциях
+	workID := tags[2].ID
+	updated, err := store.UpdateTag(workID, user.ID, "job")
+	if err != nil || !updated {
+		t.Fatalf("Failed to rename tag: %v", err)
+	}
+	fetched, err = store.GetTransaction(first.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if !reflect.DeepEqual(fetched.Tags, []string{"job"}) {
+		t.Errorf("Expected tags [job], got %v", fetched.Tags)
+	}
+
+	// Удаление тега снимает его с транзакций
+	deleted, err := store.DeleteTag(workID, user.ID)
+	if err != nil || !deleted {
+		t.Fatalf("Failed to delete tag: %v", err)
+	}
+	fetched, err = store.GetTransaction(first.ID, user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get transaction: %v", err)
+	}
+	if len(fetched.Tags) != 0 {
+		t.Errorf("Expected no tags, got %v", fetched.Tags)
+	}
+
+	// Чужой тег недоступен
+	tag, err := store.GetTag(tags[0].ID, user.ID+1)
+	if err != nil {
+		t.Fatalf("Failed to get tag: %v", err)
+	}
+	if tag != nil {
+		t.Errorf("Expected nil for another user's tag, got %+v", tag)
+	}
+}
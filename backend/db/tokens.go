@@ -0,0 +1,61 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"time"
+)
+
+// Назначения одноразовых токенов
+const (
+	TokenPurposeMagicLink = "magic_link"
+)
+
+// newToken генерирует случайный токен и его хеш для хранения в базе.
+func newToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateOneTimeToken создает одноразовый токен для пользователя и возвращает его.
+// В базе хранится только хеш токена.
+func (s *Storage) CreateOneTimeToken(userID int, purpose string, ttl time.Duration) (string, error) {
+	token, hash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.DB.Exec("INSERT INTO one_time_tokens (user_id, token_hash, purpose, expires_at) VALUES ($1, $2, $3, $4)",
+		userID, hash, purpose, time.Now().Add(ttl))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConsumeOneTimeToken помечает токен использованным и возвращает ID его владельца.
+// Если токен не найден, уже использован или истек, возвращается 0.
+func (s *Storage) ConsumeOneTimeToken(token, purpose string) (int, error) {
+	var userID int
+	err := s.DB.QueryRow(`UPDATE one_time_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`, hashToken(token), purpose).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
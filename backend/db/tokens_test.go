@@ -0,0 +1,59 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+// TestOneTimeTokens тестирует создание и однократное использование токенов.
+func TestOneTimeTokens(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUserWithEmail("testuser", "password123", "test@example.com")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// Проверяем поиск пользователя по email без учета регистра
+	fetched, err := store.GetUserByEmail("TEST@example.com")
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if fetched == nil || fetched.ID != user.ID {
+		t.Fatalf("Expected user %d, got %+v", user.ID, fetched)
+	}
+
+	token, err := store.CreateOneTimeToken(user.ID, TokenPurposeMagicLink, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	// Токен с другим назначением не подходит
+	userID, err := store.ConsumeOneTimeToken(token, "other")
+	if err != nil || userID != 0 {
+		t.Errorf("Expected 0 for wrong purpose, got %d (%v)", userID, err)
+	}
+
+	// Первое использование успешно
+	userID, err = store.ConsumeOneTimeToken(token, TokenPurposeMagicLink)
+	if err != nil || userID != user.ID {
+		t.Errorf("Expected user %d, got %d (%v)", user.ID, userID, err)
+	}
+
+	// Повторное использование отклоняется
+	userID, err = store.ConsumeOneTimeToken(token, TokenPurposeMagicLink)
+	if err != nil || userID != 0 {
+		t.Errorf("Expected 0 for used token, got %d (%v)", userID, err)
+	}
+
+	// Истекший токен отклоняется
+	expired, err := store.CreateOneTimeToken(user.ID, TokenPurposeMagicLink, -time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	userID, err = store.ConsumeOneTimeToken(expired, TokenPurposeMagicLink)
+	if err != nil || userID != 0 {
+		t.Errorf("Expected 0 for expired token, got %d (%v)", userID, err)
+	}
+}
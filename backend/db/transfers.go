@@ -0,0 +1,231 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// transferSelect собирает перевод из пары его транзакций: расхода на счете-источнике и дохода на счете-получателе.
+const transferSelect = `SELECT tr.id, f.account_id, t.account_id, f.amount, f.currency, t.amount, t.currency,
+	COALESCE(t.fx_rate, 0), f.date, f.description, f.id, t.id
+	FROM transfers tr
+	JOIN transactions f ON f.transfer_id = tr.id AND f.type = 'expense'
+	JOIN transactions t ON t.transfer_id = tr.id AND t.type = 'income'
+	WHERE tr.user_id = $1 AND f.deleted_at IS NULL`
+
+// CreateTransfer создает перевод между счетами пользователя одной транзакцией БД.
+// Заполняет валюты счетов и, для счетов в разных валютах, недостающую сумму зачисления или курс.
+func (s *Storage) CreateTransfer(userID int, t *models.Transfer) error {
+	var created models.Transfer
+	err := s.WithTx(sql.LevelReadCommitted, func(tx *sql.Tx) error {
+		// Повторная попытка начинает с исходных значений: createTransfer дополняет перевод
+		created = *t
+		return createTransfer(tx, userID, &created)
+	})
+	if err != nil {
+		return err
+	}
+	*t = created
+	return nil
+}
+
+func createTransfer(tx *sql.Tx, userID int, t *models.Transfer) error {
+	if t.FromAccountID == t.ToAccountID {
+		return fmt.Errorf("transfer accounts must differ")
+	}
+
+	var err error
+	if t.Currency, err = accountCurrency(tx, t.FromAccountID, userID); err != nil {
+		return err
+	}
+	if t.ToCurrency, err = accountCurrency(tx, t.ToAccountID, userID); err != nil {
+		return err
+	}
+	if err := convertTransfer(t); err != nil {
+		return err
+	}
+	if t.Date.IsZero() {
+		t.Date = time.Now()
+	}
+
+	var transferID int
+	if err := tx.QueryRow("INSERT INTO transfers (user_id) VALUES ($1) RETURNING id", userID).Scan(&transferID); err != nil {
+		return err
+	}
+
+	from := &models.Transaction{UserID: userID, Amount: t.Amount, Type: "expense", Date: t.Date, Description: t.Description,
+		Currency: t.Currency, AccountID: t.FromAccountID, TransferID: transferID}
+	to := &models.Transaction{UserID: userID, Amount: t.ToAmount, Type: "income", Date: t.Date, Description: t.Description,
+		Currency: t.ToCurrency, AccountID: t.ToAccountID, TransferID: transferID}
+	if t.FXRate != 0 {
+		to.OriginalAmount, to.OriginalCurrency, to.FXRate = t.Amount, t.Currency, t.FXRate
+	}
+	for _, leg := range []*models.Transaction{from, to} {
+		if err := insertTransferTransaction(tx, leg); err != nil {
+			return err
+		}
+	}
+
+	t.ID = transferID
+	t.FromTransactionID, t.ToTransactionID = from.ID, to.ID
+	return nil
+}
+
+func accountCurrency(tx *sql.Tx, accountID, userID int) (string, error) {
+	var currency string
+	var closed bool
+	err := tx.QueryRow("SELECT currency, closed_at IS NOT NULL FROM accounts WHERE id = $1 AND "+accountAccess("accounts", "$2", true), accountID, userID).
+		Scan(&currency, &closed)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("account %d does not exist or does not belong to user", accountID)
+	}
+	if err != nil {
+		return "", err
+	}
+	if closed {
+		return "", fmt.Errorf("account %d is closed", accountID)
+	}
+	return currency, nil
+}
+
+// convertTransfer проверяет суммы перевода: в одной валюте зачисляется списанная сумма,
+// между валютами сумма зачисления и курс вычисляются друг из друга и должны быть согласованы.
+func convertTransfer(t *models.Transfer) error {
+	if t.Currency == t.ToCurrency {
+		if t.FXRate != 0 && t.FXRate != 1 {
+			return fmt.Errorf("fx_rate is only allowed between accounts in different currencies")
+		}
+		if t.ToAmount != 0 && t.ToAmount != t.Amount {
+			return fmt.Errorf("to_amount must equal amount for accounts in the same currency")
+		}
+		t.ToAmount, t.FXRate = t.Amount, 0
+		return nil
+	}
+
+	switch {
+	case t.ToAmount == 0 && t.FXRate == 0:
+		return fmt.Errorf("to_amount or fx_rate is required for accounts in different currencies")
+	case t.ToAmount == 0:
+		t.ToAmount = models.MoneyFromFloat(t.Amount.Float64() * t.FXRate)
+		if t.ToAmount <= 0 {
+			return fmt.Errorf("to_amount computed from fx_rate must be positive")
+		}
+	case t.FXRate == 0:
+		t.FXRate = math.Round(float64(t.ToAmount)/float64(t.Amount)*1e8) / 1e8
+	default:
+		expected := models.MoneyFromFloat(t.Amount.Float64() * t.FXRate)
+		if diff := expected - t.ToAmount; diff > 1 || diff < -1 {
+			return fmt.Errorf("to_amount %s does not match amount * fx_rate = %s", t.ToAmount, expected)
+		}
+	}
+	if t.ToAmount > MaxAmount {
+		return fmt.Errorf("to_amount must be at most %s", MaxAmount)
+	}
+	return nil
+}
+
+// insertTransferTransaction записывает транзакцию перевода: без категории, контрагента и проверки дубликатов.
+func insertTransferTransaction(tx *sql.Tx, t *models.Transaction) error {
+	err := tx.QueryRow(`INSERT INTO transactions (user_id, amount, type, date, description, currency, account_id, transfer_id,
+		original_amount, original_currency, fx_rate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9::numeric, 0), NULLIF($10, ''), NULLIF($11::numeric, 0)) RETURNING id`,
+		t.UserID, t.Amount, t.Type, t.Date, t.Description, t.Currency, t.AccountID, t.TransferID,
+		t.OriginalAmount, t.OriginalCurrency, t.FXRate).Scan(&t.ID)
+	if err != nil {
+		return err
+	}
+	return recordVersion(tx, t.ID)
+}
+
+func scanTransfer(row rowScanner) (models.Transfer, error) {
+	var t models.Transfer
+	err := row.Scan(&t.ID, &t.FromAccountID, &t.ToAccountID, &t.Amount, &t.Currency, &t.ToAmount, &t.ToCurrency,
+		&t.FXRate, &t.Date, &t.Description, &t.FromTransactionID, &t.ToTransactionID)
+	return t, err
+}
+
+// GetTransfers возвращает страницу переводов пользователя, начиная с последних, и их общее число.
+// При accountID > 0 возвращаются только переводы с этого счета или на него.
+func (s *Storage) GetTransfers(userID, accountID, page, limit int) ([]models.Transfer, int, error) {
+	const where = ` AND ($2 = 0 OR f.account_id = $2 OR t.account_id = $2)`
+
+	var total int
+	if err := s.DB.QueryRow("SELECT COUNT(*) FROM ("+transferSelect+where+") AS transfers", userID, accountID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.DB.Query(transferSelect+where+" ORDER BY f.date DESC, tr.id DESC LIMIT $3 OFFSET $4",
+		userID, accountID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transfers := []models.Transfer{}
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, total, rows.Err()
+}
+
+// GetTransfer возвращает перевод пользователя или nil, если его нет или он в корзине.
+func (s *Storage) GetTransfer(id, userID int) (*models.Transfer, error) {
+	t, err := scanTransfer(s.DB.QueryRow(transferSelect+" AND tr.id = $2", userID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteTransfer перемещает обе транзакции перевода в корзину, если ни один из его счетов не закрыт.
+// Счета перевода блокируются до удаления, чтобы их не закрыли между проверкой и удалением.
+func (s *Storage) DeleteTransfer(id, userID int) (bool, error) {
+	var deleted bool
+	err := s.WithTx(sql.LevelReadCommitted, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT a.closed_at IS NULL FROM accounts a
+			WHERE a.id IN (SELECT account_id FROM transactions WHERE transfer_id = $1 AND user_id = $2)
+			ORDER BY a.id FOR SHARE`, id, userID)
+		if err != nil {
+			return err
+		}
+		open := true
+		for rows.Next() {
+			var accountOpen bool
+			if err := rows.Scan(&accountOpen); err != nil {
+				rows.Close()
+				return err
+			}
+			open = open && accountOpen
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if !open {
+			return fmt.Errorf("account is closed")
+		}
+
+		result, err := tx.Exec("UPDATE transactions SET deleted_at = NOW() WHERE transfer_id = $1 AND user_id = $2 AND deleted_at IS NULL", id, userID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		deleted = rowsAffected > 0
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,43 @@
+package db
+
+import "time"
+
+// RestoreTransaction возвращает транзакцию из корзины; транзакция перевода возвращается вместе с парной.
+// Транзакции закрытых счетов не восстанавливаются.
+func (s *Storage) RestoreTransaction(id, userID int) (bool, error) {
+	if err := s.checkOpenAccounts(id, userID); err != nil {
+		return false, err
+	}
+
+	result, err := s.DB.Exec(`UPDATE transactions SET deleted_at = NULL WHERE user_id = $2 AND deleted_at IS NOT NULL
+		AND (id = $1 OR transfer_id IN (SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL))`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// EmptyTrash окончательно удаляет все транзакции пользователя из корзины.
+func (s *Storage) EmptyTrash(userID int) (int64, error) {
+	result, err := s.DB.Exec("DELETE FROM transactions WHERE user_id = $1 AND deleted_at IS NOT NULL", userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeDeletedTransactions окончательно удаляет транзакции всех пользователей,
+// пролежавшие в корзине дольше retention. Время отсчитывается по часам БД,
+// как и deleted_at.
+func (s *Storage) PurgeDeletedTransactions(retention time.Duration) (int64, error) {
+	result, err := s.DB.Exec("DELETE FROM transactions WHERE deleted_at IS NOT NULL AND deleted_at < NOW() - make_interval(secs => $1)",
+		retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// maxTxAttempts — сколько раз WithTx выполняет транзакцию, прерванную конфликтом с параллельной.
+const maxTxAttempts = 3
+
+// WithTx выполняет fn в транзакции БД с уровнем изоляции level (sql.LevelDefault — READ COMMITTED):
+// фиксирует ее, если fn вернула nil, и откатывает при ошибке. Транзакция, прерванная ошибкой
+// сериализации или взаимной блокировкой, повторяется целиком, поэтому fn не должна менять
+// ничего, кроме БД, и должна заново заполнять свои результаты при каждом вызове.
+func (s *Storage) WithTx(level sql.IsolationLevel, fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 0; attempt < maxTxAttempts; attempt++ {
+		if err = s.runTx(level, fn); !retryableTxError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *Storage) runTx(level sql.IsolationLevel, fn func(tx *sql.Tx) error) error {
+	tx, err := s.DB.BeginTx(context.Background(), &sql.TxOptions{Isolation: level})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// retryableTxError сообщает, прервана ли транзакция конфликтом, после которого ее можно повторить:
+// ошибкой сериализации (40001) или взаимной блокировкой (40P01).
+func retryableTxError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == "40001" || pqErr.Code == "40P01")
+}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestRetryableTxError тестирует выбор ошибок, после которых транзакция повторяется.
+func TestRetryableTxError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{&pq.Error{Code: "40001"}, true},
+		{&pq.Error{Code: "40P01"}, true},
+		{fmt.Errorf("transaction 3: %w", &pq.Error{Code: "40001"}), true},
+		{&pq.Error{Code: "23503"}, false},
+		{errors.New("category is used in transactions"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := retryableTxError(tt.err); got != tt.expected {
+			t.Errorf("retryableTxError(%v) = %v, expected %v", tt.err, got, tt.expected)
+		}
+	}
+}
+
+// TestWithTx тестирует фиксацию, откат и повтор транзакции после ошибки сериализации.
+func TestWithTx(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	insert := func(name string) func(tx *sql.Tx) error {
+		return func(tx *sql.Tx) error {
+			_, err := tx.Exec("INSERT INTO categories (user_id, name) VALUES ($1, $2)", user.ID, name)
+			return err
+		}
+	}
+
+	if err := store.WithTx(sql.LevelDefault, insert("Продукты")); err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	err = store.WithTx(sql.LevelDefault, func(tx *sql.Tx) error {
+		if err := insert("Транспорт")(tx); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	if err == nil || err.Error() != "rollback" {
+		t.Errorf("Expected rollback error, got %v", err)
+	}
+
+	attempts := 0
+	err = store.WithTx(sql.LevelSerializable, func(tx *sql.Tx) error {
+		attempts++
+		if err := insert("Кафе")(tx); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &pq.Error{Code: "40001"}
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("Expected success on second attempt, got %v after %d attempts", err, attempts)
+	}
+
+	categories, err := store.GetCategories(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get categories: %v", err)
+	}
+	var names []string
+	for _, c := range categories {
+		if !c.System {
+			names = append(names, c.Name)
+		}
+	}
+	if strings.Join(names, ",") != "Кафе,Продукты" && strings.Join(names, ",") != "Продукты,Кафе" {
+		t.Errorf("Expected committed categories only, got %v", names)
+	}
+}
+
+// TestDeleteCategoryConcurrentTransaction тестирует, что транзакция, созданная во время удаления категории,
+// не остается в удаленной категории.
+func TestDeleteCategoryConcurrentTransaction(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := store.CreateCategory(user.ID, "Продукты")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	// Параллельная транзакция вставляет строку в категорию и держит блокировку внешнего ключа
+	tx, err := store.DB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin: %v", err)
+	}
+	_, err = tx.Exec("INSERT INTO transactions (user_id, amount, type, category_id, date) VALUES ($1, 10, 'expense', $2, NOW())", user.ID, category.ID)
+	if err != nil {
+		t.Fatalf("Failed to insert transaction: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var deleted bool
+	var deleteErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		deleted, deleteErr = store.DeleteCategory(category.ID, user.ID)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	wg.Wait()
+
+	if deleted || deleteErr == nil || deleteErr.Error() != "category is used in transactions" {
+		t.Errorf("Expected category in use, got %v, %v", deleted, deleteErr)
+	}
+
+	// Без транзакций категория удаляется
+	if _, err := store.DB.Exec("DELETE FROM transactions WHERE category_id = $1", category.ID); err != nil {
+		t.Fatalf("Failed to delete transactions: %v", err)
+	}
+	if deleted, err := store.DeleteCategory(category.ID, user.ID); err != nil || !deleted {
+		t.Errorf("Expected category to be deleted, got %v, %v", deleted, err)
+	}
+	if deleted, err := store.DeleteCategory(category.ID, user.ID); err != nil || deleted {
+		t.Errorf("Expected missing category, got %v, %v", deleted, err)
+	}
+}
+
+// TestCreateTransactionsWithCategories тестирует создание категорий вместе с транзакциями и их откат при ошибке.
+func TestCreateTransactionsWithCategories(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	newTransaction := func(amount int64) *models.Transaction {
+		return &models.Transaction{UserID: user.ID, Amount: models.NewMoney(amount, 0), Type: "expense", Date: time.Now(), Currency: "RUB"}
+	}
+
+	ts := []*models.Transaction{newTransaction(100), newTransaction(200), newTransaction(300)}
+	if err := store.CreateTransactionsWithCategories(ts, []string{"Кафе", "кафе", "Такси"}); err != nil {
+		t.Fatalf("Failed to create transactions: %v", err)
+	}
+	if ts[0].CategoryID == 0 || ts[0].CategoryID != ts[1].CategoryID || ts[2].CategoryID == ts[0].CategoryID {
+		t.Errorf("Unexpected categories %d, %d, %d", ts[0].CategoryID, ts[1].CategoryID, ts[2].CategoryID)
+	}
+
+	// Ошибка в любой транзакции откатывает и новые категории
+	bad := newTransaction(100)
+	bad.CategoryID = 100000
+	if err := store.CreateTransactionsWithCategories([]*models.Transaction{newTransaction(100), bad}, []string{"Аптека", ""}); err == nil {
+		t.Fatal("Expected error for missing category")
+	}
+	categories, err := store.GetCategories(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to get categories: %v", err)
+	}
+	for _, c := range categories {
+		if c.Name == "Аптека" {
+			t.Error("Expected category to be rolled back")
+		}
+	}
+}
+
+// TestDeleteSystemCategoryConcurrentTransaction тестирует, что транзакция, созданная во время удаления
+// общей категории, не остается в удаленной категории.
+func TestDeleteSystemCategoryConcurrentTransaction(t *testing.T) {
+	store := setupTestDB(t)
+	defer store.Close()
+
+	user, err := store.CreateUser("testuser", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category, err := store.CreateSystemCategory("Подарки", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	defer store.DB.Exec("DELETE FROM categories WHERE id = $1", category.ID)
+
+	tx, err := store.DB.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin: %v", err)
+	}
+	_, err = tx.Exec("INSERT INTO transactions (user_id, amount, type, category_id, date) VALUES ($1, 10, 'expense', $2, NOW())", user.ID, category.ID)
+	if err != nil {
+		t.Fatalf("Failed to insert transaction: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var deleted bool
+	var deleteErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		deleted, deleteErr = store.DeleteSystemCategory(category.ID)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	wg.Wait()
+
+	if deleted || deleteErr == nil || deleteErr.Error() != "category is used in transactions" {
+		t.Errorf("Expected category in use, got %v, %v", deleted, deleteErr)
+	}
+
+	if _, err := store.DB.Exec("DELETE FROM transactions WHERE category_id = $1", category.ID); err != nil {
+		t.Fatalf("Failed to delete transactions: %v", err)
+	}
+	if deleted, err := store.DeleteSystemCategory(category.ID); err != nil || !deleted {
+		t.Errorf("Expected category to be deleted, got %v, %v", deleted, err)
+	}
+	if deleted, err := store.DeleteSystemCategory(category.ID); err != nil || deleted {
+		t.Errorf("Expected missing category, got %v, %v", deleted, err)
+	}
+}
@@ -15,31 +15,45 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/categories": {
+        "/accounts": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получает список категорий пользователя",
+                "description": "Возвращает счета пользователя и открытые ему чужие счета с текущими остатками и ролью пользователя.\nЗакрытые счета возвращаются только с include_closed=true",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts"
+                ],
+                "summary": "Список счетов",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Включить закрытые счета (по умолчанию false)",
+                        "name": "include_closed",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получить список категорий",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Category"
+                                "$ref": "#/definitions/models.Account"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -54,7 +68,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Создает новую категорию для пользователя",
+                "description": "Создает счет с начальным балансом. Валюта и тип счета не меняются после создания,\nтранзакции счета ведутся в его валюте. Для кредитной карты (type=credit_card) обязательны\nкредитный лимит, день закрытия выписки и день платежа, для кредита (type=loan) — условия кредита loan.\nНачальный остаток кредита — минус сумма кредита. Инвестиционный (type=investment) и криптовалютный (type=crypto)\nсчета кроме остатка денежных средств хранят позиции ценных бумаг или криптовалют",
                 "consumes": [
                     "application/json"
                 ],
@@ -62,17 +76,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts"
                 ],
-                "summary": "Создать новую категорию",
+                "summary": "Создать счет",
                 "parameters": [
                     {
-                        "description": "Данные категории",
-                        "name": "category",
+                        "description": "Данные счета",
+                        "name": "account",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateCategory"
+                            "$ref": "#/definitions/models.CreateAccount"
                         }
                     }
                 ],
@@ -80,13 +94,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -98,35 +112,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{id}": {
+        "/accounts/available-funds": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получает категорию пользователя по ID",
+                "description": "Возвращает остатки открытых счетов по валютам и их сумму в базовой валюте пользователя.\nУчитываются свои счета и чужие счета, открытые для редактирования. Если курсы валют недоступны, сумма не возвращается",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
-                ],
-                "summary": "Получить категорию",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "accounts"
                 ],
+                "summary": "Доступные средства",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.AvailableFunds"
                         }
                     },
                     "401": {
@@ -134,55 +139,47 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/accounts/balance-history": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Обновляет существующую категорию пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает суммы остатков всех счетов по валютам на конец каждого дня периода из ежедневных снимков",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts"
                 ],
-                "summary": "Обновить категорию",
+                "summary": "История суммарного остатка",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней до to)",
+                        "name": "from",
+                        "in": "query"
                     },
                     {
-                        "description": "Новое имя категории",
-                        "name": "category",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateCategory"
-                        }
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateCategoryResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BalancePoint"
+                            }
                         }
                     },
                     "400": {
@@ -196,41 +193,39 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/accounts/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет категорию пользователя, если она не используется в транзакциях",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "accounts"
                 ],
-                "summary": "Удалить категорию",
+                "summary": "Получить счет",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID категории",
+                        "description": "ID счета",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Account"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -251,11 +246,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/login": {
-            "post": {
-                "description": "Аутентифицирует пользователя и возвращает JWT токен",
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет имя, начальный баланс, условия кредитной карты и кредита. Закрытый счет изменить нельзя.\nИзменить счет может только его владелец",
                 "consumes": [
                     "application/json"
                 ],
@@ -263,17 +261,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "accounts"
                 ],
-                "summary": "Вход пользователя",
+                "summary": "Обновить счет",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
-                        "name": "credentials",
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные счета",
+                        "name": "account",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUser"
+                            "$ref": "#/definitions/models.UpdateAccount"
                         }
                     }
                 ],
@@ -281,13 +286,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginResponse"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -295,115 +300,44 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/register": {
-            "post": {
-                "description": "Создает нового пользователя с именем пользователя и паролем",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Регистрация нового пользователя",
-                "parameters": [
-                    {
-                        "description": "Данные пользователя",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateUser"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/transactions": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получает список транзакций пользователя с возможностью фильтрации и пагинации",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Удаляет счет, по которому нет транзакций. Удалить счет может только его владелец",
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Получить список транзакций",
+                "summary": "Удалить счет",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Тип транзакции (income или expense)",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Минимальная сумма",
-                        "name": "min_amount",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Максимальная сумма",
-                        "name": "max_amount",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Сортировка по дате (asc или desc)",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Номер страницы",
-                        "name": "page",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "Лимит на страницу",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.GetTransactionsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -416,16 +350,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/accounts/{id}/adjustments": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Создает новую транзакцию для пользователя",
+                "description": "Создает доход или расход на разницу между фактическим остатком balance и остатком по транзакциям.\nКорректировка не учитывается в доходах, расходах и отчетах по категориям и не изменяется — только удаляется.\nНачальный остаток счета задается полем initial_balance счета",
                 "consumes": [
                     "application/json"
                 ],
@@ -433,17 +375,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Создать новую транзакцию",
+                "summary": "Скорректировать остаток счета",
                 "parameters": [
                     {
-                        "description": "Данные транзакции",
-                        "name": "transaction",
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Фактический остаток",
+                        "name": "adjustment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTransaction"
+                            "$ref": "#/definitions/models.CreateAdjustment"
                         }
                     }
                 ],
@@ -457,7 +406,7 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
                         }
                     },
                     "401": {
@@ -465,29 +414,35 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/transactions/{id}": {
+        "/accounts/{id}/amortization": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Получает детали конкретной транзакции пользователя",
+                "description": "Возвращает аннуитетный график погашения по условиям кредита. Платежи отмечаются внесенными по числу внесенных платежей",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "loans"
                 ],
-                "summary": "Получить транзакцию по ID",
+                "summary": "График погашения кредита",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID транзакции",
+                        "description": "ID счета кредита",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -497,7 +452,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.AmortizationSchedule"
                         }
                     },
                     "400": {
@@ -519,47 +474,211 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/accounts/{id}/balance": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Обновляет существующую транзакцию пользователя",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает текущий остаток счета: начальный баланс плюс доходы и минус расходы по счету\nбез запланированных и удаленных транзакций. Остаток счетов с длинной историей кэшируется\nи пересчитывается после изменения их транзакций",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "accounts"
                 ],
-                "summary": "Обновить транзакцию",
+                "summary": "Остаток счета",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID транзакции",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountBalance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/balance-history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает остатки счета на конец каждого дня периода из ежедневных снимков.\nСнимок за день сохраняется после его окончания; дни без снимка пропускаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "История остатка счета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Новые данные транзакции",
-                        "name": "transaction",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней до to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTransaction"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BalancePoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/accounts/{id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Закрывает счет: он скрывается из списка счетов и не входит в доступные средства, но остается в отчетах.\nТранзакции закрытого счета нельзя создавать, изменять и удалять, переводы с ним недоступны",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Закрыть счет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/holdings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает позиции инвестиционного или криптовалютного счета по тикеру",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Позиции счета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID инвестиционного или криптовалютного счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Holding"
+                            }
                         }
                     },
                     "400": {
@@ -582,38 +701,130 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаляет транзакцию пользователя",
+                "description": "Добавляет на открытый инвестиционный или криптовалютный счет позицию: тикер бумаги или символ криптовалюты,\nколичество и сумму покупки в валюте счета.\nТикер приводится к верхнему регистру и уникален в пределах счета",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "investments"
                 ],
-                "summary": "Удалить транзакцию",
+                "summary": "Добавить позицию",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID транзакции",
+                        "description": "ID инвестиционного или криптовалютного счета",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Данные позиции",
+                        "name": "holding",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateHolding"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Holding"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/holdings/{holding_id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет количество и сумму покупки позиции открытого инвестиционного или криптовалютного счета",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Обновить позицию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID инвестиционного или криптовалютного счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID позиции",
+                        "name": "holding_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные позиции",
+                        "name": "holding",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateHolding"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Holding"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -621,6 +832,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -628,129 +845,10704 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет позицию открытого инвестиционного или криптовалютного счета",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Удалить позицию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID инвестиционного или криптовалютного счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID позиции",
+                        "name": "holding_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/loan": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает остаток основного долга, уплаченные проценты и следующий платеж по графику",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "loans"
+                ],
+                "summary": "Состояние кредита",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета кредита",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoanStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/loan-payments": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Проценты за месяц на остаток долга записываются расходом счета-источника в категории category_id,\nостальная сумма переводится на счет кредита и уменьшает долг. Без суммы вносится платеж по графику",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "loans"
+                ],
+                "summary": "Внести платеж по кредиту",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета кредита",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные платежа",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateLoanPayment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoanPayment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/reopen": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снимает с закрытого счета ограничения и возвращает его в список счетов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Открыть счет заново",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Account"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/shares": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает пользователей, которым владелец открыл доступ к счету",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Пользователи с доступом к счету",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AccountShare"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Открывает зарегистрированному пользователю доступ к счету или меняет его роль. viewer видит счет,\nего остаток и транзакции всех пользователей по счету, editor также создает по счету транзакции, переводы\nи позиции. Транзакции создаются от имени их автора в его категориях. Открыть доступ может только владелец",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Открыть доступ к счету",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Пользователь и роль",
+                        "name": "share",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ShareAccount"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountShare"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/shares/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Закрывает пользователю доступ к счету. Владелец закрывает доступ любому пользователю,\nпользователь с доступом может отказаться от него сам. Транзакции пользователя по счету сохраняются",
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Закрыть доступ к счету",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/statement": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает долг по последней закрытой выписке, обязательный платеж и срок его внесения.\nПлатежами считаются поступления на карту после закрытия выписки. Напоминание возвращается,\nесли обязательный платеж не внесен, а до срока осталось не больше недели или срок прошел",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Выписка кредитной карты",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreditCardStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает транзакции по счету всех пользователей с доступом к нему, начиная с последних.\nДоступно владельцу и пользователям, которым открыт счет",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "accounts"
+                ],
+                "summary": "Транзакции счета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество транзакций на странице (по умолчанию 10, не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetTransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/valuation": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Оценивает позиции инвестиционного или криптовалютного счета по текущим котировкам в валюте счета и считает нереализованную\nприбыль или убыток относительно суммы покупки. Позиции без котировки оцениваются по сумме покупки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "Стоимость позиций счета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID инвестиционного или криптовалютного счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.InvestmentValuation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/valuation-history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает стоимость позиций инвестиционного или криптовалютного счета и сумму их покупки на конец каждого дня\nпериода из ежедневных снимков. Снимок текущего дня обновляется до его окончания; дни без снимка пропускаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investments"
+                ],
+                "summary": "История стоимости позиций счета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID инвестиционного или криптовалютного счета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней до to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ValuationPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/categories": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает общие категории, которые видят все пользователи. Доступно только администраторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Системные категории",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает общую категорию, доступную всем пользователям только для чтения. Доступно только администраторам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Создать системную категорию",
+                "parameters": [
+                    {
+                        "description": "Данные категории",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/categories/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет имя, значок и цвет общей категории. Доступно только администраторам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Обновить системную категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные категории",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет общую категорию, если ее не использует ни один пользователь. Доступно только администраторам",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удалить системную категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/invites": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает код приглашения для регистрации в режиме REGISTRATION_MODE=invite. Доступно только администраторам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Создать приглашение",
+                "parameters": [
+                    {
+                        "description": "Параметры приглашения",
+                        "name": "invite",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateInvite"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Invite"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает фоновые задачи планировщика: расписание, включена ли задача и результат последнего запуска.\nДоступно только администраторам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Фоновые задачи",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JobStatus"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/magic-link": {
+            "post": {
+                "description": "Отправляет на email одноразовую ссылку для входа без пароля. Ответ не раскрывает, существует ли пользователь с таким email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Запросить ссылку для входа",
+                "parameters": [
+                    {
+                        "description": "Email пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MagicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/magic-link/verify": {
+            "get": {
+                "description": "Обменивает одноразовый токен из письма на JWT токен",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Войти по ссылке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен из ссылки",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Обменивает код авторизации на ID токен, находит или создает связанного пользователя и возвращает JWT токен",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обработка ответа OIDC провайдера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Код авторизации",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние из запроса входа",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Перенаправляет пользователя на страницу входа настроенного OpenID Connect провайдера",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Вход через OIDC провайдер",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget-templates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Шаблоны бюджетов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BudgetTemplate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает шаблон с лимитами категорий. Шаблон с автоприменением в начале каждого месяца создает бюджеты месяца,\nначиная со следующего; к текущему месяцу его можно применить вручную",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Создать шаблон бюджетов",
+                "parameters": [
+                    {
+                        "description": "Данные шаблона",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBudgetTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget-templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Получить шаблон бюджетов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет название, автоприменение и лимиты шаблона. Созданные по шаблону бюджеты не меняются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Обновить шаблон бюджетов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные шаблона",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBudgetTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет шаблон; созданные по нему бюджеты остаются",
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Удалить шаблон бюджетов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budget-templates/{id}/apply": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает по шаблону месячные бюджеты месяца. Категории, у которых в месяце уже есть месячный бюджет, пропускаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Применить шаблон бюджетов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID шаблона",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Месяц в формате YYYY-MM (по умолчанию текущий)",
+                        "name": "month",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplyBudgetTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает бюджеты, периоды которых пересекаются с месяцем month или включают день date, с фактическими расходами\nпо категориям или тегам в валюте бюджета за вычетом возвратов. Без параметров возвращаются бюджеты, действующие сегодня.\nЗапланированные транзакции, переводы и корректировки не учитываются. Перенос переносимых бюджетов пересчитывается\nперед ответом, доступная сумма (available) — лимит с учетом переноса",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Бюджеты с расходами",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Месяц в формате YYYY-MM",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "День в формате YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BudgetProgress"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Задает лимит расходов категории или тега на неделю (с понедельника), месяц, квартал или произвольный период.\nМесячный бюджет задается месяцем month или днем start_date, недельный и квартальный — любым днем периода,\nпроизвольный — первым и последним днем. В бюджет тега входят расходы с тегом во всех категориях.\nБез валюты лимит задается в базовой валюте пользователя.\nУ переносимого бюджета (rollover) остаток бюджета той же категории или того же тега, валюты и вида периода, закончившегося\nнакануне, прибавляется к лимиту, а перерасход вычитается из него",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Создать бюджет",
+                "parameters": [
+                    {
+                        "description": "Данные бюджета",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateBudget"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/budgets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Получить бюджет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет лимит бюджета и перенос остатка. Категория или тег, период и валюта бюджета не меняются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Обновить бюджет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные бюджета",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateBudget"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Удалить бюджет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бюджета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает список категорий пользователя вместе с общими системными категориями (system=true), доступными только для чтения.\nС with_stats=true для каждой категории возвращаются число транзакций, суммы по валютам\nи расходы в базовой валюте пользователя за все время",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Получить список категорий",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Включить статистику транзакций (по умолчанию false)",
+                        "name": "with_stats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Список не изменился"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает новую категорию для пользователя. Значок выбирается из набора /categories/icons, цвет задается как #rrggbb",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Создать новую категорию",
+                "parameters": [
+                    {
+                        "description": "Данные категории",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/icons": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает имена значков, которые можно назначить категории",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Значки категорий",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает категорию пользователя по ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Получить категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Обновляет существующую категорию пользователя. Незаданные значок и цвет сбрасываются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Обновить категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое имя категории",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateCategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет категорию пользователя, если она не используется в транзакциях.\nС reassign_to транзакции категории (включая корзину) сначала переносятся в указанную категорию в той же транзакции БД",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Удалить категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID категории, в которую переносятся транзакции удаляемой",
+                        "name": "reassign_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет только переданные поля категории",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Частично обновить категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchCategory"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/keywords": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает ключевые слова, по которым категория назначается при импорте и создании транзакций по чекам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Ключевые слова категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryKeyword"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Транзакциям без категории, в контрагенте или описании которых встречается слово (без учета регистра),\nназначается эта категория. Слово уникально у пользователя; при нескольких совпадениях выбирается самое длинное.\nКлючевые слова можно добавлять и к системным категориям",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Добавить ключевое слово категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ключевое слово",
+                        "name": "keyword",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateCategoryKeyword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryKeyword"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/keywords/{keyword_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Удалить ключевое слово категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID ключевого слова",
+                        "name": "keyword_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/reassign": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переносит все транзакции категории (включая корзину) в целевую категорию одним запросом,\nпри необходимости только за период. Используется для объединения и чистки категорий.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Перенести транзакции в другую категорию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исходной категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Целевая категория и период",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReassignCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReassignCategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает число транзакций, суммы доходов и расходов, среднюю сумму транзакции\nи помесячный ряд по валютам за последние месяцы, включая текущий. Запланированные транзакции не учитываются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Статистика категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Период: month, quarter, year (по умолчанию) или all",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CategoryStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает одним ответом суммарный остаток своих открытых счетов и итоги текущего месяца (с дня начала месяца из профиля) в базовой валюте,\nдействующие бюджеты, 10 последних транзакций и запланированные транзакции на 30 дней вперед",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Главный экран",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dashboard"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отправляет те же события, что и /ws, в формате text/event-stream: поле id — эпоха и ID события через дефис,\nevent — его тип, data — событие в формате JSON. Для EventSource токен можно передать в параметре token.\nПри переподключении браузер передает заголовок Last-Event-ID, и сервер сначала отправляет пропущенные события.\nЕсли часть из них уже не хранится или ID выдан в другой эпохе, например до перезапуска сервиса,\nсначала отправляется событие reset, а за ним все хранящиеся события пользователя: клиенту нужно заново загрузить данные.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Поток событий через Server-Sent Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT, если не передан заголовок Authorization",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID последнего полученного события",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID последнего полученного события, если не передан заголовок Last-Event-ID",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает цели с прогрессом: накопленной суммой, остатком, ожидаемой датой достижения при среднем темпе взносов\nи ежемесячным взносом, нужным, чтобы успеть к сроку",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Цели накоплений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Goal"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает цель с суммой, необязательным сроком и счетом, на котором копятся деньги.\nБез валюты цель создается в валюте счета или в базовой валюте пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Создать цель накоплений",
+                "parameters": [
+                    {
+                        "description": "Данные цели",
+                        "name": "goal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateGoal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Получить цель накоплений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет название, сумму, срок и счет цели. Валюта не меняется; незаданные срок и счет сбрасываются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Обновить цель накоплений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные цели",
+                        "name": "goal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateGoal"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Goal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет цель вместе со взносами; транзакции, учтенные взносами, остаются",
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Удалить цель накоплений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/contributions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает взносы по дате. Взносы-транзакции удаленных транзакций не возвращаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Взносы в цель",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GoalContribution"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет взнос суммой (отрицательная сумма — изъятие) или учитывает взносом транзакцию в валюте цели.\nУ цели со счетом взносом может быть только транзакция этого счета. Сумма и дата взноса-транзакции\nберутся из транзакции и меняются вместе с ней",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Внести взнос в цель",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные взноса",
+                        "name": "contribution",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateGoalContribution"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.GoalContribution"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/goals/{id}/contributions/{contribution_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет взнос из цели; транзакция взноса остается",
+                "tags": [
+                    "goals"
+                ],
+                "summary": "Удалить взнос",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID цели",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID взноса",
+                        "name": "contribution_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Принимает те же поля, что и /transactions/import, проверяет файл и сразу возвращает задание.\nСтроки создаются в фоне партиями; прогресс и отчет по строкам доступны в /imports/{id}.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Фоновый импорт транзакций",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл выписки",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: csv, ofx или qif; по умолчанию определяется по расширению файла",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Для CSV: сопоставление столбцов в JSON",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Профиль выгрузки банка вместо mapping: tinkoff, sber, revolut или wise",
+                        "name": "preset",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Для QIF: формат даты в нотации Go",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Правила категорий в JSON",
+                        "name": "rules",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Создавать отсутствующие категории",
+                        "name": "create_categories",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Категория для строк без категории",
+                        "name": "default_category",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает статус, прогресс и отчет по уже обработанным строкам",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Задание импорта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Останавливает импорт после текущей партии. Уже созданные транзакции остаются",
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Отменить задание импорта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Аутентифицирует пользователя и возвращает JWT токен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Вход пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает профиль текущего пользователя. Ответ содержит ETag: при совпадении\nзаголовка If-None-Match с ним возвращается 304 без тела",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Профиль пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "304": {
+                        "description": "Профиль не изменился"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/currency": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Устанавливает валюту, в которую пересчитываются итоги, и в которой по умолчанию создаются транзакции",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Изменить базовую валюту",
+                "parameters": [
+                    {
+                        "description": "Код валюты ISO 4217",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetBaseCurrencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/email": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отправляет ссылку подтверждения на новый адрес. Email меняется только после перехода по ссылке.\nУказание текущего адреса отправляет ссылку для его подтверждения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Сменить email",
+                "parameters": [
+                    {
+                        "description": "Новый email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/email/confirm": {
+            "get": {
+                "description": "Применяет смену email по токену из письма",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Подтвердить смену email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен из ссылки",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выгружает профиль, категории и все транзакции пользователя в ZIP-архиве (profile.json, categories.json, transactions.json)",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Экспорт данных пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/language": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Устанавливает язык писем, которые отправляются без запроса пользователя: отчетов и уведомлений\nо бюджетах. Язык ответов API и остальных писем выбирается по заголовку Accept-Language",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Изменить язык писем",
+                "parameters": [
+                    {
+                        "description": "Код языка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetLanguageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/logins": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает последние успешные входы пользователя с IP-адресом и User-Agent",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "История входов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (1-100, по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LoginEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/month-start": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Устанавливает день, с которого в отчетах начинаются месяцы, например день зарплаты: при 25 месяц\nдлится с 25 числа по 24 следующего месяца, кварталы и годы складываются из таких месяцев.\nБюджеты и отчеты за явно указанные календарные месяцы не меняются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Изменить день начала месяца",
+                "parameters": [
+                    {
+                        "description": "День месяца от 1 до 28",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetMonthStartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/report-emails": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает, подписан ли пользователь на еженедельные и ежемесячные письма с итогами и бюджетами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Подписки на письма с отчетами",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportEmailPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подписывает на письма с итогами и ходом исполнения бюджетов за прошедшую неделю (с понедельника)\nили календарный месяц и отписывает от них. Письмо отправляется на email пользователя после окончания\nпериода; первое письмо приходит за период, в котором оформлена подписка. Для подписки нужен email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Изменить подписки на письма с отчетами",
+                "parameters": [
+                    {
+                        "description": "Подписки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportEmailPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportEmailPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payees": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает контрагентов пользователя для автодополнения: с q — только содержащих подстроку,\nсначала совпадения с начала имени, затем самые используемые",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payees"
+                ],
+                "summary": "Получить список контрагентов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Подстрока имени",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное число результатов (по умолчанию 10, не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Payee"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает контрагента пользователя. Имя уникально без учета регистра",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payees"
+                ],
+                "summary": "Создать контрагента",
+                "parameters": [
+                    {
+                        "description": "Данные контрагента",
+                        "name": "payee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePayee"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payee"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payees/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает контрагента пользователя по ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payees"
+                ],
+                "summary": "Получить контрагента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID контрагента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payee"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переименовывает контрагента пользователя; новое имя применяется ко всем его транзакциям",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payees"
+                ],
+                "summary": "Переименовать контрагента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID контрагента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое имя контрагента",
+                        "name": "payee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePayee"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Payee"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет контрагента пользователя; его транзакции остаются без контрагента",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payees"
+                ],
+                "summary": "Удалить контрагента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID контрагента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payees/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает число транзакций контрагента, даты первой и последней из них и суммы доходов и расходов по валютам.\nЗапланированные транзакции не учитываются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payees"
+                ],
+                "summary": "Получить статистику по контрагенту",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID контрагента",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayeeStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Создает нового пользователя с именем пользователя и паролем",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация нового пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/budget-vs-actual": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает по каждому месяцу периода бюджеты категорий и фактические расходы за вычетом возвратов\nс отклонением (бюджет минус расходы) и итоги месяцев по валютам. Расходы категорий без бюджета\nвходят с нулевым бюджетом; учитываются только месячные бюджеты категорий; запланированные транзакции, переводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Бюджет и факт",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Первый месяц периода в формате YYYY-MM (по умолчанию за 5 месяцев до to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний месяц периода в формате YYYY-MM (по умолчанию текущий)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetVsActual"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/compare": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает доходы, расходы за вычетом возвратов и их разницу за два периода в базовой валюте пользователя\nи те же суммы по категориям с изменением от периода A к периоду B. Периоды могут пересекаться и иметь разную длину.\nПереводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Сравнение периодов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Базовый период: год YYYY, месяц YYYY-MM или диапазон YYYY-MM-DD..YYYY-MM-DD",
+                        "name": "period_a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сравниваемый период в том же формате",
+                        "name": "period_b",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PeriodComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/forecast": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Прогнозирует суммарный остаток своих открытых счетов в базовой валюте пользователя на каждый день\nгоризонта: к текущему остатку прибавляются средний дневной поток за последние history_days дней\nи запланированные транзакции их дат (просроченные — завтрашним днем). Возвращает первый день,\nв который остаток становится отрицательным, если такой есть. Переводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Прогноз остатка",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Горизонт прогноза в днях, от 1 до 366 (по умолчанию 90)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Период истории для среднего дневного потока в днях, от 1 до 730 (по умолчанию 90)",
+                        "name": "history_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CashFlowForecast"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/net-worth": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает сумму остатков всех своих счетов, включая закрытые, в базовой валюте пользователя:\nактивы (положительные остатки) минус обязательства (отрицательные остатки).\nК остатку инвестиционного счета прибавляется текущая стоимость его позиций",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Чистые активы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NetWorth"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/net-worth/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает чистые активы — положительные остатки своих счетов минус отрицательные — на последний день\nкаждого дня, недели (с понедельника), месяца, квартала или года периода, за который сохранен снимок остатков. Интервалы\nбез снимков пропускаются. Остатки пересчитываются в базовую валюту пользователя по текущим курсам;\nстоимость позиций инвестиционных счетов в снимки не входит",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "История чистых активов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Интервал: day, week, month (по умолчанию), quarter или year; месяцы начинаются с дня начала месяца из профиля",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет до to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NetWorthHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/query": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Строит отчет по транзакциям из описания: фильтров, измерений группировки и показателей.\nИзмерения: category, payee, account, tag, currency, type, day, week, month, quarter, year.\nПоказатели: count, income, expense, net, average, min, max; доходы и расходы считаются за вычетом возвратов.\nДенежные показатели без фильтра по валюте группируются еще и по валюте. Переводы и корректировки не учитываются.\nМесяцы, кварталы и годы начинаются с дня начала месяца из профиля и обозначаются по первому дню",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Пользовательский отчет",
+                "parameters": [
+                    {
+                        "description": "Описание отчета",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportQuery"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportQueryResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/sankey": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает потоки за период в базовой валюте пользователя в виде узлов и связей диаграммы Санкея:\nдоходы категорий сходятся в узел бюджета, из которого расходятся расходы категорий за вычетом возвратов\nи остаток в сбережения. Переводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Диаграмма денежных потоков",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CashFlowSankey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/savings-rate": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает долю доходов, оставшуюся после расходов за вычетом возвратов, за весь период и по дням,\nнеделям (с понедельника), месяцам, кварталам или годам в базовой валюте пользователя. Переводы между счетами\nи корректировки остатков не учитываются ни в доходах, ни в расходах. Для интервалов без доходов норма не определена",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Норма сбережений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Интервал: day, week, month (по умолчанию), quarter или year; месяцы начинаются с дня начала месяца из профиля",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет до to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavingsRate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/spending-stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает по каждой валюте статистику расходов за вычетом возвратов за период: сумму, среднее, медиану\nи стандартное отклонение дневных расходов с учетом дней без расходов, расходы по дням недели\nи самый крупный расход. Запланированные транзакции, переводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Статистика расходов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SpendingStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/statement.pdf": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Формирует PDF-выписку за месяц: итоги по категориям и таблицу транзакций",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Выписка в PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Месяц в формате YYYY-MM",
+                        "name": "month",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Находит в расходах за последние 25 месяцев подписки — списания одной суммы у одного контрагента\nраз в неделю, месяц, квартал или год — и возвращает их стоимость в месяц и дату следующего списания.\nПодписки, очередное списание которых давно просрочено, считаются отмененными и не возвращаются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Subscriptions"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/subscriptions/{id}/schedule": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает запланированную транзакцию на дату следующего списания найденной подписки — копию\nпоследнего списания с той же суммой, категорией, счетом, контрагентом и тегами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Запланировать списание подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции последнего списания подписки (last_transaction_id)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/summary": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает доходы, расходы за вычетом возвратов, их разницу и число транзакций за период\nв базовой валюте пользователя и по каждой валюте. Переводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Итоги за период",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PeriodSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/timeseries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает доходы и расходы за вычетом возвратов по дням, неделям (с понедельника), месяцам, кварталам или годам периода\nв базовой валюте пользователя и по каждой валюте. Интервалы без транзакций входят с нулями; первый интервал\nначинается с начала интервала, содержащего from. Переводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Доходы и расходы по интервалам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Интервал: day, week, month (по умолчанию), quarter или year; месяцы начинаются с дня начала месяца из профиля",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет до to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/top-payees": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает контрагентов с наибольшими расходами за вычетом возвратов в базовой валюте пользователя\nили с наибольшим числом покупок за период. Запланированные транзакции, переводы и корректировки не учитываются",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Главные контрагенты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Первый день периода в формате YYYY-MM-DD (по умолчанию первый день месяца to с учетом дня начала месяца из профиля)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последний день периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Число контрагентов, от 1 до 100 (по умолчанию 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: spent (по умолчанию) или visits",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TopPayees"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/trends": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сравнивает расходы каждой категории за текущую неделю, месяц, квартал или год со средними расходами\nза несколько предыдущих периодов того же вида и возвращает изменение в процентах и направление.\nТекущий период может быть неполным. Суммы считаются в валютах транзакций за вычетом возвратов;\nзапланированные транзакции, переводы и корректировки не учитываются. Месяцы, кварталы и годы начинаются\nс дня начала месяца из профиля",
+                "produces": [
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Тренды расходов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Вид периода: week, month (по умолчанию), quarter или year",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Число предыдущих периодов для среднего, от 1 до 12 (по умолчанию 3)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "День текущего периода в формате YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат ответа: json (по умолчанию) или xlsx — книга Excel с листом на каждый раздел отчета",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SpendingTrends"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает список тегов пользователя, отсортированный по имени",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить список тегов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает новый тег пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Создать тег",
+                "parameters": [
+                    {
+                        "description": "Данные тега",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает тег пользователя по ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переименовывает тег пользователя; новое имя применяется ко всем транзакциям с этим тегом",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Переименовать тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое имя тега",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет тег пользователя и снимает его со всех транзакций",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удалить тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает список транзакций пользователя с возможностью фильтрации и пагинации.\nconverted_totals содержит суммы доходов и расходов по фильтру в базовой валюте пользователя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Получить список транзакций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип транзакции (income или expense)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID контрагента",
+                        "name": "payee_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока для поиска в описании",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имена тегов через запятую (достаточно совпадения с любым)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статус сверки (pending, cleared или reconciled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только транзакции, отмеченные как возможные дубликаты",
+                        "name": "possible_duplicate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только транзакции, отмеченные для проверки",
+                        "name": "flagged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка по дате (asc или desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключи сортировки через запятую: date, amount, category. Несовместим с sort",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Направления для ключей sort_by через запятую (asc или desc); одно значение применяется ко всем ключам",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из next_cursor предыдущей страницы; пустое значение — первая страница. Несовместим с page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит на страницу",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetTransactionsResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Страница не изменилась"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает новую транзакцию для пользователя. Если найдены похожие транзакции (та же сумма, тип, валюта\nи контрагент, дата в пределах суток), транзакция создается с possible_duplicate и ID похожих в duplicate_of,\nа при on_duplicate=reject не создается и возвращается 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Создать новую транзакцию",
+                "parameters": [
+                    {
+                        "description": "Данные транзакции",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransactionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Реакция на возможный дубликат: flag (по умолчанию) или reject",
+                        "name": "on_duplicate",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateConflictResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Перемещает в корзину одним запросом транзакции, выбранные списком ID и/или фильтром (тип, категория, период),\nи возвращает их число. Пустой запрос отклоняется, чтобы случайно не удалить все.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Удалить несколько транзакций",
+                "parameters": [
+                    {
+                        "description": "Условия удаления",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteTransactionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteTransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает транзакции из массива в одной транзакции БД. Если хотя бы один элемент\nне проходит проверку, не создается ни одна транзакция, а в results перечисляются ошибки по индексам.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Создать несколько транзакций",
+                "parameters": [
+                    {
+                        "description": "Массив транзакций",
+                        "name": "transactions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CreateTransactionRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkTransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkTransactionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выгружает транзакции пользователя в формате JSON Lines (по одной транзакции на строку) в порядке даты.\nСтроки читаются из базы по мере отправки клиенту, поэтому выгрузка не накапливается в памяти.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Потоковый экспорт транзакций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Формат выгрузки; поддерживается только jsonl (по умолчанию)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип транзакции (income или expense)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода включительно (RFC 3339)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (RFC 3339)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включать запланированные транзакции (по умолчанию false)",
+                        "name": "include_planned",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загружает банковскую выписку и создает транзакции. Для CSV столбцы задаются сопоставлением mapping\nили встроенным профилем банка preset.\nКатегории строк без категории назначаются по правилам rules (подстрока в контрагенте или описании),\nзатем по ключевым словам категорий пользователя.\nНекорректные строки пропускаются и перечисляются в отчете; корректные создаются одной транзакцией БД.\nСтроки, похожие на уже существующие транзакции, создаются с отметкой possible_duplicate.\nПри dry_run=true файл только проверяется: отчет содержит транзакции, которые были бы созданы, и ничего не записывается.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Импорт транзакций из CSV, OFX или QIF",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл выписки",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: csv, ofx или qif; по умолчанию определяется по расширению файла",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Для CSV: сопоставление столбцов в JSON (date, amount, type, category, payee, description, currency, tags, date_format, delimiter, encoding)",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Профиль выгрузки банка вместо mapping: tinkoff, sber, revolut или wise; подразумевает формат csv",
+                        "name": "preset",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Для QIF: формат даты в нотации Go",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Правила категорий в JSON: [{\\",
+                        "name": "rules",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Создавать отсутствующие категории",
+                        "name": "create_categories",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Категория для строк без категории",
+                        "name": "default_category",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить файл и показать результат без записи",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/mark-cleared": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит ожидающие (pending) транзакции в статус cleared: по списку ID и/или все с датой не позже date_to.\nТранзакции в других статусах не изменяются. Возвращает число обновленных транзакций.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Отметить транзакции проведенными",
+                "parameters": [
+                    {
+                        "description": "Отмечаемые транзакции",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MarkClearedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarkClearedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/receipt": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает кассовый чек из ФНС по строке QR-кода и создает по нему транзакцию в рублях\nс продавцом в качестве контрагента. Позиции чека сохраняются и доступны в /transactions/{id}/receipt.\nПокупка и возврат расхода создаются как расход, возврат покупки — как доход.\nЕсли category_id не указан, категория подбирается по ключевым словам категорий в продавце и описании.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Транзакция по QR-коду чека",
+                "parameters": [
+                    {
+                        "description": "Данные QR-кода",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ScanReceiptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceiptTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ищет транзакции по описанию, названию категории и имени контрагента с учетом словоформ и по подстроке.\nРезультаты упорядочены по релевантности, совпадения в описании выделены тегом \u003cmark\u003e.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Полнотекстовый поиск транзакций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос (поддерживаются кавычки, or и -)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит на страницу",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchTransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает детали конкретной транзакции пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Получить транзакцию по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Обновляет существующую транзакцию пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Обновить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные транзакции",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Перемещает транзакцию пользователя в корзину",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Удалить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет только переданные поля транзакции; остальные сохраняют прежние значения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Частично обновить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/duplicate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает копию транзакции (тип, категория, описание, валюта, теги) с текущей датой.\nДату и сумму можно переопределить в теле запроса; тело необязательно.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Повторить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исходной транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Переопределяемые поля",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/flag": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ставит или снимает отметку flagged, которой пользователь помечает транзакции для последующей проверки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Переключить отметку транзакции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает версии транзакции от первой к последней: состояние после каждого изменения и список изменившихся полей",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Получить историю изменений транзакции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TransactionVersion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/linked": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает расход, привязанные к нему возвраты и сумму расхода за вычетом возвратов.\nДля возврата возвращается группа его исходного расхода.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Связанные транзакции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LinkedTransactions"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/receipt": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает кассовый чек с позициями, по которому создана транзакция",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Чек транзакции",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Receipt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/resolve-duplicate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Снимает с транзакции отметку possible_duplicate. При action=confirm транзакция признается дубликатом\nи перемещается в корзину, при action=dismiss остается как обычная транзакция.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Разрешить возможный дубликат",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Решение",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolveDuplicateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает транзакцию из корзины",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Восстановить транзакцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/revert/{version}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает транзакции состояние из указанной версии истории. Откат сохраняется в истории новой версией.\nЕсли контрагент версии был удален, он создается заново по имени.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Откатить транзакцию к версии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID транзакции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер версии",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает переводы между счетами как единые операции, начиная с последних",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Список переводов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Только переводы с этого счета или на него",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы (по умолчанию 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество переводов на странице (по умолчанию 10, не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetTransfersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Атомарно создает расход на счете-источнике и доход на счете-получателе. Переводы не учитываются\nв доходах и расходах. Для счетов в разных валютах нужна сумма зачисления to_amount или курс fx_rate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Перевести между счетами",
+                "parameters": [
+                    {
+                        "description": "Данные перевода",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ValidationErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Получить перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID перевода",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Перемещает обе транзакции перевода в корзину; восстановление любой из них возвращает перевод целиком",
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Удалить перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID перевода",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Получает удаленные транзакции пользователя. Транзакции удаляются из корзины окончательно по истечении срока хранения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Получить корзину",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит на страницу",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetTransactionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Окончательно удаляет все транзакции пользователя из корзины",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Очистить корзину",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteTransactionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Открывает соединение WebSocket, по которому сервер отправляет события пользователя в формате JSON:\ntransaction.created (data — транзакция), budget.threshold (data — превышенный бюджет)\nи import.finished (data — завершенное задание импорта без отчета по строкам).\nБраузер не может передать заголовок Authorization, поэтому токен принимается и в параметре token.\nСообщения клиента игнорируются. Если клиент не успевает читать события, соединение закрывается с кодом 1013.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Поток событий через WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT, если не передан заголовок Authorization",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "epoch": {
+                    "type": "string",
+                    "example": "d2x1q8v0c3k"
+                },
+                "id": {
+                    "description": "ID возрастает в пределах эпохи — запуска процесса, который опубликовал событие",
+                    "type": "integer",
+                    "example": 42
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2025-07-01T12:00:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "transaction.created"
+                }
+            }
+        },
+        "models.Account": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance — текущий остаток: начальный баланс плюс доходы и минус расходы по счету\nбез запланированных и удаленных транзакций",
+                    "type": "number",
+                    "example": 15250.5
+                },
+                "closed_at": {
+                    "description": "ClosedAt — время закрытия счета; транзакции закрытого счета доступны только для чтения",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_limit": {
+                    "description": "CreditLimit, StatementDay и PaymentDueDay — кредитный лимит, день закрытия выписки\nи день платежа; только для кредитных карт",
+                    "type": "number",
+                    "example": 100000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "initial_balance": {
+                    "description": "InitialBalance — начальный остаток счета до всех его транзакций",
+                    "type": "number",
+                    "example": 1000
+                },
+                "loan": {
+                    "description": "Loan — условия кредита; только для кредитов",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LoanTerms"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Наличные"
+                },
+                "payment_due_day": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "role": {
+                    "description": "Role — доступ текущего пользователя к счету: owner, editor или viewer",
+                    "type": "string",
+                    "example": "owner"
+                },
+                "statement_day": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "type": {
+                    "description": "Type — тип счета: regular, credit_card, loan, investment или crypto",
+                    "type": "string",
+                    "example": "regular"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AccountBalance": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "balance": {
+                    "type": "number",
+                    "example": 15250.5
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "initial_balance": {
+                    "type": "number",
+                    "example": 1000
+                }
+            }
+        },
+        "models.AccountShare": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role — viewer (просмотр счета и его транзакций) или editor (также транзакции, переводы и позиции по счету)",
+                    "type": "string",
+                    "example": "editor"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jane_doe"
+                }
+            }
+        },
+        "models.AmortizationRow": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance — остаток основного долга после платежа",
+                    "type": "number",
+                    "example": 2997165.78
+                },
+                "date": {
+                    "type": "string"
+                },
+                "interest": {
+                    "type": "number",
+                    "example": 31250
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "paid": {
+                    "description": "Paid — платеж уже внесен: внесено не меньше платежей, чем его номер",
+                    "type": "boolean"
+                },
+                "payment": {
+                    "type": "number",
+                    "example": 34084.22
+                },
+                "principal": {
+                    "type": "number",
+                    "example": 2834.22
+                }
+            }
+        },
+        "models.AmortizationSchedule": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "payment": {
+                    "type": "number",
+                    "example": 34084.22
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AmortizationRow"
+                    }
+                },
+                "total_interest": {
+                    "type": "number",
+                    "example": 5180212.8
+                }
+            }
+        },
+        "models.ApplyBudgetTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-08"
+                }
+            }
+        },
+        "models.AvailableFunds": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencyAmount"
+                    }
+                },
+                "currency": {
+                    "description": "Currency и Total — сумма остатков в базовой валюте пользователя; Total нет, если курсы недоступны",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 25250.5
+                }
+            }
+        },
+        "models.BalancePoint": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number",
+                    "example": 15250.5
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "carryover": {
+                    "description": "Carryover — перенос с прошлого периода; отрицательный после перерасхода",
+                    "type": "number",
+                    "example": 1500
+                },
+                "category_id": {
+                    "description": "CategoryID или TagID — категория или тег бюджета; задано одно из двух",
+                    "type": "integer",
+                    "example": 3
+                },
+                "currency": {
+                    "description": "Currency — валюта лимита; расходы в других валютах в бюджете не учитываются",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-07-31T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "month": {
+                    "description": "Month — месяц бюджета в формате YYYY-MM; только у месячных бюджетов",
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "period": {
+                    "description": "Period — вид периода: week, month, quarter или custom",
+                    "type": "string",
+                    "example": "month"
+                },
+                "rollover": {
+                    "description": "Rollover — остаток бюджета переносится на следующий период того же вида, перерасход уменьшает его лимит",
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "description": "StartDate и EndDate — первый и последний день периода включительно",
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "tag_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BudgetProgress": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Available — лимит с учетом переноса с прошлого периода",
+                    "type": "number",
+                    "example": 21500
+                },
+                "carryover": {
+                    "type": "number",
+                    "example": 1500
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "elapsed": {
+                    "description": "Elapsed — прошедшая доля периода от 0 до 1; расходы идут с опережением, если spent/available больше нее",
+                    "type": "number",
+                    "example": 0.55
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-07-31T00:00:00Z"
+                },
+                "exceeded": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "remaining": {
+                    "description": "Remaining — неизрасходованная часть доступной суммы; отрицательная, если она превышена",
+                    "type": "number",
+                    "example": 6249.5
+                },
+                "rollover": {
+                    "type": "boolean"
+                },
+                "spent": {
+                    "description": "Spent — расходы категории или по тегу за период в валюте бюджета за вычетом возвратов",
+                    "type": "number",
+                    "example": 15250.5
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "tag_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "tag_name": {
+                    "type": "string",
+                    "example": "vacation-2025"
+                }
+            }
+        },
+        "models.BudgetTemplate": {
+            "type": "object",
+            "properties": {
+                "auto_apply": {
+                    "description": "AutoApply — в начале каждого месяца по шаблону создаются бюджеты месяца",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetTemplateItem"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Обычный месяц"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BudgetTemplateItem": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "limit": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "rollover": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.BudgetVsActual": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "From и To — первый и последний месяцы периода в формате YYYY-MM",
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetVsActualRow"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-06"
+                },
+                "totals": {
+                    "description": "Totals — итоги по месяцам в каждой валюте",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetVsActualTotal"
+                    }
+                }
+            }
+        },
+        "models.BudgetVsActualRow": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "description": "Actual — расходы за вычетом возвратов",
+                    "type": "number",
+                    "example": 23000
+                },
+                "budgeted": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "variance": {
+                    "description": "Variance — бюджет минус расходы; отрицательная при перерасходе",
+                    "type": "number",
+                    "example": -3000
+                }
+            }
+        },
+        "models.BudgetVsActualTotal": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "number",
+                    "example": 76500
+                },
+                "budgeted": {
+                    "type": "number",
+                    "example": 80000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "variance": {
+                    "type": "number",
+                    "example": 3500
+                }
+            }
+        },
+        "models.BulkTransactionResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "amount must be positive"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "transaction": {
+                    "$ref": "#/definitions/models.Transaction"
+                }
+            }
+        },
+        "models.BulkTransactionsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "validation failed"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BulkTransactionResult"
+                    }
+                }
+            }
+        },
+        "models.CashFlowForecast": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance — текущий суммарный остаток",
+                    "type": "number",
+                    "example": 120000
+                },
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которой считается прогноз",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "daily_average": {
+                    "description": "DailyAverage — средний чистый поток за день (доходы минус расходы) за последние history_days дней",
+                    "type": "number",
+                    "example": -350.25
+                },
+                "history_days": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "negative_date": {
+                    "description": "NegativeDate — первый день, в который прогнозируемый остаток становится отрицательным",
+                    "type": "string",
+                    "example": "2025-09-14"
+                },
+                "points": {
+                    "description": "Points — прогноз на каждый день, начиная с завтрашнего",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ForecastPoint"
+                    }
+                }
+            }
+        },
+        "models.CashFlowSankey": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны потоки",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-07-01"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SankeyLink"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SankeyNode"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#4caf50"
+                },
+                "icon": {
+                    "description": "Icon — имя значка из набора /categories/icons; Color — цвет в формате #rrggbb.\nПустые значения означают, что значок и цвет не заданы",
+                    "type": "string",
+                    "example": "cart"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "system": {
+                    "description": "System — общая категория, доступная всем пользователям только для чтения; ее user_id равен 0",
+                    "type": "boolean"
+                },
+                "total_spent": {
+                    "type": "number",
+                    "example": 52500
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionTotals"
+                    }
+                },
+                "transaction_count": {
+                    "description": "TransactionCount, TotalSpent и Totals заполняются только в списке категорий с with_stats=true.\nTotalSpent — расходы за вычетом возвратов в базовой валюте пользователя; отсутствует, если курсы недоступны.\nTotals — суммы доходов и расходов по валютам",
+                    "type": "integer",
+                    "example": 42
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CategoryComparison": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "$ref": "#/definitions/models.ComparisonTotals"
+                },
+                "b": {
+                    "$ref": "#/definitions/models.ComparisonTotals"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "delta": {
+                    "$ref": "#/definitions/models.ComparisonTotals"
+                },
+                "direction": {
+                    "description": "Direction — направление изменения расходов: up, down, flat или new",
+                    "type": "string",
+                    "example": "down"
+                },
+                "expense_change": {
+                    "description": "ExpenseChange — изменение расходов в процентах; отсутствует, если в периоде A расходов не было",
+                    "type": "number",
+                    "example": -12.5
+                }
+            }
+        },
+        "models.CategoryKeyword": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "keyword": {
+                    "type": "string",
+                    "example": "Пятерочка"
+                }
+            }
+        },
+        "models.CategoryMonthStats": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "expense": {
+                    "type": "number",
+                    "example": 4800
+                },
+                "income": {
+                    "type": "number",
+                    "example": 0
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2025-01"
+                }
+            }
+        },
+        "models.CategoryStats": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "from": {
+                    "description": "From — начало периода; не задано для all",
+                    "type": "string"
+                },
+                "monthly": {
+                    "description": "Monthly — суммы по месяцам периода в каждой валюте; месяцы без транзакций входят с нулями",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryMonthStats"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "period": {
+                    "description": "Period — month, quarter, year или all",
+                    "type": "string",
+                    "example": "year"
+                },
+                "to": {
+                    "description": "To — конец периода (не включительно): начало следующего месяца",
+                    "type": "string"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryStatsTotals"
+                    }
+                }
+            }
+        },
+        "models.CategoryStatsTotals": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "description": "Average — средняя сумма транзакции",
+                    "type": "number",
+                    "example": 1250
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "expense": {
+                    "description": "Expense — расходы за вычетом привязанных к ним возвратов",
+                    "type": "number",
+                    "example": 52500
+                },
+                "income": {
+                    "type": "number",
+                    "example": 0
+                }
+            }
+        },
+        "models.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "new@example.com"
+                }
+            }
+        },
+        "models.ComparedPeriod": {
+            "type": "object",
+            "properties": {
+                "expense": {
+                    "type": "number",
+                    "example": 32000.5
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-06-01"
+                },
+                "income": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "net": {
+                    "type": "number",
+                    "example": 17999.5
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-06-30"
+                }
+            }
+        },
+        "models.ComparisonTotals": {
+            "type": "object",
+            "properties": {
+                "expense": {
+                    "type": "number",
+                    "example": 32000.5
+                },
+                "income": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "net": {
+                    "type": "number",
+                    "example": 17999.5
+                }
+            }
+        },
+        "models.CreateAccount": {
+            "type": "object",
+            "properties": {
+                "credit_limit": {
+                    "description": "CreditLimit, StatementDay и PaymentDueDay обязательны для кредитных карт и не задаются для других счетов.\nStatementDay и PaymentDueDay — дни месяца от 1 до 28: закрытие выписки и срок платежа по ней",
+                    "type": "number",
+                    "example": 100000
+                },
+                "currency": {
+                    "description": "Currency — код валюты ISO 4217; по умолчанию базовая валюта пользователя",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "initial_balance": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "loan": {
+                    "description": "Loan — условия кредита; обязательны для кредитов и не задаются для других счетов.\nНачальный остаток кредита — минус сумма кредита",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LoanTerms"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Наличные"
+                },
+                "payment_due_day": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "statement_day": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "type": {
+                    "description": "Type — regular (по умолчанию), credit_card, loan, investment или crypto; не меняется после создания",
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "credit_card",
+                        "loan",
+                        "investment",
+                        "crypto"
+                    ],
+                    "example": "regular"
+                }
+            }
+        },
+        "models.CreateAdjustment": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance — фактический остаток счета, к которому приводится остаток по транзакциям",
+                    "type": "number",
+                    "example": 15000
+                },
+                "date": {
+                    "description": "Date — дата корректировки; по умолчанию текущее время",
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Сверка с банком"
+                }
+            }
+        },
+        "models.CreateBudget": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "description": "CategoryID или TagID — категория или тег бюджета; задается одно из двух.\nВ бюджет тега входят расходы с этим тегом во всех категориях",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "currency": {
+                    "description": "Currency — код валюты ISO 4217; по умолчанию базовая валюта пользователя",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "description": "EndDate — последний день периода включительно; только для custom",
+                    "type": "string",
+                    "example": "2025-07-27T00:00:00Z"
+                },
+                "limit": {
+                    "type": "number",
+                    "example": 20000
+                },
+                "month": {
+                    "description": "Month — месяц бюджета в формате YYYY-MM; для месячного бюджета вместо start_date",
+                    "type": "string",
+                    "example": "2025-07"
+                },
+                "period": {
+                    "description": "Period — week, month (по умолчанию), quarter или custom",
+                    "type": "string",
+                    "example": "month"
+                },
+                "rollover": {
+                    "description": "Rollover — переносить остаток или перерасход на следующий период",
+                    "type": "boolean",
+                    "example": false
+                },
+                "start_date": {
+                    "description": "StartDate — день, неделя или квартал которого задают период; для custom — первый день",
+                    "type": "string",
+                    "example": "2025-07-14T00:00:00Z"
+                },
+                "tag_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "models.CreateBudgetTemplate": {
+            "type": "object",
+            "properties": {
+                "auto_apply": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "items": {
+                    "description": "Items — лимиты категорий; валюта по умолчанию — базовая валюта пользователя",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetTemplateItem"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Обычный месяц"
+                }
+            }
+        },
+        "models.CreateCategory": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "description": "Color — цвет в формате #rrggbb",
+                    "type": "string",
+                    "example": "#4caf50"
+                },
+                "icon": {
+                    "description": "Icon — имя значка из набора /categories/icons",
+                    "type": "string",
+                    "example": "cart"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Продукты"
+                }
+            }
+        },
+        "models.CreateCategoryKeyword": {
+            "type": "object",
+            "properties": {
+                "keyword": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Пятерочка"
+                }
+            }
+        },
+        "models.CreateGoal": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "AccountID — счет, на котором копятся деньги; необязателен",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "currency": {
+                    "description": "Currency — код валюты ISO 4217; по умолчанию валюта счета или базовая валюта пользователя",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "deadline": {
+                    "type": "string",
+                    "example": "2026-06-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Отпуск"
+                },
+                "target": {
+                    "type": "number",
+                    "example": 150000
+                }
+            }
+        },
+        "models.CreateGoalContribution": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount — сумма взноса в валюте цели; отрицательная — изъятие",
+                    "type": "number",
+                    "example": 5000
+                },
+                "date": {
+                    "description": "Date — дата взноса; по умолчанию сегодня",
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Отложил с зарплаты"
+                },
+                "transaction_id": {
+                    "description": "TransactionID — транзакция в валюте цели, которая учитывается взносом",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 42
+                }
+            }
+        },
+        "models.CreateHolding": {
+            "type": "object",
+            "properties": {
+                "cost_basis": {
+                    "description": "CostBasis — сумма покупки всех бумаг позиции в валюте счета",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 25000
+                },
+                "quantity": {
+                    "description": "Quantity — количество бумаг, не больше 1e12",
+                    "type": "number",
+                    "maximum": 1000000000000,
+                    "example": 100
+                },
+                "ticker": {
+                    "description": "Ticker — тикер бумаги или символ криптовалюты у провайдера котировок; уникален в пределах счета",
+                    "type": "string",
+                    "example": "SBER"
+                }
+            }
+        },
+        "models.CreateInvite": {
+            "type": "object",
+            "properties": {
+                "expires_in_hours": {
+                    "description": "Срок действия приглашения в часах; 0 — бессрочно",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 72
+                }
+            }
+        },
+        "models.CreateLoanPayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount — сумма платежа; по умолчанию платеж по графику",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 34084.22
+                },
+                "category_id": {
+                    "description": "CategoryID — категория расхода на проценты; обязательна для кредитов с ненулевой ставкой",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-08-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Платеж по ипотеке"
+                },
+                "from_account_id": {
+                    "description": "FromAccountID — счет, с которого вносится платеж; валюта счета должна совпадать с валютой кредита",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.CreatePayee": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Пятерочка"
+                }
+            }
+        },
+        "models.CreateTag": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "vacation"
+                }
+            }
+        },
+        "models.CreateTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "AccountID — счет транзакции; валюта транзакции должна совпадать с валютой счета",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 1250.5
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "currency": {
+                    "description": "Currency — код валюты ISO 4217; по умолчанию базовая валюта пользователя",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "description": "Date — дата транзакции; по умолчанию текущее время",
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Продукты на неделю"
+                },
+                "fx_rate": {
+                    "description": "FXRate — курс пересчета в валюту транзакции; если не задан, вычисляется как amount / original_amount",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 98.5
+                },
+                "linked_transaction_id": {
+                    "description": "LinkedTransactionID — ID расхода, возвратом по которому является этот доход",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 42
+                },
+                "original_amount": {
+                    "description": "OriginalAmount и OriginalCurrency — сумма и валюта покупки за границей; передаются вместе",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 25.5
+                },
+                "original_currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "payee": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Пятерочка"
+                },
+                "payee_id": {
+                    "description": "PayeeID — ID контрагента; вместо него можно передать имя в Payee до 100 символов",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "planned": {
+                    "description": "Planned — запланированная транзакция; требует дату в будущем",
+                    "type": "boolean",
+                    "example": false
+                },
+                "status": {
+                    "description": "Status — pending, cleared или reconciled; по умолчанию cleared при создании и прежний при обновлении",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "cleared",
+                        "reconciled"
+                    ],
+                    "example": "pending"
+                },
+                "tags": {
+                    "description": "Tags — имена тегов до 50 символов",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vacation",
+                        "work"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ],
+                    "example": "expense"
+                }
+            }
+        },
+        "models.CreateTransfer": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "date": {
+                    "description": "Date — дата перевода; по умолчанию текущее время",
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Пополнение карты"
+                },
+                "from_account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "fx_rate": {
+                    "description": "FXRate — курс перевода; по умолчанию to_amount / amount",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0.0105
+                },
+                "to_account_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "to_amount": {
+                    "description": "ToAmount — сумма, зачисленная на счет-получатель; по умолчанию amount * fx_rate",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 10.5
+                }
+            }
+        },
+        "models.CreateUser": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "captcha_token": {
+                    "description": "Токен CAPTCHA-виджета, обязателен при включенной CAPTCHA",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "invite_code": {
+                    "description": "Код приглашения, обязателен при REGISTRATION_MODE=invite",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password — не короче 6 символов",
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreditCardStatement": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "available_credit": {
+                    "type": "number",
+                    "example": 69000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "current_balance": {
+                    "type": "number",
+                    "example": 31000
+                },
+                "days_until_due": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "minimum_due": {
+                    "type": "number",
+                    "example": 250
+                },
+                "minimum_payment": {
+                    "description": "MinimumPayment — обязательный платеж по выписке, MinimumDue — его непогашенная часть",
+                    "type": "number",
+                    "example": 1250
+                },
+                "overdue": {
+                    "type": "boolean"
+                },
+                "paid": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "description": "PeriodStart и PeriodEnd — первый и последний день закрытой выписки",
+                    "type": "string"
+                },
+                "remaining_due": {
+                    "description": "RemainingDue — сколько осталось внести до даты платежа, чтобы погасить долг по выписке",
+                    "type": "number",
+                    "example": 24000
+                },
+                "reminder": {
+                    "description": "Reminder — напоминание об обязательном платеже, если он не внесен и срок близко или прошел",
+                    "type": "string",
+                    "example": "Внесите обязательный платеж 250.00 RUB до 15.03.2025"
+                },
+                "statement_balance": {
+                    "description": "StatementBalance — долг на конец выписки; Paid — поступления на карту после закрытия выписки",
+                    "type": "number",
+                    "example": 25000
+                }
+            }
+        },
+        "models.CurrencyAmount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 15250.5
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                }
+            }
+        },
+        "models.CurrencySpendingStats": {
+            "type": "object",
+            "properties": {
+                "by_weekday": {
+                    "description": "ByWeekday — расходы по дням недели с понедельника по воскресенье",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WeekdaySpend"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "expense_count": {
+                    "type": "integer",
+                    "example": 57
+                },
+                "largest_expense": {
+                    "description": "LargestExpense — самый крупный расход за период",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    ]
+                },
+                "mean_daily": {
+                    "type": "number",
+                    "example": 1032.27
+                },
+                "median_daily": {
+                    "type": "number",
+                    "example": 640
+                },
+                "stddev_daily": {
+                    "type": "number",
+                    "example": 1210.4
+                },
+                "total": {
+                    "description": "Total — расходы за вычетом возвратов",
+                    "type": "number",
+                    "example": 32000.5
+                }
+            }
+        },
+        "models.CurrencySummary": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "expense": {
+                    "type": "number",
+                    "example": 32000.5
+                },
+                "income": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "net": {
+                    "type": "number",
+                    "example": 17999.5
+                }
+            }
+        },
+        "models.Dashboard": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance — суммарный остаток своих открытых счетов",
+                    "type": "number",
+                    "example": 120000
+                },
+                "budgets": {
+                    "description": "Budgets — бюджеты, действующие сегодня, с ходом исполнения",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BudgetProgress"
+                    }
+                },
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны остаток и итоги месяца",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "month": {
+                    "description": "Month — итоги текущего месяца, начинающегося с дня начала месяца пользователя, по сегодняшний день",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PeriodSummary"
+                        }
+                    ]
+                },
+                "recent_transactions": {
+                    "description": "RecentTransactions — последние транзакции по убыванию даты",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                },
+                "upcoming": {
+                    "description": "Upcoming — запланированные транзакции на ближайшие дни по возрастанию даты",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                }
+            }
+        },
+        "models.DeleteTransactionsRequest": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "date_from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "date_to": {
+                    "type": "string",
+                    "example": "2025-01-31T23:59:59Z"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ],
+                    "example": "expense"
+                }
+            }
+        },
+        "models.DeleteTransactionsResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.DuplicateConflictResponse": {
+            "type": "object",
+            "properties": {
+                "duplicate_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        15
+                    ]
+                },
+                "error": {
+                    "type": "string",
+                    "example": "possible duplicate"
+                }
+            }
+        },
+        "models.DuplicateTransactionRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 1250.5
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-06-14T10:00:00Z"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "error"
+                },
+                "request_id": {
+                    "description": "RequestID — идентификатор запроса из заголовка X-Request-ID для обращения в поддержку",
+                    "type": "string",
+                    "example": "4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field — имя поля в JSON; для элементов списка с индексом, например tags[1]",
+                    "type": "string",
+                    "example": "amount"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "amount must be positive"
+                }
+            }
+        },
+        "models.ForecastPoint": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number",
+                    "example": 104649.75
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-07-16"
+                },
+                "planned": {
+                    "description": "Planned — сумма запланированных на день транзакций: доходы со знаком плюс, расходы со знаком минус",
+                    "type": "number",
+                    "example": -15000
+                }
+            }
+        },
+        "models.GetTransactionsResponse": {
+            "type": "object",
+            "properties": {
+                "converted_totals": {
+                    "description": "ConvertedTotals — суммы по фильтру в базовой валюте пользователя",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionTotals"
+                        }
+                    ]
+                },
+                "next_cursor": {
+                    "description": "NextCursor — значение after для следующей страницы при курсорной пагинации;\nотсутствует на последней странице",
+                    "type": "string",
+                    "example": "MjAyNS0wMS0xNVQwMDowMDowMFp8NDI"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                }
+            }
+        },
+        "models.GetTransfersResponse": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transfer"
+                    }
+                }
+            }
+        },
+        "models.Goal": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "AccountID — счет, на котором копятся деньги; взносами могут быть только его транзакции",
+                    "type": "integer",
+                    "example": 2
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "deadline": {
+                    "description": "Deadline — срок, к которому нужно накопить сумму; может отсутствовать",
+                    "type": "string",
+                    "example": "2026-06-01T00:00:00Z"
+                },
+                "first_contribution_date": {
+                    "description": "FirstContributionDate — дата первого взноса; от нее считается средний темп накоплений",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Отпуск"
+                },
+                "on_track": {
+                    "description": "OnTrack — цель будет достигнута к сроку при текущем темпе; задано только при сроке",
+                    "type": "boolean"
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 30
+                },
+                "projected_date": {
+                    "description": "ProjectedDate — ожидаемая дата достижения цели при среднем темпе взносов",
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "number",
+                    "example": 105000
+                },
+                "required_monthly": {
+                    "description": "RequiredMonthly — ежемесячный взнос, нужный, чтобы успеть к сроку",
+                    "type": "number",
+                    "example": 10500
+                },
+                "saved": {
+                    "description": "Saved — сумма взносов; Remaining — сколько осталось накопить; Percent — процент выполнения",
+                    "type": "number",
+                    "example": 45000
+                },
+                "target": {
+                    "type": "number",
+                    "example": 150000
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GoalContribution": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 5000
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Отложил с зарплаты"
+                },
+                "goal_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "description": "TransactionID — транзакция, учтенная взносом; ее сумма, дата и описание",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.Holding": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "cost_basis": {
+                    "description": "CostBasis — сумма покупки всех бумаг позиции в валюте счета",
+                    "type": "number",
+                    "example": 25000
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "Quantity — количество бумаг или монет; может быть дробным",
+                    "type": "number",
+                    "example": 100
+                },
+                "ticker": {
+                    "description": "Ticker — тикер бумаги или символ криптовалюты",
+                    "type": "string",
+                    "example": "SBER"
+                }
+            }
+        },
+        "models.HoldingValue": {
+            "type": "object",
+            "properties": {
+                "cost_basis": {
+                    "type": "number",
+                    "example": 25000
+                },
+                "holding_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "market_value": {
+                    "type": "number",
+                    "example": 31025
+                },
+                "price": {
+                    "description": "Price — цена одной бумаги в валюте счета",
+                    "type": "number",
+                    "example": 310.25
+                },
+                "priced": {
+                    "description": "Priced — цена бумаги известна; без цены позиция оценивается по сумме покупки",
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "type": "number",
+                    "example": 100
+                },
+                "ticker": {
+                    "type": "string",
+                    "example": "SBER"
+                },
+                "unrealized_pl": {
+                    "type": "number",
+                    "example": 6025
+                }
+            }
+        },
+        "models.ImportJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "processed_rows": {
+                    "type": "integer",
+                    "example": 1500
+                },
+                "report": {
+                    "description": "Report — отчет по уже обработанным строкам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status — pending, running, completed, failed или cancelled",
+                    "type": "string",
+                    "example": "running"
+                },
+                "total_rows": {
+                    "type": "integer",
+                    "example": 5000
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created_categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Кафе"
+                    ]
+                },
+                "dry_run": {
+                    "description": "DryRun — файл только проверен, транзакции и категории не созданы;\nImported и CreatedCategories показывают, что было бы создано",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowResult"
+                    }
+                }
+            }
+        },
+        "models.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Category — категория, назначенная строке",
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "invalid amount \"abc\""
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "possible_duplicate": {
+                    "description": "PossibleDuplicate — созданная транзакция похожа на уже существующую",
+                    "type": "boolean"
+                },
+                "transaction": {
+                    "description": "Transaction — транзакция, которая была бы создана; только при dry_run.\nУ категорий, которые были бы созданы, category_id равен 0",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    ]
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.InvestmentValuation": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "cost_basis": {
+                    "type": "number",
+                    "example": 25000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HoldingValue"
+                    }
+                },
+                "market_value": {
+                    "type": "number",
+                    "example": 31025
+                },
+                "unrealized_pl": {
+                    "type": "number",
+                    "example": 6025
+                }
+            }
+        },
+        "models.Invite": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "3f9a1c0b7e2d4a6f8c1b2d3e"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.JobStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "last_duration": {
+                    "description": "LastDuration — длительность последнего запуска, например \"1.5s\"",
+                    "type": "string",
+                    "example": "1.5s"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_run": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "trash_purge"
+                },
+                "next_run": {
+                    "type": "string"
+                },
+                "running": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "runs": {
+                    "description": "Runs — число запусков с момента старта сервиса",
+                    "type": "integer",
+                    "example": 12
+                },
+                "schedule": {
+                    "type": "string",
+                    "example": "@every 1h"
+                }
+            }
+        },
+        "models.LinkedTransactions": {
+            "type": "object",
+            "properties": {
+                "net_amount": {
+                    "description": "NetAmount — сумма расхода за вычетом возвратов",
+                    "type": "number",
+                    "example": 750.5
+                },
+                "refunded_amount": {
+                    "description": "RefundedAmount — сумма возвратов без запланированных",
+                    "type": "number",
+                    "example": 500
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                },
+                "transaction": {
+                    "$ref": "#/definitions/models.Transaction"
+                }
+            }
+        },
+        "models.LoanPayment": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 34084.22
+                },
+                "date": {
+                    "type": "string"
+                },
+                "from_account_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interest": {
+                    "type": "number",
+                    "example": 31250
+                },
+                "interest_transaction_id": {
+                    "description": "InterestTransactionID — расход на проценты; нет, если проценты не начислены",
+                    "type": "integer",
+                    "example": 43
+                },
+                "principal": {
+                    "type": "number",
+                    "example": 2834.22
+                },
+                "transfer_id": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.LoanStatus": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "interest_paid": {
+                    "type": "number",
+                    "example": 31250
+                },
+                "next_payment": {
+                    "type": "number",
+                    "example": 34084.22
+                },
+                "next_payment_date": {
+                    "description": "NextPaymentDate и NextPayment — следующий платеж по графику; нет, если график выполнен",
+                    "type": "string"
+                },
+                "payments_made": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "principal": {
+                    "type": "number",
+                    "example": 3000000
+                },
+                "principal_paid": {
+                    "type": "number",
+                    "example": 2834.22
+                },
+                "remaining_balance": {
+                    "description": "RemainingBalance — остаток основного долга",
+                    "type": "number",
+                    "example": 2997165.78
+                }
+            }
+        },
+        "models.LoanTerms": {
+            "type": "object",
+            "properties": {
+                "first_payment_date": {
+                    "description": "FirstPaymentDate — дата первого платежа; по умолчанию через месяц после создания счета",
+                    "type": "string",
+                    "example": "2025-08-01T00:00:00Z"
+                },
+                "interest_rate": {
+                    "description": "InterestRate — годовая процентная ставка",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "principal": {
+                    "type": "number",
+                    "example": 3000000
+                },
+                "term_months": {
+                    "type": "integer",
+                    "example": 240
+                }
+            }
+        },
+        "models.LoginEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "captcha_token": {
+                    "description": "Токен CAPTCHA-виджета, требуется после нескольких неудачных попыток входа",
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "remember_me": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer",
+                    "example": 1735689600
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "models.MagicLinkRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                }
+            }
+        },
+        "models.MarkClearedRequest": {
+            "type": "object",
+            "properties": {
+                "date_to": {
+                    "type": "string",
+                    "example": "2025-01-31T23:59:59Z"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "models.MarkClearedResponse": {
+            "type": "object",
+            "properties": {
+                "updated": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.NetWorth": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "number",
+                    "example": 30250.5
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "liabilities": {
+                    "type": "number",
+                    "example": 5000
+                },
+                "net_worth": {
+                    "type": "number",
+                    "example": 25250.5
+                }
+            }
+        },
+        "models.NetWorthHistory": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны остатки",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-08-01"
+                },
+                "group_by": {
+                    "description": "GroupBy — длина интервала: day, week (с понедельника), month, quarter или year",
+                    "type": "string",
+                    "example": "month"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NetWorthHistoryPoint"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                }
+            }
+        },
+        "models.NetWorthHistoryPoint": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "number",
+                    "example": 30250.5
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                },
+                "liabilities": {
+                    "type": "number",
+                    "example": 5000
+                },
+                "net_worth": {
+                    "type": "number",
+                    "example": 25250.5
+                },
+                "start": {
+                    "description": "Start — первый день интервала, Date — день снимка в формате YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2025-07-01"
+                }
+            }
+        },
+        "models.PatchCategory": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#4caf50"
+                },
+                "icon": {
+                    "description": "Icon и Color — пустая строка сбрасывает значок или цвет",
+                    "type": "string",
+                    "example": "cart"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Продукты"
+                }
+            }
+        },
+        "models.PatchTransaction": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "AccountID — ID счета; 0 убирает транзакцию со счета",
+                    "type": "integer",
+                    "example": 1
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 1250.5
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Продукты на неделю"
+                },
+                "fx_rate": {
+                    "type": "number",
+                    "example": 98.5
+                },
+                "linked_transaction_id": {
+                    "description": "LinkedTransactionID — ID расхода для возврата; 0 убирает связь",
+                    "type": "integer",
+                    "example": 42
+                },
+                "original_amount": {
+                    "description": "OriginalAmount, OriginalCurrency и FXRate — сумма в валюте покупки и курс; 0 и пустая строка убирают их",
+                    "type": "number",
+                    "example": 25.5
+                },
+                "original_currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "payee": {
+                    "description": "Payee — имя контрагента; пустая строка убирает контрагента",
+                    "type": "string",
+                    "example": "Пятерочка"
+                },
+                "payee_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "planned": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "status": {
+                    "type": "string",
+                    "example": "cleared"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vacation",
+                        "work"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "example": "expense"
+                }
+            }
+        },
+        "models.Payee": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PayeeStats": {
+            "type": "object",
+            "properties": {
+                "first_date": {
+                    "type": "string"
+                },
+                "last_date": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Пятерочка"
+                },
+                "payee_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionTotals"
+                    }
+                },
+                "transaction_count": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.PeriodComparison": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "$ref": "#/definitions/models.ComparedPeriod"
+                },
+                "b": {
+                    "$ref": "#/definitions/models.ComparedPeriod"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryComparison"
+                    }
+                },
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны суммы",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "delta": {
+                    "description": "Delta — разница итогов периода B и периода A",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ComparisonTotals"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PeriodSummary": {
+            "type": "object",
+            "properties": {
+                "by_currency": {
+                    "description": "ByCurrency — итоги в каждой валюте без пересчета",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencySummary"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны итоги",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "expense": {
+                    "description": "Expense — расходы за вычетом возвратов",
+                    "type": "number",
+                    "example": 32000.5
+                },
+                "from": {
+                    "description": "From и To — первый и последний дни периода в формате YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2025-07-01"
+                },
+                "income": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "net": {
+                    "description": "Net — доходы минус расходы",
+                    "type": "number",
+                    "example": 17999.5
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                }
+            }
+        },
+        "models.Profile": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "email_verified": {
+                    "description": "EmailVerified — email подтвержден переходом по ссылке из письма",
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "language": {
+                    "description": "Language — язык писем с отчетами и уведомлений о бюджетах",
+                    "type": "string",
+                    "example": "ru"
+                },
+                "month_start": {
+                    "description": "MonthStart — день, с которого начинаются месяцы, кварталы и годы в отчетах",
+                    "type": "integer",
+                    "example": 25
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "models.ReassignCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "date_from": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "date_to": {
+                    "type": "string",
+                    "example": "2025-01-31T23:59:59Z"
+                },
+                "target_category_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.ReassignCategoryResponse": {
+            "type": "object",
+            "properties": {
+                "moved": {
+                    "type": "integer",
+                    "example": 25
+                }
+            }
+        },
+        "models.Receipt": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "fd": {
+                    "type": "string",
+                    "example": "12345"
+                },
+                "fn": {
+                    "description": "FN, FD и FP — номер фискального накопителя, номер и признак фискального документа",
+                    "type": "string",
+                    "example": "9999078900012345"
+                },
+                "fp": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceiptItem"
+                    }
+                },
+                "seller": {
+                    "type": "string",
+                    "example": "ООО \"Ромашка\""
+                },
+                "seller_inn": {
+                    "type": "string",
+                    "example": "7700000000"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 1234.5
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.ReceiptItem": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Молоко 3,2% 1 л"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 89.99
+                },
+                "quantity": {
+                    "type": "number",
+                    "example": 2
+                },
+                "sum": {
+                    "type": "number",
+                    "example": 179.98
+                }
+            }
+        },
+        "models.ReceiptTransaction": {
+            "type": "object",
+            "properties": {
+                "receipt": {
+                    "$ref": "#/definitions/models.Receipt"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/models.Transaction"
+                }
+            }
+        },
+        "models.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "models.ReportEmailPreferences": {
+            "type": "object",
+            "properties": {
+                "monthly": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "weekly": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ReportQuery": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "boolean"
+                },
+                "filters": {
+                    "$ref": "#/definitions/models.ReportQueryFilters"
+                },
+                "group_by": {
+                    "description": "GroupBy — измерения: category, payee, account, tag, currency, type, day, week, month, quarter или year",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "month",
+                        "category"
+                    ]
+                },
+                "limit": {
+                    "description": "Limit — наибольшее число строк, по умолчанию 1000, не больше 10000",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0,
+                    "example": 100
+                },
+                "metrics": {
+                    "description": "Metrics — показатели: count, income, expense, net, average, min или max",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "expense",
+                        "count"
+                    ]
+                },
+                "order_by": {
+                    "description": "OrderBy — измерение или показатель из запроса для сортировки; по умолчанию строки упорядочены по измерениям",
+                    "type": "string",
+                    "example": "expense"
+                }
+            }
+        },
+        "models.ReportQueryFilters": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1
+                    ]
+                },
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        5
+                    ]
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "date_from": {
+                    "description": "DateFrom и DateTo — первый и последний дни периода включительно",
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "date_to": {
+                    "type": "string",
+                    "example": "2025-06-30T00:00:00Z"
+                },
+                "include_planned": {
+                    "description": "IncludePlanned — учитывать запланированные транзакции",
+                    "type": "boolean"
+                },
+                "max_amount": {
+                    "type": "number",
+                    "example": 5000
+                },
+                "min_amount": {
+                    "type": "number",
+                    "example": 100
+                },
+                "payee_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2
+                    ]
+                },
+                "tags": {
+                    "description": "Tags — транзакция подходит, если у нее есть хотя бы один из тегов",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vacation"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "income",
+                        "expense"
+                    ],
+                    "example": "expense"
+                }
+            }
+        },
+        "models.ReportQueryResult": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "month",
+                        "category",
+                        "currency",
+                        "expense"
+                    ]
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "models.ResolveDuplicateRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action — confirm (это дубликат, транзакция перемещается в корзину) или dismiss (не дубликат, отметка снимается)",
+                    "type": "string",
+                    "enum": [
+                        "confirm",
+                        "dismiss"
+                    ],
+                    "example": "dismiss"
+                }
+            }
+        },
+        "models.SankeyLink": {
+            "type": "object",
+            "properties": {
+                "source": {
+                    "type": "string",
+                    "example": "budget"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "expense:3"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 24600
+                }
+            }
+        },
+        "models.SankeyNode": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID — идентификатор узла: income:\u003cID категории\u003e, expense:\u003cID категории\u003e, budget, savings или deficit.\nТранзакции без категории собраны в узлы с ID категории 0",
+                    "type": "string",
+                    "example": "expense:3"
+                },
+                "kind": {
+                    "description": "Kind — вид узла: income, budget, expense, savings (остаток доходов) или deficit (расходы сверх доходов)",
+                    "type": "string",
+                    "example": "expense"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Продукты"
+                }
+            }
+        },
+        "models.SavingsRate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны суммы",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "expense": {
+                    "type": "number",
+                    "example": 420000
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-08-01"
+                },
+                "group_by": {
+                    "description": "GroupBy — длина интервала: day, week (с понедельника), month, quarter или year",
+                    "type": "string",
+                    "example": "month"
+                },
+                "income": {
+                    "type": "number",
+                    "example": 600000
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SavingsRatePoint"
+                    }
+                },
+                "rate": {
+                    "description": "Rate — (доходы − расходы) / доходы в процентах; отсутствует, если доходов не было",
+                    "type": "number",
+                    "example": 30
+                },
+                "savings": {
+                    "description": "Savings — доходы минус расходы",
+                    "type": "number",
+                    "example": 180000
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                }
+            }
+        },
+        "models.SavingsRatePoint": {
+            "type": "object",
+            "properties": {
+                "expense": {
+                    "type": "number",
+                    "example": 32000.5
+                },
+                "income": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 36
+                },
+                "savings": {
+                    "type": "number",
+                    "example": 17999.5
+                },
+                "start": {
+                    "description": "Start — первый день интервала в формате YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2025-07-01"
+                }
+            }
+        },
+        "models.ScanReceiptRequest": {
+            "type": "object",
+            "required": [
+                "qr"
+            ],
+            "properties": {
+                "category_id": {
+                    "description": "CategoryID — категория транзакции; если не указана, подбирается по ключевым словам категорий",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "description": {
+                    "description": "Description — описание транзакции; продавец из чека становится контрагентом",
+                    "type": "string",
+                    "example": "Продукты на неделю"
+                },
+                "qr": {
+                    "description": "QR — строка из QR-кода чека: t=20250115T1230\u0026s=1234.50\u0026fn=...\u0026i=...\u0026fp=...\u0026n=1",
+                    "type": "string",
+                    "example": "t=20250115T1230\u0026s=1234.50\u0026fn=9999078900012345\u0026i=12345\u0026fp=1234567890\u0026n=1"
+                }
+            }
+        },
+        "models.SearchTransactionsResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSearchResult"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.SetBaseCurrencyRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                }
+            }
+        },
+        "models.SetLanguageRequest": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "description": "Language — код языка: en или ru",
+                    "type": "string",
+                    "example": "ru"
+                }
+            }
+        },
+        "models.SetMonthStartRequest": {
+            "type": "object",
+            "properties": {
+                "month_start": {
+                    "description": "MonthStart — день месяца от 1 до 28",
+                    "type": "integer",
+                    "maximum": 28,
+                    "minimum": 1,
+                    "example": 25
+                }
+            }
+        },
+        "models.ShareAccount": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "description": "Role — viewer или editor",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor"
+                    ],
+                    "example": "editor"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jane_doe"
+                }
+            }
+        },
+        "models.SpendingStats": {
+            "type": "object",
+            "properties": {
+                "currencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencySpendingStats"
+                    }
+                },
+                "days": {
+                    "description": "Days — число дней в периоде",
+                    "type": "integer",
+                    "example": 31
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-07-01"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                }
+            }
+        },
+        "models.SpendingTrend": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "description": "Average — средние расходы за предыдущие периоды; периоды без расходов входят в среднее нулем",
+                    "type": "number",
+                    "example": 20000
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "change": {
+                    "description": "Change — изменение текущих расходов относительно среднего в процентах; отсутствует при нулевом среднем",
+                    "type": "number",
+                    "example": 23
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "current": {
+                    "description": "Current — расходы за текущий период за вычетом возвратов",
+                    "type": "number",
+                    "example": 24600
+                },
+                "direction": {
+                    "description": "Direction — up, down, flat или new, если в предыдущих периодах расходов не было",
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "models.SpendingTrends": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                },
+                "period": {
+                    "description": "Period — вид периода: week, month, quarter или year",
+                    "type": "string",
+                    "example": "month"
+                },
+                "start": {
+                    "description": "Start и End — первый и последний дни текущего периода",
+                    "type": "string",
+                    "example": "2025-07-01"
+                },
+                "trends": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SpendingTrend"
+                    }
+                },
+                "window": {
+                    "description": "Window — число предыдущих периодов, по которым считается среднее",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.Subscription": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount — сумма последнего списания",
+                    "type": "number",
+                    "example": 299
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "charges": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "first_charge": {
+                    "description": "FirstCharge, LastCharge и NextCharge — даты первого, последнего и следующего ожидаемого списания в формате YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2024-12-05"
+                },
+                "frequency": {
+                    "description": "Frequency — периодичность: weekly, monthly, quarterly или yearly",
+                    "type": "string",
+                    "example": "monthly"
+                },
+                "last_charge": {
+                    "type": "string",
+                    "example": "2025-07-05"
+                },
+                "last_transaction_id": {
+                    "description": "LastTransactionID — ID транзакции последнего списания; по нему подписку можно запланировать",
+                    "type": "integer",
+                    "example": 321
+                },
+                "monthly_cost": {
+                    "description": "MonthlyCost — стоимость в месяц в валюте списаний",
+                    "type": "number",
+                    "example": 299
+                },
+                "next_charge": {
+                    "type": "string",
+                    "example": "2025-08-05"
+                },
+                "payee": {
+                    "type": "string",
+                    "example": "Кинопоиск"
+                },
+                "payee_id": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.Subscriptions": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которой посчитана MonthlyTotal",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "monthly_total": {
+                    "description": "MonthlyTotal — суммарная стоимость подписок в месяц",
+                    "type": "number",
+                    "example": 1847
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Subscription"
+                    }
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TimeSeries": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны суммы интервалов",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-08-01"
+                },
+                "group_by": {
+                    "description": "GroupBy — длина интервала: day, week (с понедельника), month, quarter или year",
+                    "type": "string",
+                    "example": "month"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimeSeriesPoint"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-31"
+                }
+            }
+        },
+        "models.TimeSeriesPoint": {
+            "type": "object",
+            "properties": {
+                "by_currency": {
+                    "description": "ByCurrency — суммы интервала в каждой валюте без пересчета",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionTotals"
+                    }
+                },
+                "expense": {
+                    "type": "number",
+                    "example": 32000.5
+                },
+                "income": {
+                    "type": "number",
+                    "example": 50000
+                },
+                "net": {
+                    "type": "number",
+                    "example": 17999.5
+                },
+                "start": {
+                    "description": "Start — первый день интервала в формате YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2025-07-01"
+                }
             }
-        }
-    },
-    "definitions": {
-        "models.Category": {
+        },
+        "models.TopPayee": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "average": {
+                    "description": "Average — средний расход на одну покупку",
+                    "type": "number",
+                    "example": 1270.88
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Пятерочка"
                 },
-                "user_id": {
-                    "type": "integer"
+                "payee_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "spent": {
+                    "description": "Spent — расходы за вычетом возвратов в базовой валюте пользователя",
+                    "type": "number",
+                    "example": 15250.5
+                },
+                "totals": {
+                    "description": "Totals — расходы в каждой валюте без пересчета",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionTotals"
+                    }
+                },
+                "visits": {
+                    "description": "Visits — число расходных транзакций",
+                    "type": "integer",
+                    "example": 12
                 }
             }
         },
-        "models.CreateCategory": {
+        "models.TopPayees": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string"
+                "currency": {
+                    "description": "Currency — базовая валюта пользователя, в которую пересчитаны расходы",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-07-01"
+                },
+                "payees": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TopPayee"
+                    }
+                },
+                "sort": {
+                    "description": "Sort — порядок: spent (по расходам) или visits (по числу покупок)",
+                    "type": "string",
+                    "example": "spent"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-31"
                 }
             }
         },
-        "models.CreateTransaction": {
+        "models.Transaction": {
             "type": "object",
             "properties": {
+                "account_id": {
+                    "description": "AccountID — счет транзакции; транзакции без счета не влияют на остатки",
+                    "type": "integer"
+                },
+                "adjustment": {
+                    "description": "Adjustment — корректировка остатка счета; не учитывается в доходах и расходах и не изменяется",
+                    "type": "boolean"
+                },
                 "amount": {
-                    "type": "number"
+                    "type": "number",
+                    "example": 1250.5
                 },
                 "category_id": {
                     "type": "integer"
                 },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt — время перемещения в корзину; только для транзакций из корзины",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "description": "DuplicateOf — ID похожих транзакций; заполняется только в ответе на создание",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "flagged": {
+                    "description": "Flagged — пользователь отметил транзакцию, чтобы вернуться к ней позже",
+                    "type": "boolean"
+                },
+                "fx_rate": {
+                    "type": "number",
+                    "example": 98.5
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "linked_transaction_id": {
+                    "description": "LinkedTransactionID — ID расхода, к которому привязан этот доход как возврат",
+                    "type": "integer"
+                },
+                "original_amount": {
+                    "description": "OriginalAmount, OriginalCurrency и FXRate — сумма и валюта покупки за границей и курс,\nпо которому она пересчитана в Amount: Amount = OriginalAmount * FXRate",
+                    "type": "number",
+                    "example": 25.5
+                },
+                "original_currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "payee": {
+                    "type": "string"
+                },
+                "payee_id": {
+                    "description": "PayeeID и Payee — контрагент; при создании можно передать только имя, контрагент будет создан",
+                    "type": "integer"
+                },
+                "planned": {
+                    "description": "Planned — запланированная транзакция с будущей датой; становится обычной в день даты",
+                    "type": "boolean"
+                },
+                "possible_duplicate": {
+                    "description": "PossibleDuplicate — при создании найдены похожие транзакции; снимается подтверждением или отклонением",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status — статус сверки с выпиской: pending, cleared или reconciled",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "transfer_id": {
+                    "description": "TransferID — перевод между счетами, частью которого является транзакция; такие транзакции\nне учитываются в доходах и расходах и изменяются только вместе с переводом",
+                    "type": "integer"
+                },
                 "type": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateUser": {
+        "models.TransactionSearchResult": {
             "type": "object",
             "properties": {
-                "password": {
+                "account_id": {
+                    "description": "AccountID — счет транзакции; транзакции без счета не влияют на остатки",
+                    "type": "integer"
+                },
+                "adjustment": {
+                    "description": "Adjustment — корректировка остатка счета; не учитывается в доходах и расходах и не изменяется",
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 1250.5
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
                     "type": "string"
                 },
-                "username": {
+                "date": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt — время перемещения в корзину; только для транзакций из корзины",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "description": "DuplicateOf — ID похожих транзакций; заполняется только в ответе на создание",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "flagged": {
+                    "description": "Flagged — пользователь отметил транзакцию, чтобы вернуться к ней позже",
+                    "type": "boolean"
+                },
+                "fx_rate": {
+                    "type": "number",
+                    "example": 98.5
+                },
+                "highlight": {
+                    "description": "Highlight — описание с совпадениями, выделенными тегом \u003cmark\u003e",
+                    "type": "string",
+                    "example": "Продукты на \u003cmark\u003eнеделю\u003c/mark\u003e"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "linked_transaction_id": {
+                    "description": "LinkedTransactionID — ID расхода, к которому привязан этот доход как возврат",
+                    "type": "integer"
+                },
+                "original_amount": {
+                    "description": "OriginalAmount, OriginalCurrency и FXRate — сумма и валюта покупки за границей и курс,\nпо которому она пересчитана в Amount: Amount = OriginalAmount * FXRate",
+                    "type": "number",
+                    "example": 25.5
+                },
+                "original_currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "payee": {
+                    "type": "string"
+                },
+                "payee_id": {
+                    "description": "PayeeID и Payee — контрагент; при создании можно передать только имя, контрагент будет создан",
+                    "type": "integer"
+                },
+                "planned": {
+                    "description": "Planned — запланированная транзакция с будущей датой; становится обычной в день даты",
+                    "type": "boolean"
+                },
+                "possible_duplicate": {
+                    "description": "PossibleDuplicate — при создании найдены похожие транзакции; снимается подтверждением или отклонением",
+                    "type": "boolean"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.42
+                },
+                "status": {
+                    "description": "Status — статус сверки с выпиской: pending, cleared или reconciled",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "transfer_id": {
+                    "description": "TransferID — перевод между счетами, частью которого является транзакция; такие транзакции\nне учитываются в доходах и расходах и изменяются только вместе с переводом",
+                    "type": "integer"
+                },
+                "type": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.TransactionTotals": {
             "type": "object",
             "properties": {
-                "error": {
+                "currency": {
                     "type": "string",
-                    "example": "error"
+                    "example": "RUB"
+                },
+                "expense": {
+                    "type": "number",
+                    "example": 32000.5
+                },
+                "income": {
+                    "type": "number",
+                    "example": 50000
                 }
             }
         },
-        "models.GetTransactionsResponse": {
+        "models.TransactionVersion": {
             "type": "object",
             "properties": {
-                "total": {
-                    "type": "integer",
-                    "example": 100
+                "changed_at": {
+                    "type": "string"
                 },
-                "transactions": {
+                "changed_fields": {
+                    "description": "ChangedFields — поля, изменившиеся относительно предыдущей версии; у первой версии пусто",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Transaction"
-                    }
+                        "type": "string"
+                    },
+                    "example": [
+                        "amount",
+                        "description"
+                    ]
+                },
+                "transaction": {
+                    "$ref": "#/definitions/models.Transaction"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "models.LoginResponse": {
+        "models.Transfer": {
             "type": "object",
             "properties": {
-                "token": {
+                "amount": {
+                    "description": "Amount и Currency — сумма, списанная со счета-источника, в его валюте",
+                    "type": "number",
+                    "example": 1000
+                },
+                "currency": {
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
-                }
-            }
-        },
-        "models.RegisterResponse": {
-            "type": "object",
-            "properties": {
-                "id": {
+                    "example": "RUB"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Пополнение карты"
+                },
+                "from_account_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "username": {
+                "from_transaction_id": {
+                    "description": "FromTransactionID и ToTransactionID — транзакции перевода на счетах",
+                    "type": "integer",
+                    "example": 41
+                },
+                "fx_rate": {
+                    "description": "FXRate — курс перевода между валютами: ToAmount = Amount * FXRate; только для счетов в разных валютах",
+                    "type": "number",
+                    "example": 0.0105
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "to_account_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "to_amount": {
+                    "description": "ToAmount и ToCurrency — сумма, зачисленная на счет-получатель, в его валюте",
+                    "type": "number",
+                    "example": 10.5
+                },
+                "to_currency": {
                     "type": "string",
-                    "example": "john_doe"
+                    "example": "USD"
+                },
+                "to_transaction_id": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
-        "models.Transaction": {
+        "models.UpdateAccount": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
+                "credit_limit": {
+                    "description": "Условия кредитной карты, как в CreateAccount",
+                    "type": "number",
+                    "example": 100000
                 },
-                "category_id": {
-                    "type": "integer"
+                "initial_balance": {
+                    "type": "number",
+                    "example": 1000
                 },
-                "date": {
-                    "type": "string"
+                "loan": {
+                    "$ref": "#/definitions/models.LoanTerms"
                 },
-                "id": {
-                    "type": "integer"
+                "name": {
+                    "type": "string",
+                    "example": "Наличные"
                 },
-                "type": {
-                    "type": "string"
+                "payment_due_day": {
+                    "type": "integer",
+                    "example": 15
                 },
-                "user_id": {
-                    "type": "integer"
+                "statement_day": {
+                    "type": "integer",
+                    "example": 25
+                }
+            }
+        },
+        "models.UpdateBudget": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "number",
+                    "example": 25000
+                },
+                "rollover": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
         "models.UpdateCategoryResponse": {
             "type": "object",
             "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#4caf50"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "cart"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -764,6 +11556,104 @@ const docTemplate = `{
                     "example": 1
                 }
             }
+        },
+        "models.UpdateGoal": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "deadline": {
+                    "type": "string",
+                    "example": "2026-06-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Отпуск"
+                },
+                "target": {
+                    "type": "number",
+                    "example": 150000
+                }
+            }
+        },
+        "models.UpdateHolding": {
+            "type": "object",
+            "properties": {
+                "cost_basis": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 40000
+                },
+                "quantity": {
+                    "type": "number",
+                    "maximum": 1000000000000,
+                    "example": 150
+                }
+            }
+        },
+        "models.ValidationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "validation failed"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "request_id": {
+                    "description": "RequestID — идентификатор запроса из заголовка X-Request-ID для обращения в поддержку",
+                    "type": "string",
+                    "example": "4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"
+                }
+            }
+        },
+        "models.ValuationPoint": {
+            "type": "object",
+            "properties": {
+                "cost_basis": {
+                    "type": "number",
+                    "example": 25000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "market_value": {
+                    "type": "number",
+                    "example": 31025
+                }
+            }
+        },
+        "models.WeekdaySpend": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "description": "Average — средние расходы за один такой день периода",
+                    "type": "number",
+                    "example": 1680
+                },
+                "total": {
+                    "type": "number",
+                    "example": 8400
+                },
+                "weekday": {
+                    "description": "Weekday — номер дня недели по ISO 8601: 1 — понедельник, 7 — воскресенье",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -779,7 +11669,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "",
 	Host:             "",
-	BasePath:         "",
+	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "",
 	Description:      "",
@@ -0,0 +1,181 @@
+// Package events рассылает события об изменении данных пользователя подключенным клиентам,
+// чтобы несколько устройств оставались синхронными без опроса API.
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Типы событий.
+const (
+	TransactionCreated = "transaction.created"
+	BudgetThreshold    = "budget.threshold"
+	ImportFinished     = "import.finished"
+)
+
+// subscriptionBuffer — число событий, которые подписка накапливает, пока клиент их не прочитал.
+const subscriptionBuffer = 64
+
+// historySize — число последних событий всех пользователей, которые Hub хранит
+// для повторной отправки переподключившимся клиентам.
+const historySize = 1024
+
+// Event — событие пользователя.
+type Event struct {
+	// ID возрастает в пределах эпохи — запуска процесса, который опубликовал событие
+	ID    int64     `json:"id" example:"42"`
+	Epoch string    `json:"epoch" example:"d2x1q8v0c3k"`
+	Type  string    `json:"type" example:"transaction.created"`
+	Time  time.Time `json:"time" example:"2025-07-01T12:00:00Z"`
+	Data  any       `json:"data" swaggertype:"object"`
+
+	userID int
+}
+
+// Hub хранит подписки пользователей и рассылает им события. События хранятся только
+// в памяти и доставляются клиентам, подключенным к этому же процессу.
+type Hub struct {
+	mu sync.Mutex
+	// epoch отличает события этого процесса от событий прежних запусков и других экземпляров,
+	// у которых ID начинаются с того же числа
+	epoch  string
+	nextID int64
+	subs   map[int]map[*Subscription]struct{}
+	// history — последние события в порядке ID
+	history []Event
+}
+
+// NewHub создает пустой Hub.
+func NewHub() *Hub {
+	return &Hub{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:  make(map[int]map[*Subscription]struct{}),
+	}
+}
+
+// StreamID возвращает ID события для клиента: эпоху и номер события через дефис.
+func (e Event) StreamID() string {
+	return e.Epoch + "-" + strconv.FormatInt(e.ID, 10)
+}
+
+// ParseStreamID разбирает ID, возвращенный StreamID. ID без эпохи выдавались до ее появления
+// и возвращаются с пустой эпохой.
+func ParseStreamID(s string) (epoch string, id int64, err error) {
+	epoch, number, ok := strings.Cut(s, "-")
+	if !ok {
+		epoch, number = "", s
+	}
+	id, err = strconv.ParseInt(number, 10, 64)
+	if err != nil || id < 0 {
+		return "", 0, fmt.Errorf("invalid event id %q", s)
+	}
+	return epoch, id, nil
+}
+
+// Subscription — подписка одного клиента на события пользователя.
+type Subscription struct {
+	// C получает события пользователя; закрывается при Close или если клиент
+	// не успевает читать события
+	C <-chan Event
+
+	hub    *Hub
+	userID int
+	ch     chan Event
+}
+
+// Subscribe подписывает клиента на события пользователя userID.
+func (h *Hub) Subscribe(userID int) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.subscribe(userID)
+}
+
+// SubscribeSince подписывает клиента на события пользователя userID и возвращает
+// события пользователя с ID больше lastID, которые клиент пропустил. Возвращает false,
+// если часть пропущенных событий уже не хранится или lastID выдан в другой эпохе,
+// например до перезапуска сервиса: тогда возвращается вся история пользователя,
+// а клиенту нужно заново загрузить данные.
+func (h *Hub) SubscribeSince(userID int, epoch string, lastID int64) (*Subscription, []Event, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	complete := epoch == h.epoch && lastID <= h.nextID
+	if !complete {
+		lastID = 0
+	}
+	if len(h.history) > 0 && h.history[0].ID > lastID+1 {
+		complete = false
+	}
+	missed := []Event{}
+	for _, event := range h.history {
+		if event.userID == userID && event.ID > lastID {
+			missed = append(missed, event)
+		}
+	}
+	return h.subscribe(userID), missed, complete
+}
+
+// subscribe добавляет подписку; вызывается под h.mu.
+func (h *Hub) subscribe(userID int) *Subscription {
+	ch := make(chan Event, subscriptionBuffer)
+	sub := &Subscription{C: ch, hub: h, userID: userID, ch: ch}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	return sub
+}
+
+// Close отменяет подписку. Повторный вызов ничего не делает.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// remove удаляет подписку и закрывает ее канал; вызывается под h.mu.
+func (h *Hub) remove(s *Subscription) {
+	subs := h.subs[s.userID]
+	if _, ok := subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(h.subs, s.userID)
+	}
+	close(s.ch)
+}
+
+// Publish отправляет событие всем подпискам пользователя userID и возвращает его.
+// Подписка, буфер которой заполнен, закрывается, чтобы медленный клиент не задерживал
+// остальных; клиент должен переподключиться.
+func (h *Hub) Publish(userID int, eventType string, data any) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	event := Event{ID: h.nextID, Epoch: h.epoch, Type: eventType, Time: time.Now().UTC(), Data: data, userID: userID}
+	if len(h.history) == historySize {
+		h.history = append(h.history[:0], h.history[1:]...)
+	}
+	h.history = append(h.history, event)
+	for sub := range h.subs[userID] {
+		select {
+		case sub.ch <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+	return event
+}
+
+// Subscribers возвращает число подписок пользователя userID.
+func (h *Hub) Subscribers(userID int) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[userID])
+}
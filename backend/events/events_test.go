@@ -0,0 +1,106 @@
+package events
+
+import "testing"
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	phone, laptop, other := hub.Subscribe(1), hub.Subscribe(1), hub.Subscribe(2)
+	if n := hub.Subscribers(1); n != 2 {
+		t.Fatalf("Expected 2 subscribers, got %d", n)
+	}
+
+	// Событие получают все устройства пользователя, но не другие пользователи
+	published := hub.Publish(1, TransactionCreated, map[string]int{"id": 5})
+	for _, sub := range []*Subscription{phone, laptop} {
+		if event := <-sub.C; event.ID != published.ID || event.Type != TransactionCreated || event.Time.IsZero() {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	}
+	select {
+	case event := <-other.C:
+		t.Errorf("Expected no event for other user, got %+v", event)
+	default:
+	}
+	if next := hub.Publish(2, ImportFinished, nil); next.ID != published.ID+1 || (<-other.C).ID != next.ID {
+		t.Errorf("Expected increasing IDs, got %d after %d", next.ID, published.ID)
+	}
+
+	// Подписка, которая не успевает читать события, закрывается
+	for i := 0; i <= subscriptionBuffer; i++ {
+		hub.Publish(1, BudgetThreshold, i)
+	}
+	received := 0
+	for range phone.C {
+		received++
+	}
+	if received != subscriptionBuffer || hub.Subscribers(1) != 0 {
+		t.Errorf("Expected slow subscription to be closed after %d events, got %d events and %d subscribers",
+			subscriptionBuffer, received, hub.Subscribers(1))
+	}
+
+	other.Close()
+	other.Close()
+	if _, ok := <-other.C; ok || hub.Subscribers(2) != 0 {
+		t.Error("Expected closed subscription")
+	}
+}
+
+func TestHubSubscribeSince(t *testing.T) {
+	hub := NewHub()
+	first := hub.Publish(1, TransactionCreated, 1)
+	hub.Publish(2, TransactionCreated, 2)
+	third := hub.Publish(1, ImportFinished, 3)
+
+	// Пропущены только события пользователя после first
+	sub, missed, complete := hub.SubscribeSince(1, first.Epoch, first.ID)
+	if !complete || len(missed) != 1 || missed[0].ID != third.ID {
+		t.Errorf("Expected third event to be missed, got %+v (complete=%v)", missed, complete)
+	}
+	if next := hub.Publish(1, TransactionCreated, 4); (<-sub.C).ID != next.ID {
+		t.Error("Expected subscription to receive new events")
+	}
+	sub.Close()
+
+	// ID из будущего выдан до перезапуска: отправляется вся история пользователя
+	sub, missed, complete = hub.SubscribeSince(1, first.Epoch, 100)
+	sub.Close()
+	if complete || len(missed) != 3 {
+		t.Errorf("Expected incomplete history of 3 events, got %d (complete=%v)", len(missed), complete)
+	}
+	// Номер из другой эпохи не сравнивается с номерами этой, даже если он меньше
+	sub, missed, complete = NewHub().SubscribeSince(1, first.Epoch, 0)
+	sub.Close()
+	if complete || len(missed) != 0 {
+		t.Errorf("Expected incomplete empty history in another hub, got %d (complete=%v)", len(missed), complete)
+	}
+	sub, missed, complete = hub.SubscribeSince(1, "", first.ID)
+	sub.Close()
+	if complete || len(missed) != 3 {
+		t.Errorf("Expected incomplete history of 3 events for ID without epoch, got %d (complete=%v)", len(missed), complete)
+	}
+
+	// Вытесненные из истории события не восстановить
+	for i := 0; i < historySize; i++ {
+		hub.Publish(2, TransactionCreated, i)
+	}
+	sub, missed, complete = hub.SubscribeSince(1, third.Epoch, third.ID)
+	sub.Close()
+	if complete || len(missed) != 0 {
+		t.Errorf("Expected incomplete empty history, got %d (complete=%v)", len(missed), complete)
+	}
+}
+
+func TestParseStreamID(t *testing.T) {
+	event := NewHub().Publish(1, TransactionCreated, nil)
+	if epoch, id, err := ParseStreamID(event.StreamID()); err != nil || epoch != event.Epoch || id != event.ID {
+		t.Errorf("Expected %s/%d, got %s/%d, %v", event.Epoch, event.ID, epoch, id, err)
+	}
+	if epoch, id, err := ParseStreamID("42"); err != nil || epoch != "" || id != 42 {
+		t.Errorf("Expected ID without epoch, got %q/%d, %v", epoch, id, err)
+	}
+	for _, invalid := range []string{"abc", "x-abc", "x--1", ""} {
+		if _, _, err := ParseStreamID(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
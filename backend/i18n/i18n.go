@@ -0,0 +1,157 @@
+// Package i18n переводит сообщения API и тексты уведомлений на язык пользователя.
+// Каталоги сообщений встроены в бинарный файл: locales/<язык>.json.
+//
+// Ключи каталога — идентификаторы текстов уведомлений (например, "email.magic_link.subject")
+// и английские тексты ошибок. Ошибки с подставленными значениями задаются шаблоном
+// с глаголами %s, %d или %v: при переводе значения переносятся в перевод по порядку.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English = "en"
+	Russian = "ru"
+	// Default — язык, на котором написаны исходные сообщения
+	Default = English
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// pattern — шаблон каталога для сообщений с подставленными значениями.
+type pattern struct {
+	re          *regexp.Regexp
+	translation string
+}
+
+type catalog struct {
+	messages map[string]string
+	patterns []pattern
+}
+
+var (
+	catalogs = map[string]*catalog{}
+	verb     = regexp.MustCompile(`%[sdv]`)
+)
+
+func init() {
+	for _, lang := range []string{English, Russian} {
+		data, err := locales.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s catalog: %v", lang, err))
+		}
+		c := &catalog{}
+		if err := json.Unmarshal(data, &c.messages); err != nil {
+			panic(fmt.Sprintf("i18n: failed to parse %s catalog: %v", lang, err))
+		}
+		for key, translation := range c.messages {
+			if !verb.MatchString(key) {
+				continue
+			}
+			parts := verb.Split(key, -1)
+			for i := range parts {
+				parts[i] = regexp.QuoteMeta(parts[i])
+			}
+			c.patterns = append(c.patterns, pattern{
+				re:          regexp.MustCompile("^" + strings.Join(parts, "(.+?)") + "$"),
+				translation: verb.ReplaceAllString(translation, "%s"),
+			})
+		}
+		// Более длинные шаблоны точнее, поэтому проверяются первыми
+		sort.Slice(c.patterns, func(i, j int) bool {
+			return len(c.patterns[i].re.String()) > len(c.patterns[j].re.String())
+		})
+		catalogs[lang] = c
+	}
+}
+
+// Supported сообщает, есть ли каталог для языка lang.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Match выбирает поддерживаемый язык по заголовку Accept-Language с учетом весов q.
+// Региональные варианты сводятся к основному языку (ru-RU — ru). Если подходящего языка нет, возвращает Default.
+func Match(acceptLanguage string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang == "*" {
+			lang = Default
+		}
+		if q > bestQ && Supported(lang) {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// T возвращает текст с идентификатором key на языке lang, подставляя в него args.
+// Если текста нет в каталоге языка, используется английский, а если нет и его — сам key.
+func T(lang, key string, args ...any) string {
+	format, ok := lookup(lang, key)
+	if !ok {
+		format, ok = lookup(Default, key)
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func lookup(lang, key string) (string, bool) {
+	c, ok := catalogs[lang]
+	if !ok {
+		return "", false
+	}
+	format, ok := c.messages[key]
+	return format, ok
+}
+
+// Translate переводит готовое английское сообщение на язык lang. Сообщения без перевода возвращаются как есть.
+func Translate(lang, message string) string {
+	if lang == Default || message == "" {
+		return message
+	}
+	c, ok := catalogs[lang]
+	if !ok {
+		return message
+	}
+	if translation, ok := c.messages[message]; ok {
+		return translation
+	}
+	for _, p := range c.patterns {
+		match := p.re.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		args := make([]any, len(match)-1)
+		for i, value := range match[1:] {
+			// Подставленные значения сами могут быть сообщениями, например ошибкой поля
+			args[i] = Translate(lang, value)
+		}
+		return fmt.Sprintf(p.translation, args...)
+	}
+	return message
+}
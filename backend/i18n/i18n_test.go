@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMatch тестирует выбор языка по заголовку Accept-Language.
+func TestMatch(t *testing.T) {
+	tests := map[string]string{
+		"":                           English,
+		"ru":                         Russian,
+		"ru-RU,ru;q=0.9,en-US;q=0.8": Russian,
+		"en-US,en;q=0.9,ru;q=0.8":    English,
+		"de-DE,de;q=0.9,ru;q=0.5":    Russian,
+		"de, fr":                     English,
+		"*":                          English,
+		"en;q=0.1, RU;q=0.7":         Russian,
+		"ru;q=abc, en;q=0.5":         English,
+		"ru;q=0":                     English,
+	}
+	for header, expected := range tests {
+		if got := Match(header); got != expected {
+			t.Errorf("Match(%q) = %q, expected %q", header, got, expected)
+		}
+	}
+}
+
+// TestT тестирует тексты уведомлений и возврат к английскому.
+func TestT(t *testing.T) {
+	if got := T(Russian, "email.budget_alert.subject_many", 3); got != "Превышено бюджетов: 3" {
+		t.Errorf("Unexpected russian text %q", got)
+	}
+	if got := T(English, "email.budget_alert.subject_many", 3); got != "3 budgets exceeded" {
+		t.Errorf("Unexpected english text %q", got)
+	}
+	if got := T("de", "email.magic_link.subject"); got != "Your sign-in link" {
+		t.Errorf("Expected english fallback, got %q", got)
+	}
+	if got := T(Russian, "unknown.key"); got != "unknown.key" {
+		t.Errorf("Expected key fallback, got %q", got)
+	}
+
+	// Каждый текст уведомления переведен и принимает те же значения
+	for key, format := range catalogs[English].messages {
+		translation, ok := catalogs[Russian].messages[key]
+		if !ok {
+			t.Errorf("Missing russian text %q", key)
+			continue
+		}
+		if strings.Join(verb.FindAllString(format, -1), "") != strings.Join(verb.FindAllString(translation, -1), "") {
+			t.Errorf("Russian text %q has different arguments", key)
+		}
+	}
+}
+
+// TestTranslate тестирует перевод готовых сообщений об ошибках.
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		lang, message, expected string
+	}{
+		{Russian, "category not found", "категория не найдена"},
+		{Russian, "amount must be positive", "поле amount должно быть положительным"},
+		{Russian, "tags[1] must be at most 50 characters", "поле tags[1] должно быть не длиннее 50 символов"},
+		{Russian, "description must be at most 255 characters", "описание должно быть не длиннее 255 символов"},
+		{Russian, "type must be 'income' or 'expense'", "type должен быть 'income' или 'expense'"},
+		{Russian, "status must be 'pending', 'cleared' or 'reconciled'", "поле status должно быть 'pending', 'cleared' или 'reconciled'"},
+		{Russian, "transaction currency USD does not match account currency RUB", "валюта транзакции USD не совпадает с валютой счета RUB"},
+		{Russian, "failed to convert totals: account not found", "не удалось пересчитать итоги: счет не найден"},
+		{Russian, "something unexpected", "something unexpected"},
+		{English, "category not found", "category not found"},
+		{"de", "category not found", "category not found"},
+	}
+	for _, tt := range tests {
+		if got := Translate(tt.lang, tt.message); got != tt.expected {
+			t.Errorf("Translate(%q, %q) = %q, expected %q", tt.lang, tt.message, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package importer разбирает выгрузки транзакций из внешних источников.
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nemopss/fin-ng/backend/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// MaxRows — максимальное число строк в одном импорте.
+const MaxRows = 10000
+
+// Mapping задает, в каких столбцах CSV (по заголовку) находятся поля транзакции.
+type Mapping struct {
+	Date   string `json:"date" example:"Дата операции"`
+	Amount string `json:"amount" example:"Сумма"`
+	// Type — столбец типа (income/expense); если не задан, тип определяется по знаку суммы
+	Type        string `json:"type"`
+	Category    string `json:"category" example:"Категория"`
+	Payee       string `json:"payee" example:"Получатель"`
+	Description string `json:"description" example:"Описание"`
+	Currency    string `json:"currency" example:"Валюта"`
+	// Tags — столбец со списком тегов через запятую
+	Tags string `json:"tags"`
+	// DateFormat — формат даты в нотации Go; по умолчанию пробуются распространенные форматы
+	DateFormat string `json:"date_format" example:"02.01.2006"`
+	// Delimiter — разделитель столбцов; по умолчанию запятая
+	Delimiter string `json:"delimiter" example:";"`
+	// Encoding — кодировка файла: utf-8 (по умолчанию) или windows-1251
+	Encoding string `json:"encoding" example:"windows-1251"`
+}
+
+// Row — разобранная строка выгрузки. Если Err не nil, остальные поля могут быть не заполнены.
+type Row struct {
+	// Line — номер строки в файле, начиная с 1 (заголовок — строка 1)
+	Line     int
+	Date     time.Time
+	Amount   models.Money
+	Type     string
+	Category string
+	// Payee — контрагент (получатель или плательщик), если он указан в выписке отдельно
+	Payee       string
+	Description string
+	Currency    string
+	Tags        []string
+	Err         error
+}
+
+// dateFormats — форматы дат, которые пробуются, если DateFormat не задан.
+var dateFormats = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+	"02.01.2006",
+	"01/02/2006",
+}
+
+// ParseCSV читает CSV с заголовком и разбирает строки согласно mapping.
+// Ошибки отдельных строк возвращаются в Row.Err; ошибка функции означает,
+// что файл не удалось разобрать целиком.
+func ParseCSV(r io.Reader, mapping Mapping) ([]Row, error) {
+	switch strings.ToLower(mapping.Encoding) {
+	case "", "utf-8", "utf8":
+	case "windows-1251", "cp1251", "1251":
+		r = charmap.Windows1251.NewDecoder().Reader(r)
+	default:
+		return nil, fmt.Errorf("encoding must be 'utf-8' or 'windows-1251'")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != "" {
+		delimiter, size := utf8.DecodeRuneInString(mapping.Delimiter)
+		if size != len(mapping.Delimiter) {
+			return nil, fmt.Errorf("delimiter must be a single character")
+		}
+		reader.Comma = delimiter
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel добавляет BOM в начало файла
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	index := func(name string, required bool) (int, error) {
+		if name == "" {
+			if required {
+				return -1, fmt.Errorf("mapping is incomplete")
+			}
+			return -1, nil
+		}
+		i, ok := columns[name]
+		if !ok {
+			return -1, fmt.Errorf("column %q not found", name)
+		}
+		return i, nil
+	}
+
+	var idx struct{ date, amount, typ, category, payee, description, currency, tags int }
+	for _, f := range []struct {
+		target   *int
+		name     string
+		required bool
+	}{
+		{&idx.date, mapping.Date, true},
+		{&idx.amount, mapping.Amount, true},
+		{&idx.typ, mapping.Type, false},
+		{&idx.category, mapping.Category, false},
+		{&idx.payee, mapping.Payee, false},
+		{&idx.description, mapping.Description, false},
+		{&idx.currency, mapping.Currency, false},
+		{&idx.tags, mapping.Tags, false},
+	} {
+		if *f.target, err = index(f.name, f.required); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []Row
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("file has more than %d rows", MaxRows)
+		}
+		if isBlank(record) {
+			continue
+		}
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := Row{Line: line, Category: field(idx.category), Payee: field(idx.payee), Description: field(idx.description), Currency: strings.ToUpper(field(idx.currency))}
+		if tags := field(idx.tags); tags != "" {
+			row.Tags = strings.Split(tags, ",")
+		}
+
+		if row.Date, err = parseDate(field(idx.date), mapping.DateFormat); err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+
+		amount, err := ParseAmount(field(idx.amount))
+		if err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+
+		if idx.typ >= 0 {
+			row.Type = strings.ToLower(field(idx.typ))
+			if row.Type != "income" && row.Type != "expense" {
+				row.Err = fmt.Errorf("type must be 'income' or 'expense'")
+			}
+		} else if amount < 0 {
+			row.Type = "expense"
+		} else {
+			row.Type = "income"
+		}
+		if amount < 0 {
+			amount = -amount
+		}
+		row.Amount = amount
+
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func parseDate(value, format string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("date is required")
+	}
+	if format != "" {
+		date, err := time.Parse(format, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		return date, nil
+	}
+	for _, layout := range dateFormats {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// ParseAmount разбирает сумму, записанную как в банковских выгрузках: с пробелами
+// между разрядами, запятой или точкой в качестве десятичного разделителя и знаком.
+func ParseAmount(value string) (models.Money, error) {
+	cleaned := strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(value)
+	if cleaned == "" {
+		return 0, fmt.Errorf("amount is required")
+	}
+	// "1,234.56" — запятая разделяет разряды; "1234,56" — десятичная запятая
+	if strings.Contains(cleaned, ".") {
+		cleaned = strings.ReplaceAll(cleaned, ",", "")
+	} else {
+		cleaned = strings.Replace(cleaned, ",", ".", 1)
+	}
+	amount, err := models.ParseMoney(cleaned)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
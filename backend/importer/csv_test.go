@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestParseCSV тестирует разбор CSV по сопоставлению столбцов.
+func TestParseCSV(t *testing.T) {
+	data := "\ufeffДата;Сумма;Категория;Описание;Теги\n" +
+		"15.01.2025;-1 234,50;Продукты;Магазин;food,weekly\n" +
+		"16.01.2025;50000;Зарплата;Аванс;\n" +
+		";;;;\n" +
+		"32.01.2025;100;Продукты;;\n" +
+		"17.01.2025;abc;Продукты;;\n"
+
+	rows, err := ParseCSV(strings.NewReader(data), Mapping{
+		Date: "Дата", Amount: "Сумма", Category: "Категория", Description: "Описание", Tags: "Теги",
+		DateFormat: "02.01.2006", Delimiter: ";",
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Expected 4 rows (blank row skipped), got %d", len(rows))
+	}
+
+	// Отрицательная сумма — расход, положительная — доход
+	first := rows[0]
+	if first.Err != nil || first.Line != 2 || first.Amount != models.NewMoney(1234, 50) || first.Type != "expense" ||
+		!first.Date.Equal(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)) || first.Category != "Продукты" ||
+		len(first.Tags) != 2 {
+		t.Errorf("Unexpected first row: %+v", first)
+	}
+	if rows[1].Err != nil || rows[1].Type != "income" || rows[1].Amount != models.NewMoney(50000, 0) {
+		t.Errorf("Unexpected second row: %+v", rows[1])
+	}
+
+	// Ошибки строк не прерывают разбор
+	if rows[2].Err == nil || rows[2].Line != 5 {
+		t.Errorf("Expected date error on line 5, got %+v", rows[2])
+	}
+	if rows[3].Err == nil || rows[3].Line != 6 {
+		t.Errorf("Expected amount error on line 6, got %+v", rows[3])
+	}
+
+	// Отсутствующий столбец — ошибка всего файла
+	if _, err := ParseCSV(strings.NewReader(data), Mapping{Date: "Date", Amount: "Сумма", Delimiter: ";"}); err == nil {
+		t.Error("Expected error for missing column")
+	}
+}
+
+// TestParseAmount тестирует разбор сумм в разных записях.
+func TestParseAmount(t *testing.T) {
+	tests := map[string]models.Money{
+		"100":        models.NewMoney(100, 0),
+		"-42.5":      models.NewMoney(-42, 50),
+		"1 234,56":   models.NewMoney(1234, 56),
+		"1,234.56":   models.NewMoney(1234, 56),
+		"1\u00a0000": models.NewMoney(1000, 0),
+	}
+	for input, expected := range tests {
+		amount, err := ParseAmount(input)
+		if err != nil || amount != expected {
+			t.Errorf("ParseAmount(%q): expected %v, got %v (%v)", input, expected, amount, err)
+		}
+	}
+	for _, input := range []string{"12a", "1.234"} {
+		if _, err := ParseAmount(input); err == nil {
+			t.Errorf("Expected error for invalid amount %q", input)
+		}
+	}
+}
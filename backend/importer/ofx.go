@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+var (
+	ofxTransactionPattern = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxFieldPattern       = regexp.MustCompile(`(?is)<([A-Z0-9.]+)>([^<]*)`)
+	ofxCurrencyPattern    = regexp.MustCompile(`(?i)<CURDEF>\s*([A-Z]{3})`)
+	ofxCharsetPattern     = regexp.MustCompile(`(?im)^CHARSET:\s*(\S+)|encoding="([^"]+)"`)
+)
+
+// ParseOFX разбирает выписку OFX версии 1 (SGML) или 2 (XML). Контрагент (NAME)
+// возвращается в Row.Payee, примечание (MEMO) — в Row.Description.
+func ParseOFX(r io.Reader) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(bytes.ToUpper(data), []byte("<OFX>")) {
+		return nil, fmt.Errorf("file is not an OFX statement")
+	}
+
+	// Российские банки часто выгружают OFX 1.x в кодировке windows-1251
+	if m := ofxCharsetPattern.FindSubmatch(data); m != nil {
+		charset := strings.ToLower(string(m[1]) + string(m[2]))
+		if charset == "1251" || charset == "windows-1251" {
+			if data, err = charmap.Windows1251.NewDecoder().Bytes(data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	currency := ""
+	if m := ofxCurrencyPattern.FindSubmatch(data); m != nil {
+		currency = strings.ToUpper(string(m[1]))
+	}
+
+	var rows []Row
+	for i, block := range ofxTransactionPattern.FindAllSubmatch(data, -1) {
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("file has more than %d rows", MaxRows)
+		}
+
+		fields := make(map[string]string)
+		for _, m := range ofxFieldPattern.FindAllSubmatch(block[1], -1) {
+			fields[strings.ToUpper(string(m[1]))] = strings.TrimSpace(unescapeOFX(string(m[2])))
+		}
+
+		row := Row{Line: i + 1, Payee: fields["NAME"], Description: fields["MEMO"], Currency: currency}
+		if row.Date, err = parseOFXDate(fields["DTPOSTED"]); err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+		amount, err := ParseAmount(fields["TRNAMT"])
+		if err != nil {
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+		row.Type = "income"
+		if amount < 0 {
+			row.Type = "expense"
+			amount = -amount
+		}
+		row.Amount = amount
+		rows = append(rows, row)
+	}
+	if rows == nil {
+		return nil, fmt.Errorf("no transactions found")
+	}
+	return rows, nil
+}
+
+func unescapeOFX(value string) string {
+	return strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&", "&quot;", `"`, "&apos;", "'").Replace(value)
+}
+
+// parseOFXDate разбирает дату вида YYYYMMDD[HHMMSS[.XXX]][[gmt offset:tz name]].
+func parseOFXDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("date is required")
+	}
+	raw := value
+
+	location := time.UTC
+	if i := strings.Index(value, "["); i >= 0 {
+		offset := strings.TrimSuffix(value[i+1:], "]")
+		offset, _, _ = strings.Cut(offset, ":")
+		value = value[:i]
+		var hours float64
+		if _, err := fmt.Sscanf(offset, "%g", &hours); err == nil {
+			location = time.FixedZone("", int(hours*3600))
+		}
+	}
+	if i := strings.Index(value, "."); i >= 0 {
+		value = value[:i]
+	}
+
+	for _, layout := range []string{"20060102150405", "200601021504", "20060102"} {
+		if len(value) == len(layout) {
+			date, err := time.ParseInLocation(layout, value, location)
+			if err != nil {
+				break
+			}
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", raw)
+}
+
+// qifDateFormats — форматы дат, встречающиеся в QIF (включая апостроф перед годом у Quicken).
+var qifDateFormats = []string{
+	"01/02/2006", "1/2/2006", "01/02'06", "1/2'06", "01/02/06", "1/2/06",
+	"02.01.2006", "02.01.06", "2006-01-02",
+}
+
+// ParseQIF разбирает выписку QIF. Контрагент (P) возвращается в Row.Payee,
+// примечание (M) — в Row.Description, категория (L) — в Row.Category.
+// Если dateFormat пуст, пробуются распространенные форматы дат.
+func ParseQIF(r io.Reader, dateFormat string) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	var rows []Row
+	row := Row{}
+	var dateValue, amountValue string
+	hasData := false
+	headerSeen := false
+
+	flush := func() {
+		if !hasData {
+			return
+		}
+		row.Line = len(rows) + 1
+		row.Date, row.Err = parseQIFDate(dateValue, dateFormat)
+		if row.Err == nil {
+			amount, err := ParseAmount(amountValue)
+			if err != nil {
+				row.Err = err
+			} else {
+				row.Type = "income"
+				if amount < 0 {
+					row.Type = "expense"
+					amount = -amount
+				}
+				row.Amount = amount
+			}
+		}
+		rows = append(rows, row)
+		row = Row{}
+		dateValue, amountValue = "", ""
+		hasData = false
+	}
+
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "!") {
+			headerSeen = true
+			continue
+		}
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("file has more than %d rows", MaxRows)
+		}
+
+		code, value := text[0], strings.TrimSpace(text[1:])
+		switch code {
+		case '^':
+			flush()
+			continue
+		case 'D':
+			dateValue = value
+		case 'T', 'U':
+			amountValue = value
+		case 'P':
+			row.Payee = value
+		case 'M':
+			row.Description = value
+		case 'L':
+			// Переводы между счетами записываются как [Счет]; подкатегории — через двоеточие
+			if !strings.HasPrefix(value, "[") {
+				row.Category, _, _ = strings.Cut(value, ":")
+			}
+		}
+		hasData = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	if !headerSeen {
+		return nil, fmt.Errorf("file is not a QIF statement")
+	}
+	if rows == nil {
+		return nil, fmt.Errorf("no transactions found")
+	}
+	return rows, nil
+}
+
+func parseQIFDate(value, format string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("date is required")
+	}
+	if format != "" {
+		return parseDate(value, format)
+	}
+	// Quicken может записывать год с пробелом после апострофа: 1/ 2' 6
+	value = strings.ReplaceAll(value, " ", "")
+	if prefix, year, ok := strings.Cut(value, "'"); ok && len(year) == 1 {
+		value = prefix + "'0" + year
+	}
+	for _, layout := range qifDateFormats {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
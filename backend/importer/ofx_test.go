@@ -0,0 +1,128 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestParseOFX тестирует разбор OFX 1.x (SGML в windows-1251) и OFX 2.x (XML).
+func TestParseOFX(t *testing.T) {
+	sgml, err := charmap.Windows1251.NewEncoder().String(`OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+ENCODING:USASCII
+CHARSET:1251
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>RUB
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250115120000.000[+3:MSK]
+<TRNAMT>-1234.50
+<FITID>1
+<NAME>Пятерочка
+<MEMO>Продукты &amp; хозтовары
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20250116
+<TRNAMT>50000
+<FITID>2
+<NAME>ООО Ромашка
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`)
+	if err != nil {
+		t.Fatalf("Failed to encode statement: %v", err)
+	}
+
+	rows, err := ParseOFX(strings.NewReader(sgml))
+	if err != nil {
+		t.Fatalf("Failed to parse OFX: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	first := rows[0]
+	expectedDate := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	if first.Err != nil || first.Amount != models.NewMoney(1234, 50) || first.Type != "expense" || first.Payee != "Пятерочка" ||
+		first.Description != "Продукты & хозтовары" || first.Currency != "RUB" || !first.Date.Equal(expectedDate) {
+		t.Errorf("Unexpected first row: %+v", first)
+	}
+	if rows[1].Err != nil || rows[1].Type != "income" || rows[1].Amount != models.NewMoney(50000, 0) || rows[1].Payee != "ООО Ромашка" {
+		t.Errorf("Unexpected second row: %+v", rows[1])
+	}
+
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>USD</CURDEF><BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20250201</DTPOSTED><TRNAMT>-9.99</TRNAMT><NAME>Netflix</NAME></STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+	rows, err = ParseOFX(strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("Failed to parse OFX: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Payee != "Netflix" || rows[0].Amount != models.NewMoney(9, 99) || rows[0].Currency != "USD" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+
+	if _, err := ParseOFX(strings.NewReader("date,amount\n")); err == nil {
+		t.Error("Expected error for non-OFX file")
+	}
+}
+
+// TestParseQIF тестирует разбор QIF и назначение категорий по правилам.
+func TestParseQIF(t *testing.T) {
+	data := `!Type:Bank
+D01/15/2025
+T-1,234.50
+PPyaterochka
+MWeekly
+^
+D1/16' 5
+T50000
+PEmployer
+LSalary:Bonus
+^
+D02/30/2025
+T-10
+^
+`
+	rows, err := ParseQIF(strings.NewReader(data), "")
+	if err != nil {
+		t.Fatalf("Failed to parse QIF: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Err != nil || rows[0].Amount != models.NewMoney(1234, 50) || rows[0].Type != "expense" || rows[0].Payee != "Pyaterochka" ||
+		!rows[0].Date.Equal(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Err != nil || rows[1].Category != "Salary" || rows[1].Type != "income" ||
+		!rows[1].Date.Equal(time.Date(2005, 1, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected second row: %+v", rows[1])
+	}
+	if rows[2].Err == nil {
+		t.Errorf("Expected date error for third row, got %+v", rows[2])
+	}
+
+	// Правила не меняют уже заданную категорию
+	ApplyRules(rows, []Rule{{Match: "PYATEROCHKA", Category: "Food"}, {Match: "employer", Category: "Work"}})
+	if rows[0].Category != "Food" || rows[1].Category != "Salary" {
+		t.Errorf("Unexpected categories after rules: %q, %q", rows[0].Category, rows[1].Category)
+	}
+
+	if _, err := ParseQIF(strings.NewReader("D01/15/2025\n^\n"), ""); err == nil {
+		t.Error("Expected error for file without header")
+	}
+}
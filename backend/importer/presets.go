@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+)
+
+// presets — сопоставления столбцов для выгрузок распространенных банков.
+var presets = map[string]Mapping{
+	// Тинькофф: «Операции» → «Выгрузить в CSV»; сумма платежа в валюте карты
+	"tinkoff": {
+		Date: "Дата операции", Amount: "Сумма платежа", Currency: "Валюта платежа",
+		Category: "Категория", Payee: "Описание",
+		DateFormat: "02.01.2006 15:04:05", Delimiter: ";", Encoding: "windows-1251",
+	},
+	// Сбербанк: выписка по карте в формате CSV
+	"sber": {
+		Date: "Дата операции", Amount: "Сумма в валюте счёта", Category: "Категория", Payee: "Описание",
+		DateFormat: "02.01.2006", Delimiter: ";",
+	},
+	// Revolut: Statement → Excel/CSV; Started Date заполнена и у незавершенных операций
+	"revolut": {
+		Date: "Started Date", Amount: "Amount", Currency: "Currency", Payee: "Description",
+		DateFormat: "2006-01-02 15:04:05",
+	},
+	// Wise: Statements → CSV
+	"wise": {
+		Date: "Date", Amount: "Amount", Currency: "Currency", Payee: "Merchant", Description: "Description",
+		DateFormat: "02-01-2006",
+	},
+}
+
+// Preset возвращает сопоставление столбцов для выгрузки банка по имени без учета регистра.
+func Preset(name string) (Mapping, bool) {
+	mapping, ok := presets[strings.ToLower(name)]
+	return mapping, ok
+}
+
+// PresetNames возвращает имена встроенных сопоставлений по алфавиту.
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestPresets тестирует разбор выгрузок банков по встроенным профилям.
+func TestPresets(t *testing.T) {
+	tinkoff := "Дата операции;Дата платежа;Номер карты;Статус;Сумма операции;Валюта операции;Сумма платежа;Валюта платежа;Кэшбэк;Категория;MCC;Описание\n" +
+		"15.01.2025 18:42:10;16.01.2025;*1234;OK;-1 234,50;RUB;-1 234,50;RUB;;Супермаркеты;5411;Пятерочка\n"
+	encoded, err := charmap.Windows1251.NewEncoder().String(tinkoff)
+	if err != nil {
+		t.Fatalf("Failed to encode sample: %v", err)
+	}
+
+	revolut := "Type,Product,Started Date,Completed Date,Description,Amount,Fee,Currency,State,Balance\n" +
+		"CARD_PAYMENT,Current,2025-01-15 18:42:10,2025-01-16 09:00:00,Netflix,-9.99,0.00,EUR,COMPLETED,90.01\n"
+
+	wise := "TransferWise ID,Date,Amount,Currency,Description,Payment Reference,Running Balance,Merchant\n" +
+		"CARD-1,15-01-2025,-9.99,USD,Card transaction,,90.01,Netflix\n"
+
+	for _, tc := range []struct {
+		preset string
+		data   string
+		amount models.Money
+		cur    string
+		payee  string
+	}{
+		{"Tinkoff", encoded, models.NewMoney(1234, 50), "RUB", "Пятерочка"},
+		{"revolut", revolut, models.NewMoney(9, 99), "EUR", "Netflix"},
+		{"wise", wise, models.NewMoney(9, 99), "USD", "Netflix"},
+	} {
+		mapping, ok := Preset(tc.preset)
+		if !ok {
+			t.Fatalf("Preset %q not found", tc.preset)
+		}
+		rows, err := ParseCSV(strings.NewReader(tc.data), mapping)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", tc.preset, err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("%s: expected 1 row, got %d", tc.preset, len(rows))
+		}
+		row := rows[0]
+		if row.Err != nil || row.Amount != tc.amount || row.Type != "expense" || row.Currency != tc.cur || row.Payee != tc.payee ||
+			row.Date.Year() != 2025 || row.Date.Month() != time.January || row.Date.Day() != 15 {
+			t.Errorf("%s: unexpected row %+v", tc.preset, row)
+		}
+	}
+
+	if _, ok := Preset("unknown"); ok {
+		t.Error("Expected unknown preset to be missing")
+	}
+	if _, err := ParseCSV(strings.NewReader("a\n"), Mapping{Date: "a", Amount: "a", Encoding: "koi8-r"}); err == nil {
+		t.Error("Expected error for unsupported encoding")
+	}
+}
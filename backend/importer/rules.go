@@ -0,0 +1,26 @@
+package importer
+
+import "strings"
+
+// Rule назначает категорию строкам, у которых контрагент или описание содержит Match.
+type Rule struct {
+	Match    string `json:"match" example:"Пятерочка"`
+	Category string `json:"category" example:"Продукты"`
+}
+
+// ApplyRules заполняет категорию строк без категории по первому подходящему правилу.
+// Сравнение выполняется без учета регистра.
+func ApplyRules(rows []Row, rules []Rule) {
+	for i := range rows {
+		if rows[i].Err != nil || rows[i].Category != "" {
+			continue
+		}
+		text := strings.ToLower(rows[i].Payee + " " + rows[i].Description)
+		for _, rule := range rules {
+			if rule.Match != "" && strings.Contains(text, strings.ToLower(rule.Match)) {
+				rows[i].Category = rule.Category
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package loan рассчитывает аннуитетные платежи и графики погашения кредитов.
+package loan
+
+import (
+	"math"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// MonthlyInterest возвращает проценты за месяц на остаток долга balance при годовой ставке annualRate в процентах.
+func MonthlyInterest(balance models.Money, annualRate float64) models.Money {
+	return models.Money(math.Round(float64(balance) * annualRate / 1200))
+}
+
+// Payment возвращает ежемесячный аннуитетный платеж, погашающий principal за months месяцев.
+func Payment(principal models.Money, annualRate float64, months int) models.Money {
+	if months <= 0 {
+		return 0
+	}
+	r := annualRate / 1200
+	if r == 0 {
+		return models.Money(math.Ceil(float64(principal) / float64(months)))
+	}
+	return models.Money(math.Round(float64(principal) * r / (1 - math.Pow(1+r, -float64(months)))))
+}
+
+// AddMonths сдвигает дату t на months месяцев. Если такого дня нет в целевом месяце, берется его последний день:
+// платежи с 31 января приходятся на 28 или 29 февраля и 31 марта, а не на 3 марта.
+func AddMonths(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// Schedule возвращает график погашения по условиям кредита. Последний платеж погашает остаток долга,
+// накопившийся из-за округления платежей до копейки.
+func Schedule(terms models.LoanTerms) []models.AmortizationRow {
+	payment := Payment(terms.Principal, terms.InterestRate, terms.TermMonths)
+	rows := make([]models.AmortizationRow, 0, terms.TermMonths)
+	balance := terms.Principal
+	for i := 1; i <= terms.TermMonths && balance > 0; i++ {
+		interest := MonthlyInterest(balance, terms.InterestRate)
+		principal := payment - interest
+		if i == terms.TermMonths || principal > balance {
+			principal = balance
+		}
+		balance -= principal
+		rows = append(rows, models.AmortizationRow{
+			Number:    i,
+			Date:      AddMonths(terms.FirstPaymentDate, i-1),
+			Payment:   principal + interest,
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return rows
+}
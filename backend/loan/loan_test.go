@@ -0,0 +1,85 @@
+package loan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestPayment тестирует аннуитетный платеж.
+func TestPayment(t *testing.T) {
+	tests := []struct {
+		principal models.Money
+		rate      float64
+		months    int
+		want      models.Money
+	}{
+		{models.NewMoney(100000, 0), 12, 12, models.NewMoney(8884, 88)},
+		{models.NewMoney(3000000, 0), 12.5, 240, models.NewMoney(34084, 22)},
+		// Без процентов долг делится поровну с округлением вверх
+		{models.NewMoney(1000, 0), 0, 3, models.NewMoney(333, 34)},
+		{models.NewMoney(1000, 0), 10, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := Payment(tt.principal, tt.rate, tt.months); got != tt.want {
+			t.Errorf("Payment(%s, %g, %d) = %s, want %s", tt.principal, tt.rate, tt.months, got, tt.want)
+		}
+	}
+}
+
+// TestSchedule тестирует график погашения.
+func TestSchedule(t *testing.T) {
+	first := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	rows := Schedule(models.LoanTerms{Principal: models.NewMoney(100000, 0), InterestRate: 12, TermMonths: 12, FirstPaymentDate: first})
+	if len(rows) != 12 {
+		t.Fatalf("Expected 12 payments, got %d", len(rows))
+	}
+
+	firstRow := rows[0]
+	if firstRow.Interest != models.NewMoney(1000, 0) || firstRow.Principal != models.NewMoney(7884, 88) || firstRow.Balance != models.NewMoney(92115, 12) {
+		t.Errorf("Unexpected first payment: %+v", firstRow)
+	}
+	if !rows[11].Date.Equal(time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected last payment date: %s", rows[11].Date)
+	}
+
+	var principal models.Money
+	for _, row := range rows {
+		principal += row.Principal
+		if row.Payment != row.Principal+row.Interest {
+			t.Errorf("Payment %d does not add up: %+v", row.Number, row)
+		}
+	}
+	if principal != models.NewMoney(100000, 0) || rows[11].Balance != 0 {
+		t.Errorf("Expected the schedule to repay the principal, got %s with balance %s", principal, rows[11].Balance)
+	}
+	if diff := rows[11].Payment - rows[0].Payment; diff > 5 || diff < -5 {
+		t.Errorf("Expected the last payment to be close to the others, got %s and %s", rows[11].Payment, rows[0].Payment)
+	}
+}
+
+// TestScheduleMonthEnd тестирует даты платежей, когда первый платеж приходится на конец месяца.
+func TestScheduleMonthEnd(t *testing.T) {
+	tests := []struct {
+		first time.Time
+		want  []string
+	}{
+		{time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30", "2025-05-31"}},
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31"}},
+		// С 29 февраля високосного года платежи идут 29 числа, в невисокосный февраль — 28-го
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), []string{"2024-02-29", "2024-03-29", "2024-04-29"}},
+		{time.Date(2024, 12, 29, 0, 0, 0, 0, time.UTC), []string{"2024-12-29", "2025-01-29", "2025-02-28", "2025-03-29"}},
+	}
+	for _, tt := range tests {
+		rows := Schedule(models.LoanTerms{Principal: models.NewMoney(100000, 0), InterestRate: 12, TermMonths: len(tt.want), FirstPaymentDate: tt.first})
+		if len(rows) != len(tt.want) {
+			t.Fatalf("Expected %d payments, got %d", len(tt.want), len(rows))
+		}
+		for i, row := range rows {
+			if got := row.Date.Format("2006-01-02"); got != tt.want[i] {
+				t.Errorf("Payment %d from %s: expected %s, got %s", row.Number, tt.first.Format("2006-01-02"), tt.want[i], got)
+			}
+		}
+	}
+}
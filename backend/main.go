@@ -1,19 +1,40 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	//"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	"github.com/nemopss/fin-ng/backend/api"
+	"github.com/nemopss/fin-ng/backend/cache"
+	"github.com/nemopss/fin-ng/backend/captcha"
 	"github.com/nemopss/fin-ng/backend/db"
 	_ "github.com/nemopss/fin-ng/backend/docs"
+	"github.com/nemopss/fin-ng/backend/mail"
+	"github.com/nemopss/fin-ng/backend/metrics"
+	"github.com/nemopss/fin-ng/backend/quotes"
+	"github.com/nemopss/fin-ng/backend/ratelimit"
+	"github.com/nemopss/fin-ng/backend/rates"
+	"github.com/nemopss/fin-ng/backend/receipt"
+	"github.com/nemopss/fin-ng/backend/scheduler"
+	"github.com/nemopss/fin-ng/backend/server"
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
+// @BasePath /api/v1
 // @SecurityDefinitions.apikey ApiKeyAuth
 // @In header
 // @Name Authorization
@@ -24,37 +45,447 @@ func main() {
 
 	// Подключение к PostgreSQL
 	connStr := os.Getenv("POSTGRES_URL")
-	storage, err := db.NewStorage(connStr)
+
+	// Команда migrate up|down|status|version управляет схемой базы без запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if len(os.Args) != 3 {
+			log.Fatal("usage: migrate up|down|status|version")
+		}
+		if err := runMigrations(connStr, os.Args[2]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Пул соединений: DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME (например 30m).
+	// При запуске база ожидается DB_CONNECT_TIMEOUT (по умолчанию 30s)
+	var (
+		pool db.PoolConfig
+		err  error
+	)
+	if pool.MaxOpenConns, err = intFromEnv("DB_MAX_OPEN_CONNS"); err != nil {
+		log.Fatal(err)
+	}
+	if pool.MaxIdleConns, err = intFromEnv("DB_MAX_IDLE_CONNS"); err != nil {
+		log.Fatal(err)
+	}
+	if pool.ConnMaxLifetime, err = durationFromEnv("DB_CONN_MAX_LIFETIME"); err != nil {
+		log.Fatal(err)
+	}
+	if pool.ConnectTimeout, err = durationFromEnv("DB_CONNECT_TIMEOUT"); err != nil {
+		log.Fatal(err)
+	}
+	if os.Getenv("DB_CONNECT_TIMEOUT") == "" {
+		pool.ConnectTimeout = 30 * time.Second
+	}
+
+	storage, err := db.NewStorage(connStr, pool)
 	if err != nil {
 		panic(err)
 	}
 	defer storage.Close()
 
+	// Остаток счетов с числом транзакций от BALANCE_CACHE_MIN_TRANSACTIONS (по умолчанию 1000)
+	// кэшируется до изменения их транзакций; отрицательное значение отключает кэш
+	balanceCacheMin, err := intFromEnv("BALANCE_CACHE_MIN_TRANSACTIONS")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if balanceCacheMin < 0 {
+		storage.BalanceCacheMinTransactions = 0
+	} else if balanceCacheMin > 0 {
+		storage.BalanceCacheMinTransactions = balanceCacheMin
+	}
+
 	// Получение JWT_SECRET из .env
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		log.Fatal("JWT_SECRET is required")
 	}
 
-	handler := api.NewHandler(storage, jwtSecret)
+	tokenTTL, err := durationFromEnv("JWT_TTL")
+	if err != nil {
+		log.Fatal(err)
+	}
+	rememberMeTTL, err := durationFromEnv("JWT_REMEMBER_ME_TTL")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	magicLinkTTL, err := durationFromEnv("MAGIC_LINK_TTL")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Без SMTP_HOST письма только пишутся в лог
+	var mailer mail.Sender = mail.LogSender{}
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		mailer = mail.NewSMTPSender(mail.SMTPConfig{
+			Host:     host,
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		})
+	}
+
+	registrationMode := os.Getenv("REGISTRATION_MODE")
+	if registrationMode != "" && registrationMode != api.RegistrationOpen && registrationMode != api.RegistrationInvite {
+		log.Fatalf("invalid REGISTRATION_MODE: must be '%s' or '%s'", api.RegistrationOpen, api.RegistrationInvite)
+	}
+
+	// Пользователи из ADMIN_USERNAMES (через запятую) получают роль администратора
+	if admins := os.Getenv("ADMIN_USERNAMES"); admins != "" {
+		var usernames []string
+		for _, username := range strings.Split(admins, ",") {
+			if username = strings.TrimSpace(username); username != "" {
+				usernames = append(usernames, username)
+			}
+		}
+		if err := storage.PromoteAdmins(usernames); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Маршруты без версии включены по умолчанию, чтобы не сломать существующих клиентов
+	legacyRoutes := true
+	if value := os.Getenv("LEGACY_ROUTES"); value != "" {
+		if legacyRoutes, err = strconv.ParseBool(value); err != nil {
+			log.Fatalf("invalid LEGACY_ROUTES: %v", err)
+		}
+	}
+	var legacySunset time.Time
+	if value := os.Getenv("LEGACY_ROUTES_SUNSET"); value != "" {
+		if legacySunset, err = time.Parse("2006-01-02", value); err != nil {
+			log.Fatalf("invalid LEGACY_ROUTES_SUNSET: %v", err)
+		}
+	}
+
+	// Вход через внешний OpenID Connect провайдер включается заданием OIDC_ISSUER_URL.
+	// Пока маршруты без версии включены, адрес возврата по умолчанию остается прежним,
+	// чтобы не менять адрес, зарегистрированный у провайдера
+	var oidcProvider *api.OIDCProvider
+	if issuer := os.Getenv("OIDC_ISSUER_URL"); issuer != "" {
+		redirectURL := os.Getenv("OIDC_REDIRECT_URL")
+		if redirectURL == "" {
+			redirectURL = strings.TrimSuffix(os.Getenv("APP_URL"), "/") + "/auth/oidc/callback"
+			if !legacyRoutes {
+				redirectURL = strings.TrimSuffix(os.Getenv("APP_URL"), "/") + api.V1Prefix + "/auth/oidc/callback"
+			}
+		}
+		oidcProvider, err = api.NewOIDCProvider(context.Background(), api.OIDCConfig{
+			IssuerURL:    issuer,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  redirectURL,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// CAPTCHA включается заданием CAPTCHA_PROVIDER (hcaptcha или turnstile) и CAPTCHA_SECRET
+	var captchaVerifier captcha.Verifier
+	if provider := os.Getenv("CAPTCHA_PROVIDER"); provider != "" {
+		captchaVerifier, err = captcha.New(provider, os.Getenv("CAPTCHA_SECRET"))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	loginCaptchaThreshold, err := intFromEnv("CAPTCHA_LOGIN_THRESHOLD")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Квоты запросов в минуту: USER_RATE_QUOTA — для всех, ROLE_RATE_QUOTAS — по ролям ("admin=1000,user=120"),
+	// IP_RATE_QUOTA — с одного IP-адреса к маршрутам без авторизации
+	userQuota, err := intFromEnv("USER_RATE_QUOTA")
+	if err != nil {
+		log.Fatal(err)
+	}
+	ipQuota, err := intFromEnv("IP_RATE_QUOTA")
+	if err != nil {
+		log.Fatal(err)
+	}
+	roleQuotas, err := parseRoleQuotas(os.Getenv("ROLE_RATE_QUOTAS"))
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	r := gin.Default()
-	r.POST("/register", handler.Register)
-	r.POST("/login", handler.Login)
+	// Срок хранения удаленных транзакций в корзине (TRASH_RETENTION, по умолчанию 30 дней)
+	trashRetention, err := durationFromEnv("TRASH_RETENTION")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Курсы валют: EXCHANGE_RATES_PROVIDER — ecb, cbr или openapi
+	var ratesProvider rates.Provider
+	if provider := os.Getenv("EXCHANGE_RATES_PROVIDER"); provider != "" {
+		ratesProvider, err = rates.New(provider)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	protected := r.Group("/", handler.AuthMiddleware())
-	protected.GET("/transactions", handler.GetTransactions)
-	protected.GET("/transactions/:id", handler.GetTransaction)
-	protected.POST("/transactions", handler.CreateTransaction)
-	protected.DELETE("/transactions/:id", handler.DeleteTransaction)
-	protected.PUT("/transactions/:id", handler.UpdateTransaction)
-	protected.POST("/categories", handler.CreateCategory)
-	protected.GET("/categories", handler.GetCategories)
-	protected.GET("/categories/:id", handler.GetCategory)
-	protected.PUT("/categories/:id", handler.UpdateCategory)
-	protected.DELETE("/categories/:id", handler.DeleteCategory)
+	// Чеки по QR-коду: FNS_SESSION_ID — сессия приложения ФНС «Проверка чеков», FNS_DEVICE_ID — ID устройства
+	var receiptProvider receipt.Provider
+	if sessionID := os.Getenv("FNS_SESSION_ID"); sessionID != "" {
+		receiptProvider = receipt.NewFNS(sessionID, os.Getenv("FNS_DEVICE_ID"))
+	}
+
+	// Котировки ценных бумаг: QUOTES_PROVIDER — moex
+	var quotesProvider quotes.Provider
+	if provider := os.Getenv("QUOTES_PROVIDER"); provider != "" {
+		quotesProvider, err = quotes.New(provider)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Цены криптовалют: CRYPTO_QUOTES_PROVIDER — coingecko
+	var cryptoQuotesProvider quotes.Provider
+	if provider := os.Getenv("CRYPTO_QUOTES_PROVIDER"); provider != "" {
+		cryptoQuotesProvider, err = quotes.New(provider)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Кэш ответов и квоты запросов в Redis: REDIS_URL (по умолчанию кэш отключен, а квоты
+	// считаются в памяти каждого экземпляра), срок хранения записей кэша — REDIS_CACHE_TTL (по умолчанию 5m)
+	var (
+		responseCache cache.Store
+		rateLimiter   ratelimit.Limiter
+	)
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		cacheTTL, err := durationFromEnv("REDIS_CACHE_TTL")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if cacheTTL <= 0 {
+			cacheTTL = 5 * time.Minute
+		}
+		redisCache, err := cache.NewRedis(context.Background(), redisURL, cacheTTL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer redisCache.Close()
+		responseCache = redisCache
+
+		redisLimiter, err := ratelimit.NewRedis(context.Background(), redisURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer redisLimiter.Close()
+		rateLimiter = redisLimiter
+	}
+
+	jobs := scheduler.New()
+	handler := api.NewHandler(storage, api.Config{
+		JWTSecret:             jwtSecret,
+		TokenTTL:              tokenTTL,
+		RememberMeTTL:         rememberMeTTL,
+		BaseURL:               os.Getenv("APP_URL"),
+		Mailer:                mailer,
+		MagicLinkTTL:          magicLinkTTL,
+		OIDC:                  oidcProvider,
+		RegistrationMode:      registrationMode,
+		Captcha:               captchaVerifier,
+		LoginCaptchaThreshold: loginCaptchaThreshold,
+		LoginAlerts:           os.Getenv("LOGIN_ALERTS") == "true",
+		UserQuota:             userQuota,
+		RoleQuotas:            roleQuotas,
+		IPQuota:               ipQuota,
+		RateLimiter:           rateLimiter,
+		Rates:                 ratesProvider,
+		TrashRetention:        trashRetention,
+		Receipts:              receiptProvider,
+		Quotes:                quotesProvider,
+		CryptoQuotes:          cryptoQuotesProvider,
+		Cache:                 responseCache,
+		Scheduler:             jobs,
+	})
+	handler.FailInterruptedImports()
+
+	// Фоновые задачи: JOB_<ИМЯ>_ENABLED=false выключает задачу, JOB_<ИМЯ>_SCHEDULE задает расписание
+	// в формате cron или "@every 30m", например JOB_TRASH_PURGE_SCHEDULE="0 3 * * *"
+	for _, job := range handler.Jobs() {
+		prefix := "JOB_" + strings.ToUpper(job.Name)
+		enabled := true
+		if value := os.Getenv(prefix + "_ENABLED"); value != "" {
+			if enabled, err = strconv.ParseBool(value); err != nil {
+				log.Fatalf("invalid %s_ENABLED: %v", prefix, err)
+			}
+		}
+		if schedule := os.Getenv(prefix + "_SCHEDULE"); schedule != "" {
+			job.Schedule = schedule
+		}
+		if err := jobs.Add(job, enabled); err != nil {
+			log.Fatal(err)
+		}
+	}
+	jobs.Start()
+	defer jobs.Stop()
+
+	// CORS для фронтенда на другом источнике: CORS_ALLOWED_ORIGINS — источники через запятую
+	// (без них CORS отключен), CORS_ALLOWED_METHODS и CORS_ALLOWED_HEADERS переопределяют
+	// разрешенные методы и заголовки, CORS_MAX_AGE — срок хранения предварительного запроса
+	corsMaxAge, err := durationFromEnv("CORS_MAX_AGE")
+	if err != nil {
+		log.Fatal(err)
+	}
+	corsMiddleware, err := api.CORSMiddleware(api.CORSConfig{
+		AllowedOrigins: listFromEnv("CORS_ALLOWED_ORIGINS"),
+		AllowedMethods: listFromEnv("CORS_ALLOWED_METHODS"),
+		AllowedHeaders: listFromEnv("CORS_ALLOWED_HEADERS"),
+		MaxAge:         corsMaxAge,
+	})
+	if err != nil {
+		log.Fatalf("invalid CORS configuration: %v", err)
+	}
+
+	// Идентификатор запроса попадает в журнал запросов, заголовок X-Request-ID и ответы с ошибкой.
+	// Тексты ошибок переводятся на язык из заголовка Accept-Language
+	r := gin.New()
+	// Адрес клиента для квот и истории входов берется из X-Forwarded-For только за прокси из TRUSTED_PROXIES
+	// (IP-адреса и подсети через запятую). По умолчанию заголовку не доверяют и используется адрес соединения
+	if err := r.SetTrustedProxies(listFromEnv("TRUSTED_PROXIES")); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	r.Use(api.RequestIDMiddleware(), api.LocaleMiddleware(), gin.LoggerWithFormatter(api.LogFormatter), gin.Recovery(), metrics.Middleware())
+	if corsMiddleware != nil {
+		r.Use(corsMiddleware)
+	}
+	// Маршруты API монтируются в группу своей версии. Прежние адреса без версии работают так же,
+	// как /api/v1, но отмечаются устаревшими; LEGACY_ROUTES=false отключает их, а LEGACY_ROUTES_SUNSET
+	// (YYYY-MM-DD) объявляет клиентам дату отключения
+	handler.RegisterRoutes(r.Group(api.V1Prefix))
+	if legacyRoutes {
+		handler.RegisterRoutes(r.Group("/", api.DeprecationMiddleware(api.V1Prefix, legacySunset)))
+	}
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	r.Run()
+	// HTTPS без обратного прокси: TLS_CERT_FILE и TLS_KEY_FILE — готовый сертификат, либо
+	// TLS_AUTOCERT_DOMAINS — домены через запятую для сертификатов Let's Encrypt (каталог
+	// TLS_AUTOCERT_CACHE_DIR, адрес для уведомлений TLS_AUTOCERT_EMAIL; порт 80 должен быть доступен).
+	// Адрес сервера — ADDR, по умолчанию :$PORT или :8080, а с TLS — :443
+	addr := os.Getenv("ADDR")
+	if port := os.Getenv("PORT"); addr == "" && port != "" {
+		addr = ":" + port
+	}
+	srv, err := server.New(r, server.Config{
+		Addr:             addr,
+		CertFile:         os.Getenv("TLS_CERT_FILE"),
+		KeyFile:          os.Getenv("TLS_KEY_FILE"),
+		AutocertDomains:  listFromEnv("TLS_AUTOCERT_DOMAINS"),
+		AutocertCacheDir: os.Getenv("TLS_AUTOCERT_CACHE_DIR"),
+		AutocertEmail:    os.Getenv("TLS_AUTOCERT_EMAIL"),
+	})
+	if err != nil {
+		log.Fatalf("invalid TLS configuration: %v", err)
+	}
+
+	// gRPC-сервер основных операций на GRPC_ADDR, например :9090; без адреса gRPC отключен.
+	// С TLS использует тот же сертификат, что и HTTPS
+	if grpcAddr := os.Getenv("GRPC_ADDR"); grpcAddr != "" {
+		var opts []grpc.ServerOption
+		if tlsConfig := srv.TLSConfig(); tlsConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig.Clone())))
+		}
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			log.Fatalf("failed to listen for gRPC on %s: %v", grpcAddr, err)
+		}
+		grpcServer := handler.GRPCServer(opts...)
+		go func() {
+			log.Fatal(grpcServer.Serve(listener))
+		}()
+		log.Printf("gRPC server listening on %s", grpcAddr)
+	}
+
+	// Метрики Prometheus (время и статусы запросов, пул соединений с базой, счетчики событий) отдаются
+	// без авторизации, поэтому не монтируются в публичный роутер: их сервер слушает METRICS_ADDR,
+	// например 127.0.0.1:9100 или адрес во внутренней сети. Без адреса метрики отключены
+	if metricsAddr := os.Getenv("METRICS_ADDR"); metricsAddr != "" {
+		metricsRouter := gin.New()
+		metricsRouter.Use(gin.Recovery())
+		metricsRouter.GET("/metrics", metrics.Handler(metrics.NewRegistry(storage.DB)))
+		metricsServer := &http.Server{Addr: metricsAddr, Handler: metricsRouter, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			log.Fatal(metricsServer.ListenAndServe())
+		}()
+		log.Printf("metrics server listening on %s", metricsAddr)
+	}
+
+	log.Fatal(srv.ListenAndServe())
+}
+
+// durationFromEnv читает длительность (например, "1h" или "720h") из переменной окружения.
+// Пустое значение означает значение по умолчанию.
+func durationFromEnv(key string) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+// runMigrations выполняет команду миграций над базой connStr.
+func runMigrations(connStr, command string) error {
+	conn, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return db.RunMigrationCommand(context.Background(), conn, command, os.Stdout)
+}
+
+// listFromEnv читает список значений через запятую из переменной окружения, пропуская пустые.
+func listFromEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// intFromEnv читает целое число из переменной окружения.
+// Пустое значение означает значение по умолчанию.
+func intFromEnv(key string) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// parseRoleQuotas разбирает квоты по ролям в формате "role=limit,role=limit".
+func parseRoleQuotas(value string) (map[string]int, error) {
+	quotas := make(map[string]int)
+	if value == "" {
+		return quotas, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		role, limitStr, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid ROLE_RATE_QUOTAS entry: %q", pair)
+		}
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ROLE_RATE_QUOTAS limit for %s: %w", role, err)
+		}
+		quotas[role] = limit
+	}
+	return quotas, nil
 }
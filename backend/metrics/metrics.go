@@ -0,0 +1,85 @@
+// Package metrics собирает метрики приложения в формате Prometheus: время и статусы запросов API,
+// состояние пула соединений с базой и счетчики предметных событий.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "fin_ng"
+
+var (
+	// RequestDuration — время обработки запросов API по методу, маршруту и статусу ответа
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of API requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// TransactionsCreated — число созданных транзакций
+	TransactionsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_created_total",
+		Help:      "Number of created transactions.",
+	})
+
+	// Logins — попытки входа по результату: success или failure
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Number of login attempts by result.",
+	}, []string{"result"})
+
+	// Imports — завершенные задания импорта по статусу: completed, failed или cancelled
+	Imports = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "imports_total",
+		Help:      "Number of finished import jobs by status.",
+	}, []string{"status"})
+)
+
+// NewRegistry создает реестр с метриками приложения, среды выполнения Go и процесса,
+// а также статистикой пула соединений db.
+func NewRegistry(db *sql.DB) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "postgres"),
+		RequestDuration, TransactionsCreated, Logins, Imports,
+	)
+	return registry
+}
+
+// Handler отдает метрики реестра в текстовом формате Prometheus.
+func Handler(registry *prometheus.Registry) gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware измеряет время обработки запросов. Маршрут берется из шаблона пути,
+// чтобы ID в пути не порождали отдельные ряды; запросы без маршрута объединяются в "unmatched".
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		RequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := sql.Open("postgres", "postgres://localhost/metrics?sslmode=disable")
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/transactions/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/metrics", Handler(NewRegistry(db)))
+
+	for _, path := range []string{"/transactions/1", "/transactions/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	TransactionsCreated.Add(3)
+	Logins.WithLabelValues("success").Inc()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`fin_ng_http_request_duration_seconds_count{method="GET",route="/transactions/:id",status="404"} 2`,
+		`fin_ng_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+		`fin_ng_transactions_created_total 3`,
+		`fin_ng_logins_total{result="success"} 1`,
+		`go_sql_open_connections{db_name="postgres"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package models
+
+import "time"
+
+// Account — счет пользователя (наличные, карта, вклад). Транзакции счета ведутся в его валюте.
+type Account struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"user_id"`
+	Name     string `json:"name" example:"Наличные"`
+	Currency string `json:"currency" example:"RUB"`
+	// Type — тип счета: regular, credit_card, loan, investment или crypto
+	Type string `json:"type" example:"regular"`
+	// InitialBalance — начальный остаток счета до всех его транзакций
+	InitialBalance Money `json:"initial_balance" swaggertype:"number" example:"1000"`
+	// CreditLimit, StatementDay и PaymentDueDay — кредитный лимит, день закрытия выписки
+	// и день платежа; только для кредитных карт
+	CreditLimit   Money `json:"credit_limit,omitempty" swaggertype:"number" example:"100000"`
+	StatementDay  int   `json:"statement_day,omitempty" example:"25"`
+	PaymentDueDay int   `json:"payment_due_day,omitempty" example:"15"`
+	// Loan — условия кредита; только для кредитов
+	Loan *LoanTerms `json:"loan,omitempty"`
+	// Balance — текущий остаток: начальный баланс плюс доходы и минус расходы по счету
+	// без запланированных и удаленных транзакций
+	Balance   Money     `json:"balance" swaggertype:"number" example:"15250.5"`
+	CreatedAt time.Time `json:"created_at"`
+	// ClosedAt — время закрытия счета; транзакции закрытого счета доступны только для чтения
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+	// Role — доступ текущего пользователя к счету: owner, editor или viewer
+	Role string `json:"role,omitempty" example:"owner"`
+}
+
+// AccountShare — доступ пользователя к чужому счету.
+type AccountShare struct {
+	AccountID int    `json:"account_id" example:"1"`
+	UserID    int    `json:"user_id" example:"2"`
+	Username  string `json:"username" example:"jane_doe"`
+	// Role — viewer (просмотр счета и его транзакций) или editor (также транзакции, переводы и позиции по счету)
+	Role      string    `json:"role" example:"editor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccountBalance — текущий остаток счета.
+type AccountBalance struct {
+	AccountID      int    `json:"account_id" example:"1"`
+	Currency       string `json:"currency" example:"RUB"`
+	InitialBalance Money  `json:"initial_balance" swaggertype:"number" example:"1000"`
+	Balance        Money  `json:"balance" swaggertype:"number" example:"15250.5"`
+}
+
+// CurrencyAmount — сумма в одной валюте.
+type CurrencyAmount struct {
+	Currency string `json:"currency" example:"RUB"`
+	Amount   Money  `json:"amount" swaggertype:"number" example:"15250.5"`
+}
+
+// AvailableFunds — доступные средства: остатки открытых счетов по валютам.
+type AvailableFunds struct {
+	Balances []CurrencyAmount `json:"balances"`
+	// Currency и Total — сумма остатков в базовой валюте пользователя; Total нет, если курсы недоступны
+	Currency string `json:"currency" example:"RUB"`
+	Total    *Money `json:"total,omitempty" swaggertype:"number" example:"25250.5"`
+}
+
+// CreditCardStatement — текущая выписка кредитной карты. Суммы долга положительные.
+type CreditCardStatement struct {
+	AccountID int    `json:"account_id" example:"1"`
+	Currency  string `json:"currency" example:"RUB"`
+	// PeriodStart и PeriodEnd — первый и последний день закрытой выписки
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	DueDate     time.Time `json:"due_date"`
+	// StatementBalance — долг на конец выписки; Paid — поступления на карту после закрытия выписки
+	StatementBalance Money `json:"statement_balance" swaggertype:"number" example:"25000"`
+	Paid             Money `json:"paid" swaggertype:"number" example:"1000"`
+	// RemainingDue — сколько осталось внести до даты платежа, чтобы погасить долг по выписке
+	RemainingDue Money `json:"remaining_due" swaggertype:"number" example:"24000"`
+	// MinimumPayment — обязательный платеж по выписке, MinimumDue — его непогашенная часть
+	MinimumPayment  Money `json:"minimum_payment" swaggertype:"number" example:"1250"`
+	MinimumDue      Money `json:"minimum_due" swaggertype:"number" example:"250"`
+	CurrentBalance  Money `json:"current_balance" swaggertype:"number" example:"31000"`
+	AvailableCredit Money `json:"available_credit" swaggertype:"number" example:"69000"`
+	DaysUntilDue    int   `json:"days_until_due" example:"5"`
+	Overdue         bool  `json:"overdue"`
+	// Reminder — напоминание об обязательном платеже, если он не внесен и срок близко или прошел
+	Reminder string `json:"reminder,omitempty" example:"Внесите обязательный платеж 250.00 RUB до 15.03.2025"`
+}
+
+// BalancePoint — сохраненный остаток на конец дня: счета или суммы счетов в одной валюте.
+type BalancePoint struct {
+	Date     time.Time `json:"date" example:"2025-07-01T00:00:00Z"`
+	Currency string    `json:"currency" example:"RUB"`
+	Balance  Money     `json:"balance" swaggertype:"number" example:"15250.5"`
+}
+
+// LoanTerms — условия кредита с аннуитетными ежемесячными платежами.
+type LoanTerms struct {
+	Principal Money `json:"principal" swaggertype:"number" example:"3000000"`
+	// InterestRate — годовая процентная ставка
+	InterestRate float64 `json:"interest_rate" example:"12.5"`
+	TermMonths   int     `json:"term_months" example:"240"`
+	// FirstPaymentDate — дата первого платежа; по умолчанию через месяц после создания счета
+	FirstPaymentDate time.Time `json:"first_payment_date" example:"2025-08-01T00:00:00Z"`
+}
+
+// AmortizationRow — платеж графика погашения кредита.
+type AmortizationRow struct {
+	Number    int       `json:"number" example:"1"`
+	Date      time.Time `json:"date"`
+	Payment   Money     `json:"payment" swaggertype:"number" example:"34084.22"`
+	Principal Money     `json:"principal" swaggertype:"number" example:"2834.22"`
+	Interest  Money     `json:"interest" swaggertype:"number" example:"31250"`
+	// Balance — остаток основного долга после платежа
+	Balance Money `json:"balance" swaggertype:"number" example:"2997165.78"`
+	// Paid — платеж уже внесен: внесено не меньше платежей, чем его номер
+	Paid bool `json:"paid"`
+}
+
+// AmortizationSchedule — график погашения кредита.
+type AmortizationSchedule struct {
+	AccountID     int               `json:"account_id" example:"1"`
+	Currency      string            `json:"currency" example:"RUB"`
+	Payment       Money             `json:"payment" swaggertype:"number" example:"34084.22"`
+	TotalInterest Money             `json:"total_interest" swaggertype:"number" example:"5180212.8"`
+	Rows          []AmortizationRow `json:"rows"`
+}
+
+// LoanStatus — состояние погашения кредита.
+type LoanStatus struct {
+	AccountID int    `json:"account_id" example:"1"`
+	Currency  string `json:"currency" example:"RUB"`
+	Principal Money  `json:"principal" swaggertype:"number" example:"3000000"`
+	// RemainingBalance — остаток основного долга
+	RemainingBalance Money `json:"remaining_balance" swaggertype:"number" example:"2997165.78"`
+	PrincipalPaid    Money `json:"principal_paid" swaggertype:"number" example:"2834.22"`
+	InterestPaid     Money `json:"interest_paid" swaggertype:"number" example:"31250"`
+	PaymentsMade     int   `json:"payments_made" example:"1"`
+	// NextPaymentDate и NextPayment — следующий платеж по графику; нет, если график выполнен
+	NextPaymentDate *time.Time `json:"next_payment_date,omitempty"`
+	NextPayment     Money      `json:"next_payment,omitempty" swaggertype:"number" example:"34084.22"`
+}
+
+// LoanPayment — платеж по кредиту: перевод основного долга на счет кредита и расход на проценты.
+type LoanPayment struct {
+	ID            int       `json:"id"`
+	AccountID     int       `json:"account_id" example:"1"`
+	FromAccountID int       `json:"from_account_id" example:"2"`
+	Amount        Money     `json:"amount" swaggertype:"number" example:"34084.22"`
+	Principal     Money     `json:"principal" swaggertype:"number" example:"2834.22"`
+	Interest      Money     `json:"interest" swaggertype:"number" example:"31250"`
+	Date          time.Time `json:"date"`
+	TransferID    int       `json:"transfer_id" example:"7"`
+	// InterestTransactionID — расход на проценты; нет, если проценты не начислены
+	InterestTransactionID int `json:"interest_transaction_id,omitempty" example:"43"`
+}
+
+// Transfer — перевод между счетами пользователя: расход на счете-источнике и доход на счете-получателе,
+// которые не учитываются в доходах и расходах.
+type Transfer struct {
+	ID            int `json:"id"`
+	FromAccountID int `json:"from_account_id" example:"1"`
+	ToAccountID   int `json:"to_account_id" example:"2"`
+	// Amount и Currency — сумма, списанная со счета-источника, в его валюте
+	Amount   Money  `json:"amount" swaggertype:"number" example:"1000"`
+	Currency string `json:"currency" example:"RUB"`
+	// ToAmount и ToCurrency — сумма, зачисленная на счет-получатель, в его валюте
+	ToAmount   Money  `json:"to_amount" swaggertype:"number" example:"10.5"`
+	ToCurrency string `json:"to_currency" example:"USD"`
+	// FXRate — курс перевода между валютами: ToAmount = Amount * FXRate; только для счетов в разных валютах
+	FXRate      float64   `json:"fx_rate,omitempty" example:"0.0105"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description" example:"Пополнение карты"`
+	// FromTransactionID и ToTransactionID — транзакции перевода на счетах
+	FromTransactionID int `json:"from_transaction_id" example:"41"`
+	ToTransactionID   int `json:"to_transaction_id" example:"42"`
+}
+
+// NetWorth — чистые активы пользователя: остатки всех счетов в базовой валюте.
+// К остатку инвестиционного и криптовалютного счета прибавляется текущая стоимость его позиций.
+// Счета с положительным остатком считаются активами, с отрицательным — обязательствами.
+type NetWorth struct {
+	Currency    string `json:"currency" example:"RUB"`
+	Assets      Money  `json:"assets" swaggertype:"number" example:"30250.5"`
+	Liabilities Money  `json:"liabilities" swaggertype:"number" example:"5000"`
+	NetWorth    Money  `json:"net_worth" swaggertype:"number" example:"25250.5"`
+}
+
+// NetWorthHistory — чистые активы на конец интервалов периода по ежедневным снимкам остатков.
+type NetWorthHistory struct {
+	From string `json:"from" example:"2024-08-01"`
+	To   string `json:"to" example:"2025-07-31"`
+	// GroupBy — длина интервала: day, week (с понедельника), month, quarter или year
+	GroupBy string `json:"group_by" example:"month"`
+	// Currency — базовая валюта пользователя, в которую пересчитаны остатки
+	Currency string                 `json:"currency" example:"RUB"`
+	Points   []NetWorthHistoryPoint `json:"points"`
+}
+
+// NetWorthHistoryPoint — чистые активы на последний день интервала, за который сохранен снимок остатков.
+type NetWorthHistoryPoint struct {
+	// Start — первый день интервала, Date — день снимка в формате YYYY-MM-DD
+	Start       string `json:"start" example:"2025-07-01"`
+	Date        string `json:"date" example:"2025-07-31"`
+	Assets      Money  `json:"assets" swaggertype:"number" example:"30250.5"`
+	Liabilities Money  `json:"liabilities" swaggertype:"number" example:"5000"`
+	NetWorth    Money  `json:"net_worth" swaggertype:"number" example:"25250.5"`
+}
+
+// Holding — позиция инвестиционного или криптовалютного счета: количество бумаг или монет и сумма, уплаченная за них.
+type Holding struct {
+	ID        int `json:"id"`
+	AccountID int `json:"account_id" example:"1"`
+	// Ticker — тикер бумаги или символ криптовалюты
+	Ticker string `json:"ticker" example:"SBER"`
+	// Quantity — количество бумаг или монет; может быть дробным
+	Quantity float64 `json:"quantity" example:"100"`
+	// CostBasis — сумма покупки всех бумаг позиции в валюте счета
+	CostBasis Money     `json:"cost_basis" swaggertype:"number" example:"25000"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HoldingValue — текущая стоимость позиции в валюте счета.
+type HoldingValue struct {
+	HoldingID int     `json:"holding_id" example:"1"`
+	Ticker    string  `json:"ticker" example:"SBER"`
+	Quantity  float64 `json:"quantity" example:"100"`
+	CostBasis Money   `json:"cost_basis" swaggertype:"number" example:"25000"`
+	// Priced — цена бумаги известна; без цены позиция оценивается по сумме покупки
+	Priced bool `json:"priced"`
+	// Price — цена одной бумаги в валюте счета
+	Price        float64 `json:"price,omitempty" example:"310.25"`
+	MarketValue  Money   `json:"market_value" swaggertype:"number" example:"31025"`
+	UnrealizedPL Money   `json:"unrealized_pl" swaggertype:"number" example:"6025"`
+}
+
+// InvestmentValuation — текущая стоимость позиций инвестиционного или криптовалютного счета
+// и нереализованная прибыль или убыток.
+type InvestmentValuation struct {
+	AccountID    int            `json:"account_id" example:"1"`
+	Currency     string         `json:"currency" example:"RUB"`
+	CostBasis    Money          `json:"cost_basis" swaggertype:"number" example:"25000"`
+	MarketValue  Money          `json:"market_value" swaggertype:"number" example:"31025"`
+	UnrealizedPL Money          `json:"unrealized_pl" swaggertype:"number" example:"6025"`
+	Holdings     []HoldingValue `json:"holdings"`
+}
+
+// ValuationPoint — сохраненная стоимость позиций счета на конец дня.
+type ValuationPoint struct {
+	Date        time.Time `json:"date" example:"2025-07-01T00:00:00Z"`
+	Currency    string    `json:"currency" example:"RUB"`
+	CostBasis   Money     `json:"cost_basis" swaggertype:"number" example:"25000"`
+	MarketValue Money     `json:"market_value" swaggertype:"number" example:"31025"`
+}
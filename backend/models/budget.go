@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// Budget — лимит расходов категории или тега на период.
+type Budget struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+	// CategoryID или TagID — категория или тег бюджета; задано одно из двух
+	CategoryID int `json:"category_id,omitempty" example:"3"`
+	TagID      int `json:"tag_id,omitempty" example:"7"`
+	// Period — вид периода: week, month, quarter или custom
+	Period string `json:"period" example:"month"`
+	// StartDate и EndDate — первый и последний день периода включительно
+	StartDate time.Time `json:"start_date" example:"2025-07-01T00:00:00Z"`
+	EndDate   time.Time `json:"end_date" example:"2025-07-31T00:00:00Z"`
+	// Month — месяц бюджета в формате YYYY-MM; только у месячных бюджетов
+	Month string `json:"month,omitempty" example:"2025-07"`
+	Limit Money  `json:"limit" swaggertype:"number" example:"20000"`
+	// Currency — валюта лимита; расходы в других валютах в бюджете не учитываются
+	Currency string `json:"currency" example:"RUB"`
+	// Rollover — остаток бюджета переносится на следующий период того же вида, перерасход уменьшает его лимит
+	Rollover bool `json:"rollover"`
+	// Carryover — перенос с прошлого периода; отрицательный после перерасхода
+	Carryover Money `json:"carryover" swaggertype:"number" example:"1500"`
+}
+
+// BudgetTemplate — шаблон бюджетов месяца.
+type BudgetTemplate struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name" example:"Обычный месяц"`
+	// AutoApply — в начале каждого месяца по шаблону создаются бюджеты месяца
+	AutoApply bool                 `json:"auto_apply"`
+	Items     []BudgetTemplateItem `json:"items"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// BudgetTemplateItem — лимит категории в шаблоне бюджетов.
+type BudgetTemplateItem struct {
+	CategoryID int    `json:"category_id" binding:"gt=0" example:"3"`
+	Limit      Money  `json:"limit" binding:"gt=0,maxamount" swaggertype:"number" example:"20000"`
+	Currency   string `json:"currency" binding:"omitempty,currency" example:"RUB"`
+	Rollover   bool   `json:"rollover"`
+}
+
+// BudgetProgress — бюджет категории или тега и фактические расходы по нему за период.
+type BudgetProgress struct {
+	ID           int       `json:"id"`
+	CategoryID   int       `json:"category_id,omitempty" example:"3"`
+	CategoryName string    `json:"category_name,omitempty" example:"Продукты"`
+	TagID        int       `json:"tag_id,omitempty" example:"7"`
+	TagName      string    `json:"tag_name,omitempty" example:"vacation-2025"`
+	Period       string    `json:"period" example:"month"`
+	StartDate    time.Time `json:"start_date" example:"2025-07-01T00:00:00Z"`
+	EndDate      time.Time `json:"end_date" example:"2025-07-31T00:00:00Z"`
+	Month        string    `json:"month,omitempty" example:"2025-07"`
+	Limit        Money     `json:"limit" swaggertype:"number" example:"20000"`
+	Currency     string    `json:"currency" example:"RUB"`
+	Rollover     bool      `json:"rollover"`
+	Carryover    Money     `json:"carryover" swaggertype:"number" example:"1500"`
+	// Available — лимит с учетом переноса с прошлого периода
+	Available Money `json:"available" swaggertype:"number" example:"21500"`
+	// Spent — расходы категории или по тегу за период в валюте бюджета за вычетом возвратов
+	Spent Money `json:"spent" swaggertype:"number" example:"15250.5"`
+	// Remaining — неизрасходованная часть доступной суммы; отрицательная, если она превышена
+	Remaining Money `json:"remaining" swaggertype:"number" example:"6249.5"`
+	Exceeded  bool  `json:"exceeded"`
+	// Elapsed — прошедшая доля периода от 0 до 1; расходы идут с опережением, если spent/available больше нее
+	Elapsed float64 `json:"elapsed" example:"0.55"`
+}
+
+// BudgetVsActual — бюджеты и фактические расходы по категориям и месяцам периода.
+type BudgetVsActual struct {
+	// From и To — первый и последний месяцы периода в формате YYYY-MM
+	From string              `json:"from" example:"2025-01"`
+	To   string              `json:"to" example:"2025-06"`
+	Rows []BudgetVsActualRow `json:"rows"`
+	// Totals — итоги по месяцам в каждой валюте
+	Totals []BudgetVsActualTotal `json:"totals"`
+}
+
+// BudgetVsActualRow — бюджет и расходы категории за месяц в одной валюте.
+// Расходы категорий без бюджета входят с нулевым бюджетом.
+type BudgetVsActualRow struct {
+	Month        string `json:"month" example:"2025-07"`
+	CategoryID   int    `json:"category_id" example:"3"`
+	CategoryName string `json:"category_name" example:"Продукты"`
+	Currency     string `json:"currency" example:"RUB"`
+	Budgeted     Money  `json:"budgeted" swaggertype:"number" example:"20000"`
+	// Actual — расходы за вычетом возвратов
+	Actual Money `json:"actual" swaggertype:"number" example:"23000"`
+	// Variance — бюджет минус расходы; отрицательная при перерасходе
+	Variance Money `json:"variance" swaggertype:"number" example:"-3000"`
+}
+
+// BudgetVsActualTotal — итог месяца в одной валюте.
+type BudgetVsActualTotal struct {
+	Month    string `json:"month" example:"2025-07"`
+	Currency string `json:"currency" example:"RUB"`
+	Budgeted Money  `json:"budgeted" swaggertype:"number" example:"80000"`
+	Actual   Money  `json:"actual" swaggertype:"number" example:"76500"`
+	Variance Money  `json:"variance" swaggertype:"number" example:"3500"`
+}
@@ -1,7 +1,63 @@
 package models
 
+import "time"
+
 type Category struct {
 	ID     int    `json:"id"`
 	UserID int    `json:"user_id"`
 	Name   string `json:"name"`
+	// Icon — имя значка из набора /categories/icons; Color — цвет в формате #rrggbb.
+	// Пустые значения означают, что значок и цвет не заданы
+	Icon  string `json:"icon" example:"cart"`
+	Color string `json:"color" example:"#4caf50"`
+	// System — общая категория, доступная всем пользователям только для чтения; ее user_id равен 0
+	System bool `json:"system"`
+	// TransactionCount, TotalSpent и Totals заполняются только в списке категорий с with_stats=true.
+	// TotalSpent — расходы за вычетом возвратов в базовой валюте пользователя; отсутствует, если курсы недоступны.
+	// Totals — суммы доходов и расходов по валютам
+	TransactionCount *int                `json:"transaction_count,omitempty" example:"42"`
+	TotalSpent       *Money              `json:"total_spent,omitempty" swaggertype:"number" example:"52500"`
+	Totals           []TransactionTotals `json:"totals,omitempty"`
+}
+
+// CategoryKeyword — ключевое слово, по которому транзакциям назначается категория.
+type CategoryKeyword struct {
+	ID         int    `json:"id" example:"1"`
+	CategoryID int    `json:"category_id" example:"3"`
+	Keyword    string `json:"keyword" example:"Пятерочка"`
+}
+
+// CategoryStats — статистика транзакций категории за период.
+type CategoryStats struct {
+	CategoryID int    `json:"category_id" example:"1"`
+	Name       string `json:"name" example:"Продукты"`
+	// Period — month, quarter, year или all
+	Period string `json:"period" example:"year"`
+	// From — начало периода; не задано для all
+	From *time.Time `json:"from,omitempty"`
+	// To — конец периода (не включительно): начало следующего месяца
+	To     time.Time             `json:"to"`
+	Totals []CategoryStatsTotals `json:"totals"`
+	// Monthly — суммы по месяцам периода в каждой валюте; месяцы без транзакций входят с нулями
+	Monthly []CategoryMonthStats `json:"monthly"`
+}
+
+// CategoryStatsTotals — итоги категории за период в одной валюте.
+type CategoryStatsTotals struct {
+	Currency string `json:"currency" example:"RUB"`
+	Count    int    `json:"count" example:"42"`
+	Income   Money  `json:"income" swaggertype:"number" example:"0"`
+	// Expense — расходы за вычетом привязанных к ним возвратов
+	Expense Money `json:"expense" swaggertype:"number" example:"52500"`
+	// Average — средняя сумма транзакции
+	Average Money `json:"average" swaggertype:"number" example:"1250"`
+}
+
+// CategoryMonthStats — суммы категории за месяц в одной валюте.
+type CategoryMonthStats struct {
+	Month    string `json:"month" example:"2025-01"`
+	Currency string `json:"currency" example:"RUB"`
+	Count    int    `json:"count" example:"4"`
+	Income   Money  `json:"income" swaggertype:"number" example:"0"`
+	Expense  Money  `json:"expense" swaggertype:"number" example:"4800"`
 }
@@ -1,16 +1,352 @@
 package models
 
-type CreateTransaction struct {
-	Amount     float64 `json:"amount"`
-	Type       string  `json:"type"`
-	CaregoryID int     `json:"category_id"`
+import "time"
+
+// CreateTransactionRequest — данные транзакции при создании и полном обновлении. Правила, связывающие
+// несколько полей, — возврат, запланированная дата и сумма в валюте покупки — проверяются в пакете api.
+type CreateTransactionRequest struct {
+	Amount      Money  `json:"amount" binding:"gt=0,maxamount" swaggertype:"number" example:"1250.5"`
+	Type        string `json:"type" binding:"oneof=income expense" example:"expense"`
+	CategoryID  int    `json:"category_id" binding:"gt=0" example:"1"`
+	Description string `json:"description" binding:"max=1000" example:"Продукты на неделю"`
+	// Tags — имена тегов до 50 символов
+	Tags []string `json:"tags" binding:"dive,notblank,max=50" example:"vacation,work"`
+	// Currency — код валюты ISO 4217; по умолчанию базовая валюта пользователя
+	Currency string `json:"currency" binding:"omitempty,currency" example:"USD"`
+	// Date — дата транзакции; по умолчанию текущее время
+	Date time.Time `json:"date" example:"2025-07-01T00:00:00Z"`
+	// Planned — запланированная транзакция; требует дату в будущем
+	Planned bool `json:"planned" example:"false"`
+	// Status — pending, cleared или reconciled; по умолчанию cleared при создании и прежний при обновлении
+	Status string `json:"status" binding:"omitempty,oneof=pending cleared reconciled" example:"pending"`
+	// PayeeID — ID контрагента; вместо него можно передать имя в Payee до 100 символов
+	PayeeID int    `json:"payee_id" binding:"gte=0" example:"1"`
+	Payee   string `json:"payee" binding:"max=100" example:"Пятерочка"`
+	// LinkedTransactionID — ID расхода, возвратом по которому является этот доход
+	LinkedTransactionID int `json:"linked_transaction_id" binding:"gte=0" example:"42"`
+	// OriginalAmount и OriginalCurrency — сумма и валюта покупки за границей; передаются вместе
+	OriginalAmount   Money  `json:"original_amount" binding:"required_with=OriginalCurrency,gte=0,maxamount" swaggertype:"number" example:"25.5"`
+	OriginalCurrency string `json:"original_currency" binding:"required_with=OriginalAmount,omitempty,currency,nefield=Currency" example:"EUR"`
+	// FXRate — курс пересчета в валюту транзакции; если не задан, вычисляется как amount / original_amount
+	FXRate float64 `json:"fx_rate" binding:"gte=0,lt=1e10" example:"98.5"`
+	// AccountID — счет транзакции; валюта транзакции должна совпадать с валютой счета
+	AccountID int `json:"account_id" binding:"gte=0" example:"1"`
+}
+
+// Transaction возвращает транзакцию с данными запроса.
+func (r CreateTransactionRequest) Transaction() Transaction {
+	return Transaction{
+		Amount:              r.Amount,
+		Type:                r.Type,
+		CategoryID:          r.CategoryID,
+		Description:         r.Description,
+		Tags:                r.Tags,
+		Currency:            r.Currency,
+		Date:                r.Date,
+		Planned:             r.Planned,
+		Status:              r.Status,
+		PayeeID:             r.PayeeID,
+		Payee:               r.Payee,
+		LinkedTransactionID: r.LinkedTransactionID,
+		OriginalAmount:      r.OriginalAmount,
+		OriginalCurrency:    r.OriginalCurrency,
+		FXRate:              r.FXRate,
+		AccountID:           r.AccountID,
+	}
+}
+
+// PatchTransaction — частичное обновление транзакции: изменяются только переданные поля.
+type PatchTransaction struct {
+	Amount      *Money     `json:"amount" swaggertype:"number" example:"1250.5"`
+	Type        *string    `json:"type" example:"expense"`
+	CategoryID  *int       `json:"category_id" example:"1"`
+	Date        *time.Time `json:"date" example:"2025-07-01T00:00:00Z"`
+	Description *string    `json:"description" example:"Продукты на неделю"`
+	Currency    *string    `json:"currency" example:"USD"`
+	Tags        *[]string  `json:"tags" example:"vacation,work"`
+	Planned     *bool      `json:"planned" example:"false"`
+	Status      *string    `json:"status" example:"cleared"`
+	PayeeID     *int       `json:"payee_id" example:"1"`
+	// Payee — имя контрагента; пустая строка убирает контрагента
+	Payee *string `json:"payee" example:"Пятерочка"`
+	// LinkedTransactionID — ID расхода для возврата; 0 убирает связь
+	LinkedTransactionID *int `json:"linked_transaction_id" example:"42"`
+	// OriginalAmount, OriginalCurrency и FXRate — сумма в валюте покупки и курс; 0 и пустая строка убирают их
+	OriginalAmount   *Money   `json:"original_amount" swaggertype:"number" example:"25.5"`
+	OriginalCurrency *string  `json:"original_currency" example:"EUR"`
+	FXRate           *float64 `json:"fx_rate" example:"98.5"`
+	// AccountID — ID счета; 0 убирает транзакцию со счета
+	AccountID *int `json:"account_id" example:"1"`
+}
+
+// ReassignCategoryRequest задает категорию, в которую переносятся транзакции, и необязательный период.
+type ReassignCategoryRequest struct {
+	TargetCategoryID int        `json:"target_category_id" binding:"gt=0" example:"2"`
+	DateFrom         *time.Time `json:"date_from" example:"2025-01-01T00:00:00Z"`
+	DateTo           *time.Time `json:"date_to" example:"2025-01-31T23:59:59Z"`
+}
+
+// PatchCategory — частичное обновление категории.
+type PatchCategory struct {
+	Name *string `json:"name" binding:"omitnil,notblank" example:"Продукты"`
+	// Icon и Color — пустая строка сбрасывает значок или цвет
+	Icon  *string `json:"icon" example:"cart"`
+	Color *string `json:"color" example:"#4caf50"`
+}
+
+// MarkClearedRequest задает ожидающие транзакции, отмечаемые проведенными:
+// списком ID и/или все по дату выписки включительно. Хотя бы одно условие обязательно.
+type MarkClearedRequest struct {
+	IDs    []int      `json:"ids" binding:"max=1000,dive,gt=0" example:"1,2,3"`
+	DateTo *time.Time `json:"date_to" example:"2025-01-31T23:59:59Z"`
+}
+
+// DeleteTransactionsRequest задает удаляемые транзакции: списком ID и/или фильтром.
+// Условия объединяются через И; хотя бы одно из них обязательно.
+type DeleteTransactionsRequest struct {
+	IDs        []int      `json:"ids" binding:"dive,gt=0" example:"1,2,3"`
+	Type       string     `json:"type" binding:"omitempty,oneof=income expense" example:"expense"`
+	CategoryID int        `json:"category_id" binding:"gte=0" example:"1"`
+	DateFrom   *time.Time `json:"date_from" example:"2025-01-01T00:00:00Z"`
+	DateTo     *time.Time `json:"date_to" example:"2025-01-31T23:59:59Z"`
+}
+
+// DuplicateTransactionRequest — поля, переопределяемые при повторе транзакции.
+type DuplicateTransactionRequest struct {
+	Date   *time.Time `json:"date" example:"2025-06-14T10:00:00Z"`
+	Amount *Money     `json:"amount" binding:"omitnil,gt=0,maxamount" swaggertype:"number" example:"1250.5"`
+}
+
+type CreatePayee struct {
+	Name string `json:"name" binding:"notblank,max=100" example:"Пятерочка"`
+}
+
+type CreateTag struct {
+	Name string `json:"name" binding:"notblank,max=50" example:"vacation"`
 }
 
 type CreateUser struct {
-	Login    string `json:"username"`
-	Password string `json:"password"`
+	Login string `json:"username" binding:"required"`
+	// Password — не короче 6 символов
+	Password string `json:"password" binding:"min=6"`
+	Email    string `json:"email,omitempty" binding:"omitempty,email"`
+	// Код приглашения, обязателен при REGISTRATION_MODE=invite
+	InviteCode string `json:"invite_code,omitempty"`
+	// Токен CAPTCHA-виджета, обязателен при включенной CAPTCHA
+	CaptchaToken string `json:"captcha_token,omitempty"`
+}
+
+type CreateInvite struct {
+	// Срок действия приглашения в часах; 0 — бессрочно
+	ExpiresInHours int `json:"expires_in_hours" binding:"gte=0" example:"72"`
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email" example:"new@example.com"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required" example:"john@example.com"`
+}
+
+type LoginRequest struct {
+	Username   string `json:"username" binding:"required" example:"john_doe"`
+	Password   string `json:"password" binding:"required" example:"password123"`
+	RememberMe bool   `json:"remember_me" example:"false"`
+	// Токен CAPTCHA-виджета, требуется после нескольких неудачных попыток входа
+	CaptchaToken string `json:"captcha_token,omitempty"`
 }
 
 type CreateCategory struct {
-	Name string `json:"name"`
+	Name string `json:"name" binding:"required" example:"Продукты"`
+	// Icon — имя значка из набора /categories/icons
+	Icon string `json:"icon" example:"cart"`
+	// Color — цвет в формате #rrggbb
+	Color string `json:"color" example:"#4caf50"`
+}
+
+type CreateCategoryKeyword struct {
+	Keyword string `json:"keyword" binding:"notblank,max=100" example:"Пятерочка"`
+}
+
+type SetBaseCurrencyRequest struct {
+	Currency string `json:"currency" binding:"currency" example:"EUR"`
+}
+
+type SetMonthStartRequest struct {
+	// MonthStart — день месяца от 1 до 28
+	MonthStart int `json:"month_start" binding:"gte=1,lte=28" example:"25"`
+}
+
+type SetLanguageRequest struct {
+	// Language — код языка: en или ru
+	Language string `json:"language" binding:"language" example:"ru"`
+}
+
+type ResolveDuplicateRequest struct {
+	// Action — confirm (это дубликат, транзакция перемещается в корзину) или dismiss (не дубликат, отметка снимается)
+	Action string `json:"action" binding:"oneof=confirm dismiss" example:"dismiss"`
+}
+
+// ScanReceiptRequest — данные QR-кода кассового чека.
+type ScanReceiptRequest struct {
+	// QR — строка из QR-кода чека: t=20250115T1230&s=1234.50&fn=...&i=...&fp=...&n=1
+	QR string `json:"qr" binding:"required" example:"t=20250115T1230&s=1234.50&fn=9999078900012345&i=12345&fp=1234567890&n=1"`
+	// CategoryID — категория транзакции; если не указана, подбирается по ключевым словам категорий
+	CategoryID int `json:"category_id" binding:"gte=0" example:"1"`
+	// Description — описание транзакции; продавец из чека становится контрагентом
+	Description string `json:"description" example:"Продукты на неделю"`
+}
+
+// CreateAccount — данные нового счета.
+type CreateAccount struct {
+	Name string `json:"name" binding:"notblank" example:"Наличные"`
+	// Currency — код валюты ISO 4217; по умолчанию базовая валюта пользователя
+	Currency       string `json:"currency" binding:"omitempty,currency" example:"RUB"`
+	InitialBalance Money  `json:"initial_balance" binding:"absamount" swaggertype:"number" example:"1000"`
+	// Type — regular (по умолчанию), credit_card, loan, investment или crypto; не меняется после создания
+	Type string `json:"type" binding:"omitempty,oneof=regular credit_card loan investment crypto" example:"regular"`
+	// CreditLimit, StatementDay и PaymentDueDay обязательны для кредитных карт и не задаются для других счетов.
+	// StatementDay и PaymentDueDay — дни месяца от 1 до 28: закрытие выписки и срок платежа по ней
+	CreditLimit   Money `json:"credit_limit" swaggertype:"number" example:"100000"`
+	StatementDay  int   `json:"statement_day" example:"25"`
+	PaymentDueDay int   `json:"payment_due_day" example:"15"`
+	// Loan — условия кредита; обязательны для кредитов и не задаются для других счетов.
+	// Начальный остаток кредита — минус сумма кредита
+	Loan *LoanTerms `json:"loan,omitempty"`
+}
+
+// ShareAccount — пользователь, которому открывается доступ к счету, и его роль.
+type ShareAccount struct {
+	Username string `json:"username" binding:"notblank" example:"jane_doe"`
+	// Role — viewer или editor
+	Role string `json:"role" binding:"oneof=viewer editor" example:"editor"`
+}
+
+// UpdateAccount — изменяемые поля счета. Валюта и тип счета не меняются.
+type UpdateAccount struct {
+	Name           string `json:"name" binding:"notblank" example:"Наличные"`
+	InitialBalance Money  `json:"initial_balance" binding:"absamount" swaggertype:"number" example:"1000"`
+	// Условия кредитной карты, как в CreateAccount
+	CreditLimit   Money      `json:"credit_limit" swaggertype:"number" example:"100000"`
+	StatementDay  int        `json:"statement_day" example:"25"`
+	PaymentDueDay int        `json:"payment_due_day" example:"15"`
+	Loan          *LoanTerms `json:"loan,omitempty"`
+}
+
+// CreateLoanPayment — данные платежа по кредиту.
+type CreateLoanPayment struct {
+	// FromAccountID — счет, с которого вносится платеж; валюта счета должна совпадать с валютой кредита
+	FromAccountID int `json:"from_account_id" binding:"gt=0" example:"2"`
+	// Amount — сумма платежа; по умолчанию платеж по графику
+	Amount Money `json:"amount" binding:"gte=0,maxamount" swaggertype:"number" example:"34084.22"`
+	// CategoryID — категория расхода на проценты; обязательна для кредитов с ненулевой ставкой
+	CategoryID  int       `json:"category_id" binding:"gte=0" example:"5"`
+	Date        time.Time `json:"date" example:"2025-08-01T00:00:00Z"`
+	Description string    `json:"description" binding:"max=1000" example:"Платеж по ипотеке"`
+}
+
+// CreateAdjustment — данные корректировки остатка счета.
+type CreateAdjustment struct {
+	// Balance — фактический остаток счета, к которому приводится остаток по транзакциям
+	Balance Money `json:"balance" binding:"absamount" swaggertype:"number" example:"15000"`
+	// Date — дата корректировки; по умолчанию текущее время
+	Date        time.Time `json:"date" example:"2025-07-01T00:00:00Z"`
+	Description string    `json:"description" binding:"max=1000" example:"Сверка с банком"`
+}
+
+// CreateTransfer — данные перевода между счетами. Для счетов в разных валютах
+// передается зачисленная сумма to_amount или курс fx_rate.
+type CreateTransfer struct {
+	FromAccountID int   `json:"from_account_id" binding:"gt=0" example:"1"`
+	ToAccountID   int   `json:"to_account_id" binding:"gt=0" example:"2"`
+	Amount        Money `json:"amount" binding:"gt=0,maxamount" swaggertype:"number" example:"1000"`
+	// ToAmount — сумма, зачисленная на счет-получатель; по умолчанию amount * fx_rate
+	ToAmount Money `json:"to_amount" binding:"gte=0,maxamount" swaggertype:"number" example:"10.5"`
+	// FXRate — курс перевода; по умолчанию to_amount / amount
+	FXRate      float64 `json:"fx_rate" binding:"gte=0,lt=1e10" example:"0.0105"`
+	Description string  `json:"description" binding:"max=1000" example:"Пополнение карты"`
+	// Date — дата перевода; по умолчанию текущее время
+	Date time.Time `json:"date" example:"2025-07-01T00:00:00Z"`
+}
+
+// CreateHolding — данные новой позиции инвестиционного или криптовалютного счета.
+type CreateHolding struct {
+	// Ticker — тикер бумаги или символ криптовалюты у провайдера котировок; уникален в пределах счета
+	Ticker string `json:"ticker" example:"SBER"`
+	// Quantity — количество бумаг, не больше 1e12
+	Quantity float64 `json:"quantity" binding:"gt=0,lte=1e12" example:"100"`
+	// CostBasis — сумма покупки всех бумаг позиции в валюте счета
+	CostBasis Money `json:"cost_basis" binding:"gte=0,maxamount" swaggertype:"number" example:"25000"`
+}
+
+// UpdateHolding — изменяемые поля позиции. Тикер не меняется.
+type UpdateHolding struct {
+	Quantity  float64 `json:"quantity" binding:"gt=0,lte=1e12" example:"150"`
+	CostBasis Money   `json:"cost_basis" binding:"gte=0,maxamount" swaggertype:"number" example:"40000"`
+}
+
+// CreateBudget — данные нового бюджета. Бюджет категории или тега на период одного вида с одной даты может быть только один.
+type CreateBudget struct {
+	// CategoryID или TagID — категория или тег бюджета; задается одно из двух.
+	// В бюджет тега входят расходы с этим тегом во всех категориях
+	CategoryID int `json:"category_id" binding:"gte=0" example:"3"`
+	TagID      int `json:"tag_id" binding:"gte=0" example:"0"`
+	// Period — week, month (по умолчанию), quarter или custom
+	Period string `json:"period" example:"month"`
+	// Month — месяц бюджета в формате YYYY-MM; для месячного бюджета вместо start_date
+	Month string `json:"month" example:"2025-07"`
+	// StartDate — день, неделя или квартал которого задают период; для custom — первый день
+	StartDate *time.Time `json:"start_date" example:"2025-07-14T00:00:00Z"`
+	// EndDate — последний день периода включительно; только для custom
+	EndDate *time.Time `json:"end_date" example:"2025-07-27T00:00:00Z"`
+	Limit   Money      `json:"limit" binding:"gt=0,maxamount" swaggertype:"number" example:"20000"`
+	// Currency — код валюты ISO 4217; по умолчанию базовая валюта пользователя
+	Currency string `json:"currency" binding:"omitempty,currency" example:"RUB"`
+	// Rollover — переносить остаток или перерасход на следующий период
+	Rollover bool `json:"rollover" example:"false"`
+}
+
+// UpdateBudget — изменяемые поля бюджета. Категория или тег, период и валюта не меняются.
+type UpdateBudget struct {
+	Limit    Money `json:"limit" binding:"gt=0,maxamount" swaggertype:"number" example:"25000"`
+	Rollover bool  `json:"rollover" example:"true"`
+}
+
+// CreateGoal — данные новой цели накоплений.
+type CreateGoal struct {
+	Name   string `json:"name" binding:"notblank,max=100" example:"Отпуск"`
+	Target Money  `json:"target" binding:"gt=0,maxamount" swaggertype:"number" example:"150000"`
+	// Currency — код валюты ISO 4217; по умолчанию валюта счета или базовая валюта пользователя
+	Currency string     `json:"currency" binding:"omitempty,currency" example:"RUB"`
+	Deadline *time.Time `json:"deadline" example:"2026-06-01T00:00:00Z"`
+	// AccountID — счет, на котором копятся деньги; необязателен
+	AccountID int `json:"account_id" binding:"gte=0" example:"2"`
+}
+
+// UpdateGoal — изменяемые поля цели. Валюта цели не меняется; незаданные срок и счет сбрасываются.
+type UpdateGoal struct {
+	Name      string     `json:"name" binding:"notblank,max=100" example:"Отпуск"`
+	Target    Money      `json:"target" binding:"gt=0,maxamount" swaggertype:"number" example:"150000"`
+	Deadline  *time.Time `json:"deadline" example:"2026-06-01T00:00:00Z"`
+	AccountID int        `json:"account_id" binding:"gte=0" example:"2"`
+}
+
+// CreateGoalContribution — взнос в цель: сумма или транзакция. Для транзакции сумма и дата не передаются.
+type CreateGoalContribution struct {
+	// Amount — сумма взноса в валюте цели; отрицательная — изъятие
+	Amount Money `json:"amount" binding:"absamount" swaggertype:"number" example:"5000"`
+	// Date — дата взноса; по умолчанию сегодня
+	Date        *time.Time `json:"date" example:"2025-07-01T00:00:00Z"`
+	Description string     `json:"description" binding:"max=1000" example:"Отложил с зарплаты"`
+	// TransactionID — транзакция в валюте цели, которая учитывается взносом
+	TransactionID int `json:"transaction_id" binding:"gte=0" example:"42"`
+}
+
+// CreateBudgetTemplate — данные шаблона бюджетов; при обновлении шаблон заменяется целиком.
+type CreateBudgetTemplate struct {
+	Name      string `json:"name" binding:"notblank,max=100" example:"Обычный месяц"`
+	AutoApply bool   `json:"auto_apply" example:"true"`
+	// Items — лимиты категорий; валюта по умолчанию — базовая валюта пользователя
+	Items []BudgetTemplateItem `json:"items" binding:"min=1,dive"`
 }
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Goal — цель накоплений с прогрессом по взносам.
+type Goal struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"user_id"`
+	Name     string `json:"name" example:"Отпуск"`
+	Target   Money  `json:"target" swaggertype:"number" example:"150000"`
+	Currency string `json:"currency" example:"RUB"`
+	// Deadline — срок, к которому нужно накопить сумму; может отсутствовать
+	Deadline *time.Time `json:"deadline,omitempty" example:"2026-06-01T00:00:00Z"`
+	// AccountID — счет, на котором копятся деньги; взносами могут быть только его транзакции
+	AccountID int       `json:"account_id,omitempty" example:"2"`
+	CreatedAt time.Time `json:"created_at"`
+	// Saved — сумма взносов; Remaining — сколько осталось накопить; Percent — процент выполнения
+	Saved     Money   `json:"saved" swaggertype:"number" example:"45000"`
+	Remaining Money   `json:"remaining" swaggertype:"number" example:"105000"`
+	Percent   float64 `json:"percent" example:"30"`
+	Completed bool    `json:"completed"`
+	// FirstContributionDate — дата первого взноса; от нее считается средний темп накоплений
+	FirstContributionDate *time.Time `json:"first_contribution_date,omitempty"`
+	// ProjectedDate — ожидаемая дата достижения цели при среднем темпе взносов
+	ProjectedDate *time.Time `json:"projected_date,omitempty"`
+	// RequiredMonthly — ежемесячный взнос, нужный, чтобы успеть к сроку
+	RequiredMonthly Money `json:"required_monthly,omitempty" swaggertype:"number" example:"10500"`
+	// OnTrack — цель будет достигнута к сроку при текущем темпе; задано только при сроке
+	OnTrack *bool `json:"on_track,omitempty"`
+}
+
+// GoalContribution — взнос в цель. Отрицательная сумма — изъятие.
+type GoalContribution struct {
+	ID          int       `json:"id"`
+	GoalID      int       `json:"goal_id"`
+	Amount      Money     `json:"amount" swaggertype:"number" example:"5000"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description" example:"Отложил с зарплаты"`
+	// TransactionID — транзакция, учтенная взносом; ее сумма, дата и описание
+	TransactionID int       `json:"transaction_id,omitempty" example:"42"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money — денежная сумма в сотых долях валюты (копейках, центах).
+// В JSON и в базе данных записывается десятичным числом с двумя знаками после точки,
+// поэтому суммы складываются и сравниваются без ошибок округления.
+type Money int64
+
+// NewMoney возвращает сумму из целой части и сотых: NewMoney(1250, 50) — 1250.50.
+func NewMoney(units, cents int64) Money {
+	if units < 0 {
+		return Money(units*100 - cents)
+	}
+	return Money(units*100 + cents)
+}
+
+// MoneyFromFloat округляет дробное значение до сотых. Используется только там,
+// где сумма уже вычислена в плавающей точке, например при пересчете по курсу.
+func MoneyFromFloat(value float64) Money {
+	return Money(math.Round(value * 100))
+}
+
+// ParseMoney разбирает десятичную запись суммы вида "-1234.5". Больше двух
+// знаков после точки не допускается: такая сумма не может быть сохранена точно.
+func ParseMoney(value string) (Money, error) {
+	s := value
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || len(fracPart) > 2 || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	for len(fracPart) < 2 {
+		fracPart += "0"
+	}
+
+	units := int64(0)
+	if intPart != "" {
+		var err error
+		// Запас в два разряда под сотые, чтобы умножение не переполнилось
+		if units, err = strconv.ParseInt(intPart, 10, 64); err != nil || units > math.MaxInt64/100-1 {
+			return 0, fmt.Errorf("amount %q is too large", value)
+		}
+	}
+	cents, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	m := Money(units*100 + cents)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String возвращает сумму с двумя знаками после точки: "1234.50".
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// Float64 возвращает сумму в плавающей точке для пересчета по курсу.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON принимает сумму числом; экспоненциальная запись не поддерживается.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	v, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan читает значение столбца NUMERIC, которое драйвер возвращает строкой.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	case float64:
+		*m = MoneyFromFloat(v)
+		return nil
+	case nil:
+		*m = 0
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Money", src)
+}
+
+func (m *Money) scanString(s string) error {
+	// Результат вычислений над NUMERIC может иметь больший масштаб: 12.500
+	if i := strings.Index(s, "."); i >= 0 && len(s)-i-1 > 2 {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value записывает сумму десятичной строкой, которую PostgreSQL приводит к NUMERIC без потерь.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParseMoney тестирует разбор и запись денежных сумм.
+func TestParseMoney(t *testing.T) {
+	tests := map[string]Money{
+		"0":        0,
+		"100":      NewMoney(100, 0),
+		"0.1":      NewMoney(0, 10),
+		"1250.5":   NewMoney(1250, 50),
+		"-42.05":   NewMoney(-42, 5),
+		".99":      NewMoney(0, 99),
+		"+7":       NewMoney(7, 0),
+		"12345.67": NewMoney(12345, 67),
+	}
+	for input, expected := range tests {
+		m, err := ParseMoney(input)
+		if err != nil || m != expected {
+			t.Errorf("ParseMoney(%q): expected %s, got %s (%v)", input, expected, m, err)
+		}
+	}
+
+	for _, input := range []string{"", "-", ".", "1.234", "1e3", "12a", "1.2.3", "99999999999999999999"} {
+		if _, err := ParseMoney(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+
+	if s := NewMoney(-5, 3).String(); s != "-5.03" {
+		t.Errorf("Expected -5.03, got %s", s)
+	}
+}
+
+// TestMoneyJSON тестирует запись суммы в JSON числом без ошибок округления.
+func TestMoneyJSON(t *testing.T) {
+	var totals TransactionTotals
+	// 0.1 + 0.2 в плавающей точке дает 0.30000000000000004
+	for _, amount := range []string{"0.1", "0.2"} {
+		var m Money
+		if err := json.Unmarshal([]byte(amount), &m); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", amount, err)
+		}
+		totals.Income += m
+	}
+	data, err := json.Marshal(totals)
+	if err != nil {
+		t.Fatalf("Failed to marshal totals: %v", err)
+	}
+	if string(data) != `{"currency":"","income":0.30,"expense":0.00}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`"10"`), &m); err == nil {
+		t.Error("Expected error for amount given as string")
+	}
+}
+
+// TestMoneyScan тестирует чтение значений столбца NUMERIC.
+func TestMoneyScan(t *testing.T) {
+	tests := map[interface{}]Money{
+		"1250.50":   NewMoney(1250, 50),
+		"0":         0,
+		"12.500":    NewMoney(12, 50),
+		"-3.000000": NewMoney(-3, 0),
+		int64(7):    NewMoney(7, 0),
+	}
+	for src, expected := range tests {
+		var m Money
+		if err := m.Scan(src); err != nil || m != expected {
+			t.Errorf("Scan(%v): expected %s, got %s (%v)", src, expected, m, err)
+		}
+	}
+	var m Money
+	if err := m.Scan([]byte("1.005")); err == nil {
+		t.Error("Expected error for value with more than two decimals")
+	}
+}
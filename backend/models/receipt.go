@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ReceiptItem — позиция кассового чека.
+type ReceiptItem struct {
+	Name     string  `json:"name" example:"Молоко 3,2% 1 л"`
+	Price    Money   `json:"price" swaggertype:"number" example:"89.99"`
+	Quantity float64 `json:"quantity" example:"2"`
+	Sum      Money   `json:"sum" swaggertype:"number" example:"179.98"`
+}
+
+// Receipt — кассовый чек, по которому создана транзакция.
+type Receipt struct {
+	TransactionID int `json:"transaction_id,omitempty" example:"42"`
+	// FN, FD и FP — номер фискального накопителя, номер и признак фискального документа
+	FN        string        `json:"fn" example:"9999078900012345"`
+	FD        string        `json:"fd" example:"12345"`
+	FP        string        `json:"fp" example:"1234567890"`
+	Seller    string        `json:"seller" example:"ООО \"Ромашка\""`
+	SellerINN string        `json:"seller_inn,omitempty" example:"7700000000"`
+	Date      time.Time     `json:"date"`
+	Total     Money         `json:"total" swaggertype:"number" example:"1234.5"`
+	Items     []ReceiptItem `json:"items"`
+}
+
+// ReceiptTransaction — транзакция, созданная по QR-коду чека, вместе с чеком.
+type ReceiptTransaction struct {
+	Transaction Transaction `json:"transaction"`
+	Receipt     Receipt     `json:"receipt"`
+}
@@ -0,0 +1,339 @@
+package models
+
+import "time"
+
+// PeriodSummary — итоги транзакций за период: в базовой валюте пользователя и по каждой валюте.
+type PeriodSummary struct {
+	// From и To — первый и последний дни периода в формате YYYY-MM-DD
+	From string `json:"from" example:"2025-07-01"`
+	To   string `json:"to" example:"2025-07-31"`
+	// Currency — базовая валюта пользователя, в которую пересчитаны итоги
+	Currency string `json:"currency" example:"RUB"`
+	Income   Money  `json:"income" swaggertype:"number" example:"50000"`
+	// Expense — расходы за вычетом возвратов
+	Expense Money `json:"expense" swaggertype:"number" example:"32000.5"`
+	// Net — доходы минус расходы
+	Net   Money `json:"net" swaggertype:"number" example:"17999.5"`
+	Count int   `json:"count" example:"42"`
+	// ByCurrency — итоги в каждой валюте без пересчета
+	ByCurrency []CurrencySummary `json:"by_currency"`
+}
+
+// CurrencySummary — итоги транзакций за период в одной валюте.
+type CurrencySummary struct {
+	Currency string `json:"currency" example:"RUB"`
+	Income   Money  `json:"income" swaggertype:"number" example:"50000"`
+	Expense  Money  `json:"expense" swaggertype:"number" example:"32000.5"`
+	Net      Money  `json:"net" swaggertype:"number" example:"17999.5"`
+	Count    int    `json:"count" example:"42"`
+}
+
+// TimeSeries — доходы и расходы по дням, неделям или месяцам периода.
+type TimeSeries struct {
+	From string `json:"from" example:"2024-08-01"`
+	To   string `json:"to" example:"2025-07-31"`
+	// GroupBy — длина интервала: day, week (с понедельника), month, quarter или year
+	GroupBy string `json:"group_by" example:"month"`
+	// Currency — базовая валюта пользователя, в которую пересчитаны суммы интервалов
+	Currency string            `json:"currency" example:"RUB"`
+	Points   []TimeSeriesPoint `json:"points"`
+}
+
+// TimeSeriesPoint — доходы и расходы за один интервал; интервалы без транзакций входят с нулями.
+type TimeSeriesPoint struct {
+	// Start — первый день интервала в формате YYYY-MM-DD
+	Start   string `json:"start" example:"2025-07-01"`
+	Income  Money  `json:"income" swaggertype:"number" example:"50000"`
+	Expense Money  `json:"expense" swaggertype:"number" example:"32000.5"`
+	Net     Money  `json:"net" swaggertype:"number" example:"17999.5"`
+	// ByCurrency — суммы интервала в каждой валюте без пересчета
+	ByCurrency []TransactionTotals `json:"by_currency"`
+}
+
+// SpendingTrends — расходы категорий за текущий период в сравнении со средними за предыдущие периоды.
+type SpendingTrends struct {
+	// Period — вид периода: week, month, quarter или year
+	Period string `json:"period" example:"month"`
+	// Start и End — первый и последний дни текущего периода
+	Start string `json:"start" example:"2025-07-01"`
+	End   string `json:"end" example:"2025-07-31"`
+	// Window — число предыдущих периодов, по которым считается среднее
+	Window int             `json:"window" example:"3"`
+	Trends []SpendingTrend `json:"trends"`
+}
+
+// SpendingTrend — расходы категории в одной валюте за текущий период и в среднем за предыдущие.
+type SpendingTrend struct {
+	CategoryID   int    `json:"category_id" example:"3"`
+	CategoryName string `json:"category_name" example:"Продукты"`
+	Currency     string `json:"currency" example:"RUB"`
+	// Current — расходы за текущий период за вычетом возвратов
+	Current Money `json:"current" swaggertype:"number" example:"24600"`
+	// Average — средние расходы за предыдущие периоды; периоды без расходов входят в среднее нулем
+	Average Money `json:"average" swaggertype:"number" example:"20000"`
+	// Change — изменение текущих расходов относительно среднего в процентах; отсутствует при нулевом среднем
+	Change *float64 `json:"change,omitempty" example:"23"`
+	// Direction — up, down, flat или new, если в предыдущих периодах расходов не было
+	Direction string `json:"direction" example:"up"`
+}
+
+// CashFlowForecast — прогноз суммарного остатка своих открытых счетов по дням.
+type CashFlowForecast struct {
+	// Currency — базовая валюта пользователя, в которой считается прогноз
+	Currency string `json:"currency" example:"RUB"`
+	// Balance — текущий суммарный остаток
+	Balance Money `json:"balance" swaggertype:"number" example:"120000"`
+	// DailyAverage — средний чистый поток за день (доходы минус расходы) за последние history_days дней
+	DailyAverage Money `json:"daily_average" swaggertype:"number" example:"-350.25"`
+	HistoryDays  int   `json:"history_days" example:"90"`
+	// Points — прогноз на каждый день, начиная с завтрашнего
+	Points []ForecastPoint `json:"points"`
+	// NegativeDate — первый день, в который прогнозируемый остаток становится отрицательным
+	NegativeDate string `json:"negative_date,omitempty" example:"2025-09-14"`
+}
+
+// ForecastPoint — прогнозируемый остаток на конец дня.
+type ForecastPoint struct {
+	Date string `json:"date" example:"2025-07-16"`
+	// Planned — сумма запланированных на день транзакций: доходы со знаком плюс, расходы со знаком минус
+	Planned Money `json:"planned" swaggertype:"number" example:"-15000"`
+	Balance Money `json:"balance" swaggertype:"number" example:"104649.75"`
+}
+
+// ReportQuery — описание пользовательского отчета: фильтры транзакций, измерения группировки и показатели.
+type ReportQuery struct {
+	// GroupBy — измерения: category, payee, account, tag, currency, type, day, week, month, quarter или year
+	GroupBy []string `json:"group_by" example:"month,category"`
+	// Metrics — показатели: count, income, expense, net, average, min или max
+	Metrics []string           `json:"metrics" example:"expense,count"`
+	Filters ReportQueryFilters `json:"filters"`
+	// OrderBy — измерение или показатель из запроса для сортировки; по умолчанию строки упорядочены по измерениям
+	OrderBy string `json:"order_by" example:"expense"`
+	Desc    bool   `json:"desc"`
+	// Limit — наибольшее число строк, по умолчанию 1000, не больше 10000
+	Limit int `json:"limit" binding:"gte=0,lte=10000" example:"100"`
+}
+
+// ReportQueryFilters — условия отбора транзакций пользовательского отчета; пустые условия не ограничивают отбор.
+type ReportQueryFilters struct {
+	// DateFrom и DateTo — первый и последний дни периода включительно
+	DateFrom    *time.Time `json:"date_from" example:"2025-01-01T00:00:00Z"`
+	DateTo      *time.Time `json:"date_to" example:"2025-06-30T00:00:00Z"`
+	Type        string     `json:"type" binding:"omitempty,oneof=income expense" example:"expense"`
+	CategoryIDs []int      `json:"category_ids" binding:"dive,gt=0" example:"3,5"`
+	AccountIDs  []int      `json:"account_ids" binding:"dive,gt=0" example:"1"`
+	PayeeIDs    []int      `json:"payee_ids" binding:"dive,gt=0" example:"2"`
+	// Tags — транзакция подходит, если у нее есть хотя бы один из тегов
+	Tags      []string `json:"tags" example:"vacation"`
+	Currency  string   `json:"currency" binding:"omitempty,currency" example:"RUB"`
+	MinAmount *Money   `json:"min_amount" binding:"omitnil,absamount" swaggertype:"number" example:"100"`
+	MaxAmount *Money   `json:"max_amount" binding:"omitnil,absamount" swaggertype:"number" example:"5000"`
+	// IncludePlanned — учитывать запланированные транзакции
+	IncludePlanned bool `json:"include_planned"`
+}
+
+// ReportQueryResult — строки пользовательского отчета. Значения строки идут в порядке Columns:
+// сначала измерения (строки или null), затем показатели (числа).
+type ReportQueryResult struct {
+	Columns []string        `json:"columns" example:"month,category,currency,expense"`
+	Rows    [][]interface{} `json:"rows" swaggertype:"array,object"`
+}
+
+// TopPayees — контрагенты с наибольшими расходами или числом покупок за период.
+type TopPayees struct {
+	From string `json:"from" example:"2025-07-01"`
+	To   string `json:"to" example:"2025-07-31"`
+	// Currency — базовая валюта пользователя, в которую пересчитаны расходы
+	Currency string `json:"currency" example:"RUB"`
+	// Sort — порядок: spent (по расходам) или visits (по числу покупок)
+	Sort   string     `json:"sort" example:"spent"`
+	Payees []TopPayee `json:"payees"`
+}
+
+// TopPayee — расходы у контрагента за период.
+type TopPayee struct {
+	PayeeID int    `json:"payee_id" example:"1"`
+	Name    string `json:"name" example:"Пятерочка"`
+	// Spent — расходы за вычетом возвратов в базовой валюте пользователя
+	Spent Money `json:"spent" swaggertype:"number" example:"15250.5"`
+	// Visits — число расходных транзакций
+	Visits int `json:"visits" example:"12"`
+	// Average — средний расход на одну покупку
+	Average Money `json:"average" swaggertype:"number" example:"1270.88"`
+	// Totals — расходы в каждой валюте без пересчета
+	Totals []TransactionTotals `json:"totals"`
+}
+
+// SpendingStats — статистика расходов за период по каждой валюте.
+type SpendingStats struct {
+	From string `json:"from" example:"2025-07-01"`
+	To   string `json:"to" example:"2025-07-31"`
+	// Days — число дней в периоде
+	Days       int                     `json:"days" example:"31"`
+	Currencies []CurrencySpendingStats `json:"currencies"`
+}
+
+// CurrencySpendingStats — статистика расходов за период в одной валюте.
+// Дневные показатели считаются по всем дням периода, включая дни без расходов.
+type CurrencySpendingStats struct {
+	Currency string `json:"currency" example:"RUB"`
+	// Total — расходы за вычетом возвратов
+	Total        Money `json:"total" swaggertype:"number" example:"32000.5"`
+	MeanDaily    Money `json:"mean_daily" swaggertype:"number" example:"1032.27"`
+	MedianDaily  Money `json:"median_daily" swaggertype:"number" example:"640"`
+	StdDevDaily  Money `json:"stddev_daily" swaggertype:"number" example:"1210.4"`
+	ExpenseCount int   `json:"expense_count" example:"57"`
+	// LargestExpense — самый крупный расход за период
+	LargestExpense *Transaction `json:"largest_expense,omitempty"`
+	// ByWeekday — расходы по дням недели с понедельника по воскресенье
+	ByWeekday []WeekdaySpend `json:"by_weekday"`
+}
+
+// WeekdaySpend — расходы за вычетом возвратов по одному дню недели.
+type WeekdaySpend struct {
+	// Weekday — номер дня недели по ISO 8601: 1 — понедельник, 7 — воскресенье
+	Weekday int   `json:"weekday" example:"5"`
+	Total   Money `json:"total" swaggertype:"number" example:"8400"`
+	// Average — средние расходы за один такой день периода
+	Average Money `json:"average" swaggertype:"number" example:"1680"`
+}
+
+// PeriodComparison — итоги двух периодов и расходы их категорий в базовой валюте пользователя.
+// Изменения считаются от периода A к периоду B: B минус A.
+type PeriodComparison struct {
+	// Currency — базовая валюта пользователя, в которую пересчитаны суммы
+	Currency string         `json:"currency" example:"RUB"`
+	A        ComparedPeriod `json:"a"`
+	B        ComparedPeriod `json:"b"`
+	// Delta — разница итогов периода B и периода A
+	Delta      ComparisonTotals     `json:"delta"`
+	Categories []CategoryComparison `json:"categories"`
+}
+
+// ComparedPeriod — границы и итоги одного из сравниваемых периодов.
+type ComparedPeriod struct {
+	From string `json:"from" example:"2025-06-01"`
+	To   string `json:"to" example:"2025-06-30"`
+	ComparisonTotals
+}
+
+// ComparisonTotals — доходы, расходы за вычетом возвратов и их разница.
+type ComparisonTotals struct {
+	Income  Money `json:"income" swaggertype:"number" example:"50000"`
+	Expense Money `json:"expense" swaggertype:"number" example:"32000.5"`
+	Net     Money `json:"net" swaggertype:"number" example:"17999.5"`
+}
+
+// CategoryComparison — суммы категории в двух периодах. Транзакции без категории собраны в строку с ID 0.
+type CategoryComparison struct {
+	CategoryID   int              `json:"category_id" example:"3"`
+	CategoryName string           `json:"category_name" example:"Продукты"`
+	A            ComparisonTotals `json:"a"`
+	B            ComparisonTotals `json:"b"`
+	Delta        ComparisonTotals `json:"delta"`
+	// ExpenseChange — изменение расходов в процентах; отсутствует, если в периоде A расходов не было
+	ExpenseChange *float64 `json:"expense_change,omitempty" example:"-12.5"`
+	// Direction — направление изменения расходов: up, down, flat или new
+	Direction string `json:"direction" example:"down"`
+}
+
+// Dashboard — данные главного экрана приложения, собранные одним запросом.
+type Dashboard struct {
+	// Currency — базовая валюта пользователя, в которую пересчитаны остаток и итоги месяца
+	Currency string `json:"currency" example:"RUB"`
+	// Balance — суммарный остаток своих открытых счетов
+	Balance Money `json:"balance" swaggertype:"number" example:"120000"`
+	// Month — итоги текущего месяца, начинающегося с дня начала месяца пользователя, по сегодняшний день
+	Month PeriodSummary `json:"month"`
+	// Budgets — бюджеты, действующие сегодня, с ходом исполнения
+	Budgets []BudgetProgress `json:"budgets"`
+	// RecentTransactions — последние транзакции по убыванию даты
+	RecentTransactions []Transaction `json:"recent_transactions"`
+	// Upcoming — запланированные транзакции на ближайшие дни по возрастанию даты
+	Upcoming []Transaction `json:"upcoming"`
+}
+
+// CashFlowSankey — денежные потоки за период в виде узлов и связей диаграммы Санкея:
+// категории доходов → бюджет → категории расходов и сбережения.
+type CashFlowSankey struct {
+	From string `json:"from" example:"2025-07-01"`
+	To   string `json:"to" example:"2025-07-31"`
+	// Currency — базовая валюта пользователя, в которую пересчитаны потоки
+	Currency string       `json:"currency" example:"RUB"`
+	Nodes    []SankeyNode `json:"nodes"`
+	Links    []SankeyLink `json:"links"`
+}
+
+// SankeyNode — узел диаграммы.
+type SankeyNode struct {
+	// ID — идентификатор узла: income:<ID категории>, expense:<ID категории>, budget, savings или deficit.
+	// Транзакции без категории собраны в узлы с ID категории 0
+	ID   string `json:"id" example:"expense:3"`
+	Name string `json:"name" example:"Продукты"`
+	// Kind — вид узла: income, budget, expense, savings (остаток доходов) или deficit (расходы сверх доходов)
+	Kind string `json:"kind" example:"expense"`
+}
+
+// SankeyLink — поток между узлами диаграммы.
+type SankeyLink struct {
+	Source string `json:"source" example:"budget"`
+	Target string `json:"target" example:"expense:3"`
+	Value  Money  `json:"value" swaggertype:"number" example:"24600"`
+}
+
+// Subscriptions — регулярные списания, найденные в истории транзакций.
+type Subscriptions struct {
+	// Currency — базовая валюта пользователя, в которой посчитана MonthlyTotal
+	Currency string `json:"currency" example:"RUB"`
+	// MonthlyTotal — суммарная стоимость подписок в месяц
+	MonthlyTotal  Money          `json:"monthly_total" swaggertype:"number" example:"1847"`
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// Subscription — регулярное списание одной суммы у одного контрагента через равные промежутки времени.
+type Subscription struct {
+	PayeeID  int    `json:"payee_id" example:"4"`
+	Payee    string `json:"payee" example:"Кинопоиск"`
+	Currency string `json:"currency" example:"RUB"`
+	// Amount — сумма последнего списания
+	Amount Money `json:"amount" swaggertype:"number" example:"299"`
+	// Frequency — периодичность: weekly, monthly, quarterly или yearly
+	Frequency string `json:"frequency" example:"monthly"`
+	// MonthlyCost — стоимость в месяц в валюте списаний
+	MonthlyCost Money `json:"monthly_cost" swaggertype:"number" example:"299"`
+	Charges     int   `json:"charges" example:"8"`
+	CategoryID  int   `json:"category_id,omitempty" example:"7"`
+	// FirstCharge, LastCharge и NextCharge — даты первого, последнего и следующего ожидаемого списания в формате YYYY-MM-DD
+	FirstCharge string `json:"first_charge" example:"2024-12-05"`
+	LastCharge  string `json:"last_charge" example:"2025-07-05"`
+	NextCharge  string `json:"next_charge" example:"2025-08-05"`
+	// LastTransactionID — ID транзакции последнего списания; по нему подписку можно запланировать
+	LastTransactionID int `json:"last_transaction_id" example:"321"`
+}
+
+// SavingsRate — норма сбережений: доля доходов, которая остается после расходов, за период и по интервалам.
+type SavingsRate struct {
+	From string `json:"from" example:"2024-08-01"`
+	To   string `json:"to" example:"2025-07-31"`
+	// GroupBy — длина интервала: day, week (с понедельника), month, quarter или year
+	GroupBy string `json:"group_by" example:"month"`
+	// Currency — базовая валюта пользователя, в которую пересчитаны суммы
+	Currency string `json:"currency" example:"RUB"`
+	Income   Money  `json:"income" swaggertype:"number" example:"600000"`
+	Expense  Money  `json:"expense" swaggertype:"number" example:"420000"`
+	// Savings — доходы минус расходы
+	Savings Money `json:"savings" swaggertype:"number" example:"180000"`
+	// Rate — (доходы − расходы) / доходы в процентах; отсутствует, если доходов не было
+	Rate   *float64           `json:"rate,omitempty" example:"30"`
+	Points []SavingsRatePoint `json:"points"`
+}
+
+// SavingsRatePoint — норма сбережений за один интервал.
+type SavingsRatePoint struct {
+	// Start — первый день интервала в формате YYYY-MM-DD
+	Start   string   `json:"start" example:"2025-07-01"`
+	Income  Money    `json:"income" swaggertype:"number" example:"50000"`
+	Expense Money    `json:"expense" swaggertype:"number" example:"32000.5"`
+	Savings Money    `json:"savings" swaggertype:"number" example:"17999.5"`
+	Rate    *float64 `json:"rate,omitempty" example:"36"`
+}
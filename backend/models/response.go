@@ -1,25 +1,152 @@
 package models
 
+import "time"
+
 type RegisterResponse struct {
 	ID       int    `json:"id" example:"1"`
 	Username string `json:"username" example:"john_doe"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Token     string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt int64  `json:"expires_at" example:"1735689600"`
 }
 
 type UpdateCategoryResponse struct {
 	ID     int    `json:"id" example:"1"`
 	UserID int    `json:"user_id" example:"1"`
 	Name   string `json:"name" example:"Food"`
+	Icon   string `json:"icon" example:"cart"`
+	Color  string `json:"color" example:"#4caf50"`
 }
 
 type GetTransactionsResponse struct {
 	Transactions []Transaction `json:"transactions"`
 	Total        int           `json:"total" example:"100"`
+	// ConvertedTotals — суммы по фильтру в базовой валюте пользователя
+	ConvertedTotals *TransactionTotals `json:"converted_totals,omitempty"`
+	// NextCursor — значение after для следующей страницы при курсорной пагинации;
+	// отсутствует на последней странице
+	NextCursor string `json:"next_cursor,omitempty" example:"MjAyNS0wMS0xNVQwMDowMDowMFp8NDI"`
 }
 
 type ErrorResponse struct {
 	Error string `json:"error" example:"error"`
+	// RequestID — идентификатор запроса из заголовка X-Request-ID для обращения в поддержку
+	RequestID string `json:"request_id,omitempty" example:"4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"`
+}
+
+// ValidationErrorResponse — ответ на запрос, не прошедший проверку: ошибки всех полей сразу.
+type ValidationErrorResponse struct {
+	Error  string       `json:"error" example:"validation failed"`
+	Fields []FieldError `json:"fields"`
+	// RequestID — идентификатор запроса из заголовка X-Request-ID для обращения в поддержку
+	RequestID string `json:"request_id,omitempty" example:"4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"`
+}
+
+// FieldError — ошибка в значении поля запроса.
+type FieldError struct {
+	// Field — имя поля в JSON; для элементов списка с индексом, например tags[1]
+	Field   string `json:"field" example:"amount"`
+	Message string `json:"message" example:"amount must be positive"`
+}
+
+// BulkTransactionResult — результат обработки одного элемента пакетного создания.
+type BulkTransactionResult struct {
+	Index       int          `json:"index" example:"0"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Error       string       `json:"error,omitempty" example:"amount must be positive"`
+}
+
+type BulkTransactionsResponse struct {
+	Error   string                  `json:"error,omitempty" example:"validation failed"`
+	Results []BulkTransactionResult `json:"results"`
+}
+
+// ApplyBudgetTemplateResponse — результат применения шаблона бюджетов к месяцу.
+// Бюджеты категорий, у которых в месяце уже есть бюджет, не создаются.
+type ApplyBudgetTemplateResponse struct {
+	Month   string `json:"month" example:"2025-08"`
+	Created int64  `json:"created" example:"12"`
+}
+
+type DeleteTransactionsResponse struct {
+	Deleted int64 `json:"deleted" example:"42"`
+}
+
+type GetTransfersResponse struct {
+	Transfers []Transfer `json:"transfers"`
+	Total     int        `json:"total" example:"12"`
+}
+
+type SearchTransactionsResponse struct {
+	Results []TransactionSearchResult `json:"results"`
+	Total   int                       `json:"total" example:"3"`
+}
+
+// ImportRowResult — результат импорта одной строки файла.
+type ImportRowResult struct {
+	Line          int    `json:"line" example:"2"`
+	TransactionID int    `json:"transaction_id,omitempty" example:"42"`
+	Error         string `json:"error,omitempty" example:"invalid amount \"abc\""`
+	// PossibleDuplicate — созданная транзакция похожа на уже существующую
+	PossibleDuplicate bool `json:"possible_duplicate,omitempty"`
+	// Category — категория, назначенная строке
+	Category string `json:"category,omitempty" example:"Продукты"`
+	// Transaction — транзакция, которая была бы создана; только при dry_run.
+	// У категорий, которые были бы созданы, category_id равен 0
+	Transaction *Transaction `json:"transaction,omitempty"`
+}
+
+type ImportReport struct {
+	// DryRun — файл только проверен, транзакции и категории не созданы;
+	// Imported и CreatedCategories показывают, что было бы создано
+	DryRun            bool              `json:"dry_run"`
+	Imported          int               `json:"imported" example:"10"`
+	Failed            int               `json:"failed" example:"1"`
+	CreatedCategories []string          `json:"created_categories,omitempty" example:"Кафе"`
+	Rows              []ImportRowResult `json:"rows"`
+}
+
+// ImportJob — фоновое задание импорта.
+type ImportJob struct {
+	ID int `json:"id" example:"7"`
+	// Status — pending, running, completed, failed или cancelled
+	Status        string `json:"status" example:"running"`
+	TotalRows     int    `json:"total_rows" example:"5000"`
+	ProcessedRows int    `json:"processed_rows" example:"1500"`
+	// Report — отчет по уже обработанным строкам
+	Report     *ImportReport `json:"report,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+}
+
+type MarkClearedResponse struct {
+	Updated int64 `json:"updated" example:"12"`
+}
+
+// DuplicateConflictResponse — ответ 409 при создании транзакции, похожей на существующие.
+type DuplicateConflictResponse struct {
+	Error        string `json:"error" example:"possible duplicate"`
+	DuplicateIDs []int  `json:"duplicate_ids" example:"12,15"`
+}
+
+type ReassignCategoryResponse struct {
+	Moved int64 `json:"moved" example:"25"`
+}
+
+// JobStatus — состояние фоновой задачи планировщика.
+type JobStatus struct {
+	Name     string `json:"name" example:"trash_purge"`
+	Schedule string `json:"schedule" example:"@every 1h"`
+	Enabled  bool   `json:"enabled" example:"true"`
+	Running  bool   `json:"running" example:"false"`
+	// Runs — число запусков с момента старта сервиса
+	Runs    int        `json:"runs" example:"12"`
+	LastRun *time.Time `json:"last_run,omitempty"`
+	// LastDuration — длительность последнего запуска, например "1.5s"
+	LastDuration string     `json:"last_duration,omitempty" example:"1.5s"`
+	LastError    string     `json:"last_error,omitempty"`
+	NextRun      *time.Time `json:"next_run,omitempty"`
 }
@@ -3,10 +3,107 @@ package models
 import "time"
 
 type Transaction struct {
-	ID         int       `json:"id"`
-	UserID     int       `json:"user_id"`
-	Amount     float64   `json:"amount"`
-	Type       string    `json:"type"`
-	CategoryID int       `json:"category_id"`
-	Date       time.Time `json:"date"`
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	Amount      Money     `json:"amount" swaggertype:"number" example:"1250.5"`
+	Type        string    `json:"type"`
+	CategoryID  int       `json:"category_id"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Currency    string    `json:"currency"`
+	Tags        []string  `json:"tags"`
+	// Planned — запланированная транзакция с будущей датой; становится обычной в день даты
+	Planned bool `json:"planned"`
+	// Status — статус сверки с выпиской: pending, cleared или reconciled
+	Status string `json:"status"`
+	// PayeeID и Payee — контрагент; при создании можно передать только имя, контрагент будет создан
+	PayeeID int    `json:"payee_id,omitempty"`
+	Payee   string `json:"payee,omitempty"`
+	// PossibleDuplicate — при создании найдены похожие транзакции; снимается подтверждением или отклонением
+	PossibleDuplicate bool `json:"possible_duplicate"`
+	// Flagged — пользователь отметил транзакцию, чтобы вернуться к ней позже
+	Flagged bool `json:"flagged"`
+	// LinkedTransactionID — ID расхода, к которому привязан этот доход как возврат
+	LinkedTransactionID int `json:"linked_transaction_id,omitempty"`
+	// DuplicateOf — ID похожих транзакций; заполняется только в ответе на создание
+	DuplicateOf []int `json:"duplicate_of,omitempty"`
+	// OriginalAmount, OriginalCurrency и FXRate — сумма и валюта покупки за границей и курс,
+	// по которому она пересчитана в Amount: Amount = OriginalAmount * FXRate
+	OriginalAmount   Money   `json:"original_amount,omitempty" swaggertype:"number" example:"25.5"`
+	OriginalCurrency string  `json:"original_currency,omitempty" example:"EUR"`
+	FXRate           float64 `json:"fx_rate,omitempty" example:"98.5"`
+	// DeletedAt — время перемещения в корзину; только для транзакций из корзины
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// AccountID — счет транзакции; транзакции без счета не влияют на остатки
+	AccountID int `json:"account_id,omitempty"`
+	// TransferID — перевод между счетами, частью которого является транзакция; такие транзакции
+	// не учитываются в доходах и расходах и изменяются только вместе с переводом
+	TransferID int `json:"transfer_id,omitempty"`
+	// Adjustment — корректировка остатка счета; не учитывается в доходах и расходах и не изменяется
+	Adjustment bool `json:"adjustment,omitempty"`
+}
+
+type Tag struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// TransactionTotals — суммы доходов и расходов в одной валюте.
+type TransactionTotals struct {
+	Currency string `json:"currency" example:"RUB"`
+	Income   Money  `json:"income" swaggertype:"number" example:"50000"`
+	Expense  Money  `json:"expense" swaggertype:"number" example:"32000.5"`
+}
+
+// TransactionSearchResult — транзакция, найденная полнотекстовым поиском.
+type TransactionSearchResult struct {
+	Transaction
+	Rank float64 `json:"rank" example:"0.42"`
+	// Highlight — описание с совпадениями, выделенными тегом <mark>
+	Highlight string `json:"highlight" example:"Продукты на <mark>неделю</mark>"`
+}
+
+// CategoryTotals — суммы доходов и расходов по категории в одной валюте.
+type CategoryTotals struct {
+	CategoryID int    `json:"category_id" example:"1"`
+	Category   string `json:"category" example:"Продукты"`
+	Currency   string `json:"currency" example:"RUB"`
+	Income     Money  `json:"income" swaggertype:"number" example:"0"`
+	Expense    Money  `json:"expense" swaggertype:"number" example:"12500"`
+}
+
+type Payee struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// PayeeStats — статистика транзакций по контрагенту.
+type PayeeStats struct {
+	PayeeID          int                 `json:"payee_id" example:"1"`
+	Name             string              `json:"name" example:"Пятерочка"`
+	TransactionCount int                 `json:"transaction_count" example:"42"`
+	FirstDate        *time.Time          `json:"first_date,omitempty"`
+	LastDate         *time.Time          `json:"last_date,omitempty"`
+	Totals           []TransactionTotals `json:"totals"`
+}
+
+// LinkedTransactions — расход и привязанные к нему возвраты.
+type LinkedTransactions struct {
+	Transaction Transaction   `json:"transaction"`
+	Refunds     []Transaction `json:"refunds"`
+	// RefundedAmount — сумма возвратов без запланированных
+	RefundedAmount Money `json:"refunded_amount" swaggertype:"number" example:"500"`
+	// NetAmount — сумма расхода за вычетом возвратов
+	NetAmount Money `json:"net_amount" swaggertype:"number" example:"750.5"`
+}
+
+// TransactionVersion — состояние транзакции после одного изменения.
+type TransactionVersion struct {
+	Version   int       `json:"version" example:"2"`
+	ChangedAt time.Time `json:"changed_at"`
+	// ChangedFields — поля, изменившиеся относительно предыдущей версии; у первой версии пусто
+	ChangedFields []string    `json:"changed_fields" example:"amount,description"`
+	Transaction   Transaction `json:"transaction"`
 }
@@ -1,7 +1,59 @@
 package models
 
+import "time"
+
+// Роли пользователей
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID       int    `json:"id"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+	// EmailVerified — пользователь подтвердил, что email принадлежит ему
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	Role          string `json:"role,omitempty"`
+	// BaseCurrency — валюта, в которую пересчитываются итоги
+	BaseCurrency string `json:"base_currency,omitempty"`
+	// MonthStart — день, с которого начинаются месяцы, кварталы и годы в отчетах
+	MonthStart int `json:"month_start,omitempty"`
+	// Language — язык писем, которые отправляются без запроса пользователя
+	Language string `json:"language,omitempty"`
+}
+
+type Profile struct {
+	ID       int    `json:"id" example:"1"`
+	Username string `json:"username" example:"john_doe"`
+	Email    string `json:"email,omitempty" example:"john@example.com"`
+	// EmailVerified — email подтвержден переходом по ссылке из письма
+	EmailVerified bool   `json:"email_verified" example:"true"`
+	BaseCurrency  string `json:"base_currency,omitempty" example:"RUB"`
+	// MonthStart — день, с которого начинаются месяцы, кварталы и годы в отчетах
+	MonthStart int `json:"month_start,omitempty" example:"25"`
+	// Language — язык писем с отчетами и уведомлений о бюджетах
+	Language string `json:"language,omitempty" example:"ru"`
+}
+
+type Invite struct {
+	ID        int        `json:"id" example:"1"`
+	Code      string     `json:"code" example:"3f9a1c0b7e2d4a6f8c1b2d3e"`
+	CreatedBy int        `json:"created_by" example:"1"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type LoginEvent struct {
+	ID        int       `json:"id" example:"1"`
+	IP        string    `json:"ip" example:"203.0.113.7"`
+	UserAgent string    `json:"user_agent" example:"Mozilla/5.0"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReportEmailPreferences — подписки пользователя на письма с итогами и бюджетами за прошедшую неделю и месяц.
+type ReportEmailPreferences struct {
+	Weekly  bool `json:"weekly" example:"true"`
+	Monthly bool `json:"monthly" example:"false"`
 }
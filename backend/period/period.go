@@ -0,0 +1,137 @@
+// Package period делит даты на интервалы отчетов: дни, недели, месяцы, кварталы и годы.
+// Месяц может начинаться не с первого числа, например в день зарплаты; кварталы и годы
+// складываются из таких месяцев.
+package period
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	Day     = "day"
+	Week    = "week"
+	Month   = "month"
+	Quarter = "quarter"
+	Year    = "year"
+)
+
+// MaxMonthStart — наибольший день начала месяца: с ним в каждом месяце есть день начала.
+const MaxMonthStart = 28
+
+// Grouping — длина интервала Kind и день месяца MonthStart, с которого начинаются месяцы, кварталы и годы.
+// Недели начинаются с понедельника. Даты интервалов — полночь UTC.
+type Grouping struct {
+	Kind       string
+	MonthStart int
+}
+
+// NewGrouping проверяет длину интервала и день начала месяца. Пустая длина означает месяц, нулевой день — первое число.
+func NewGrouping(kind string, monthStart int) (Grouping, error) {
+	if kind == "" {
+		kind = Month
+	}
+	if monthStart == 0 {
+		monthStart = 1
+	}
+	switch kind {
+	case Day, Week, Month, Quarter, Year:
+	default:
+		return Grouping{}, fmt.Errorf("group_by must be 'day', 'week', 'month', 'quarter' or 'year'")
+	}
+	if monthStart < 1 || monthStart > MaxMonthStart {
+		return Grouping{}, fmt.Errorf("month start must be between 1 and %d", MaxMonthStart)
+	}
+	return Grouping{Kind: kind, MonthStart: monthStart}, nil
+}
+
+// Calendar возвращает интервалы календарных месяцев, кварталов и годов.
+func Calendar(kind string) Grouping {
+	return Grouping{Kind: kind, MonthStart: 1}
+}
+
+// Date возвращает полночь UTC дня t.
+func Date(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Offset возвращает сдвиг начала интервала в днях относительно календарного: месяцы, кварталы и годы
+// начинаются на MonthStart-1 дней позже, дни и недели не сдвигаются.
+func (g Grouping) Offset() int {
+	if g.Kind == Day || g.Kind == Week || g.MonthStart < 1 {
+		return 0
+	}
+	return g.MonthStart - 1
+}
+
+// Start возвращает первый день интервала, содержащего день t.
+func (g Grouping) Start(t time.Time) time.Time {
+	t = Date(t)
+	switch g.Kind {
+	case Day:
+		return t
+	case Week:
+		return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	}
+	// Месяц с MonthStart — календарный месяц, сдвинутый на Offset дней
+	shifted := t.AddDate(0, 0, -g.Offset())
+	month := shifted.Month()
+	switch g.Kind {
+	case Quarter:
+		month -= (month - 1) % 3
+	case Year:
+		month = time.January
+	}
+	return time.Date(shifted.Year(), month, 1+g.Offset(), 0, 0, 0, 0, time.UTC)
+}
+
+// Add возвращает первый день интервала, отстоящего от интервала с первым днем start на n интервалов.
+func (g Grouping) Add(start time.Time, n int) time.Time {
+	switch g.Kind {
+	case Day:
+		return start.AddDate(0, 0, n)
+	case Week:
+		return start.AddDate(0, 0, 7*n)
+	case Quarter:
+		return start.AddDate(0, 3*n, 0)
+	case Year:
+		return start.AddDate(n, 0, 0)
+	default:
+		return start.AddDate(0, n, 0)
+	}
+}
+
+// End возвращает последний день интервала с первым днем start.
+func (g Grouping) End(start time.Time) time.Time {
+	return g.Add(start, 1).AddDate(0, 0, -1)
+}
+
+// Count возвращает число интервалов, на которые делятся дни [from, to]. Первый интервал может начинаться до from.
+func (g Grouping) Count(from, to time.Time) int {
+	first, last := g.Start(from), g.Start(to)
+	switch g.Kind {
+	case Day:
+		return int(last.Sub(first).Hours()/24) + 1
+	case Week:
+		return int(last.Sub(first).Hours()/24)/7 + 1
+	}
+	months := (last.Year()-first.Year())*12 + int(last.Month()) - int(first.Month())
+	switch g.Kind {
+	case Quarter:
+		return months/3 + 1
+	case Year:
+		return months/12 + 1
+	default:
+		return months + 1
+	}
+}
+
+// defaultCounts — число интервалов в периоде отчета по умолчанию.
+var defaultCounts = map[string]int{Day: 30, Week: 12, Month: 12, Quarter: 8, Year: 5}
+
+// DefaultFrom возвращает первый день периода отчета по умолчанию, который заканчивается днем to:
+// 30 дней, 12 недель, 12 месяцев, 8 кварталов или 5 лет.
+func (g Grouping) DefaultFrom(to time.Time) time.Time {
+	return g.Add(g.Start(to), 1-defaultCounts[g.Kind])
+}
@@ -0,0 +1,100 @@
+package period
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// TestNewGrouping тестирует проверку длины интервала и дня начала месяца.
+func TestNewGrouping(t *testing.T) {
+	if g, err := NewGrouping("", 0); err != nil || g.Kind != Month || g.MonthStart != 1 {
+		t.Errorf("Expected calendar month by default, got %+v %v", g, err)
+	}
+	for _, tt := range []struct {
+		kind       string
+		monthStart int
+	}{{"hour", 1}, {Month, 29}, {Year, -1}} {
+		if _, err := NewGrouping(tt.kind, tt.monthStart); err == nil {
+			t.Errorf("Expected error for %s starting on %d", tt.kind, tt.monthStart)
+		}
+	}
+}
+
+// TestStart тестирует начало и конец интервала, содержащего день.
+func TestStart(t *testing.T) {
+	tests := []struct {
+		g          Grouping
+		day        time.Time
+		start, end time.Time
+	}{
+		{Calendar(Day), time.Date(2025, 7, 16, 15, 30, 0, 0, time.UTC), date(2025, 7, 16), date(2025, 7, 16)},
+		{Calendar(Week), date(2025, 7, 20), date(2025, 7, 14), date(2025, 7, 20)},
+		{Grouping{Week, 25}, date(2025, 7, 14), date(2025, 7, 14), date(2025, 7, 20)},
+		{Calendar(Month), date(2024, 2, 29), date(2024, 2, 1), date(2024, 2, 29)},
+		{Calendar(Quarter), date(2025, 12, 31), date(2025, 10, 1), date(2025, 12, 31)},
+		{Calendar(Year), date(2025, 7, 16), date(2025, 1, 1), date(2025, 12, 31)},
+		// Месяц с 25 числа длится по 24 число следующего месяца
+		{Grouping{Month, 25}, date(2025, 7, 24), date(2025, 6, 25), date(2025, 7, 24)},
+		{Grouping{Month, 25}, date(2025, 7, 25), date(2025, 7, 25), date(2025, 8, 24)},
+		{Grouping{Month, 28}, date(2025, 3, 1), date(2025, 2, 28), date(2025, 3, 27)},
+		{Grouping{Quarter, 25}, date(2025, 4, 10), date(2025, 1, 25), date(2025, 4, 24)},
+		{Grouping{Year, 25}, date(2025, 1, 10), date(2024, 1, 25), date(2025, 1, 24)},
+	}
+	for _, tt := range tests {
+		start := tt.g.Start(tt.day)
+		if !start.Equal(tt.start) || !tt.g.End(start).Equal(tt.end) {
+			t.Errorf("%+v: interval of %s = %s - %s, expected %s - %s", tt.g, tt.day.Format("2006-01-02"),
+				start.Format("2006-01-02"), tt.g.End(start).Format("2006-01-02"), tt.start.Format("2006-01-02"), tt.end.Format("2006-01-02"))
+		}
+	}
+}
+
+// TestCount тестирует подсчет интервалов периода.
+func TestCount(t *testing.T) {
+	tests := []struct {
+		g        Grouping
+		from, to time.Time
+		expected int
+	}{
+		{Calendar(Day), date(2025, 7, 1), date(2025, 7, 1), 1},
+		{Calendar(Day), date(2025, 7, 1), date(2025, 7, 31), 31},
+		{Calendar(Week), date(2025, 7, 28), date(2025, 8, 3), 1},
+		{Calendar(Week), date(2025, 7, 30), date(2025, 8, 4), 2},
+		{Calendar(Month), date(2025, 7, 31), date(2025, 8, 1), 2},
+		{Calendar(Month), date(2024, 8, 15), date(2025, 7, 31), 12},
+		{Grouping{Month, 25}, date(2025, 7, 24), date(2025, 7, 25), 2},
+		{Calendar(Quarter), date(2025, 3, 31), date(2025, 4, 1), 2},
+		{Grouping{Quarter, 25}, date(2025, 1, 1), date(2025, 12, 31), 5},
+		{Calendar(Year), date(2020, 6, 1), date(2025, 1, 1), 6},
+	}
+	for _, tt := range tests {
+		if got := tt.g.Count(tt.from, tt.to); got != tt.expected {
+			t.Errorf("%+v: Count(%s, %s) = %d, expected %d", tt.g, tt.from.Format("2006-01-02"), tt.to.Format("2006-01-02"), got, tt.expected)
+		}
+	}
+}
+
+// TestDefaultFrom тестирует начало периода отчета по умолчанию.
+func TestDefaultFrom(t *testing.T) {
+	to := date(2025, 7, 16)
+	tests := []struct {
+		g        Grouping
+		expected time.Time
+	}{
+		{Calendar(Day), date(2025, 6, 17)},
+		{Calendar(Week), date(2025, 4, 28)},
+		{Calendar(Month), date(2024, 8, 1)},
+		{Grouping{Month, 25}, date(2024, 7, 25)},
+		{Calendar(Quarter), date(2023, 10, 1)},
+		{Calendar(Year), date(2021, 1, 1)},
+	}
+	for _, tt := range tests {
+		if got := tt.g.DefaultFrom(to); !got.Equal(tt.expected) {
+			t.Errorf("%+v: DefaultFrom = %s, expected %s", tt.g, got.Format("2006-01-02"), tt.expected.Format("2006-01-02"))
+		}
+	}
+}
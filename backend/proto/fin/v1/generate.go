@@ -0,0 +1,4 @@
+// Package finv1 содержит код, сгенерированный из finance.proto.
+package finv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative fin/v1/finance.proto
@@ -0,0 +1,146 @@
+// Package quotes получает текущие цены ценных бумаг и криптовалют из внешних источников (Московская биржа, CoinGecko).
+package quotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	moexURL      = "https://iss.moex.com/iss/engines/stock/markets/shares/boards/TQBR/securities.json"
+	coinGeckoURL = "https://api.coingecko.com/api/v3/simple/price"
+)
+
+// Quote — текущая цена одной бумаги.
+type Quote struct {
+	Price    float64
+	Currency string
+}
+
+// Provider возвращает текущие цены бумаг по их тикерам. Тикеры, для которых цены нет,
+// в ответ не входят.
+type Provider interface {
+	Quotes(ctx context.Context, tickers []string) (map[string]Quote, error)
+}
+
+// New создает провайдера "moex" или "coingecko".
+func New(provider string) (Provider, error) {
+	switch provider {
+	case "moex":
+		return NewMOEX(), nil
+	case "coingecko":
+		return NewCoinGecko(), nil
+	default:
+		return nil, fmt.Errorf("unknown quote provider: %s", provider)
+	}
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+func get(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("quote request failed: status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// MOEX получает цены последних сделок акций основного режима торгов Московской биржи (в рублях).
+type MOEX struct {
+	url    string
+	client *http.Client
+}
+
+func NewMOEX() *MOEX {
+	return &MOEX{url: moexURL, client: newClient()}
+}
+
+func (p *MOEX) Quotes(ctx context.Context, tickers []string) (map[string]Quote, error) {
+	query := url.Values{
+		"securities":         {strings.Join(tickers, ",")},
+		"iss.only":           {"marketdata"},
+		"iss.meta":           {"off"},
+		"marketdata.columns": {"SECID,LAST"},
+	}
+	resp, err := get(ctx, p.client, p.url+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Marketdata struct {
+			Data [][]interface{} `json:"data"`
+		} `json:"marketdata"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	// Строка — [SECID, LAST]; без сделок за день LAST равен null
+	quotes := make(map[string]Quote)
+	for _, row := range result.Marketdata.Data {
+		if len(row) != 2 {
+			return nil, fmt.Errorf("unexpected marketdata row: %v", row)
+		}
+		ticker, ok := row[0].(string)
+		price, hasPrice := row[1].(float64)
+		if ok && hasPrice && price > 0 {
+			quotes[ticker] = Quote{Price: price, Currency: "RUB"}
+		}
+	}
+	return quotes, nil
+}
+
+// CoinGecko получает цены криптовалют в долларах США по их символам (BTC, ETH).
+type CoinGecko struct {
+	url    string
+	client *http.Client
+}
+
+func NewCoinGecko() *CoinGecko {
+	return &CoinGecko{url: coinGeckoURL, client: newClient()}
+}
+
+func (p *CoinGecko) Quotes(ctx context.Context, tickers []string) (map[string]Quote, error) {
+	query := url.Values{
+		"symbols":       {strings.ToLower(strings.Join(tickers, ","))},
+		"vs_currencies": {"usd"},
+	}
+	resp, err := get(ctx, p.client, p.url+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Ответ — цены по символам в нижнем регистре: {"btc": {"usd": 67000.5}}
+	var result map[string]struct {
+		USD float64 `json:"usd"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	quotes := make(map[string]Quote)
+	for _, ticker := range tickers {
+		if price, ok := result[strings.ToLower(ticker)]; ok && price.USD > 0 {
+			quotes[ticker] = Quote{Price: price.USD, Currency: "USD"}
+		}
+	}
+	return quotes, nil
+}
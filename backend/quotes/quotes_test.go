@@ -0,0 +1,66 @@
+package quotes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMOEX тестирует запрос и разбор цен Московской биржи.
+func TestMOEX(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("securities")
+		w.Write([]byte(`{"marketdata": {"columns": ["SECID", "LAST"], "data": [["SBER", 310.25], ["GAZP", null]]}}`))
+	}))
+	defer server.Close()
+
+	provider := NewMOEX()
+	provider.url = server.URL
+	result, err := provider.Quotes(context.Background(), []string{"SBER", "GAZP"})
+	if err != nil {
+		t.Fatalf("Failed to fetch quotes: %v", err)
+	}
+	if query != "SBER,GAZP" {
+		t.Errorf("Unexpected securities query: %q", query)
+	}
+	if len(result) != 1 || result["SBER"] != (Quote{Price: 310.25, Currency: "RUB"}) {
+		t.Errorf("Unexpected quotes: %v", result)
+	}
+}
+
+// TestCoinGecko тестирует запрос и разбор цен криптовалют CoinGecko.
+func TestCoinGecko(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("symbols")
+		w.Write([]byte(`{"btc": {"usd": 67000.5}, "eth": {"usd": 3200}}`))
+	}))
+	defer server.Close()
+
+	provider := NewCoinGecko()
+	provider.url = server.URL
+	result, err := provider.Quotes(context.Background(), []string{"BTC", "ETH", "XYZ"})
+	if err != nil {
+		t.Fatalf("Failed to fetch quotes: %v", err)
+	}
+	if query != "btc,eth,xyz" {
+		t.Errorf("Unexpected symbols query: %q", query)
+	}
+	if len(result) != 2 || result["BTC"] != (Quote{Price: 67000.5, Currency: "USD"}) || result["ETH"].Price != 3200 {
+		t.Errorf("Unexpected quotes: %v", result)
+	}
+}
+
+// TestNew тестирует выбор провайдера по имени.
+func TestNew(t *testing.T) {
+	for _, name := range []string{"moex", "coingecko"} {
+		if _, err := New(name); err != nil {
+			t.Errorf("Unexpected error for %s: %v", name, err)
+		}
+	}
+	if _, err := New("unknown"); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+}
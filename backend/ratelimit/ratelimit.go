@@ -0,0 +1,173 @@
+// Package ratelimit ограничивает частоту запросов по алгоритму маркерной корзины
+// (token bucket).
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Result — решение по запросу.
+type Result struct {
+	// Allowed сообщает, пропущен ли запрос
+	Allowed bool
+	// Remaining — число запросов, которые можно сделать сразу
+	Remaining int
+	// Reset — момент, когда корзина снова заполнится полностью
+	Reset time.Time
+	// RetryAfter — через сколько появится следующий маркер; 0, если запрос пропущен
+	RetryAfter time.Duration
+}
+
+// Limiter выдает маркеры из корзин вместимостью limit, которые полностью
+// заполняются за period. Каждый ключ (IP-адрес, пользователь) имеет свою корзину.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit int, period time.Duration, now time.Time) (Result, error)
+}
+
+// refill возвращает число маркеров в корзине, где было tokens маркеров в момент updated.
+func refill(tokens float64, updated time.Time, limit int, period time.Duration, now time.Time) float64 {
+	if elapsed := now.Sub(updated).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(limit), tokens+elapsed*float64(limit)/period.Seconds())
+	}
+	return tokens
+}
+
+// result формирует решение по числу маркеров до выдачи и возвращает остаток после нее.
+func result(tokens float64, limit int, period time.Duration, now time.Time) Result {
+	capacity := float64(limit)
+	rate := capacity / period.Seconds()
+	res := Result{Allowed: tokens >= 1}
+	if res.Allowed {
+		tokens--
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	res.Remaining = int(tokens)
+	res.Reset = now.Add(seconds((capacity - tokens) / rate))
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// Memory — Limiter в памяти процесса; подходит для одного экземпляра сервиса.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full — момент, когда корзина заполнится и ее можно удалить
+	full time.Time
+}
+
+// NewMemory создает Limiter в памяти.
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket)}
+}
+
+func (m *Memory) Allow(_ context.Context, key string, limit int, period time.Duration, now time.Time) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, exists := m.buckets[key]
+	if !exists {
+		m.cleanup(now)
+		b = &bucket{tokens: float64(limit), updated: now}
+		m.buckets[key] = b
+	}
+	tokens := refill(b.tokens, b.updated, limit, period, now)
+	res := result(tokens, limit, period, now)
+	if res.Allowed {
+		tokens--
+	}
+	b.tokens, b.updated, b.full = tokens, now, res.Reset
+	return res, nil
+}
+
+// cleanup удаляет заполненные корзины: они не отличаются от новых.
+func (m *Memory) cleanup(now time.Time) {
+	if len(m.buckets) < 1000 {
+		return
+	}
+	for key, b := range m.buckets {
+		if !b.full.After(now) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+const keyPrefix = "fin-ng:ratelimit:"
+
+// takeScript пересчитывает корзину и забирает маркер атомарно, чтобы экземпляры сервиса
+// не выдали один маркер дважды. Корзина хранится в хэше с полями tokens и updated
+// (миллисекунды) и удаляется, когда заполнится.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = capacity
+	updated = now
+end
+if now > updated then
+	tokens = math.min(capacity, tokens + (now - updated) * capacity / period)
+end
+local before = tokens
+if tokens >= 1 then
+	tokens = tokens - 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((capacity - tokens) * period / capacity) + 1)
+return tostring(before)
+`)
+
+// Redis — Limiter в Redis; корзины общие для всех экземпляров сервиса.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis подключается к Redis по адресу вида redis://[:password@]host:port[/db].
+func NewRedis(ctx context.Context, url string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &Redis{client: client}, nil
+}
+
+func (r *Redis) Allow(ctx context.Context, key string, limit int, period time.Duration, now time.Time) (Result, error) {
+	reply, err := takeScript.Run(ctx, r.client, []string{keyPrefix + key},
+		limit, period.Milliseconds(), now.UnixMilli()).Text()
+	if err != nil {
+		return Result{}, err
+	}
+	tokens, err := strconv.ParseFloat(reply, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid rate limit reply %q: %w", reply, err)
+	}
+	return result(tokens, limit, period, now), nil
+}
+
+// Close закрывает соединения с Redis.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// testLimiter проверяет выдачу и восполнение маркеров: квота 3 запроса в минуту.
+func testLimiter(t *testing.T, limiter Limiter) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 10, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		res, err := limiter.Allow(ctx, "user:1", 3, time.Minute, now)
+		if err != nil || !res.Allowed || res.Remaining != 2-i {
+			t.Errorf("Request %d: expected allowed with %d remaining, got %+v (%v)", i, 2-i, res, err)
+		}
+	}
+
+	// Четвертый запрос отклоняется до появления следующего маркера
+	res, err := limiter.Allow(ctx, "user:1", 3, time.Minute, now)
+	if err != nil || res.Allowed {
+		t.Fatalf("Expected request over quota to be rejected, got %+v (%v)", res, err)
+	}
+	if res.RetryAfter != 20*time.Second || !res.Reset.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected retry after 20s and reset at 12:01:10, got %v %v", res.RetryAfter, res.Reset)
+	}
+
+	// Квоты разных ключей независимы
+	if res, _ := limiter.Allow(ctx, "user:2", 3, time.Minute, now); !res.Allowed {
+		t.Error("Expected other key to be allowed")
+	}
+
+	// Через 20 секунд восполняется один маркер
+	if res, _ := limiter.Allow(ctx, "user:1", 3, time.Minute, now.Add(20*time.Second)); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("Expected refilled token, got %+v", res)
+	}
+	if res, _ := limiter.Allow(ctx, "user:1", 3, time.Minute, now.Add(25*time.Second)); res.Allowed {
+		t.Error("Expected request before refill to be rejected")
+	}
+
+	// Корзина не копит больше limit маркеров
+	if res, _ := limiter.Allow(ctx, "user:1", 3, time.Minute, now.Add(time.Hour)); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("Expected full bucket, got %+v", res)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	testLimiter(t, NewMemory())
+}
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	limiter, err := NewRedis(context.Background(), "redis://"+server.Addr())
+	if err != nil {
+		t.Fatalf("NewRedis failed: %v", err)
+	}
+	defer limiter.Close()
+
+	testLimiter(t, limiter)
+	if ttl := server.TTL(keyPrefix + "user:2"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected bucket to expire when refilled, got TTL %v", ttl)
+	}
+}
@@ -0,0 +1,200 @@
+// Package rates получает курсы валют из внешних источников
+// (Европейский центральный банк, ЦБ РФ, open.er-api.com).
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	ecbURL     = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	cbrURL     = "https://www.cbr.ru/scripts/XML_daily.asp"
+	openAPIURL = "https://open.er-api.com/v6/latest/USD"
+)
+
+// Provider возвращает текущие курсы: сколько единиц каждой валюты стоит
+// одна единица опорной валюты провайдера. Опорная валюта входит в ответ с курсом 1.
+type Provider interface {
+	Fetch(ctx context.Context) (map[string]float64, error)
+}
+
+// Convert пересчитывает сумму из одной валюты в другую по курсам провайдера.
+func Convert(rates map[string]float64, amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromRate, ok := rates[from]
+	if !ok || fromRate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", from)
+	}
+	toRate, ok := rates[to]
+	if !ok || toRate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", to)
+	}
+	return amount / fromRate * toRate, nil
+}
+
+// New создает провайдера "ecb", "cbr" или "openapi".
+func New(provider string) (Provider, error) {
+	switch provider {
+	case "ecb":
+		return NewECB(), nil
+	case "cbr":
+		return NewCBR(), nil
+	case "openapi":
+		return NewOpenAPI(), nil
+	default:
+		return nil, fmt.Errorf("unknown exchange rate provider: %s", provider)
+	}
+}
+
+func newClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+func get(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("exchange rate request failed: status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// ECB получает ежедневные курсы Европейского центрального банка (опорная валюта EUR).
+type ECB struct {
+	url    string
+	client *http.Client
+}
+
+func NewECB() *ECB {
+	return &ECB{url: ecbURL, client: newClient()}
+}
+
+func (p *ECB) Fetch(ctx context.Context) (map[string]float64, error) {
+	resp, err := get(ctx, p.client, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Cube struct {
+			Cube struct {
+				Rates []struct {
+					Currency string  `xml:"currency,attr"`
+					Rate     float64 `xml:"rate,attr"`
+				} `xml:"Cube"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, err
+	}
+
+	result := map[string]float64{"EUR": 1}
+	for _, r := range envelope.Cube.Cube.Rates {
+		result[r.Currency] = r.Rate
+	}
+	return result, nil
+}
+
+// CBR получает официальные курсы ЦБ РФ (опорная валюта RUB).
+type CBR struct {
+	url    string
+	client *http.Client
+}
+
+func NewCBR() *CBR {
+	return &CBR{url: cbrURL, client: newClient()}
+}
+
+func (p *CBR) Fetch(ctx context.Context) (map[string]float64, error) {
+	resp, err := get(ctx, p.client, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Ответ ЦБ РФ приходит в кодировке windows-1251
+	decoder := xml.NewDecoder(resp.Body)
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(charset, "windows-1251") {
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		}
+		return nil, fmt.Errorf("unsupported charset: %s", charset)
+	}
+
+	var curs struct {
+		Valutes []struct {
+			CharCode string `xml:"CharCode"`
+			Nominal  string `xml:"Nominal"`
+			Value    string `xml:"Value"`
+		} `xml:"Valute"`
+	}
+	if err := decoder.Decode(&curs); err != nil {
+		return nil, err
+	}
+
+	result := map[string]float64{"RUB": 1}
+	for _, v := range curs.Valutes {
+		// Value — стоимость Nominal единиц валюты в рублях, с запятой в качестве разделителя
+		nominal, err := strconv.ParseFloat(v.Nominal, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nominal for %s: %w", v.CharCode, err)
+		}
+		value, err := strconv.ParseFloat(strings.Replace(v.Value, ",", ".", 1), 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid rate for %s", v.CharCode)
+		}
+		result[v.CharCode] = nominal / value
+	}
+	return result, nil
+}
+
+// OpenAPI получает курсы из открытого API open.er-api.com (опорная валюта USD).
+type OpenAPI struct {
+	url    string
+	client *http.Client
+}
+
+func NewOpenAPI() *OpenAPI {
+	return &OpenAPI{url: openAPIURL, client: newClient()}
+}
+
+func (p *OpenAPI) Fetch(ctx context.Context) (map[string]float64, error) {
+	resp, err := get(ctx, p.client, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Result string             `json:"result"`
+		Rates  map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Result != "success" {
+		return nil, fmt.Errorf("exchange rate request failed: %s", result.Result)
+	}
+	return result.Rates, nil
+}
@@ -0,0 +1,98 @@
+package rates
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func serve(t *testing.T, body []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestECB тестирует разбор ежедневных курсов ЕЦБ.
+func TestECB(t *testing.T) {
+	server := serve(t, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2025-06-13">
+			<Cube currency="USD" rate="1.1512"/>
+			<Cube currency="JPY" rate="165.76"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`))
+
+	provider := NewECB()
+	provider.url = server.URL
+	result, err := provider.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch rates: %v", err)
+	}
+	if result["EUR"] != 1 || result["USD"] != 1.1512 || result["JPY"] != 165.76 {
+		t.Errorf("Unexpected rates: %v", result)
+	}
+}
+
+// TestCBR тестирует разбор курсов ЦБ РФ в кодировке windows-1251.
+func TestCBR(t *testing.T) {
+	body, err := charmap.Windows1251.NewEncoder().String(`<?xml version="1.0" encoding="windows-1251"?>
+<ValCurs Date="14.06.2025" name="Foreign Currency Market">
+	<Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>Доллар США</Name><Value>80,0000</Value></Valute>
+	<Valute ID="R01820"><NumCode>392</NumCode><CharCode>JPY</CharCode><Nominal>100</Nominal><Name>Японских иен</Name><Value>55,0000</Value></Valute>
+</ValCurs>`)
+	if err != nil {
+		t.Fatalf("Failed to encode body: %v", err)
+	}
+	server := serve(t, []byte(body))
+
+	provider := NewCBR()
+	provider.url = server.URL
+	result, err := provider.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch rates: %v", err)
+	}
+	if result["RUB"] != 1 || result["USD"] != 1.0/80 || result["JPY"] != 100.0/55 {
+		t.Errorf("Unexpected rates: %v", result)
+	}
+}
+
+// TestOpenAPI тестирует разбор ответа open.er-api.com.
+func TestOpenAPI(t *testing.T) {
+	server := serve(t, []byte(`{"result": "success", "base_code": "USD", "rates": {"USD": 1, "EUR": 0.87}}`))
+
+	provider := NewOpenAPI()
+	provider.url = server.URL
+	result, err := provider.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch rates: %v", err)
+	}
+	if result["USD"] != 1 || result["EUR"] != 0.87 {
+		t.Errorf("Unexpected rates: %v", result)
+	}
+}
+
+// TestConvert тестирует пересчет через опорную валюту.
+func TestConvert(t *testing.T) {
+	rates := map[string]float64{"RUB": 1, "USD": 1.0 / 80, "EUR": 1.0 / 90}
+
+	amount, err := Convert(rates, 10, "USD", "RUB")
+	if err != nil || math.Abs(amount-800) > 1e-9 {
+		t.Errorf("Expected 800, got %v (%v)", amount, err)
+	}
+	amount, err = Convert(rates, 90, "EUR", "USD")
+	if err != nil || math.Abs(amount-101.25) > 1e-9 {
+		t.Errorf("Expected 101.25, got %v (%v)", amount, err)
+	}
+	if _, err := Convert(rates, 1, "GBP", "RUB"); err == nil {
+		t.Error("Expected error for unknown currency")
+	}
+}
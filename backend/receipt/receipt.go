@@ -0,0 +1,241 @@
+// Package receipt разбирает QR-коды российских кассовых чеков и получает чеки
+// из API ФНС «Проверка чеков».
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const fnsURL = "https://irkkt-mobile.nalog.ru:8888/v2"
+
+// Признаки расчета (тег n в QR-коде)
+const (
+	OperationIncome        = 1 // приход: покупка
+	OperationIncomeReturn  = 2 // возврат прихода
+	OperationExpense       = 3 // расход: продавец платит покупателю
+	OperationExpenseReturn = 4 // возврат расхода
+)
+
+// ErrNotFound возвращается, если ФНС не нашла чек по данным QR-кода.
+var ErrNotFound = errors.New("receipt not found")
+
+// moscow — часовой пояс по умолчанию для времени в QR-коде, в котором зона не указывается.
+var moscow = time.FixedZone("MSK", 3*60*60)
+
+// QR — данные QR-кода кассового чека.
+type QR struct {
+	Raw       string
+	Time      time.Time
+	Sum       models.Money
+	FN        string
+	FD        string
+	FP        string
+	Operation int
+}
+
+// ParseQR разбирает строку QR-кода вида t=20250115T1230&s=1234.50&fn=...&i=...&fp=...&n=1.
+func ParseQR(raw string) (QR, error) {
+	raw = strings.TrimSpace(raw)
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return QR{}, fmt.Errorf("invalid receipt qr: %w", err)
+	}
+
+	qr := QR{Raw: raw, FN: values.Get("fn"), FD: values.Get("i"), FP: values.Get("fp")}
+	for name, value := range map[string]string{"fn": qr.FN, "i": qr.FD, "fp": qr.FP} {
+		if value == "" {
+			return QR{}, fmt.Errorf("receipt qr: %s is required", name)
+		}
+		if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+			return QR{}, fmt.Errorf("receipt qr: invalid %s %q", name, value)
+		}
+	}
+
+	for _, layout := range []string{"20060102T150405", "20060102T1504"} {
+		if qr.Time, err = time.ParseInLocation(layout, values.Get("t"), moscow); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return QR{}, fmt.Errorf("receipt qr: invalid time %q", values.Get("t"))
+	}
+
+	if qr.Sum, err = models.ParseMoney(values.Get("s")); err != nil || qr.Sum <= 0 {
+		return QR{}, fmt.Errorf("receipt qr: invalid sum %q", values.Get("s"))
+	}
+
+	if qr.Operation, err = strconv.Atoi(values.Get("n")); err != nil || qr.Operation < OperationIncome || qr.Operation > OperationExpenseReturn {
+		return QR{}, fmt.Errorf("receipt qr: invalid operation type %q", values.Get("n"))
+	}
+	return qr, nil
+}
+
+// TransactionType возвращает тип транзакции покупателя: покупка и возврат расхода — расход,
+// возврат покупки и расход продавца — доход.
+func (qr QR) TransactionType() string {
+	if qr.Operation == OperationIncome || qr.Operation == OperationExpenseReturn {
+		return "expense"
+	}
+	return "income"
+}
+
+// Provider получает чек по данным QR-кода.
+type Provider interface {
+	Fetch(ctx context.Context, qr QR) (*models.Receipt, error)
+}
+
+// FNS получает чеки через API мобильного приложения ФНС «Проверка чеков».
+// Для запросов нужен идентификатор сессии, полученный при входе в приложение.
+type FNS struct {
+	url       string
+	sessionID string
+	deviceID  string
+	client    *http.Client
+	// attempts и retryDelay — сколько раз и с каким интервалом запрашивать чек, пока ФНС его обрабатывает
+	attempts   int
+	retryDelay time.Duration
+}
+
+func NewFNS(sessionID, deviceID string) *FNS {
+	return &FNS{
+		url:        fnsURL,
+		sessionID:  sessionID,
+		deviceID:   deviceID,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		attempts:   5,
+		retryDelay: time.Second,
+	}
+}
+
+func (p *FNS) Fetch(ctx context.Context, qr QR) (*models.Receipt, error) {
+	body, err := json.Marshal(map[string]string{"qr": qr.Raw})
+	if err != nil {
+		return nil, err
+	}
+	var ticket struct {
+		ID string `json:"id"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/ticket", body, &ticket); err != nil {
+		return nil, err
+	}
+	if ticket.ID == "" {
+		return nil, ErrNotFound
+	}
+
+	for attempt := 0; attempt < p.attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(p.retryDelay):
+			}
+		}
+
+		var result struct {
+			Ticket *struct {
+				Document struct {
+					Receipt *fnsReceipt `json:"receipt"`
+				} `json:"document"`
+			} `json:"ticket"`
+		}
+		if err := p.do(ctx, http.MethodGet, "/tickets/"+url.PathEscape(ticket.ID), nil, &result); err != nil {
+			return nil, err
+		}
+		if result.Ticket != nil && result.Ticket.Document.Receipt != nil {
+			return result.Ticket.Document.Receipt.receipt(qr)
+		}
+	}
+	return nil, fmt.Errorf("receipt is still being processed by FNS, try again later")
+}
+
+func (p *FNS) do(ctx context.Context, method, path string, body []byte, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, p.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("sessionId", p.sessionID)
+	req.Header.Set("Device-Id", p.deviceID)
+	req.Header.Set("Device-OS", "Android")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotAcceptable:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("fns request failed: status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// fnsReceipt — чек в формате ФНС: суммы в копейках, время — Unix-время или локальное время без зоны.
+type fnsReceipt struct {
+	DateTime    json.RawMessage `json:"dateTime"`
+	TotalSum    int64           `json:"totalSum"`
+	User        string          `json:"user"`
+	UserINN     string          `json:"userInn"`
+	RetailPlace string          `json:"retailPlace"`
+	Items       []struct {
+		Name     string  `json:"name"`
+		Price    int64   `json:"price"`
+		Quantity float64 `json:"quantity"`
+		Sum      int64   `json:"sum"`
+	} `json:"items"`
+}
+
+func (r *fnsReceipt) receipt(qr QR) (*models.Receipt, error) {
+	date := qr.Time
+	var unix int64
+	var local string
+	if err := json.Unmarshal(r.DateTime, &unix); err == nil && unix > 0 {
+		date = time.Unix(unix, 0).In(moscow)
+	} else if err := json.Unmarshal(r.DateTime, &local); err == nil {
+		if parsed, err := time.ParseInLocation("2006-01-02T15:04:05", local, moscow); err == nil {
+			date = parsed
+		}
+	}
+
+	seller := strings.TrimSpace(r.User)
+	if seller == "" {
+		seller = strings.TrimSpace(r.RetailPlace)
+	}
+
+	result := &models.Receipt{
+		FN:        qr.FN,
+		FD:        qr.FD,
+		FP:        qr.FP,
+		Seller:    seller,
+		SellerINN: strings.TrimSpace(r.UserINN),
+		Date:      date,
+		Total:     models.Money(r.TotalSum),
+		Items:     make([]models.ReceiptItem, 0, len(r.Items)),
+	}
+	if result.Total <= 0 {
+		return nil, fmt.Errorf("fns returned receipt without total")
+	}
+	for _, item := range r.Items {
+		result.Items = append(result.Items, models.ReceiptItem{
+			Name:     strings.TrimSpace(item.Name),
+			Price:    models.Money(item.Price),
+			Quantity: item.Quantity,
+			Sum:      models.Money(item.Sum),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package receipt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const testQR = "t=20250115T1230&s=1234.50&fn=9999078900012345&i=12345&fp=1234567890&n=1"
+
+// TestParseQR тестирует разбор строки QR-кода чека.
+func TestParseQR(t *testing.T) {
+	qr, err := ParseQR(testQR)
+	if err != nil {
+		t.Fatalf("Failed to parse qr: %v", err)
+	}
+	if qr.FN != "9999078900012345" || qr.FD != "12345" || qr.FP != "1234567890" || qr.Sum != models.NewMoney(1234, 50) {
+		t.Errorf("Unexpected qr: %+v", qr)
+	}
+	if want := time.Date(2025, 1, 15, 12, 30, 0, 0, moscow); !qr.Time.Equal(want) {
+		t.Errorf("Expected time %v, got %v", want, qr.Time)
+	}
+	if qr.TransactionType() != "expense" {
+		t.Errorf("Expected purchase to be expense, got %s", qr.TransactionType())
+	}
+
+	qr, err = ParseQR("t=20250115T123015&s=100&fn=1&i=2&fp=3&n=2")
+	if err != nil || qr.Time.Second() != 15 || qr.TransactionType() != "income" {
+		t.Errorf("Expected refund with seconds, got %+v (%v)", qr, err)
+	}
+
+	for _, raw := range []string{
+		"",
+		"t=20250115T1230&s=1234.50&i=12345&fp=1234567890&n=1",
+		"t=20250115T1230&s=1234.50&fn=abc&i=12345&fp=1234567890&n=1",
+		"t=2025-01-15&s=1234.50&fn=1&i=2&fp=3&n=1",
+		"t=20250115T1230&s=0&fn=1&i=2&fp=3&n=1",
+		"t=20250115T1230&s=1234.50&fn=1&i=2&fp=3&n=5",
+	} {
+		if _, err := ParseQR(raw); err == nil {
+			t.Errorf("Expected error for %q", raw)
+		}
+	}
+}
+
+// TestFNS тестирует получение чека: регистрацию запроса, ожидание обработки и разбор чека.
+func TestFNS(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("sessionId") != "session" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/ticket":
+			var body struct {
+				QR string `json:"qr"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.QR != testQR {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			w.Write([]byte(`{"kind": "kkt", "id": "abc"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/tickets/abc":
+			// Первый запрос приходит, пока чек еще обрабатывается
+			if polls++; polls == 1 {
+				w.Write([]byte(`{"status": 1}`))
+				return
+			}
+			w.Write([]byte(`{"status": 2, "ticket": {"document": {"receipt": {
+				"dateTime": 1736933400, "totalSum": 123450, "user": "ООО \"Ромашка\"", "userInn": "7700000000  ",
+				"items": [
+					{"name": "Молоко", "price": 8999, "quantity": 2, "sum": 17998},
+					{"name": "Сыр весовой", "price": 105452, "quantity": 1, "sum": 105452}
+				]}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewFNS("session", "device")
+	provider.url = server.URL
+	provider.retryDelay = time.Millisecond
+
+	qr, _ := ParseQR(testQR)
+	r, err := provider.Fetch(context.Background(), qr)
+	if err != nil {
+		t.Fatalf("Failed to fetch receipt: %v", err)
+	}
+	if r.Seller != `ООО "Ромашка"` || r.SellerINN != "7700000000" || r.Total != models.NewMoney(1234, 50) || r.FN != qr.FN {
+		t.Errorf("Unexpected receipt: %+v", r)
+	}
+	if !r.Date.Equal(qr.Time) {
+		t.Errorf("Expected date %v, got %v", qr.Time, r.Date)
+	}
+	if len(r.Items) != 2 || r.Items[0].Price != models.NewMoney(89, 99) || r.Items[0].Quantity != 2 || r.Items[1].Sum != models.NewMoney(1054, 52) {
+		t.Errorf("Unexpected items: %+v", r.Items)
+	}
+
+	other, _ := ParseQR("t=20250115T1230&s=100&fn=1&i=2&fp=3&n=1")
+	if _, err := provider.Fetch(context.Background(), other); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+// Package report формирует печатные отчеты по транзакциям.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/nemopss/fin-ng/backend/models"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Statement — данные выписки за период.
+type Statement struct {
+	Username string
+	// From и To — границы периода; To не входит в период
+	From time.Time
+	To   time.Time
+	// Categories — названия категорий по ID
+	Categories   map[int]string
+	Totals       []models.CategoryTotals
+	Transactions []models.Transaction
+}
+
+const (
+	fontFamily = "Go"
+	margin     = 15.0
+	lineHeight = 6.0
+)
+
+// Столбцы таблицы транзакций: дата, категория, описание, сумма (ширина в мм)
+var transactionColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Дата", 22, "L"},
+	{"Категория", 40, "L"},
+	{"Описание", 80, "L"},
+	{"Сумма", 38, "R"},
+}
+
+// Столбцы таблицы итогов: категория, валюта, доходы, расходы
+var totalsColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Категория", 80, "L"},
+	{"Валюта", 20, "L"},
+	{"Доходы", 40, "R"},
+	{"Расходы", 40, "R"},
+}
+
+// WritePDF формирует выписку в формате PDF: итоги по категориям и таблицу транзакций.
+// Шрифты Go встраиваются в документ, поэтому кириллица отображается без системных шрифтов.
+func (s Statement) WritePDF(w io.Writer) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-margin + 5)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Стр. %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	last := s.To.AddDate(0, 0, -1)
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.CellFormat(0, 10, fmt.Sprintf("Выписка за %s — %s", s.From.Format("02.01.2006"), last.Format("02.01.2006")), "", 1, "L", false, 0, "")
+	pdf.SetFont(fontFamily, "", 10)
+	pdf.CellFormat(0, lineHeight, "Пользователь: "+s.Username, "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	s.writeTotals(pdf)
+	pdf.Ln(6)
+	s.writeTransactions(pdf)
+
+	return pdf.Output(w)
+}
+
+func (s Statement) writeTotals(pdf *fpdf.Fpdf) {
+	pdf.SetFont(fontFamily, "B", 12)
+	pdf.CellFormat(0, 8, "Итоги по категориям", "", 1, "L", false, 0, "")
+
+	header := func() {
+		pdf.SetFont(fontFamily, "B", 10)
+		for _, col := range totalsColumns {
+			pdf.CellFormat(col.width, lineHeight, col.title, "B", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(fontFamily, "", 10)
+	}
+	header()
+
+	if len(s.Totals) == 0 {
+		pdf.CellFormat(0, lineHeight, "Нет транзакций за период", "", 1, "L", false, 0, "")
+		return
+	}
+
+	// Общие итоги считаются по каждой валюте отдельно
+	byCurrency := make(map[string]*models.TransactionTotals)
+	for _, t := range s.Totals {
+		if s.pageBreak(pdf) {
+			header()
+		}
+		values := []string{s.categoryName(t.CategoryID, t.Category), t.Currency, formatAmount(t.Income), formatAmount(t.Expense)}
+		for i, col := range totalsColumns {
+			pdf.CellFormat(col.width, lineHeight, fitText(pdf, values[i], col.width), "", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+
+		total, ok := byCurrency[t.Currency]
+		if !ok {
+			total = &models.TransactionTotals{Currency: t.Currency}
+			byCurrency[t.Currency] = total
+		}
+		total.Income += t.Income
+		total.Expense += t.Expense
+	}
+
+	currencies := make([]string, 0, len(byCurrency))
+	for currency := range byCurrency {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	pdf.SetFont(fontFamily, "B", 10)
+	for i, currency := range currencies {
+		border := ""
+		if i == 0 {
+			border = "T"
+		}
+		total := byCurrency[currency]
+		values := []string{"Итого", currency, formatAmount(total.Income), formatAmount(total.Expense)}
+		for j, col := range totalsColumns {
+			pdf.CellFormat(col.width, lineHeight, values[j], border, 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+func (s Statement) writeTransactions(pdf *fpdf.Fpdf) {
+	pdf.SetFont(fontFamily, "B", 12)
+	pdf.CellFormat(0, 8, "Транзакции", "", 1, "L", false, 0, "")
+
+	header := func() {
+		pdf.SetFont(fontFamily, "B", 10)
+		for _, col := range transactionColumns {
+			pdf.CellFormat(col.width, lineHeight, col.title, "B", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(fontFamily, "", 9)
+	}
+	header()
+
+	for _, t := range s.Transactions {
+		// Заголовок таблицы повторяется на каждой странице
+		if s.pageBreak(pdf) {
+			header()
+		}
+		amount := formatAmount(t.Amount)
+		if t.Type == "expense" {
+			amount = "-" + amount
+		}
+		values := []string{
+			t.Date.Format("02.01.2006"),
+			s.categoryName(t.CategoryID, ""),
+			t.Description,
+			amount + " " + t.Currency,
+		}
+		for i, col := range transactionColumns {
+			pdf.CellFormat(col.width, lineHeight, fitText(pdf, values[i], col.width), "", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+// pageBreak начинает новую страницу, если следующая строка на текущей не помещается.
+func (s Statement) pageBreak(pdf *fpdf.Fpdf) bool {
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+lineHeight <= pageHeight-margin {
+		return false
+	}
+	pdf.AddPage()
+	return true
+}
+
+func (s Statement) categoryName(id int, name string) string {
+	if name != "" {
+		return name
+	}
+	if name, ok := s.Categories[id]; ok {
+		return name
+	}
+	return "Без категории"
+}
+
+// fitText обрезает текст с многоточием, чтобы он поместился в ячейку шириной width.
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	width -= 2 * pdf.GetCellMargin()
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// formatAmount форматирует сумму с двумя знаками и пробелами между разрядами: 1 234 567.89.
+func formatAmount(amount models.Money) string {
+	s := amount.String()
+	intPart, fracPart := s[:len(s)-3], s[len(s)-3:]
+	sign := ""
+	if intPart[0] == '-' {
+		sign, intPart = "-", intPart[1:]
+	}
+	for i := len(intPart) - 3; i > 0; i -= 3 {
+		intPart = intPart[:i] + " " + intPart[i:]
+	}
+	return sign + intPart + fracPart
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestWritePDF тестирует формирование многостраничной выписки.
+func TestWritePDF(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	statement := Statement{
+		Username:   "testuser",
+		From:       from,
+		To:         from.AddDate(0, 1, 0),
+		Categories: map[int]string{1: "Продукты"},
+		Totals: []models.CategoryTotals{
+			{CategoryID: 1, Category: "Продукты", Currency: "RUB", Expense: models.NewMoney(120000, 0)},
+			{Currency: "USD", Income: models.NewMoney(50, 0)},
+		},
+	}
+	for i := 0; i < 120; i++ {
+		statement.Transactions = append(statement.Transactions, models.Transaction{
+			ID: i + 1, Amount: models.NewMoney(1000, 0), Type: "expense", CategoryID: 1, Currency: "RUB",
+			Date: from.AddDate(0, 0, i%30), Description: strings.Repeat("Очень длинное описание ", 5),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := statement.WritePDF(&buf); err != nil {
+		t.Fatalf("Failed to write PDF: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("Expected PDF document, got %q", buf.Bytes()[:min(buf.Len(), 16)])
+	}
+	// 120 строк не помещаются на одну страницу A4
+	if pages := bytes.Count(buf.Bytes(), []byte("/Type /Page\n")); pages < 2 {
+		t.Errorf("Expected several pages, got %d", pages)
+	}
+
+	// Пустая выписка тоже формируется
+	buf.Reset()
+	if err := (Statement{From: from, To: from.AddDate(0, 1, 0)}).WritePDF(&buf); err != nil {
+		t.Fatalf("Failed to write empty PDF: %v", err)
+	}
+}
+
+// TestFormatAmount тестирует форматирование сумм.
+func TestFormatAmount(t *testing.T) {
+	tests := map[models.Money]string{
+		0:                            "0.00",
+		models.NewMoney(999, 50):     "999.50",
+		models.NewMoney(1234, 50):    "1 234.50",
+		models.NewMoney(1234567, 89): "1 234 567.89",
+		models.NewMoney(-98765, 40):  "-98 765.40",
+	}
+	for amount, expected := range tests {
+		if got := formatAmount(amount); got != expected {
+			t.Errorf("formatAmount(%v) = %q, expected %q", amount, got, expected)
+		}
+	}
+}
+
+// TestFitText тестирует обрезку текста по ширине ячейки.
+func TestFitText(t *testing.T) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Helvetica", "", 10)
+	if got := fitText(pdf, "short", 40); got != "short" {
+		t.Errorf("Expected text unchanged, got %q", got)
+	}
+	long := fmt.Sprintf("%0100d", 0)
+	got := fitText(pdf, long, 40)
+	if !strings.HasSuffix(got, "…") || len(got) >= len(long) {
+		t.Errorf("Expected truncated text, got %q", got)
+	}
+}
@@ -0,0 +1,209 @@
+package report
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// Workbook — книга Excel из листов с таблицами.
+type Workbook struct {
+	Sheets []Sheet
+}
+
+// Sheet — лист книги: строка заголовков и строки значений. Значение ячейки — строка, целое или дробное число,
+// сумма models.Money (записывается числом с двумя знаками) или nil для пустой ячейки.
+type Sheet struct {
+	Name    string
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// Стили ячеек из styles.xml: обычный, полужирный для заголовков и денежный формат с разделителями разрядов
+const (
+	styleDefault = 0
+	styleHeader  = 1
+	styleMoney   = 2
+	// maxColumnWidth — наибольшая ширина столбца в символах
+	maxColumnWidth = 60
+)
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>
+</styleSheet>`
+
+// columnName возвращает буквенное имя столбца по номеру с нуля: A, B, ..., Z, AA, ...
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// validateSheetName проверяет имя листа по ограничениям Excel.
+func validateSheetName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > 31 || strings.ContainsAny(name, `[]:*?/\`) {
+		return fmt.Errorf("invalid sheet name %q", name)
+	}
+	return nil
+}
+
+// cell возвращает разметку ячейки и длину ее текста для ширины столбца.
+func cell(ref string, value interface{}, style int) (string, int, error) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return "", 0, nil
+	case string:
+		return fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escapeXML(v)),
+			utf8.RuneCountInString(v), nil
+	case models.Money:
+		text, style = v.String(), styleMoney
+	case int:
+		text = strconv.Itoa(v)
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return "", 0, fmt.Errorf("unsupported cell value %T", value)
+	}
+	return fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, style, text), len(text) + 2, nil
+}
+
+// writeSheet записывает разметку листа: закрепленную строку заголовков, ширины столбцов по содержимому и строки.
+func writeSheet(w io.Writer, sheet Sheet) error {
+	var rows strings.Builder
+	widths := make([]int, len(sheet.Headers))
+	write := func(r int, values []interface{}, style int) error {
+		fmt.Fprintf(&rows, `<row r="%d">`, r)
+		for i, value := range values {
+			markup, width, err := cell(columnName(i)+strconv.Itoa(r), value, style)
+			if err != nil {
+				return err
+			}
+			rows.WriteString(markup)
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], width)
+		}
+		rows.WriteString(`</row>`)
+		return nil
+	}
+
+	headers := make([]interface{}, len(sheet.Headers))
+	for i, header := range sheet.Headers {
+		headers[i] = header
+	}
+	if err := write(1, headers, styleHeader); err != nil {
+		return err
+	}
+	for i, row := range sheet.Rows {
+		if err := write(i+2, row, styleDefault); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	if len(widths) > 0 {
+		fmt.Fprint(w, `<cols>`)
+		for i, width := range widths {
+			fmt.Fprintf(w, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, min(max(width, 8)+2, maxColumnWidth))
+		}
+		fmt.Fprint(w, `</cols>`)
+	}
+	_, err := fmt.Fprintf(w, `<sheetData>%s</sheetData></worksheet>`, rows.String())
+	return err
+}
+
+// WriteXLSX записывает книгу в формате Office Open XML (.xlsx).
+func (wb Workbook) WriteXLSX(w io.Writer) error {
+	if len(wb.Sheets) == 0 {
+		return fmt.Errorf("workbook must have at least one sheet")
+	}
+	names := make(map[string]bool, len(wb.Sheets))
+	for _, sheet := range wb.Sheets {
+		if err := validateSheetName(sheet.Name); err != nil {
+			return err
+		}
+		if names[strings.ToLower(sheet.Name)] {
+			return fmt.Errorf("duplicate sheet name %q", sheet.Name)
+		}
+		names[strings.ToLower(sheet.Name)] = true
+	}
+
+	var contentTypes, workbook, rels strings.Builder
+	contentTypes.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, sheet := range wb.Sheets {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(sheet.Name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.Sheets)+1)
+	rels.WriteString(`</Relationships>`)
+
+	z := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := z.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	for i, sheet := range wb.Sheets {
+		f, err := z.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeSheet(f, sheet); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
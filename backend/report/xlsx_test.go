@@ -0,0 +1,98 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+// TestColumnName тестирует буквенные имена столбцов.
+func TestColumnName(t *testing.T) {
+	for i, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != expected {
+			t.Errorf("columnName(%d) = %s, expected %s", i, got, expected)
+		}
+	}
+}
+
+// TestWriteXLSX тестирует состав книги и разметку листов.
+func TestWriteXLSX(t *testing.T) {
+	workbook := Workbook{Sheets: []Sheet{
+		{
+			Name:    "Итоги",
+			Headers: []string{"Категория", "Сумма", "Число"},
+			Rows: [][]interface{}{
+				{"Продукты & <кафе>", models.NewMoney(1234, 50), 3},
+				{nil, -models.NewMoney(10, 0), int64(1)},
+			},
+		},
+		{Name: "Пусто", Headers: []string{"Дата"}},
+	}}
+
+	var buf bytes.Buffer
+	if err := workbook.WriteXLSX(&buf); err != nil {
+		t.Fatalf("Failed to write XLSX: %v", err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open XLSX as zip: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(r)
+		r.Close()
+		parts[f.Name] = string(content)
+
+		// Каждая часть книги — корректный XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Invalid XML in %s: %v", f.Name, err)
+			}
+		}
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml",
+		"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Expected part %s in workbook", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Итоги" sheetId="1" r:id="rId1"/>`) {
+		t.Errorf("Unexpected workbook: %s", parts["xl/workbook.xml"])
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, expected := range []string{
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Категория</t></is></c>`,
+		`<t xml:space="preserve">Продукты &amp; &lt;кафе&gt;</t>`,
+		`<c r="B2" s="2"><v>1234.50</v></c>`,
+		`<c r="C2" s="0"><v>3</v></c>`,
+		`<row r="3"><c r="B3" s="2"><v>-10.00</v></c>`,
+	} {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("Expected %s in sheet: %s", expected, sheet)
+		}
+	}
+
+	for _, invalid := range []Workbook{
+		{},
+		{Sheets: []Sheet{{Name: "a/b"}}},
+		{Sheets: []Sheet{{Name: strings.Repeat("я", 32)}}},
+		{Sheets: []Sheet{{Name: "Лист"}, {Name: "лист"}}},
+		{Sheets: []Sheet{{Name: "Лист", Headers: []string{"A"}, Rows: [][]interface{}{{true}}}}},
+	} {
+		if err := invalid.WriteXLSX(io.Discard); err == nil {
+			t.Errorf("Expected error for workbook %+v", invalid)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package scheduler запускает фоновые задачи сервиса по расписанию в формате cron
+// и хранит состояние их последних запусков.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+	"github.com/robfig/cron/v3"
+)
+
+// Job — фоновая задача.
+type Job struct {
+	// Name — имя задачи из строчных букв и подчеркиваний, например trash_purge
+	Name string
+	// Schedule — расписание: cron из пяти полей ("0 3 * * *") или "@every 1h", "@daily" и т.п.
+	Schedule string
+	Run      func(ctx context.Context) error
+}
+
+// job — задача с состоянием запусков.
+type job struct {
+	Job
+	enabled bool
+	entryID cron.EntryID
+
+	mu           sync.Mutex
+	running      bool
+	runs         int
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastErr      error
+}
+
+// Scheduler запускает задачи по расписанию. Запуск задачи пропускается, если предыдущий
+// еще не завершился.
+type Scheduler struct {
+	cron   *cron.Cron
+	jobs   []*job
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu защищает stopped и согласует его с wg: после Stop новые запуски не начинаются
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// New создает планировщик.
+func New() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{cron: cron.New(), ctx: ctx, cancel: cancel}
+}
+
+// Add добавляет задачу. Выключенная задача не запускается, но видна в Status.
+func (s *Scheduler) Add(j Job, enabled bool) error {
+	for _, existing := range s.jobs {
+		if existing.Name == j.Name {
+			return fmt.Errorf("job %s already added", j.Name)
+		}
+	}
+	added := &job{Job: j, enabled: enabled}
+	if enabled {
+		id, err := s.cron.AddFunc(j.Schedule, func() { s.run(added) })
+		if err != nil {
+			return fmt.Errorf("invalid schedule for job %s: %w", j.Name, err)
+		}
+		added.entryID = id
+	} else if _, err := cron.ParseStandard(j.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule for job %s: %w", j.Name, err)
+	}
+	s.jobs = append(s.jobs, added)
+	return nil
+}
+
+// Start запускает включенные задачи сразу, чтобы после перезапуска сервиса не ждать
+// следующего срока, и затем по расписанию.
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		if j.enabled {
+			go s.run(j)
+		}
+	}
+	s.cron.Start()
+}
+
+// Stop останавливает планировщик, отменяет контекст задач и ждет завершения запущенных.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	s.cron.Stop()
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run выполняет задачу, если она еще не выполняется, и запоминает результат.
+func (s *Scheduler) run(j *job) {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+	defer s.wg.Done()
+
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return
+	}
+	j.running = true
+	j.mu.Unlock()
+
+	start := time.Now()
+	err := safeRun(s.ctx, j.Run)
+	if err != nil {
+		log.Printf("job %s failed: %v", j.Name, err)
+	}
+
+	j.mu.Lock()
+	j.running = false
+	j.runs++
+	j.lastRun, j.lastDuration, j.lastErr = start, time.Since(start), err
+	j.mu.Unlock()
+}
+
+// safeRun превращает панику задачи в ошибку, чтобы она не остановила сервис.
+func safeRun(ctx context.Context, fn func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// Status возвращает состояние задач в порядке добавления.
+func (s *Scheduler) Status() []models.JobStatus {
+	statuses := make([]models.JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		status := models.JobStatus{Name: j.Name, Schedule: j.Schedule, Enabled: j.enabled}
+		if j.enabled {
+			if next := s.cron.Entry(j.entryID).Next; !next.IsZero() {
+				status.NextRun = &next
+			}
+		}
+
+		j.mu.Lock()
+		status.Running, status.Runs = j.running, j.runs
+		if !j.lastRun.IsZero() {
+			lastRun := j.lastRun
+			status.LastRun = &lastRun
+			status.LastDuration = j.lastDuration.String()
+		}
+		if j.lastErr != nil {
+			status.LastError = j.lastErr.Error()
+		}
+		j.mu.Unlock()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	s := New()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if err := s.Add(Job{Name: "slow", Schedule: "@every 1h", Run: func(ctx context.Context) error {
+		close(started)
+		<-release
+		return errors.New("failed")
+	}}, true); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := s.Add(Job{Name: "panics", Schedule: "0 3 * * *", Run: func(context.Context) error { panic("boom") }}, true); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	disabledRuns := 0
+	if err := s.Add(Job{Name: "disabled", Schedule: "@daily", Run: func(context.Context) error {
+		disabledRuns++
+		return nil
+	}}, false); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if err := s.Add(Job{Name: "slow", Schedule: "@every 1h"}, true); err == nil {
+		t.Error("Expected error for duplicate job")
+	}
+	for _, enabled := range []bool{true, false} {
+		if err := s.Add(Job{Name: "invalid", Schedule: "every hour"}, enabled); err == nil {
+			t.Errorf("Expected error for invalid schedule (enabled=%v)", enabled)
+		}
+	}
+
+	// Включенные задачи запускаются сразу; повторный запуск выполняющейся задачи пропускается
+	s.Start()
+	<-started
+	s.run(s.jobs[0])
+	if status := s.Status()[0]; !status.Running || status.Runs != 0 || status.NextRun == nil {
+		t.Errorf("Expected running job with next run, got %+v", status)
+	}
+	for deadline := time.Now().Add(5 * time.Second); s.Status()[1].Runs == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(release)
+	s.Stop()
+
+	statuses := s.Status()
+	if len(statuses) != 3 {
+		t.Fatalf("Expected 3 jobs, got %d", len(statuses))
+	}
+	if slow := statuses[0]; slow.Running || slow.Runs != 1 || slow.LastRun == nil || slow.LastError != "failed" || slow.LastDuration == "" {
+		t.Errorf("Unexpected status of slow job: %+v", slow)
+	}
+	if panics := statuses[1]; panics.Runs != 1 || panics.LastError != "panic: boom" {
+		t.Errorf("Expected panic to be recorded, got %+v", panics)
+	}
+	if disabled := statuses[2]; disabled.Enabled || disabled.Runs != 0 || disabled.NextRun != nil || disabledRuns != 0 {
+		t.Errorf("Expected disabled job not to run, got %+v", disabled)
+	}
+
+	// После остановки задачи не запускаются
+	s.run(s.jobs[1])
+	if runs := s.Status()[1].Runs; runs != 1 {
+		t.Errorf("Expected no runs after Stop, got %d", runs)
+	}
+}
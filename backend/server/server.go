@@ -0,0 +1,115 @@
+// Package server запускает HTTP-сервер API, при необходимости с TLS: по готовому
+// сертификату или с автоматическим получением сертификатов Let's Encrypt.
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	defaultAddr             = ":8080"
+	defaultTLSAddr          = ":443"
+	defaultHTTPAddr         = ":80"
+	defaultAutocertCacheDir = "autocert-cache"
+	readHeaderTimeout       = 10 * time.Second
+)
+
+// Config — настройки сервера.
+type Config struct {
+	// Addr — адрес сервера API; по умолчанию :8080 без TLS и :443 с TLS
+	Addr string
+	// CertFile и KeyFile — пути к сертификату и закрытому ключу в формате PEM
+	CertFile string
+	KeyFile  string
+	// AutocertDomains — домены, для которых сертификаты выпускаются в Let's Encrypt;
+	// нельзя задавать вместе с CertFile
+	AutocertDomains []string
+	// AutocertCacheDir — каталог для выпущенных сертификатов; по умолчанию autocert-cache
+	AutocertCacheDir string
+	// AutocertEmail — адрес для уведомлений Let's Encrypt о сроке действия сертификатов
+	AutocertEmail string
+	// HTTPAddr — адрес HTTP-сервера для проверок Let's Encrypt и перенаправления на HTTPS;
+	// по умолчанию :80
+	HTTPAddr string
+}
+
+// Server — сервер API и, для Let's Encrypt, вспомогательный HTTP-сервер.
+type Server struct {
+	api *http.Server
+	// redirect отвечает на проверки HTTP-01 и перенаправляет остальные запросы на HTTPS
+	redirect *http.Server
+}
+
+// New создает сервер для handler. Сертификат из CertFile загружается сразу, чтобы ошибка
+// в путях обнаружилась при запуске.
+func New(handler http.Handler, cfg Config) (*Server, error) {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("both TLS certificate and key files must be set")
+	}
+	if cfg.CertFile != "" && len(cfg.AutocertDomains) > 0 {
+		return nil, errors.New("TLS certificate files and autocert domains are mutually exclusive")
+	}
+
+	s := &Server{api: &http.Server{Addr: cfg.Addr, Handler: handler, ReadHeaderTimeout: readHeaderTimeout}}
+	switch {
+	case cfg.CertFile != "":
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		s.api.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	case len(cfg.AutocertDomains) > 0:
+		if cfg.AutocertCacheDir == "" {
+			cfg.AutocertCacheDir = defaultAutocertCacheDir
+		}
+		if cfg.HTTPAddr == "" {
+			cfg.HTTPAddr = defaultHTTPAddr
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCacheDir),
+			Email:      cfg.AutocertEmail,
+		}
+		s.api.TLSConfig = manager.TLSConfig()
+		s.api.TLSConfig.MinVersion = tls.VersionTLS12
+		s.redirect = &http.Server{Addr: cfg.HTTPAddr, Handler: manager.HTTPHandler(nil), ReadHeaderTimeout: readHeaderTimeout}
+	}
+
+	if s.api.Addr == "" {
+		s.api.Addr = defaultAddr
+		if s.api.TLSConfig != nil {
+			s.api.Addr = defaultTLSAddr
+		}
+	}
+	return s, nil
+}
+
+// TLSConfig возвращает настройки TLS сервера API или nil, если сервер работает без TLS.
+// Через них другие серверы процесса, например gRPC, используют тот же сертификат.
+func (s *Server) TLSConfig() *tls.Config {
+	return s.api.TLSConfig
+}
+
+// ListenAndServe запускает сервер и блокируется до его остановки.
+func (s *Server) ListenAndServe() error {
+	if s.api.TLSConfig == nil {
+		return s.api.ListenAndServe()
+	}
+	if s.redirect != nil {
+		go func() {
+			if err := s.redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("HTTP redirect server stopped: %v", err)
+			}
+		}()
+	}
+	// Сертификаты уже заданы в TLSConfig
+	return s.api.ListenAndServeTLS("", "")
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeCertificate создает самоподписанный сертификат для 127.0.0.1 и возвращает пути
+// к сертификату и ключу.
+func writeCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fin-ng test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNew(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s, err := New(handler, Config{})
+	if err != nil || s.api.Addr != ":8080" || s.api.TLSConfig != nil || s.redirect != nil {
+		t.Fatalf("Expected plain HTTP server on :8080, got %+v (%v)", s, err)
+	}
+
+	certFile, keyFile := writeCertificate(t)
+	for _, cfg := range []Config{
+		{CertFile: certFile},
+		{CertFile: certFile, KeyFile: keyFile, AutocertDomains: []string{"example.com"}},
+		{CertFile: certFile, KeyFile: filepath.Join(t.TempDir(), "missing.pem")},
+	} {
+		if _, err := New(handler, cfg); err == nil {
+			t.Errorf("Expected error for %+v", cfg)
+		}
+	}
+
+	// Сервер с готовым сертификатом отвечает по HTTPS
+	s, err = New(handler, Config{CertFile: certFile, KeyFile: keyFile})
+	if err != nil || s.api.Addr != ":443" {
+		t.Fatalf("Expected TLS server on :443, got %+v (%v)", s, err)
+	}
+	ts := httptest.NewUnstartedServer(s.api.Handler)
+	ts.TLS = s.api.TLSConfig
+	ts.StartTLS()
+	defer ts.Close()
+	resp, err := ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatalf("HTTPS request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", resp.StatusCode)
+	}
+
+	// Let's Encrypt: проверка TLS-ALPN-01 и HTTP-сервер с перенаправлением на HTTPS
+	s, err = New(handler, Config{Addr: ":8443", AutocertDomains: []string{"fin.example.com"}, AutocertCacheDir: t.TempDir()})
+	if err != nil || s.api.Addr != ":8443" || s.redirect == nil || s.redirect.Addr != ":80" {
+		t.Fatalf("Expected autocert server, got %+v (%v)", s, err)
+	}
+	if s.api.TLSConfig.GetCertificate == nil || !slices.Contains(s.api.TLSConfig.NextProtos, "acme-tls/1") {
+		t.Errorf("Expected autocert TLS config, got %+v", s.api.TLSConfig)
+	}
+	w := httptest.NewRecorder()
+	s.redirect.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://fin.example.com/dashboard", nil))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "https://fin.example.com/dashboard" {
+		t.Errorf("Expected redirect to HTTPS, got %d %v", w.Code, w.Header())
+	}
+}
@@ -0,0 +1,156 @@
+// Package subscription находит подписки — регулярные списания одной суммы у одного контрагента.
+package subscription
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+const (
+	Weekly    = "weekly"
+	Monthly   = "monthly"
+	Quarterly = "quarterly"
+	Yearly    = "yearly"
+)
+
+// HistoryMonths — глубина истории для поиска подписок: годовую подписку видно по двум списаниям.
+const HistoryMonths = 25
+
+// amountTolerance — допустимое отличие суммы списания от наименьшей суммы подписки, доля.
+const amountTolerance = 0.1
+
+// frequency — периодичность подписки: ожидаемый промежуток между списаниями с допуском в днях.
+type frequency struct {
+	name      string
+	days      int
+	tolerance int
+	// months — длина периода в календарных месяцах; 0 для недельной подписки
+	months int
+	// perMonth — число списаний в месяц
+	perMonth   float64
+	minCharges int
+}
+
+var frequencies = []frequency{
+	{name: Weekly, days: 7, tolerance: 1, perMonth: 52.0 / 12, minCharges: 3},
+	{name: Monthly, days: 30, tolerance: 4, months: 1, perMonth: 1, minCharges: 3},
+	{name: Quarterly, days: 91, tolerance: 7, months: 3, perMonth: 1.0 / 3, minCharges: 3},
+	{name: Yearly, days: 365, tolerance: 10, months: 12, perMonth: 1.0 / 12, minCharges: 2},
+}
+
+// Detect находит подписки среди расходов с контрагентом. Списания группируются по контрагенту, валюте
+// и близкой сумме; группа считается подпиской, если все промежутки между списаниями соответствуют одной
+// периодичности. Подписки, очередное списание которых просрочено больше допуска на сегодня today, считаются
+// отмененными и не возвращаются. Результат упорядочен по контрагенту и сумме.
+func Detect(transactions []models.Transaction, today time.Time) []models.Subscription {
+	type key struct {
+		payeeID  int
+		currency string
+	}
+	groups := make(map[key][]models.Transaction)
+	var keys []key
+	for _, t := range transactions {
+		if t.PayeeID == 0 || t.Type != "expense" {
+			continue
+		}
+		k := key{t.PayeeID, t.Currency}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], t)
+	}
+
+	subscriptions := []models.Subscription{}
+	for _, k := range keys {
+		for _, charges := range clusterByAmount(groups[k]) {
+			if s, ok := detectCharges(charges, today); ok {
+				subscriptions = append(subscriptions, s)
+			}
+		}
+	}
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		a, b := subscriptions[i], subscriptions[j]
+		if a.Payee != b.Payee {
+			return a.Payee < b.Payee
+		}
+		return a.Amount < b.Amount
+	})
+	return subscriptions
+}
+
+// clusterByAmount делит списания на группы близких сумм, чтобы разные подписки одного контрагента не смешивались.
+func clusterByAmount(transactions []models.Transaction) [][]models.Transaction {
+	sorted := append([]models.Transaction(nil), transactions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Amount < sorted[j].Amount })
+
+	var clusters [][]models.Transaction
+	var smallest models.Money
+	for _, t := range sorted {
+		if len(clusters) == 0 || t.Amount.Float64() > smallest.Float64()*(1+amountTolerance) {
+			clusters = append(clusters, nil)
+			smallest = t.Amount
+		}
+		clusters[len(clusters)-1] = append(clusters[len(clusters)-1], t)
+	}
+	return clusters
+}
+
+// detectCharges проверяет, что списания одной суммы идут с одной периодичностью и подписка не отменена.
+func detectCharges(charges []models.Transaction, today time.Time) (models.Subscription, bool) {
+	sort.SliceStable(charges, func(i, j int) bool { return charges[i].Date.Before(charges[j].Date) })
+	first, last := charges[0], charges[len(charges)-1]
+
+	for _, f := range frequencies {
+		if len(charges) < f.minCharges || !regular(charges, f) {
+			continue
+		}
+		next := nextCharge(day(last.Date), f)
+		if day(today).After(next.AddDate(0, 0, f.tolerance)) {
+			return models.Subscription{}, false
+		}
+		return models.Subscription{
+			PayeeID:           last.PayeeID,
+			Payee:             last.Payee,
+			Currency:          last.Currency,
+			Amount:            last.Amount,
+			Frequency:         f.name,
+			MonthlyCost:       models.MoneyFromFloat(last.Amount.Float64() * f.perMonth),
+			Charges:           len(charges),
+			CategoryID:        last.CategoryID,
+			FirstCharge:       first.Date.Format("2006-01-02"),
+			LastCharge:        last.Date.Format("2006-01-02"),
+			NextCharge:        next.Format("2006-01-02"),
+			LastTransactionID: last.ID,
+		}, true
+	}
+	return models.Subscription{}, false
+}
+
+// regular проверяет, что каждый промежуток между соседними списаниями отличается от периода f не больше допуска.
+func regular(charges []models.Transaction, f frequency) bool {
+	for i := 1; i < len(charges); i++ {
+		days := int(day(charges[i].Date).Sub(day(charges[i-1].Date)).Hours() / 24)
+		if days < f.days-f.tolerance || days > f.days+f.tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// nextCharge возвращает дату списания через период f после last. Для месячных периодов день месяца
+// сохраняется, а в коротком месяце заменяется последним днем: после 31 января — 28 или 29 февраля.
+func nextCharge(last time.Time, f frequency) time.Time {
+	if f.months == 0 {
+		return last.AddDate(0, 0, f.days)
+	}
+	firstOfMonth := time.Date(last.Year(), last.Month()+time.Month(f.months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(last.Day(), lastDay)-1)
+}
+
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,82 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nemopss/fin-ng/backend/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// TestDetect тестирует поиск подписок по истории расходов.
+func TestDetect(t *testing.T) {
+	charge := func(id, payeeID int, payee string, amount models.Money, when time.Time) models.Transaction {
+		return models.Transaction{ID: id, Type: "expense", PayeeID: payeeID, Payee: payee, Currency: "RUB", Amount: amount, Date: when}
+	}
+	transactions := []models.Transaction{
+		// Месячная подписка с ценой, выросшей в пределах допуска, и списаниями в конце месяца
+		charge(1, 1, "Кинопоиск", models.NewMoney(269, 0), date(2025, 3, 31)),
+		charge(2, 1, "Кинопоиск", models.NewMoney(269, 0), date(2025, 4, 30)),
+		charge(3, 1, "Кинопоиск", models.NewMoney(269, 0), date(2025, 5, 31)),
+		charge(4, 1, "Кинопоиск", models.NewMoney(289, 0), date(2025, 6, 30)),
+		// Вторая подписка того же контрагента с другой суммой
+		charge(5, 1, "Кинопоиск", models.NewMoney(2990, 0), date(2024, 7, 10)),
+		charge(6, 1, "Кинопоиск", models.NewMoney(2990, 0), date(2025, 7, 10)),
+		// Недельная
+		charge(7, 2, "Доставка", models.NewMoney(199, 0), date(2025, 6, 27)),
+		charge(8, 2, "Доставка", models.NewMoney(199, 0), date(2025, 7, 4)),
+		charge(9, 2, "Доставка", models.NewMoney(199, 0), date(2025, 7, 11)),
+		// Нерегулярные покупки
+		charge(10, 3, "Пятерочка", models.NewMoney(1000, 0), date(2025, 6, 1)),
+		charge(11, 3, "Пятерочка", models.NewMoney(1050, 0), date(2025, 6, 3)),
+		charge(12, 3, "Пятерочка", models.NewMoney(990, 0), date(2025, 6, 20)),
+		// Отмененная месячная подписка
+		charge(13, 4, "Музыка", models.NewMoney(169, 0), date(2025, 1, 5)),
+		charge(14, 4, "Музыка", models.NewMoney(169, 0), date(2025, 2, 5)),
+		charge(15, 4, "Музыка", models.NewMoney(169, 0), date(2025, 3, 5)),
+		// Два списания — мало для месячной подписки; доходы и расходы без контрагента не учитываются
+		charge(16, 5, "Облако", models.NewMoney(99, 0), date(2025, 6, 1)),
+		charge(17, 5, "Облако", models.NewMoney(99, 0), date(2025, 7, 1)),
+		{ID: 18, Type: "expense", Currency: "RUB", Amount: models.NewMoney(100, 0), Date: date(2025, 7, 1)},
+	}
+
+	subscriptions := Detect(transactions, date(2025, 7, 15))
+	if len(subscriptions) != 3 {
+		t.Fatalf("Expected 3 subscriptions, got %+v", subscriptions)
+	}
+	if s := subscriptions[0]; s.Payee != "Доставка" || s.Frequency != Weekly || s.NextCharge != "2025-07-18" ||
+		s.MonthlyCost != models.NewMoney(862, 33) || s.Charges != 3 {
+		t.Errorf("Unexpected weekly subscription: %+v", s)
+	}
+	if s := subscriptions[1]; s.Payee != "Кинопоиск" || s.Frequency != Monthly || s.Amount != models.NewMoney(289, 0) ||
+		s.MonthlyCost != models.NewMoney(289, 0) || s.FirstCharge != "2025-03-31" || s.NextCharge != "2025-07-30" || s.LastTransactionID != 4 {
+		t.Errorf("Unexpected monthly subscription: %+v", s)
+	}
+	if s := subscriptions[2]; s.Frequency != Yearly || s.MonthlyCost != models.NewMoney(249, 17) || s.NextCharge != "2026-07-10" {
+		t.Errorf("Unexpected yearly subscription: %+v", s)
+	}
+}
+
+// TestNextCharge тестирует дату следующего списания.
+func TestNextCharge(t *testing.T) {
+	monthly, quarterly := frequencies[1], frequencies[2]
+	tests := []struct {
+		last     time.Time
+		f        frequency
+		expected time.Time
+	}{
+		{date(2025, 1, 31), monthly, date(2025, 2, 28)},
+		{date(2024, 1, 31), monthly, date(2024, 2, 29)},
+		{date(2025, 12, 15), monthly, date(2026, 1, 15)},
+		{date(2025, 11, 30), quarterly, date(2026, 2, 28)},
+		{date(2025, 7, 1), frequencies[0], date(2025, 7, 8)},
+	}
+	for _, tt := range tests {
+		if got := nextCharge(tt.last, tt.f); !got.Equal(tt.expected) {
+			t.Errorf("nextCharge(%s, %s) = %s, expected %s", tt.last.Format("2006-01-02"), tt.f.name, got.Format("2006-01-02"), tt.expected.Format("2006-01-02"))
+		}
+	}
+}